@@ -173,11 +173,16 @@ func (b *Buyer) Update() gin.HandlerFunc {
 // Delete DeleteBuyer godoc
 // @Summary     Delete buyer
 // @Tags        Buyers
-// @Description delete buyer
+// @Description delete buyer, with strategy=block (default) the delete fails while the buyer has purchase orders,
+// @Description with strategy=reassign&to=<id> they are moved to another buyer first
 // @Produce     json
-// @Param       id path int true "buyer id"
+// @Param       id       path  int    true  "buyer id"
+// @Param       strategy query string false "block or reassign"
+// @Param       to       query int    false "buyer to reassign the purchase orders to"
 // @Success     204
+// @Failure     400 {object} web.errorResponse
 // @Failure     404 {object} web.errorResponse
+// @Failure     409 {object} web.errorResponse
 // @Failure     422 {object} web.errorResponse
 // @Failure     500 {object} web.errorResponse
 // @Router      /api/v1/buyers/{id} [delete]
@@ -189,12 +194,27 @@ func (b *Buyer) Delete() gin.HandlerFunc {
 			web.Error(c, http.StatusBadRequest, "invalid Id")
 			return
 		}
-		errDelete := b.buyerService.Delete(c, id)
+		options, err := deleteOptions(c)
+		if err != nil {
+			logging.Log(err)
+			web.Error(c, http.StatusBadRequest, buyer.ErrInvalidStrategy.Error())
+			return
+		}
+		errDelete := b.buyerService.Delete(c, id, options)
 		if errDelete != nil {
 			switch errDelete {
 			case buyer.ErrNotFound:
 				logging.Log(errors.New(fmt.Sprintf("buyer with %d id not found", id)))
 				web.Error(c, http.StatusNotFound, "buyer with id %d not found", id)
+			case buyer.ErrInvalidStrategy:
+				logging.Log(errDelete.Error())
+				web.Error(c, http.StatusBadRequest, errDelete.Error())
+			case buyer.ErrHasOrders:
+				logging.Log(errDelete.Error())
+				web.Error(c, http.StatusConflict, errDelete.Error())
+			case buyer.ErrTargetNotFound:
+				logging.Log(errDelete.Error())
+				web.Error(c, http.StatusUnprocessableEntity, errDelete.Error())
 			default:
 				logging.Log(errDelete.Error())
 				web.Error(c, http.StatusInternalServerError, errDelete.Error())
@@ -204,3 +224,38 @@ func (b *Buyer) Delete() gin.HandlerFunc {
 		web.Success(c, http.StatusNoContent, "Deleted ok")
 	}
 }
+
+// DeletePreview DeletePreviewBuyer godoc
+// @Summary     Preview buyer delete
+// @Tags        Buyers
+// @Description list the purchase orders a delete of the buyer affects and the strategies it accepts
+// @Produce     json
+// @Param       id  path     int true "buyer id"
+// @Success     200 {object} web.response
+// @Failure     400 {object} web.errorResponse
+// @Failure     404 {object} web.errorResponse
+// @Failure     500 {object} web.errorResponse
+// @Router      /api/v1/buyers/{id}/deletePreview [get]
+func (b *Buyer) DeletePreview() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			logging.Log("invalid Id")
+			web.Error(c, http.StatusBadRequest, "invalid Id")
+			return
+		}
+		preview, err := b.buyerService.DeletePreview(c, id)
+		if err != nil {
+			switch err {
+			case buyer.ErrNotFound:
+				logging.Log(errors.New(fmt.Sprintf("buyer with %d id not found", id)))
+				web.Error(c, http.StatusNotFound, "buyer with id %d not found", id)
+			default:
+				logging.Log(err.Error())
+				web.Error(c, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+		web.Success(c, http.StatusOK, preview)
+	}
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -26,6 +27,7 @@ func createServer(mockRepository buyer.MockRepository) *gin.Engine {
 	pr.GET("/:id", handler.Get())
 	pr.POST("/", handler.Create())
 	pr.DELETE("/:id", handler.Delete())
+	pr.GET("/:id/deletePreview", handler.DeletePreview())
 	pr.PATCH("/:id", handler.Update())
 
 	return r
@@ -376,6 +378,106 @@ func TestDeleteFailInternalServerError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
 }
 
+func TestDeleteBuyerFailHasOrders(t *testing.T) {
+	//arrange
+	repo := buyer.MockRepository{
+		Data:   []domain.Buyer{{ID: 1, CardNumberID: "001", FirstName: "Comprador 1", LastName: "Vendedor 1"}},
+		Orders: map[int][]int{1: {7}},
+	}
+
+	//Act
+	r := createServer(repo)
+	req, recorder := createRequestTest(http.MethodDelete, "/api/v1/buyers/1", "")
+	r.ServeHTTP(recorder, req)
+	//arrange
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+}
+
+func TestDeleteBuyerReassignSuccess(t *testing.T) {
+	//arrange
+	repo := buyer.MockRepository{
+		Data: []domain.Buyer{
+			{ID: 1, CardNumberID: "001", FirstName: "Comprador 1", LastName: "Vendedor 1"},
+			{ID: 2, CardNumberID: "002", FirstName: "Comprador 2", LastName: "Vendedor 2"},
+		},
+		Orders: map[int][]int{1: {7}},
+	}
+
+	//Act
+	r := createServer(repo)
+	req, recorder := createRequestTest(http.MethodDelete, "/api/v1/buyers/1?strategy=reassign&to=2", "")
+	r.ServeHTTP(recorder, req)
+	//arrange
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+}
+
+func TestDeleteBuyerFailTargetNotFound(t *testing.T) {
+	//arrange
+	repo := buyer.MockRepository{
+		Data:   []domain.Buyer{{ID: 1, CardNumberID: "001", FirstName: "Comprador 1", LastName: "Vendedor 1"}},
+		Orders: map[int][]int{1: {7}},
+	}
+
+	//Act
+	r := createServer(repo)
+	req, recorder := createRequestTest(http.MethodDelete, "/api/v1/buyers/1?strategy=reassign&to=9", "")
+	r.ServeHTTP(recorder, req)
+	//arrange
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+}
+
+func TestDeleteBuyerFailInvalidStrategy(t *testing.T) {
+	//arrange
+	repo := buyer.MockRepository{
+		Data: []domain.Buyer{{ID: 1, CardNumberID: "001", FirstName: "Comprador 1", LastName: "Vendedor 1"}},
+	}
+
+	//Act
+	r := createServer(repo)
+	for _, url := range []string{"/api/v1/buyers/1?strategy=detach", "/api/v1/buyers/1?strategy=reassign&to=aaa"} {
+		req, recorder := createRequestTest(http.MethodDelete, url, "")
+		r.ServeHTTP(recorder, req)
+		//arrange
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	}
+}
+
+func TestDeletePreviewBuyerSuccess(t *testing.T) {
+	//arrange
+	repo := buyer.MockRepository{
+		Data:   []domain.Buyer{{ID: 1, CardNumberID: "001", FirstName: "Comprador 1", LastName: "Vendedor 1"}},
+		Orders: map[int][]int{1: {7, 8}},
+	}
+
+	//Act
+	r := createServer(repo)
+	req, recorder := createRequestTest(http.MethodGet, "/api/v1/buyers/1/deletePreview", "")
+	r.ServeHTTP(recorder, req)
+
+	var response struct {
+		Data domain.DeletePreview `json:"data"`
+	}
+	//arrange
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, domain.DeletePreview{ID: 1, Strategies: buyer.DeleteStrategies, Blocked: true, Dependents: map[string][]int{"purchase_orders": {7, 8}}}, response.Data)
+}
+
+func TestDeletePreviewBuyerFailNotFound(t *testing.T) {
+	//arrange
+	repo := buyer.MockRepository{
+		Data: []domain.Buyer{{ID: 1, CardNumberID: "001", FirstName: "Comprador 1", LastName: "Vendedor 1"}},
+		Err:  buyer.ErrNotFound,
+	}
+
+	//Act
+	r := createServer(repo)
+	req, recorder := createRequestTest(http.MethodGet, "/api/v1/buyers/1/deletePreview", "")
+	r.ServeHTTP(recorder, req)
+	//arrange
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
 func TestUpdateSuccess(t *testing.T) {
 	//arrange
 	ListBuyers := []domain.Buyer{
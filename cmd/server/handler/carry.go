@@ -2,9 +2,11 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/cmd/server/handler/requests"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/carry"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/web"
 	"github.com/gin-gonic/gin"
@@ -60,3 +62,158 @@ func (c *Carry) Save(ctx *gin.Context) {
 
 	web.Success(ctx, http.StatusCreated, carryCreated)
 }
+
+// GetAll GetCarries godoc
+// @Summary     List carries
+// @Tags        Carries
+// @Description list carries, optionally the ones serving a locality or every locality of a province or country
+// @Produce     json
+// @Param       locality_id query    string false "locality id"
+// @Param       province    query    string false "province name"
+// @Param       country     query    string false "country name"
+// @Success     200         {object} web.response
+// @Failure     500         {object} web.errorResponse
+// @Router      /api/v1/carries [get]
+func (c *Carry) GetAll(ctx *gin.Context) {
+	filter := domain.CarryFilter{
+		LocalityID: ctx.Query("locality_id"),
+		Province:   ctx.Query("province"),
+		Country:    ctx.Query("country"),
+	}
+	carries, err := c.service.GetAll(ctx, filter)
+	if err != nil {
+		logging.Log(err)
+		web.Error(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if carries == nil {
+		carries = []domain.Carry{}
+	}
+
+	web.Success(ctx, http.StatusOK, carries)
+}
+
+// Get GetCarryByID godoc
+// @Summary     Get carry by ID
+// @Tags        Carries
+// @Description get carry by ID
+// @Produce     json
+// @Param       id  path     int true "carry id"
+// @Success     200 {object} web.response
+// @Failure     400 {object} web.errorResponse
+// @Failure     404 {object} web.errorResponse
+// @Failure     500 {object} web.errorResponse
+// @Router      /api/v1/carries/{id} [get]
+func (c *Carry) Get(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		logging.Log(carry.ErrBadRequest)
+		web.Error(ctx, http.StatusBadRequest, carry.ErrBadRequest.Error())
+		return
+	}
+
+	carryObtained, err := c.service.Get(ctx, id)
+	if err != nil {
+		switch err {
+		case carry.ErrNotFound:
+			web.Error(ctx, http.StatusNotFound, err.Error())
+		default:
+			logging.Log(err)
+			web.Error(ctx, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	web.Success(ctx, http.StatusOK, carryObtained)
+}
+
+// Update UpdateCarry godoc
+// @Summary     Update carry
+// @Tags        Carries
+// @Description update the given fields of a carry
+// @Accept      json
+// @Produce     json
+// @Param       id    path     int                        true "carry id"
+// @Param       carry body     requests.CarryPatchRequest true "Fields to update"
+// @Success     200   {object} web.response
+// @Failure     400   {object} web.errorResponse
+// @Failure     404   {object} web.errorResponse
+// @Failure     409   {object} web.errorResponse
+// @Failure     422   {object} web.errorResponse
+// @Failure     500   {object} web.errorResponse
+// @Router      /api/v1/carries/{id} [patch]
+func (c *Carry) Update(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		logging.Log(carry.ErrBadRequest)
+		web.Error(ctx, http.StatusBadRequest, carry.ErrBadRequest.Error())
+		return
+	}
+
+	var req requests.CarryPatchRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		logging.Log(carry.ErrBodyValidation)
+		web.Error(ctx, http.StatusUnprocessableEntity, carry.ErrBodyValidation.Error())
+		return
+	}
+
+	carryUpdated, err := c.service.Update(ctx, domain.Carry{
+		ID:          id,
+		CID:         req.CID,
+		CompanyName: req.CompanyName,
+		Address:     req.Address,
+		Telephone:   req.Telephone,
+		Locality_id: req.Locality_id,
+	})
+	if err != nil {
+		switch err {
+		case carry.ErrNotFound:
+			web.Error(ctx, http.StatusNotFound, err.Error())
+		case carry.ErrAlreadyExists, carry.ErrFKConstraint:
+			web.Error(ctx, http.StatusConflict, err.Error())
+		case carry.ErrDataLong:
+			web.Error(ctx, http.StatusUnprocessableEntity, err.Error())
+		default:
+			logging.Log(err)
+			web.Error(ctx, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	web.Success(ctx, http.StatusOK, carryUpdated)
+}
+
+// Delete DeleteCarry godoc
+// @Summary     Delete carry
+// @Tags        Carries
+// @Description delete carry by ID
+// @Param       id  path int true "carry id"
+// @Success     204
+// @Failure     400 {object} web.errorResponse
+// @Failure     404 {object} web.errorResponse
+// @Failure     409 {object} web.errorResponse
+// @Failure     500 {object} web.errorResponse
+// @Router      /api/v1/carries/{id} [delete]
+func (c *Carry) Delete(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		logging.Log(carry.ErrBadRequest)
+		web.Error(ctx, http.StatusBadRequest, carry.ErrBadRequest.Error())
+		return
+	}
+
+	if err := c.service.Delete(ctx, id); err != nil {
+		switch err {
+		case carry.ErrNotFound:
+			web.Error(ctx, http.StatusNotFound, err.Error())
+		case carry.ErrHasShipments:
+			web.Error(ctx, http.StatusConflict, err.Error())
+		default:
+			logging.Log(err)
+			web.Error(ctx, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	web.Success(ctx, http.StatusNoContent, nil)
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/cmd/server/handler/requests"
@@ -19,8 +20,10 @@ import (
 
 // MOCK SERVICE
 type MockCarryService struct {
-	mockCarry domain.Carry
-	mockError error
+	mockCarry   domain.Carry
+	mockCarries []domain.Carry
+	mockError   error
+	lastFilter  domain.CarryFilter
 }
 
 func (s *MockCarryService) Save(ctx context.Context, CID string, CompanyName string, Address string, Telephone string, Locality_id string) (domain.Carry, error) {
@@ -30,6 +33,32 @@ func (s *MockCarryService) Save(ctx context.Context, CID string, CompanyName str
 	return s.mockCarry, nil
 }
 
+func (s *MockCarryService) GetAll(ctx context.Context, filter domain.CarryFilter) ([]domain.Carry, error) {
+	s.lastFilter = filter
+	if s.mockError != nil {
+		return nil, s.mockError
+	}
+	return s.mockCarries, nil
+}
+
+func (s *MockCarryService) Get(ctx context.Context, id int) (domain.Carry, error) {
+	if s.mockError != nil {
+		return domain.Carry{}, s.mockError
+	}
+	return s.mockCarry, nil
+}
+
+func (s *MockCarryService) Update(ctx context.Context, c domain.Carry) (domain.Carry, error) {
+	if s.mockError != nil {
+		return domain.Carry{}, s.mockError
+	}
+	return s.mockCarry, nil
+}
+
+func (s *MockCarryService) Delete(ctx context.Context, id int) error {
+	return s.mockError
+}
+
 // MOCK GIN
 func mockCarryGin(structBody interface{}) (*gin.Context, *httptest.ResponseRecorder) {
 	logging.InitLog(nil)
@@ -46,6 +75,10 @@ func mockCarryGin(structBody interface{}) (*gin.Context, *httptest.ResponseRecor
 
 // RESPONSE STRUCTS
 
+type responseDataCarries struct {
+	Data []domain.Carry `json:"data"`
+}
+
 type responseDataCarry struct {
 	Data domain.Carry `json:"data"`
 }
@@ -296,3 +329,126 @@ func TestCarrySaveFailureInternal(t *testing.T) {
 	assert.Equal(t, expectedStatus, response.StatusCode)
 	assert.Equal(t, expectedError.Error(), responseMessage)
 }
+
+// TestCarryGetAllByProvince checks that the query filters reach the service
+// Expected HTTP Status code: 200
+func TestCarryGetAllByProvince(t *testing.T) {
+	// arrange
+	carries := []domain.Carry{{ID: 1, CID: "CID#1", Locality_id: "1"}}
+	mockService := MockCarryService{mockCarries: carries}
+	handler := NewCarry(&mockService)
+
+	ctx, recorder := mockCarryGin(nil)
+	ctx.Request.URL, _ = url.Parse("/api/v1/carries?province=Buenos%20Aires&country=Argentina")
+
+	// act
+	handler.GetAll(ctx)
+
+	// parse response body
+	var body responseDataCarries
+	err := json.Unmarshal(recorder.Body.Bytes(), &body)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, carries, body.Data)
+	assert.Equal(t, domain.CarryFilter{Province: "Buenos Aires", Country: "Argentina"}, mockService.lastFilter)
+}
+
+// TestCarryGetNotFound is correct when the carry does not exist
+// Expected HTTP Status code: 404
+func TestCarryGetNotFound(t *testing.T) {
+	// arrange
+	mockService := MockCarryService{mockError: carry.ErrNotFound}
+	handler := NewCarry(&mockService)
+
+	ctx, recorder := mockCarryGin(nil)
+	ctx.Params = gin.Params{{Key: "id", Value: "9"}}
+
+	// act
+	handler.Get(ctx)
+
+	// parse response body
+	var body carryErrorResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &body)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, carry.ErrNotFound.Error(), body.Message)
+}
+
+// TestCarryUpdate checks the correct operation of the Update handler method
+// Expected HTTP Status code: 200
+func TestCarryUpdate(t *testing.T) {
+	// arrange
+	expectedCarry := domain.Carry{ID: 1, CID: "CID#2", CompanyName: "some name", Address: "corrientes 800", Telephone: "4567-4567", Locality_id: "1"}
+	mockService := MockCarryService{mockCarry: expectedCarry}
+	handler := NewCarry(&mockService)
+
+	ctx, recorder := mockCarryGin(requests.CarryPatchRequest{CID: "CID#2"})
+	ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	// act
+	handler.Update(ctx)
+
+	// parse response body
+	var body responseDataCarry
+	err := json.Unmarshal(recorder.Body.Bytes(), &body)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, expectedCarry, body.Data)
+}
+
+// TestCarryUpdateFailureConflict is correct when the new cid belongs to another carry
+// Expected HTTP Status code: 409
+func TestCarryUpdateFailureConflict(t *testing.T) {
+	// arrange
+	mockService := MockCarryService{mockError: carry.ErrAlreadyExists}
+	handler := NewCarry(&mockService)
+
+	ctx, recorder := mockCarryGin(requests.CarryPatchRequest{CID: "CID#2"})
+	ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	// act
+	handler.Update(ctx)
+
+	// assert
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+}
+
+// TestCarryDelete checks the correct operation of the Delete handler method
+// Expected HTTP Status code: 204
+func TestCarryDelete(t *testing.T) {
+	// arrange
+	mockService := MockCarryService{}
+	handler := NewCarry(&mockService)
+
+	ctx, _ := mockCarryGin(nil)
+	ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	// act
+	handler.Delete(ctx)
+
+	// assert
+	assert.Equal(t, http.StatusNoContent, ctx.Writer.Status())
+}
+
+// TestCarryDeleteBadRequest is correct when the id is not a number
+// Expected HTTP Status code: 400
+func TestCarryDeleteBadRequest(t *testing.T) {
+	// arrange
+	mockService := MockCarryService{}
+	handler := NewCarry(&mockService)
+
+	ctx, recorder := mockCarryGin(nil)
+	ctx.Params = gin.Params{{Key: "id", Value: "one"}}
+
+	// act
+	handler.Delete(ctx)
+
+	// assert
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
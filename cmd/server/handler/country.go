@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/cmd/server/handler/requests"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/country"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type Country struct {
+	countryService country.Service
+}
+
+func NewCountry(c country.Service) *Country {
+	return &Country{
+		countryService: c,
+	}
+}
+
+// GetAll List countries godoc
+// @Summary     List countries
+// @Tags        Countries
+// @Description get every country ordered by name
+// @Produce     json
+// @Success     200 {object} web.response
+// @Failure     500 {object} web.errorResponse
+// @Router      /api/v1/countries [get]
+func (h *Country) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		countries, err := h.countryService.GetAll(c)
+		if err != nil {
+			logging.Log(err)
+			web.Error(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, countries)
+	}
+}
+
+// Get Country by id godoc
+// @Summary     Country by id
+// @Tags        Countries
+// @Description get country
+// @Produce     json
+// @Param       id  path     int true "country id"
+// @Success     200 {object} web.response
+// @Failure     400 {object} web.errorResponse
+// @Failure     404 {object} web.errorResponse
+// @Failure     500 {object} web.errorResponse
+// @Router      /api/v1/countries/{id} [get]
+func (h *Country) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		countryObtained, err := h.countryService.Get(c, id)
+		if err != nil {
+			writeCountryError(c, err)
+			return
+		}
+		web.Success(c, http.StatusOK, countryObtained)
+	}
+}
+
+// Create Country godoc
+// @Summary     Create Country
+// @Tags        Countries
+// @Description create a country, names are unique without case
+// @Accept      json
+// @Produce     json
+// @Param       country body     requests.CountryRequest true "Country to create"
+// @Success     201     {object} web.response
+// @Failure     409     {object} web.errorResponse
+// @Failure     422     {object} web.errorResponse
+// @Failure     500     {object} web.errorResponse
+// @Router      /api/v1/countries [post]
+func (h *Country) Create() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.CountryRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+
+		countryCreated, err := h.countryService.Create(c, req.Name)
+		if err != nil {
+			writeCountryError(c, err)
+			return
+		}
+		web.Success(c, http.StatusCreated, countryCreated)
+	}
+}
+
+// Update Country godoc
+// @Summary     Update Country
+// @Tags        Countries
+// @Description rename a country
+// @Accept      json
+// @Produce     json
+// @Param       id      path     int                     true "country id"
+// @Param       country body     requests.CountryRequest true "New name"
+// @Success     200     {object} web.response
+// @Failure     400     {object} web.errorResponse
+// @Failure     404     {object} web.errorResponse
+// @Failure     409     {object} web.errorResponse
+// @Failure     422     {object} web.errorResponse
+// @Failure     500     {object} web.errorResponse
+// @Router      /api/v1/countries/{id} [patch]
+func (h *Country) Update() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		var req requests.CountryRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+
+		countryUpdated, err := h.countryService.Update(c, id, req.Name)
+		if err != nil {
+			writeCountryError(c, err)
+			return
+		}
+		web.Success(c, http.StatusOK, countryUpdated)
+	}
+}
+
+// Delete Country godoc
+// @Summary     Delete Country
+// @Tags        Countries
+// @Description delete a country without provinces
+// @Param       id  path int true "country id"
+// @Success     204
+// @Failure     400 {object} web.errorResponse
+// @Failure     404 {object} web.errorResponse
+// @Failure     409 {object} web.errorResponse
+// @Failure     500 {object} web.errorResponse
+// @Router      /api/v1/countries/{id} [delete]
+func (h *Country) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		if err := h.countryService.Delete(c, id); err != nil {
+			writeCountryError(c, err)
+			return
+		}
+		web.Success(c, http.StatusNoContent, nil)
+	}
+}
+
+func writeCountryError(c *gin.Context, err error) {
+	logging.Log(err)
+	switch err {
+	case country.ErrNotFound:
+		web.Error(c, http.StatusNotFound, err.Error())
+	case country.ErrAlreadyExists, country.ErrHasProvinces:
+		web.Error(c, http.StatusConflict, err.Error())
+	case country.ErrBadRequest:
+		web.Error(c, http.StatusUnprocessableEntity, err.Error())
+	default:
+		web.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
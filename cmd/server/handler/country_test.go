@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/country"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func createServerCountry(mockRepository *country.MockRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewCountry(country.NewService(mockRepository))
+
+	r := gin.Default()
+	cou := r.Group("/api/v1/countries")
+	cou.GET("", handler.GetAll())
+	cou.GET("/:id", handler.Get())
+	cou.POST("", handler.Create())
+	cou.PATCH("/:id", handler.Update())
+	cou.DELETE("/:id", handler.Delete())
+	return r
+}
+
+// TestCreateCountry_OK passes when the country is created (status code 201)
+func TestCreateCountry_OK(t *testing.T) {
+	r := createServerCountry(&country.MockRepository{})
+	req, recorder := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/countries", `{"name":" Argentina "}`)
+	r.ServeHTTP(recorder, req)
+
+	var body struct {
+		Data domain.Country `json:"data"`
+	}
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Equal(t, domain.Country{ID: 1, Name: "Argentina"}, body.Data)
+}
+
+// TestCreateCountry_FailConflict passes when the name already exists (status code 409)
+func TestCreateCountry_FailConflict(t *testing.T) {
+	r := createServerCountry(&country.MockRepository{ErrorMock: country.ErrAlreadyExists})
+	req, recorder := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/countries", `{"name":"argentina"}`)
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+}
+
+// TestGetCountry_FailNotFound passes when the country does not exist (status code 404)
+func TestGetCountry_FailNotFound(t *testing.T) {
+	r := createServerCountry(&country.MockRepository{})
+	req, recorder := createRequestTestPurchaseOrders(http.MethodGet, "/api/v1/countries/7", "")
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+// TestDeleteCountry_FailHasProvinces passes when the country still has provinces (status code 409)
+func TestDeleteCountry_FailHasProvinces(t *testing.T) {
+	r := createServerCountry(&country.MockRepository{ErrorMock: country.ErrHasProvinces})
+	req, recorder := createRequestTestPurchaseOrders(http.MethodDelete, "/api/v1/countries/1", "")
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+}
@@ -185,12 +185,17 @@ func (e *Employee) Update() gin.HandlerFunc {
 // Delete godoc
 // @Summary     Delete employee
 // @Tags        Employees
-// @Description Deletes an existing employee from database
+// @Description Deletes an existing employee from database, block fails while the employee has inbound orders
+// @Description and reassign moves them to the employee to
 // @Produce     json
-// @Param       id path int true "Employee id"
+// @Param       id       path  int    true  "Employee id"
+// @Param       strategy query string false "block (default) or reassign"
+// @Param       to       query int    false "Employee that receives the inbound orders on reassign"
 // @Success     204
-// @Failure     400 {object} web.errorResponse "Invalid id type"
+// @Failure     400 {object} web.errorResponse "Invalid id type or strategy"
 // @Failure     404 {object} web.errorResponse "Employee not found"
+// @Failure     409 {object} web.errorResponse "Employee has inbound orders"
+// @Failure     422 {object} web.errorResponse "Employee to reassign to not found"
 // @Failure     500 {object} web.errorResponse "Connection to dabatase error"
 // @Router      /api/v1/employees/{id} [delete]
 func (e *Employee) Delete() gin.HandlerFunc {
@@ -203,13 +208,26 @@ func (e *Employee) Delete() gin.HandlerFunc {
 			return
 		}
 
-		err = e.employeeService.Delete(ctx, id)
+		options, err := deleteOptions(ctx)
+		if err != nil {
+			logging.Log(err)
+			web.Error(ctx, http.StatusBadRequest, employee.ErrInvalidDeleteStrategy.Error())
+			return
+		}
+
+		err = e.employeeService.Delete(ctx, id, options)
 
 		if err != nil {
 			logging.Log(err)
 			switch err.Error() {
 			case employee.ErrEmployeeNotFound.Error():
 				web.Error(ctx, http.StatusNotFound, employee.ErrEmployeeNotFound.Error())
+			case employee.ErrInvalidDeleteStrategy.Error():
+				web.Error(ctx, http.StatusBadRequest, err.Error())
+			case employee.ErrEmployeeHasInboundOrders.Error():
+				web.Error(ctx, http.StatusConflict, err.Error())
+			case employee.ErrEmployeeTargetNotFound.Error():
+				web.Error(ctx, http.StatusUnprocessableEntity, err.Error())
 			default:
 				web.Error(ctx, http.StatusInternalServerError, err.Error())
 			}
@@ -219,3 +237,41 @@ func (e *Employee) Delete() gin.HandlerFunc {
 		web.Success(ctx, http.StatusNoContent, "")
 	}
 }
+
+// DeletePreview godoc
+// @Summary     Preview the delete of an employee
+// @Tags        Employees
+// @Description Retrieves the inbound orders a delete of the employee affects and the strategies it can be deleted with
+// @Produce     json
+// @Param       id  path     int               true "Employee id"
+// @Success     200 {object} web.response      "Delete preview"
+// @Failure     400 {object} web.errorResponse "Invalid id type"
+// @Failure     404 {object} web.errorResponse "Employee not found"
+// @Failure     500 {object} web.errorResponse "Connection to database error"
+// @Router      /api/v1/employees/{id}/deletePreview [get]
+func (e *Employee) DeletePreview() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.Atoi(ctx.Param("id"))
+
+		if err != nil {
+			logging.Log("invalid id")
+			web.Error(ctx, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		preview, err := e.employeeService.DeletePreview(ctx, id)
+
+		if err != nil {
+			logging.Log(err)
+			switch err.Error() {
+			case employee.ErrEmployeeNotFound.Error():
+				web.Error(ctx, http.StatusNotFound, employee.ErrEmployeeNotFound.Error())
+			default:
+				web.Error(ctx, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+
+		web.Success(ctx, http.StatusOK, preview)
+	}
+}
@@ -40,6 +40,7 @@ func createServerEmployee(mockRepository employee.MockRepository) *gin.Engine {
 	employeesRoutesGroup.POST("/", employeeHandler.Create())
 	employeesRoutesGroup.PATCH("/:id", employeeHandler.Update())
 	employeesRoutesGroup.DELETE("/:id", employeeHandler.Delete())
+	employeesRoutesGroup.GET("/:id/deletePreview", employeeHandler.DeletePreview())
 
 	return router
 }
@@ -315,3 +316,81 @@ func TestDeleteEmployeeInvalidID(t *testing.T) {
 	router.ServeHTTP(recorder, req)
 	assert.Equal(t, 400, recorder.Code)
 }
+
+func TestDeleteEmployeeWithInboundOrders(t *testing.T) {
+	mockRepository := employee.MockRepository{
+		DataMock:      []domain.Employee{{ID: 1, CardNumberID: "123456", FirstName: "John", LastName: "Doe", WarehouseID: 3}},
+		InboundOrders: map[int][]int{1: {4}},
+	}
+
+	router := createServerEmployee(mockRepository)
+	req, recorder := createRequestTestEmployee(http.MethodDelete, "/api/v1/employees/1", "")
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+}
+
+func TestDeleteEmployeeReassign(t *testing.T) {
+	mockRepository := employee.MockRepository{
+		DataMock: []domain.Employee{
+			{ID: 1, CardNumberID: "123456", FirstName: "John", LastName: "Doe", WarehouseID: 3},
+			{ID: 2, CardNumberID: "654321", FirstName: "Jane", LastName: "Doe", WarehouseID: 7},
+		},
+		InboundOrders: map[int][]int{1: {4}},
+	}
+
+	router := createServerEmployee(mockRepository)
+	req, recorder := createRequestTestEmployee(http.MethodDelete, "/api/v1/employees/1?strategy=reassign&to=2", "")
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+}
+
+func TestDeleteEmployeeReassignTargetNotFound(t *testing.T) {
+	mockRepository := employee.MockRepository{
+		DataMock:      []domain.Employee{{ID: 1, CardNumberID: "123456", FirstName: "John", LastName: "Doe", WarehouseID: 3}},
+		InboundOrders: map[int][]int{1: {4}},
+	}
+
+	router := createServerEmployee(mockRepository)
+	req, recorder := createRequestTestEmployee(http.MethodDelete, "/api/v1/employees/1?strategy=reassign&to=9", "")
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+}
+
+func TestDeleteEmployeeInvalidStrategy(t *testing.T) {
+	mockRepository := employee.MockRepository{
+		DataMock: []domain.Employee{{ID: 1, CardNumberID: "123456", FirstName: "John", LastName: "Doe", WarehouseID: 3}},
+	}
+
+	router := createServerEmployee(mockRepository)
+	req, recorder := createRequestTestEmployee(http.MethodDelete, "/api/v1/employees/1?strategy=detach", "")
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestDeletePreviewEmployee(t *testing.T) {
+	mockRepository := employee.MockRepository{
+		DataMock:      []domain.Employee{{ID: 1, CardNumberID: "123456", FirstName: "John", LastName: "Doe", WarehouseID: 3}},
+		InboundOrders: map[int][]int{1: {4, 6}},
+	}
+
+	router := createServerEmployee(mockRepository)
+	req, recorder := createRequestTestEmployee(http.MethodGet, "/api/v1/employees/1/deletePreview", "")
+	router.ServeHTTP(recorder, req)
+
+	var response struct {
+		Data domain.DeletePreview `json:"data"`
+	}
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, domain.DeletePreview{ID: 1, Strategies: employee.DeleteStrategies, Blocked: true,
+		Dependents: map[string][]int{"inbound_orders": {4, 6}}}, response.Data)
+}
+
+func TestDeletePreviewEmployeeNotFound(t *testing.T) {
+	mockRepository := employee.MockRepository{MockError: employee.ErrEmployeeNotFound}
+
+	router := createServerEmployee(mockRepository)
+	req, recorder := createRequestTestEmployee(http.MethodGet, "/api/v1/employees/1/deletePreview", "")
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/excursion"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type Excursion struct {
+	excursionService excursion.Service
+}
+
+func NewExcursion(s excursion.Service) *Excursion {
+	return &Excursion{
+		excursionService: s,
+	}
+}
+
+// GetAll ListExcursions godoc
+// @Summary     List temperature excursions
+// @Tags        Excursions
+// @Description get the periods in which a section was warmer than a product batch stored in it allows
+// @Produce     json
+// @Param       status     query    string false "open or closed"
+// @Param       section_id query    int    false "section id"
+// @Success     200        {object} web.response
+// @Failure     400        {object} web.errorResponse
+// @Failure     500        {object} web.errorResponse
+// @Router      /api/v1/excursions [get]
+func (e *Excursion) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var sectionID int
+		if value := c.Query("section_id"); value != "" {
+			var err error
+			if sectionID, err = strconv.Atoi(value); err != nil {
+				logging.Log(err)
+				web.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		data, err := e.excursionService.GetAll(c, c.Query("status"), sectionID)
+		if err != nil {
+			switch err {
+			case excursion.ErrInvalidStatus:
+				web.Error(c, http.StatusBadRequest, err.Error())
+			default:
+				web.Error(c, http.StatusInternalServerError, err.Error())
+			}
+			logging.Log(err)
+			return
+		}
+		web.Success(c, http.StatusOK, data)
+	}
+}
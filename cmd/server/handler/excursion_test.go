@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/excursion"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type responseExcursions struct {
+	Data []domain.Excursion `json:"data"`
+}
+
+func createExcursionServer(mockRepository *excursion.MockRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewExcursion(excursion.NewService(mockRepository, time.Now))
+	router := gin.Default()
+
+	router.GET("/excursions", handler.GetAll())
+
+	return router
+}
+
+func TestExcursionGetAllOk(t *testing.T) {
+	var response responseExcursions
+	started := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	router := createExcursionServer(&excursion.MockRepository{DataMockExcursions: []domain.Excursion{
+		{ID: 1, SectionID: 1, ProductBatchID: 1, LimitTemperature: -18, MaximumTemperature: -10, StartedAt: started},
+		{ID: 2, SectionID: 2, ProductBatchID: 2, LimitTemperature: -18, MaximumTemperature: -10, StartedAt: started},
+	}})
+
+	req, rw := createRequestTest(http.MethodGet, "/excursions?status=open&section_id=2", "")
+	router.ServeHTTP(rw, req)
+
+	assert.Equal(t, 200, rw.Code)
+	assert.NoError(t, json.Unmarshal(rw.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, 2, response.Data[0].ID)
+	assert.Nil(t, response.Data[0].EndedAt)
+}
+
+func TestExcursionGetAllBadRequest(t *testing.T) {
+	router := createExcursionServer(&excursion.MockRepository{})
+
+	req, rw := createRequestTest(http.MethodGet, "/excursions?status=pending", "")
+	router.ServeHTTP(rw, req)
+	assert.Equal(t, 400, rw.Code)
+
+	req, rw = createRequestTest(http.MethodGet, "/excursions?section_id=one", "")
+	router.ServeHTTP(rw, req)
+	assert.Equal(t, 400, rw.Code)
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/geo"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultNearestWarehouses is used by the nearest warehouses query when no limit is requested
+const DefaultNearestWarehouses = 5
+
+type Geo struct {
+	geoService geo.Service
+}
+
+func NewGeo(g geo.Service) *Geo {
+	return &Geo{
+		geoService: g,
+	}
+}
+
+// NearestWarehouses godoc
+// @Summary     Nearest warehouses
+// @Tags        Localities
+// @Description get the warehouses closest to a locality, with their distance in kilometers.
+// @Description Warehouses whose locality has no coordinates are left out.
+// @Produce     json
+// @Param       id    path     string true  "locality id"
+// @Param       limit query    int    false "number of warehouses, 5 by default"
+// @Success     200   {object} web.response
+// @Failure     400   {object} web.errorResponse
+// @Failure     404   {object} web.errorResponse
+// @Failure     409   {object} web.errorResponse "The locality has no coordinates"
+// @Failure     500   {object} web.errorResponse
+// @Router      /api/v1/localities/{id}/nearestWarehouses [get]
+func (h *Geo) NearestWarehouses() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := DefaultNearestWarehouses
+		if value := c.Query("limit"); value != "" {
+			var err error
+			if limit, err = strconv.Atoi(value); err != nil {
+				web.Error(c, http.StatusBadRequest, geo.ErrInvalidLimit.Error())
+				return
+			}
+		}
+
+		warehouses, err := h.geoService.NearestWarehouses(c, c.Param("id"), limit)
+		if err != nil {
+			writeGeoError(c, err)
+			return
+		}
+		web.Success(c, http.StatusOK, warehouses)
+	}
+}
+
+// CarriesWithin godoc
+// @Summary     Carries within a radius
+// @Tags        Localities
+// @Description get the carries at most radius_km kilometers away from a locality, closest first.
+// @Description Carries whose locality has no coordinates are left out.
+// @Produce     json
+// @Param       id        path     string true "locality id"
+// @Param       radius_km query    number true "radius in kilometers"
+// @Success     200       {object} web.response
+// @Failure     400       {object} web.errorResponse
+// @Failure     404       {object} web.errorResponse
+// @Failure     409       {object} web.errorResponse "The locality has no coordinates"
+// @Failure     500       {object} web.errorResponse
+// @Router      /api/v1/localities/{id}/carries [get]
+func (h *Geo) CarriesWithin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		radiusKm, err := strconv.ParseFloat(c.Query("radius_km"), 64)
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, geo.ErrInvalidRadius.Error())
+			return
+		}
+
+		carries, err := h.geoService.CarriesWithin(c, c.Param("id"), radiusKm)
+		if err != nil {
+			writeGeoError(c, err)
+			return
+		}
+		web.Success(c, http.StatusOK, carries)
+	}
+}
+
+func writeGeoError(c *gin.Context, err error) {
+	logging.Log(err)
+	switch err {
+	case geo.ErrInvalidLimit, geo.ErrInvalidRadius:
+		web.Error(c, http.StatusBadRequest, err.Error())
+	case geo.ErrLocalityNotFound:
+		web.Error(c, http.StatusNotFound, err.Error())
+	case geo.ErrNoCoordinates:
+		web.Error(c, http.StatusConflict, err.Error())
+	default:
+		web.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/geo"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	geoBuenosAires = domain.Coordinates{Latitude: -34.603722, Longitude: -58.381592}
+	geoLaPlata     = domain.Coordinates{Latitude: -34.921450, Longitude: -57.954530}
+	geoCordoba     = domain.Coordinates{Latitude: -31.420083, Longitude: -64.188776}
+)
+
+func createServerGeo(mockRepository *geo.MockRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewGeo(geo.NewService(mockRepository))
+
+	r := gin.Default()
+	loc := r.Group("/api/v1/localities")
+	loc.GET("/:id/nearestWarehouses", handler.NearestWarehouses())
+	loc.GET("/:id/carries", handler.CarriesWithin())
+	return r
+}
+
+// TestNearestWarehouses_OK passes when the closest warehouse comes first (status code 200)
+func TestNearestWarehouses_OK(t *testing.T) {
+	repo := geo.MockRepository{
+		Localities: map[string]domain.Coordinates{"1000": geoBuenosAires},
+		Warehouses: []domain.WarehouseDistance{
+			{Warehouse: domain.Warehouse{ID: 1, LocalityID: "5000"}, Coordinates: geoCordoba},
+			{Warehouse: domain.Warehouse{ID: 2, LocalityID: "1900"}, Coordinates: geoLaPlata},
+		},
+	}
+	r := createServerGeo(&repo)
+	req, recorder := createRequestTestPurchaseOrders(http.MethodGet, "/api/v1/localities/1000/nearestWarehouses?limit=1", "")
+	r.ServeHTTP(recorder, req)
+
+	var body struct {
+		Data []domain.WarehouseDistance `json:"data"`
+	}
+	err := json.Unmarshal(recorder.Body.Bytes(), &body)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Len(t, body.Data, 1)
+	assert.Equal(t, 2, body.Data[0].ID)
+	assert.Equal(t, "1900", body.Data[0].LocalityID)
+	assert.InDelta(t, 52.6, body.Data[0].DistanceKm, 0.1)
+}
+
+// TestNearestWarehouses_Fail passes when the limit is invalid (400) or the locality does not exist (404)
+func TestNearestWarehouses_Fail(t *testing.T) {
+	r := createServerGeo(&geo.MockRepository{})
+
+	reqLimit, recorderLimit := createRequestTestPurchaseOrders(http.MethodGet, "/api/v1/localities/1000/nearestWarehouses?limit=abc", "")
+	r.ServeHTTP(recorderLimit, reqLimit)
+	reqLocality, recorderLocality := createRequestTestPurchaseOrders(http.MethodGet, "/api/v1/localities/1000/nearestWarehouses", "")
+	r.ServeHTTP(recorderLocality, reqLocality)
+
+	assert.Equal(t, http.StatusBadRequest, recorderLimit.Code)
+	assert.Equal(t, http.StatusNotFound, recorderLocality.Code)
+}
+
+// TestCarriesWithin_OK passes when only the carries inside the radius are returned (status code 200)
+func TestCarriesWithin_OK(t *testing.T) {
+	repo := geo.MockRepository{
+		Localities: map[string]domain.Coordinates{"1000": geoBuenosAires},
+		Carries: []domain.CarryDistance{
+			{Carry: domain.Carry{ID: 1}, Coordinates: geoCordoba},
+			{Carry: domain.Carry{ID: 2}, Coordinates: geoLaPlata},
+		},
+	}
+	r := createServerGeo(&repo)
+	req, recorder := createRequestTestPurchaseOrders(http.MethodGet, "/api/v1/localities/1000/carries?radius_km=100", "")
+	r.ServeHTTP(recorder, req)
+
+	var body struct {
+		Data []domain.CarryDistance `json:"data"`
+	}
+	err := json.Unmarshal(recorder.Body.Bytes(), &body)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Len(t, body.Data, 1)
+	assert.Equal(t, 2, body.Data[0].ID)
+}
+
+// TestCarriesWithin_FailRadius passes when the radius is missing or not positive (status code 400)
+func TestCarriesWithin_FailRadius(t *testing.T) {
+	r := createServerGeo(&geo.MockRepository{Localities: map[string]domain.Coordinates{"1000": geoBuenosAires}})
+
+	reqMissing, recorderMissing := createRequestTestPurchaseOrders(http.MethodGet, "/api/v1/localities/1000/carries", "")
+	r.ServeHTTP(recorderMissing, reqMissing)
+	reqNegative, recorderNegative := createRequestTestPurchaseOrders(http.MethodGet, "/api/v1/localities/1000/carries?radius_km=-5", "")
+	r.ServeHTTP(recorderNegative, reqNegative)
+
+	assert.Equal(t, http.StatusBadRequest, recorderMissing.Code)
+	assert.Equal(t, http.StatusBadRequest, recorderNegative.Code)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/cmd/server/handler/requests"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/inbound_order"
+	productbatch "github.com/extmatperez/meli_bootcamp_go_w6-2/internal/productBatch"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/web"
 	"github.com/gin-gonic/gin"
@@ -25,13 +26,50 @@ func NewInboundOrder(ib inbound_order.Service) *InboundOrder {
 // GetAllEmployeesInboundOrders godoc
 // @Summary     List employees and their inbound orders
 // @Tags        Inbound Orders
-// @Description Lists all existing employees and their inbound orders from database
+// @Description Lists all existing employees and their inbound orders from database.
+// @Description With from, to or warehouse_id the employees that received orders in the period are listed with their received units and a bucket per day,
+// @Description with top the N employees with most orders and with most received units are ranked instead.
+// @Description Use format=csv or an Accept: text/csv header to get the report as CSV
 // @Produce     json
-// @Success     200 {object} web.response      "List of employees and their inbound orders"
-// @Failure     500 {object} web.errorResponse "Connection to database error"
+// @Produce     text/csv
+// @Param       from         query    string            false "Minimum order date (yyyy-mm-dd)"
+// @Param       to           query    string            false "Maximum order date (yyyy-mm-dd)"
+// @Param       warehouse_id query    int               false "Warehouse of the inbound orders"
+// @Param       top          query    int               false "Size of the rankings"
+// @Param       format       query    string            false "json or csv"
+// @Success     200          {object} web.response      "List of employees and their inbound orders"
+// @Failure     400          {object} web.errorResponse "Invalid filter"
+// @Failure     500          {object} web.errorResponse "Connection to database error"
 // @Router      /api/v1/employees/reportInboundOrders [get]
 func (inboundOrder *InboundOrder) GetAllEmployeesInboundOrders() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		filter := domain.ProductivityFilter{From: ctx.Query("from"), To: ctx.Query("to")}
+
+		if warehouseID := ctx.Query("warehouse_id"); warehouseID != "" {
+			var err error
+			if filter.WarehouseID, err = strconv.Atoi(warehouseID); err != nil {
+				logging.Log(err)
+				web.Error(ctx, http.StatusBadRequest, "invalid warehouse_id")
+				return
+			}
+		}
+
+		if top := ctx.Query("top"); top != "" {
+			n, err := strconv.Atoi(top)
+			if err != nil {
+				logging.Log(err)
+				web.Error(ctx, http.StatusBadRequest, "invalid top")
+				return
+			}
+			inboundOrder.productivityRanking(ctx, filter, n)
+			return
+		}
+
+		if !filter.IsZero() || web.WantsCSV(ctx) {
+			inboundOrder.productivity(ctx, filter)
+			return
+		}
+
 		employees, err := inboundOrder.inboundOrderService.GetAllEmployeesInboundOrders(ctx)
 
 		if err != nil {
@@ -49,6 +87,83 @@ func (inboundOrder *InboundOrder) GetAllEmployeesInboundOrders() gin.HandlerFunc
 	}
 }
 
+// productivity writes the inbound orders and received units of the employees in the period, with one CSV row per employee and day
+func (inboundOrder *InboundOrder) productivity(ctx *gin.Context, filter domain.ProductivityFilter) {
+	employees, err := inboundOrder.inboundOrderService.GetEmployeesProductivity(ctx, filter)
+
+	if err != nil {
+		writeProductivityError(ctx, err)
+		return
+	}
+
+	if web.WantsCSV(ctx) {
+		header := []string{"employee_id", "card_number_id", "first_name", "last_name", "warehouse_id", "date", "inbound_orders_count", "received_units"}
+		var records [][]string
+		for _, employee := range employees {
+			for _, day := range employee.Days {
+				records = append(records, []string{
+					strconv.Itoa(employee.ID), employee.CardNumberID, employee.FirstName, employee.LastName, strconv.Itoa(employee.WarehouseID),
+					day.Date, strconv.Itoa(day.InboundOrders), strconv.Itoa(day.ReceivedUnits),
+				})
+			}
+		}
+		web.CSV(ctx, http.StatusOK, "employees_productivity.csv", header, records)
+		return
+	}
+
+	if employees == nil {
+		web.Success(ctx, http.StatusOK, []domain.EmployeeProductivity{})
+		return
+	}
+
+	web.Success(ctx, http.StatusOK, employees)
+}
+
+// productivityRanking writes the top employees of the period, with one CSV row per ranking and position
+func (inboundOrder *InboundOrder) productivityRanking(ctx *gin.Context, filter domain.ProductivityFilter, top int) {
+	ranking, err := inboundOrder.inboundOrderService.GetProductivityRanking(ctx, filter, top)
+
+	if err != nil {
+		writeProductivityError(ctx, err)
+		return
+	}
+
+	if web.WantsCSV(ctx) {
+		header := []string{"ranking", "position", "employee_id", "card_number_id", "first_name", "last_name", "warehouse_id", "inbound_orders_count", "received_units"}
+		var records [][]string
+		rankings := []struct {
+			name      string
+			employees []domain.EmployeeProductivity
+		}{{"inbound_orders", ranking.ByInboundOrders}, {"received_units", ranking.ByReceivedUnits}}
+		for _, r := range rankings {
+			for i, employee := range r.employees {
+				records = append(records, []string{
+					r.name, strconv.Itoa(i + 1), strconv.Itoa(employee.ID), employee.CardNumberID, employee.FirstName, employee.LastName,
+					strconv.Itoa(employee.WarehouseID), strconv.Itoa(employee.InboundOrders), strconv.Itoa(employee.ReceivedUnits),
+				})
+			}
+		}
+		web.CSV(ctx, http.StatusOK, "employees_ranking.csv", header, records)
+		return
+	}
+
+	if ranking.ByInboundOrders == nil {
+		ranking = domain.ProductivityRanking{ByInboundOrders: []domain.EmployeeProductivity{}, ByReceivedUnits: []domain.EmployeeProductivity{}}
+	}
+
+	web.Success(ctx, http.StatusOK, ranking)
+}
+
+func writeProductivityError(ctx *gin.Context, err error) {
+	logging.Log(err)
+	switch err {
+	case inbound_order.ErrInvalidDateRange, inbound_order.ErrInvalidTop:
+		web.Error(ctx, http.StatusBadRequest, err.Error())
+	default:
+		web.Error(ctx, http.StatusInternalServerError, err.Error())
+	}
+}
+
 // GetEmployeeInboundOrders godoc
 // @Summary     Get employee by ID
 // @Tags        InboundOrders
@@ -96,7 +211,7 @@ func (inboundOrder *InboundOrder) GetEmployeeInboundOrders() gin.HandlerFunc {
 // @Param       inboundOrder body     requests.InboundOrderDTOPOST true "Inbound order to be stored"
 // @Success     201          {object} web.response                 "Inbound order created"
 // @Failure     409          {object} web.errorResponse            "Inbound order with order number already exists error"
-// @Failure     422          {object} web.errorResponse            "Missing field or type casting error"
+// @Failure     422          {object} web.errorResponse            "Missing field, type casting error or invalid order date"
 // @Failure     500          {object} web.errorResponse            "Connection to database error"
 // @Router      /api/v1/inboundOrders [post]
 func (inboundOrder *InboundOrder) Create() gin.HandlerFunc {
@@ -121,6 +236,8 @@ func (inboundOrder *InboundOrder) Create() gin.HandlerFunc {
 				web.Error(ctx, http.StatusInternalServerError, inbound_order.ErrInboundOrderNotSaved.Error())
 			case inbound_order.ErrEmptyOrderNumber:
 				web.Error(ctx, http.StatusConflict, inbound_order.ErrEmptyOrderNumber.Error())
+			case inbound_order.ErrInvalidOrderDate:
+				web.Error(ctx, http.StatusUnprocessableEntity, inbound_order.ErrInvalidOrderDate.Error())
 			case inbound_order.ErrEmployeeNonExistent:
 				web.Error(ctx, http.StatusNotFound, inbound_order.ErrEmployeeNonExistent.Error())
 			case inbound_order.ErrWarehouseNonExistent:
@@ -136,3 +253,259 @@ func (inboundOrder *InboundOrder) Create() gin.HandlerFunc {
 		web.Success(ctx, http.StatusCreated, newInboundOrder)
 	}
 }
+
+// GetAll godoc
+// @Summary     List inbound orders
+// @Tags        InboundOrders
+// @Description Lists the inbound orders from database, the most recent first
+// @Produce     json
+// @Param       warehouse_id     query    int               false "Warehouse id"
+// @Param       employee_id      query    int               false "Employee id"
+// @Param       product_batch_id query    int               false "Product batch id"
+// @Param       from             query    string            false "Minimum order date (yyyy-mm-dd)"
+// @Param       to               query    string            false "Maximum order date (yyyy-mm-dd)"
+// @Success     200              {object} web.response      "List of inbound orders"
+// @Failure     400              {object} web.errorResponse "Invalid filter"
+// @Failure     500              {object} web.errorResponse "Connection to database error"
+// @Router      /api/v1/inboundOrders [get]
+func (inboundOrder *InboundOrder) GetAll() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var filter domain.InboundOrderFilter
+		ids := map[string]*int{
+			"warehouse_id":     &filter.WarehouseID,
+			"employee_id":      &filter.EmployeeID,
+			"product_batch_id": &filter.ProductBatchID,
+		}
+
+		for param, id := range ids {
+			value := ctx.Query(param)
+			if value == "" {
+				continue
+			}
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				logging.Log(err)
+				web.Error(ctx, http.StatusBadRequest, "invalid %s", param)
+				return
+			}
+			*id = parsed
+		}
+		filter.From = ctx.Query("from")
+		filter.To = ctx.Query("to")
+
+		inboundOrders, err := inboundOrder.inboundOrderService.GetAll(ctx, filter)
+
+		if err != nil {
+			logging.Log(err)
+			switch err {
+			case inbound_order.ErrInvalidDateRange:
+				web.Error(ctx, http.StatusBadRequest, err.Error())
+			default:
+				web.Error(ctx, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+
+		if inboundOrders == nil {
+			web.Success(ctx, http.StatusOK, []domain.InboundOrder{})
+			return
+		}
+
+		web.Success(ctx, http.StatusOK, inboundOrders)
+	}
+}
+
+// Get godoc
+// @Summary     Get inbound order by ID
+// @Tags        InboundOrders
+// @Description Retrieves an existing inbound order by ID from database
+// @Produce     json
+// @Param       id  path     int               true "Inbound order id"
+// @Success     200 {object} web.response      "Inbound order"
+// @Failure     400 {object} web.errorResponse "Invalid id type"
+// @Failure     404 {object} web.errorResponse "Inbound order not found"
+// @Failure     500 {object} web.errorResponse "Connection to database error"
+// @Router      /api/v1/inboundOrders/{id} [get]
+func (inboundOrder *InboundOrder) Get() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.Atoi(ctx.Param("id"))
+
+		if err != nil {
+			logging.Log("invalid id")
+			web.Error(ctx, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		obtainedInboundOrder, err := inboundOrder.inboundOrderService.Get(ctx, id)
+
+		if err != nil {
+			logging.Log(err)
+			switch err {
+			case inbound_order.ErrInboundOrderNotFound:
+				web.Error(ctx, http.StatusNotFound, err.Error())
+			default:
+				web.Error(ctx, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+
+		web.Success(ctx, http.StatusOK, obtainedInboundOrder)
+	}
+}
+
+// Update godoc
+// @Summary     Update inbound order
+// @Tags        InboundOrders
+// @Description Updates the given fields of an existing inbound order, cancelled orders cannot be updated
+// @Accept      json
+// @Produce     json
+// @Param       id           path     int                           true "Inbound order id"
+// @Param       inboundOrder body     requests.InboundOrderDTOPATCH true "Fields to update"
+// @Success     200          {object} web.response                  "Inbound order updated"
+// @Failure     400          {object} web.errorResponse             "Invalid id type"
+// @Failure     404          {object} web.errorResponse             "Inbound order, employee, warehouse or product batch not found"
+// @Failure     409          {object} web.errorResponse             "Inbound order cancelled or order number already exists"
+// @Failure     422          {object} web.errorResponse             "Type casting error or invalid order date"
+// @Failure     500          {object} web.errorResponse             "Connection to database error"
+// @Router      /api/v1/inboundOrders/{id} [patch]
+func (inboundOrder *InboundOrder) Update() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req requests.InboundOrderDTOPATCH
+		id, err := strconv.Atoi(ctx.Param("id"))
+
+		if err != nil {
+			logging.Log("invalid id")
+			web.Error(ctx, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			logging.Log(err)
+			web.Error(ctx, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+
+		inboundOrderToUpdate := domain.InboundOrder{ID: id, OrderDate: req.OrderDate, OrderNumber: req.OrderNumber, EmployeeID: req.EmployeeID, ProductBatchID: req.ProductBatchID, WarehouseID: req.WarehouseID}
+		updatedInboundOrder, err := inboundOrder.inboundOrderService.Update(ctx, inboundOrderToUpdate)
+
+		if err != nil {
+			logging.Log(err)
+			switch err {
+			case inbound_order.ErrInboundOrderNotFound, inbound_order.ErrEmployeeNonExistent, inbound_order.ErrWarehouseNonExistent, inbound_order.ErrProductBatchNonExistent:
+				web.Error(ctx, http.StatusNotFound, err.Error())
+			case inbound_order.ErrInboundOrderCancelled, inbound_order.ErrInboundOrderAlreadyExists:
+				web.Error(ctx, http.StatusConflict, err.Error())
+			case inbound_order.ErrInvalidOrderDate:
+				web.Error(ctx, http.StatusUnprocessableEntity, err.Error())
+			default:
+				web.Error(ctx, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+
+		web.Success(ctx, http.StatusOK, updatedInboundOrder)
+	}
+}
+
+// Cancel godoc
+// @Summary     Cancel inbound order
+// @Tags        InboundOrders
+// @Description Cancels an existing inbound order, cancelled orders are not counted in the employee reports
+// @Produce     json
+// @Param       id  path     int               true "Inbound order id"
+// @Success     200 {object} web.response      "Inbound order cancelled"
+// @Failure     400 {object} web.errorResponse "Invalid id type"
+// @Failure     404 {object} web.errorResponse "Inbound order not found"
+// @Failure     409 {object} web.errorResponse "Inbound order already cancelled"
+// @Failure     500 {object} web.errorResponse "Connection to database error"
+// @Router      /api/v1/inboundOrders/{id}/cancel [post]
+func (inboundOrder *InboundOrder) Cancel() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.Atoi(ctx.Param("id"))
+
+		if err != nil {
+			logging.Log("invalid id")
+			web.Error(ctx, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		cancelledInboundOrder, err := inboundOrder.inboundOrderService.Cancel(ctx, id)
+
+		if err != nil {
+			logging.Log(err)
+			switch err {
+			case inbound_order.ErrInboundOrderNotFound:
+				web.Error(ctx, http.StatusNotFound, err.Error())
+			case inbound_order.ErrInboundOrderCancelled:
+				web.Error(ctx, http.StatusConflict, err.Error())
+			default:
+				web.Error(ctx, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+
+		web.Success(ctx, http.StatusOK, cancelledInboundOrder)
+	}
+}
+
+// Receive godoc
+// @Summary     Receive inbound order
+// @Tags        InboundOrders
+// @Description Creates the received product batch and its inbound order at once, the batch is stored in a section of the warehouse of the order
+// @Accept      json
+// @Produce     json
+// @Param       receipt body     requests.InboundOrderReceiveDTOPOST true "Inbound order and received batch"
+// @Success     201     {object} web.response                        "Inbound order and product batch created"
+// @Failure     404     {object} web.errorResponse                   "Employee, warehouse, section or product not found"
+// @Failure     409     {object} web.errorResponse                   "Order or batch number already exists, section full or too warm"
+// @Failure     422     {object} web.errorResponse                   "Missing field, invalid dates or quantity, or section of another warehouse"
+// @Failure     500     {object} web.errorResponse                   "Connection to database error"
+// @Router      /api/v1/inboundOrders/receive [post]
+func (inboundOrder *InboundOrder) Receive() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req requests.InboundOrderReceiveDTOPOST
+
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			logging.Log(err)
+			web.Error(ctx, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+
+		batch := req.ProductBatch
+		receipt := domain.InboundReceipt{
+			InboundOrder: domain.InboundOrder{OrderDate: *req.OrderDate, OrderNumber: *req.OrderNumber, EmployeeID: *req.EmployeeID, WarehouseID: *req.WarehouseID},
+			ProductBatch: domain.ProductBatch{
+				BatchNumber:        *batch.BatchNumber,
+				CurrentQuantity:    *batch.Quantity,
+				CurrentTemperature: *batch.CurrentTemperature,
+				MinimumTemperature: *batch.MinimumTemperature,
+				DueDate:            *batch.DueDate,
+				ManufacturingDate:  *batch.ManufacturingDate,
+				ManufacturingHour:  *batch.ManufacturingHour,
+				ProductID:          *batch.ProductID,
+				SectionID:          *batch.SectionID,
+			},
+		}
+
+		received, err := inboundOrder.inboundOrderService.Receive(ctx, receipt)
+
+		if err != nil {
+			logging.Log(err)
+			switch err {
+			case inbound_order.ErrEmployeeNonExistent, inbound_order.ErrWarehouseNonExistent, inbound_order.ErrSectionNonExistent,
+				productbatch.ErrForeignSectionNotFound, productbatch.ErrForeignProductNotFound:
+				web.Error(ctx, http.StatusNotFound, err.Error())
+			case inbound_order.ErrInboundOrderAlreadyExists, productbatch.ErrAlreadyExists, productbatch.ErrCapacityExceeded, productbatch.ErrTemperature, productbatch.ErrProductType:
+				web.Error(ctx, http.StatusConflict, err.Error())
+			case inbound_order.ErrInvalidOrderDate, inbound_order.ErrEmptyOrderNumber, inbound_order.ErrInvalidReceivedBatch, inbound_order.ErrSectionNotInWarehouse,
+				productbatch.ErrDateValue:
+				web.Error(ctx, http.StatusUnprocessableEntity, err.Error())
+			default:
+				web.Error(ctx, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+
+		web.Success(ctx, http.StatusCreated, received)
+	}
+}
@@ -7,9 +7,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/inbound_order"
+	productbatch "github.com/extmatperez/meli_bootcamp_go_w6-2/internal/productBatch"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -31,7 +33,12 @@ func createServerInboundOrders(mockRepository inbound_order.MockRepository) *gin
 
 	inboundOrderRoutesGroup := router.Group("/api/v1/inboundOrders")
 
+	inboundOrderRoutesGroup.GET("/", inboundOrderHandler.GetAll())
+	inboundOrderRoutesGroup.GET("/:id", inboundOrderHandler.Get())
 	inboundOrderRoutesGroup.POST("/", inboundOrderHandler.Create())
+	inboundOrderRoutesGroup.POST("/receive", inboundOrderHandler.Receive())
+	inboundOrderRoutesGroup.PATCH("/:id", inboundOrderHandler.Update())
+	inboundOrderRoutesGroup.POST("/:id/cancel", inboundOrderHandler.Cancel())
 
 	return router
 }
@@ -45,7 +52,7 @@ func createRequestTestInboundOrders(method, url, body string) (*http.Request, *h
 
 func TestSaveInboundOrder_Ok(t *testing.T) {
 	var response successfulResponseInboundOrders
-	expectedInboundOrder := domain.InboundOrder{ID: 1, OrderDate: "01/01/2022", OrderNumber: "Test#1", EmployeeID: 1, ProductBatchID: 1, WarehouseID: 1}
+	expectedInboundOrder := domain.InboundOrder{ID: 1, OrderDate: "2022-01-01", OrderNumber: "Test#1", EmployeeID: 1, ProductBatchID: 1, WarehouseID: 1}
 
 	db := []domain.InboundOrder{}
 
@@ -276,3 +283,208 @@ func TestGetEmployeeInboundOrders_InvalidID(t *testing.T) {
 
 	assert.Equal(t, 400, recorder.Code)
 }
+
+func TestSaveInboundOrder_InvalidOrderDate(t *testing.T) {
+	router := createServerInboundOrders(inbound_order.MockRepository{ExpectedID: 1})
+	req, recorder := createRequestTestInboundOrders(http.MethodPost, "/api/v1/inboundOrders/", `{"order_date": "2022/01/01", "order_number": "Test#1", "employee_id": 1, "product_batch_id": 1, "warehouse_id": 1}`)
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, 422, recorder.Code)
+}
+
+func TestGetAllInboundOrders_Ok(t *testing.T) {
+	mockRepository := inbound_order.MockRepository{
+		DataMockInboundOrders: []domain.InboundOrder{{ID: 1, OrderDate: "2022-01-01", OrderNumber: "Test#1", EmployeeID: 1, ProductBatchID: 1, WarehouseID: 1}},
+	}
+
+	router := createServerInboundOrders(mockRepository)
+	req, recorder := createRequestTestInboundOrders(http.MethodGet, "/api/v1/inboundOrders/?warehouse_id=1&employee_id=1&from=2022-01-01", "")
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"order_number":"Test#1"`)
+}
+
+func TestGetAllInboundOrders_BadRequest(t *testing.T) {
+	for _, url := range []string{"/api/v1/inboundOrders/?employee_id=abc", "/api/v1/inboundOrders/?from=01-01-2022", "/api/v1/inboundOrders/?from=2022-02-01&to=2022-01-01"} {
+		router := createServerInboundOrders(inbound_order.MockRepository{})
+		req, recorder := createRequestTestInboundOrders(http.MethodGet, url, "")
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, 400, recorder.Code, url)
+	}
+}
+
+func TestGetInboundOrder(t *testing.T) {
+	mockRepository := inbound_order.MockRepository{
+		DataMockInboundOrders: []domain.InboundOrder{{ID: 1, OrderDate: "2022-01-01", OrderNumber: "Test#1", EmployeeID: 1, ProductBatchID: 1, WarehouseID: 1}},
+	}
+	codes := map[string]int{"/api/v1/inboundOrders/1": 200, "/api/v1/inboundOrders/2": 404, "/api/v1/inboundOrders/abc": 400}
+
+	for url, code := range codes {
+		router := createServerInboundOrders(mockRepository)
+		req, recorder := createRequestTestInboundOrders(http.MethodGet, url, "")
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, code, recorder.Code, url)
+	}
+}
+
+func TestUpdateInboundOrder(t *testing.T) {
+	cancelledAt := time.Date(2022, 1, 5, 0, 0, 0, 0, time.UTC)
+	data := []domain.InboundOrder{
+		{ID: 1, OrderDate: "2022-01-01", OrderNumber: "Test#1", EmployeeID: 1, ProductBatchID: 1, WarehouseID: 1},
+		{ID: 2, OrderDate: "2022-01-01", OrderNumber: "Test#2", EmployeeID: 1, ProductBatchID: 1, WarehouseID: 1, CancelledAt: &cancelledAt},
+	}
+	cases := []struct {
+		name string
+		url  string
+		body string
+		err  error
+		code int
+	}{
+		{"ok", "/api/v1/inboundOrders/1", `{"order_date": "15/01/2022", "warehouse_id": 2}`, nil, 200},
+		{"invalid id", "/api/v1/inboundOrders/abc", `{}`, nil, 400},
+		{"not found", "/api/v1/inboundOrders/3", `{"warehouse_id": 2}`, nil, 404},
+		{"cancelled", "/api/v1/inboundOrders/2", `{"warehouse_id": 2}`, nil, 409},
+		{"invalid date", "/api/v1/inboundOrders/1", `{"order_date": "tomorrow"}`, nil, 422},
+		{"missing warehouse", "/api/v1/inboundOrders/1", `{"warehouse_id": 10}`, inbound_order.ErrWarehouseNonExistent, 404},
+		{"repeated number", "/api/v1/inboundOrders/1", `{"order_number": "Test#2"}`, inbound_order.ErrInboundOrderAlreadyExists, 409},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			mockRepository := inbound_order.MockRepository{DataMockInboundOrders: append([]domain.InboundOrder{}, data...), MockErrorUpdate: c.err}
+			router := createServerInboundOrders(mockRepository)
+			req, recorder := createRequestTestInboundOrders(http.MethodPatch, c.url, c.body)
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.code, recorder.Code)
+		})
+	}
+}
+
+func TestCancelInboundOrder(t *testing.T) {
+	cancelledAt := time.Date(2022, 1, 5, 0, 0, 0, 0, time.UTC)
+	mockRepository := inbound_order.MockRepository{DataMockInboundOrders: []domain.InboundOrder{
+		{ID: 1, OrderDate: "2022-01-01", OrderNumber: "Test#1"},
+		{ID: 2, OrderDate: "2022-01-01", OrderNumber: "Test#2", CancelledAt: &cancelledAt},
+	}}
+	codes := map[string]int{"/api/v1/inboundOrders/1/cancel": 200, "/api/v1/inboundOrders/2/cancel": 409, "/api/v1/inboundOrders/3/cancel": 404}
+
+	for url, code := range codes {
+		router := createServerInboundOrders(mockRepository)
+		req, recorder := createRequestTestInboundOrders(http.MethodPost, url, "")
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, code, recorder.Code, url)
+	}
+}
+
+const receiptBody = `{"order_date": "2022-01-10", "order_number": "Order#1", "employee_id": 1, "warehouse_id": 2,
+	"product_batch": {"batch_number": 7, "quantity": 5, "current_temperature": -5, "minimum_temperature": -10,
+	"due_date": "2022-03-01", "manufacturing_date": "2022-01-01", "manufacturing_hour": 8, "product_id": 3, "section_id": 4}}`
+
+func TestReceiveInboundOrder_Ok(t *testing.T) {
+	mockRepository := inbound_order.MockRepository{ExpectedID: 9}
+
+	router := createServerInboundOrders(mockRepository)
+	req, recorder := createRequestTestInboundOrders(http.MethodPost, "/api/v1/inboundOrders/receive", receiptBody)
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, 201, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"product_batch_id":9`)
+	assert.Contains(t, recorder.Body.String(), `"initial_quantity":5`)
+}
+
+func TestReceiveInboundOrder_Fail(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		err  error
+		code int
+	}{
+		{"missing batch", `{"order_date": "2022-01-10", "order_number": "Order#1", "employee_id": 1, "warehouse_id": 2}`, nil, 422},
+		{"section of another warehouse", receiptBody, inbound_order.ErrSectionNotInWarehouse, 422},
+		{"section not found", receiptBody, inbound_order.ErrSectionNonExistent, 404},
+		{"section full", receiptBody, productbatch.ErrCapacityExceeded, 409},
+		{"repeated batch number", receiptBody, productbatch.ErrAlreadyExists, 409},
+		{"section too warm", receiptBody, productbatch.ErrTemperature, 409},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			mockRepository := inbound_order.MockRepository{ExpectedID: 9, MockErrorReceive: c.err}
+			router := createServerInboundOrders(mockRepository)
+			req, recorder := createRequestTestInboundOrders(http.MethodPost, "/api/v1/inboundOrders/receive", c.body)
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.code, recorder.Code)
+		})
+	}
+}
+
+var dailyInboundTest = []domain.EmployeeDailyInbound{
+	{Employee: domain.Employee{ID: 1, CardNumberID: "123456", FirstName: "John", LastName: "Doe", WarehouseID: 1}, Day: domain.EmployeeDay{Date: "2022-01-10", InboundOrders: 2, ReceivedUnits: 10}},
+	{Employee: domain.Employee{ID: 1, CardNumberID: "123456", FirstName: "John", LastName: "Doe", WarehouseID: 1}, Day: domain.EmployeeDay{Date: "2022-01-11", InboundOrders: 1, ReceivedUnits: 5}},
+	{Employee: domain.Employee{ID: 2, CardNumberID: "654321", FirstName: "Jane", LastName: "Roe", WarehouseID: 1}, Day: domain.EmployeeDay{Date: "2022-01-10", InboundOrders: 1, ReceivedUnits: 40}},
+}
+
+func TestGetEmployeesProductivity_Ok(t *testing.T) {
+	router := createServerEmployeeWithIO(inbound_order.MockRepository{DataMockDailyInbound: dailyInboundTest})
+	req, recorder := createRequestTestEmployeeWithIO(http.MethodGet, "/api/v1/employees/reportInboundOrders?from=2022-01-01&to=2022-01-31&warehouse_id=1", "")
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"inbound_orders_count":3,"received_units":15,"days":[{"date":"2022-01-10"`)
+}
+
+func TestGetEmployeesProductivity_CSV(t *testing.T) {
+	router := createServerEmployeeWithIO(inbound_order.MockRepository{DataMockDailyInbound: dailyInboundTest})
+	req, recorder := createRequestTestEmployeeWithIO(http.MethodGet, "/api/v1/employees/reportInboundOrders?from=2022-01-01&format=csv", "")
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "employee_id,card_number_id,first_name,last_name,warehouse_id,date,inbound_orders_count,received_units\n"+
+		"1,123456,John,Doe,1,2022-01-10,2,10\n"+
+		"1,123456,John,Doe,1,2022-01-11,1,5\n"+
+		"2,654321,Jane,Roe,1,2022-01-10,1,40\n", recorder.Body.String())
+}
+
+func TestGetProductivityRanking_Ok(t *testing.T) {
+	router := createServerEmployeeWithIO(inbound_order.MockRepository{DataMockDailyInbound: dailyInboundTest})
+	req, recorder := createRequestTestEmployeeWithIO(http.MethodGet, "/api/v1/employees/reportInboundOrders?top=1", "")
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"by_inbound_orders":[{"id":1,`)
+	assert.Contains(t, recorder.Body.String(), `"by_received_units":[{"id":2,`)
+}
+
+func TestGetProductivityRanking_CSV(t *testing.T) {
+	router := createServerEmployeeWithIO(inbound_order.MockRepository{DataMockDailyInbound: dailyInboundTest})
+	req, recorder := createRequestTestEmployeeWithIO(http.MethodGet, "/api/v1/employees/reportInboundOrders?top=1&format=csv", "")
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "ranking,position,employee_id,card_number_id,first_name,last_name,warehouse_id,inbound_orders_count,received_units\n"+
+		"inbound_orders,1,1,123456,John,Doe,1,3,15\n"+
+		"received_units,1,2,654321,Jane,Roe,1,1,40\n", recorder.Body.String())
+}
+
+func TestGetEmployeesProductivity_BadRequest(t *testing.T) {
+	urls := []string{
+		"/api/v1/employees/reportInboundOrders?warehouse_id=abc",
+		"/api/v1/employees/reportInboundOrders?from=10/01/2022",
+		"/api/v1/employees/reportInboundOrders?top=abc",
+		"/api/v1/employees/reportInboundOrders?top=0",
+	}
+
+	for _, url := range urls {
+		router := createServerEmployeeWithIO(inbound_order.MockRepository{})
+		req, recorder := createRequestTestEmployeeWithIO(http.MethodGet, url, "")
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, 400, recorder.Code, url)
+	}
+}
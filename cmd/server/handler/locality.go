@@ -1,8 +1,13 @@
 package handler
 
 import (
+	"io"
 	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
 
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/cmd/server/handler/requests"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/locality"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
@@ -115,11 +120,15 @@ func (l *Locality) Get() gin.HandlerFunc {
 // Create locality godoc
 // @Summary Create Locality
 // @Tags    Localities
+// @Description the province is given by province_id, or by province_name together with country_id or country_name.
+// @Description Names are matched without case and missing provinces or countries are created.
+// @Description latitude and longitude are optional, but given together.
 // @Accept  json
 // @Produce json
 // @Param   locality body     domain.Locality   true "Locality to Create"
 // @Success 201      {object} web.response      "New locality"
 // @Failure 400      {object} web.errorResponse "BadRequest"
+// @Failure 404      {object} web.errorResponse "Province or country not found"
 // @Failure 409      {object} web.errorResponse "Conflict"
 // @Failure 422      {object} web.errorResponse "UnprocessableEntity"
 // @Failure 500      {object} web.errorResponse "Internal server error"
@@ -139,11 +148,22 @@ func (l *Locality) Create() gin.HandlerFunc {
 			return
 		}
 
-		localityCreated, err := l.localityService.Create(c, domain.Locality{ID: req.ID, LocalityName: req.LocalityName, ProvinceName: req.ProvinceName, CountryName: req.CountryName})
+		if req.ProvinceID == 0 && (req.ProvinceName == "" || (req.CountryID == 0 && req.CountryName == "")) {
+			web.Error(c, http.StatusBadRequest, "Bad Request, missing required fields")
+			return
+		}
+
+		localityCreated, err := l.localityService.Create(c, domain.Locality{ID: req.ID, LocalityName: req.LocalityName, ProvinceID: req.ProvinceID, ProvinceName: req.ProvinceName, CountryID: req.CountryID, CountryName: req.CountryName, Latitude: req.Latitude, Longitude: req.Longitude})
 		if err != nil {
 			switch err {
 			case locality.ErrAlreadyExists:
 				web.Error(c, http.StatusConflict, err.Error())
+			case locality.ErrBadRequest:
+				web.Error(c, http.StatusBadRequest, err.Error())
+			case locality.ErrProvinceNotFound, locality.ErrCountryNotFound:
+				web.Error(c, http.StatusNotFound, err.Error())
+			case locality.ErrInvalidCoordinates, locality.ErrDataTooLong:
+				web.Error(c, http.StatusUnprocessableEntity, err.Error())
 			default:
 				web.Error(c, http.StatusInternalServerError, err.Error())
 			}
@@ -153,3 +173,128 @@ func (l *Locality) Create() gin.HandlerFunc {
 		web.Success(c, http.StatusCreated, localityCreated)
 	}
 }
+
+// UpdateCoordinates locality godoc
+// @Summary Update locality coordinates
+// @Tags    Localities
+// @Description set the latitude and longitude of a locality, used to find its nearest warehouses and carries
+// @Accept  json
+// @Produce json
+// @Param   id          path     string                              true "locality id"
+// @Param   coordinates body     requests.LocalityCoordinatesRequest true "Coordinates in decimal degrees"
+// @Success 200         {object} web.response      "Updated locality"
+// @Failure 404         {object} web.errorResponse "Not found"
+// @Failure 422         {object} web.errorResponse "UnprocessableEntity"
+// @Failure 500         {object} web.errorResponse "Internal server error"
+// @Router  /api/v1/localities/{id} [PATCH]
+func (l *Locality) UpdateCoordinates() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.LocalityCoordinatesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, locality.ErrInvalidCoordinates.Error())
+			return
+		}
+
+		localityUpdated, err := l.localityService.UpdateCoordinates(c, c.Param("id"), domain.Coordinates{Latitude: *req.Latitude, Longitude: *req.Longitude})
+		if err != nil {
+			switch err {
+			case locality.ErrNotFound:
+				web.Error(c, http.StatusNotFound, err.Error())
+			case locality.ErrInvalidCoordinates:
+				web.Error(c, http.StatusUnprocessableEntity, err.Error())
+			default:
+				web.Error(c, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+
+		web.Success(c, http.StatusOK, localityUpdated)
+	}
+}
+
+// Import localities godoc
+// @Summary Import localities
+// @Tags    Localities
+// @Description create localities from a csv file with a header row, or from ndjson with one locality per line.
+// @Description The file is the body, with a text/csv or application/x-ndjson content type, or the file field of a multipart form.
+// @Description Every row follows the rules of Create, valid rows are stored in batches and the report lists the rejected ones.
+// @Description With dry_run the rows are validated against the database but nothing is stored.
+// @Accept  text/csv,application/x-ndjson,multipart/form-data
+// @Produce json
+// @Param   dry_run query    bool false "validate without storing"
+// @Success 200     {object} web.response      "Import report"
+// @Failure 400     {object} web.errorResponse "BadRequest"
+// @Failure 415     {object} web.errorResponse "Unsupported format"
+// @Failure 500     {object} web.errorResponse "Internal server error"
+// @Router  /api/v1/localities/import [POST]
+func (l *Locality) Import() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dryRun := false
+		if value := c.Query("dry_run"); value != "" {
+			var err error
+			if dryRun, err = strconv.ParseBool(value); err != nil {
+				web.Error(c, http.StatusBadRequest, "invalid dry_run")
+				return
+			}
+		}
+
+		format, body, err := importFile(c)
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		defer body.Close()
+
+		rows, err := locality.NewImportReader(format, body)
+		if err != nil {
+			switch err {
+			case locality.ErrUnsupportedFormat:
+				web.Error(c, http.StatusUnsupportedMediaType, err.Error())
+			default:
+				web.Error(c, http.StatusBadRequest, err.Error())
+			}
+			return
+		}
+
+		report, err := l.localityService.Import(c, rows, dryRun)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if err == locality.ErrUnreadableFile {
+				status = http.StatusBadRequest
+			}
+			web.Error(c, status, "%s, %d localities were imported before the import stopped", err.Error(), report.Imported)
+			return
+		}
+		web.Success(c, http.StatusOK, report)
+	}
+}
+
+// importFile returns the uploaded file with its format, taken from the content type of the body
+// or from the extension of the file field of a multipart form
+func importFile(c *gin.Context) (string, io.ReadCloser, error) {
+	if c.ContentType() == "multipart/form-data" {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return "", nil, err
+		}
+		file, err := header.Open()
+		if err != nil {
+			return "", nil, err
+		}
+		switch strings.ToLower(filepath.Ext(header.Filename)) {
+		case ".csv":
+			return locality.ImportCSV, file, nil
+		case ".ndjson", ".jsonl":
+			return locality.ImportNDJSON, file, nil
+		}
+		return "", file, nil
+	}
+
+	switch c.ContentType() {
+	case "text/csv":
+		return locality.ImportCSV, c.Request.Body, nil
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return locality.ImportNDJSON, c.Request.Body, nil
+	}
+	return "", c.Request.Body, nil
+}
@@ -27,6 +27,9 @@ type MockServiceLocality struct {
 	ErrorGet    error
 	ErrorCreate error
 	ErrorReport error
+	LastCoords  domain.Coordinates
+	ErrorImport error
+	LastDryRun  bool
 }
 
 // *---------------------- Mock service functions -----------------*
@@ -64,6 +67,37 @@ func (s *MockServiceLocality) ReportCarries(ctx context.Context, locality_id *st
 	return s.ReportCarry, nil
 }
 
+func (l *MockServiceLocality) UpdateCoordinates(ctx context.Context, id string, c domain.Coordinates) (loc domain.Locality, err error) {
+	if l.ErrorGet != nil {
+		err = l.ErrorGet
+		return
+	}
+	l.LastCoords = c
+	loc = l.Locality
+	loc.Latitude = &c.Latitude
+	loc.Longitude = &c.Longitude
+	return
+}
+
+// Import counts the rows it can read, like the service does before storing them
+func (l *MockServiceLocality) Import(ctx context.Context, rows locality.ImportReader, dryRun bool) (report domain.LocalityImportReport, err error) {
+	l.LastDryRun = dryRun
+	report = domain.LocalityImportReport{DryRun: dryRun, Errors: []domain.LocalityImportError{}}
+	if l.ErrorImport != nil {
+		err = l.ErrorImport
+		return
+	}
+	for {
+		_, rowErr := rows.Next()
+		if rowErr == io.EOF {
+			break
+		}
+		report.Rows++
+	}
+	report.Valid = report.Rows
+	return
+}
+
 // *---------------------- Others functions -----------------*
 func createServerLocality() (ctx *gin.Context, recorder *httptest.ResponseRecorder) {
 	logging.InitLog(nil)
@@ -502,6 +536,149 @@ func TestCreateInternalError_Locality(t *testing.T) {
 
 }
 
+// TestCreateProvinceNotFound_Locality passes when the province id does not exist (status code 404)
+func TestCreateProvinceNotFound_Locality(t *testing.T) {
+	// Arrange
+	expectedError := locality.ErrProvinceNotFound
+
+	localityRequestBody := domain.Locality{
+		ID:           "5700",
+		LocalityName: "San Luis",
+		ProvinceID:   99,
+	}
+
+	ctx, rr := createServerLocality()
+
+	service := MockServiceLocality{
+		ErrorCreate: expectedError,
+	}
+	handler := NewLocality(&service)
+
+	body, _ := json.Marshal(&localityRequestBody)
+	request := &http.Request{
+		Body: io.NopCloser(bytes.NewBuffer(body)),
+	}
+	ctx.Request = request
+
+	// Act
+	handler.Create()(ctx)
+
+	/* Parse response body */
+	var responseBody responseErrorLocality
+	err := json.Unmarshal(rr.Body.Bytes(), &responseBody)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.EqualError(t, expectedError, responseBody.Message)
+}
+
+// *--------------------------- UpdateCoordinates ----------------------*
+// TestUpdateCoordinates_Locality_OK passes when the locality is returned with its coordinates (status code 200)
+func TestUpdateCoordinates_Locality_OK(t *testing.T) {
+	// Arrange
+	ctx, rr := createServerLocality()
+	ctx.AddParam("id", "5700")
+	ctx.Request = &http.Request{
+		Body: io.NopCloser(bytes.NewBufferString(`{"latitude": -33.301726, "longitude": -66.337752}`)),
+	}
+
+	service := MockServiceLocality{
+		Locality: domain.Locality{ID: "5700", LocalityName: "Capital"},
+	}
+	handler := NewLocality(&service)
+
+	// Act
+	handler.UpdateCoordinates()(ctx)
+
+	var body responseLocality
+	err := json.Unmarshal(rr.Body.Bytes(), &body)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, domain.Coordinates{Latitude: -33.301726, Longitude: -66.337752}, service.LastCoords)
+	assert.Equal(t, -66.337752, *body.Data.Longitude)
+}
+
+// TestUpdateCoordinates_Locality_Fail passes when a coordinate is missing (422) or the locality does not exist (404)
+func TestUpdateCoordinates_Locality_Fail(t *testing.T) {
+	// Arrange
+	ctxMissing, rrMissing := createServerLocality()
+	ctxMissing.AddParam("id", "5700")
+	ctxMissing.Request = &http.Request{
+		Body: io.NopCloser(bytes.NewBufferString(`{"latitude": -33.301726}`)),
+	}
+	ctxNotFound, rrNotFound := createServerLocality()
+	ctxNotFound.AddParam("id", "9999")
+	ctxNotFound.Request = &http.Request{
+		Body: io.NopCloser(bytes.NewBufferString(`{"latitude": 0, "longitude": 0}`)),
+	}
+
+	handler := NewLocality(&MockServiceLocality{ErrorGet: locality.ErrNotFound})
+
+	// Act
+	handler.UpdateCoordinates()(ctxMissing)
+	handler.UpdateCoordinates()(ctxNotFound)
+
+	// Assert
+	assert.Equal(t, http.StatusUnprocessableEntity, rrMissing.Code)
+	assert.Equal(t, http.StatusNotFound, rrNotFound.Code)
+}
+
+// *--------------------------- Import ----------------------*
+// TestImport_Locality_CSV passes when a csv body is read as a dry run (status code 200)
+func TestImport_Locality_CSV(t *testing.T) {
+	// Arrange
+	service := MockServiceLocality{}
+	r := gin.New()
+	r.POST("/api/v1/localities/import", NewLocality(&service).Import())
+
+	req, recorder := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/localities/import?dry_run=true", "id,locality_name,province_id\n5700,Capital,3\n1900,La Plata,3\n")
+	req.Header.Set("Content-Type", "text/csv")
+
+	// Act
+	r.ServeHTTP(recorder, req)
+
+	var body struct {
+		Data domain.LocalityImportReport `json:"data"`
+	}
+	err := json.Unmarshal(recorder.Body.Bytes(), &body)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.True(t, service.LastDryRun)
+	assert.Equal(t, 2, body.Data.Rows)
+}
+
+// TestImport_Locality_Fail passes when the format is unknown (415), the header is wrong (400) or the import stops (500)
+func TestImport_Locality_Fail(t *testing.T) {
+	// Arrange
+	r := gin.New()
+	r.POST("/api/v1/localities/import", NewLocality(&MockServiceLocality{ErrorImport: locality.ErrInternal}).Import())
+
+	reqFormat, recorderFormat := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/localities/import", "[]")
+	reqHeader, recorderHeader := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/localities/import", "name\nCapital\n")
+	reqHeader.Header.Set("Content-Type", "text/csv")
+	reqDryRun, recorderDryRun := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/localities/import?dry_run=maybe", "")
+	reqStopped, recorderStopped := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/localities/import", "{}\n")
+	reqStopped.Header.Set("Content-Type", "application/x-ndjson")
+
+	// Act
+	r.ServeHTTP(recorderFormat, reqFormat)
+	r.ServeHTTP(recorderHeader, reqHeader)
+	r.ServeHTTP(recorderDryRun, reqDryRun)
+	r.ServeHTTP(recorderStopped, reqStopped)
+
+	// Assert
+	assert.Equal(t, http.StatusUnsupportedMediaType, recorderFormat.Code)
+	assert.Equal(t, http.StatusBadRequest, recorderHeader.Code)
+	assert.Equal(t, http.StatusBadRequest, recorderDryRun.Code)
+	assert.Equal(t, http.StatusInternalServerError, recorderStopped.Code)
+	assert.Contains(t, recorderStopped.Body.String(), "0 localities were imported")
+}
+
 // *--------------------------- Report ----------------------*
 // TestGetReportSellers_OK passes when handler method recive a non empty id (status code 200)
 func TestGetReportSellers_OK(t *testing.T) {
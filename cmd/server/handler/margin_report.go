@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/record/margin_report"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/web"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+var (
+	MarginReportErrInvalidDate = errors.New("from and to must be dates like 2006-01-02")
+)
+
+type MarginReport struct {
+	marginReportService margin_report.Service
+}
+
+func NewMarginReport(service margin_report.Service) *MarginReport {
+	return &MarginReport{
+		marginReportService: service,
+	}
+}
+
+// GetReportMargins
+// @Summary     GET the margins of the Products
+// @Description "Retrieves the margin of every Product with a price at to (today by default), its change since from and the Products sold below their purchase price"
+// @Description "With group_by, also summarizes the margins per seller or product type"
+// @Tags        ReportRecords
+// @Produce     json
+// @Param       from     query    string            false "Start of the period, 2006-01-02"
+// @Param       to       query    string            false "End of the period, 2006-01-02"
+// @Param       group_by query    string            false "seller or product_type"
+// @Success     200      {object} web.response      "Margin report"
+// @Failure     400      {object} web.errorResponse "Invalid dates or grouping"
+// @Failure     500      {object} web.errorResponse "Unknown or unhandled error"
+// @Router      /api/v1/products/reportMargins [get]
+func (mr *MarginReport) GetReportMargins() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		from, errFrom := queryDate(ctx, "from")
+		to, errTo := queryDate(ctx, "to")
+		if errFrom != nil || errTo != nil {
+			web.Error(ctx, http.StatusBadRequest, MarginReportErrInvalidDate.Error())
+			return
+		}
+		report, errGet := mr.marginReportService.Get(ctx, from, to, ctx.Query("group_by"))
+		if errGet != nil {
+			logging.Log(errGet)
+			switch errGet {
+			case margin_report.ServiceErrDateRange, margin_report.ServiceErrGroupBy:
+				web.Error(ctx, http.StatusBadRequest, errGet.Error())
+			default:
+				// errorMessage = "" for security reasons (we don't want to expose internal data to the outside)
+				web.Error(ctx, http.StatusInternalServerError, "")
+			}
+			return
+		}
+		web.Success(ctx, http.StatusOK, report)
+	}
+}
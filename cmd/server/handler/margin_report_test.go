@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/record/margin_report"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func setupMarginReportHandlersEngineMock(query string) (ctx *gin.Context, responseRecorder *httptest.ResponseRecorder) {
+	logging.InitLog(nil)
+	gin.SetMode(gin.TestMode)
+	responseRecorder = httptest.NewRecorder()
+	ctx, _ = gin.CreateTestContext(responseRecorder)
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/api/v1/products/reportMargins"+query, nil)
+	return
+}
+
+// TestMarginReport_GetReportMargins_OK passes when the report is built (return 200 and domain.MarginReport)
+func TestMarginReport_GetReportMargins_OK(t *testing.T) {
+	// Arrange
+	expectedReport := domain.MarginReport{
+		From:      "2026-01-01",
+		To:        "2026-06-30",
+		GroupBy:   domain.MarginGroupBySeller,
+		Groups:    []domain.MarginGroup{{ProductsCount: 1, AverageMargin: 2}},
+		Products:  []domain.ProductMargin{{ProductID: 1, PurchasePrice: 1, SalePrice: 3, Margin: 2}},
+		BelowCost: []domain.ProductMargin{},
+	}
+
+	// Act
+	ctx, responseRecorder := setupMarginReportHandlersEngineMock("?from=2026-01-01&to=2026-06-30&group_by=seller")
+	marginReportService := margin_report.ServiceMock{Report: expectedReport}
+	NewMarginReport(&marginReportService).GetReportMargins()(ctx)
+	var response struct {
+		Data domain.MarginReport `json:"data"`
+	}
+	errUnmarshal := json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+
+	// Assert
+	assert.Nil(t, errUnmarshal)
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	assert.Equal(t, expectedReport, response.Data)
+	assert.Equal(t, "2026-01-01", marginReportService.LastFrom.Format(domain.ISO8601))
+	assert.Equal(t, "2026-06-30", marginReportService.LastTo.Format(domain.ISO8601))
+	assert.Equal(t, domain.MarginGroupBySeller, marginReportService.LastGroupBy)
+}
+
+// TestMarginReport_GetReportMargins_Fail passes when the dates or grouping are invalid (400) or the service fails (500)
+func TestMarginReport_GetReportMargins_Fail(t *testing.T) {
+	tests := []struct {
+		query        string
+		err          error
+		expectedCode int
+		called       bool
+	}{
+		{query: "?from=yesterday", expectedCode: http.StatusBadRequest},
+		{query: "?to=2026-13-01", expectedCode: http.StatusBadRequest},
+		{query: "?from=2026-06-30&to=2026-01-01", err: margin_report.ServiceErrDateRange, expectedCode: http.StatusBadRequest, called: true},
+		{query: "?group_by=warehouse", err: margin_report.ServiceErrGroupBy, expectedCode: http.StatusBadRequest, called: true},
+		{err: margin_report.ServiceErrInternal, expectedCode: http.StatusInternalServerError, called: true},
+	}
+	for _, test := range tests {
+		ctx, responseRecorder := setupMarginReportHandlersEngineMock(test.query)
+		marginReportService := margin_report.ServiceMock{ForcedErrGet: test.err}
+		NewMarginReport(&marginReportService).GetReportMargins()(ctx)
+
+		assert.Equal(t, test.expectedCode, responseRecorder.Code, test.query)
+		assert.Equal(t, test.called, marginReportService.FlagGet, test.query)
+	}
+}
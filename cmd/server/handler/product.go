@@ -94,16 +94,16 @@ func (p *Product) Get() gin.HandlerFunc {
 
 // Create
 // @Summary     POST Product
-// @Description Creates a new Product on the database
+// @Description Creates a new Product on the database, recommended_freezing_temperature defaults to the maximum temperature of the ProductType
 // @Tags        Products
 // @Accept      json
 // @Produce     json
 // @Param       product body     requests.ProductPOSTRequest true "Product to be created"
 // @Success     201     {object} web.response                "Product created"
 // @Failure     400     {object} web.errorResponse           "Missing field"
-// @Failure     404     {object} web.errorResponse           "Product created but not found in database or Seller not found"
+// @Failure     404     {object} web.errorResponse           "Product created but not found in database, Seller or ProductType not found"
 // @Failure     409     {object} web.errorResponse           "Product code already exists"
-// @Failure     422     {object} web.errorResponse           "Type casting error"
+// @Failure     422     {object} web.errorResponse           "Type casting error, or no recommended_freezing_temperature for a ProductType without temperature range"
 // @Failure     500     {object} web.errorResponse           "Unknown or unhandled error"
 // @Router      /api/v1/products [post]
 func (p *Product) Create() gin.HandlerFunc {
@@ -129,8 +129,10 @@ func (p *Product) Create() gin.HandlerFunc {
 			switch errSave {
 			case product.ServiceErrAlreadyExists:
 				web.Error(ctx, http.StatusConflict, errSave.Error())
-			case product.ServiceErrForeignKeyNotFound:
+			case product.ServiceErrForeignKeyNotFound, product.ServiceErrProductTypeNotFound:
 				web.Error(ctx, http.StatusNotFound, errSave.Error())
+			case product.ServiceErrNoTemperatureRange:
+				web.Error(ctx, http.StatusUnprocessableEntity, errSave.Error())
 			case product.ServiceErrNotFound:
 				web.Error(ctx, http.StatusNotFound, ProductErrCreatedButNotFound.Error())
 			default:
@@ -153,7 +155,7 @@ func (p *Product) Create() gin.HandlerFunc {
 // @Param       product body     requests.ProductPATCHRequest true "Product to be updated"
 // @Success     200     {object} web.response                 "Product updated"
 // @Failure     400     {object} web.errorResponse            "Invalid field or ID"
-// @Failure     404     {object} web.errorResponse            "Product not found or Seller not found"
+// @Failure     404     {object} web.errorResponse            "Product, Seller or ProductType not found"
 // @Failure     409     {object} web.errorResponse            "Product code already exists"
 // @Failure     422     {object} web.errorResponse            "Type casting error"
 // @Failure     500     {object} web.errorResponse            "Unknown or unhandled error"
@@ -186,7 +188,7 @@ func (p *Product) PartialUpdate() gin.HandlerFunc {
 			switch errPartialUpdate {
 			case product.ServiceErrAlreadyExists:
 				web.Error(ctx, http.StatusConflict, errPartialUpdate.Error())
-			case product.ServiceErrForeignKeyNotFound:
+			case product.ServiceErrForeignKeyNotFound, product.ServiceErrProductTypeNotFound:
 				web.Error(ctx, http.StatusNotFound, errPartialUpdate.Error())
 			case product.ServiceErrNotFound:
 				web.Error(ctx, http.StatusNotFound, ProductErrNotFound.Error())
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/cmd/server/handler/requests"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
@@ -21,9 +22,105 @@ func NewProductBatch(s productbatch.Service) *ProductBatch {
 	}
 }
 
+// GetAll ListProductBatches godoc
+// @Summary     List product batches
+// @Tags        ProductBatches
+// @Description get product batches, ordered by due date
+// @Produce     json
+// @Param       product_id query    int    false "product id"
+// @Param       section_id query    int    false "section id"
+// @Param       due_from   query    string false "minimum due date (yyyy-mm-dd)"
+// @Param       due_to     query    string false "maximum due date (yyyy-mm-dd)"
+// @Param       expired    query    bool   false "only expired batches"
+// @Success     200        {object} web.response
+// @Failure     400        {object} web.errorResponse
+// @Failure     500        {object} web.errorResponse
+// @Router      /productBatches [get]
+func (pb *ProductBatch) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var filter domain.ProductBatchFilter
+		var err error
+		if productID := c.Query("product_id"); productID != "" {
+			if filter.ProductID, err = strconv.Atoi(productID); err != nil {
+				logging.Log(err)
+				web.Error(c, http.StatusBadRequest, "invalid product_id %s", productID)
+				return
+			}
+		}
+		if sectionID := c.Query("section_id"); sectionID != "" {
+			if filter.SectionID, err = strconv.Atoi(sectionID); err != nil {
+				logging.Log(err)
+				web.Error(c, http.StatusBadRequest, "invalid section_id %s", sectionID)
+				return
+			}
+		}
+		if expired := c.Query("expired"); expired != "" {
+			if filter.ExpiredOnly, err = strconv.ParseBool(expired); err != nil {
+				logging.Log(err)
+				web.Error(c, http.StatusBadRequest, "invalid expired %s", expired)
+				return
+			}
+		}
+		filter.DueFrom = c.Query("due_from")
+		filter.DueTo = c.Query("due_to")
+
+		data, err := pb.productBatchService.GetAll(c, filter)
+		if err != nil {
+			switch err {
+			case productbatch.ErrDateValue:
+				web.Error(c, http.StatusBadRequest, err.Error())
+			default:
+				web.Error(c, http.StatusInternalServerError, err.Error())
+			}
+			logging.Log(err)
+			return
+		}
+		//if data is empty replace null with empty slice
+		if data == nil {
+			web.Success(c, http.StatusOK, []domain.ProductBatch{})
+			return
+		}
+		web.Success(c, http.StatusOK, data)
+	}
+}
+
+// Get GetProductBatchByID godoc
+// @Summary     Get product batch by ID
+// @Tags        ProductBatches
+// @Description get product batch by ID
+// @Produce     json
+// @Param       id  path     int true "product batch id"
+// @Success     200 {object} web.response
+// @Failure     400 {object} web.errorResponse
+// @Failure     404 {object} web.errorResponse
+// @Failure     500 {object} web.errorResponse
+// @Router      /productBatches/{id} [get]
+func (pb *ProductBatch) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			logging.Log(err)
+			web.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		data, err := pb.productBatchService.Get(c, id)
+		if err != nil {
+			switch err {
+			case productbatch.ErrNotFound:
+				web.Error(c, http.StatusNotFound, "The product batch with id %d does not exists", id)
+			default:
+				web.Error(c, http.StatusInternalServerError, err.Error())
+			}
+			logging.Log(err)
+			return
+		}
+		web.Success(c, http.StatusOK, data)
+	}
+}
+
 // Create CreateProductBatch godoc
 // @Summary     Create product batch
-// @Tags        Sections
+// @Tags        ProductBatches
 // @Description create product batch
 // @Produce     json
 // @Param       productBatch body     requests.PostProductBatch true "Product batch to store"
@@ -47,6 +144,12 @@ func (pb *ProductBatch) Create() gin.HandlerFunc {
 				web.Error(c, http.StatusConflict, "a product batch with the batch_number %d already exists", req.BatchNumber)
 			case productbatch.ErrForeignProductNotFound, productbatch.ErrForeignSectionNotFound:
 				web.Error(c, http.StatusConflict, err.Error())
+			case productbatch.ErrCapacityExceeded:
+				web.Error(c, http.StatusConflict, "the section %d does not have enough capacity to store %d more products", req.SectionID, req.CurrentQuantity)
+			case productbatch.ErrTemperature:
+				web.Error(c, http.StatusConflict, "the temperature of the section %d is not compatible with the product %d", req.SectionID, req.ProductID)
+			case productbatch.ErrProductType:
+				web.Error(c, http.StatusConflict, "the product type of the section %d is not the product type of the product %d", req.SectionID, req.ProductID)
 			case productbatch.ErrDateValue:
 				web.Error(c, http.StatusBadRequest, err.Error())
 			default:
@@ -58,3 +161,135 @@ func (pb *ProductBatch) Create() gin.HandlerFunc {
 		web.Success(c, http.StatusCreated, prodBatch)
 	}
 }
+
+// Update UpdateProductBatch godoc
+// @Summary     Update product batch
+// @Tags        ProductBatches
+// @Description update product batch
+// @Produce     json
+// @Param       id           path     int                        true "product batch id"
+// @Param       productBatch body     requests.PatchProductBatch true "Updated product batch"
+// @Success     200          {object} web.response
+// @Failure     400          {object} web.errorResponse
+// @Failure     404          {object} web.errorResponse
+// @Failure     409          {object} web.errorResponse
+// @Failure     422          {object} web.errorResponse
+// @Failure     500          {object} web.errorResponse
+// @Router      /productBatches/{id} [patch]
+func (pb *ProductBatch) Update() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			logging.Log(err)
+			web.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		var req requests.PatchProductBatch
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logging.Log(err)
+			web.Error(c, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		currentQuantity, currentTemperature, minimumTemperature := -1, -273, -273
+		if req.CurrentQuantity != nil {
+			currentQuantity = *req.CurrentQuantity
+		}
+		if req.CurrentTemperature != nil {
+			currentTemperature = *req.CurrentTemperature
+		}
+		if req.MinimumTemperature != nil {
+			minimumTemperature = *req.MinimumTemperature
+		}
+		data, err := pb.productBatchService.Update(c, domain.ProductBatch{ID: id, BatchNumber: req.BatchNumber, CurrentQuantity: currentQuantity, CurrentTemperature: currentTemperature, DueDate: req.DueDate, InitialQuantity: req.InitialQuantity, ManufacturingDate: req.ManufacturingDate, ManufacturingHour: req.ManufacturingHour, MinimumTemperature: minimumTemperature, ProductID: req.ProductID, SectionID: req.SectionID})
+		if err != nil {
+			switch err {
+			case productbatch.ErrNotFound:
+				web.Error(c, http.StatusNotFound, "The product batch with id %d does not exists", id)
+			case productbatch.ErrAlreadyExists:
+				web.Error(c, http.StatusConflict, "a product batch with the batch_number %d already exists", req.BatchNumber)
+			case productbatch.ErrForeignProductNotFound, productbatch.ErrForeignSectionNotFound, productbatch.ErrQuantityExceeds, productbatch.ErrBelowReserved, productbatch.ErrCapacityExceeded, productbatch.ErrTemperature, productbatch.ErrProductType:
+				web.Error(c, http.StatusConflict, err.Error())
+			case productbatch.ErrDateValue:
+				web.Error(c, http.StatusBadRequest, err.Error())
+			default:
+				web.Error(c, http.StatusInternalServerError, err.Error())
+			}
+			logging.Log(err)
+			return
+		}
+		web.Success(c, http.StatusOK, data)
+	}
+}
+
+// Delete DeleteProductBatch godoc
+// @Summary     Delete product batch
+// @Tags        ProductBatches
+// @Description delete product batch
+// @Produce     json
+// @Param       id path int true "product batch id"
+// @Success     204
+// @Failure     400 {object} web.errorResponse
+// @Failure     404 {object} web.errorResponse
+// @Failure     409 {object} web.errorResponse
+// @Failure     500 {object} web.errorResponse
+// @Router      /productBatches/{id} [delete]
+func (pb *ProductBatch) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			logging.Log(err)
+			web.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		err = pb.productBatchService.Delete(c, id)
+		if err != nil {
+			switch err {
+			case productbatch.ErrNotFound:
+				web.Error(c, http.StatusNotFound, "The product batch with id %d does not exists", id)
+			case productbatch.ErrReferenced:
+				web.Error(c, http.StatusConflict, err.Error())
+			default:
+				web.Error(c, http.StatusInternalServerError, err.Error())
+			}
+			logging.Log(err)
+			return
+		}
+		web.Success(c, http.StatusNoContent, "")
+	}
+}
+
+// Pick PickProductBatches godoc
+// @Summary     Pick product stock
+// @Tags        ProductBatches
+// @Description take stock of a product from its batches in first-expired-first-out order
+// @Produce     json
+// @Param       pick body     requests.PickProductBatch true "Product and quantity to pick"
+// @Success     200  {object} web.response
+// @Failure     409  {object} web.errorResponse
+// @Failure     422  {object} web.errorResponse
+// @Failure     500  {object} web.errorResponse
+// @Router      /productBatches/pick [post]
+func (pb *ProductBatch) Pick() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.PickProductBatch
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logging.Log(err)
+			web.Error(c, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		pick, err := pb.productBatchService.Pick(c, req.ProductID, req.Quantity)
+		if err != nil {
+			switch err {
+			case productbatch.ErrInvalidQuantity:
+				web.Error(c, http.StatusUnprocessableEntity, err.Error())
+			case productbatch.ErrInsufficientStock:
+				web.Error(c, http.StatusConflict, "there is not enough stock of the product %d to pick %d units", req.ProductID, req.Quantity)
+			default:
+				web.Error(c, http.StatusInternalServerError, err.Error())
+			}
+			logging.Log(err)
+			return
+		}
+		web.Success(c, http.StatusOK, pick)
+	}
+}
@@ -14,6 +14,12 @@ import (
 type responseProductBatch struct {
 	Data domain.ProductBatch
 }
+type responseProductBatches struct {
+	Data []domain.ProductBatch
+}
+type responseProductBatchPick struct {
+	Data domain.ProductBatchPick
+}
 
 var productBatchService productbatch.MockService = productbatch.MockService{
 	MockProductBatches: []domain.ProductBatch{},
@@ -33,7 +39,12 @@ func createProductBatchServer() *gin.Engine {
 
 	sec := r.Group("/productBatches")
 
+	sec.GET("", p.GetAll())
+	sec.GET("/:id", p.Get())
 	sec.POST("", p.Create())
+	sec.PATCH("/:id", p.Update())
+	sec.DELETE("/:id", p.Delete())
+	sec.POST("/pick", p.Pick())
 
 	return r
 }
@@ -121,3 +132,154 @@ func TestProductBatchCreateMissingForeignSection(t *testing.T) {
 
 	assert.Equal(t, 409, rw.Code)
 }
+
+func TestProductBatchPickOk(t *testing.T) {
+	allocations := []domain.BatchAllocation{
+		{ProductBatchID: 1, BatchNumber: 1, DueDate: "2030-01-01", Quantity: 5},
+		{ProductBatchID: 2, BatchNumber: 2, DueDate: "2030-02-01", Quantity: 3},
+	}
+	productBatchService = productbatch.MockService{MockAllocations: allocations}
+	body := `{"product_id": 1, "quantity": 8}`
+	req, rw := createRequestTest(http.MethodPost, "/productBatches/pick", body)
+	pbS.ServeHTTP(rw, req)
+
+	expected := domain.ProductBatchPick{ProductID: 1, Quantity: 8, Allocations: allocations}
+
+	var objRes responseProductBatchPick
+	assert.Equal(t, 200, rw.Code)
+	err := json.Unmarshal(rw.Body.Bytes(), &objRes)
+
+	assert.Nil(t, err)
+	assert.Equal(t, expected, objRes.Data)
+}
+
+func TestProductBatchPickFail(t *testing.T) {
+	req, rw := createRequestTest(http.MethodPost, "/productBatches/pick", `{"product_id": 1}`)
+	pbS.ServeHTTP(rw, req)
+
+	assert.Equal(t, 422, rw.Code)
+}
+
+func TestProductBatchPickInvalidQuantity(t *testing.T) {
+	productBatchService.MockError = productbatch.ErrInvalidQuantity
+	req, rw := createRequestTest(http.MethodPost, "/productBatches/pick", `{"product_id": 1, "quantity": -2}`)
+	pbS.ServeHTTP(rw, req)
+
+	assert.Equal(t, 422, rw.Code)
+}
+
+func TestProductBatchPickInsufficientStock(t *testing.T) {
+	productBatchService.MockError = productbatch.ErrInsufficientStock
+	req, rw := createRequestTest(http.MethodPost, "/productBatches/pick", `{"product_id": 1, "quantity": 100}`)
+	pbS.ServeHTTP(rw, req)
+
+	assert.Equal(t, 409, rw.Code)
+}
+
+func TestProductBatchGetAllOk(t *testing.T) {
+	batches := []domain.ProductBatch{{ID: 1, BatchNumber: 1, DueDate: "1999-12-12", ProductID: 1, SectionID: 1}}
+	productBatchService = productbatch.MockService{MockProductBatches: batches}
+	req, rw := createRequestTest(http.MethodGet, "/productBatches?product_id=1&section_id=1&expired=true&due_from=1999-01-01", "")
+	pbS.ServeHTTP(rw, req)
+
+	var objRes responseProductBatches
+	assert.Equal(t, 200, rw.Code)
+	err := json.Unmarshal(rw.Body.Bytes(), &objRes)
+
+	assert.Nil(t, err)
+	assert.Equal(t, batches, objRes.Data)
+}
+
+func TestProductBatchGetAllBadFilter(t *testing.T) {
+	req, rw := createRequestTest(http.MethodGet, "/productBatches?section_id=one", "")
+	pbS.ServeHTTP(rw, req)
+
+	assert.Equal(t, 400, rw.Code)
+}
+
+func TestProductBatchGetAllInvalidDate(t *testing.T) {
+	productBatchService.MockError = productbatch.ErrDateValue
+	req, rw := createRequestTest(http.MethodGet, "/productBatches?due_to=yesterday", "")
+	pbS.ServeHTTP(rw, req)
+
+	assert.Equal(t, 400, rw.Code)
+}
+
+func TestProductBatchGetOk(t *testing.T) {
+	batches := []domain.ProductBatch{{ID: 1, BatchNumber: 1, DueDate: "1999-12-12", ProductID: 1, SectionID: 1}}
+	productBatchService = productbatch.MockService{MockProductBatches: batches}
+	req, rw := createRequestTest(http.MethodGet, "/productBatches/1", "")
+	pbS.ServeHTTP(rw, req)
+
+	var objRes responseProductBatch
+	assert.Equal(t, 200, rw.Code)
+	err := json.Unmarshal(rw.Body.Bytes(), &objRes)
+
+	assert.Nil(t, err)
+	assert.Equal(t, batches[0], objRes.Data)
+}
+
+func TestProductBatchGetNonExistent(t *testing.T) {
+	productBatchService.MockError = productbatch.ErrNotFound
+	req, rw := createRequestTest(http.MethodGet, "/productBatches/7", "")
+	pbS.ServeHTTP(rw, req)
+
+	assert.Equal(t, 404, rw.Code)
+}
+
+func TestProductBatchUpdateOk(t *testing.T) {
+	productBatchService = productbatch.MockService{MockProductBatches: []domain.ProductBatch{{ID: 1, BatchNumber: 1, CurrentQuantity: 10, InitialQuantity: 10, ProductID: 1, SectionID: 1}}}
+	req, rw := createRequestTest(http.MethodPatch, "/productBatches/1", `{"current_quantity": 0, "section_id": 3}`)
+	pbS.ServeHTTP(rw, req)
+
+	expected := domain.ProductBatch{ID: 1, BatchNumber: 1, CurrentQuantity: 0, InitialQuantity: 10, ProductID: 1, SectionID: 3}
+
+	var objRes responseProductBatch
+	assert.Equal(t, 200, rw.Code)
+	err := json.Unmarshal(rw.Body.Bytes(), &objRes)
+
+	assert.Nil(t, err)
+	assert.Equal(t, expected, objRes.Data)
+}
+
+func TestProductBatchUpdateConflict(t *testing.T) {
+	productBatchService.MockError = productbatch.ErrAlreadyExists
+	req, rw := createRequestTest(http.MethodPatch, "/productBatches/1", `{"batch_number": 2}`)
+	pbS.ServeHTTP(rw, req)
+
+	assert.Equal(t, 409, rw.Code)
+}
+
+func TestProductBatchDeleteOk(t *testing.T) {
+	productBatchService = productbatch.MockService{MockProductBatches: []domain.ProductBatch{{ID: 1}}}
+	req, rw := createRequestTest(http.MethodDelete, "/productBatches/1", "")
+	pbS.ServeHTTP(rw, req)
+
+	assert.Equal(t, 204, rw.Code)
+}
+
+func TestProductBatchDeleteReferenced(t *testing.T) {
+	productBatchService.MockError = productbatch.ErrReferenced
+	req, rw := createRequestTest(http.MethodDelete, "/productBatches/1", "")
+	pbS.ServeHTTP(rw, req)
+
+	assert.Equal(t, 409, rw.Code)
+}
+
+func TestProductBatchCreateCapacityExceeded(t *testing.T) {
+	productBatchService.MockError = productbatch.ErrCapacityExceeded
+	body := `{"batch_number":4,"current_quantity": 1,"current_temperature": 1,"due_date": "1999-12-12","initial_quantity": 1,"manufacturing_date": "1999-12-12","manufacturing_hour": 1,"minimum_temperature": 1,"product_id": 1,"section_id": 1}`
+	req, rw := createRequestTest(http.MethodPost, "/productBatches", body)
+	pbS.ServeHTTP(rw, req)
+
+	assert.Equal(t, 409, rw.Code)
+}
+
+func TestProductBatchCreateTemperatureIncompatible(t *testing.T) {
+	productBatchService.MockError = productbatch.ErrTemperature
+	body := `{"batch_number":4,"current_quantity": 1,"current_temperature": 1,"due_date": "1999-12-12","initial_quantity": 1,"manufacturing_date": "1999-12-12","manufacturing_hour": 1,"minimum_temperature": 1,"product_id": 1,"section_id": 1}`
+	req, rw := createRequestTest(http.MethodPost, "/productBatches", body)
+	pbS.ServeHTTP(rw, req)
+
+	assert.Equal(t, 409, rw.Code)
+}
@@ -4,18 +4,23 @@ import (
 	"encoding/json"
 	"errors"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/cmd/server/handler/requests"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/record/product_record"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/web"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 var (
 	ProductRecordErrCreatedButNotFound = errors.New("product created with no errors but not found in database")
 	ProductRecordErrDate               = errors.New("input date cannot be less than today")
 	ProductRecordErrInvalidDate        = errors.New("invalid input date")
+	ProductRecordErrInvalidQueryDate   = errors.New("from, to and at must be dates like 2006-01-02, at can not be used with from or to")
+	ProductRecordErrInvalidID          = errors.New("invalid ID")
 )
 
 type ProductRecord struct {
@@ -31,6 +36,7 @@ func NewProductRecord(service product_record.Service) *ProductRecord {
 // Create
 // @Summary     POST "ProductRecord"
 // @Description "Creates a new ProductRecord on the database"
+// @Description "A last_update_date after today schedules a price change, it becomes the price of the Product on that date"
 // @Tags        ProductRecords
 // @Accept      json
 // @Produce     json
@@ -82,3 +88,147 @@ func (pr *ProductRecord) Create() gin.HandlerFunc {
 		web.Success(ctx, http.StatusCreated, prod)
 	}
 }
+
+// PriceHistory
+// @Summary     GET the price history of a Product
+// @Description "Retrieves the purchase and sale prices of a Product ordered by date, between from and to when they are given"
+// @Description "With at, retrieves the ProductRecord in effect at that date instead, the one purchase orders take their price from"
+// @Tags        ProductRecords
+// @Produce     json
+// @Param       id   path     int               true  "Product ID"
+// @Param       from query    string            false "First date, 2006-01-02"
+// @Param       to   query    string            false "Last date, 2006-01-02"
+// @Param       at   query    string            false "Date of the price in effect, 2006-01-02"
+// @Success     200  {object} web.response      "Price history or price in effect"
+// @Failure     400  {object} web.errorResponse "Invalid ID or dates"
+// @Failure     404  {object} web.errorResponse "Product not found or without a price at the date"
+// @Failure     500  {object} web.errorResponse "Unknown or unhandled error"
+// @Router      /api/v1/products/{id}/priceHistory [get]
+func (pr *ProductRecord) PriceHistory() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		productID, errID := strconv.Atoi(ctx.Param("id"))
+		if errID != nil {
+			logging.Log(errID)
+			web.Error(ctx, http.StatusBadRequest, ProductRecordErrInvalidID.Error())
+			return
+		}
+		from, errFrom := queryDate(ctx, "from")
+		to, errTo := queryDate(ctx, "to")
+		at, errAt := queryDate(ctx, "at")
+		if errFrom != nil || errTo != nil || errAt != nil || (at != nil && (from != nil || to != nil)) {
+			web.Error(ctx, http.StatusBadRequest, ProductRecordErrInvalidQueryDate.Error())
+			return
+		}
+
+		var result interface{}
+		var errGet error
+		if at != nil {
+			result, errGet = pr.productRecordService.GetEffectivePrice(ctx, productID, *at)
+		} else {
+			result, errGet = pr.productRecordService.GetPriceHistory(ctx, productID, from, to)
+		}
+		if errGet != nil {
+			logging.Log(errGet)
+			switch errGet {
+			case product_record.ServiceErrNotFound, product_record.ServiceErrNoPrice:
+				web.Error(ctx, http.StatusNotFound, errGet.Error())
+			case product_record.ServiceErrDateRange:
+				web.Error(ctx, http.StatusBadRequest, errGet.Error())
+			default:
+				// errorMessage = "" for security reasons (we don't want to expose internal data to the outside)
+				web.Error(ctx, http.StatusInternalServerError, "")
+			}
+			return
+		}
+		web.Success(ctx, http.StatusOK, result)
+	}
+}
+
+// GetScheduled
+// @Summary     GET the scheduled price changes
+// @Description "Retrieves the ProductRecords dated after today ordered by date, each one becomes the price of its Product on its date"
+// @Tags        ProductRecords
+// @Produce     json
+// @Param       product_id query    int               false "Product ID"
+// @Success     200        {object} web.response      "Scheduled ProductRecords"
+// @Failure     400        {object} web.errorResponse "Invalid ID"
+// @Failure     404        {object} web.errorResponse "Product not found"
+// @Failure     500        {object} web.errorResponse "Unknown or unhandled error"
+// @Router      /api/v1/productRecords/scheduled [get]
+func (pr *ProductRecord) GetScheduled() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var productID *int
+		if idString := ctx.Query("product_id"); idString != "" {
+			id, errID := strconv.Atoi(idString)
+			if errID != nil {
+				logging.Log(errID)
+				web.Error(ctx, http.StatusBadRequest, ProductRecordErrInvalidID.Error())
+				return
+			}
+			productID = &id
+		}
+		records, errGet := pr.productRecordService.GetScheduled(ctx, productID)
+		if errGet != nil {
+			logging.Log(errGet)
+			switch errGet {
+			case product_record.ServiceErrNotFound:
+				web.Error(ctx, http.StatusNotFound, errGet.Error())
+			default:
+				// errorMessage = "" for security reasons (we don't want to expose internal data to the outside)
+				web.Error(ctx, http.StatusInternalServerError, "")
+			}
+			return
+		}
+		web.Success(ctx, http.StatusOK, records)
+	}
+}
+
+// Cancel
+// @Summary     DELETE a scheduled price change
+// @Description "Deletes a ProductRecord dated after today, records already effective can not be deleted"
+// @Tags        ProductRecords
+// @Param       id  path     int               true "ProductRecord ID"
+// @Success     204 {object} web.response      "Scheduled price change canceled"
+// @Failure     400 {object} web.errorResponse "Invalid ID"
+// @Failure     404 {object} web.errorResponse "ProductRecord not found"
+// @Failure     409 {object} web.errorResponse "ProductRecord already effective or used by purchase orders"
+// @Failure     500 {object} web.errorResponse "Unknown or unhandled error"
+// @Router      /api/v1/productRecords/{id} [delete]
+func (pr *ProductRecord) Cancel() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, errID := strconv.Atoi(ctx.Param("id"))
+		if errID != nil {
+			logging.Log(errID)
+			web.Error(ctx, http.StatusBadRequest, ProductRecordErrInvalidID.Error())
+			return
+		}
+		if errCancel := pr.productRecordService.Cancel(ctx, id); errCancel != nil {
+			logging.Log(errCancel)
+			switch errCancel {
+			case product_record.ServiceErrRecordNotFound:
+				web.Error(ctx, http.StatusNotFound, errCancel.Error())
+			case product_record.ServiceErrNotScheduled, product_record.ServiceErrReferenced:
+				web.Error(ctx, http.StatusConflict, errCancel.Error())
+			default:
+				// errorMessage = "" for security reasons (we don't want to expose internal data to the outside)
+				web.Error(ctx, http.StatusInternalServerError, "")
+			}
+			return
+		}
+		// Using ctx.Status(http.StatusNoContent) works on release mode, but on testing mode 204 is changed to 200 latter on
+		web.Success(ctx, http.StatusNoContent, "")
+	}
+}
+
+// queryDate returns the date of the query parameter, or nil when it is not given
+func queryDate(ctx *gin.Context, name string) (*time.Time, error) {
+	value := ctx.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	date, errParse := time.Parse(domain.ISO8601, value)
+	if errParse != nil {
+		return nil, errParse
+	}
+	return &date, nil
+}
@@ -374,3 +374,145 @@ func TestProductRecord_Create_BadDateNonsense(t *testing.T) {
 	assert.Equal(t, expectedCode, responseRecorder.Code)
 	assert.Equal(t, ProductRecordErrInvalidDate.Error(), response.Message)
 }
+
+func priceHistoryRequest(id string, query string) (*gin.Context, *httptest.ResponseRecorder) {
+	ctx, responseRecorder := setupProductRecordHandlersEngineMock()
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/api/v1/products/"+id+"/priceHistory"+query, nil)
+	ctx.Params = gin.Params{{Key: "id", Value: id}}
+	return ctx, responseRecorder
+}
+
+// TestProductRecord_PriceHistory_OK passes when the product exists (return 200 and domain.PriceHistory)
+func TestProductRecord_PriceHistory_OK(t *testing.T) {
+	// Arrange
+	expectedHistory := domain.PriceHistory{ProductID: 1, From: "2026-01-01", To: "2026-06-30", Records: []domain.ProductRecord{}}
+
+	// Act
+	ctx, responseRecorder := priceHistoryRequest("1", "?from=2026-01-01&to=2026-06-30")
+	productRecordService := product_record.ServiceMock{History: expectedHistory}
+	NewProductRecord(&productRecordService).PriceHistory()(ctx)
+	var response struct {
+		Data domain.PriceHistory `json:"data"`
+	}
+	errUnmarshal := json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+
+	// Assert
+	assert.Nil(t, errUnmarshal)
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	assert.Equal(t, expectedHistory, response.Data)
+	assert.Equal(t, "2026-01-01", productRecordService.LastFrom.Format(domain.ISO8601))
+	assert.Equal(t, "2026-06-30", productRecordService.LastTo.Format(domain.ISO8601))
+}
+
+// TestProductRecord_PriceHistory_OKAt passes when at is given (return 200 and domain.EffectivePrice)
+func TestProductRecord_PriceHistory_OKAt(t *testing.T) {
+	// Arrange
+	expectedPrice := domain.EffectivePrice{ProductID: 1, At: "2026-05-01", Record: domain.ProductRecord{ID: 2, PurchasePrice: 12, SalePrice: 18, ProductID: 1}}
+
+	// Act
+	ctx, responseRecorder := priceHistoryRequest("1", "?at=2026-05-01")
+	productRecordService := product_record.ServiceMock{Effective: expectedPrice}
+	NewProductRecord(&productRecordService).PriceHistory()(ctx)
+	var response struct {
+		Data domain.EffectivePrice `json:"data"`
+	}
+	errUnmarshal := json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+
+	// Assert
+	assert.Nil(t, errUnmarshal)
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	assert.Equal(t, expectedPrice.Record.SalePrice, response.Data.Record.SalePrice)
+	assert.Equal(t, "2026-05-01", productRecordService.LastAt.Format(domain.ISO8601))
+}
+
+// TestProductRecord_PriceHistory_Fail passes when the id or dates are invalid (400) or the product or price is missing (404)
+func TestProductRecord_PriceHistory_Fail(t *testing.T) {
+	tests := []struct {
+		id           string
+		query        string
+		err          error
+		expectedCode int
+	}{
+		{id: "a", expectedCode: http.StatusBadRequest},
+		{id: "1", query: "?from=01-01-2026", expectedCode: http.StatusBadRequest},
+		{id: "1", query: "?at=2026-05-01&to=2026-06-01", expectedCode: http.StatusBadRequest},
+		{id: "1", query: "?from=2026-06-01&to=2026-01-01", err: product_record.ServiceErrDateRange, expectedCode: http.StatusBadRequest},
+		{id: "1", err: product_record.ServiceErrNotFound, expectedCode: http.StatusNotFound},
+		{id: "1", query: "?at=2020-01-01", err: product_record.ServiceErrNoPrice, expectedCode: http.StatusNotFound},
+		{id: "1", err: product_record.ServiceErrInternal, expectedCode: http.StatusInternalServerError},
+	}
+	for _, test := range tests {
+		ctx, responseRecorder := priceHistoryRequest(test.id, test.query)
+		productRecordService := product_record.ServiceMock{ForcedErrHistory: test.err}
+		NewProductRecord(&productRecordService).PriceHistory()(ctx)
+
+		assert.Equal(t, test.expectedCode, responseRecorder.Code, test.query)
+	}
+}
+
+// TestProductRecord_GetScheduled_OK passes when the scheduled records of the product are returned (return 200)
+func TestProductRecord_GetScheduled_OK(t *testing.T) {
+	// Arrange
+	scheduled := []domain.ProductRecord{{ID: 4, PurchasePrice: 11, SalePrice: 16, ProductID: 1}}
+
+	// Act
+	ctx, responseRecorder := setupProductRecordHandlersEngineMock()
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/api/v1/productRecords/scheduled?product_id=1", nil)
+	productRecordService := product_record.ServiceMock{Scheduled: scheduled}
+	NewProductRecord(&productRecordService).GetScheduled()(ctx)
+	var response struct {
+		Data []domain.ProductRecord `json:"data"`
+	}
+	errUnmarshal := json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+
+	// Assert
+	assert.Nil(t, errUnmarshal)
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, 1, *productRecordService.LastProductID)
+}
+
+// TestProductRecord_GetScheduled_Fail passes when the product id is invalid (400) or the product is missing (404)
+func TestProductRecord_GetScheduled_Fail(t *testing.T) {
+	tests := []struct {
+		query        string
+		err          error
+		expectedCode int
+	}{
+		{query: "?product_id=a", expectedCode: http.StatusBadRequest},
+		{query: "?product_id=9", err: product_record.ServiceErrNotFound, expectedCode: http.StatusNotFound},
+		{err: product_record.ServiceErrInternal, expectedCode: http.StatusInternalServerError},
+	}
+	for _, test := range tests {
+		ctx, responseRecorder := setupProductRecordHandlersEngineMock()
+		ctx.Request = httptest.NewRequest(http.MethodGet, "/api/v1/productRecords/scheduled"+test.query, nil)
+		productRecordService := product_record.ServiceMock{ForcedErrScheduled: test.err}
+		NewProductRecord(&productRecordService).GetScheduled()(ctx)
+
+		assert.Equal(t, test.expectedCode, responseRecorder.Code, test.query)
+	}
+}
+
+// TestProductRecord_Cancel passes when a scheduled record is canceled (204) or can not be (400, 404, 409)
+func TestProductRecord_Cancel(t *testing.T) {
+	tests := []struct {
+		id           string
+		err          error
+		expectedCode int
+	}{
+		{id: "4", expectedCode: http.StatusNoContent},
+		{id: "a", expectedCode: http.StatusBadRequest},
+		{id: "9", err: product_record.ServiceErrRecordNotFound, expectedCode: http.StatusNotFound},
+		{id: "3", err: product_record.ServiceErrNotScheduled, expectedCode: http.StatusConflict},
+		{id: "4", err: product_record.ServiceErrReferenced, expectedCode: http.StatusConflict},
+	}
+	for _, test := range tests {
+		ctx, responseRecorder := setupProductRecordHandlersEngineMock()
+		ctx.Request = httptest.NewRequest(http.MethodDelete, "/api/v1/productRecords/"+test.id, nil)
+		ctx.Params = gin.Params{{Key: "id", Value: test.id}}
+		productRecordService := product_record.ServiceMock{ForcedErrScheduled: test.err}
+		NewProductRecord(&productRecordService).Cancel()(ctx)
+
+		assert.Equal(t, test.expectedCode, responseRecorder.Code, test.id)
+	}
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/cmd/server/handler/requests"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/product_types"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type ProductType struct {
+	productTypeService product_types.Service
+}
+
+func NewProductType(p product_types.Service) *ProductType {
+	return &ProductType{
+		productTypeService: p,
+	}
+}
+
+// GetAll List product types godoc
+// @Summary     List product types
+// @Tags        ProductTypes
+// @Description get every product type with its default temperature range
+// @Produce     json
+// @Success     200 {object} web.response
+// @Failure     500 {object} web.errorResponse
+// @Router      /api/v1/productTypes [get]
+func (h *ProductType) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		productTypes, err := h.productTypeService.GetAll(c)
+		if err != nil {
+			logging.Log(err)
+			web.Error(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, productTypes)
+	}
+}
+
+// Get ProductType by id godoc
+// @Summary     ProductType by id
+// @Tags        ProductTypes
+// @Description get product type
+// @Produce     json
+// @Param       id  path     int true "product type id"
+// @Success     200 {object} web.response
+// @Failure     400 {object} web.errorResponse
+// @Failure     404 {object} web.errorResponse
+// @Failure     500 {object} web.errorResponse
+// @Router      /api/v1/productTypes/{id} [get]
+func (h *ProductType) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		productType, err := h.productTypeService.Get(c, id)
+		if err != nil {
+			writeProductTypeError(c, err)
+			return
+		}
+		web.Success(c, http.StatusOK, productType)
+	}
+}
+
+// Create ProductType godoc
+// @Summary     Create ProductType
+// @Tags        ProductTypes
+// @Description create a product type with its default temperature range, names are unique without case
+// @Accept      json
+// @Produce     json
+// @Param       productType body     requests.ProductTypeRequest true "ProductType to create"
+// @Success     201         {object} web.response
+// @Failure     409         {object} web.errorResponse
+// @Failure     422         {object} web.errorResponse
+// @Failure     500         {object} web.errorResponse
+// @Router      /api/v1/productTypes [post]
+func (h *ProductType) Create() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.ProductTypeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+
+		productType, err := h.productTypeService.Create(c, domain.ProductType{Name: req.Name, MinimumTemperature: req.MinimumTemperature, MaximumTemperature: req.MaximumTemperature})
+		if err != nil {
+			writeProductTypeError(c, err)
+			return
+		}
+		web.Success(c, http.StatusCreated, productType)
+	}
+}
+
+// Update ProductType godoc
+// @Summary     Update ProductType
+// @Tags        ProductTypes
+// @Description rename a product type or change its default temperature range
+// @Accept      json
+// @Produce     json
+// @Param       id          path     int                              true "product type id"
+// @Param       productType body     requests.ProductTypePatchRequest true "Fields to change"
+// @Success     200         {object} web.response
+// @Failure     400         {object} web.errorResponse
+// @Failure     404         {object} web.errorResponse
+// @Failure     409         {object} web.errorResponse
+// @Failure     422         {object} web.errorResponse
+// @Failure     500         {object} web.errorResponse
+// @Router      /api/v1/productTypes/{id} [patch]
+func (h *ProductType) Update() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		var req requests.ProductTypePatchRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+
+		productType, err := h.productTypeService.Update(c, id, req.Name, req.MinimumTemperature, req.MaximumTemperature)
+		if err != nil {
+			writeProductTypeError(c, err)
+			return
+		}
+		web.Success(c, http.StatusOK, productType)
+	}
+}
+
+// Delete ProductType godoc
+// @Summary     Delete ProductType
+// @Tags        ProductTypes
+// @Description delete a product type no product or section uses
+// @Param       id  path int true "product type id"
+// @Success     204
+// @Failure     400 {object} web.errorResponse
+// @Failure     404 {object} web.errorResponse
+// @Failure     409 {object} web.errorResponse
+// @Failure     500 {object} web.errorResponse
+// @Router      /api/v1/productTypes/{id} [delete]
+func (h *ProductType) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		if err := h.productTypeService.Delete(c, id); err != nil {
+			writeProductTypeError(c, err)
+			return
+		}
+		web.Success(c, http.StatusNoContent, nil)
+	}
+}
+
+func writeProductTypeError(c *gin.Context, err error) {
+	logging.Log(err)
+	switch err {
+	case product_types.ErrNotFound:
+		web.Error(c, http.StatusNotFound, err.Error())
+	case product_types.ErrAlreadyExists, product_types.ErrInUse:
+		web.Error(c, http.StatusConflict, err.Error())
+	case product_types.ErrBadRequest, product_types.ErrInvalidRange, product_types.ErrPartialRange:
+		web.Error(c, http.StatusUnprocessableEntity, err.Error())
+	default:
+		web.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/product_types"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func createServerProductType(mockRepository *product_types.MockRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewProductType(product_types.NewService(mockRepository))
+
+	r := gin.Default()
+	pt := r.Group("/api/v1/productTypes")
+	pt.GET("", handler.GetAll())
+	pt.GET("/:id", handler.Get())
+	pt.POST("", handler.Create())
+	pt.PATCH("/:id", handler.Update())
+	pt.DELETE("/:id", handler.Delete())
+	return r
+}
+
+// TestCreateProductType_OK passes when the product type is created (status code 201)
+func TestCreateProductType_OK(t *testing.T) {
+	r := createServerProductType(&product_types.MockRepository{})
+	req, recorder := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/productTypes",
+		`{"name":"frozen","minimum_temperature":-30,"maximum_temperature":-18}`)
+	r.ServeHTTP(recorder, req)
+
+	var body struct {
+		Data domain.ProductType `json:"data"`
+	}
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Equal(t, domain.ProductType{ID: 1, Name: "frozen", MinimumTemperature: newFloatPointer(-30), MaximumTemperature: newFloatPointer(-18)}, body.Data)
+}
+
+// TestCreateProductType_FailMissingRange passes when the temperatures are not sent (status code 422)
+func TestCreateProductType_FailMissingRange(t *testing.T) {
+	r := createServerProductType(&product_types.MockRepository{})
+	req, recorder := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/productTypes", `{"name":"frozen"}`)
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+}
+
+// TestCreateProductType_FailInvalidRange passes when the minimum is greater than the maximum (status code 422)
+func TestCreateProductType_FailInvalidRange(t *testing.T) {
+	r := createServerProductType(&product_types.MockRepository{})
+	req, recorder := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/productTypes",
+		`{"name":"frozen","minimum_temperature":-18,"maximum_temperature":-30}`)
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+}
+
+// TestGetProductType_FailNotFound passes when the product type does not exist (status code 404)
+func TestGetProductType_FailNotFound(t *testing.T) {
+	r := createServerProductType(&product_types.MockRepository{})
+	req, recorder := createRequestTestPurchaseOrders(http.MethodGet, "/api/v1/productTypes/7", "")
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+// TestUpdateProductType_OK passes when only the sent values change (status code 200)
+func TestUpdateProductType_OK(t *testing.T) {
+	mockRepository := product_types.MockRepository{Data: []domain.ProductType{{ID: 1, Name: "frozen", MinimumTemperature: newFloatPointer(-30), MaximumTemperature: newFloatPointer(-18)}}}
+	r := createServerProductType(&mockRepository)
+	req, recorder := createRequestTestPurchaseOrders(http.MethodPatch, "/api/v1/productTypes/1", `{"maximum_temperature":-15}`)
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, domain.ProductType{ID: 1, Name: "frozen", MinimumTemperature: newFloatPointer(-30), MaximumTemperature: newFloatPointer(-15)}, mockRepository.Data[0])
+}
+
+// TestDeleteProductType_FailInUse passes when products or sections use the product type (status code 409)
+func TestDeleteProductType_FailInUse(t *testing.T) {
+	r := createServerProductType(&product_types.MockRepository{Data: []domain.ProductType{{ID: 1, Name: "frozen"}}, InUse: []int{1}})
+	req, recorder := createRequestTestPurchaseOrders(http.MethodDelete, "/api/v1/productTypes/1", "")
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+}
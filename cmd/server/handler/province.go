@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/cmd/server/handler/requests"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/province"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type Province struct {
+	provinceService province.Service
+}
+
+func NewProvince(p province.Service) *Province {
+	return &Province{
+		provinceService: p,
+	}
+}
+
+// GetAll List provinces godoc
+// @Summary     List provinces
+// @Tags        Provinces
+// @Description get every province, or the provinces of a country
+// @Produce     json
+// @Param       country_id query    int false "country id"
+// @Success     200        {object} web.response
+// @Failure     400        {object} web.errorResponse
+// @Failure     500        {object} web.errorResponse
+// @Router      /api/v1/provinces [get]
+func (h *Province) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var countryID int
+		if value := c.Query("country_id"); value != "" {
+			var err error
+			if countryID, err = strconv.Atoi(value); err != nil {
+				web.Error(c, http.StatusBadRequest, "invalid country_id")
+				return
+			}
+		}
+
+		provinces, err := h.provinceService.GetAll(c, countryID)
+		if err != nil {
+			writeProvinceError(c, err)
+			return
+		}
+		web.Success(c, http.StatusOK, provinces)
+	}
+}
+
+// Get Province by id godoc
+// @Summary     Province by id
+// @Tags        Provinces
+// @Description get province with its country
+// @Produce     json
+// @Param       id  path     int true "province id"
+// @Success     200 {object} web.response
+// @Failure     400 {object} web.errorResponse
+// @Failure     404 {object} web.errorResponse
+// @Failure     500 {object} web.errorResponse
+// @Router      /api/v1/provinces/{id} [get]
+func (h *Province) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		provinceObtained, err := h.provinceService.Get(c, id)
+		if err != nil {
+			writeProvinceError(c, err)
+			return
+		}
+		web.Success(c, http.StatusOK, provinceObtained)
+	}
+}
+
+// Create Province godoc
+// @Summary     Create Province
+// @Tags        Provinces
+// @Description create a province of a country, names are unique without case inside a country
+// @Accept      json
+// @Produce     json
+// @Param       province body     requests.ProvincePostRequest true "Province to create"
+// @Success     201      {object} web.response
+// @Failure     404      {object} web.errorResponse
+// @Failure     409      {object} web.errorResponse
+// @Failure     422      {object} web.errorResponse
+// @Failure     500      {object} web.errorResponse
+// @Router      /api/v1/provinces [post]
+func (h *Province) Create() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.ProvincePostRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+
+		provinceCreated, err := h.provinceService.Create(c, domain.Province{Name: req.Name, CountryID: req.CountryID})
+		if err != nil {
+			writeProvinceError(c, err)
+			return
+		}
+		web.Success(c, http.StatusCreated, provinceCreated)
+	}
+}
+
+// Update Province godoc
+// @Summary     Update Province
+// @Tags        Provinces
+// @Description rename a province or move it to another country
+// @Accept      json
+// @Produce     json
+// @Param       id       path     int                           true "province id"
+// @Param       province body     requests.ProvincePatchRequest true "Fields to update"
+// @Success     200      {object} web.response
+// @Failure     400      {object} web.errorResponse
+// @Failure     404      {object} web.errorResponse
+// @Failure     409      {object} web.errorResponse
+// @Failure     422      {object} web.errorResponse
+// @Failure     500      {object} web.errorResponse
+// @Router      /api/v1/provinces/{id} [patch]
+func (h *Province) Update() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		var req requests.ProvincePatchRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+
+		provinceUpdated, err := h.provinceService.Update(c, domain.Province{ID: id, Name: req.Name, CountryID: req.CountryID})
+		if err != nil {
+			writeProvinceError(c, err)
+			return
+		}
+		web.Success(c, http.StatusOK, provinceUpdated)
+	}
+}
+
+// Delete Province godoc
+// @Summary     Delete Province
+// @Tags        Provinces
+// @Description delete a province without localities
+// @Param       id  path int true "province id"
+// @Success     204
+// @Failure     400 {object} web.errorResponse
+// @Failure     404 {object} web.errorResponse
+// @Failure     409 {object} web.errorResponse
+// @Failure     500 {object} web.errorResponse
+// @Router      /api/v1/provinces/{id} [delete]
+func (h *Province) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		if err := h.provinceService.Delete(c, id); err != nil {
+			writeProvinceError(c, err)
+			return
+		}
+		web.Success(c, http.StatusNoContent, nil)
+	}
+}
+
+// GetReportSellers Province sellers report godoc
+// @Summary     Province sellers report
+// @Tags        Provinces
+// @Description get the sellers of every locality of a province and their total
+// @Produce     json
+// @Param       id  path     int true "province id"
+// @Success     200 {object} web.response
+// @Failure     400 {object} web.errorResponse
+// @Failure     404 {object} web.errorResponse
+// @Failure     500 {object} web.errorResponse
+// @Router      /api/v1/provinces/{id}/reportSellers [get]
+func (h *Province) GetReportSellers() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		report, err := h.provinceService.ReportSellers(c, id)
+		if err != nil {
+			writeProvinceError(c, err)
+			return
+		}
+		web.Success(c, http.StatusOK, report)
+	}
+}
+
+// GetReportCarries Province carries report godoc
+// @Summary     Province carries report
+// @Tags        Provinces
+// @Description get the carries of every locality of a province and their total
+// @Produce     json
+// @Param       id  path     int true "province id"
+// @Success     200 {object} web.response
+// @Failure     400 {object} web.errorResponse
+// @Failure     404 {object} web.errorResponse
+// @Failure     500 {object} web.errorResponse
+// @Router      /api/v1/provinces/{id}/reportCarries [get]
+func (h *Province) GetReportCarries() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		report, err := h.provinceService.ReportCarries(c, id)
+		if err != nil {
+			writeProvinceError(c, err)
+			return
+		}
+		web.Success(c, http.StatusOK, report)
+	}
+}
+
+func writeProvinceError(c *gin.Context, err error) {
+	logging.Log(err)
+	switch err {
+	case province.ErrNotFound, province.ErrCountryNotFound:
+		web.Error(c, http.StatusNotFound, err.Error())
+	case province.ErrAlreadyExists, province.ErrHasLocalities:
+		web.Error(c, http.StatusConflict, err.Error())
+	case province.ErrBadRequest:
+		web.Error(c, http.StatusUnprocessableEntity, err.Error())
+	default:
+		web.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
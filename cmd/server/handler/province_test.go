@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/province"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func createServerProvince(mockRepository *province.MockRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewProvince(province.NewService(mockRepository))
+
+	r := gin.Default()
+	pro := r.Group("/api/v1/provinces")
+	pro.GET("", handler.GetAll())
+	pro.GET("/:id", handler.Get())
+	pro.POST("", handler.Create())
+	pro.PATCH("/:id", handler.Update())
+	pro.DELETE("/:id", handler.Delete())
+	pro.GET("/:id/reportSellers", handler.GetReportSellers())
+	pro.GET("/:id/reportCarries", handler.GetReportCarries())
+	return r
+}
+
+// TestGetAllProvinces_ByCountry passes when the country filter reaches the repository (status code 200)
+func TestGetAllProvinces_ByCountry(t *testing.T) {
+	repo := province.MockRepository{Data: []domain.Province{{ID: 3, Name: "San Luis", CountryID: 1, CountryName: "Argentina"}}}
+	r := createServerProvince(&repo)
+	req, recorder := createRequestTestPurchaseOrders(http.MethodGet, "/api/v1/provinces?country_id=1", "")
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, 1, repo.LastCountryID)
+}
+
+// TestCreateProvince_FailMissingCountry passes when the body has no country_id (status code 422)
+func TestCreateProvince_FailMissingCountry(t *testing.T) {
+	r := createServerProvince(&province.MockRepository{})
+	req, recorder := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/provinces", `{"name":"San Luis"}`)
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+}
+
+// TestGetProvinceReportSellers_OK passes when the sellers of the localities are added up (status code 200)
+func TestGetProvinceReportSellers_OK(t *testing.T) {
+	repo := province.MockRepository{
+		Data:         []domain.Province{{ID: 3, Name: "San Luis", CountryID: 1, CountryName: "Argentina"}},
+		ReportSeller: []domain.ReportSellers{{LocalityID: "5700", LocalityName: "Capital", SellersCount: 2}, {LocalityID: "5730", LocalityName: "Villa Mercedes", SellersCount: 1}},
+	}
+	r := createServerProvince(&repo)
+	req, recorder := createRequestTestPurchaseOrders(http.MethodGet, "/api/v1/provinces/3/reportSellers", "")
+	r.ServeHTTP(recorder, req)
+
+	var body struct {
+		Data domain.ProvinceReportSellers `json:"data"`
+	}
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Equal(t, 3, body.Data.SellersCount)
+	assert.Len(t, body.Data.Localities, 2)
+}
+
+// TestGetProvinceReportCarries_FailNotFound passes when the province does not exist (status code 404)
+func TestGetProvinceReportCarries_FailNotFound(t *testing.T) {
+	r := createServerProvince(&province.MockRepository{})
+	req, recorder := createRequestTestPurchaseOrders(http.MethodGet, "/api/v1/provinces/3/reportCarries", "")
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -25,10 +26,13 @@ func NewPurchaseOrders(s purchaseorders.Service) *Purchase_Order {
 // Create CreateOrder godoc
 // @Summary     Create Purchase_Order
 // @Tags        Purchase_Order
-// @Description create Purchase_Order
+// @Description create Purchase_Order, either for a single product_record_id or for a list of items priced at the current sale price of each product.
+// @Description The stock of every item is held until the order is confirmed or the reservation TTL ends.
+// @Description An order with both items and a product_record_id is rejected with 400.
 // @Produce     json
 // @Param       purchase_order body     requests.RequestPurchaseOrdersPost true "Purchase_Order to store"
 // @Success     201            {object} web.response
+// @Failure     400            {object} web.errorResponse
 // @Failure     404            {object} web.errorResponse
 // @Failure     409            {object} web.errorResponse
 // @Failure     422            {object} web.errorResponse
@@ -43,8 +47,35 @@ func (o *Purchase_Order) CreateOrder() gin.HandlerFunc {
 			return
 		}
 
-		po, errCreate := o.service.SaveOrder(c, domain.Purchase_orders(req))
+		order := domain.Purchase_orders{
+			OrderNumber:     req.OrderNumber,
+			OrderDate:       req.OrderDate,
+			TrackingCode:    req.TrackingCode,
+			BuyerId:         req.BuyerId,
+			ProductRecordId: req.ProductRecordId,
+			OrderStatusId:   req.OrderStatusId,
+		}
+		for _, item := range req.Items {
+			order.Items = append(order.Items, domain.OrderItem{ProductId: item.ProductId, Quantity: item.Quantity})
+		}
+
+		po, errCreate := o.service.SaveOrder(c, order)
 		if errCreate != nil {
+			if errors.Is(errCreate, purchaseorders.ErrItemsAndRecord) {
+				logging.Log(errCreate.Error())
+				web.Error(c, http.StatusBadRequest, errCreate.Error())
+				return
+			}
+			if errors.Is(errCreate, purchaseorders.ErrNoItems) || errors.Is(errCreate, purchaseorders.ErrInvalidItems) || errors.Is(errCreate, purchaseorders.ErrPriceNotFound) || errors.Is(errCreate, purchaseorders.ErrPriceNotEffective) {
+				logging.Log(errCreate.Error())
+				web.Error(c, http.StatusUnprocessableEntity, errCreate.Error())
+				return
+			}
+			if errors.Is(errCreate, purchaseorders.ErrInsufficientStock) {
+				logging.Log(errCreate.Error())
+				web.Error(c, http.StatusConflict, errCreate.Error())
+				return
+			}
 			switch errCreate.Error() {
 			case purchaseorders.ErrAlreadyExists.Error():
 				logging.Log(errCreate.Error())
@@ -52,6 +83,9 @@ func (o *Purchase_Order) CreateOrder() gin.HandlerFunc {
 			case purchaseorders.ErrFKConstraint.Error():
 				logging.Log(errCreate.Error())
 				web.Error(c, http.StatusConflict, errCreate.Error())
+			case purchaseorders.ErrInitialStatus.Error():
+				logging.Log(errCreate.Error())
+				web.Error(c, http.StatusConflict, errCreate.Error())
 			case purchaseorders.ErrDataLong.Error():
 				logging.Log(errCreate.Error())
 				web.Error(c, http.StatusUnprocessableEntity, errCreate.Error())
@@ -105,3 +139,225 @@ func (o *Purchase_Order) GetAllOrdersByBuyers() gin.HandlerFunc {
 		web.Success(c, http.StatusOK, data)
 	}
 }
+
+// UpdateStatus Update Purchase_Order status godoc
+// @Summary     Update Purchase_Order status
+// @Tags        Purchase_Order
+// @Description move a Purchase_Order to a new status (pending, confirmed, picking, shipped, delivered or cancelled) and record who did it.
+// @Description Confirming makes the stock reservations firm, cancelling releases them and shipping takes the stock out of the batches.
+// @Produce     json
+// @Param       id     path     int                                      true "Purchase_Order id"
+// @Param       status body     requests.RequestPurchaseOrderStatusPatch true "new status and actor"
+// @Success     200    {object} web.response
+// @Failure     400    {object} web.errorResponse
+// @Failure     404    {object} web.errorResponse
+// @Failure     409    {object} web.errorResponse
+// @Failure     422    {object} web.errorResponse
+// @Failure     500    {object} web.errorResponse
+// @Router      /api/v1/purchase_orders/{id}/status [patch]
+func (o *Purchase_Order) UpdateStatus() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			logging.Log("invalid Id")
+			web.Error(c, http.StatusBadRequest, "invalid Id")
+			return
+		}
+
+		var req requests.RequestPurchaseOrderStatusPatch
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logging.Log(purchaseorders.ErrBodyValidation.Error())
+			web.Error(c, http.StatusUnprocessableEntity, purchaseorders.ErrBodyValidation.Error())
+			return
+		}
+
+		change, err := o.service.UpdateStatus(c, id, req.Status, req.Actor)
+		if err != nil {
+			switch {
+			case errors.Is(err, purchaseorders.ErrNotFound):
+				web.Error(c, http.StatusNotFound, err.Error())
+			case errors.Is(err, purchaseorders.ErrInvalidTransition), errors.Is(err, purchaseorders.ErrStatusChanged), errors.Is(err, purchaseorders.ErrInsufficientStock):
+				web.Error(c, http.StatusConflict, err.Error())
+			case errors.Is(err, purchaseorders.ErrInvalidStatus), errors.Is(err, purchaseorders.ErrDataLong):
+				web.Error(c, http.StatusUnprocessableEntity, err.Error())
+			default:
+				web.Error(c, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+		web.Success(c, http.StatusOK, change)
+	}
+}
+
+// GetStatusHistory List Purchase_Order status history godoc
+// @Summary     List Purchase_Order status history
+// @Tags        Purchase_Order
+// @Description get every status change of a Purchase_Order, the oldest first
+// @Produce     json
+// @Param       id  path     int true "Purchase_Order id"
+// @Success     200 {object} web.response
+// @Failure     400 {object} web.errorResponse
+// @Failure     404 {object} web.errorResponse
+// @Failure     500 {object} web.errorResponse
+// @Router      /api/v1/purchase_orders/{id}/status [get]
+func (o *Purchase_Order) GetStatusHistory() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			logging.Log("invalid Id")
+			web.Error(c, http.StatusBadRequest, "invalid Id")
+			return
+		}
+
+		history, err := o.service.GetStatusHistory(c, id)
+		if err != nil {
+			if errors.Is(err, purchaseorders.ErrNotFound) {
+				web.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, history)
+	}
+}
+
+// GetOrder Get Purchase_Order godoc
+// @Summary     Get Purchase_Order
+// @Tags        Purchase_Order
+// @Description get a Purchase_Order with its status, buyer and product record
+// @Produce     json
+// @Param       id  path     int true "Purchase_Order id"
+// @Success     200 {object} web.response
+// @Failure     400 {object} web.errorResponse
+// @Failure     404 {object} web.errorResponse
+// @Failure     500 {object} web.errorResponse
+// @Router      /api/v1/purchase_orders/{id} [get]
+func (o *Purchase_Order) GetOrder() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			logging.Log("invalid Id")
+			web.Error(c, http.StatusBadRequest, "invalid Id")
+			return
+		}
+
+		order, err := o.service.Get(c, id)
+		if err != nil {
+			if errors.Is(err, purchaseorders.ErrNotFound) {
+				web.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, order)
+	}
+}
+
+// GetOrders List Purchase_Order godoc
+// @Summary     List Purchase_Order
+// @Tags        Purchase_Order
+// @Description get the Purchase_Order with their status, buyer and product record, the most recent first
+// @Produce     json
+// @Param       buyer_id query    int    false "buyer id"
+// @Param       status   query    string false "status name"
+// @Param       from     query    string false "minimum order date (yyyy-mm-dd)"
+// @Param       to       query    string false "maximum order date (yyyy-mm-dd)"
+// @Success     200      {object} web.response
+// @Failure     400      {object} web.errorResponse
+// @Failure     500      {object} web.errorResponse
+// @Router      /api/v1/purchase_orders [get]
+func (o *Purchase_Order) GetOrders() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var filter domain.PurchaseOrderFilter
+		var err error
+		if buyerId := c.Query("buyer_id"); buyerId != "" {
+			if filter.BuyerId, err = strconv.Atoi(buyerId); err != nil {
+				logging.Log(err)
+				web.Error(c, http.StatusBadRequest, "invalid buyer_id %s", buyerId)
+				return
+			}
+		}
+		filter.From = c.Query("from")
+		filter.To = c.Query("to")
+
+		orders, err := o.service.GetAll(c, filter, c.Query("status"))
+		if err != nil {
+			switch err {
+			case purchaseorders.ErrInvalidStatus, purchaseorders.ErrInvalidDate:
+				web.Error(c, http.StatusBadRequest, err.Error())
+			default:
+				web.Error(c, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+		web.Success(c, http.StatusOK, orders)
+	}
+}
+
+// GetOrdersByBuyer List Purchase_Order of a buyer godoc
+// @Summary     List Purchase_Order of a buyer
+// @Tags        Purchase_Order
+// @Description get every Purchase_Order of a buyer with their status and product record, the most recent first
+// @Produce     json
+// @Param       id  path     int true "buyer id"
+// @Success     200 {object} web.response
+// @Failure     400 {object} web.errorResponse
+// @Failure     404 {object} web.errorResponse
+// @Failure     500 {object} web.errorResponse
+// @Router      /api/v1/buyers/{id}/purchase_orders [get]
+func (o *Purchase_Order) GetOrdersByBuyer() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			logging.Log("invalid Id")
+			web.Error(c, http.StatusBadRequest, "invalid Id")
+			return
+		}
+
+		orders, err := o.service.GetByBuyer(c, id)
+		if err != nil {
+			if errors.Is(err, purchaseorders.ErrBuyerNotFound) {
+				web.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, orders)
+	}
+}
+
+// GetReservations List Purchase_Order stock reservations godoc
+// @Summary     List Purchase_Order stock reservations
+// @Tags        Purchase_Order
+// @Description get the product batches and quantities reserved for a Purchase_Order
+// @Produce     json
+// @Param       id  path     int true "Purchase_Order id"
+// @Success     200 {object} web.response
+// @Failure     400 {object} web.errorResponse
+// @Failure     404 {object} web.errorResponse
+// @Failure     500 {object} web.errorResponse
+// @Router      /api/v1/purchase_orders/{id}/reservations [get]
+func (o *Purchase_Order) GetReservations() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			logging.Log("invalid Id")
+			web.Error(c, http.StatusBadRequest, "invalid Id")
+			return
+		}
+
+		reservations, err := o.service.GetReservations(c, id)
+		if err != nil {
+			if errors.Is(err, purchaseorders.ErrNotFound) {
+				web.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, reservations)
+	}
+}
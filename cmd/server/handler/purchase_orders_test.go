@@ -15,13 +15,20 @@ import (
 
 func createServerPurchaseOrders(mockRepository purchaseorders.MockRepository) *gin.Engine {
 	gin.SetMode(gin.TestMode)
-	service := purchaseorders.NewService(&mockRepository)
+	service := purchaseorders.NewService(&mockRepository, purchaseorders.DefaultReservationTTL)
 	handler := NewPurchaseOrders(service)
 
 	r := gin.Default()
 
 	pr := r.Group("/api/v1/purchase_orders")
+	pr.GET("/", handler.GetOrders())
+	pr.GET("/:id", handler.GetOrder())
 	pr.POST("/", handler.CreateOrder())
+	pr.GET("/:id/status", handler.GetStatusHistory())
+	pr.PATCH("/:id/status", handler.UpdateStatus())
+	pr.GET("/:id/reservations", handler.GetReservations())
+
+	r.GET("/api/v1/buyers/:id/purchase_orders", handler.GetOrdersByBuyer())
 
 	pr2 := r.Group("/api/v1/reportPurchaseOrder")
 	pr2.GET("", handler.GetAllOrdersByBuyers())
@@ -71,7 +78,7 @@ func TestCreateOrderFailErrorFKConstraint(t *testing.T) {
 		Err: purchaseorders.ErrFKConstraint,
 	}
 	r := createServerPurchaseOrders(repo)
-	req, recorder := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/purchase_orders/", `{"id":1, "order_number":"004", "order_date":"2022-10-10", "tracking_code":"asd233501", "buyer_id": 1, "product_record_id":1, "order_status_id":5}`)
+	req, recorder := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/purchase_orders/", `{"id":1, "order_number":"004", "order_date":"2022-10-10", "tracking_code":"asd233501", "buyer_id": 1, "product_record_id":1, "order_status_id":1}`)
 	r.ServeHTTP(recorder, req)
 
 	//asserts
@@ -86,7 +93,7 @@ func TestCreateOrderFailErrAlreadyExists(t *testing.T) {
 		Err: purchaseorders.ErrAlreadyExists,
 	}
 	r := createServerPurchaseOrders(repo)
-	req, recorder := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/purchase_orders/", `{"id":1, "order_number":"004", "order_date":"2022-10-10", "tracking_code":"asd233501", "buyer_id": 1, "product_record_id":1, "order_status_id":5}`)
+	req, recorder := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/purchase_orders/", `{"id":1, "order_number":"004", "order_date":"2022-10-10", "tracking_code":"asd233501", "buyer_id": 1, "product_record_id":1, "order_status_id":1}`)
 	r.ServeHTTP(recorder, req)
 
 	//asserts
@@ -101,7 +108,7 @@ func TestCreateOrderFailErrDataLong(t *testing.T) {
 		Err: purchaseorders.ErrDataLong,
 	}
 	r := createServerPurchaseOrders(repo)
-	req, recorder := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/purchase_orders/", `{"id":1, "order_number":"004", "order_date":"2022-10-10", "tracking_code":"asd233501", "buyer_id": 1, "product_record_id":1, "order_status_id":5}`)
+	req, recorder := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/purchase_orders/", `{"id":1, "order_number":"004", "order_date":"2022-10-10", "tracking_code":"asd233501", "buyer_id": 1, "product_record_id":1, "order_status_id":1}`)
 	r.ServeHTTP(recorder, req)
 
 	//asserts
@@ -227,3 +234,267 @@ func TestGetAllOrdersByBuyersFailStatusInternalServerError(t *testing.T) {
 	//arrange
 	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
 }
+
+// TestCreateOrderFailNotPending passes when an order created in other status than pending is rejected (status code 409)
+func TestCreateOrderFailNotPending(t *testing.T) {
+	repo := purchaseorders.MockRepository{}
+	r := createServerPurchaseOrders(repo)
+	req, recorder := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/purchase_orders/", `{"order_number":"006", "order_date":"2022-10-10", "tracking_code":"asd233501", "buyer_id": 1, "product_record_id":1, "order_status_id":4}`)
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+}
+
+// TestUpdateOrderStatusSuccess passes when the order moves to a following status (status code 200)
+func TestUpdateOrderStatusSuccess(t *testing.T) {
+	repo := purchaseorders.MockRepository{Status: domain.OrderStatusPending}
+	r := createServerPurchaseOrders(repo)
+	req, recorder := createRequestTestPurchaseOrders(http.MethodPatch, "/api/v1/purchase_orders/1/status", `{"status":"confirmed", "actor":"jdoe"}`)
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"from_status":"pending"`)
+	assert.Contains(t, recorder.Body.String(), `"to_status":"confirmed"`)
+	assert.Contains(t, recorder.Body.String(), `"actor":"jdoe"`)
+}
+
+// TestUpdateOrderStatusInvalidTransition passes when the order can not jump to the requested status (status code 409)
+func TestUpdateOrderStatusInvalidTransition(t *testing.T) {
+	repo := purchaseorders.MockRepository{Status: domain.OrderStatusPending}
+	r := createServerPurchaseOrders(repo)
+	req, recorder := createRequestTestPurchaseOrders(http.MethodPatch, "/api/v1/purchase_orders/1/status", `{"status":"delivered", "actor":"jdoe"}`)
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+}
+
+// TestUpdateOrderStatusFailures passes when every error is answered with its status code
+func TestUpdateOrderStatusFailures(t *testing.T) {
+	cases := []struct {
+		name string
+		url  string
+		body string
+		repo purchaseorders.MockRepository
+		code int
+	}{
+		{"invalid id", "/api/v1/purchase_orders/abc/status", `{"status":"confirmed", "actor":"jdoe"}`, purchaseorders.MockRepository{}, http.StatusBadRequest},
+		{"missing actor", "/api/v1/purchase_orders/1/status", `{"status":"confirmed"}`, purchaseorders.MockRepository{}, http.StatusUnprocessableEntity},
+		{"unknown status", "/api/v1/purchase_orders/1/status", `{"status":"lost", "actor":"jdoe"}`, purchaseorders.MockRepository{}, http.StatusUnprocessableEntity},
+		{"not found", "/api/v1/purchase_orders/1/status", `{"status":"confirmed", "actor":"jdoe"}`, purchaseorders.MockRepository{ErrStatus: purchaseorders.ErrNotFound}, http.StatusNotFound},
+		{"changed meanwhile", "/api/v1/purchase_orders/1/status", `{"status":"confirmed", "actor":"jdoe"}`, purchaseorders.MockRepository{Status: domain.OrderStatusPending, ErrUpdateStatus: purchaseorders.ErrStatusChanged}, http.StatusConflict},
+		{"stock gone", "/api/v1/purchase_orders/1/status", `{"status":"confirmed", "actor":"jdoe"}`, purchaseorders.MockRepository{Status: domain.OrderStatusPending, ErrUpdateStatus: purchaseorders.ErrInsufficientStock}, http.StatusConflict},
+		{"internal", "/api/v1/purchase_orders/1/status", `{"status":"confirmed", "actor":"jdoe"}`, purchaseorders.MockRepository{ErrStatus: purchaseorders.ErrInternal}, http.StatusInternalServerError},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := createServerPurchaseOrders(c.repo)
+			req, recorder := createRequestTestPurchaseOrders(http.MethodPatch, c.url, c.body)
+			r.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.code, recorder.Code)
+		})
+	}
+}
+
+// TestGetOrderStatusHistory passes when return the status changes of the order (status code 200)
+func TestGetOrderStatusHistory(t *testing.T) {
+	repo := purchaseorders.MockRepository{
+		Status: domain.OrderStatusConfirmed,
+		History: []domain.OrderStatusChange{
+			{ID: 1, PurchaseOrderId: 1, FromStatusId: domain.OrderStatusPending, ToStatusId: domain.OrderStatusConfirmed, Actor: "jdoe"},
+		},
+	}
+	r := createServerPurchaseOrders(repo)
+	req, recorder := createRequestTestPurchaseOrders(http.MethodGet, "/api/v1/purchase_orders/1/status", "")
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"to_status":"confirmed"`)
+}
+
+// TestGetOrderStatusHistoryNotFound passes when the order does not exist (status code 404)
+func TestGetOrderStatusHistoryNotFound(t *testing.T) {
+	repo := purchaseorders.MockRepository{ErrStatus: purchaseorders.ErrNotFound}
+	r := createServerPurchaseOrders(repo)
+	req, recorder := createRequestTestPurchaseOrders(http.MethodGet, "/api/v1/purchase_orders/1/status", "")
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+var purchaseOrderDetails = []domain.PurchaseOrderDetail{
+	{
+		ID:            1,
+		OrderNumber:   "002",
+		OrderDate:     "2022-10-10",
+		TrackingCode:  "asd233501",
+		OrderStatusId: domain.OrderStatusShipped,
+		Buyer:         domain.Buyer{ID: 3, CardNumberID: "402323", FirstName: "Jhon", LastName: "Doe"},
+		ProductRecord: &domain.ProductRecord{ID: 4, PurchasePrice: 10, SalePrice: 15.5, ProductID: 2},
+	},
+}
+
+// TestGetOrderSuccess passes when return the order with its buyer and product record (status code 200)
+func TestGetOrderSuccess(t *testing.T) {
+	r := createServerPurchaseOrders(purchaseorders.MockRepository{Details: purchaseOrderDetails})
+	req, recorder := createRequestTestPurchaseOrders(http.MethodGet, "/api/v1/purchase_orders/1", "")
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"status":"shipped"`)
+	assert.Contains(t, recorder.Body.String(), `"sale_price":15.5`)
+	assert.Contains(t, recorder.Body.String(), `"first_name":"Jhon"`)
+}
+
+// TestGetOrderFailures passes when every error is answered with its status code
+func TestGetOrderFailures(t *testing.T) {
+	cases := []struct {
+		name string
+		url  string
+		repo purchaseorders.MockRepository
+		code int
+	}{
+		{"invalid id", "/api/v1/purchase_orders/abc", purchaseorders.MockRepository{}, http.StatusBadRequest},
+		{"not found", "/api/v1/purchase_orders/2", purchaseorders.MockRepository{Details: purchaseOrderDetails}, http.StatusNotFound},
+		{"internal", "/api/v1/purchase_orders/1", purchaseorders.MockRepository{Err: purchaseorders.ErrInternal}, http.StatusInternalServerError},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := createServerPurchaseOrders(c.repo)
+			req, recorder := createRequestTestPurchaseOrders(http.MethodGet, c.url, "")
+			r.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.code, recorder.Code)
+		})
+	}
+}
+
+// TestGetOrdersSuccess passes when return the orders matching the filters (status code 200)
+func TestGetOrdersSuccess(t *testing.T) {
+	r := createServerPurchaseOrders(purchaseorders.MockRepository{Details: purchaseOrderDetails})
+	req, recorder := createRequestTestPurchaseOrders(http.MethodGet, "/api/v1/purchase_orders/?buyer_id=3&status=shipped&from=2022-10-01&to=2022-10-31", "")
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"order_number":"002"`)
+}
+
+// TestGetOrdersFailures passes when every error is answered with its status code
+func TestGetOrdersFailures(t *testing.T) {
+	cases := []struct {
+		name string
+		url  string
+		repo purchaseorders.MockRepository
+		code int
+	}{
+		{"invalid buyer", "/api/v1/purchase_orders/?buyer_id=abc", purchaseorders.MockRepository{}, http.StatusBadRequest},
+		{"invalid status", "/api/v1/purchase_orders/?status=lost", purchaseorders.MockRepository{}, http.StatusBadRequest},
+		{"invalid date", "/api/v1/purchase_orders/?from=10-10-2022", purchaseorders.MockRepository{}, http.StatusBadRequest},
+		{"internal", "/api/v1/purchase_orders/", purchaseorders.MockRepository{Err: purchaseorders.ErrInternal}, http.StatusInternalServerError},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := createServerPurchaseOrders(c.repo)
+			req, recorder := createRequestTestPurchaseOrders(http.MethodGet, c.url, "")
+			r.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.code, recorder.Code)
+		})
+	}
+}
+
+// TestGetOrdersByBuyer passes when return the orders of an existing buyer, or 404 for a missing one
+func TestGetOrdersByBuyer(t *testing.T) {
+	r := createServerPurchaseOrders(purchaseorders.MockRepository{Details: purchaseOrderDetails, Buyers: []int{3}})
+
+	req, recorder := createRequestTestPurchaseOrders(http.MethodGet, "/api/v1/buyers/3/purchase_orders", "")
+	r.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"tracking_code":"asd233501"`)
+
+	req, recorder = createRequestTestPurchaseOrders(http.MethodGet, "/api/v1/buyers/4/purchase_orders", "")
+	r.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	req, recorder = createRequestTestPurchaseOrders(http.MethodGet, "/api/v1/buyers/abc/purchase_orders", "")
+	r.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+// TestCreateOrderWithItemsSuccess passes when the items are priced and the total is computed (status code 201)
+func TestCreateOrderWithItemsSuccess(t *testing.T) {
+	repo := purchaseorders.MockRepository{Prices: []domain.ProductRecord{{ID: 7, ProductID: 1, SalePrice: 2.5}, {ID: 8, ProductID: 2, SalePrice: 10}}}
+	r := createServerPurchaseOrders(repo)
+	req, recorder := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/purchase_orders/", `{"order_number":"010", "order_date":"2022-10-10", "tracking_code":"asd233501", "buyer_id": 1, "items":[{"product_id":1, "quantity":3}, {"product_id":2, "quantity":1}]}`)
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"total":17.5`)
+	assert.Contains(t, recorder.Body.String(), `"subtotal":7.5`)
+}
+
+// TestCreateOrderWithItemsFailures passes when invalid items are rejected (status code 422)
+func TestCreateOrderWithItemsFailures(t *testing.T) {
+	repo := purchaseorders.MockRepository{Prices: []domain.ProductRecord{{ID: 7, ProductID: 1, SalePrice: 2.5}}}
+	bodies := map[string]string{
+		"no items":          `{"order_number":"010", "order_date":"2022-10-10", "tracking_code":"asd233501", "buyer_id": 1}`,
+		"missing quantity":  `{"order_number":"010", "order_date":"2022-10-10", "tracking_code":"asd233501", "buyer_id": 1, "items":[{"product_id":1}]}`,
+		"negative quantity": `{"order_number":"010", "order_date":"2022-10-10", "tracking_code":"asd233501", "buyer_id": 1, "items":[{"product_id":1, "quantity":-1}]}`,
+		"repeated product":  `{"order_number":"010", "order_date":"2022-10-10", "tracking_code":"asd233501", "buyer_id": 1, "items":[{"product_id":1, "quantity":1}, {"product_id":1, "quantity":2}]}`,
+		"without price":     `{"order_number":"010", "order_date":"2022-10-10", "tracking_code":"asd233501", "buyer_id": 1, "items":[{"product_id":2, "quantity":1}]}`,
+	}
+	for name, body := range bodies {
+		t.Run(name, func(t *testing.T) {
+			r := createServerPurchaseOrders(repo)
+			req, recorder := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/purchase_orders/", body)
+			r.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+		})
+	}
+}
+
+// TestCreateOrderWithItemsAndRecord passes when an order with both items and a product_record_id is rejected (status code 400)
+func TestCreateOrderWithItemsAndRecord(t *testing.T) {
+	repo := purchaseorders.MockRepository{Prices: []domain.ProductRecord{{ID: 7, ProductID: 1, SalePrice: 2.5}}}
+	r := createServerPurchaseOrders(repo)
+	req, recorder := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/purchase_orders/", `{"order_number":"010", "order_date":"2022-10-10", "tracking_code":"asd233501", "buyer_id": 1, "product_record_id": 7, "items":[{"product_id":1, "quantity":3}]}`)
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), purchaseorders.ErrItemsAndRecord.Error())
+}
+
+// TestCreateOrderInsufficientStock passes when the stock of the items cannot be held (status code 409)
+func TestCreateOrderInsufficientStock(t *testing.T) {
+	repo := purchaseorders.MockRepository{Prices: []domain.ProductRecord{{ID: 7, ProductID: 1, SalePrice: 2.5}}, ErrReserve: purchaseorders.ErrInsufficientStock}
+	r := createServerPurchaseOrders(repo)
+	req, recorder := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/purchase_orders/", `{"order_number":"010", "order_date":"2022-10-10", "tracking_code":"asd233501", "buyer_id": 1, "items":[{"product_id":1, "quantity":3}]}`)
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+}
+
+// TestGetOrderReservations passes when return the stock reservations of the order (status code 200)
+func TestGetOrderReservations(t *testing.T) {
+	repo := purchaseorders.MockRepository{
+		Status:       domain.OrderStatusPending,
+		Reservations: []domain.StockReservation{{ID: 1, PurchaseOrderId: 1, ProductBatchId: 4, ProductId: 2, Quantity: 3, Status: domain.ReservationHeld}},
+	}
+	r := createServerPurchaseOrders(repo)
+	req, recorder := createRequestTestPurchaseOrders(http.MethodGet, "/api/v1/purchase_orders/1/reservations", "")
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"status":"held"`)
+}
+
+// TestGetOrderReservationsNotFound passes when the order does not exist (status code 404)
+func TestGetOrderReservationsNotFound(t *testing.T) {
+	repo := purchaseorders.MockRepository{ErrStatus: purchaseorders.ErrNotFound}
+	r := createServerPurchaseOrders(repo)
+	req, recorder := createRequestTestPurchaseOrders(http.MethodGet, "/api/v1/purchase_orders/1/reservations", "")
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
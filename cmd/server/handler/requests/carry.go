@@ -7,3 +7,11 @@ type CarryPostRequest struct {
 	Telephone   *string `json:"telephone" binding:"required"`
 	Locality_id *string `json:"locality_id" binding:"required"`
 }
+
+type CarryPatchRequest struct {
+	CID         string `json:"cid"`
+	CompanyName string `json:"company_name"`
+	Address     string `json:"address"`
+	Telephone   string `json:"telephone"`
+	Locality_id string `json:"locality_id"`
+}
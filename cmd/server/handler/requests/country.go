@@ -0,0 +1,5 @@
+package requests
+
+type CountryRequest struct {
+	Name string `json:"name" binding:"required"`
+}
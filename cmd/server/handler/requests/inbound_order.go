@@ -7,3 +7,32 @@ type InboundOrderDTOPOST struct {
 	ProductBatchID *int    `json:"product_batch_id" binding:"required"`
 	WarehouseID    *int    `json:"warehouse_id" binding:"required"`
 }
+
+type InboundOrderDTOPATCH struct {
+	OrderDate      string `json:"order_date"`
+	OrderNumber    string `json:"order_number"`
+	EmployeeID     int    `json:"employee_id"`
+	ProductBatchID int    `json:"product_batch_id"`
+	WarehouseID    int    `json:"warehouse_id"`
+}
+
+type InboundOrderReceiveDTOPOST struct {
+	OrderDate    *string              `json:"order_date" binding:"required"`
+	OrderNumber  *string              `json:"order_number" binding:"required"`
+	EmployeeID   *int                 `json:"employee_id" binding:"required"`
+	WarehouseID  *int                 `json:"warehouse_id" binding:"required"`
+	ProductBatch ReceivedBatchDTOPOST `json:"product_batch" binding:"required"`
+}
+
+// ReceivedBatchDTOPOST holds the details of the product batch received with an inbound order
+type ReceivedBatchDTOPOST struct {
+	BatchNumber        *int    `json:"batch_number" binding:"required"`
+	Quantity           *int    `json:"quantity" binding:"required"`
+	CurrentTemperature *int    `json:"current_temperature" binding:"required"`
+	MinimumTemperature *int    `json:"minimum_temperature" binding:"required"`
+	DueDate            *string `json:"due_date" binding:"required"`
+	ManufacturingDate  *string `json:"manufacturing_date" binding:"required"`
+	ManufacturingHour  *int    `json:"manufacturing_hour" binding:"required"`
+	ProductID          *int    `json:"product_id" binding:"required"`
+	SectionID          *int    `json:"section_id" binding:"required"`
+}
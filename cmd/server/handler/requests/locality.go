@@ -0,0 +1,6 @@
+package requests
+
+type LocalityCoordinatesRequest struct {
+	Latitude  *float64 `json:"latitude" binding:"required"`
+	Longitude *float64 `json:"longitude" binding:"required"`
+}
@@ -6,11 +6,14 @@ var (
 	DefaultIntValue            = 0
 	DefaultFloatValue  float32 = 0.0
 	DefaultStringValue         = ""
+	// DefaultTemperatureValue is below absolute zero, the service takes it as a temperature that was not given
+	DefaultTemperatureValue float32 = -273
 )
 
 // A ProductPOSTRequest
 //   - uses pointers to allow 'zero' values on database
 //   - gin.context.ShouldBindJSON() validates 'required' on specified fields
+//   - RecommendedFreezingTemperature is optional, it defaults to the temperature range of the product type
 type ProductPOSTRequest struct {
 	Description                    *string  `json:"description" binding:"required"`
 	ExpirationRate                 *int     `json:"expiration_rate" binding:"required"`
@@ -19,7 +22,7 @@ type ProductPOSTRequest struct {
 	Length                         *float32 `json:"length" binding:"required"`
 	NetWeight                      *float32 `json:"net_weight" binding:"required"`
 	ProductCode                    *string  `json:"product_code" binding:"required"`
-	RecommendedFreezingTemperature *float32 `json:"recommended_freezing_temperature" binding:"required"`
+	RecommendedFreezingTemperature *float32 `json:"recommended_freezing_temperature"`
 	Width                          *float32 `json:"width" binding:"required"`
 	ProductTypeID                  *int     `json:"product_type_id" binding:"required"`
 	SellerID                       *int     `json:"seller_id"`
@@ -43,6 +46,9 @@ type ProductPATCHRequest struct {
 }
 
 func (request *ProductPOSTRequest) MapToDomain() domain.Product {
+	if request.RecommendedFreezingTemperature == nil {
+		request.RecommendedFreezingTemperature = &DefaultTemperatureValue
+	}
 	return domain.Product{
 		Description:                    *request.Description,
 		ExpirationRate:                 *request.ExpirationRate,
@@ -12,3 +12,22 @@ type PostProductBatch struct {
 	ProductID          int    `json:"product_id" binding:"required"`
 	SectionID          int    `json:"section_id" binding:"required"`
 }
+
+// A PatchProductBatch recives the body of a request, and atempts to update a product batch in the database
+type PatchProductBatch struct {
+	BatchNumber        int    `json:"batch_number"`
+	CurrentQuantity    *int   `json:"current_quantity"`
+	CurrentTemperature *int   `json:"current_temperature"`
+	DueDate            string `json:"due_date"`
+	InitialQuantity    int    `json:"initial_quantity"`
+	ManufacturingDate  string `json:"manufacturing_date"`
+	ManufacturingHour  int    `json:"manufacturing_hour"`
+	MinimumTemperature *int   `json:"minimum_temperature"`
+	ProductID          int    `json:"product_id"`
+	SectionID          int    `json:"section_id"`
+}
+
+type PickProductBatch struct {
+	ProductID int `json:"product_id" binding:"required"`
+	Quantity  int `json:"quantity" binding:"required"`
+}
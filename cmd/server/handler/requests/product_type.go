@@ -0,0 +1,14 @@
+package requests
+
+type ProductTypeRequest struct {
+	Name               string   `json:"name" binding:"required"`
+	MinimumTemperature *float32 `json:"minimum_temperature" binding:"required"`
+	MaximumTemperature *float32 `json:"maximum_temperature" binding:"required"`
+}
+
+// ProductTypePatchRequest only changes the fields that are given
+type ProductTypePatchRequest struct {
+	Name               *string  `json:"name"`
+	MinimumTemperature *float32 `json:"minimum_temperature"`
+	MaximumTemperature *float32 `json:"maximum_temperature"`
+}
@@ -0,0 +1,11 @@
+package requests
+
+type ProvincePostRequest struct {
+	Name      string `json:"name" binding:"required"`
+	CountryID int    `json:"country_id" binding:"required"`
+}
+
+type ProvincePatchRequest struct {
+	Name      string `json:"name"`
+	CountryID int    `json:"country_id"`
+}
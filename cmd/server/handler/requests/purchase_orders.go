@@ -6,6 +6,18 @@ type RequestPurchaseOrdersPost struct {
 	OrderDate       string `json:"order_date" binding:"required"`
 	TrackingCode    string `json:"tracking_code" binding:"required"`
 	BuyerId         int    `json:"buyer_id" binding:"required"`
-	ProductRecordId int    `json:"product_record_id" binding:"required"`
-	OrderStatusId   int    `json:"order_status_id" binding:"required"`
+	ProductRecordId int    `json:"product_record_id"`
+	OrderStatusId   int    `json:"order_status_id"`
+	// Items replace product_record_id for orders of several products
+	Items []RequestOrderItemPost `json:"items" binding:"dive"`
+}
+
+type RequestOrderItemPost struct {
+	ProductId int `json:"product_id" binding:"required"`
+	Quantity  int `json:"quantity" binding:"required"`
+}
+
+type RequestPurchaseOrderStatusPatch struct {
+	Status string `json:"status" binding:"required"`
+	Actor  string `json:"actor" binding:"required"`
 }
@@ -0,0 +1,15 @@
+package requests
+
+import "time"
+
+// PostSectionTemperatures recives a batch of sensor readings, and returns error if there are values missing
+type PostSectionTemperatures struct {
+	Readings []SectionTemperatureReading `json:"readings" binding:"required,dive"`
+}
+
+// SectionTemperatureReading is a single reading, the timestamp must be in RFC3339 format
+type SectionTemperatureReading struct {
+	SectionID   int       `json:"section_id" binding:"required"`
+	Timestamp   time.Time `json:"timestamp" binding:"required"`
+	Temperature *float32  `json:"temperature" binding:"required"`
+}
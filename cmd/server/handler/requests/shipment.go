@@ -0,0 +1,11 @@
+package requests
+
+type RequestShipmentPost struct {
+	PurchaseOrderIds []int `json:"purchase_order_ids" binding:"required"`
+	// TrackingCode is generated when it is empty
+	TrackingCode string `json:"tracking_code"`
+}
+
+type RequestShipmentEvent struct {
+	Actor string `json:"actor" binding:"required"`
+}
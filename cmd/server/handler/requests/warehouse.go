@@ -6,6 +6,8 @@ type WarehousePostRequest struct {
 	WarehouseCode      *string `json:"warehouse_code" binding:"required"`
 	MinimumCapacity    *int    `json:"minimum_capacity" binding:"required"`
 	MinimumTemperature *int    `json:"minimum_temperature" binding:"required"`
+	TemperaturePolicy  string  `json:"temperature_policy"`
+	LocalityID         string  `json:"locality_id"`
 }
 
 type WarehousePatchRequest struct {
@@ -14,4 +16,6 @@ type WarehousePatchRequest struct {
 	WarehouseCode      *string `json:"warehouse_code"`
 	MinimumCapacity    *int    `json:"minimum_capacity"`
 	MinimumTemperature *int    `json:"minimum_temperature"`
+	TemperaturePolicy  *string `json:"temperature_policy"`
+	LocalityID         *string `json:"locality_id"`
 }
@@ -3,6 +3,7 @@ package handler
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/cmd/server/handler/requests"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
@@ -86,7 +87,9 @@ func (s *Section) Get() gin.HandlerFunc {
 // Create CreateSection godoc
 // @Summary     Create section
 // @Tags        Sections
-// @Description create section
+// @Description create section, current_temperature and minimum_temperature default to the warmest and coldest temperature
+// @Description of the range of the product type and are required when the product type has no range,
+// @Description current_capacity is kept by the product batches of the section and starts at 0
 // @Produce     json
 // @Param       section body     requests.PostSection true "Section to store"
 // @Success     201     {object} web.response
@@ -102,11 +105,22 @@ func (s *Section) Create() gin.HandlerFunc {
 			web.Error(c, http.StatusUnprocessableEntity, err.Error())
 			return
 		}
-		sec, err := s.sectionService.Create(c, domain.Section{ID: 0, SectionNumber: req.SectionNumber, CurrentTemperature: *req.CurrentTemperature, MinimumTemperature: *req.MinimumTemperature, CurrentCapacity: req.CurrentCapacity, MinimumCapacity: req.MinimumCapacity, MaximumCapacity: req.MaximumCapacity, WarehouseID: req.WarehouseID, ProductTypeID: req.ProductTypeID})
+		currentTemperature, minimumTemperature := -273, -273
+		if req.CurrentTemperature != nil {
+			currentTemperature = *req.CurrentTemperature
+		}
+		if req.MinimumTemperature != nil {
+			minimumTemperature = *req.MinimumTemperature
+		}
+		sec, err := s.sectionService.Create(c, domain.Section{ID: 0, SectionNumber: req.SectionNumber, CurrentTemperature: currentTemperature, MinimumTemperature: minimumTemperature, MinimumCapacity: req.MinimumCapacity, MaximumCapacity: req.MaximumCapacity, WarehouseID: req.WarehouseID, ProductTypeID: req.ProductTypeID})
 		if err != nil {
 			switch err {
 			case section.ErrAlreadyExists:
 				web.Error(c, http.StatusConflict, "a section with the section_number %d already exists", req.SectionNumber)
+			case section.ErrCapacityExceeded, section.ErrForeignNotFound, section.ErrProductTypeNotFound:
+				web.Error(c, http.StatusConflict, err.Error())
+			case section.ErrNoTemperatureRange:
+				web.Error(c, http.StatusUnprocessableEntity, err.Error())
 			default:
 				web.Error(c, http.StatusInternalServerError, err.Error())
 			}
@@ -120,7 +134,10 @@ func (s *Section) Create() gin.HandlerFunc {
 // Update UpdateSection godoc
 // @Summary     Update section
 // @Tags        Sections
-// @Description update section
+// @Description update section, the stored batches whose products do not fit a new temperature are listed as affected_batches
+// @Description and the change is rejected with 409 unless the warehouse temperature policy is warn
+// @Description current_capacity is kept by the product batches of the section, a body that sets it is rejected with 400
+// @Description product_type_id can not change while the section stores batches of another product type, 409
 // @Produce     json
 // @Param       section body     requests.PatchSection true "Updated section"
 // @Success     200     {object} web.response
@@ -144,6 +161,11 @@ func (s *Section) Update() gin.HandlerFunc {
 			web.Error(c, http.StatusBadRequest, err.Error())
 			return
 		}
+		if req.CurrentCapacity != nil {
+			logging.Log(section.ErrCapacityReadOnly)
+			web.Error(c, http.StatusBadRequest, section.ErrCapacityReadOnly.Error())
+			return
+		}
 		var currentTemperature, minimumTemperature int
 		if req.CurrentTemperature == nil {
 			currentTemperature = -273
@@ -155,7 +177,7 @@ func (s *Section) Update() gin.HandlerFunc {
 		} else {
 			minimumTemperature = *req.MinimumTemperature
 		}
-		data, err := s.sectionService.Update(c, domain.Section{ID: sectionId, SectionNumber: req.SectionNumber, CurrentTemperature: currentTemperature, MinimumTemperature: minimumTemperature, CurrentCapacity: req.CurrentCapacity, MinimumCapacity: req.MinimumCapacity, MaximumCapacity: req.MaximumCapacity, WarehouseID: req.WarehouseID, ProductTypeID: req.ProductTypeID})
+		data, err := s.sectionService.Update(c, domain.Section{ID: sectionId, SectionNumber: req.SectionNumber, CurrentTemperature: currentTemperature, MinimumTemperature: minimumTemperature, MinimumCapacity: req.MinimumCapacity, MaximumCapacity: req.MaximumCapacity, WarehouseID: req.WarehouseID, ProductTypeID: req.ProductTypeID})
 		if err != nil {
 			switch err {
 			case section.ErrNotFound:
@@ -163,6 +185,10 @@ func (s *Section) Update() gin.HandlerFunc {
 			case section.ErrAlreadyExists:
 				web.Error(c, http.StatusConflict, "a section with the section_number %d already exists", req.SectionNumber)
 				return
+			case section.ErrCapacityExceeded, section.ErrForeignNotFound, section.ErrProductTypeNotFound, section.ErrStoredProductType:
+				web.Error(c, http.StatusConflict, err.Error())
+			case section.ErrTemperature:
+				web.Error(c, http.StatusConflict, "%s, affected batch numbers: %s", err.Error(), batchNumbers(data.AffectedBatches))
 			default:
 				web.Error(c, http.StatusInternalServerError, err.Error())
 			}
@@ -176,12 +202,20 @@ func (s *Section) Update() gin.HandlerFunc {
 // Delete DeleteSection godoc
 // @Summary     Delete section
 // @Tags        Sections
-// @Description delete section
+// @Description delete section, with strategy=block (default) the delete fails while batches, excursions or temperature readings reference it,
+// @Description with strategy=reassign&to=<id> the batches and the current capacity move to a section of the same product type first.
+// @Description Temperature readings and excursions are never reassigned and block the delete, and the moved batches whose products
+// @Description do not fit the temperatures of the target are rejected with 409 unless its warehouse temperature policy is warn
 // @Produce     json
-// @Param       id path int true "section id"
+// @Param       id       path  int    true  "section id"
+// @Param       strategy query string false "block or reassign"
+// @Param       to       query int    false "section to reassign to"
+// @Success     200 {object} web.response "Deleted, the moved batches that do not fit the target"
 // @Success     204
 // @Failure     400 {object} web.errorResponse
 // @Failure     404 {object} web.errorResponse
+// @Failure     409 {object} web.errorResponse
+// @Failure     422 {object} web.errorResponse
 // @Failure     500 {object} web.errorResponse
 // @Router      /sections/{id} [delete]
 func (s *Section) Delete() gin.HandlerFunc {
@@ -193,18 +227,78 @@ func (s *Section) Delete() gin.HandlerFunc {
 			web.Error(c, http.StatusBadRequest, err.Error())
 			return
 		}
-		err = s.sectionService.Delete(c, sectionId)
+		options, err := deleteOptions(c)
+		if err != nil {
+			logging.Log(err)
+			web.Error(c, http.StatusBadRequest, section.ErrInvalidStrategy.Error())
+			return
+		}
+		affected, err := s.sectionService.Delete(c, sectionId, options)
+		if err != nil {
+			logging.Log(err)
+			switch err {
+			case section.ErrNotFound:
+				web.Error(c, http.StatusNotFound, "The section with id %d does not exists", sectionId)
+			case section.ErrInvalidStrategy:
+				web.Error(c, http.StatusBadRequest, err.Error())
+			case section.ErrHasDependents, section.ErrHasHistory, section.ErrTargetProductType, section.ErrCapacityExceeded:
+				web.Error(c, http.StatusConflict, err.Error())
+			case section.ErrTemperature:
+				web.Error(c, http.StatusConflict, "%s, affected batch numbers: %s", err.Error(), batchNumbers(affected))
+			case section.ErrTargetNotFound:
+				web.Error(c, http.StatusUnprocessableEntity, err.Error())
+			default:
+				web.Error(c, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+		if len(affected) > 0 {
+			web.Success(c, http.StatusOK, affected)
+			return
+		}
+		web.Success(c, http.StatusNoContent, "")
+	}
+}
+
+// batchNumbers lists the batch numbers of the batches that do not fit the temperatures of a section
+func batchNumbers(affected []domain.BatchTemperature) string {
+	numbers := make([]string, 0, len(affected))
+	for _, bt := range affected {
+		numbers = append(numbers, strconv.Itoa(bt.BatchNumber))
+	}
+	return strings.Join(numbers, ", ")
+}
+
+// DeletePreview DeletePreviewSection godoc
+// @Summary     Preview section delete
+// @Tags        Sections
+// @Description list the batches, excursions and temperature readings a delete of the section affects and the strategies it accepts
+// @Produce     json
+// @Param       id  path     int true "section id"
+// @Success     200 {object} web.response
+// @Failure     400 {object} web.errorResponse
+// @Failure     404 {object} web.errorResponse
+// @Failure     500 {object} web.errorResponse
+// @Router      /sections/{id}/deletePreview [get]
+func (s *Section) DeletePreview() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sectionId, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			logging.Log(err)
+			web.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		data, err := s.sectionService.DeletePreview(c, sectionId)
 		if err != nil {
+			logging.Log(err)
 			if err == section.ErrNotFound {
-				logging.Log(err)
 				web.Error(c, http.StatusNotFound, "The section with id %d does not exists", sectionId)
 				return
 			}
-			logging.Log(err)
 			web.Error(c, http.StatusInternalServerError, err.Error())
 			return
 		}
-		web.Success(c, http.StatusNoContent, "")
+		web.Success(c, http.StatusOK, data)
 	}
 }
 
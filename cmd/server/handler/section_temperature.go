@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/cmd/server/handler/requests"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/telemetry"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type SectionTemperature struct {
+	telemetryService telemetry.Service
+}
+
+func NewSectionTemperature(s telemetry.Service) *SectionTemperature {
+	return &SectionTemperature{
+		telemetryService: s,
+	}
+}
+
+// Create CreateSectionTemperatures godoc
+// @Summary     Store section temperature readings
+// @Tags        Sections
+// @Description store a batch of sensor readings, the current temperature of each section is taken from its latest reading
+// @Produce     json
+// @Param       readings body     requests.PostSectionTemperatures true "Readings to store"
+// @Success     201      {object} web.response
+// @Failure     404      {object} web.errorResponse
+// @Failure     422      {object} web.errorResponse
+// @Failure     500      {object} web.errorResponse
+// @Router      /sections/temperatures [post]
+func (st *SectionTemperature) Create() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req requests.PostSectionTemperatures
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logging.Log(err)
+			web.Error(c, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		readings := make([]domain.SectionTemperature, 0, len(req.Readings))
+		for _, reading := range req.Readings {
+			readings = append(readings, domain.SectionTemperature{SectionID: reading.SectionID, RecordedAt: reading.Timestamp, Temperature: *reading.Temperature})
+		}
+		data, err := st.telemetryService.Ingest(c, readings)
+		if err != nil {
+			switch err {
+			case telemetry.ErrSectionNotFound:
+				web.Error(c, http.StatusNotFound, err.Error())
+			case telemetry.ErrNoReadings, telemetry.ErrTooManyReadings, telemetry.ErrInvalidReading:
+				web.Error(c, http.StatusUnprocessableEntity, err.Error())
+			default:
+				web.Error(c, http.StatusInternalServerError, err.Error())
+			}
+			logging.Log(err)
+			return
+		}
+		web.Success(c, http.StatusCreated, data)
+	}
+}
+
+// GetHistory GetSectionTemperatures godoc
+// @Summary     Section temperature history
+// @Tags        Sections
+// @Description get the minimum, average and maximum temperature of a section per bucket, the last day in buckets of one hour by default
+// @Produce     json
+// @Param       id     path     int    true  "section id"
+// @Param       from   query    string false "start, yyyy-mm-dd or RFC3339"
+// @Param       to     query    string false "end (exclusive), yyyy-mm-dd or RFC3339"
+// @Param       bucket query    string false "bucket duration, like 15m or 1h"
+// @Success     200    {object} web.response
+// @Failure     400    {object} web.errorResponse
+// @Failure     404    {object} web.errorResponse
+// @Failure     500    {object} web.errorResponse
+// @Router      /sections/{id}/temperatures [get]
+func (st *SectionTemperature) GetHistory() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sectionID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			logging.Log(err)
+			web.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		data, err := st.telemetryService.GetHistory(c, sectionID, c.Query("from"), c.Query("to"), c.Query("bucket"))
+		if err != nil {
+			switch err {
+			case telemetry.ErrSectionNotFound:
+				web.Error(c, http.StatusNotFound, "The section with id %d does not exists", sectionID)
+			case telemetry.ErrInvalidPeriod, telemetry.ErrInvalidBucket:
+				web.Error(c, http.StatusBadRequest, err.Error())
+			default:
+				web.Error(c, http.StatusInternalServerError, err.Error())
+			}
+			logging.Log(err)
+			return
+		}
+		web.Success(c, http.StatusOK, data)
+	}
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/telemetry"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type responseSectionTemperatures struct {
+	Data []domain.SectionTemperature `json:"data"`
+}
+
+type responseTemperatureBuckets struct {
+	Data []domain.TemperatureBucket `json:"data"`
+}
+
+func createSectionTemperatureServer(mockRepository *telemetry.MockRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewSectionTemperature(telemetry.NewService(mockRepository))
+	router := gin.Default()
+
+	sec := router.Group("/sections")
+	sec.POST("/temperatures", handler.Create())
+	sec.GET("/:id/temperatures", handler.GetHistory())
+
+	return router
+}
+
+func TestSectionTemperatureCreateOk(t *testing.T) {
+	var response responseSectionTemperatures
+	mockRepository := telemetry.MockRepository{}
+	router := createSectionTemperatureServer(&mockRepository)
+	body := `{"readings": [{"section_id": 1, "timestamp": "2026-05-01T10:00:00Z", "temperature": -18.5}, {"section_id": 1, "timestamp": "2026-05-01T10:05:00Z", "temperature": 0}]}`
+
+	req, rw := createRequestTest(http.MethodPost, "/sections/temperatures", body)
+	router.ServeHTTP(rw, req)
+
+	assert.Equal(t, 201, rw.Code)
+	assert.NoError(t, json.Unmarshal(rw.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 2)
+	assert.Equal(t, float32(0), response.Data[1].Temperature)
+}
+
+func TestSectionTemperatureCreateInvalid(t *testing.T) {
+	router := createSectionTemperatureServer(&telemetry.MockRepository{})
+	body := `{"readings": [{"section_id": 1, "timestamp": "yesterday", "temperature": -18.5}]}`
+
+	req, rw := createRequestTest(http.MethodPost, "/sections/temperatures", body)
+	router.ServeHTTP(rw, req)
+
+	assert.Equal(t, 422, rw.Code)
+}
+
+func TestSectionTemperatureCreateSectionNotFound(t *testing.T) {
+	router := createSectionTemperatureServer(&telemetry.MockRepository{Error: telemetry.ErrSectionNotFound})
+	body := `{"readings": [{"section_id": 9, "timestamp": "2026-05-01T10:00:00Z", "temperature": -18.5}]}`
+
+	req, rw := createRequestTest(http.MethodPost, "/sections/temperatures", body)
+	router.ServeHTTP(rw, req)
+
+	assert.Equal(t, 404, rw.Code)
+}
+
+func TestSectionTemperatureGetHistoryOk(t *testing.T) {
+	var response responseTemperatureBuckets
+	buckets := []domain.TemperatureBucket{{From: "2026-05-01T10:00:00Z", Minimum: -19, Average: -18.5, Maximum: -18, Readings: 4}}
+	router := createSectionTemperatureServer(&telemetry.MockRepository{DataMockBuckets: buckets})
+
+	req, rw := createRequestTest(http.MethodGet, "/sections/1/temperatures?from=2026-05-01&to=2026-05-02&bucket=1h", "")
+	router.ServeHTTP(rw, req)
+
+	assert.Equal(t, 200, rw.Code)
+	assert.NoError(t, json.Unmarshal(rw.Body.Bytes(), &response))
+	assert.Equal(t, buckets, response.Data)
+}
+
+func TestSectionTemperatureGetHistoryBadRequest(t *testing.T) {
+	router := createSectionTemperatureServer(&telemetry.MockRepository{})
+
+	req, rw := createRequestTest(http.MethodGet, "/sections/1/temperatures?bucket=often", "")
+	router.ServeHTTP(rw, req)
+
+	assert.Equal(t, 400, rw.Code)
+}
+
+func TestSectionTemperatureGetHistoryNotFound(t *testing.T) {
+	router := createSectionTemperatureServer(&telemetry.MockRepository{MissingSection: true})
+
+	req, rw := createRequestTest(http.MethodGet, "/sections/1/temperatures", "")
+	router.ServeHTTP(rw, req)
+
+	assert.Equal(t, 404, rw.Code)
+}
@@ -49,18 +49,20 @@ func createSectionServer() *gin.Engine {
 	sec.POST("", p.Create())
 	sec.PATCH("/:id", p.Update())
 	sec.DELETE("/:id", p.Delete())
+	sec.GET("/:id/deletePreview", p.DeletePreview())
 	sec.GET("/reportProducts", p.GetSectionProducts())
 
 	return r
 }
 
-// TestSectionCreateOk tests if the hanlder correctly calls the sectionService to create in storage and return the given section
+// TestSectionCreateOk tests if the hanlder correctly calls the sectionService to create in storage and return the given section,
+// a current capacity in the body is ignored since the section starts empty
 func TestSectionCreateOk(t *testing.T) {
 	sectionService = section.MockService{
 		MockSections: []domain.Section{},
 		MockError:    nil,
 	}
-	body := `{"section_number":1,"current_temperature":-1,"minimum_temperature":-5,"current_capacity":1,"minimum_capacity":1,"maximum_capacity":1,"warehouse_id":1,"product_type_id":1}`
+	body := `{"section_number":1,"current_temperature":-1,"minimum_temperature":-5,"current_capacity":5,"minimum_capacity":1,"maximum_capacity":1,"warehouse_id":1,"product_type_id":1}`
 	req, rw := createRequestTest(http.MethodPost, "/sections", body)
 	s.ServeHTTP(rw, req)
 
@@ -69,7 +71,7 @@ func TestSectionCreateOk(t *testing.T) {
 		SectionNumber:      1,
 		CurrentTemperature: -1,
 		MinimumTemperature: -5,
-		CurrentCapacity:    1,
+		CurrentCapacity:    0,
 		MinimumCapacity:    1,
 		MaximumCapacity:    1,
 		WarehouseID:        1,
@@ -95,16 +97,26 @@ func TestSectionCreateFail(t *testing.T) {
 // TestSectionCreateConflict tests if the handler returns the correct error when a section with the given section number already exists
 func TestSectionCreateConflict(t *testing.T) {
 	sectionService.MockError = section.ErrAlreadyExists
-	body := `{"section_number":1,"current_temperature":-1,"minimum_temperature":-5,"current_capacity":1,"minimum_capacity":1,"maximum_capacity":1,"warehouse_id":1,"product_type_id":1}`
+	body := `{"section_number":1,"current_temperature":-1,"minimum_temperature":-5,"minimum_capacity":1,"maximum_capacity":1,"warehouse_id":1,"product_type_id":1}`
 	req, rw := createRequestTest(http.MethodPost, "/sections", body)
 	s.ServeHTTP(rw, req)
 
 	assert.Equal(t, 409, rw.Code)
 }
 
+// TestSectionCreateNoTemperatureRange tests if the handler rejects a section without temperatures whose product type has no range
+func TestSectionCreateNoTemperatureRange(t *testing.T) {
+	sectionService.MockError = section.ErrNoTemperatureRange
+	body := `{"section_number":1,"minimum_capacity":1,"maximum_capacity":1,"warehouse_id":1,"product_type_id":1}`
+	req, rw := createRequestTest(http.MethodPost, "/sections", body)
+	s.ServeHTTP(rw, req)
+
+	assert.Equal(t, 422, rw.Code)
+}
+
 func TestSectionCreateInternalErr(t *testing.T) {
 	sectionService.MockError = section.ErrInternal
-	body := `{"section_number":1,"current_temperature":-1,"minimum_temperature":-5,"current_capacity":1,"minimum_capacity":1,"maximum_capacity":1,"warehouse_id":1,"product_type_id":1}`
+	body := `{"section_number":1,"current_temperature":-1,"minimum_temperature":-5,"minimum_capacity":1,"maximum_capacity":1,"warehouse_id":1,"product_type_id":1}`
 	req, rw := createRequestTest(http.MethodPost, "/sections", body)
 	s.ServeHTTP(rw, req)
 
@@ -297,7 +309,7 @@ func TestSectionUpdateOk(t *testing.T) {
 	assert.Nil(t, err1)
 	assert.Equal(t, expected1, objRes1.Data)
 
-	body := `{"section_number":2,"current_temperature":1,"minimum_temperature":-10,"current_capacity":2,"minimum_capacity":2,"maximum_capacity":2,"warehouse_id":2,"product_type_id":2}`
+	body := `{"section_number":2,"current_temperature":1,"minimum_temperature":-10,"minimum_capacity":2,"maximum_capacity":2,"warehouse_id":2,"product_type_id":2}`
 	req2, rw2 := createRequestTest(http.MethodPatch, "/sections/1", body)
 	s.ServeHTTP(rw2, req2)
 
@@ -306,7 +318,7 @@ func TestSectionUpdateOk(t *testing.T) {
 		SectionNumber:      2,
 		CurrentTemperature: 1,
 		MinimumTemperature: -10,
-		CurrentCapacity:    2,
+		CurrentCapacity:    1,
 		MinimumCapacity:    2,
 		MaximumCapacity:    2,
 		WarehouseID:        2,
@@ -320,6 +332,21 @@ func TestSectionUpdateOk(t *testing.T) {
 	assert.Equal(t, expected2, objRes2.Data)
 }
 
+// TestSectionUpdateCurrentCapacity tests if the handler rejects a current capacity, it is kept by the product batches of the section
+func TestSectionUpdateCurrentCapacity(t *testing.T) {
+	sectionService = section.MockService{
+		MockSections: []domain.Section{{ID: 1, SectionNumber: 1, CurrentCapacity: 1, MaximumCapacity: 10}},
+	}
+	req, rw := createRequestTest(http.MethodPatch, "/sections/1", `{"current_capacity":5}`)
+	s.ServeHTTP(rw, req)
+
+	var objRes responseErrorSection
+	assert.Equal(t, 400, rw.Code)
+	assert.Nil(t, json.Unmarshal(rw.Body.Bytes(), &objRes))
+	assert.Equal(t, section.ErrCapacityReadOnly.Error(), objRes.Message)
+	assert.Equal(t, 1, sectionService.MockSections[0].CurrentCapacity)
+}
+
 // TestSectionUpdateInvalidId tests if the handler returns the correct error when the given id isn´t a valid decimal number
 func TestSectionUpdateInvalidId(t *testing.T) {
 	req, rw := createRequestTest(http.MethodPatch, "/sections/a", "")
@@ -352,6 +379,18 @@ func TestSectionUpdateExistentSectionNumber(t *testing.T) {
 	assert.Equal(t, expected, objRes.Message)
 }
 
+// TestSectionUpdateStoredProductType tests if the handler returns a conflict when the section stores batches of another product type
+func TestSectionUpdateStoredProductType(t *testing.T) {
+	sectionService.MockError = section.ErrStoredProductType
+	req, rw := createRequestTest(http.MethodPatch, "/sections/1", `{"product_type_id":2}`)
+	s.ServeHTTP(rw, req)
+
+	var objRes responseErrorSection
+	assert.Equal(t, 409, rw.Code)
+	assert.Nil(t, json.Unmarshal(rw.Body.Bytes(), &objRes))
+	assert.Equal(t, section.ErrStoredProductType.Error(), objRes.Message)
+}
+
 // TestSectionUpdateNonExistent tests if the handler returns the correct error when a section with the given id doesn´t exist
 func TestSectionUpdateNonExistent(t *testing.T) {
 	sectionService.MockError = section.ErrNotFound
@@ -450,6 +489,98 @@ func TestSectionDeleteInvalidId(t *testing.T) {
 	assert.Equal(t, 400, rw.Code)
 }
 
+// TestSectionDeleteReassign tests if the handler passes the strategy and the target section to the sectionService
+func TestSectionDeleteReassign(t *testing.T) {
+	sectionService = section.MockService{
+		MockSections: []domain.Section{{ID: 1, SectionNumber: 1, WarehouseID: 1, ProductTypeID: 1}},
+	}
+	req, rw := createRequestTest(http.MethodDelete, "/sections/1?strategy=reassign&to=2", "")
+	s.ServeHTTP(rw, req)
+
+	assert.Equal(t, 204, rw.Code)
+	assert.Equal(t, domain.DeleteOptions{Strategy: domain.DeleteReassign, To: 2}, sectionService.MockDeleteOptions)
+}
+
+// TestSectionDeleteErrors tests the status returned for every error of a delete with a strategy
+func TestSectionDeleteErrors(t *testing.T) {
+	cases := map[error]int{
+		section.ErrInvalidStrategy:   400,
+		section.ErrHasDependents:     409,
+		section.ErrHasHistory:        409,
+		section.ErrTargetProductType: 409,
+		section.ErrCapacityExceeded:  409,
+		section.ErrTargetNotFound:    422,
+	}
+	for mockError, code := range cases {
+		sectionService = section.MockService{MockError: mockError}
+		req, rw := createRequestTest(http.MethodDelete, "/sections/1?strategy=reassign&to=2", "")
+		s.ServeHTTP(rw, req)
+
+		var objRes responseErrorSection
+		assert.Equal(t, code, rw.Code)
+		assert.Nil(t, json.Unmarshal(rw.Body.Bytes(), &objRes))
+		assert.Equal(t, mockError.Error(), objRes.Message)
+	}
+}
+
+// TestSectionDeleteReassignTemperature tests if the handler lists the batches that do not fit the target section,
+// as a conflict when its warehouse rejects them and as the response when they were moved
+func TestSectionDeleteReassignTemperature(t *testing.T) {
+	affected := []domain.BatchTemperature{{ProductBatchID: 4, BatchNumber: 40, ProductID: 2, SectionID: 2, RecommendedFreezingTemperature: -18, SectionCurrentTemperature: 20, SectionMinimumTemperature: 15}}
+	sectionService = section.MockService{MockError: section.ErrTemperature, MockAffectedBatches: affected}
+	req, rw := createRequestTest(http.MethodDelete, "/sections/1?strategy=reassign&to=2", "")
+	s.ServeHTTP(rw, req)
+
+	var objRes responseErrorSection
+	assert.Equal(t, 409, rw.Code)
+	assert.Nil(t, json.Unmarshal(rw.Body.Bytes(), &objRes))
+	assert.Equal(t, section.ErrTemperature.Error()+", affected batch numbers: 40", objRes.Message)
+
+	sectionService = section.MockService{MockSections: []domain.Section{{ID: 1, SectionNumber: 1, WarehouseID: 1, ProductTypeID: 1}}, MockAffectedBatches: affected}
+	req, rw = createRequestTest(http.MethodDelete, "/sections/1?strategy=reassign&to=2", "")
+	s.ServeHTTP(rw, req)
+
+	var moved struct {
+		Data []domain.BatchTemperature
+	}
+	assert.Equal(t, 200, rw.Code)
+	assert.Nil(t, json.Unmarshal(rw.Body.Bytes(), &moved))
+	assert.Equal(t, affected, moved.Data)
+}
+
+// TestSectionDeleteInvalidTo tests if the handler rejects a target section that isn´t a valid decimal number
+func TestSectionDeleteInvalidTo(t *testing.T) {
+	sectionService = section.MockService{}
+	req, rw := createRequestTest(http.MethodDelete, "/sections/1?strategy=reassign&to=a", "")
+	s.ServeHTTP(rw, req)
+
+	assert.Equal(t, 400, rw.Code)
+}
+
+// TestSectionDeletePreview tests if the handler returns the delete preview of the section
+func TestSectionDeletePreview(t *testing.T) {
+	preview := domain.NewDeletePreview(1, section.DeleteStrategies, map[string][]int{"product_batches": {3}, "temperature_excursions": nil, "section_temperatures": {5, 6}})
+	sectionService = section.MockService{MockPreview: preview}
+	req, rw := createRequestTest(http.MethodGet, "/sections/1/deletePreview", "")
+	s.ServeHTTP(rw, req)
+
+	var objRes struct {
+		Data domain.DeletePreview
+	}
+	assert.Equal(t, 200, rw.Code)
+	assert.Nil(t, json.Unmarshal(rw.Body.Bytes(), &objRes))
+	assert.Equal(t, preview, objRes.Data)
+}
+
+// TestSectionDeletePreviewNonExistent tests if the handler returns the correct error when a section with the given id doesn´t exist
+func TestSectionDeletePreviewNonExistent(t *testing.T) {
+	sectionService = section.MockService{MockError: section.ErrNotFound}
+	req, rw := createRequestTest(http.MethodGet, "/sections/1/deletePreview", "")
+	s.ServeHTTP(rw, req)
+
+	assert.Equal(t, 404, rw.Code)
+}
+
 func TestSectionGetAllSectionProducts(t *testing.T) {
 	sectionService = section.MockService{
 		MockProductsBySection: []domain.ProductsBySection{
@@ -178,14 +178,19 @@ func (s *Seller) Update() gin.HandlerFunc {
 }
 
 // Delete seller
-// @Summary Delete seller
-// @Tags    Sellers
-// @Param   id path int true "seller id"
-// @Success 204
-// @Failure 400 {object} web.errorResponse "BadRequest"
-// @Failure 404 {object} web.errorResponse "Not found"
-// @Failure 500 {object} web.errorResponse "Internal server error"
-// @Router  /api/v1/sellers/{id}   [DELETE]
+// @Summary     Delete seller
+// @Tags        Sellers
+// @Description block fails while the seller has products, reassign moves them to the seller to and detach leaves them without seller
+// @Param       id       path  int    true  "seller id"
+// @Param       strategy query string false "block (default), reassign or detach"
+// @Param       to       query int    false "seller that receives the products on reassign"
+// @Success     204
+// @Failure     400 {object} web.errorResponse "BadRequest"
+// @Failure     404 {object} web.errorResponse "Not found"
+// @Failure     409 {object} web.errorResponse "The seller has products"
+// @Failure     422 {object} web.errorResponse "The seller to reassign to does not exist"
+// @Failure     500 {object} web.errorResponse "Internal server error"
+// @Router      /api/v1/sellers/{id}   [DELETE]
 func (s *Seller) Delete() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		sellerId, err := strconv.ParseInt(c.Param("id"), 10, 64)
@@ -195,12 +200,25 @@ func (s *Seller) Delete() gin.HandlerFunc {
 			return
 		}
 
-		err = s.sellerService.Delete(c, int(sellerId))
+		options, err := deleteOptions(c)
+		if err != nil {
+			logging.Log(err)
+			web.Error(c, http.StatusBadRequest, seller.ServiceErrInvalidStrategy.Error())
+			return
+		}
+
+		err = s.sellerService.Delete(c, int(sellerId), options)
 		if err != nil {
 			logging.Log(err)
 			switch err {
 			case seller.ErrNotFound:
 				web.Error(c, http.StatusNotFound, "Id %d does not exist", sellerId)
+			case seller.ServiceErrInvalidStrategy:
+				web.Error(c, http.StatusBadRequest, err.Error())
+			case seller.ErrHasProducts:
+				web.Error(c, http.StatusConflict, err.Error())
+			case seller.ErrTargetNotFound:
+				web.Error(c, http.StatusUnprocessableEntity, err.Error())
 			default:
 				web.Error(c, http.StatusInternalServerError, err.Error())
 			}
@@ -210,3 +228,125 @@ func (s *Seller) Delete() gin.HandlerFunc {
 		web.Success(c, http.StatusNoContent, "")
 	}
 }
+
+// GetProducts godoc
+// @Summary     Products of a seller
+// @Tags        Sellers
+// @Description get the products the seller sells
+// @Produce     json
+// @Param       id  path     int               true "seller id"
+// @Success     200 {object} web.response      "Products of the seller"
+// @Failure     400 {object} web.errorResponse "BadRequest"
+// @Failure     404 {object} web.errorResponse "Not found"
+// @Failure     500 {object} web.errorResponse "Internal server error"
+// @Router      /api/v1/sellers/{id}/products [get]
+func (s *Seller) GetProducts() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sellerId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			logging.Log(err)
+			web.Error(c, http.StatusBadRequest, "Invalid ID")
+			return
+		}
+
+		products, err := s.sellerService.GetProducts(c, int(sellerId))
+		if err != nil {
+			logging.Log(err)
+			switch err {
+			case seller.ErrNotFound:
+				web.Error(c, http.StatusNotFound, "Id %d does not exist", sellerId)
+			default:
+				web.Error(c, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+
+		web.Success(c, http.StatusOK, products)
+	}
+}
+
+// GetReportPerformance godoc
+// @Summary     Performance of the sellers
+// @Tags        Sellers
+// @Description get per seller the products count, the stock on hand of their batches that can still be picked and the units sold
+// @Description and revenue of the purchase orders of the period that were not cancelled, with or without order items
+// @Produce     json
+// @Param       from query    string            false "Start of the period, 2006-01-02"
+// @Param       to   query    string            false "End of the period, 2006-01-02"
+// @Success     200  {object} web.response      "Performance of the sellers"
+// @Failure     400  {object} web.errorResponse "Invalid dates"
+// @Failure     500  {object} web.errorResponse "Internal server error"
+// @Router      /api/v1/sellers/reportPerformance [get]
+func (s *Seller) GetReportPerformance() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		from, errFrom := queryDate(c, "from")
+		to, errTo := queryDate(c, "to")
+		if errFrom != nil || errTo != nil {
+			web.Error(c, http.StatusBadRequest, "from and to must be dates like 2006-01-02")
+			return
+		}
+
+		performances, err := s.sellerService.GetPerformance(c, from, to)
+		if err != nil {
+			logging.Log(err)
+			switch err {
+			case seller.ServiceErrDateRange:
+				web.Error(c, http.StatusBadRequest, err.Error())
+			default:
+				web.Error(c, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+
+		web.Success(c, http.StatusOK, performances)
+	}
+}
+
+// DeletePreview godoc
+// @Summary     Preview the delete of a seller
+// @Tags        Sellers
+// @Description get the products a delete of the seller affects and the strategies it can be deleted with
+// @Produce     json
+// @Param       id  path     int               true "seller id"
+// @Success     200 {object} web.response      "Products of the seller"
+// @Failure     400 {object} web.errorResponse "BadRequest"
+// @Failure     404 {object} web.errorResponse "Not found"
+// @Failure     500 {object} web.errorResponse "Internal server error"
+// @Router      /api/v1/sellers/{id}/deletePreview [get]
+func (s *Seller) DeletePreview() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sellerId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			logging.Log(err)
+			web.Error(c, http.StatusBadRequest, "Invalid ID")
+			return
+		}
+
+		preview, err := s.sellerService.DeletePreview(c, int(sellerId))
+		if err != nil {
+			logging.Log(err)
+			switch err {
+			case seller.ErrNotFound:
+				web.Error(c, http.StatusNotFound, "Id %d does not exist", sellerId)
+			default:
+				web.Error(c, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+
+		web.Success(c, http.StatusOK, preview)
+	}
+}
+
+// deleteOptions reads the strategy and to query values of a delete, the strategy is block when it is not given
+func deleteOptions(c *gin.Context) (domain.DeleteOptions, error) {
+	options := domain.DeleteOptions{Strategy: c.DefaultQuery("strategy", domain.DeleteBlock)}
+	if to := c.Query("to"); to != "" {
+		id, err := strconv.Atoi(to)
+		if err != nil {
+			return domain.DeleteOptions{}, err
+		}
+		options.To = id
+	}
+	return options, nil
+}
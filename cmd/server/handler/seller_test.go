@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/cmd/server/handler/requests"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
@@ -29,6 +30,13 @@ type MockServiceSeller struct {
 	ErrorCreate error
 	ErrorUpdate error
 	ErrorDelete error
+	Products    []domain.Product
+	Performance []domain.SellerPerformance
+	ErrorReport error
+	LastFrom    *time.Time
+	LastTo      *time.Time
+	Preview     domain.DeletePreview
+	Options     domain.DeleteOptions
 }
 
 // *---------------------- Mock service functions -----------------*
@@ -59,7 +67,8 @@ func (s *MockServiceSeller) Create(ctx context.Context, seller domain.Seller) (s
 	return
 }
 
-func (s *MockServiceSeller) Delete(ctx context.Context, id int) (err error) {
+func (s *MockServiceSeller) Delete(ctx context.Context, id int, options domain.DeleteOptions) (err error) {
+	s.Options = options
 	if s.ErrorDelete != nil {
 		err = s.ErrorDelete
 		return
@@ -76,6 +85,34 @@ func (s *MockServiceSeller) Update(ctx context.Context, id int, cid *int, compan
 	return
 }
 
+func (s *MockServiceSeller) GetProducts(ctx context.Context, id int) (products []domain.Product, err error) {
+	if s.ErrorGet != nil {
+		err = s.ErrorGet
+		return
+	}
+	products = s.Products
+	return
+}
+
+func (s *MockServiceSeller) GetPerformance(ctx context.Context, from *time.Time, to *time.Time) (performances []domain.SellerPerformance, err error) {
+	s.LastFrom, s.LastTo = from, to
+	if s.ErrorReport != nil {
+		err = s.ErrorReport
+		return
+	}
+	performances = s.Performance
+	return
+}
+
+func (s *MockServiceSeller) DeletePreview(ctx context.Context, id int) (preview domain.DeletePreview, err error) {
+	if s.ErrorGet != nil {
+		err = s.ErrorGet
+		return
+	}
+	preview = s.Preview
+	return
+}
+
 // *---------------------- Others functions -----------------*
 func createServerSeller() (ctx *gin.Context, recorder *httptest.ResponseRecorder) {
 	logging.InitLog(nil)
@@ -852,3 +889,187 @@ func TestDeleteInternalServerError_Seller(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, rr.Code)
 	assert.EqualError(t, expectedError, body.Message)
 }
+
+// *--------------------------- GetProducts ----------------------*
+// TestGetProducts_Seller passes when return the products of the seller (status code 200)
+func TestGetProducts_Seller(t *testing.T) {
+	// Arrange
+	sellerID := 1
+	expectedProducts := []domain.Product{{ID: 3, Description: "milk", ProductCode: "P3", ProductTypeID: 2, SellerID: &sellerID}}
+	ctx, rr := createServerSeller()
+	ctx.AddParam("id", "1")
+
+	service := MockServiceSeller{Products: expectedProducts}
+	handler := NewSeller(&service)
+
+	// Act
+	handler.GetProducts()(ctx)
+
+	/* Parse response body */
+	var body struct {
+		Data []domain.Product `json:"data"`
+	}
+	err := json.Unmarshal(rr.Body.Bytes(), &body)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, expectedProducts, body.Data)
+}
+
+// TestGetProductsIdNotFound_Seller passes when the seller does not exist (status code 404)
+func TestGetProductsIdNotFound_Seller(t *testing.T) {
+	// Arrange
+	ctx, rr := createServerSeller()
+	ctx.AddParam("id", "7")
+
+	service := MockServiceSeller{ErrorGet: seller.ErrNotFound}
+	handler := NewSeller(&service)
+
+	// Act
+	handler.GetProducts()(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+// *--------------------------- GetReportPerformance ----------------------*
+// TestGetReportPerformance_Seller passes when return the performance of the sellers in the period (status code 200)
+func TestGetReportPerformance_Seller(t *testing.T) {
+	// Arrange
+	expected := []domain.SellerPerformance{{SellerID: 1, CompanyName: "Kiosco 1", ProductsCount: 2, StockOnHand: 40, UnitsSold: 5, Revenue: 52.5}}
+	ctx, rr := createServerSeller()
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/api/v1/sellers/reportPerformance?from=2022-01-01&to=2022-01-31", nil)
+
+	service := MockServiceSeller{Performance: expected}
+	handler := NewSeller(&service)
+
+	// Act
+	handler.GetReportPerformance()(ctx)
+
+	/* Parse response body */
+	var body struct {
+		Data []domain.SellerPerformance `json:"data"`
+	}
+	err := json.Unmarshal(rr.Body.Bytes(), &body)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, expected, body.Data)
+	assert.Equal(t, "2022-01-01", service.LastFrom.Format(domain.ISO8601))
+	assert.Equal(t, "2022-01-31", service.LastTo.Format(domain.ISO8601))
+}
+
+// TestGetReportPerformanceInvalidDate_Seller passes when a date can not be parsed (status code 400)
+func TestGetReportPerformanceInvalidDate_Seller(t *testing.T) {
+	// Arrange
+	ctx, rr := createServerSeller()
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/api/v1/sellers/reportPerformance?from=01/01/2022", nil)
+
+	service := MockServiceSeller{}
+	handler := NewSeller(&service)
+
+	// Act
+	handler.GetReportPerformance()(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Nil(t, service.LastFrom)
+}
+
+// TestGetReportPerformanceDateRange_Seller passes when from is after to (status code 400)
+func TestGetReportPerformanceDateRange_Seller(t *testing.T) {
+	// Arrange
+	ctx, rr := createServerSeller()
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/api/v1/sellers/reportPerformance?from=2022-02-01&to=2022-01-01", nil)
+
+	service := MockServiceSeller{ErrorReport: seller.ServiceErrDateRange}
+	handler := NewSeller(&service)
+
+	// Act
+	handler.GetReportPerformance()(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+// TestDeleteReassign_Seller passes when the strategy and target are read from the query (status code 204)
+func TestDeleteReassign_Seller(t *testing.T) {
+	// Arrange
+	ctx, rr := createServerSeller()
+	ctx.AddParam("id", "1")
+	ctx.Request = httptest.NewRequest(http.MethodDelete, "/api/v1/sellers/1?strategy=reassign&to=2", nil)
+
+	service := MockServiceSeller{}
+	handler := NewSeller(&service)
+
+	// Act
+	handler.Delete()(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, domain.DeleteOptions{Strategy: domain.DeleteReassign, To: 2}, service.Options)
+}
+
+// TestDeleteDefaultStrategy_Seller passes when a delete without strategy blocks on products (status code 409)
+func TestDeleteDefaultStrategy_Seller(t *testing.T) {
+	// Arrange
+	ctx, rr := createServerSeller()
+	ctx.AddParam("id", "1")
+	ctx.Request = httptest.NewRequest(http.MethodDelete, "/api/v1/sellers/1", nil)
+
+	service := MockServiceSeller{ErrorDelete: seller.ErrHasProducts}
+	handler := NewSeller(&service)
+
+	// Act
+	handler.Delete()(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, domain.DeleteOptions{Strategy: domain.DeleteBlock}, service.Options)
+}
+
+// TestDeleteInvalidTarget_Seller passes when to is not a number (status code 400)
+func TestDeleteInvalidTarget_Seller(t *testing.T) {
+	// Arrange
+	ctx, rr := createServerSeller()
+	ctx.AddParam("id", "1")
+	ctx.Request = httptest.NewRequest(http.MethodDelete, "/api/v1/sellers/1?strategy=reassign&to=two", nil)
+
+	service := MockServiceSeller{}
+	handler := NewSeller(&service)
+
+	// Act
+	handler.Delete()(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Empty(t, service.Options)
+}
+
+// *--------------------------- DeletePreview ----------------------*
+// TestDeletePreview_Seller passes when return the products a delete affects (status code 200)
+func TestDeletePreview_Seller(t *testing.T) {
+	// Arrange
+	expected := domain.DeletePreview{ID: 1, Strategies: seller.DeleteStrategies, Blocked: true, Dependents: map[string][]int{"products": {3, 5}}}
+	ctx, rr := createServerSeller()
+	ctx.AddParam("id", "1")
+
+	service := MockServiceSeller{Preview: expected}
+	handler := NewSeller(&service)
+
+	// Act
+	handler.DeletePreview()(ctx)
+
+	/* Parse response body */
+	var body struct {
+		Data domain.DeletePreview `json:"data"`
+	}
+	err := json.Unmarshal(rr.Body.Bytes(), &body)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, expected, body.Data)
+}
@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/cmd/server/handler/requests"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	purchaseorders "github.com/extmatperez/meli_bootcamp_go_w6-2/internal/purchase_orders"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/shipment"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type Shipment struct {
+	service shipment.Service
+}
+
+func NewShipment(s shipment.Service) *Shipment {
+	return &Shipment{
+		service: s,
+	}
+}
+
+// Create CreateShipment godoc
+// @Summary     Create shipment
+// @Tags        Shipments
+// @Description assign picking purchase orders to a carry. Every order gets the tracking code of the shipment,
+// @Description which is generated when it is not given.
+// @Accept      json
+// @Produce     json
+// @Param       id       path     int                          true "carry id"
+// @Param       shipment body     requests.RequestShipmentPost true "orders of the shipment"
+// @Success     201      {object} web.response
+// @Failure     400      {object} web.errorResponse
+// @Failure     404      {object} web.errorResponse
+// @Failure     409      {object} web.errorResponse
+// @Failure     422      {object} web.errorResponse
+// @Failure     500      {object} web.errorResponse
+// @Router      /api/v1/carries/{id}/shipments [post]
+func (h *Shipment) Create() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		carryID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			logging.Log("invalid Id")
+			web.Error(c, http.StatusBadRequest, "invalid Id")
+			return
+		}
+
+		var req requests.RequestShipmentPost
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logging.Log(err)
+			web.Error(c, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+
+		created, err := h.service.Save(c, carryID, req.PurchaseOrderIds, req.TrackingCode)
+		if err != nil {
+			writeShipmentError(c, err)
+			return
+		}
+		web.Success(c, http.StatusCreated, created)
+	}
+}
+
+// GetByCarry List carry shipments godoc
+// @Summary     List carry shipments
+// @Tags        Shipments
+// @Description get every shipment of a carry with its orders, tracking code and timestamps
+// @Produce     json
+// @Param       id  path     int true "carry id"
+// @Success     200 {object} web.response
+// @Failure     400 {object} web.errorResponse
+// @Failure     404 {object} web.errorResponse
+// @Failure     500 {object} web.errorResponse
+// @Router      /api/v1/carries/{id}/shipments [get]
+func (h *Shipment) GetByCarry() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		carryID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			logging.Log("invalid Id")
+			web.Error(c, http.StatusBadRequest, "invalid Id")
+			return
+		}
+
+		shipments, err := h.service.GetByCarry(c, carryID)
+		if err != nil {
+			writeShipmentError(c, err)
+			return
+		}
+		web.Success(c, http.StatusOK, shipments)
+	}
+}
+
+// Get GetShipment godoc
+// @Summary     Get shipment
+// @Tags        Shipments
+// @Description get a shipment with its orders, tracking code and timestamps
+// @Produce     json
+// @Param       id  path     int true "shipment id"
+// @Success     200 {object} web.response
+// @Failure     400 {object} web.errorResponse
+// @Failure     404 {object} web.errorResponse
+// @Failure     500 {object} web.errorResponse
+// @Router      /api/v1/shipments/{id} [get]
+func (h *Shipment) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			logging.Log("invalid Id")
+			web.Error(c, http.StatusBadRequest, "invalid Id")
+			return
+		}
+
+		s, err := h.service.Get(c, id)
+		if err != nil {
+			writeShipmentError(c, err)
+			return
+		}
+		web.Success(c, http.StatusOK, s)
+	}
+}
+
+// Dispatch DispatchShipment godoc
+// @Summary     Dispatch shipment
+// @Tags        Shipments
+// @Description record that the shipment left and move every order to shipped, the cancelled orders are left out
+// @Accept      json
+// @Produce     json
+// @Param       id    path     int                           true "shipment id"
+// @Param       event body     requests.RequestShipmentEvent true "who dispatched the shipment"
+// @Success     200   {object} web.response
+// @Failure     400   {object} web.errorResponse
+// @Failure     404   {object} web.errorResponse
+// @Failure     409   {object} web.errorResponse
+// @Failure     422   {object} web.errorResponse
+// @Failure     500   {object} web.errorResponse
+// @Router      /api/v1/shipments/{id}/dispatch [post]
+func (h *Shipment) Dispatch() gin.HandlerFunc {
+	return h.event(h.service.Dispatch)
+}
+
+// Deliver DeliverShipment godoc
+// @Summary     Deliver shipment
+// @Tags        Shipments
+// @Description record that a dispatched shipment arrived and move every order to delivered, the cancelled orders are left out
+// @Accept      json
+// @Produce     json
+// @Param       id    path     int                           true "shipment id"
+// @Param       event body     requests.RequestShipmentEvent true "who delivered the shipment"
+// @Success     200   {object} web.response
+// @Failure     400   {object} web.errorResponse
+// @Failure     404   {object} web.errorResponse
+// @Failure     409   {object} web.errorResponse
+// @Failure     422   {object} web.errorResponse
+// @Failure     500   {object} web.errorResponse
+// @Router      /api/v1/shipments/{id}/deliver [post]
+func (h *Shipment) Deliver() gin.HandlerFunc {
+	return h.event(h.service.Deliver)
+}
+
+func (h *Shipment) event(apply func(ctx context.Context, id int, actor string) (domain.Shipment, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			logging.Log("invalid Id")
+			web.Error(c, http.StatusBadRequest, "invalid Id")
+			return
+		}
+
+		var req requests.RequestShipmentEvent
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logging.Log(err)
+			web.Error(c, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+
+		s, err := apply(c, id, req.Actor)
+		if err != nil {
+			writeShipmentError(c, err)
+			return
+		}
+		web.Success(c, http.StatusOK, s)
+	}
+}
+
+// writeShipmentError answers with the status of a shipment error, the errors of the purchase orders
+// come from the status changes of the orders of the shipment
+func writeShipmentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, shipment.ErrNotFound), errors.Is(err, shipment.ErrCarryNotFound),
+		errors.Is(err, shipment.ErrOrderNotFound), errors.Is(err, purchaseorders.ErrNotFound):
+		web.Error(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, shipment.ErrOrderNotAssignable), errors.Is(err, shipment.ErrOrderAlreadyAssigned),
+		errors.Is(err, shipment.ErrTrackingCodeExists), errors.Is(err, shipment.ErrInvalidState),
+		errors.Is(err, shipment.ErrOrderNotReady), errors.Is(err, shipment.ErrAlreadyDispatched),
+		errors.Is(err, shipment.ErrNotDispatched), errors.Is(err, shipment.ErrAlreadyDelivered),
+		errors.Is(err, purchaseorders.ErrInvalidTransition), errors.Is(err, purchaseorders.ErrStatusChanged),
+		errors.Is(err, purchaseorders.ErrInsufficientStock):
+		web.Error(c, http.StatusConflict, err.Error())
+	case errors.Is(err, shipment.ErrInvalidOrders):
+		web.Error(c, http.StatusUnprocessableEntity, err.Error())
+	default:
+		web.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	purchaseorders "github.com/extmatperez/meli_bootcamp_go_w6-2/internal/purchase_orders"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/shipment"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func createServerShipments(repo *shipment.MockRepository, orders *purchaseorders.MockRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	ordersService := purchaseorders.NewService(orders, purchaseorders.DefaultReservationTTL)
+	handler := NewShipment(shipment.NewService(repo, ordersService))
+
+	r := gin.Default()
+	r.GET("/api/v1/carries/:id/shipments", handler.GetByCarry())
+	r.POST("/api/v1/carries/:id/shipments", handler.Create())
+	sh := r.Group("/api/v1/shipments")
+	sh.GET("/:id", handler.Get())
+	sh.POST("/:id/dispatch", handler.Dispatch())
+	sh.POST("/:id/deliver", handler.Deliver())
+	return r
+}
+
+// TestCreateShipmentSuccess passes when the orders are assigned to the carry (status code 201)
+func TestCreateShipmentSuccess(t *testing.T) {
+	repo := shipment.MockRepository{}
+	r := createServerShipments(&repo, &purchaseorders.MockRepository{})
+	req, recorder := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/carries/3/shipments", `{"purchase_order_ids":[10, 11], "tracking_code":"TRK-1"}`)
+	r.ServeHTTP(recorder, req)
+
+	var body struct {
+		Data domain.Shipment `json:"data"`
+	}
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Equal(t, "TRK-1", body.Data.TrackingCode)
+	assert.Equal(t, []int{10, 11}, body.Data.PurchaseOrderIDs)
+	assert.Equal(t, 3, repo.LastShipment.CarryID)
+}
+
+// TestCreateShipmentOrderAssigned passes when an order already belongs to another shipment (status code 409)
+func TestCreateShipmentOrderAssigned(t *testing.T) {
+	repo := shipment.MockRepository{ErrSave: shipment.ErrOrderAlreadyAssigned}
+	r := createServerShipments(&repo, &purchaseorders.MockRepository{})
+	req, recorder := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/carries/3/shipments", `{"purchase_order_ids":[10]}`)
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+}
+
+// TestCreateShipmentWithoutOrders passes when the body has no orders (status code 422)
+func TestCreateShipmentWithoutOrders(t *testing.T) {
+	r := createServerShipments(&shipment.MockRepository{}, &purchaseorders.MockRepository{})
+	req, recorder := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/carries/3/shipments", `{"purchase_order_ids":[]}`)
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+}
+
+// TestGetCarryShipmentsNotFound passes when the carry does not exist (status code 404)
+func TestGetCarryShipmentsNotFound(t *testing.T) {
+	r := createServerShipments(&shipment.MockRepository{Err: shipment.ErrCarryNotFound}, &purchaseorders.MockRepository{})
+	req, recorder := createRequestTestPurchaseOrders(http.MethodGet, "/api/v1/carries/3/shipments", "")
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+// TestDispatchShipmentShipsOrder passes when the order of the shipment moves to shipped (status code 200)
+func TestDispatchShipmentShipsOrder(t *testing.T) {
+	repo := shipment.MockRepository{Data: []domain.Shipment{{ID: 1, CarryID: 3, PurchaseOrderIDs: []int{10}}}}
+	orders := purchaseorders.MockRepository{
+		Details: []domain.PurchaseOrderDetail{{ID: 10, OrderStatusId: domain.OrderStatusPicking}},
+		Status:  domain.OrderStatusPicking,
+	}
+	r := createServerShipments(&repo, &orders)
+	req, recorder := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/shipments/1/dispatch", `{"actor":"carrier"}`)
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Len(t, repo.Changes, 1)
+	assert.Equal(t, domain.OrderStatusShipped, repo.Changes[0].ToStatusId)
+	assert.Equal(t, "carrier", repo.Changes[0].Actor)
+	assert.False(t, repo.DispatchedAt.IsZero())
+}
+
+// TestDispatchShipmentOrderNotReady passes when the order is still confirmed (status code 409)
+func TestDispatchShipmentOrderNotReady(t *testing.T) {
+	repo := shipment.MockRepository{Data: []domain.Shipment{{ID: 1, CarryID: 3, PurchaseOrderIDs: []int{10}}}}
+	orders := purchaseorders.MockRepository{
+		Details: []domain.PurchaseOrderDetail{{ID: 10, OrderStatusId: domain.OrderStatusConfirmed}},
+		Status:  domain.OrderStatusConfirmed,
+	}
+	r := createServerShipments(&repo, &orders)
+	req, recorder := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/shipments/1/dispatch", `{"actor":"carrier"}`)
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Empty(t, orders.History)
+}
+
+// TestDeliverShipmentNotDispatched passes when the shipment has not left (status code 409)
+func TestDeliverShipmentNotDispatched(t *testing.T) {
+	repo := shipment.MockRepository{Data: []domain.Shipment{{ID: 1, CarryID: 3, PurchaseOrderIDs: []int{10}}}}
+	r := createServerShipments(&repo, &purchaseorders.MockRepository{})
+	req, recorder := createRequestTestPurchaseOrders(http.MethodPost, "/api/v1/shipments/1/deliver", `{"actor":"carrier"}`)
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.True(t, repo.DeliveredAt.Equal(time.Time{}))
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -11,6 +12,9 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// DefaultExpiringDays is used by the expiring stock report when no days are requested
+const DefaultExpiringDays = 7
+
 type Warehouse struct {
 	service warehouse.Service
 }
@@ -94,12 +98,18 @@ func (w *Warehouse) Create(ctx *gin.Context) {
 		return
 	}
 
-	warehouseCreated, err := w.service.Create(ctx, *req.Address, *req.Telephone, *req.WarehouseCode, *req.MinimumCapacity, *req.MinimumTemperature)
+	warehouseCreated, err := w.service.Create(ctx, *req.Address, *req.Telephone, *req.WarehouseCode, *req.MinimumCapacity, *req.MinimumTemperature, req.TemperaturePolicy, req.LocalityID)
 	if err != nil {
 		switch err.Error() {
 		case warehouse.ErrAlreadyExists.Error():
 			logging.Log(warehouse.ErrAlreadyExists)
 			web.Error(ctx, http.StatusConflict, err.Error())
+		case warehouse.ErrInvalidPolicy.Error():
+			logging.Log(warehouse.ErrInvalidPolicy)
+			web.Error(ctx, http.StatusUnprocessableEntity, err.Error())
+		case warehouse.ErrLocalityNotFound.Error():
+			logging.Log(warehouse.ErrLocalityNotFound)
+			web.Error(ctx, http.StatusConflict, err.Error())
 		default:
 			logging.Log(err)
 			web.Error(ctx, http.StatusInternalServerError, err.Error())
@@ -140,7 +150,7 @@ func (w *Warehouse) Update(ctx *gin.Context) {
 		return
 	}
 
-	warehouseUpdated, err := w.service.Update(ctx, id, req.Address, req.Telephone, req.WarehouseCode, req.MinimumCapacity, req.MinimumTemperature)
+	warehouseUpdated, err := w.service.Update(ctx, id, req.Address, req.Telephone, req.WarehouseCode, req.MinimumCapacity, req.MinimumTemperature, req.TemperaturePolicy, req.LocalityID)
 	if err != nil {
 		switch err {
 		case warehouse.ErrAlreadyExists:
@@ -149,6 +159,12 @@ func (w *Warehouse) Update(ctx *gin.Context) {
 		case warehouse.ErrNotFound:
 			logging.Log(warehouse.ErrNotFound)
 			web.Error(ctx, http.StatusNotFound, warehouse.ErrNotFound.Error())
+		case warehouse.ErrInvalidPolicy:
+			logging.Log(warehouse.ErrInvalidPolicy)
+			web.Error(ctx, http.StatusUnprocessableEntity, err.Error())
+		case warehouse.ErrLocalityNotFound:
+			logging.Log(warehouse.ErrLocalityNotFound)
+			web.Error(ctx, http.StatusConflict, err.Error())
 		default:
 			logging.Log(err)
 			web.Error(ctx, http.StatusInternalServerError, err.Error())
@@ -161,12 +177,17 @@ func (w *Warehouse) Update(ctx *gin.Context) {
 // Delete DeleteWarehouse godoc
 // @Summary     Delete warehouse
 // @Tags        Warehouses
-// @Description delete warehouse
+// @Description delete warehouse, block fails while sections, employees or inbound orders reference it
+// @Description and reassign moves them to the warehouse to
 // @Produce     json
-// @Param       id path int true "warehouse id"
+// @Param       id       path  int    true  "warehouse id"
+// @Param       strategy query string false "block (default) or reassign"
+// @Param       to       query int    false "warehouse that receives the dependents on reassign"
 // @Success     204
 // @Failure     400 {object} web.errorResponse
 // @Failure     404 {object} web.errorResponse
+// @Failure     409 {object} web.errorResponse
+// @Failure     422 {object} web.errorResponse
 // @Failure     500 {object} web.errorResponse
 // @Router      /api/v1/warehouses/{id} [delete]
 func (w *Warehouse) Delete(ctx *gin.Context) {
@@ -178,12 +199,28 @@ func (w *Warehouse) Delete(ctx *gin.Context) {
 		return
 	}
 
-	err = w.service.Delete(ctx, id)
+	options, err := deleteOptions(ctx)
+	if err != nil {
+		logging.Log(warehouse.ErrInvalidStrategy)
+		web.Error(ctx, http.StatusBadRequest, warehouse.ErrInvalidStrategy.Error())
+		return
+	}
+
+	err = w.service.Delete(ctx, id, options)
 	if err != nil {
 		switch err {
 		case warehouse.ErrNotFound:
 			logging.Log(warehouse.ErrNotFound)
 			web.Error(ctx, http.StatusNotFound, warehouse.ErrNotFound.Error())
+		case warehouse.ErrInvalidStrategy:
+			logging.Log(err)
+			web.Error(ctx, http.StatusBadRequest, err.Error())
+		case warehouse.ErrHasDependents:
+			logging.Log(err)
+			web.Error(ctx, http.StatusConflict, err.Error())
+		case warehouse.ErrTargetNotFound:
+			logging.Log(err)
+			web.Error(ctx, http.StatusUnprocessableEntity, err.Error())
 		default:
 			logging.Log(err)
 			web.Error(ctx, http.StatusInternalServerError, err.Error())
@@ -193,3 +230,106 @@ func (w *Warehouse) Delete(ctx *gin.Context) {
 
 	web.Success(ctx, http.StatusNoContent, "")
 }
+
+// DeletePreview DeleteWarehousePreview godoc
+// @Summary     Preview the delete of a warehouse
+// @Tags        Warehouses
+// @Description get the sections, employees and inbound orders a delete of the warehouse affects
+// @Produce     json
+// @Param       id  path     int true "warehouse id"
+// @Success     200 {object} web.response
+// @Failure     400 {object} web.errorResponse
+// @Failure     404 {object} web.errorResponse
+// @Failure     500 {object} web.errorResponse
+// @Router      /api/v1/warehouses/{id}/deletePreview [get]
+func (w *Warehouse) DeletePreview(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		logging.Log(warehouse.ErrBadRequest)
+		web.Error(ctx, http.StatusBadRequest, warehouse.ErrBadRequest.Error())
+		return
+	}
+
+	preview, err := w.service.DeletePreview(ctx, id)
+	if err != nil {
+		switch err {
+		case warehouse.ErrNotFound:
+			logging.Log(warehouse.ErrNotFound)
+			web.Error(ctx, http.StatusNotFound, warehouse.ErrNotFound.Error())
+		default:
+			logging.Log(err)
+			web.Error(ctx, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	web.Success(ctx, http.StatusOK, preview)
+}
+
+// ReportExpiring ReportExpiringStock godoc
+// @Summary     Expiring stock report
+// @Tags        Warehouses
+// @Description get the batches of the warehouse that expire within the given days (7 by default), grouped by section and product.
+// @Description use format=csv or an Accept: text/csv header to get one row per batch as CSV
+// @Produce     json
+// @Produce     text/csv
+// @Param       id     path     int    true  "warehouse id"
+// @Param       days   query    int    false "days from today"
+// @Param       format query    string false "json or csv"
+// @Success     200    {object} web.response
+// @Failure     400    {object} web.errorResponse
+// @Failure     404    {object} web.errorResponse
+// @Failure     500    {object} web.errorResponse
+// @Router      /api/v1/warehouses/{id}/reportExpiring [get]
+func (w *Warehouse) ReportExpiring(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		logging.Log(warehouse.ErrBadRequest)
+		web.Error(ctx, http.StatusBadRequest, warehouse.ErrBadRequest.Error())
+		return
+	}
+	days := DefaultExpiringDays
+	if value := ctx.Query("days"); value != "" {
+		if days, err = strconv.Atoi(value); err != nil {
+			logging.Log(warehouse.ErrInvalidDays)
+			web.Error(ctx, http.StatusBadRequest, warehouse.ErrInvalidDays.Error())
+			return
+		}
+	}
+
+	report, err := w.service.ReportExpiring(ctx, id, days)
+	if err != nil {
+		switch err {
+		case warehouse.ErrInvalidDays:
+			logging.Log(warehouse.ErrInvalidDays)
+			web.Error(ctx, http.StatusBadRequest, err.Error())
+		case warehouse.ErrNotFound:
+			logging.Log(warehouse.ErrNotFound)
+			web.Error(ctx, http.StatusNotFound, warehouse.ErrNotFound.Error())
+		default:
+			logging.Log(err)
+			web.Error(ctx, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	if web.WantsCSV(ctx) {
+		header := []string{"section_id", "section_number", "product_id", "product_code", "description", "product_batch_id", "batch_number", "due_date", "days_remaining", "current_quantity", "quarantined"}
+		var records [][]string
+		for _, section := range report {
+			for _, product := range section.Products {
+				for _, batch := range product.Batches {
+					records = append(records, []string{
+						strconv.Itoa(section.SectionID), strconv.Itoa(section.SectionNumber),
+						strconv.Itoa(product.ProductID), product.ProductCode, product.Description,
+						strconv.Itoa(batch.ProductBatchID), strconv.Itoa(batch.BatchNumber), batch.DueDate,
+						strconv.Itoa(batch.DaysRemaining), strconv.Itoa(batch.CurrentQuantity), strconv.FormatBool(batch.Quarantined),
+					})
+				}
+			}
+		}
+		web.CSV(ctx, http.StatusOK, fmt.Sprintf("expiring_warehouse_%d.csv", id), header, records)
+		return
+	}
+	web.Success(ctx, http.StatusOK, report)
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/cmd/server/handler/requests"
@@ -21,8 +22,11 @@ import (
 type MockWarehouseService struct {
 	mockWarehouse     domain.Warehouse
 	mockWarehouses    []domain.Warehouse
+	mockExpiring      []domain.ExpiringSection
 	mockErrorInternal error
 	mockErrorUpdate   error
+	mockPreview       domain.DeletePreview
+	deleteOptions     domain.DeleteOptions
 }
 
 func (s *MockWarehouseService) Get(ctx context.Context, id int) (domain.Warehouse, error) {
@@ -39,27 +43,42 @@ func (s *MockWarehouseService) GetAll(ctx context.Context) ([]domain.Warehouse,
 	return s.mockWarehouses, nil
 }
 
-func (s *MockWarehouseService) Create(ctx context.Context, address string, telephone string, warehouseCode string, minimumCapacity int, minimumTemperature int) (domain.Warehouse, error) {
+func (s *MockWarehouseService) Create(ctx context.Context, address string, telephone string, warehouseCode string, minimumCapacity int, minimumTemperature int, temperaturePolicy string, localityID string) (domain.Warehouse, error) {
 	if s.mockErrorInternal != nil {
 		return domain.Warehouse{}, s.mockErrorInternal
 	}
 	return s.mockWarehouse, nil
 }
 
-func (s *MockWarehouseService) Delete(ctx context.Context, id int) error {
+func (s *MockWarehouseService) Delete(ctx context.Context, id int, options domain.DeleteOptions) error {
+	s.deleteOptions = options
 	if s.mockErrorInternal != nil {
 		return s.mockErrorInternal
 	}
 	return nil
 }
 
-func (s *MockWarehouseService) Update(ctx context.Context, id int, address *string, telephone *string, warehouseCode *string, minimumCapacity *int, minimumTemperature *int) (domain.Warehouse, error) {
+func (s *MockWarehouseService) DeletePreview(ctx context.Context, id int) (domain.DeletePreview, error) {
+	if s.mockErrorInternal != nil {
+		return domain.DeletePreview{}, s.mockErrorInternal
+	}
+	return s.mockPreview, nil
+}
+
+func (s *MockWarehouseService) Update(ctx context.Context, id int, address *string, telephone *string, warehouseCode *string, minimumCapacity *int, minimumTemperature *int, temperaturePolicy *string, localityID *string) (domain.Warehouse, error) {
 	if s.mockErrorUpdate != nil {
 		return domain.Warehouse{}, s.mockErrorUpdate
 	}
 	return s.mockWarehouse, nil
 }
 
+func (s *MockWarehouseService) ReportExpiring(ctx context.Context, id int, days int) ([]domain.ExpiringSection, error) {
+	if s.mockErrorInternal != nil {
+		return nil, s.mockErrorInternal
+	}
+	return s.mockExpiring, nil
+}
+
 // MOCK GIN
 func mockWarehouseGin(warehouseID string, structBody interface{}) (*gin.Context, *httptest.ResponseRecorder) {
 	logging.InitLog(nil)
@@ -70,6 +89,7 @@ func mockWarehouseGin(warehouseID string, structBody interface{}) (*gin.Context,
 	body, _ := json.Marshal(&structBody)
 	req := &http.Request{
 		Body: io.NopCloser(bytes.NewBuffer(body)),
+		URL:  &url.URL{},
 	}
 	ctx.Request = req
 	return ctx, recorder
@@ -437,6 +457,43 @@ func TestWarehouseCreateFailureInternal(t *testing.T) {
 	assert.Equal(t, expectedError.Error(), responseMessage)
 }
 
+// TestWarehouseCreateFailureLocality is correct when the locality of the warehouse does not exist
+// Expected HTTP Status code: 409
+func TestWarehouseCreateFailureLocality(t *testing.T) {
+	// arrange
+	address := "Monroe 1230"
+	telephone := "47470000"
+	warehouseCode := "DHM1"
+	minimumCapacity := 10
+	minimumTemperature := 0
+	requestWarehouse := requests.WarehousePostRequest{
+		Address:            &address,
+		Telephone:          &telephone,
+		WarehouseCode:      &warehouseCode,
+		MinimumCapacity:    &minimumCapacity,
+		MinimumTemperature: &minimumTemperature,
+		LocalityID:         "9999",
+	}
+	expectedError := warehouse.ErrLocalityNotFound
+
+	mockService := MockWarehouseService{mockErrorInternal: expectedError}
+	handler := NewWarehouse(&mockService)
+
+	ctx, recorder := mockWarehouseGin("", requestWarehouse)
+
+	// act
+	handler.Create(ctx)
+
+	// parse response body
+	var body warehouseErrorResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &body)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, expectedError.Error(), body.Message)
+}
+
 // TestWarehouseUpdate checks the correct operation of the Update handler method
 // Expected HTTP Status code: 200
 func TestWarehouseUpdate(t *testing.T) {
@@ -743,3 +800,147 @@ func TestWarehouseDeleteFailureInternal(t *testing.T) {
 	assert.Equal(t, expectedStatus, response.StatusCode)
 	assert.Equal(t, expectedError.Error(), responseMessage)
 }
+
+// TestWarehouseDeleteReassign is correct when the strategy and target are read from the query
+// Expected HTTP Status code: 204
+func TestWarehouseDeleteReassign(t *testing.T) {
+	// arrange
+	mockService := MockWarehouseService{}
+	handler := NewWarehouse(&mockService)
+
+	ctx, recorder := mockWarehouseGin("1", "")
+	ctx.Request = httptest.NewRequest(http.MethodDelete, "/api/v1/warehouses/1?strategy=reassign&to=2", nil)
+
+	// act
+	handler.Delete(ctx)
+
+	// assert
+	assert.Equal(t, http.StatusNoContent, recorder.Result().StatusCode)
+	assert.Equal(t, domain.DeleteOptions{Strategy: domain.DeleteReassign, To: 2}, mockService.deleteOptions)
+}
+
+// TestWarehouseDeleteFailureDependents is correct when the block strategy finds dependents
+// Expected HTTP Status code: 409
+func TestWarehouseDeleteFailureDependents(t *testing.T) {
+	// arrange
+	mockService := MockWarehouseService{mockErrorInternal: warehouse.ErrHasDependents}
+	handler := NewWarehouse(&mockService)
+
+	ctx, recorder := mockWarehouseGin("1", "")
+
+	// act
+	handler.Delete(ctx)
+
+	// assert
+	assert.Equal(t, http.StatusConflict, recorder.Result().StatusCode)
+	assert.Equal(t, domain.DeleteOptions{Strategy: domain.DeleteBlock}, mockService.deleteOptions)
+}
+
+// TestWarehouseDeleteFailureStrategy is correct when the strategy is not supported
+// Expected HTTP Status code: 400
+func TestWarehouseDeleteFailureStrategy(t *testing.T) {
+	// arrange
+	mockService := MockWarehouseService{mockErrorInternal: warehouse.ErrInvalidStrategy}
+	handler := NewWarehouse(&mockService)
+
+	ctx, recorder := mockWarehouseGin("1", "")
+	ctx.Request = httptest.NewRequest(http.MethodDelete, "/api/v1/warehouses/1?strategy=detach", nil)
+
+	// act
+	handler.Delete(ctx)
+
+	// assert
+	assert.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
+}
+
+// TestWarehouseDeletePreview checks the correct operation of the DeletePreview handler method
+// Expected HTTP Status code: 200
+func TestWarehouseDeletePreview(t *testing.T) {
+	// arrange
+	expected := domain.DeletePreview{ID: 1, Strategies: warehouse.DeleteStrategies, Blocked: true,
+		Dependents: map[string][]int{"sections": {2}, "employees": {}, "inbound_orders": {}}}
+	mockService := MockWarehouseService{mockPreview: expected}
+	handler := NewWarehouse(&mockService)
+
+	ctx, recorder := mockWarehouseGin("1", "")
+
+	// act
+	handler.DeletePreview(ctx)
+
+	// parse response body
+	var body struct {
+		Data domain.DeletePreview `json:"data"`
+	}
+	err := json.Unmarshal(recorder.Body.Bytes(), &body)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+	assert.Equal(t, expected, body.Data)
+}
+
+func createWarehouseReportServer(mockService *MockWarehouseService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewWarehouse(mockService)
+	router := gin.New()
+	router.GET("/warehouses/:id/reportExpiring", handler.ReportExpiring)
+	return router
+}
+
+var expiringReport = []domain.ExpiringSection{
+	{SectionID: 1, SectionNumber: 10, Quantity: 15, Products: []domain.ExpiringProduct{
+		{ProductID: 2, ProductCode: "P2", Description: "milk, whole", Quantity: 15, DaysRemaining: 2, Batches: []domain.ExpiringBatch{
+			{ProductBatchID: 3, BatchNumber: 30, DueDate: "2026-05-03", DaysRemaining: 2, CurrentQuantity: 15},
+		}},
+	}},
+}
+
+// TestWarehouseReportExpiring checks the JSON output of the expiring stock report
+// Expected HTTP Status code: 200
+func TestWarehouseReportExpiring(t *testing.T) {
+	// arrange
+	router := createWarehouseReportServer(&MockWarehouseService{mockExpiring: expiringReport})
+	req, recorder := createRequestTest(http.MethodGet, "/warehouses/1/reportExpiring?days=3", "")
+	// act
+	router.ServeHTTP(recorder, req)
+	var body struct {
+		Data []domain.ExpiringSection `json:"data"`
+	}
+	err := json.Unmarshal(recorder.Body.Bytes(), &body)
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, expiringReport, body.Data)
+}
+
+// TestWarehouseReportExpiringCSV checks the CSV output of the expiring stock report
+// Expected HTTP Status code: 200
+func TestWarehouseReportExpiringCSV(t *testing.T) {
+	// arrange
+	router := createWarehouseReportServer(&MockWarehouseService{mockExpiring: expiringReport})
+	req, recorder := createRequestTest(http.MethodGet, "/warehouses/1/reportExpiring?format=csv", "")
+	expected := "section_id,section_number,product_id,product_code,description,product_batch_id,batch_number,due_date,days_remaining,current_quantity,quarantined\n" +
+		"1,10,2,P2,\"milk, whole\",3,30,2026-05-03,2,15,false\n"
+	// act
+	router.ServeHTTP(recorder, req)
+	// assert
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Header().Get("Content-Type"), "text/csv")
+	assert.Equal(t, expected, recorder.Body.String())
+}
+
+// TestWarehouseReportExpiringFailure checks the errors of the expiring stock report
+// Expected HTTP Status codes: 400 and 404
+func TestWarehouseReportExpiringFailure(t *testing.T) {
+	// arrange
+	router := createWarehouseReportServer(&MockWarehouseService{})
+	notFoundRouter := createWarehouseReportServer(&MockWarehouseService{mockErrorInternal: warehouse.ErrNotFound})
+	reqDays, recorderDays := createRequestTest(http.MethodGet, "/warehouses/1/reportExpiring?days=week", "")
+	reqNotFound, recorderNotFound := createRequestTest(http.MethodGet, "/warehouses/9/reportExpiring", "")
+	// act
+	router.ServeHTTP(recorderDays, reqDays)
+	notFoundRouter.ServeHTTP(recorderNotFound, reqNotFound)
+	// assert
+	assert.Equal(t, http.StatusBadRequest, recorderDays.Code)
+	assert.Equal(t, http.StatusNotFound, recorderNotFound.Code)
+}
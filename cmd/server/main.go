@@ -1,10 +1,21 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/cmd/server/routes"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/excursion"
+	purchaseorders "github.com/extmatperez/meli_bootcamp_go_w6-2/internal/purchase_orders"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/record/product_record"
 	"github.com/gin-gonic/gin"
 	_ "github.com/go-sql-driver/mysql"
 )
@@ -24,7 +35,50 @@ func main() {
 	router := routes.NewRouter(eng, db)
 	router.MapRoutes()
 
-	if err := eng.Run(); err != nil {
+	// the background workers run until the server is interrupted
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	startWorkers(ctx, db)
+
+	server := &http.Server{Addr: address(), Handler: eng}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdown); err != nil {
+			logging.Log(err)
+		}
+	}()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		panic(err)
 	}
 }
+
+// startWorkers starts the background jobs, they stop when ctx is cancelled.
+// The excursions are evaluated every EXCURSION_INTERVAL and the lapsed stock reservations are released
+// every RESERVATION_EXPIRY_INTERVAL (durations like 30s, one minute by default)
+func startWorkers(ctx context.Context, db *sql.DB) {
+	excursions := excursion.NewService(excursion.NewRepository(db), time.Now)
+	go excursion.Run(ctx, excursions, interval("EXCURSION_INTERVAL"))
+
+	orders := purchaseorders.NewService(purchaseorders.NewRepository(db, product_record.NewRepository(db)), routes.ReservationTTL())
+	go purchaseorders.Run(ctx, orders, interval("RESERVATION_EXPIRY_INTERVAL"))
+}
+
+// interval returns the positive duration set in the environment variable, or one minute
+func interval(name string) time.Duration {
+	d, err := time.ParseDuration(os.Getenv(name))
+	if err != nil || d <= 0 {
+		return time.Minute
+	}
+	return d
+}
+
+// address returns the address the server listens on, the PORT environment variable or 8080 like gin
+func address() string {
+	if port := os.Getenv("PORT"); port != "" {
+		return ":" + port
+	}
+	return ":8080"
+}
@@ -2,21 +2,30 @@ package routes
 
 import (
 	"database/sql"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/record/margin_report"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/record/product_record"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/record/report_record"
 	"os"
+	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/cmd/server/handler"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/buyer"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/carry"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/country"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/employee"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/excursion"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/geo"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/inbound_order"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/locality"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/product"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/productBatch"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/product_types"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/province"
 	purchaseorders "github.com/extmatperez/meli_bootcamp_go_w6-2/internal/purchase_orders"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/section"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/seller"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/shipment"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/telemetry"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/warehouse"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -46,7 +55,10 @@ func (r *router) MapRoutes() {
 	r.buildSellerRoutes()
 	r.buildProductRoutes()
 	r.buildProductRecordsRoutes()
+	r.buildProductTypeRoutes()
 	r.buildSectionRoutes()
+	r.buildSectionTemperatureRoutes()
+	r.buildExcursionRoutes()
 	r.buildWarehouseRoutes()
 	r.buildEmployeeRoutes()
 	r.buildBuyerRoutes()
@@ -54,7 +66,11 @@ func (r *router) MapRoutes() {
 	r.buildProductBatchRoutes()
 	r.buildInboundOrderRoutes()
 	r.buildCarryRoutes()
+	r.buildShipmentRoutes()
 	r.buildLocalityRoutes()
+	r.buildGeoRoutes()
+	r.buildCountryRoutes()
+	r.buildProvinceRoutes()
 }
 
 func (r *router) setGroup() {
@@ -78,6 +94,9 @@ func (r *router) buildSellerRoutes() {
 	sell.GET("/:id", handler.Get())
 	sell.PATCH("/:id", handler.Update())
 	sell.DELETE("/:id", handler.Delete())
+	sell.GET("/:id/products", handler.GetProducts())
+	sell.GET("/reportPerformance", handler.GetReportPerformance())
+	sell.GET("/:id/deletePreview", handler.DeletePreview())
 }
 
 func (r *router) buildProductRoutes() {
@@ -95,6 +114,16 @@ func (r *router) buildProductRoutes() {
 	reportRecordService := report_record.NewService(reportRecordRepository)
 	reportRecordHandler := handler.NewReportRecord(reportRecordService)
 	productGroup.GET("/reportRecords", reportRecordHandler.GetReportRecords())
+
+	productRecordRepository := product_record.NewRepository(r.db)
+	marginReportRepository := margin_report.NewRepository(r.db, productRecordRepository)
+	marginReportService := margin_report.NewService(marginReportRepository)
+	marginReportHandler := handler.NewMarginReport(marginReportService)
+	productGroup.GET("/reportMargins", marginReportHandler.GetReportMargins())
+
+	productRecordService := product_record.NewService(productRecordRepository)
+	productRecordHandler := handler.NewProductRecord(productRecordService)
+	productGroup.GET("/:id/priceHistory", productRecordHandler.PriceHistory())
 }
 
 func (r *router) buildProductRecordsRoutes() {
@@ -103,6 +132,21 @@ func (r *router) buildProductRecordsRoutes() {
 	productRecordHandler := handler.NewProductRecord(productRecordService)
 	productRecordGroup := r.rg.Group("/productRecords")
 	productRecordGroup.POST("/", productRecordHandler.Create())
+	productRecordGroup.GET("/scheduled", productRecordHandler.GetScheduled())
+	productRecordGroup.DELETE("/:id", productRecordHandler.Cancel())
+}
+
+func (r *router) buildProductTypeRoutes() {
+	repo := product_types.NewRepository(r.db)
+	service := product_types.NewService(repo)
+	handler := handler.NewProductType(service)
+	pt := r.rg.Group("/productTypes")
+
+	pt.GET("", handler.GetAll())
+	pt.GET("/:id", handler.Get())
+	pt.POST("", handler.Create())
+	pt.PATCH("/:id", handler.Update())
+	pt.DELETE("/:id", handler.Delete())
 }
 
 func (r *router) buildSectionRoutes() {
@@ -116,10 +160,30 @@ func (r *router) buildSectionRoutes() {
 	sec.POST("/", handler.Create())
 	sec.PATCH("/:id", handler.Update())
 	sec.DELETE("/:id", handler.Delete())
+	sec.GET("/:id/deletePreview", handler.DeletePreview())
 	sec.GET("/reportProducts", handler.GetSectionProducts())
 
 }
 
+func (r *router) buildSectionTemperatureRoutes() {
+	repo := telemetry.NewRepository(r.db)
+	service := telemetry.NewService(repo)
+	handler := handler.NewSectionTemperature(service)
+	sec := r.rg.Group("/sections")
+
+	sec.POST("/temperatures", handler.Create())
+	sec.GET("/:id/temperatures", handler.GetHistory())
+}
+
+func (r *router) buildExcursionRoutes() {
+	repo := excursion.NewRepository(r.db)
+	service := excursion.NewService(repo, time.Now)
+	handler := handler.NewExcursion(service)
+	exc := r.rg.Group("/excursions")
+
+	exc.GET("", handler.GetAll())
+}
+
 func (r *router) buildWarehouseRoutes() {
 	repo := warehouse.NewRepository(r.db)
 	service := warehouse.NewService(repo)
@@ -130,6 +194,8 @@ func (r *router) buildWarehouseRoutes() {
 	warehouseRouter.POST("/", controller.Create)
 	warehouseRouter.PATCH("/:id", controller.Update)
 	warehouseRouter.DELETE("/:id", controller.Delete)
+	warehouseRouter.GET("/:id/reportExpiring", controller.ReportExpiring)
+	warehouseRouter.GET("/:id/deletePreview", controller.DeletePreview)
 }
 
 func (router *router) buildEmployeeRoutes() {
@@ -143,8 +209,9 @@ func (router *router) buildEmployeeRoutes() {
 	employeesRoutesGroup.POST("/", handlerEmployee.Create())
 	employeesRoutesGroup.PATCH("/:id", handlerEmployee.Update())
 	employeesRoutesGroup.DELETE("/:id", handlerEmployee.Delete())
+	employeesRoutesGroup.GET("/:id/deletePreview", handlerEmployee.DeletePreview())
 
-	repoInboundOrder := inbound_order.NewRepository(router.db)
+	repoInboundOrder := inbound_order.NewRepository(router.db, productbatch.NewRepository(router.db))
 	serviceInboundOrder := inbound_order.NewService(repoInboundOrder)
 	handlerInboundOrder := handler.NewInboundOrder(serviceInboundOrder)
 
@@ -162,15 +229,34 @@ func (r *router) buildBuyerRoutes() {
 	sec.POST("/", handler.Create())
 	sec.PATCH("/:id", handler.Update())
 	sec.DELETE("/:id", handler.Delete())
+	sec.GET("/:id/deletePreview", handler.DeletePreview())
+}
+
+// ReservationTTL returns how long the stock of a new order is held, the RESERVATION_TTL environment variable
+// (a duration like 15m) or purchaseorders.DefaultReservationTTL
+func ReservationTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("RESERVATION_TTL"))
+	if err != nil {
+		return purchaseorders.DefaultReservationTTL
+	}
+	return ttl
 }
 
 func (r *router) buildPurchaseOrderRoutes() {
-	repo := purchaseorders.NewRepository(r.db)
-	service := purchaseorders.NewService(repo)
+	repo := purchaseorders.NewRepository(r.db, product_record.NewRepository(r.db))
+	service := purchaseorders.NewService(repo, ReservationTTL())
 	handler := handler.NewPurchaseOrders(service)
 
 	sec := r.rg.Group("/purchase_orders")
+	sec.GET("/", handler.GetOrders())
+	sec.GET("/:id", handler.GetOrder())
 	sec.POST("/", handler.CreateOrder())
+	sec.GET("/:id/status", handler.GetStatusHistory())
+	sec.PATCH("/:id/status", handler.UpdateStatus())
+	sec.GET("/:id/reservations", handler.GetReservations())
+
+	buy := r.rg.Group("/buyers")
+	buy.GET("/:id/purchase_orders", handler.GetOrdersByBuyer())
 
 	rep := r.rg.Group("/reportPurchaseOrder")
 	rep.GET("", handler.GetAllOrdersByBuyers())
@@ -181,16 +267,26 @@ func (r *router) buildProductBatchRoutes() {
 	service := productbatch.NewService(repo)
 	handler := handler.NewProductBatch(service)
 	group := r.rg.Group("/productBatches")
+	group.GET("/", handler.GetAll())
+	group.GET("/:id", handler.Get())
 	group.POST("/", handler.Create())
+	group.PATCH("/:id", handler.Update())
+	group.DELETE("/:id", handler.Delete())
+	group.POST("/pick", handler.Pick())
 }
 
 func (router *router) buildInboundOrderRoutes() {
-	repo := inbound_order.NewRepository(router.db)
+	repo := inbound_order.NewRepository(router.db, productbatch.NewRepository(router.db))
 	service := inbound_order.NewService(repo)
 	handler := handler.NewInboundOrder(service)
 	inboundOrdersRoutesGroup := router.rg.Group("/inboundOrders")
 
+	inboundOrdersRoutesGroup.GET("/", handler.GetAll())
+	inboundOrdersRoutesGroup.GET("/:id", handler.Get())
 	inboundOrdersRoutesGroup.POST("/", handler.Create())
+	inboundOrdersRoutesGroup.POST("/receive", handler.Receive())
+	inboundOrdersRoutesGroup.PATCH("/:id", handler.Update())
+	inboundOrdersRoutesGroup.POST("/:id/cancel", handler.Cancel())
 }
 
 func (r *router) buildCarryRoutes() {
@@ -199,6 +295,27 @@ func (r *router) buildCarryRoutes() {
 	controller := handler.NewCarry(service)
 	carryRouter := r.rg.Group("/carries")
 	carryRouter.POST("/", controller.Save)
+	carryRouter.GET("/", controller.GetAll)
+	carryRouter.GET("/:id", controller.Get)
+	carryRouter.PATCH("/:id", controller.Update)
+	carryRouter.DELETE("/:id", controller.Delete)
+}
+
+func (r *router) buildShipmentRoutes() {
+	ordersRepository := purchaseorders.NewRepository(r.db, product_record.NewRepository(r.db))
+	orders := purchaseorders.NewService(ordersRepository, ReservationTTL())
+	repo := shipment.NewRepository(r.db, ordersRepository)
+	service := shipment.NewService(repo, orders)
+	handler := handler.NewShipment(service)
+
+	carries := r.rg.Group("/carries")
+	carries.GET("/:id/shipments", handler.GetByCarry())
+	carries.POST("/:id/shipments", handler.Create())
+
+	ship := r.rg.Group("/shipments")
+	ship.GET("/:id", handler.Get())
+	ship.POST("/:id/dispatch", handler.Dispatch())
+	ship.POST("/:id/deliver", handler.Deliver())
 }
 
 func (r *router) buildLocalityRoutes() {
@@ -211,4 +328,44 @@ func (r *router) buildLocalityRoutes() {
 	loc.GET("/:id", handler.Get())
 	loc.GET("/reportSellers", handler.GetReportSellers())
 	loc.GET("/reportCarries", handler.GetReportCarries())
+	loc.PATCH("/:id", handler.UpdateCoordinates())
+	loc.POST("/import", handler.Import())
+}
+
+func (r *router) buildGeoRoutes() {
+	repo := geo.NewRepository(r.db)
+	service := geo.NewService(repo)
+	handler := handler.NewGeo(service)
+	loc := r.rg.Group("/localities")
+
+	loc.GET("/:id/nearestWarehouses", handler.NearestWarehouses())
+	loc.GET("/:id/carries", handler.CarriesWithin())
+}
+
+func (r *router) buildCountryRoutes() {
+	repo := country.NewRepository(r.db)
+	service := country.NewService(repo)
+	handler := handler.NewCountry(service)
+	cou := r.rg.Group("/countries")
+
+	cou.GET("", handler.GetAll())
+	cou.GET("/:id", handler.Get())
+	cou.POST("", handler.Create())
+	cou.PATCH("/:id", handler.Update())
+	cou.DELETE("/:id", handler.Delete())
+}
+
+func (r *router) buildProvinceRoutes() {
+	repo := province.NewRepository(r.db)
+	service := province.NewService(repo)
+	handler := handler.NewProvince(service)
+	pro := r.rg.Group("/provinces")
+
+	pro.GET("", handler.GetAll())
+	pro.GET("/:id", handler.Get())
+	pro.POST("", handler.Create())
+	pro.PATCH("/:id", handler.Update())
+	pro.DELETE("/:id", handler.Delete())
+	pro.GET("/:id/reportSellers", handler.GetReportSellers())
+	pro.GET("/:id/reportCarries", handler.GetReportCarries())
 }
@@ -1,11 +1,24 @@
 drop database if exists melisprint;
 create database melisprint;
 use melisprint;
+create table countries(
+    `id` int not null primary key auto_increment,
+    `name` varchar(100) not null unique
+);
+create table provinces(
+    `id` int not null primary key auto_increment,
+    `name` varchar(100) not null,
+    country_id int not null,
+    unique (country_id, `name`),
+    foreign key (country_id) references countries(id)
+);
 create table localities(
     `id` varchar(10) not null primary key,
     locality_name text not null,
-    province_name text not null,
-    country_name text not null
+    province_id int not null,
+    latitude decimal(9,6) null,
+    longitude decimal(9,6) null,
+    foreign key (province_id) references provinces(id)
 );
 create table sellers(
     `id` int not null primary key auto_increment,
@@ -16,6 +29,16 @@ create table sellers(
     locality_id varchar(10) not null,
     foreign key (locality_id) references localities(id)
 );
+create table product_types(
+    `id` int not null primary key auto_increment,
+    `name` varchar(50) not null unique,
+    minimum_temperature float null,
+    maximum_temperature float null
+);
+insert into product_types (`id`, `name`, minimum_temperature, maximum_temperature) values
+    (1, 'frozen', -30, -18),
+    (2, 'refrigerated', 0, 8),
+    (3, 'dry', 10, 25);
 create table products(
     `id` int not null primary key auto_increment,
     `description` text not null,
@@ -29,7 +52,8 @@ create table products(
     width float not null,
     id_product_type int not null,
     id_seller int,
-    foreign key (id_seller) references sellers(id)
+    foreign key (id_seller) references sellers(id),
+    foreign key (id_product_type) references product_types(id)
 );
 create table warehouses(
     `id` int not null primary key auto_increment,
@@ -37,7 +61,10 @@ create table warehouses(
     telephone text null,
     warehouse_code text null,
     minimum_capacity int null,
-    minimum_temperature int null
+    minimum_temperature int null,
+    temperature_policy varchar(10) not null default 'reject',
+    locality_id varchar(10) null,
+    foreign key (locality_id) references localities(id)
 );
 create table employees(
     `id` int not null primary key auto_increment,
@@ -57,7 +84,16 @@ create table sections(
     maximum_capacity int not null,
     warehouse_id int not null,
     id_product_type int not null,
-    foreign key (warehouse_id) references warehouses(id)
+    foreign key (warehouse_id) references warehouses(id),
+    foreign key (id_product_type) references product_types(id)
+);
+create table section_temperatures(
+    `id` int not null primary key auto_increment,
+    section_id int not null,
+    recorded_at datetime not null,
+    temperature float not null,
+    index (section_id, recorded_at),
+    foreign key (section_id) references sections(id)
 );
 create table buyers(
     `id` int not null primary key auto_increment,
@@ -71,7 +107,8 @@ create table product_records(
     purchase_price float,
     sale_price float,
     product_id int not null,
-    foreign key (product_id) references products(id)
+    foreign key (product_id) references products(id),
+    index product_records_product_date (product_id, last_update_date)
 );
 create table product_batches(
     id int not null primary key auto_increment,
@@ -86,8 +123,21 @@ create table product_batches(
     product_id int not null ,
     foreign key (product_id) references products(id),
     section_id int not null,
+    quarantined boolean not null default false,
+    reserved_quantity int not null default 0,
     foreign key (section_id) references sections(id)
 );
+create table temperature_excursions(
+    `id` int not null primary key auto_increment,
+    section_id int not null,
+    product_batch_id int not null,
+    limit_temperature float not null,
+    maximum_temperature float not null,
+    started_at datetime not null,
+    ended_at datetime null,
+    foreign key (section_id) references sections(id),
+    foreign key (product_batch_id) references product_batches(id)
+);
 create table carries(
     `id` int not null primary key auto_increment,
     cid varchar(10) unique null,
@@ -99,25 +149,90 @@ create table carries(
 );
 create table inbound_orders(
     `id` int not null primary key auto_increment,
-    order_date text not null,
+    order_date date not null,
     order_number varchar(100) not null unique,
     employee_id int not null,
     product_batch_id int not null,
     warehouse_id int not null,
+    cancelled_at datetime null,
+    index (order_date),
     foreign key (employee_id) references employees(id),
     foreign key (warehouse_id) references warehouses(id),
     foreign key (product_batch_id) references product_batches(id)
 );
+create table order_statuses(
+    `id` int not null primary key,
+    `name` varchar(20) not null unique
+);
+insert into order_statuses (`id`, `name`) values
+    (1, 'pending'),
+    (2, 'confirmed'),
+    (3, 'picking'),
+    (4, 'shipped'),
+    (5, 'delivered'),
+    (6, 'cancelled');
 create table purchase_orders(
 	`id` int not null primary key auto_increment,
     order_number text not null,
     order_date date not null,
     tracking_code text not null,
     buyer_id int not null,
-    product_record_id int not null,
-    order_status_id int not null,
+    product_record_id int,
+    order_status_id int not null default 1,
     foreign key (product_record_id) references product_records(id),
-	foreign key (buyer_id) references buyers(id)
+	foreign key (buyer_id) references buyers(id),
+    foreign key (order_status_id) references order_statuses(id)
+);
+create table order_items(
+    `id` int not null primary key auto_increment,
+    purchase_order_id int not null,
+    product_id int not null,
+    product_record_id int not null,
+    quantity int not null,
+    unit_price decimal(19,2) not null,
+    unique (purchase_order_id, product_id),
+    foreign key (purchase_order_id) references purchase_orders(id),
+    foreign key (product_id) references products(id),
+    foreign key (product_record_id) references product_records(id)
+);
+create table stock_reservations(
+    `id` int not null primary key auto_increment,
+    purchase_order_id int not null,
+    product_batch_id int not null,
+    quantity int not null,
+    `status` varchar(10) not null,
+    expires_at datetime,
+    created_at datetime not null,
+    index (`status`, expires_at),
+    foreign key (purchase_order_id) references purchase_orders(id),
+    foreign key (product_batch_id) references product_batches(id)
+);
+create table purchase_order_status_history(
+    `id` int not null primary key auto_increment,
+    purchase_order_id int not null,
+    from_status_id int not null,
+    to_status_id int not null,
+    actor varchar(100) not null,
+    changed_at datetime not null,
+    foreign key (purchase_order_id) references purchase_orders(id),
+    foreign key (from_status_id) references order_statuses(id),
+    foreign key (to_status_id) references order_statuses(id)
+);
+create table shipments(
+    `id` int not null primary key auto_increment,
+    carry_id int not null,
+    tracking_code varchar(50) not null unique,
+    created_at datetime not null,
+    dispatched_at datetime null,
+    delivered_at datetime null,
+    foreign key (carry_id) references carries(id)
+);
+create table shipment_orders(
+    shipment_id int not null,
+    purchase_order_id int not null unique,
+    primary key (shipment_id, purchase_order_id),
+    foreign key (shipment_id) references shipments(id),
+    foreign key (purchase_order_id) references purchase_orders(id)
 );
 create table logs(
     `id` int not null primary key auto_increment,
@@ -142,7 +142,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "delete buyer",
+                "description": "delete buyer, with strategy=block (default) the delete fails while the buyer has purchase orders,\nwith strategy=reassign\u0026to=\u003cid\u003e they are moved to another buyer first",
                 "produces": [
                     "application/json"
                 ],
@@ -157,18 +157,42 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "block or reassign",
+                        "name": "strategy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "buyer to reassign the purchase orders to",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "204": {
                         "description": "No Content"
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
                     "422": {
                         "description": "Unprocessable Entity",
                         "schema": {
@@ -238,75 +262,186 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/employees": {
+        "/api/v1/buyers/{id}/deletePreview": {
             "get": {
-                "description": "Lists all existing employees from database",
+                "description": "list the purchase orders a delete of the buyer affects and the strategies it accepts",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Employees"
+                    "Buyers"
+                ],
+                "summary": "Preview buyer delete",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "buyer id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "List employees",
                 "responses": {
                     "200": {
-                        "description": "List of employees",
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/web.response"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
                     "500": {
-                        "description": "Connection to database error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Creates a new employee in database",
-                "consumes": [
+            }
+        },
+        "/api/v1/buyers/{id}/purchase_orders": {
+            "get": {
+                "description": "get every Purchase_Order of a buyer with their status and product record, the most recent first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase_Order"
+                ],
+                "summary": "List Purchase_Order of a buyer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "buyer id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/carries": {
+            "get": {
+                "description": "list carries, optionally the ones serving a locality or every locality of a province or country",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "Carries"
+                ],
+                "summary": "List carries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "locality id",
+                        "name": "locality_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "province name",
+                        "name": "province",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "country name",
+                        "name": "country",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create carry",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Employees"
+                    "Carries"
                 ],
-                "summary": "Create employee",
+                "summary": "Create carry",
                 "parameters": [
                     {
-                        "description": "Employee to be stored",
-                        "name": "employee",
+                        "description": "Carry to save",
+                        "name": "carry",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.EmployeeDTOPost"
+                            "$ref": "#/definitions/requests.CarryPostRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Employee created",
+                        "description": "Created",
                         "schema": {
                             "$ref": "#/definitions/web.response"
                         }
                     },
                     "409": {
-                        "description": "Employee ID already exists error",
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "422": {
-                        "description": "Missing field or type casting error",
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "500": {
-                        "description": "Connection to database error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
@@ -314,20 +449,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/employees/{id}": {
+        "/api/v1/carries/{id}": {
             "get": {
-                "description": "Retrieves existing employee by ID from database",
+                "description": "get carry by ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Employees"
+                    "Carries"
                 ],
-                "summary": "Get employee by ID",
+                "summary": "Get carry by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Employee id",
+                        "description": "carry id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -335,25 +470,25 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Employee",
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/web.response"
                         }
                     },
                     "400": {
-                        "description": "Invalid id type",
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "404": {
-                        "description": "Employee not found",
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "500": {
-                        "description": "Connection to database error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
@@ -361,18 +496,15 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Deletes an existing employee from database",
-                "produces": [
-                    "application/json"
-                ],
+                "description": "delete carry by ID",
                 "tags": [
-                    "Employees"
+                    "Carries"
                 ],
-                "summary": "Delete employee",
+                "summary": "Delete carry",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Employee id",
+                        "description": "carry id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -383,19 +515,25 @@ const docTemplate = `{
                         "description": "No Content"
                     },
                     "400": {
-                        "description": "Invalid id type",
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "404": {
-                        "description": "Employee not found",
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "500": {
-                        "description": "Connection to dabatase error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
@@ -403,7 +541,7 @@ const docTemplate = `{
                 }
             },
             "patch": {
-                "description": "Updates information of an existing employee in database",
+                "description": "update the given fields of a carry",
                 "consumes": [
                     "application/json"
                 ],
@@ -411,54 +549,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Employees"
+                    "Carries"
                 ],
-                "summary": "Update employee",
+                "summary": "Update carry",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Employee id",
+                        "description": "carry id",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Employee to update",
-                        "name": "employee",
+                        "description": "Fields to update",
+                        "name": "carry",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.EmployeeDTOPatch"
+                            "$ref": "#/definitions/requests.CarryPatchRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Employee updated",
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/web.response"
                         }
                     },
                     "400": {
-                        "description": "Invalid id type",
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "404": {
-                        "description": "Employee not found",
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "422": {
-                        "description": "Missing field or type casting error",
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "500": {
-                        "description": "Cannection to database error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
@@ -466,25 +610,46 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/products": {
+        "/api/v1/carries/{id}/shipments": {
             "get": {
-                "description": "List of all Product from database",
+                "description": "get every shipment of a carry with its orders, tracking code and timestamps",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Shipments"
+                ],
+                "summary": "List carry shipments",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "carry id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "GET []Product",
                 "responses": {
                     "200": {
-                        "description": "List of Products",
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/web.response"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
                     "500": {
-                        "description": "Problems connecting to database",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
@@ -492,7 +657,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Creates a new Product on the database",
+                "description": "assign picking purchase orders to a carry. Every order gets the tracking code of the shipment,\nwhich is generated when it is not given.",
                 "consumes": [
                     "application/json"
                 ],
@@ -500,41 +665,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Shipments"
                 ],
-                "summary": "POST Product",
+                "summary": "Create shipment",
                 "parameters": [
                     {
-                        "description": "Product to be created",
-                        "name": "product",
+                        "type": "integer",
+                        "description": "carry id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "orders of the shipment",
+                        "name": "shipment",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.ProductPOSTRequest"
+                            "$ref": "#/definitions/requests.RequestShipmentPost"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Product created",
+                        "description": "Created",
                         "schema": {
                             "$ref": "#/definitions/web.response"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
                     "409": {
-                        "description": "Conflict: Product code already exists",
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "422": {
-                        "description": "Missing field or type casting error",
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "500": {
-                        "description": "Unknown or unhandled error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
@@ -542,46 +726,122 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/products/{id}": {
+        "/api/v1/countries": {
             "get": {
-                "description": "Retrieves one Product from database by ID",
+                "description": "get every country ordered by name",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Countries"
                 ],
-                "summary": "GET Product by ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Product ID",
-                        "name": "id",
+                "summary": "List countries",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create a country, names are unique without case",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Countries"
+                ],
+                "summary": "Create Country",
+                "parameters": [
+                    {
+                        "description": "Country to create",
+                        "name": "country",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.CountryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/countries/{id}": {
+            "get": {
+                "description": "get country",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Countries"
+                ],
+                "summary": "Country by id",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "country id",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Product",
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/web.response"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID",
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "404": {
-                        "description": "Product not found",
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "500": {
-                        "description": "Unknown or unhandled error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
@@ -589,32 +849,44 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Remove a Product from the database by ID",
-                "produces": [
-                    "application/json"
-                ],
+                "description": "delete a country without provinces",
                 "tags": [
-                    "Products"
+                    "Countries"
+                ],
+                "summary": "Delete Country",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "country id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "DELETE Product by ID",
                 "responses": {
                     "204": {
                         "description": "No Content"
                     },
                     "400": {
-                        "description": "Invalid ID",
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "404": {
-                        "description": "Product not found",
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "500": {
-                        "description": "Unknown or unhandled error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
@@ -622,7 +894,7 @@ const docTemplate = `{
                 }
             },
             "patch": {
-                "description": "Partially updates an existing Product on the database by ID",
+                "description": "rename a country",
                 "consumes": [
                     "application/json"
                 ],
@@ -630,60 +902,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Countries"
                 ],
-                "summary": "PATCH Product by ID",
+                "summary": "Update Country",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Product ID",
+                        "description": "country id",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Product to be updated",
-                        "name": "product",
+                        "description": "New name",
+                        "name": "country",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.ProductPATCHRequest"
+                            "$ref": "#/definitions/requests.CountryRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Product updated",
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/web.response"
                         }
                     },
                     "400": {
-                        "description": "Invalid field or ID",
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "404": {
-                        "description": "Product not found",
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "409": {
-                        "description": "Conflict: Product code already exists",
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "422": {
-                        "description": "Type casting error",
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "500": {
-                        "description": "Unknown or unhandled error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
@@ -691,25 +963,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/sellers": {
+        "/api/v1/employees": {
             "get": {
-                "description": "get sellers",
+                "description": "Lists all existing employees from database",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Sellers"
+                    "Employees"
                 ],
-                "summary": "List sellers",
+                "summary": "List employees",
                 "responses": {
                     "200": {
-                        "description": "Get sellers",
+                        "description": "List of employees",
                         "schema": {
                             "$ref": "#/definitions/web.response"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Connection to database error",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
@@ -717,6 +989,7 @@ const docTemplate = `{
                 }
             },
             "post": {
+                "description": "Creates a new employee in database",
                 "consumes": [
                     "application/json"
                 ],
@@ -724,47 +997,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Sellers"
+                    "Employees"
                 ],
-                "summary": "Create Seller",
+                "summary": "Create employee",
                 "parameters": [
                     {
-                        "description": "Seller to Create",
-                        "name": "seller",
+                        "description": "Employee to be stored",
+                        "name": "employee",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.SellerPostRequest"
+                            "$ref": "#/definitions/requests.EmployeeDTOPost"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "New seller",
+                        "description": "Employee created",
                         "schema": {
                             "$ref": "#/definitions/web.response"
                         }
                     },
-                    "400": {
-                        "description": "BadRequest",
-                        "schema": {
-                            "$ref": "#/definitions/web.errorResponse"
-                        }
-                    },
                     "409": {
-                        "description": "Conflict",
+                        "description": "Employee ID already exists error",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "422": {
-                        "description": "UnprocessableEntity",
+                        "description": "Missing field or type casting error",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Connection to database error",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
@@ -772,225 +1039,287 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/sellers/{id}": {
+        "/api/v1/employees/reportInboundOrders": {
             "get": {
-                "description": "get seller",
+                "description": "Lists all existing employees and their inbound orders from database.\nWith from, to or warehouse_id the employees that received orders in the period are listed with their received units and a bucket per day,\nwith top the N employees with most orders and with most received units are ranked instead.\nUse format=csv or an Accept: text/csv header to get the report as CSV",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv"
                 ],
                 "tags": [
-                    "Sellers"
+                    "Inbound Orders"
                 ],
-                "summary": "Seller by id",
+                "summary": "List employees and their inbound orders",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Minimum order date (yyyy-mm-dd)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Maximum order date (yyyy-mm-dd)",
+                        "name": "to",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "seller id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Warehouse of the inbound orders",
+                        "name": "warehouse_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Size of the rankings",
+                        "name": "top",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json or csv",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Get seller",
+                        "description": "List of employees and their inbound orders",
                         "schema": {
                             "$ref": "#/definitions/web.response"
                         }
                     },
                     "400": {
-                        "description": "BadRequest",
-                        "schema": {
-                            "$ref": "#/definitions/web.errorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not found",
+                        "description": "Invalid filter",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Connection to database error",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/employees/reportInboundOrders/{id}": {
+            "get": {
+                "description": "Retrieves existing employee by ID and its inbound orders from database",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Sellers"
+                    "InboundOrders"
                 ],
-                "summary": "Delete seller",
+                "summary": "Get employee by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "seller id",
+                        "description": "Employee id",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "Employee with inbound orders",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
                     },
                     "400": {
-                        "description": "BadRequest",
+                        "description": "Invalid id type",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "404": {
-                        "description": "Not found",
+                        "description": "Employee not found",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Connection to database error",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     }
                 }
-            },
-            "patch": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/employees/{id}": {
+            "get": {
+                "description": "Retrieves existing employee by ID from database",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Sellers"
+                    "Employees"
                 ],
-                "summary": "Update",
+                "summary": "Get employee by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "seller id",
+                        "description": "Employee id",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "seller",
-                        "name": "seller",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/requests.SellerPatchRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Seller",
+                        "description": "Employee",
                         "schema": {
                             "$ref": "#/definitions/web.response"
                         }
                     },
                     "400": {
-                        "description": "BadRequest",
+                        "description": "Invalid id type",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "404": {
-                        "description": "NotFound",
-                        "schema": {
-                            "$ref": "#/definitions/web.errorResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "Conflict",
+                        "description": "Employee not found",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
-                    "422": {
-                        "description": "UnprocessableEntity",
-                        "schema": {
-                            "$ref": "#/definitions/web.errorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
+                    "500": {
+                        "description": "Connection to database error",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/warehouses": {
-            "get": {
-                "description": "get warehouses",
+            },
+            "delete": {
+                "description": "Deletes an existing employee from database, block fails while the employee has inbound orders\nand reassign moves them to the employee to",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Warehouses"
+                    "Employees"
+                ],
+                "summary": "Delete employee",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Employee id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "block (default) or reassign",
+                        "name": "strategy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Employee that receives the inbound orders on reassign",
+                        "name": "to",
+                        "in": "query"
+                    }
                 ],
-                "summary": "List warehouses",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid id type or strategy",
                         "schema": {
-                            "$ref": "#/definitions/web.response"
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Employee not found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Employee has inbound orders",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Employee to reassign to not found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Connection to dabatase error",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     }
                 }
             },
-            "post": {
-                "description": "create warehouse",
+            "patch": {
+                "description": "Updates information of an existing employee in database",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Warehouses"
+                    "Employees"
                 ],
-                "summary": "Create warehouse",
+                "summary": "Update employee",
                 "parameters": [
                     {
-                        "description": "Warehouse to store",
-                        "name": "warehouse",
+                        "type": "integer",
+                        "description": "Employee id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Employee to update",
+                        "name": "employee",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.WarehousePostRequest"
+                            "$ref": "#/definitions/requests.EmployeeDTOPatch"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "Employee updated",
                         "schema": {
                             "$ref": "#/definitions/web.response"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "400": {
+                        "description": "Invalid id type",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Employee not found",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "422": {
-                        "description": "Unprocessable Entity",
+                        "description": "Missing field or type casting error",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Connection to database error",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
@@ -998,20 +1327,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/warehouses/{id}": {
+        "/api/v1/employees/{id}/deletePreview": {
             "get": {
-                "description": "get warehouse by ID",
+                "description": "Retrieves the inbound orders a delete of the employee affects and the strategies it can be deleted with",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Warehouses"
+                    "Employees"
                 ],
-                "summary": "Get warehouse by ID",
+                "summary": "Preview the delete of an employee",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "warehouse id",
+                        "description": "Employee id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1019,61 +1348,65 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Delete preview",
                         "schema": {
                             "$ref": "#/definitions/web.response"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid id type",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "404": {
-                        "description": "Not Found",
+                        "description": "Employee not found",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Connection to database error",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "delete warehouse",
+            }
+        },
+        "/api/v1/excursions": {
+            "get": {
+                "description": "get the periods in which a section was warmer than a product batch stored in it allows",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Warehouses"
+                    "Excursions"
                 ],
-                "summary": "Delete warehouse",
+                "summary": "List temperature excursions",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "open or closed",
+                        "name": "status",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "warehouse id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "section id",
+                        "name": "section_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.errorResponse"
+                            "$ref": "#/definitions/web.response"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
@@ -1085,134 +1418,173 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "description": "update warehouse",
+            }
+        },
+        "/api/v1/inboundOrders": {
+            "get": {
+                "description": "Lists the inbound orders from database, the most recent first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Warehouses"
+                    "InboundOrders"
                 ],
-                "summary": "Update warehouse",
+                "summary": "List inbound orders",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "warehouse id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Warehouse id",
+                        "name": "warehouse_id",
+                        "in": "query"
                     },
                     {
-                        "description": "Warehouse to update",
-                        "name": "warehouse",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/requests.WarehousePatchRequest"
-                        }
+                        "type": "integer",
+                        "description": "Employee id",
+                        "name": "employee_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Product batch id",
+                        "name": "product_batch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Minimum order date (yyyy-mm-dd)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Maximum order date (yyyy-mm-dd)",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "List of inbound orders",
                         "schema": {
                             "$ref": "#/definitions/web.response"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/web.errorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/web.errorResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "Conflict",
+                        "description": "Invalid filter",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Connection to database error",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/sections": {
-            "get": {
-                "description": "get sections",
+            },
+            "post": {
+                "description": "Creates a new inbound order in database",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Sections"
+                    "InboundOrders"
+                ],
+                "summary": "Create inbound order",
+                "parameters": [
+                    {
+                        "description": "Inbound order to be stored",
+                        "name": "inboundOrder",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.InboundOrderDTOPOST"
+                        }
+                    }
                 ],
-                "summary": "List sections",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Inbound order created",
                         "schema": {
                             "$ref": "#/definitions/web.response"
                         }
                     },
+                    "409": {
+                        "description": "Inbound order with order number already exists error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Missing field, type casting error or invalid order date",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Connection to database error",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/inboundOrders/receive": {
             "post": {
-                "description": "create section",
+                "description": "Creates the received product batch and its inbound order at once, the batch is stored in a section of the warehouse of the order",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Sections"
+                    "InboundOrders"
                 ],
-                "summary": "Create section",
+                "summary": "Receive inbound order",
                 "parameters": [
                     {
-                        "description": "Section to store",
-                        "name": "section",
+                        "description": "Inbound order and received batch",
+                        "name": "receipt",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.PostSection"
+                            "$ref": "#/definitions/requests.InboundOrderReceiveDTOPOST"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created",
+                        "description": "Inbound order and product batch created",
                         "schema": {
                             "$ref": "#/definitions/web.response"
                         }
                     },
+                    "404": {
+                        "description": "Employee, warehouse, section or product not found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
                     "409": {
-                        "description": "Conflict",
+                        "description": "Order or batch number already exists, section full or too warm",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "422": {
-                        "description": "Unprocessable Entity",
+                        "description": "Missing field, invalid dates or quantity, or section of another warehouse",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Connection to database error",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
@@ -1220,20 +1592,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/sections/{id}": {
+        "/api/v1/inboundOrders/{id}": {
             "get": {
-                "description": "get section by ID",
+                "description": "Retrieves an existing inbound order by ID from database",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Sections"
+                    "InboundOrders"
                 ],
-                "summary": "Get section by ID",
+                "summary": "Get inbound order by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "section id",
+                        "description": "Inbound order id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1241,50 +1613,3548 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Inbound order",
                         "schema": {
                             "$ref": "#/definitions/web.response"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid id type",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "404": {
-                        "description": "Not Found",
+                        "description": "Inbound order not found",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Connection to database error",
                         "schema": {
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     }
                 }
             },
-            "delete": {
-                "description": "delete section",
+            "patch": {
+                "description": "Updates the given fields of an existing inbound order, cancelled orders cannot be updated",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Sections"
+                    "InboundOrders"
                 ],
-                "summary": "Delete section",
+                "summary": "Update inbound order",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "section id",
+                        "description": "Inbound order id",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "inboundOrder",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.InboundOrderDTOPATCH"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Inbound order updated",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid id type",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Inbound order, employee, warehouse or product batch not found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Inbound order cancelled or order number already exists",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Type casting error or invalid order date",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Connection to database error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/inboundOrders/{id}/cancel": {
+            "post": {
+                "description": "Cancels an existing inbound order, cancelled orders are not counted in the employee reports",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "InboundOrders"
+                ],
+                "summary": "Cancel inbound order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Inbound order id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Inbound order cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid id type",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Inbound order not found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Inbound order already cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Connection to database error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/localities": {
+            "post": {
+                "description": "the province is given by province_id, or by province_name together with country_id or country_name.\nNames are matched without case and missing provinces or countries are created.\nlatitude and longitude are optional, but given together.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Localities"
+                ],
+                "summary": "Create Locality",
+                "parameters": [
+                    {
+                        "description": "Locality to Create",
+                        "name": "locality",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Locality"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "New locality",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "BadRequest",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Province or country not found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "UnprocessableEntity",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/localities/import": {
+            "post": {
+                "description": "create localities from a csv file with a header row, or from ndjson with one locality per line.\nThe file is the body, with a text/csv or application/x-ndjson content type, or the file field of a multipart form.\nEvery row follows the rules of Create, valid rows are stored in batches and the report lists the rejected ones.\nWith dry_run the rows are validated against the database but nothing is stored.",
+                "consumes": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Localities"
+                ],
+                "summary": "Import localities",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "validate without storing",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Import report",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "BadRequest",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported format",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/localities/reportCarries": {
+            "get": {
+                "description": "Get total sellers number of a given locality",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Localities"
+                ],
+                "summary": "Get total sellers number",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "locality id",
+                        "name": "id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/localities/reportSellers": {
+            "get": {
+                "responses": {}
+            }
+        },
+        "/api/v1/localities/{id}": {
+            "get": {
+                "description": "get locality",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Localities"
+                ],
+                "summary": "Locality by id",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "locality id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Get locality",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "BadRequest",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "set the latitude and longitude of a locality, used to find its nearest warehouses and carries",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Localities"
+                ],
+                "summary": "Update locality coordinates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "locality id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Coordinates in decimal degrees",
+                        "name": "coordinates",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.LocalityCoordinatesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated locality",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "UnprocessableEntity",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/localities/{id}/carries": {
+            "get": {
+                "description": "get the carries at most radius_km kilometers away from a locality, closest first.\nCarries whose locality has no coordinates are left out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Localities"
+                ],
+                "summary": "Carries within a radius",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "locality id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "radius in kilometers",
+                        "name": "radius_km",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The locality has no coordinates",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/localities/{id}/nearestWarehouses": {
+            "get": {
+                "description": "get the warehouses closest to a locality, with their distance in kilometers.\nWarehouses whose locality has no coordinates are left out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Localities"
+                ],
+                "summary": "Nearest warehouses",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "locality id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of warehouses, 5 by default",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The locality has no coordinates",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/productRecords": {
+            "post": {
+                "description": "\"Creates a new ProductRecord on the database\"\n\"A last_update_date after today schedules a price change, it becomes the price of the Product on that date\"",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ProductRecords"
+                ],
+                "summary": "POST \"ProductRecord\"",
+                "parameters": [
+                    {
+                        "description": "ProductRecord to be created",
+                        "name": "product_record",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.ProductRecordPOSTRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "ProductRecord created",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "404": {
+                        "description": "ProductRecord created but not found in database",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Missing Field or Type casting error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Unknown or unhandled error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/productRecords/scheduled": {
+            "get": {
+                "description": "\"Retrieves the ProductRecords dated after today ordered by date, each one becomes the price of its Product on its date\"",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ProductRecords"
+                ],
+                "summary": "GET the scheduled price changes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Scheduled ProductRecords",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Unknown or unhandled error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/productRecords/{id}": {
+            "delete": {
+                "description": "\"Deletes a ProductRecord dated after today, records already effective can not be deleted\"",
+                "tags": [
+                    "ProductRecords"
+                ],
+                "summary": "DELETE a scheduled price change",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ProductRecord ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Scheduled price change canceled",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "ProductRecord not found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "ProductRecord already effective or used by purchase orders",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Unknown or unhandled error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/productTypes": {
+            "get": {
+                "description": "get every product type with its default temperature range",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ProductTypes"
+                ],
+                "summary": "List product types",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create a product type with its default temperature range, names are unique without case",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ProductTypes"
+                ],
+                "summary": "Create ProductType",
+                "parameters": [
+                    {
+                        "description": "ProductType to create",
+                        "name": "productType",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.ProductTypeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/productTypes/{id}": {
+            "get": {
+                "description": "get product type",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ProductTypes"
+                ],
+                "summary": "ProductType by id",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "product type id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "delete a product type no product or section uses",
+                "tags": [
+                    "ProductTypes"
+                ],
+                "summary": "Delete ProductType",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "product type id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "rename a product type or change its default temperature range",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ProductTypes"
+                ],
+                "summary": "Update ProductType",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "product type id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "productType",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.ProductTypePatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/products": {
+            "get": {
+                "description": "List of all Product from database",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "GET []Product",
+                "responses": {
+                    "200": {
+                        "description": "List of Products",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Problems with the database",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a new Product on the database, recommended_freezing_temperature defaults to the maximum temperature of the ProductType",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "POST Product",
+                "parameters": [
+                    {
+                        "description": "Product to be created",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.ProductPOSTRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Product created",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing field",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product created but not found in database, Seller or ProductType not found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Product code already exists",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Type casting error, or no recommended_freezing_temperature for a ProductType without temperature range",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Unknown or unhandled error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/products/reportMargins": {
+            "get": {
+                "description": "\"Retrieves the margin of every Product with a price at to (today by default), its change since from and the Products sold below their purchase price\"\n\"With group_by, also summarizes the margins per seller or product type\"",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ReportRecords"
+                ],
+                "summary": "GET the margins of the Products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start of the period, 2006-01-02",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the period, 2006-01-02",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "seller or product_type",
+                        "name": "group_by",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Margin report",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dates or grouping",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Unknown or unhandled error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/products/reportRecords": {
+            "get": {
+                "description": "Retrieves all ReportRecord or one ReportRecord by ID from database",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ReportRecords"
+                ],
+                "summary": "GET all ReportRecord or one ReportRecord by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Report Records",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Unknown or unhandled error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/products/{id}": {
+            "get": {
+                "description": "Retrieves one Product from database by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "GET Product by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Unknown or unhandled error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a Product from the database by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "DELETE Product by ID",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Unknown or unhandled error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Partially updates an existing Product on the database by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "PATCH Product by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Product to be updated",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.ProductPATCHRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product updated",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid field or ID",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product, Seller or ProductType not found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Product code already exists",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Type casting error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Unknown or unhandled error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/products/{id}/priceHistory": {
+            "get": {
+                "description": "\"Retrieves the purchase and sale prices of a Product ordered by date, between from and to when they are given\"\n\"With at, retrieves the ProductRecord in effect at that date instead, the one purchase orders take their price from\"",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ProductRecords"
+                ],
+                "summary": "GET the price history of a Product",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First date, 2006-01-02",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last date, 2006-01-02",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date of the price in effect, 2006-01-02",
+                        "name": "at",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Price history or price in effect",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or dates",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found or without a price at the date",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Unknown or unhandled error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/provinces": {
+            "get": {
+                "description": "get every province, or the provinces of a country",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Provinces"
+                ],
+                "summary": "List provinces",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "country id",
+                        "name": "country_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create a province of a country, names are unique without case inside a country",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Provinces"
+                ],
+                "summary": "Create Province",
+                "parameters": [
+                    {
+                        "description": "Province to create",
+                        "name": "province",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.ProvincePostRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/provinces/{id}": {
+            "get": {
+                "description": "get province with its country",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Provinces"
+                ],
+                "summary": "Province by id",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "province id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "delete a province without localities",
+                "tags": [
+                    "Provinces"
+                ],
+                "summary": "Delete Province",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "province id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "rename a province or move it to another country",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Provinces"
+                ],
+                "summary": "Update Province",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "province id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "province",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.ProvincePatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/provinces/{id}/reportCarries": {
+            "get": {
+                "description": "get the carries of every locality of a province and their total",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Provinces"
+                ],
+                "summary": "Province carries report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "province id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/provinces/{id}/reportSellers": {
+            "get": {
+                "description": "get the sellers of every locality of a province and their total",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Provinces"
+                ],
+                "summary": "Province sellers report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "province id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/purchase_orders": {
+            "get": {
+                "description": "get the Purchase_Order with their status, buyer and product record, the most recent first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase_Order"
+                ],
+                "summary": "List Purchase_Order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "buyer id",
+                        "name": "buyer_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "status name",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "minimum order date (yyyy-mm-dd)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "maximum order date (yyyy-mm-dd)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create Purchase_Order, either for a single product_record_id or for a list of items priced at the current sale price of each product.\nThe stock of every item is held until the order is confirmed or the reservation TTL ends.\nAn order with both items and a product_record_id is rejected with 400.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase_Order"
+                ],
+                "summary": "Create Purchase_Order",
+                "parameters": [
+                    {
+                        "description": "Purchase_Order to store",
+                        "name": "purchase_order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.RequestPurchaseOrdersPost"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/purchase_orders/{id}": {
+            "get": {
+                "description": "get a Purchase_Order with its status, buyer and product record",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase_Order"
+                ],
+                "summary": "Get Purchase_Order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase_Order id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/purchase_orders/{id}/reservations": {
+            "get": {
+                "description": "get the product batches and quantities reserved for a Purchase_Order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase_Order"
+                ],
+                "summary": "List Purchase_Order stock reservations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase_Order id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/purchase_orders/{id}/status": {
+            "get": {
+                "description": "get every status change of a Purchase_Order, the oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase_Order"
+                ],
+                "summary": "List Purchase_Order status history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase_Order id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "move a Purchase_Order to a new status (pending, confirmed, picking, shipped, delivered or cancelled) and record who did it.\nConfirming makes the stock reservations firm, cancelling releases them and shipping takes the stock out of the batches.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase_Order"
+                ],
+                "summary": "Update Purchase_Order status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase_Order id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "new status and actor",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.RequestPurchaseOrderStatusPatch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/reportPurchaseOrder": {
+            "get": {
+                "description": "get all Purchase_Order by buyer_id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Create Purchase_Order"
+                ],
+                "summary": "List Purchase_Order",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sellers": {
+            "get": {
+                "description": "get sellers",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sellers"
+                ],
+                "summary": "List sellers",
+                "responses": {
+                    "200": {
+                        "description": "Get sellers",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sellers"
+                ],
+                "summary": "Create Seller",
+                "parameters": [
+                    {
+                        "description": "Seller to Create",
+                        "name": "seller",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.SellerPostRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "New seller",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "BadRequest",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "UnprocessableEntity",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sellers/reportPerformance": {
+            "get": {
+                "description": "get per seller the products count, the stock on hand of their batches that can still be picked and the units sold\nand revenue of the purchase orders of the period that were not cancelled, with or without order items",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sellers"
+                ],
+                "summary": "Performance of the sellers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start of the period, 2006-01-02",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the period, 2006-01-02",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Performance of the sellers",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dates",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sellers/{id}": {
+            "get": {
+                "description": "get seller",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sellers"
+                ],
+                "summary": "Seller by id",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "seller id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Get seller",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "BadRequest",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "block fails while the seller has products, reassign moves them to the seller to and detach leaves them without seller",
+                "tags": [
+                    "Sellers"
+                ],
+                "summary": "Delete seller",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "seller id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "block (default), reassign or detach",
+                        "name": "strategy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "seller that receives the products on reassign",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "BadRequest",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The seller has products",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "The seller to reassign to does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sellers"
+                ],
+                "summary": "Update",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "seller id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "seller",
+                        "name": "seller",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.SellerPatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Seller",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "BadRequest",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "NotFound",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "UnprocessableEntity",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sellers/{id}/deletePreview": {
+            "get": {
+                "description": "get the products a delete of the seller affects and the strategies it can be deleted with",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sellers"
+                ],
+                "summary": "Preview the delete of a seller",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "seller id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Products of the seller",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "BadRequest",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sellers/{id}/products": {
+            "get": {
+                "description": "get the products the seller sells",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sellers"
+                ],
+                "summary": "Products of a seller",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "seller id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Products of the seller",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "BadRequest",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/shipments/{id}": {
+            "get": {
+                "description": "get a shipment with its orders, tracking code and timestamps",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shipments"
+                ],
+                "summary": "Get shipment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "shipment id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/shipments/{id}/deliver": {
+            "post": {
+                "description": "record that a dispatched shipment arrived and move every order to delivered, the cancelled orders are left out",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shipments"
+                ],
+                "summary": "Deliver shipment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "shipment id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "who delivered the shipment",
+                        "name": "event",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.RequestShipmentEvent"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/shipments/{id}/dispatch": {
+            "post": {
+                "description": "record that the shipment left and move every order to shipped, the cancelled orders are left out",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shipments"
+                ],
+                "summary": "Dispatch shipment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "shipment id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "who dispatched the shipment",
+                        "name": "event",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.RequestShipmentEvent"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/warehouses": {
+            "get": {
+                "description": "get warehouses",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Warehouses"
+                ],
+                "summary": "List warehouses",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create warehouse",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Warehouses"
+                ],
+                "summary": "Create warehouse",
+                "parameters": [
+                    {
+                        "description": "Warehouse to store",
+                        "name": "warehouse",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.WarehousePostRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/warehouses/{id}": {
+            "get": {
+                "description": "get warehouse by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Warehouses"
+                ],
+                "summary": "Get warehouse by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "warehouse id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "delete warehouse, block fails while sections, employees or inbound orders reference it\nand reassign moves them to the warehouse to",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Warehouses"
+                ],
+                "summary": "Delete warehouse",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "warehouse id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "block (default) or reassign",
+                        "name": "strategy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "warehouse that receives the dependents on reassign",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "update warehouse",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Warehouses"
+                ],
+                "summary": "Update warehouse",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "warehouse id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Warehouse to update",
+                        "name": "warehouse",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.WarehousePatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/warehouses/{id}/deletePreview": {
+            "get": {
+                "description": "get the sections, employees and inbound orders a delete of the warehouse affects",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Warehouses"
+                ],
+                "summary": "Preview the delete of a warehouse",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "warehouse id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/warehouses/{id}/reportExpiring": {
+            "get": {
+                "description": "get the batches of the warehouse that expire within the given days (7 by default), grouped by section and product.\nuse format=csv or an Accept: text/csv header to get one row per batch as CSV",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "Warehouses"
+                ],
+                "summary": "Expiring stock report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "warehouse id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "days from today",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json or csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/productBatches": {
+            "get": {
+                "description": "get product batches, ordered by due date",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ProductBatches"
+                ],
+                "summary": "List product batches",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "product id",
+                        "name": "product_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "section id",
+                        "name": "section_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "minimum due date (yyyy-mm-dd)",
+                        "name": "due_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "maximum due date (yyyy-mm-dd)",
+                        "name": "due_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only expired batches",
+                        "name": "expired",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create product batch",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ProductBatches"
+                ],
+                "summary": "Create product batch",
+                "parameters": [
+                    {
+                        "description": "Product batch to store",
+                        "name": "productBatch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.PostProductBatch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/productBatches/pick": {
+            "post": {
+                "description": "take stock of a product from its batches in first-expired-first-out order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ProductBatches"
+                ],
+                "summary": "Pick product stock",
+                "parameters": [
+                    {
+                        "description": "Product and quantity to pick",
+                        "name": "pick",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.PickProductBatch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/productBatches/{id}": {
+            "get": {
+                "description": "get product batch by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ProductBatches"
+                ],
+                "summary": "Get product batch by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "product batch id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "delete product batch",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ProductBatches"
+                ],
+                "summary": "Delete product batch",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "product batch id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "update product batch",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ProductBatches"
+                ],
+                "summary": "Update product batch",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "product batch id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated product batch",
+                        "name": "productBatch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.PatchProductBatch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sections": {
+            "get": {
+                "description": "get sections",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sections"
+                ],
+                "summary": "List sections",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create section, current_temperature and minimum_temperature default to the warmest and coldest temperature\nof the range of the product type and are required when the product type has no range,\ncurrent_capacity is kept by the product batches of the section and starts at 0",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sections"
+                ],
+                "summary": "Create section",
+                "parameters": [
+                    {
+                        "description": "Section to store",
+                        "name": "section",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.PostSection"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sections/reportProducts": {
+            "get": {
+                "description": "get products by section",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sections"
+                ],
+                "summary": "Get products by section",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "section id",
+                        "name": "id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sections/temperatures": {
+            "post": {
+                "description": "store a batch of sensor readings, the current temperature of each section is taken from its latest reading",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sections"
+                ],
+                "summary": "Store section temperature readings",
+                "parameters": [
+                    {
+                        "description": "Readings to store",
+                        "name": "readings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.PostSectionTemperatures"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sections/{id}": {
+            "get": {
+                "description": "get section by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sections"
+                ],
+                "summary": "Get section by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "section id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "delete section, with strategy=block (default) the delete fails while batches, excursions or temperature readings reference it,\nwith strategy=reassign\u0026to=\u003cid\u003e the batches and the current capacity move to a section of the same product type first.\nTemperature readings and excursions are never reassigned and block the delete, and the moved batches whose products\ndo not fit the temperatures of the target are rejected with 409 unless its warehouse temperature policy is warn",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sections"
+                ],
+                "summary": "Delete section",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "section id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "block or reassign",
+                        "name": "strategy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "section to reassign to",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
+                    "200": {
+                        "description": "Deleted, the moved batches that do not fit the target",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
                     "204": {
                         "description": "No Content"
                     },
@@ -1300,6 +5170,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/web.errorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1309,7 +5191,7 @@ const docTemplate = `{
                 }
             },
             "patch": {
-                "description": "update section",
+                "description": "update section, the stored batches whose products do not fit a new temperature are listed as affected_batches\nand the change is rejected with 409 unless the warehouse temperature policy is warn\ncurrent_capacity is kept by the product batches of the section, a body that sets it is rejected with 400\nproduct_type_id can not change while the section stores batches of another product type, 409",
                 "produces": [
                     "application/json"
                 ],
@@ -1361,9 +5243,212 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "/sections/{id}/deletePreview": {
+            "get": {
+                "description": "list the batches, excursions and temperature readings a delete of the section affects and the strategies it accepts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sections"
+                ],
+                "summary": "Preview section delete",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "section id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sections/{id}/temperatures": {
+            "get": {
+                "description": "get the minimum, average and maximum temperature of a section per bucket, the last day in buckets of one hour by default",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sections"
+                ],
+                "summary": "Section temperature history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "section id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "start, yyyy-mm-dd or RFC3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "end (exclusive), yyyy-mm-dd or RFC3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "bucket duration, like 15m or 1h",
+                        "name": "bucket",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.errorResponse"
+                        }
+                    }
+                }
+            }
         }
     },
     "definitions": {
+        "domain.Locality": {
+            "type": "object",
+            "required": [
+                "id",
+                "locality_name"
+            ],
+            "properties": {
+                "country_id": {
+                    "type": "integer"
+                },
+                "country_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "locality_name": {
+                    "type": "string"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "province_id": {
+                    "type": "integer"
+                },
+                "province_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "requests.CarryPatchRequest": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "cid": {
+                    "type": "string"
+                },
+                "company_name": {
+                    "type": "string"
+                },
+                "locality_id": {
+                    "type": "string"
+                },
+                "telephone": {
+                    "type": "string"
+                }
+            }
+        },
+        "requests.CarryPostRequest": {
+            "type": "object",
+            "required": [
+                "address",
+                "cid",
+                "company_name",
+                "locality_id",
+                "telephone"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "cid": {
+                    "type": "string"
+                },
+                "company_name": {
+                    "type": "string"
+                },
+                "locality_id": {
+                    "type": "string"
+                },
+                "telephone": {
+                    "type": "string"
+                }
+            }
+        },
+        "requests.CountryRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
         "requests.EmployeeDTOPatch": {
             "type": "object",
             "properties": {
@@ -1378,25 +5463,149 @@ const docTemplate = `{
                 }
             }
         },
-        "requests.EmployeeDTOPost": {
+        "requests.EmployeeDTOPost": {
+            "type": "object",
+            "required": [
+                "card_number_id",
+                "first_name",
+                "last_name",
+                "warehouse_id"
+            ],
+            "properties": {
+                "card_number_id": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "warehouse_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "requests.InboundOrderDTOPATCH": {
+            "type": "object",
+            "properties": {
+                "employee_id": {
+                    "type": "integer"
+                },
+                "order_date": {
+                    "type": "string"
+                },
+                "order_number": {
+                    "type": "string"
+                },
+                "product_batch_id": {
+                    "type": "integer"
+                },
+                "warehouse_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "requests.InboundOrderDTOPOST": {
+            "type": "object",
+            "required": [
+                "employee_id",
+                "order_date",
+                "order_number",
+                "product_batch_id",
+                "warehouse_id"
+            ],
+            "properties": {
+                "employee_id": {
+                    "type": "integer"
+                },
+                "order_date": {
+                    "type": "string"
+                },
+                "order_number": {
+                    "type": "string"
+                },
+                "product_batch_id": {
+                    "type": "integer"
+                },
+                "warehouse_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "requests.InboundOrderReceiveDTOPOST": {
+            "type": "object",
+            "required": [
+                "employee_id",
+                "order_date",
+                "order_number",
+                "product_batch",
+                "warehouse_id"
+            ],
+            "properties": {
+                "employee_id": {
+                    "type": "integer"
+                },
+                "order_date": {
+                    "type": "string"
+                },
+                "order_number": {
+                    "type": "string"
+                },
+                "product_batch": {
+                    "$ref": "#/definitions/requests.ReceivedBatchDTOPOST"
+                },
+                "warehouse_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "requests.LocalityCoordinatesRequest": {
             "type": "object",
             "required": [
-                "card_number_id",
-                "first_name",
-                "last_name",
-                "warehouse_id"
+                "latitude",
+                "longitude"
             ],
             "properties": {
-                "card_number_id": {
-                    "type": "string"
+                "latitude": {
+                    "type": "number"
                 },
-                "first_name": {
+                "longitude": {
+                    "type": "number"
+                }
+            }
+        },
+        "requests.PatchProductBatch": {
+            "type": "object",
+            "properties": {
+                "batch_number": {
+                    "type": "integer"
+                },
+                "current_quantity": {
+                    "type": "integer"
+                },
+                "current_temperature": {
+                    "type": "integer"
+                },
+                "due_date": {
                     "type": "string"
                 },
-                "last_name": {
+                "initial_quantity": {
+                    "type": "integer"
+                },
+                "manufacturing_date": {
                     "type": "string"
                 },
-                "warehouse_id": {
+                "manufacturing_hour": {
+                    "type": "integer"
+                },
+                "minimum_temperature": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "section_id": {
                     "type": "integer"
                 }
             }
@@ -1430,22 +5639,78 @@ const docTemplate = `{
                 }
             }
         },
-        "requests.PostSection": {
+        "requests.PickProductBatch": {
+            "type": "object",
+            "required": [
+                "product_id",
+                "quantity"
+            ],
+            "properties": {
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "requests.PostProductBatch": {
             "type": "object",
             "required": [
-                "current_capacity",
+                "batch_number",
+                "current_quantity",
                 "current_temperature",
+                "due_date",
+                "initial_quantity",
+                "manufacturing_date",
+                "manufacturing_hour",
+                "minimum_temperature",
+                "product_id",
+                "section_id"
+            ],
+            "properties": {
+                "batch_number": {
+                    "type": "integer"
+                },
+                "current_quantity": {
+                    "type": "integer"
+                },
+                "current_temperature": {
+                    "type": "integer"
+                },
+                "due_date": {
+                    "type": "string"
+                },
+                "initial_quantity": {
+                    "type": "integer"
+                },
+                "manufacturing_date": {
+                    "type": "string"
+                },
+                "manufacturing_hour": {
+                    "type": "integer"
+                },
+                "minimum_temperature": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "section_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "requests.PostSection": {
+            "type": "object",
+            "required": [
                 "maximum_capacity",
                 "minimum_capacity",
-                "minimum_temperature",
                 "product_type_id",
                 "section_number",
                 "warehouse_id"
             ],
             "properties": {
-                "current_capacity": {
-                    "type": "integer"
-                },
                 "current_temperature": {
                     "type": "integer"
                 },
@@ -1469,6 +5734,20 @@ const docTemplate = `{
                 }
             }
         },
+        "requests.PostSectionTemperatures": {
+            "type": "object",
+            "required": [
+                "readings"
+            ],
+            "properties": {
+                "readings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/requests.SectionTemperatureReading"
+                    }
+                }
+            }
+        },
         "requests.ProductPATCHRequest": {
             "type": "object",
             "properties": {
@@ -1518,7 +5797,6 @@ const docTemplate = `{
                 "net_weight",
                 "product_code",
                 "product_type_id",
-                "recommended_freezing_temperature",
                 "width"
             ],
             "properties": {
@@ -1557,6 +5835,131 @@ const docTemplate = `{
                 }
             }
         },
+        "requests.ProductRecordPOSTRequest": {
+            "type": "object",
+            "required": [
+                "last_update_date",
+                "product_id",
+                "purchase_price",
+                "sale_price"
+            ],
+            "properties": {
+                "last_update_date": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "purchase_price": {
+                    "type": "number"
+                },
+                "sale_price": {
+                    "type": "number"
+                }
+            }
+        },
+        "requests.ProductTypePatchRequest": {
+            "type": "object",
+            "properties": {
+                "maximum_temperature": {
+                    "type": "number"
+                },
+                "minimum_temperature": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "requests.ProductTypeRequest": {
+            "type": "object",
+            "required": [
+                "maximum_temperature",
+                "minimum_temperature",
+                "name"
+            ],
+            "properties": {
+                "maximum_temperature": {
+                    "type": "number"
+                },
+                "minimum_temperature": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "requests.ProvincePatchRequest": {
+            "type": "object",
+            "properties": {
+                "country_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "requests.ProvincePostRequest": {
+            "type": "object",
+            "required": [
+                "country_id",
+                "name"
+            ],
+            "properties": {
+                "country_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "requests.ReceivedBatchDTOPOST": {
+            "type": "object",
+            "required": [
+                "batch_number",
+                "current_temperature",
+                "due_date",
+                "manufacturing_date",
+                "manufacturing_hour",
+                "minimum_temperature",
+                "product_id",
+                "quantity",
+                "section_id"
+            ],
+            "properties": {
+                "batch_number": {
+                    "type": "integer"
+                },
+                "current_temperature": {
+                    "type": "integer"
+                },
+                "due_date": {
+                    "type": "string"
+                },
+                "manufacturing_date": {
+                    "type": "string"
+                },
+                "manufacturing_hour": {
+                    "type": "integer"
+                },
+                "minimum_temperature": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "section_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "requests.RequestBuyerPatch": {
             "type": "object",
             "properties": {
@@ -1596,6 +5999,123 @@ const docTemplate = `{
                 }
             }
         },
+        "requests.RequestOrderItemPost": {
+            "type": "object",
+            "required": [
+                "product_id",
+                "quantity"
+            ],
+            "properties": {
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "requests.RequestPurchaseOrderStatusPatch": {
+            "type": "object",
+            "required": [
+                "actor",
+                "status"
+            ],
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "requests.RequestPurchaseOrdersPost": {
+            "type": "object",
+            "required": [
+                "buyer_id",
+                "order_date",
+                "order_number",
+                "tracking_code"
+            ],
+            "properties": {
+                "buyer_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "description": "Items replace product_record_id for orders of several products",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/requests.RequestOrderItemPost"
+                    }
+                },
+                "order_date": {
+                    "type": "string"
+                },
+                "order_number": {
+                    "type": "string"
+                },
+                "order_status_id": {
+                    "type": "integer"
+                },
+                "product_record_id": {
+                    "type": "integer"
+                },
+                "tracking_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "requests.RequestShipmentEvent": {
+            "type": "object",
+            "required": [
+                "actor"
+            ],
+            "properties": {
+                "actor": {
+                    "type": "string"
+                }
+            }
+        },
+        "requests.RequestShipmentPost": {
+            "type": "object",
+            "required": [
+                "purchase_order_ids"
+            ],
+            "properties": {
+                "purchase_order_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "tracking_code": {
+                    "description": "TrackingCode is generated when it is empty",
+                    "type": "string"
+                }
+            }
+        },
+        "requests.SectionTemperatureReading": {
+            "type": "object",
+            "required": [
+                "section_id",
+                "temperature",
+                "timestamp"
+            ],
+            "properties": {
+                "section_id": {
+                    "type": "integer"
+                },
+                "temperature": {
+                    "type": "number"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
         "requests.SellerPatchRequest": {
             "type": "object",
             "properties": {
@@ -1608,6 +6128,9 @@ const docTemplate = `{
                 "company_name": {
                     "type": "string"
                 },
+                "locality_id": {
+                    "type": "string"
+                },
                 "telephone": {
                     "type": "string"
                 }
@@ -1619,6 +6142,7 @@ const docTemplate = `{
                 "address",
                 "cid",
                 "company_name",
+                "locality_id",
                 "telephone"
             ],
             "properties": {
@@ -1631,6 +6155,9 @@ const docTemplate = `{
                 "company_name": {
                     "type": "string"
                 },
+                "locality_id": {
+                    "type": "string"
+                },
                 "telephone": {
                     "type": "string"
                 }
@@ -1642,6 +6169,9 @@ const docTemplate = `{
                 "address": {
                     "type": "string"
                 },
+                "locality_id": {
+                    "type": "string"
+                },
                 "minimum_capacity": {
                     "type": "integer"
                 },
@@ -1651,6 +6181,9 @@ const docTemplate = `{
                 "telephone": {
                     "type": "string"
                 },
+                "temperature_policy": {
+                    "type": "string"
+                },
                 "warehouse_code": {
                     "type": "string"
                 }
@@ -1669,6 +6202,9 @@ const docTemplate = `{
                 "address": {
                     "type": "string"
                 },
+                "locality_id": {
+                    "type": "string"
+                },
                 "minimum_capacity": {
                     "type": "integer"
                 },
@@ -1678,6 +6214,9 @@ const docTemplate = `{
                 "telephone": {
                     "type": "string"
                 },
+                "temperature_policy": {
+                    "type": "string"
+                },
                 "warehouse_code": {
                     "type": "string"
                 }
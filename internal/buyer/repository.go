@@ -7,25 +7,32 @@ import (
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+	"github.com/go-sql-driver/mysql"
 )
 
 // Errors
 var (
-	ErrNotFound      = errors.New("buyer not found")
-	ErrAlreadyExists = errors.New("card_number_id already exists")
-	ErrInternal      = errors.New("database internal error")
-	ErrDataLong      = errors.New("a field exceeds the maximum length")
+	ErrNotFound       = errors.New("buyer not found")
+	ErrAlreadyExists  = errors.New("card_number_id already exists")
+	ErrInternal       = errors.New("database internal error")
+	ErrDataLong       = errors.New("a field exceeds the maximum length")
+	ErrHasOrders      = errors.New("buyer still has purchase orders")
+	ErrTargetNotFound = errors.New("buyer to reassign the purchase orders to not found")
 )
 
 const (
-	GET_ALL_QUERY        = "SELECT * FROM buyers;"
-	GET_BY_ID_QUERY      = "SELECT * FROM buyers WHERE id = ?;"
-	EXISTS_QUERY         = "SELECT card_number_id FROM buyers WHERE card_number_id=?;"
-	INSERT_QUERY         = "INSERT INTO buyers(card_number_id,first_name,last_name) VALUES (?,?,?);"
-	UPDATE_QUERY         = "UPDATE buyers SET first_name=?, last_name=?, card_number_id=?  WHERE id=?;"
-	DELETE_QUERY         = "DELETE FROM buyers WHERE id = ?;"
-	MySqlNumberDataLong  = 1406
-	MySqlNumberDuplicate = 1062
+	GET_ALL_QUERY              = "SELECT * FROM buyers;"
+	GET_BY_ID_QUERY            = "SELECT * FROM buyers WHERE id = ?;"
+	EXISTS_QUERY               = "SELECT card_number_id FROM buyers WHERE card_number_id=?;"
+	INSERT_QUERY               = "INSERT INTO buyers(card_number_id,first_name,last_name) VALUES (?,?,?);"
+	UPDATE_QUERY               = "UPDATE buyers SET first_name=?, last_name=?, card_number_id=?  WHERE id=?;"
+	DELETE_QUERY               = "DELETE FROM buyers WHERE id = ?;"
+	LOCK_QUERY                 = "SELECT id FROM buyers WHERE id = ? FOR UPDATE;"
+	ORDER_IDS_QUERY            = "SELECT id FROM purchase_orders WHERE buyer_id = ? ORDER BY id;"
+	REASSIGN_ORDERS_QUERY      = "UPDATE purchase_orders SET buyer_id = ? WHERE buyer_id = ?;"
+	MySqlNumberDataLong        = 1406
+	MySqlNumberDuplicate       = 1062
+	MySqlNumberRowIsReferenced = 1451
 )
 
 // Repository encapsulates the storage of a buyer.
@@ -35,7 +42,10 @@ type Repository interface {
 	Exists(ctx context.Context, cardNumberID string) bool
 	Save(ctx context.Context, b domain.Buyer) (int, error)
 	Update(ctx context.Context, b domain.Buyer) error
-	Delete(ctx context.Context, id int) error
+	// Delete removes the buyer in a transaction, on reassign its purchase orders are moved to options.To first
+	Delete(ctx context.Context, id int, options domain.DeleteOptions) error
+	// GetDependents returns the ids of the purchase orders of the buyer
+	GetDependents(ctx context.Context, id int) (map[string][]int, error)
 }
 
 type repository struct {
@@ -134,29 +144,84 @@ func (r *repository) Update(ctx context.Context, b domain.Buyer) error {
 	return nil
 }
 
-func (r *repository) Delete(ctx context.Context, id int) error {
-	stmt, err := r.db.Prepare(DELETE_QUERY)
+func (r *repository) Delete(ctx context.Context, id int, options domain.DeleteOptions) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		logging.Log(err)
 		return err
 	}
 
-	res, err := stmt.Exec(id)
-	if err != nil {
-		logging.Log(err)
+	if err := deleteBuyer(ctx, tx, id, options); err != nil {
+		_ = tx.Rollback()
 		return err
 	}
 
-	affect, err := res.RowsAffected()
-	if err != nil {
+	if err := tx.Commit(); err != nil {
 		logging.Log(err)
 		return err
 	}
+	return nil
+}
 
-	if affect < 1 {
+// deleteBuyer locks the buyer, applies the strategy and deletes it inside the transaction
+func deleteBuyer(ctx context.Context, tx *sql.Tx, id int, options domain.DeleteOptions) error {
+	if err := lockBuyer(ctx, tx, id, ErrNotFound); err != nil {
+		return err
+	}
+
+	if options.Strategy == domain.DeleteReassign {
+		if err := lockBuyer(ctx, tx, options.To, ErrTargetNotFound); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, REASSIGN_ORDERS_QUERY, options.To, id); err != nil {
+			logging.Log(err)
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, DELETE_QUERY, id); err != nil {
 		logging.Log(err)
-		return ErrNotFound
+		if mysqlError, ok := err.(*mysql.MySQLError); ok && mysqlError.Number == MySqlNumberRowIsReferenced {
+			return ErrHasOrders
+		}
+		return err
 	}
+	return nil
+}
 
+// lockBuyer locks the buyer for the rest of the transaction, or returns notFound if it does not exist
+func lockBuyer(ctx context.Context, tx *sql.Tx, id int, notFound error) error {
+	var lockedID int
+	if err := tx.QueryRowContext(ctx, LOCK_QUERY, id).Scan(&lockedID); err != nil {
+		logging.Log(err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return notFound
+		}
+		return err
+	}
 	return nil
 }
+
+func (r *repository) GetDependents(ctx context.Context, id int) (map[string][]int, error) {
+	rows, err := r.db.QueryContext(ctx, ORDER_IDS_QUERY, id)
+	if err != nil {
+		logging.Log(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	orderIDs := []int{}
+	for rows.Next() {
+		var orderID int
+		if err := rows.Scan(&orderID); err != nil {
+			logging.Log(err)
+			return nil, err
+		}
+		orderIDs = append(orderIDs, orderID)
+	}
+	if err := rows.Err(); err != nil {
+		logging.Log(err)
+		return nil, err
+	}
+	return map[string][]int{"purchase_orders": orderIDs}, nil
+}
@@ -14,8 +14,9 @@ var (
 )
 
 type MockRepository struct {
-	Data []domain.Buyer
-	Err  error
+	Data   []domain.Buyer
+	Orders map[int][]int
+	Err    error
 }
 
 // GetAll returns a list od buyers or weird SQL errors
@@ -79,10 +80,31 @@ func (m *MockRepository) Update(ctx context.Context, b domain.Buyer) error {
 	return nil
 }
 
-// Delete returns only weird SQL errors or nil
-func (m *MockRepository) Delete(ctx context.Context, id int) error {
+// Delete returns weird SQL errors, ErrHasOrders if the buyer has orders to block
+// or ErrTargetNotFound if the buyer to reassign them to doesn't exist
+func (m *MockRepository) Delete(ctx context.Context, id int, options domain.DeleteOptions) error {
 	if m.Err != nil {
 		return m.Err
 	}
+	if options.Strategy == domain.DeleteReassign {
+		if _, err := m.Get(ctx, options.To); err != nil {
+			return ErrTargetNotFound
+		}
+		if len(m.Orders[id]) > 0 {
+			m.Orders[options.To] = append(m.Orders[options.To], m.Orders[id]...)
+			delete(m.Orders, id)
+		}
+	}
+	if len(m.Orders[id]) > 0 {
+		return ErrHasOrders
+	}
 	return nil
 }
+
+// GetDependents returns the orders of the buyer or weird SQL errors
+func (m *MockRepository) GetDependents(ctx context.Context, id int) (map[string][]int, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return map[string][]int{"purchase_orders": m.Orders[id]}, nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -371,77 +372,157 @@ func TestUpdateBuyerFailRowsAffected(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-// TestSaveBuyerSuccess passes when return nil and buyer´s deleted
+// TestDeleteBuyerSuccess passes when return nil and buyer´s deleted
 func TestDeleteBuyerSuccess(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
 	Buyer_Id := 1
-	mock.ExpectPrepare(regexp.QuoteMeta(DELETE_QUERY)).ExpectExec().WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_QUERY)).WithArgs(Buyer_Id).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(Buyer_Id))
+	mock.ExpectExec(regexp.QuoteMeta(DELETE_QUERY)).WithArgs(Buyer_Id).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
 	defer cancel()
 
 	repo := NewRepository(db)
-	err = repo.Delete(ctx, Buyer_Id)
+	err = repo.Delete(ctx, Buyer_Id, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 
 	assert.Empty(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-// TestDeleteBuyerFailPrepare passes when function Prepare returns an error
-func TestDeleteBuyerFailPrepare(t *testing.T) {
+// TestDeleteBuyerFailBegin passes when the transaction can´t be started
+func TestDeleteBuyerFailBegin(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
 	Buyer_Id := 1
-	mock.ExpectPrepare(regexp.QuoteMeta(DELETE_QUERY)).WillReturnError(ErrInternal)
+	mock.ExpectBegin().WillReturnError(ErrInternal)
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
 	defer cancel()
 
 	repo := NewRepository(db)
-	err = repo.Delete(ctx, Buyer_Id)
+	err = repo.Delete(ctx, Buyer_Id, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 
 	assert.NotEmpty(t, err)
 	assert.EqualError(t, ErrInternal, err.Error())
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-// TestDeleteBuyerFailExecQuery passes when return an error "data base internal error"
-func TestDeleteBuyerFailExecQuery(t *testing.T) {
+// TestDeleteBuyerFailNotFound passes when return an error "buyer not found"
+func TestDeleteBuyerFailNotFound(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
 	Buyer_Id := 1
-	mock.ExpectPrepare(regexp.QuoteMeta(DELETE_QUERY)).ExpectExec().WillReturnError(ErrInternal)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_QUERY)).WithArgs(Buyer_Id).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
 	defer cancel()
 
 	repo := NewRepository(db)
-	err = repo.Delete(ctx, Buyer_Id)
+	err = repo.Delete(ctx, Buyer_Id, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 
-	assert.NotEmpty(t, err)
-	assert.EqualError(t, ErrInternal, err.Error())
+	assert.EqualError(t, ErrNotFound, err.Error())
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-// TestDeleteBuyerFailRowsAffected passes when function RowsAffected returns an error"
-func TestDeleteBuyerFailRowsAffected(t *testing.T) {
+// TestDeleteBuyerFailExecQuery passes when return an error "data base internal error"
+func TestDeleteBuyerFailExecQuery(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
 	Buyer_Id := 1
-	mock.ExpectPrepare(regexp.QuoteMeta(DELETE_QUERY)).ExpectExec().WillReturnResult(sqlmock.NewErrorResult(ErrInternal))
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_QUERY)).WithArgs(Buyer_Id).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(Buyer_Id))
+	mock.ExpectExec(regexp.QuoteMeta(DELETE_QUERY)).WillReturnError(ErrInternal)
+	mock.ExpectRollback()
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
 	defer cancel()
 
 	repo := NewRepository(db)
-	err = repo.Delete(ctx, Buyer_Id)
+	err = repo.Delete(ctx, Buyer_Id, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 
 	assert.NotEmpty(t, err)
 	assert.EqualError(t, ErrInternal, err.Error())
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+// TestDeleteBuyerFailHasOrders passes when the purchase orders of the buyer block the delete
+func TestDeleteBuyerFailHasOrders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	Buyer_Id := 1
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_QUERY)).WithArgs(Buyer_Id).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(Buyer_Id))
+	mock.ExpectExec(regexp.QuoteMeta(DELETE_QUERY)).WillReturnError(&mysql.MySQLError{Number: MySqlNumberRowIsReferenced})
+	mock.ExpectRollback()
+
+	repo := NewRepository(db)
+	err = repo.Delete(context.TODO(), Buyer_Id, domain.DeleteOptions{Strategy: domain.DeleteBlock})
+
+	assert.EqualError(t, ErrHasOrders, err.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestDeleteBuyerReassignSuccess passes when the orders are moved before the buyer´s deleted
+func TestDeleteBuyerReassignSuccess(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_QUERY)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_QUERY)).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectExec(regexp.QuoteMeta(REASSIGN_ORDERS_QUERY)).WithArgs(2, 1).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta(DELETE_QUERY)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := NewRepository(db)
+	err = repo.Delete(context.TODO(), 1, domain.DeleteOptions{Strategy: domain.DeleteReassign, To: 2})
+
+	assert.Empty(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestDeleteBuyerReassignFailTargetNotFound passes when the buyer to reassign to doesn´t exist
+func TestDeleteBuyerReassignFailTargetNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_QUERY)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_QUERY)).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	repo := NewRepository(db)
+	err = repo.Delete(context.TODO(), 1, domain.DeleteOptions{Strategy: domain.DeleteReassign, To: 2})
+
+	assert.EqualError(t, ErrTargetNotFound, err.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestGetDependentsSuccess passes when return the ids of the orders of the buyer
+func TestGetDependentsSuccess(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(ORDER_IDS_QUERY)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7).AddRow(8))
+
+	repo := NewRepository(db)
+	result, err := repo.GetDependents(context.TODO(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]int{"purchase_orders": {7, 8}}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,6 +2,7 @@ package buyer
 
 import (
 	"context"
+	"errors"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
@@ -13,9 +14,15 @@ type Service interface {
 	Exists(ctx context.Context, cardNumberID string) bool
 	Get(ctx context.Context, id int) (domain.Buyer, error)
 	Update(ctx context.Context, b domain.Buyer) (domain.Buyer, error)
-	Delete(ctx context.Context, id int) error
+	Delete(ctx context.Context, id int, options domain.DeleteOptions) error
+	DeletePreview(ctx context.Context, id int) (domain.DeletePreview, error)
 }
 
+var ErrInvalidStrategy = errors.New("strategy must be block, or reassign with the id of another buyer")
+
+// DeleteStrategies are the strategies a buyer can be deleted with
+var DeleteStrategies = []string{domain.DeleteBlock, domain.DeleteReassign}
+
 type service struct {
 	repository Repository
 }
@@ -100,7 +107,11 @@ func (s *service) Update(ctx context.Context, b domain.Buyer) (domain.Buyer, err
 
 // Delete returns an error if the deletion of the section failed
 // if a section with the given id doesn`t exist, an error is returned
-func (s *service) Delete(ctx context.Context, id int) error {
+func (s *service) Delete(ctx context.Context, id int, options domain.DeleteOptions) error {
+	if !options.Valid(id, DeleteStrategies) {
+		logging.Log(ErrInvalidStrategy)
+		return ErrInvalidStrategy
+	}
 	data, err := s.repository.Get(ctx, id)
 
 	if err != nil {
@@ -111,5 +122,18 @@ func (s *service) Delete(ctx context.Context, id int) error {
 		logging.Log(ErrNotFound)
 		return ErrNotFound
 	}
-	return s.repository.Delete(ctx, id)
+	return s.repository.Delete(ctx, id, options)
+}
+
+// DeletePreview returns the purchase orders a delete of the buyer affects, or an error if the buyer doesn`t exist
+func (s *service) DeletePreview(ctx context.Context, id int) (domain.DeletePreview, error) {
+	if _, err := s.repository.Get(ctx, id); err != nil {
+		logging.Log(err)
+		return domain.DeletePreview{}, err
+	}
+	dependents, err := s.repository.GetDependents(ctx, id)
+	if err != nil {
+		return domain.DeletePreview{}, err
+	}
+	return domain.NewDeletePreview(id, DeleteStrategies, dependents), nil
 }
@@ -270,7 +270,7 @@ func TestDeleteSuccess(t *testing.T) {
 	}
 	serv := NewService(&MockRepo)
 	ctx := new(context.Context)
-	err := serv.Delete(*ctx, id)
+	err := serv.Delete(*ctx, id, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 
 	//arrange
 	assert.Nil(t, err)
@@ -288,12 +288,78 @@ func TestDeleteFailIdNotExist(t *testing.T) {
 	}
 	serv := NewService(&MockRepo)
 	ctx := new(context.Context)
-	err := serv.Delete(*ctx, id)
+	err := serv.Delete(*ctx, id, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 
 	//arrange
 	assert.Equal(t, expectedError, err)
 }
 
+// TestDeleteReassignSuccess passes when the orders of the buyer are moved to the target
+// (return nil error)
+func TestDeleteReassignSuccess(t *testing.T) {
+	//arrange
+	MockRepo := MockRepository{
+		Data:   ListBuyers,
+		Orders: map[int][]int{1: {7, 8}, 2: {5}},
+	}
+	serv := NewService(&MockRepo)
+	//Act
+	err := serv.Delete(context.TODO(), 1, domain.DeleteOptions{Strategy: domain.DeleteReassign, To: 2})
+
+	//arrange
+	assert.Nil(t, err)
+	assert.Equal(t, map[int][]int{2: {5, 7, 8}}, MockRepo.Orders)
+}
+
+// TestDeleteFailHasOrders passes when the buyer has orders and the strategy is block
+// (return error buyer.ErrHasOrders)
+func TestDeleteFailHasOrders(t *testing.T) {
+	//arrange
+	MockRepo := MockRepository{
+		Data:   ListBuyers,
+		Orders: map[int][]int{1: {7}},
+	}
+	serv := NewService(&MockRepo)
+	//Act
+	err := serv.Delete(context.TODO(), 1, domain.DeleteOptions{Strategy: domain.DeleteBlock})
+
+	//arrange
+	assert.Equal(t, ErrHasOrders, err)
+}
+
+// TestDeleteFailInvalidStrategy passes when the strategy can´t be used to delete a buyer
+// (return error buyer.ErrInvalidStrategy)
+func TestDeleteFailInvalidStrategy(t *testing.T) {
+	//arrange
+	MockRepo := MockRepository{
+		Data: ListBuyers,
+	}
+	serv := NewService(&MockRepo)
+	//Act
+	for _, options := range []domain.DeleteOptions{{Strategy: domain.DeleteDetach}, {Strategy: domain.DeleteReassign, To: 1}} {
+		err := serv.Delete(context.TODO(), 1, options)
+
+		//arrange
+		assert.Equal(t, ErrInvalidStrategy, err)
+	}
+}
+
+// TestDeletePreviewSuccess passes when the preview lists the orders of the buyer
+// (return domain.DeletePreview and error nil)
+func TestDeletePreviewSuccess(t *testing.T) {
+	//arrange
+	MockRepo := MockRepository{
+		Data: ListBuyers,
+	}
+	serv := NewService(&MockRepo)
+	//Act
+	result, err := serv.DeletePreview(context.TODO(), 1)
+
+	//arrange
+	assert.Nil(t, err)
+	assert.Equal(t, domain.DeletePreview{ID: 1, Strategies: DeleteStrategies, Dependents: map[string][]int{"purchase_orders": {}}}, result)
+}
+
 // TestUpdateSuccess passes when data is correct
 // (return updated domain.Buyer and error nil)
 func TestUpdateSuccess(t *testing.T) {
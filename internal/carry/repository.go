@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
@@ -17,6 +18,9 @@ var (
 	ErrBodyValidation = errors.New("invalid request body")
 	ErrFKConstraint   = errors.New("a column table constraint fails")
 	ErrDataLong       = errors.New("a field exceeds the maximum length")
+	ErrNotFound       = errors.New("carry not found")
+	ErrBadRequest     = errors.New("invalid carry id")
+	ErrHasShipments   = errors.New("carry has shipments")
 )
 
 // Queries
@@ -26,11 +30,21 @@ const (
 	MySqlNumberFKConstraint = 1452
 	MySqlNumberDataLong     = 1406
 	MySqlNumberDuplicate    = 1062
+	MySqlNumberReferenced   = 1451
+	GET_CARRIES             = "SELECT c.id, c.cid, COALESCE(c.company_name, ''), COALESCE(c.address, ''), COALESCE(c.telephone, ''), COALESCE(c.locality_id, '') FROM carries AS c"
+	GET_CARRY               = GET_CARRIES + " WHERE c.id = ?;"
+	UPDATE_CARRY            = "UPDATE carries SET cid=?, company_name=?, address=?, telephone=?, locality_id=? WHERE id=?;"
+	DELETE_CARRY            = "DELETE FROM carries WHERE id=?;"
+	JOIN_CARRY_LOCALITY     = " INNER JOIN localities AS l ON l.id = c.locality_id INNER JOIN provinces AS p ON p.id = l.province_id INNER JOIN countries AS co ON co.id = p.country_id"
 )
 
 // Repository encapsulates the storage of a carry.
 type Repository interface {
 	Save(ctx context.Context, carry domain.Carry) (int, error)
+	GetAll(ctx context.Context, filter domain.CarryFilter) ([]domain.Carry, error)
+	Get(ctx context.Context, id int) (domain.Carry, error)
+	Update(ctx context.Context, carry domain.Carry) error
+	Delete(ctx context.Context, id int) error
 }
 
 type repository struct {
@@ -52,22 +66,7 @@ func (r *repository) Save(ctx context.Context, carry domain.Carry) (int, error)
 
 	res, err := stmt.Exec(&carry.CID, &carry.CompanyName, &carry.Address, &carry.Telephone, &carry.Locality_id)
 	if err != nil {
-		mysqlError, ok := err.(*mysql.MySQLError)
-		if ok {
-			switch mysqlError.Number {
-			case MySqlNumberFKConstraint:
-				logging.Log(ErrFKConstraint)
-				return 0, ErrFKConstraint
-			case MySqlNumberDataLong:
-				logging.Log(ErrDataLong)
-				return 0, ErrDataLong
-			case MySqlNumberDuplicate:
-				logging.Log(ErrAlreadyExists)
-				return 0, ErrAlreadyExists
-			}
-		}
-		logging.Log(ErrInternal)
-		return 0, ErrInternal
+		return 0, parseWriteError(err)
 	}
 
 	id, err := res.LastInsertId()
@@ -78,3 +77,124 @@ func (r *repository) Save(ctx context.Context, carry domain.Carry) (int, error)
 
 	return int(id), nil
 }
+
+// GetAll returns the carries that match the filter, the province and the country are looked up in the locality of each carry
+func (r *repository) GetAll(ctx context.Context, filter domain.CarryFilter) ([]domain.Carry, error) {
+	query, args := buildGetAllQuery(filter)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+
+	var carries []domain.Carry
+	for rows.Next() {
+		var carry domain.Carry
+		if err := rows.Scan(&carry.ID, &carry.CID, &carry.CompanyName, &carry.Address, &carry.Telephone, &carry.Locality_id); err != nil {
+			logging.Log(err)
+			return nil, ErrInternal
+		}
+		carries = append(carries, carry)
+	}
+	if err := rows.Err(); err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+
+	return carries, nil
+}
+
+// Get returns the carry with the given id, or ErrNotFound
+func (r *repository) Get(ctx context.Context, id int) (domain.Carry, error) {
+	var carry domain.Carry
+	err := r.db.QueryRowContext(ctx, GET_CARRY, id).Scan(&carry.ID, &carry.CID, &carry.CompanyName, &carry.Address, &carry.Telephone, &carry.Locality_id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logging.Log(ErrNotFound)
+			return domain.Carry{}, ErrNotFound
+		}
+		logging.Log(err)
+		return domain.Carry{}, ErrInternal
+	}
+	return carry, nil
+}
+
+// Update stores every field of the carry, or returns ErrAlreadyExists if its cid belongs to another carry
+func (r *repository) Update(ctx context.Context, carry domain.Carry) error {
+	_, err := r.db.ExecContext(ctx, UPDATE_CARRY, carry.CID, carry.CompanyName, carry.Address, carry.Telephone, carry.Locality_id, carry.ID)
+	if err != nil {
+		return parseWriteError(err)
+	}
+	return nil
+}
+
+// Delete removes the carry with the given id, or returns ErrNotFound. Carries with shipments can not be removed
+func (r *repository) Delete(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, DELETE_CARRY, id)
+	if err != nil {
+		if mysqlError, ok := err.(*mysql.MySQLError); ok && mysqlError.Number == MySqlNumberReferenced {
+			logging.Log(ErrHasShipments)
+			return ErrHasShipments
+		}
+		logging.Log(err)
+		return ErrInternal
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		logging.Log(err)
+		return ErrInternal
+	}
+	if affected == 0 {
+		logging.Log(ErrNotFound)
+		return ErrNotFound
+	}
+	return nil
+}
+
+// buildGetAllQuery appends to GET_CARRIES a condition for every filter that is set,
+// joining the localities only when the province or the country are filtered
+func buildGetAllQuery(filter domain.CarryFilter) (string, []interface{}) {
+	query := GET_CARRIES
+	var conditions []string
+	var args []interface{}
+	if filter.Province != "" || filter.Country != "" {
+		query += JOIN_CARRY_LOCALITY
+	}
+	if filter.LocalityID != "" {
+		conditions = append(conditions, "c.locality_id = ?")
+		args = append(args, filter.LocalityID)
+	}
+	if filter.Province != "" {
+		conditions = append(conditions, "p.name = ?")
+		args = append(args, filter.Province)
+	}
+	if filter.Country != "" {
+		conditions = append(conditions, "co.name = ?")
+		args = append(args, filter.Country)
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	return query + " ORDER BY c.id;", args
+}
+
+// parseWriteError translates the mysql errors of an insert or update into the errors of the package
+func parseWriteError(err error) error {
+	mysqlError, ok := err.(*mysql.MySQLError)
+	if ok {
+		switch mysqlError.Number {
+		case MySqlNumberFKConstraint:
+			logging.Log(ErrFKConstraint)
+			return ErrFKConstraint
+		case MySqlNumberDataLong:
+			logging.Log(ErrDataLong)
+			return ErrDataLong
+		case MySqlNumberDuplicate:
+			logging.Log(ErrAlreadyExists)
+			return ErrAlreadyExists
+		}
+	}
+	logging.Log(ErrInternal)
+	return ErrInternal
+}
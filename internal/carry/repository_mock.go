@@ -7,7 +7,9 @@ import (
 )
 
 type MockRepo struct {
-	mockError error
+	mockError  error
+	data       []domain.Carry
+	lastFilter domain.CarryFilter
 }
 
 func (r *MockRepo) Save(ctx context.Context, carry domain.Carry) (int, error) {
@@ -16,3 +18,45 @@ func (r *MockRepo) Save(ctx context.Context, carry domain.Carry) (int, error) {
 	}
 	return 1, nil
 }
+
+func (r *MockRepo) GetAll(ctx context.Context, filter domain.CarryFilter) ([]domain.Carry, error) {
+	if r.mockError != nil {
+		return nil, r.mockError
+	}
+	r.lastFilter = filter
+	return r.data, nil
+}
+
+func (r *MockRepo) Get(ctx context.Context, id int) (domain.Carry, error) {
+	for _, carry := range r.data {
+		if carry.ID == id {
+			return carry, nil
+		}
+	}
+	return domain.Carry{}, ErrNotFound
+}
+
+func (r *MockRepo) Update(ctx context.Context, carry domain.Carry) error {
+	if r.mockError != nil {
+		return r.mockError
+	}
+	for i := range r.data {
+		if r.data[i].ID == carry.ID {
+			r.data[i] = carry
+		}
+	}
+	return nil
+}
+
+func (r *MockRepo) Delete(ctx context.Context, id int) error {
+	if r.mockError != nil {
+		return r.mockError
+	}
+	for i := range r.data {
+		if r.data[i].ID == id {
+			r.data = append(r.data[:i], r.data[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
@@ -236,3 +236,125 @@ func TestRepositorySaveFailLastID(t *testing.T) {
 	assert.Empty(t, newID)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+// TestRepositoryGetAllByProvince checks that the province filter joins the localities of the carries
+func TestRepositoryGetAllByProvince(t *testing.T) {
+	// Arrange
+	carry := domain.Carry{ID: 1, CID: "CID#1", CompanyName: "some name", Address: "corrientes 800", Telephone: "4567-4567", Locality_id: "1"}
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	columns := []string{"id", "cid", "company_name", "address", "telephone", "locality_id"}
+	rows := sqlmock.NewRows(columns).AddRow(carry.ID, carry.CID, carry.CompanyName, carry.Address, carry.Telephone, carry.Locality_id)
+	query := GET_CARRIES + JOIN_CARRY_LOCALITY + " WHERE p.name = ? AND co.name = ? ORDER BY c.id;"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("Buenos Aires", "Argentina").WillReturnRows(rows)
+
+	// Act
+	repository := NewRepository(db)
+	result, err := repository.GetAll(context.TODO(), domain.CarryFilter{Province: "Buenos Aires", Country: "Argentina"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Carry{carry}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestRepositoryGetAllByLocality checks that the locality filter does not join the localities
+func TestRepositoryGetAllByLocality(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	columns := []string{"id", "cid", "company_name", "address", "telephone", "locality_id"}
+	query := GET_CARRIES + " WHERE c.locality_id = ? ORDER BY c.id;"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("1").WillReturnRows(sqlmock.NewRows(columns))
+
+	// Act
+	repository := NewRepository(db)
+	result, err := repository.GetAll(context.TODO(), domain.CarryFilter{LocalityID: "1"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestRepositoryGetNotFound is correct when the carry does not exist
+func TestRepositoryGetNotFound(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	columns := []string{"id", "cid", "company_name", "address", "telephone", "locality_id"}
+	mock.ExpectQuery(regexp.QuoteMeta(GET_CARRY)).WithArgs(9).WillReturnRows(sqlmock.NewRows(columns))
+
+	// Act
+	repository := NewRepository(db)
+	_, err = repository.Get(context.TODO(), 9)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestRepositoryUpdateAlreadyExists is correct when the new cid belongs to another carry
+func TestRepositoryUpdateAlreadyExists(t *testing.T) {
+	// Arrange
+	carry := domain.Carry{ID: 1, CID: "CID#2", CompanyName: "some name", Address: "corrientes 800", Telephone: "4567-4567", Locality_id: "1"}
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(UPDATE_CARRY)).
+		WithArgs(carry.CID, carry.CompanyName, carry.Address, carry.Telephone, carry.Locality_id, carry.ID).
+		WillReturnError(&mysql.MySQLError{Number: MySqlNumberDuplicate})
+
+	// Act
+	repository := NewRepository(db)
+	err = repository.Update(context.TODO(), carry)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestRepositoryDelete checks the correct operation of the Delete repository method
+func TestRepositoryDelete(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(DELETE_CARRY)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(DELETE_CARRY)).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	repository := NewRepository(db)
+	errDeleted := repository.Delete(context.TODO(), 1)
+	errMissing := repository.Delete(context.TODO(), 2)
+
+	// Assert
+	assert.NoError(t, errDeleted)
+	assert.ErrorIs(t, errMissing, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestRepositoryDeleteHasShipments is correct when the carry is referenced by its shipments
+func TestRepositoryDeleteHasShipments(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(DELETE_CARRY)).WithArgs(1).WillReturnError(&mysql.MySQLError{Number: MySqlNumberReferenced})
+
+	// Act
+	err = NewRepository(db).Delete(context.TODO(), 1)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrHasShipments)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -10,6 +10,10 @@ import (
 // Service provides the public methods of a carry service.
 type Service interface {
 	Save(ctx context.Context, CID string, CompanyName string, Address string, Telephone string, Locality_id string) (domain.Carry, error)
+	GetAll(ctx context.Context, filter domain.CarryFilter) ([]domain.Carry, error)
+	Get(ctx context.Context, id int) (domain.Carry, error)
+	Update(ctx context.Context, carry domain.Carry) (domain.Carry, error)
+	Delete(ctx context.Context, id int) error
 }
 
 type service struct {
@@ -42,3 +46,52 @@ func (s *service) Save(ctx context.Context, cid string, companyName string, addr
 	carry.ID = carryID
 	return carry, nil
 }
+
+// GetAll returns the carries serving the locality, province or country of the filter, or every carry if it is empty
+func (s *service) GetAll(ctx context.Context, filter domain.CarryFilter) ([]domain.Carry, error) {
+	carries, err := s.repository.GetAll(ctx, filter)
+	if err != nil {
+		logging.Log(err)
+		return nil, err
+	}
+	return carries, nil
+}
+
+// Get returns the carry with the given id, or ErrNotFound
+func (s *service) Get(ctx context.Context, id int) (domain.Carry, error) {
+	return s.repository.Get(ctx, id)
+}
+
+// Update returns the updated carry if successful.
+// only the non empty fields are changed, if the new cid belongs to another carry ErrAlreadyExists is returned.
+func (s *service) Update(ctx context.Context, carry domain.Carry) (domain.Carry, error) {
+	stored, err := s.repository.Get(ctx, carry.ID)
+	if err != nil {
+		return domain.Carry{}, err
+	}
+	if carry.CID != "" {
+		stored.CID = carry.CID
+	}
+	if carry.CompanyName != "" {
+		stored.CompanyName = carry.CompanyName
+	}
+	if carry.Address != "" {
+		stored.Address = carry.Address
+	}
+	if carry.Telephone != "" {
+		stored.Telephone = carry.Telephone
+	}
+	if carry.Locality_id != "" {
+		stored.Locality_id = carry.Locality_id
+	}
+	if err := s.repository.Update(ctx, stored); err != nil {
+		logging.Log(err)
+		return domain.Carry{}, err
+	}
+	return stored, nil
+}
+
+// Delete removes the carry with the given id, or returns ErrNotFound
+func (s *service) Delete(ctx context.Context, id int) error {
+	return s.repository.Delete(ctx, id)
+}
@@ -56,3 +56,62 @@ func TestSaveFailureSave(t *testing.T) {
 		assert.Equal(t, expectedError, err)
 	}
 }
+
+// TestGetAll checks that the filter reaches the repository
+func TestGetAll(t *testing.T) {
+	// arrange
+	carries := []domain.Carry{{ID: 1, CID: "CID#1", Locality_id: "1"}}
+	filter := domain.CarryFilter{Province: "Buenos Aires"}
+	mockRepo := MockRepo{data: carries}
+	service := NewService(&mockRepo)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	// act
+	result, err := service.GetAll(ctx, filter)
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, carries, result)
+	assert.Equal(t, filter, mockRepo.lastFilter)
+}
+
+// TestUpdate checks that only the non empty fields are changed
+func TestUpdate(t *testing.T) {
+	// arrange
+	stored := domain.Carry{ID: 1, CID: "CID#1", CompanyName: "some name", Address: "corrientes 800", Telephone: "4567-4567", Locality_id: "1"}
+	expected := stored
+	expected.Telephone = "1111-2222"
+	mockRepo := MockRepo{data: []domain.Carry{stored}}
+	service := NewService(&mockRepo)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	// act
+	result, err := service.Update(ctx, domain.Carry{ID: 1, Telephone: "1111-2222"})
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+	assert.Equal(t, expected, mockRepo.data[0])
+}
+
+// TestUpdateFailureNotFound is correct when the carry does not exist
+func TestUpdateFailureNotFound(t *testing.T) {
+	// arrange
+	mockRepo := MockRepo{}
+	service := NewService(&mockRepo)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	// act
+	_, err := service.Update(ctx, domain.Carry{ID: 1, CID: "CID#2"})
+	// assert
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+// TestDelete checks the correct operation of the Delete service method
+func TestDelete(t *testing.T) {
+	// arrange
+	mockRepo := MockRepo{data: []domain.Carry{{ID: 1}}}
+	service := NewService(&mockRepo)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	// act
+	err := service.Delete(ctx, 1)
+	errMissing := service.Delete(ctx, 1)
+	// assert
+	assert.NoError(t, err)
+	assert.ErrorIs(t, errMissing, ErrNotFound)
+}
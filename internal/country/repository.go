@@ -0,0 +1,133 @@
+package country
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/go-sql-driver/mysql"
+)
+
+// Errors
+var (
+	ErrNotFound      = errors.New("country not found")
+	ErrAlreadyExists = errors.New("country name already exists")
+	ErrHasProvinces  = errors.New("country has provinces")
+	ErrBadRequest    = errors.New("country name is required")
+	ErrInternal      = errors.New("database internal error")
+)
+
+// Repository encapsulates the storage of a Country.
+type Repository interface {
+	GetAll(ctx context.Context) ([]domain.Country, error)
+	Get(ctx context.Context, id int) (domain.Country, error)
+	Save(ctx context.Context, c domain.Country) (int, error)
+	Update(ctx context.Context, c domain.Country) error
+	Delete(ctx context.Context, id int) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+const (
+	GET_ALL_COUNTRIES     = "SELECT id, name FROM countries ORDER BY name;"
+	GET_COUNTRY           = "SELECT id, name FROM countries WHERE id=?;"
+	SAVE_COUNTRY          = "INSERT INTO countries (name) VALUES (?);"
+	UPDATE_COUNTRY        = "UPDATE countries SET name=? WHERE id=?;"
+	DELETE_COUNTRY        = "DELETE FROM countries WHERE id=?;"
+	MySqlNumberDuplicate  = 1062
+	MySqlNumberReferenced = 1451
+)
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) GetAll(ctx context.Context) ([]domain.Country, error) {
+	rows, err := r.db.QueryContext(ctx, GET_ALL_COUNTRIES)
+	if err != nil {
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+
+	countries := []domain.Country{}
+	for rows.Next() {
+		c := domain.Country{}
+		if err := rows.Scan(&c.ID, &c.Name); err != nil {
+			return nil, ErrInternal
+		}
+		countries = append(countries, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, ErrInternal
+	}
+	return countries, nil
+}
+
+func (r *repository) Get(ctx context.Context, id int) (domain.Country, error) {
+	c := domain.Country{}
+	err := r.db.QueryRowContext(ctx, GET_COUNTRY, id).Scan(&c.ID, &c.Name)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return domain.Country{}, ErrNotFound
+		default:
+			return domain.Country{}, ErrInternal
+		}
+	}
+	return c, nil
+}
+
+func (r *repository) Save(ctx context.Context, c domain.Country) (int, error) {
+	res, err := r.db.ExecContext(ctx, SAVE_COUNTRY, c.Name)
+	if err != nil {
+		return 0, parseError(err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, ErrInternal
+	}
+	return int(id), nil
+}
+
+func (r *repository) Update(ctx context.Context, c domain.Country) error {
+	if _, err := r.db.ExecContext(ctx, UPDATE_COUNTRY, c.Name, c.ID); err != nil {
+		return parseError(err)
+	}
+	return nil
+}
+
+func (r *repository) Delete(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, DELETE_COUNTRY, id)
+	if err != nil {
+		return parseError(err)
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return ErrInternal
+	}
+	if affect < 1 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// parseError translates the mysql errors of the countries table, names are unique without case
+// and a country can not be deleted while it has provinces
+func parseError(err error) error {
+	if mysqlError, ok := err.(*mysql.MySQLError); ok {
+		switch mysqlError.Number {
+		case MySqlNumberDuplicate:
+			return ErrAlreadyExists
+		case MySqlNumberReferenced:
+			return ErrHasProvinces
+		}
+	}
+	return ErrInternal
+}
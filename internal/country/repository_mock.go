@@ -0,0 +1,62 @@
+package country
+
+import (
+	"context"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+)
+
+type MockRepository struct {
+	Data      []domain.Country
+	ErrorMock error
+}
+
+func (m *MockRepository) GetAll(ctx context.Context) ([]domain.Country, error) {
+	if m.ErrorMock != nil {
+		return nil, m.ErrorMock
+	}
+	return m.Data, nil
+}
+
+func (m *MockRepository) Get(ctx context.Context, id int) (domain.Country, error) {
+	for _, c := range m.Data {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return domain.Country{}, ErrNotFound
+}
+
+func (m *MockRepository) Save(ctx context.Context, c domain.Country) (int, error) {
+	if m.ErrorMock != nil {
+		return 0, m.ErrorMock
+	}
+	c.ID = len(m.Data) + 1
+	m.Data = append(m.Data, c)
+	return c.ID, nil
+}
+
+func (m *MockRepository) Update(ctx context.Context, c domain.Country) error {
+	if m.ErrorMock != nil {
+		return m.ErrorMock
+	}
+	for i := range m.Data {
+		if m.Data[i].ID == c.ID {
+			m.Data[i] = c
+		}
+	}
+	return nil
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id int) error {
+	if m.ErrorMock != nil {
+		return m.ErrorMock
+	}
+	for i := range m.Data {
+		if m.Data[i].ID == id {
+			m.Data = append(m.Data[:i], m.Data[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
@@ -0,0 +1,82 @@
+package country
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetAll_OK passes when every country is returned
+func TestGetAll_OK(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Argentina").AddRow(2, "Chile")
+	mock.ExpectQuery(regexp.QuoteMeta(GET_ALL_COUNTRIES)).WillReturnRows(rows)
+
+	// Act
+	result, err := NewRepository(db).GetAll(context.TODO())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Country{{ID: 1, Name: "Argentina"}, {ID: 2, Name: "Chile"}}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestSave_FailAlreadyExists passes when the name exists with any case
+func TestSave_FailAlreadyExists(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(SAVE_COUNTRY)).WithArgs("argentina").WillReturnError(&mysql.MySQLError{Number: MySqlNumberDuplicate})
+
+	// Act
+	_, err = NewRepository(db).Save(context.TODO(), domain.Country{Name: "argentina"})
+
+	// Assert
+	assert.EqualError(t, err, ErrAlreadyExists.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestDelete_FailHasProvinces passes when the country is referenced by its provinces
+func TestDelete_FailHasProvinces(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(DELETE_COUNTRY)).WithArgs(1).WillReturnError(&mysql.MySQLError{Number: MySqlNumberReferenced})
+
+	// Act
+	err = NewRepository(db).Delete(context.TODO(), 1)
+
+	// Assert
+	assert.EqualError(t, err, ErrHasProvinces.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestDelete_FailNotFound passes when no country is deleted
+func TestDelete_FailNotFound(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(DELETE_COUNTRY)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err = NewRepository(db).Delete(context.TODO(), 1)
+
+	// Assert
+	assert.EqualError(t, err, ErrNotFound.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,78 @@
+package country
+
+import (
+	"context"
+	"strings"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+)
+
+// Service represents a service layer for Country
+type Service interface {
+	GetAll(ctx context.Context) ([]domain.Country, error)
+	Get(ctx context.Context, id int) (domain.Country, error)
+	Create(ctx context.Context, name string) (domain.Country, error)
+	Update(ctx context.Context, id int, name string) (domain.Country, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(r Repository) Service {
+	return &service{
+		repository: r,
+	}
+}
+
+func (s *service) GetAll(ctx context.Context) ([]domain.Country, error) {
+	return s.repository.GetAll(ctx)
+}
+
+func (s *service) Get(ctx context.Context, id int) (domain.Country, error) {
+	return s.repository.Get(ctx, id)
+}
+
+// Create saves a country with the trimmed name, or returns ErrAlreadyExists if the name exists with any case
+func (s *service) Create(ctx context.Context, name string) (domain.Country, error) {
+	c := domain.Country{Name: strings.TrimSpace(name)}
+	if c.Name == "" {
+		logging.Log(ErrBadRequest)
+		return domain.Country{}, ErrBadRequest
+	}
+
+	id, err := s.repository.Save(ctx, c)
+	if err != nil {
+		logging.Log(err)
+		return domain.Country{}, err
+	}
+	c.ID = id
+	return c, nil
+}
+
+// Update renames the country, every province and locality of the country follows the new name
+func (s *service) Update(ctx context.Context, id int, name string) (domain.Country, error) {
+	c, err := s.repository.Get(ctx, id)
+	if err != nil {
+		logging.Log(err)
+		return domain.Country{}, err
+	}
+	c.Name = strings.TrimSpace(name)
+	if c.Name == "" {
+		logging.Log(ErrBadRequest)
+		return domain.Country{}, ErrBadRequest
+	}
+
+	if err := s.repository.Update(ctx, c); err != nil {
+		logging.Log(err)
+		return domain.Country{}, err
+	}
+	return c, nil
+}
+
+// Delete removes a country without provinces
+func (s *service) Delete(ctx context.Context, id int) error {
+	return s.repository.Delete(ctx, id)
+}
@@ -0,0 +1,67 @@
+package country
+
+import (
+	"context"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	logging.InitLog(nil)
+}
+
+// TestCreate_OK passes when the country is stored with its trimmed name
+func TestCreate_OK(t *testing.T) {
+	// Arrange
+	mockRepo := MockRepository{}
+	service := NewService(&mockRepo)
+
+	// Act
+	result, err := service.Create(context.TODO(), "  Argentina ")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Country{ID: 1, Name: "Argentina"}, result)
+}
+
+// TestCreate_FailEmptyName passes when the name is blank
+func TestCreate_FailEmptyName(t *testing.T) {
+	// Arrange
+	service := NewService(&MockRepository{})
+
+	// Act
+	_, err := service.Create(context.TODO(), "  ")
+
+	// Assert
+	assert.EqualError(t, err, ErrBadRequest.Error())
+}
+
+// TestUpdate_OK passes when the country is renamed
+func TestUpdate_OK(t *testing.T) {
+	// Arrange
+	mockRepo := MockRepository{Data: []domain.Country{{ID: 1, Name: "argentina"}}}
+	service := NewService(&mockRepo)
+
+	// Act
+	result, err := service.Update(context.TODO(), 1, "Argentina")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Country{ID: 1, Name: "Argentina"}, result)
+	assert.Equal(t, "Argentina", mockRepo.Data[0].Name)
+}
+
+// TestUpdate_FailNotFound passes when the country does not exist
+func TestUpdate_FailNotFound(t *testing.T) {
+	// Arrange
+	service := NewService(&MockRepository{})
+
+	// Act
+	_, err := service.Update(context.TODO(), 1, "Argentina")
+
+	// Assert
+	assert.EqualError(t, err, ErrNotFound.Error())
+}
@@ -8,3 +8,11 @@ type Carry struct {
 	Telephone   string `json:"telephone"`
 	Locality_id string `json:"locality_id"`
 }
+
+// CarryFilter narrows the listed carries to the ones serving a locality, or every locality of a province or country.
+// Empty values are not applied
+type CarryFilter struct {
+	LocalityID string
+	Province   string
+	Country    string
+}
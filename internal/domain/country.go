@@ -0,0 +1,6 @@
+package domain
+
+type Country struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
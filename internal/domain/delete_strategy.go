@@ -0,0 +1,52 @@
+package domain
+
+// Delete strategies, what a delete does with the rows that reference the deleted entity
+const (
+	DeleteBlock    = "block"
+	DeleteReassign = "reassign"
+	DeleteDetach   = "detach"
+)
+
+// DeleteOptions choose the strategy of a delete. Block fails while any row references the entity,
+// reassign moves the rows to the entity with id To and detach leaves them without entity.
+type DeleteOptions struct {
+	Strategy string
+	To       int
+}
+
+// Valid tells if the strategy is one of the given ones and, on reassign, To is another entity than id
+func (o DeleteOptions) Valid(id int, strategies []string) bool {
+	for _, strategy := range strategies {
+		if strategy != o.Strategy {
+			continue
+		}
+		if o.Strategy == DeleteReassign {
+			return o.To > 0 && o.To != id
+		}
+		return true
+	}
+	return false
+}
+
+// DeletePreview lists, per referencing table, the ids of the rows the delete of an entity affects
+// and the strategies the entity can be deleted with. Blocked tells that the block strategy would fail.
+type DeletePreview struct {
+	ID         int              `json:"id"`
+	Strategies []string         `json:"strategies"`
+	Blocked    bool             `json:"blocked"`
+	Dependents map[string][]int `json:"dependents"`
+}
+
+// NewDeletePreview returns the preview of the delete of the entity with the given dependents
+func NewDeletePreview(id int, strategies []string, dependents map[string][]int) DeletePreview {
+	preview := DeletePreview{ID: id, Strategies: strategies, Dependents: dependents}
+	for table, ids := range dependents {
+		if ids == nil {
+			dependents[table] = []int{}
+		}
+		if len(ids) > 0 {
+			preview.Blocked = true
+		}
+	}
+	return preview
+}
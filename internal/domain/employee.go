@@ -12,3 +12,43 @@ type EmployeeWithInboundOrders struct {
 	Employee
 	InboundOrders int `json:"inbound_orders_count"`
 }
+
+// EmployeeDay is the work of an employee on a single day, received units are the initial quantities of the received batches
+type EmployeeDay struct {
+	Date          string `json:"date"`
+	InboundOrders int    `json:"inbound_orders_count"`
+	ReceivedUnits int    `json:"received_units"`
+}
+
+// EmployeeDailyInbound is the work of an employee on a single day, as read from the database
+type EmployeeDailyInbound struct {
+	Employee
+	Day EmployeeDay
+}
+
+// EmployeeProductivity sums the inbound orders and received units of an employee over a period, with a bucket per worked day
+type EmployeeProductivity struct {
+	Employee
+	InboundOrders int           `json:"inbound_orders_count"`
+	ReceivedUnits int           `json:"received_units"`
+	Days          []EmployeeDay `json:"days,omitempty"`
+}
+
+// ProductivityRanking lists the top employees of a period by inbound orders and by received units
+type ProductivityRanking struct {
+	ByInboundOrders []EmployeeProductivity `json:"by_inbound_orders"`
+	ByReceivedUnits []EmployeeProductivity `json:"by_received_units"`
+}
+
+// ProductivityFilter narrows the inbound orders counted in a productivity report, zero values are not applied.
+// From and To are inclusive order dates in yyyy-mm-dd format
+type ProductivityFilter struct {
+	From        string
+	To          string
+	WarehouseID int
+}
+
+// IsZero reports whether no filter is set
+func (f ProductivityFilter) IsZero() bool {
+	return f == ProductivityFilter{}
+}
@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// Excursion statuses used to filter the excursions
+const (
+	ExcursionOpen   = "open"
+	ExcursionClosed = "closed"
+)
+
+// Excursion is a period of time in which a section was warmer than a product batch stored in it allows.
+// It is still open while EndedAt is nil.
+type Excursion struct {
+	ID                 int        `json:"id"`
+	SectionID          int        `json:"section_id"`
+	ProductBatchID     int        `json:"product_batch_id"`
+	LimitTemperature   float32    `json:"limit_temperature"`
+	MaximumTemperature float32    `json:"maximum_temperature"`
+	StartedAt          time.Time  `json:"started_at"`
+	EndedAt            *time.Time `json:"ended_at"`
+}
+
+// BatchTemperatureLimit is the highest temperature a stored product batch allows, next to the current temperature of its section.
+type BatchTemperatureLimit struct {
+	ProductBatchID            int
+	SectionID                 int
+	SectionCurrentTemperature float32
+	LimitTemperature          float32
+}
+
+// Exceeded reports whether the section is warmer than the product batch allows
+func (l BatchTemperatureLimit) Exceeded() bool {
+	return l.SectionCurrentTemperature > l.LimitTemperature
+}
@@ -0,0 +1,26 @@
+package domain
+
+// Coordinates is a point on the earth in decimal degrees
+type Coordinates struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// ValidCoordinates reports whether the latitude is within [-90, 90] and the longitude within [-180, 180]
+func ValidCoordinates(latitude float64, longitude float64) bool {
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
+
+// WarehouseDistance is a warehouse with the coordinates of its locality and its distance to another point
+type WarehouseDistance struct {
+	Warehouse
+	Coordinates
+	DistanceKm float64 `json:"distance_km"`
+}
+
+// CarryDistance is a carry with the coordinates of its locality and its distance to another point
+type CarryDistance struct {
+	Carry
+	Coordinates
+	DistanceKm float64 `json:"distance_km"`
+}
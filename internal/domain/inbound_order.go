@@ -1,10 +1,30 @@
 package domain
 
+import "time"
+
 type InboundOrder struct {
 	ID             int
-	OrderDate      string `json:"order_date"`
-	OrderNumber    string `json:"order_number"`
-	EmployeeID     int    `json:"employee_id"`
-	ProductBatchID int    `json:"product_batch_id"`
-	WarehouseID    int    `json:"warehouse_id"`
+	OrderDate      string     `json:"order_date"`
+	OrderNumber    string     `json:"order_number"`
+	EmployeeID     int        `json:"employee_id"`
+	ProductBatchID int        `json:"product_batch_id"`
+	WarehouseID    int        `json:"warehouse_id"`
+	CancelledAt    *time.Time `json:"cancelled_at,omitempty"`
+}
+
+// InboundOrderFilter narrows the listed inbound orders, zero values are not applied.
+// From and To are inclusive order dates in yyyy-mm-dd format
+type InboundOrderFilter struct {
+	WarehouseID    int
+	EmployeeID     int
+	ProductBatchID int
+	From           string
+	To             string
+}
+
+// InboundReceipt is an inbound order together with the product batch received with it.
+// Both are created at once, the batch in a section of the warehouse of the order
+type InboundReceipt struct {
+	InboundOrder
+	ProductBatch ProductBatch `json:"product_batch"`
 }
@@ -1,8 +1,37 @@
 package domain
 
+// Locality belongs to a province, which can be given by its id or by its name together with
+// the id or name of its country. Its coordinates are optional, but given together
 type Locality struct {
-	ID           string `json:"id" binding:"required"`
-	LocalityName string `json:"locality_name" binding:"required"`
-	ProvinceName string `json:"province_name" binding:"required"`
-	CountryName  string `json:"country_name" binding:"required"`
+	ID           string   `json:"id" binding:"required"`
+	LocalityName string   `json:"locality_name" binding:"required"`
+	ProvinceID   int      `json:"province_id"`
+	ProvinceName string   `json:"province_name"`
+	CountryID    int      `json:"country_id"`
+	CountryName  string   `json:"country_name"`
+	Latitude     *float64 `json:"latitude"`
+	Longitude    *float64 `json:"longitude"`
+}
+
+// LocalityImportRow is a locality read from the given line of an import file
+type LocalityImportRow struct {
+	Line     int
+	Locality Locality
+}
+
+// LocalityImportError is the reason a line of an import file was not imported
+type LocalityImportError struct {
+	Line  int    `json:"line"`
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error"`
+}
+
+// LocalityImportReport sums up an import, on a dry run valid rows are counted but not imported
+type LocalityImportReport struct {
+	DryRun   bool                  `json:"dry_run"`
+	Rows     int                   `json:"rows"`
+	Valid    int                   `json:"valid"`
+	Imported int                   `json:"imported"`
+	Failed   int                   `json:"failed"`
+	Errors   []LocalityImportError `json:"errors"`
 }
@@ -0,0 +1,56 @@
+package domain
+
+// Margin report groupings
+const (
+	MarginGroupBySeller      = "seller"
+	MarginGroupByProductType = "product_type"
+)
+
+// ProductPrice is the product record in effect for a product at a date, with the fields a margin report groups by.
+type ProductPrice struct {
+	ProductID     int
+	Description   string
+	SellerID      *int
+	ProductTypeID int
+	PurchasePrice float32
+	SalePrice     float32
+}
+
+// ProductMargin is the margin of a product at the end of a period and how it changed since the start of the period.
+// MarginRate is the margin over the sale price in percent, nil when the sale price is 0.
+// PreviousMargin and MarginChange are nil when the period has no start or the product had no price at the start.
+type ProductMargin struct {
+	ProductID        int      `json:"product_id"`
+	Description      string   `json:"description"`
+	SellerID         *int     `json:"seller_id"`
+	ProductTypeID    int      `json:"product_type_id"`
+	PurchasePrice    float32  `json:"purchase_price"`
+	SalePrice        float32  `json:"sale_price"`
+	Margin           float64  `json:"margin"`
+	MarginRate       *float64 `json:"margin_rate"`
+	PreviousMargin   *float64 `json:"previous_margin"`
+	MarginChange     *float64 `json:"margin_change"`
+	BelowCost        bool     `json:"below_cost"`
+	DroppedBelowCost bool     `json:"dropped_below_cost"`
+}
+
+// MarginGroup summarizes the margins of the products of a seller or product type, ID is nil for products without seller.
+// AverageMarginChange only counts the products with a MarginChange.
+type MarginGroup struct {
+	ID                  *int     `json:"id"`
+	ProductsCount       int      `json:"products_count"`
+	AverageMargin       float64  `json:"average_margin"`
+	AverageMarginChange *float64 `json:"average_margin_change"`
+	BelowCostCount      int      `json:"below_cost_count"`
+}
+
+// MarginReport is the margin of every product with a price at To, the ones sold below their purchase price
+// and, when GroupBy is given, the summary per seller or product type.
+type MarginReport struct {
+	From      string          `json:"from,omitempty"`
+	To        string          `json:"to"`
+	GroupBy   string          `json:"group_by,omitempty"`
+	Groups    []MarginGroup   `json:"groups,omitempty"`
+	Products  []ProductMargin `json:"products"`
+	BelowCost []ProductMargin `json:"below_cost"`
+}
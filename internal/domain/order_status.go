@@ -0,0 +1,64 @@
+package domain
+
+import "time"
+
+// Order statuses, their ids match the rows of the order_statuses table
+const (
+	OrderStatusPending = iota + 1
+	OrderStatusConfirmed
+	OrderStatusPicking
+	OrderStatusShipped
+	OrderStatusDelivered
+	OrderStatusCancelled
+)
+
+// OrderStatusNames maps every order status id to its name in the order_statuses table
+var OrderStatusNames = map[int]string{
+	OrderStatusPending:   "pending",
+	OrderStatusConfirmed: "confirmed",
+	OrderStatusPicking:   "picking",
+	OrderStatusShipped:   "shipped",
+	OrderStatusDelivered: "delivered",
+	OrderStatusCancelled: "cancelled",
+}
+
+// orderStatusTransitions lists the statuses an order can move to from each status.
+// Delivered and cancelled orders are final.
+var orderStatusTransitions = map[int][]int{
+	OrderStatusPending:   {OrderStatusConfirmed, OrderStatusCancelled},
+	OrderStatusConfirmed: {OrderStatusPicking, OrderStatusCancelled},
+	OrderStatusPicking:   {OrderStatusShipped, OrderStatusCancelled},
+	OrderStatusShipped:   {OrderStatusDelivered},
+}
+
+// OrderStatusID returns the id of the order status with the given name
+func OrderStatusID(name string) (int, bool) {
+	for id, n := range OrderStatusNames {
+		if n == name {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// CanTransition reports whether an order in the from status can move to the to status
+func CanTransition(from int, to int) bool {
+	for _, next := range orderStatusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// OrderStatusChange is a transition of a purchase order from one status to another, made by an actor
+type OrderStatusChange struct {
+	ID              int       `json:"id"`
+	PurchaseOrderId int       `json:"purchase_order_id"`
+	FromStatusId    int       `json:"from_status_id"`
+	FromStatus      string    `json:"from_status"`
+	ToStatusId      int       `json:"to_status_id"`
+	ToStatus        string    `json:"to_status"`
+	Actor           string    `json:"actor"`
+	ChangedAt       time.Time `json:"changed_at"`
+}
@@ -12,4 +12,36 @@ type ProductBatch struct {
 	MinimumTemperature int    `json:"minumum_temperature"`
 	ProductID          int    `json:"product_id"`
 	SectionID          int    `json:"section_id"`
+	// Quarantined batches went through a temperature excursion and cannot be picked
+	Quarantined bool `json:"quarantined"`
+	// ReservedQuantity is held for purchase orders, only the AvailableQuantity can be picked or reserved
+	ReservedQuantity  int `json:"reserved_quantity"`
+	AvailableQuantity int `json:"available_quantity"`
+	// Warnings are not stored, they inform about problems that were allowed when saving the batch
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ProductBatchFilter narrows the product batches returned by a search, zero values are ignored
+type ProductBatchFilter struct {
+	ProductID   int
+	SectionID   int
+	DueFrom     string
+	DueTo       string
+	ExpiredOnly bool
+}
+
+// BatchAllocation is the quantity taken from a single product batch while picking stock.
+type BatchAllocation struct {
+	ProductBatchID int    `json:"product_batch_id"`
+	BatchNumber    int    `json:"batch_number"`
+	DueDate        string `json:"due_date"`
+	SectionID      int    `json:"section_id"`
+	Quantity       int    `json:"quantity"`
+}
+
+// ProductBatchPick is the result of picking a quantity of a product across its batches.
+type ProductBatchPick struct {
+	ProductID   int               `json:"product_id"`
+	Quantity    int               `json:"quantity"`
+	Allocations []BatchAllocation `json:"allocations"`
 }
@@ -18,6 +18,21 @@ type ProductRecord struct {
 	ProductID      int       `json:"product_id"`
 }
 
+// PriceHistory is the series of records of a product ordered by date, limited to the From and To dates when they are given.
+type PriceHistory struct {
+	ProductID int             `json:"product_id"`
+	From      string          `json:"from,omitempty"`
+	To        string          `json:"to,omitempty"`
+	Records   []ProductRecord `json:"records"`
+}
+
+// EffectivePrice is the record of a product in effect at a date, the newest one that already started.
+type EffectivePrice struct {
+	ProductID int           `json:"product_id"`
+	At        string        `json:"at"`
+	Record    ProductRecord `json:"record"`
+}
+
 type MySqlTime struct {
 	time.Time
 }
@@ -0,0 +1,16 @@
+package domain
+
+// ProductType classifies products and the sections that store them, a batch can only be stored in a section of the
+// product type of its product. MinimumTemperature and MaximumTemperature are the default range its products are kept at,
+// sections and products of a type without a range must give their own temperatures.
+type ProductType struct {
+	ID                 int      `json:"id"`
+	Name               string   `json:"name"`
+	MinimumTemperature *float32 `json:"minimum_temperature"`
+	MaximumTemperature *float32 `json:"maximum_temperature"`
+}
+
+// HasTemperatureRange reports whether the product type has a range to default the temperatures of its sections and products to
+func (pt ProductType) HasTemperatureRange() bool {
+	return pt.MinimumTemperature != nil && pt.MaximumTemperature != nil
+}
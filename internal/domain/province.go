@@ -0,0 +1,26 @@
+package domain
+
+type Province struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	CountryID   int    `json:"country_id"`
+	CountryName string `json:"country_name"`
+}
+
+// ProvinceReportSellers adds up the sellers of every locality of a province
+type ProvinceReportSellers struct {
+	ProvinceID   int             `json:"province_id"`
+	ProvinceName string          `json:"province_name"`
+	CountryName  string          `json:"country_name"`
+	SellersCount int             `json:"sellers_count"`
+	Localities   []ReportSellers `json:"localities"`
+}
+
+// ProvinceReportCarries adds up the carries of every locality of a province
+type ProvinceReportCarries struct {
+	ProvinceID   int             `json:"province_id"`
+	ProvinceName string          `json:"province_name"`
+	CountryName  string          `json:"country_name"`
+	CarriesCount int             `json:"carries_count"`
+	Localities   []ReportCarries `json:"localities"`
+}
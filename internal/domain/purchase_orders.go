@@ -8,4 +8,42 @@ type Purchase_orders struct {
 	BuyerId         int    `json:"buyer_id"`
 	ProductRecordId int    `json:"product_record_id"`
 	OrderStatusId   int    `json:"order_status_id"`
+	// Items are the lines of the order, an order placed with a single product_record_id has one item of that product
+	Items        []OrderItem        `json:"items,omitempty"`
+	Total        float64            `json:"total,omitempty"`
+	Reservations []StockReservation `json:"reservations,omitempty"`
+}
+
+// OrderItem is a line of a purchase order, its unit price is the sale price of the product when the order was placed
+type OrderItem struct {
+	ID              int     `json:"id"`
+	PurchaseOrderId int     `json:"purchase_order_id"`
+	ProductId       int     `json:"product_id"`
+	ProductRecordId int     `json:"product_record_id"`
+	Quantity        int     `json:"quantity"`
+	UnitPrice       float64 `json:"unit_price"`
+	Subtotal        float64 `json:"subtotal"`
+}
+
+// PurchaseOrderDetail is a purchase order together with the name of its status, its buyer and its items.
+// Orders placed with a single product_record_id also have that record.
+type PurchaseOrderDetail struct {
+	ID            int            `json:"id"`
+	OrderNumber   string         `json:"order_number"`
+	OrderDate     string         `json:"order_date"`
+	TrackingCode  string         `json:"tracking_code"`
+	OrderStatusId int            `json:"order_status_id"`
+	Status        string         `json:"status"`
+	Buyer         Buyer          `json:"buyer"`
+	ProductRecord *ProductRecord `json:"product_record,omitempty"`
+	Items         []OrderItem    `json:"items"`
+	Total         float64        `json:"total"`
+}
+
+// PurchaseOrderFilter narrows the purchase orders returned by a search, zero values are ignored
+type PurchaseOrderFilter struct {
+	BuyerId  int
+	StatusId int
+	From     string
+	To       string
 }
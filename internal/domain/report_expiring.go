@@ -0,0 +1,39 @@
+package domain
+
+// ExpiringBatch is a product batch with stock that expires soon.
+type ExpiringBatch struct {
+	ProductBatchID  int    `json:"product_batch_id"`
+	BatchNumber     int    `json:"batch_number"`
+	DueDate         string `json:"due_date"`
+	DaysRemaining   int    `json:"days_remaining"`
+	CurrentQuantity int    `json:"current_quantity"`
+	Quarantined     bool   `json:"quarantined"`
+}
+
+// ExpiringProduct groups the expiring batches of a product inside a section.
+type ExpiringProduct struct {
+	ProductID     int             `json:"product_id"`
+	ProductCode   string          `json:"product_code"`
+	Description   string          `json:"description"`
+	Quantity      int             `json:"quantity"`
+	DaysRemaining int             `json:"days_remaining"`
+	Batches       []ExpiringBatch `json:"batches"`
+}
+
+// ExpiringSection groups the expiring products of a section.
+type ExpiringSection struct {
+	SectionID     int               `json:"section_id"`
+	SectionNumber int               `json:"section_number"`
+	Quantity      int               `json:"quantity"`
+	Products      []ExpiringProduct `json:"products"`
+}
+
+// ExpiringStock is a single expiring batch along with its section and product, as read from the database.
+type ExpiringStock struct {
+	SectionID     int
+	SectionNumber int
+	ProductID     int
+	ProductCode   string
+	Description   string
+	Batch         ExpiringBatch
+}
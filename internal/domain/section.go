@@ -11,6 +11,8 @@ type Section struct {
 	MaximumCapacity    int `json:"maximum_capacity"`
 	WarehouseID        int `json:"warehouse_id"`
 	ProductTypeID      int `json:"product_type_id"`
+	// AffectedBatches lists the stored batches whose products do not fit a new temperature of the section
+	AffectedBatches []BatchTemperature `json:"affected_batches,omitempty"`
 }
 
 type ProductsBySection struct {
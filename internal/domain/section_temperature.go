@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// SectionTemperature is a temperature reading sent by a sensor of a section.
+type SectionTemperature struct {
+	ID          int       `json:"id"`
+	SectionID   int       `json:"section_id"`
+	RecordedAt  time.Time `json:"timestamp"`
+	Temperature float32   `json:"temperature"`
+}
+
+// TemperatureBucket summarizes the readings of a section taken inside a period of time.
+type TemperatureBucket struct {
+	From     string  `json:"from"`
+	Minimum  float32 `json:"minimum"`
+	Average  float32 `json:"average"`
+	Maximum  float32 `json:"maximum"`
+	Readings int     `json:"readings"`
+}
@@ -8,3 +8,16 @@ type Seller struct {
 	Telephone   string `json:"telephone"`
 	Locality_id string `json:"locality_id"`
 }
+
+// SellerPerformance sums the products of a seller, the stock on hand of their batches and the units and revenue
+// of the items of those products in the purchase orders of a period that were not cancelled.
+// Stock on hand leaves out expired and quarantined batches and the reserved quantity, like picking does.
+// Revenue uses the sale price of the product record each item was priced with.
+type SellerPerformance struct {
+	SellerID      int     `json:"seller_id"`
+	CompanyName   string  `json:"company_name"`
+	ProductsCount int     `json:"products_count"`
+	StockOnHand   int     `json:"stock_on_hand"`
+	UnitsSold     int     `json:"units_sold"`
+	Revenue       float64 `json:"revenue"`
+}
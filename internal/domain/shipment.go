@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// Shipment statuses, they follow the timestamps of the shipment
+const (
+	ShipmentAssigned   = "assigned"
+	ShipmentDispatched = "dispatched"
+	ShipmentDelivered  = "delivered"
+)
+
+// Shipment is a carry taking one or more purchase orders, every order of the shipment gets its tracking code
+type Shipment struct {
+	ID               int        `json:"id"`
+	CarryID          int        `json:"carry_id"`
+	TrackingCode     string     `json:"tracking_code"`
+	Status           string     `json:"status"`
+	PurchaseOrderIDs []int      `json:"purchase_order_ids"`
+	CreatedAt        time.Time  `json:"created_at"`
+	DispatchedAt     *time.Time `json:"dispatched_at"`
+	DeliveredAt      *time.Time `json:"delivered_at"`
+}
+
+// ShipmentStatus returns the status of the shipment according to its dispatch and delivery timestamps
+func ShipmentStatus(s Shipment) string {
+	switch {
+	case s.DeliveredAt != nil:
+		return ShipmentDelivered
+	case s.DispatchedAt != nil:
+		return ShipmentDispatched
+	default:
+		return ShipmentAssigned
+	}
+}
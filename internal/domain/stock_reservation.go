@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// Stock reservation statuses. Held and confirmed reservations count as reserved quantity of their batch.
+const (
+	ReservationHeld      = "held"
+	ReservationConfirmed = "confirmed"
+	ReservationReleased  = "released"
+	ReservationConsumed  = "consumed"
+	ReservationExpired   = "expired"
+)
+
+// StockReservation is a quantity of a product batch held for a purchase order.
+// Held reservations lapse at ExpiresAt unless the order is confirmed before.
+type StockReservation struct {
+	ID              int        `json:"id"`
+	PurchaseOrderId int        `json:"purchase_order_id"`
+	ProductBatchId  int        `json:"product_batch_id"`
+	ProductId       int        `json:"product_id"`
+	Quantity        int        `json:"quantity"`
+	Status          string     `json:"status"`
+	ExpiresAt       *time.Time `json:"expires_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
@@ -0,0 +1,34 @@
+package domain
+
+// Temperature policies of a warehouse, they decide what happens when a product batch is stored in a section
+// whose temperature is not compatible with the product
+const (
+	TemperaturePolicyReject = "reject"
+	TemperaturePolicyWarn   = "warn"
+)
+
+// BatchTemperature pairs the temperature needs of a product batch with the temperatures of the section storing it.
+type BatchTemperature struct {
+	ProductBatchID                 int     `json:"product_batch_id"`
+	BatchNumber                    int     `json:"batch_number"`
+	ProductID                      int     `json:"product_id"`
+	SectionID                      int     `json:"section_id"`
+	RecommendedFreezingTemperature float32 `json:"recommended_freezing_temperature"`
+	SectionCurrentTemperature      int     `json:"section_current_temperature"`
+	SectionMinimumTemperature      int     `json:"section_minimum_temperature"`
+	TemperaturePolicy              string  `json:"-"`
+	ProductTypeID                  int     `json:"-"`
+	SectionProductTypeID           int     `json:"-"`
+}
+
+// Compatible reports whether the section is cold enough for the product right now and is able to reach
+// the recommended freezing temperature of the product.
+func (bt BatchTemperature) Compatible() bool {
+	return float32(bt.SectionCurrentTemperature) <= bt.RecommendedFreezingTemperature &&
+		float32(bt.SectionMinimumTemperature) <= bt.RecommendedFreezingTemperature
+}
+
+// ValidTemperaturePolicy reports whether the given policy is one of the known temperature policies
+func ValidTemperaturePolicy(policy string) bool {
+	return policy == TemperaturePolicyReject || policy == TemperaturePolicyWarn
+}
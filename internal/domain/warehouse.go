@@ -7,4 +7,6 @@ type Warehouse struct {
 	WarehouseCode      string `json:"warehouse_code"`
 	MinimumCapacity    int    `json:"minimum_capacity"`
 	MinimumTemperature int    `json:"minimum_temperature"`
+	TemperaturePolicy  string `json:"temperature_policy"`
+	LocalityID         string `json:"locality_id"`
 }
@@ -18,10 +18,14 @@ const (
 	SaveEmployee    = "INSERT INTO employees(card_number_id,first_name,last_name,warehouse_id) VALUES (?,?,?,?)"
 	UpdateEmployee  = "UPDATE employees SET first_name=?, last_name=?, warehouse_id=?  WHERE id=?"
 	DeleteEmployee  = "DELETE FROM employees WHERE id=?"
+	LockEmployee    = "SELECT id FROM employees WHERE id=? FOR UPDATE;"
+	InboundOrderIDs = "SELECT id FROM inbound_orders WHERE employee_id=? ORDER BY id;"
+	ReassignOrders  = "UPDATE inbound_orders SET employee_id=? WHERE employee_id=?;"
 )
 
 const (
 	ForeignKeyConstraint = 1452
+	RowIsReferenced      = 1451
 )
 
 // Errors
@@ -36,7 +40,10 @@ type Repository interface {
 	Exists(ctx context.Context, cardNumberID string) bool
 	Save(ctx context.Context, e domain.Employee) (int, error)
 	Update(ctx context.Context, e domain.Employee) error
-	Delete(ctx context.Context, id int) error
+	// Delete removes the employee and, with the reassign strategy, moves its inbound orders in the same transaction
+	Delete(ctx context.Context, id int, options domain.DeleteOptions) error
+	// GetDependents returns the ids of the inbound orders of the employee by table
+	GetDependents(ctx context.Context, id int) (map[string][]int, error)
 }
 
 type repository struct {
@@ -140,29 +147,85 @@ func (r *repository) Update(ctx context.Context, e domain.Employee) error {
 	return nil
 }
 
-func (r *repository) Delete(ctx context.Context, id int) error {
-	stmt, err := r.db.Prepare(DeleteEmployee)
+func (r *repository) Delete(ctx context.Context, id int, options domain.DeleteOptions) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		logging.Log(err)
 		return err
 	}
 
-	res, err := stmt.Exec(id)
-	if err != nil {
-		logging.Log(err)
+	if err := deleteEmployee(ctx, tx, id, options); err != nil {
+		_ = tx.Rollback()
 		return err
 	}
 
-	affect, err := res.RowsAffected()
-	if err != nil {
+	if err := tx.Commit(); err != nil {
 		logging.Log(err)
 		return err
 	}
+	return nil
+}
 
-	if affect < 1 {
-		logging.Log(ErrEmployeeNotFound)
-		return ErrEmployeeNotFound
+// deleteEmployee locks the employee, reassigns its inbound orders when the strategy says so and deletes it inside the transaction
+func deleteEmployee(ctx context.Context, tx *sql.Tx, id int, options domain.DeleteOptions) error {
+	if err := lockEmployee(ctx, tx, id, ErrEmployeeNotFound); err != nil {
+		return err
 	}
 
+	if options.Strategy == domain.DeleteReassign {
+		if err := lockEmployee(ctx, tx, options.To, ErrEmployeeTargetNotFound); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, ReassignOrders, options.To, id); err != nil {
+			logging.Log(err)
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, DeleteEmployee, id); err != nil {
+		if mysqlError, ok := err.(*mysql.MySQLError); ok && mysqlError.Number == RowIsReferenced {
+			logging.Log(ErrEmployeeHasInboundOrders)
+			return ErrEmployeeHasInboundOrders
+		}
+		logging.Log(err)
+		return err
+	}
+	return nil
+}
+
+// lockEmployee locks the row of the employee until the end of the transaction, or returns notFound
+func lockEmployee(ctx context.Context, tx *sql.Tx, id int, notFound error) error {
+	if err := tx.QueryRowContext(ctx, LockEmployee, id).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logging.Log(notFound)
+			return notFound
+		}
+		logging.Log(err)
+		return err
+	}
 	return nil
 }
+
+func (r *repository) GetDependents(ctx context.Context, id int) (map[string][]int, error) {
+	rows, err := r.db.QueryContext(ctx, InboundOrderIDs, id)
+	if err != nil {
+		logging.Log(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	inboundOrders := []int{}
+	for rows.Next() {
+		var orderID int
+		if err := rows.Scan(&orderID); err != nil {
+			logging.Log(err)
+			return nil, err
+		}
+		inboundOrders = append(inboundOrders, orderID)
+	}
+	if err := rows.Err(); err != nil {
+		logging.Log(err)
+		return nil, err
+	}
+	return map[string][]int{"inbound_orders": inboundOrders}, nil
+}
@@ -9,6 +9,8 @@ import (
 type MockRepository struct {
 	DataMock  []domain.Employee
 	MockError error
+	// InboundOrders lists the ids of the inbound orders of every employee
+	InboundOrders map[int][]int
 }
 
 func (mockRepository *MockRepository) GetAll(ctx context.Context) ([]domain.Employee, error) {
@@ -57,9 +59,21 @@ func (mockRepository *MockRepository) Update(ctx context.Context, updatedEmploye
 	return mockRepository.MockError
 }
 
-func (mockRepository *MockRepository) Delete(ctx context.Context, id int) error {
+func (mockRepository *MockRepository) Delete(ctx context.Context, id int, options domain.DeleteOptions) error {
 	for i, employee := range mockRepository.DataMock {
 		if employee.ID == id {
+			orders := mockRepository.InboundOrders[id]
+			if options.Strategy == domain.DeleteReassign {
+				if target, _ := mockRepository.Get(ctx, options.To); target.ID != options.To {
+					return ErrEmployeeTargetNotFound
+				}
+				if len(orders) > 0 {
+					mockRepository.InboundOrders[options.To] = append(mockRepository.InboundOrders[options.To], orders...)
+				}
+			} else if len(orders) > 0 {
+				return ErrEmployeeHasInboundOrders
+			}
+			delete(mockRepository.InboundOrders, id)
 			mockRepository.DataMock = append(mockRepository.DataMock[:i], mockRepository.DataMock[i+1:]...)
 			return nil
 		}
@@ -67,6 +81,10 @@ func (mockRepository *MockRepository) Delete(ctx context.Context, id int) error
 	return mockRepository.MockError
 }
 
+func (mockRepository *MockRepository) GetDependents(ctx context.Context, id int) (map[string][]int, error) {
+	return map[string][]int{"inbound_orders": append([]int{}, mockRepository.InboundOrders[id]...)}, nil
+}
+
 func getLastID(mockRepository *MockRepository) int {
 	lastID := 0
 
@@ -10,6 +10,7 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -191,26 +192,25 @@ func TestRepositoryEmployeeDelete_Ok(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
-	columns := []string{"id", "card_number_id", "first_name", "last_name", "warehouse_id"}
-	rows := sqlmock.NewRows(columns)
-	rows.AddRow(employeeTest.ID, employeeTest.CardNumberID, employeeTest.FirstName, employeeTest.LastName, employeeTest.WarehouseID)
-	mock.ExpectPrepare(regexp.QuoteMeta(DeleteEmployee))
-	mock.ExpectExec(regexp.QuoteMeta(DeleteEmployee)).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LockEmployee)).WithArgs(employeeTest.ID).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(employeeTest.ID))
+	mock.ExpectExec(regexp.QuoteMeta(DeleteEmployee)).WithArgs(employeeTest.ID).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 	repo := NewRepository(db)
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	err = repo.Delete(ctx, employeeTest.ID)
+	err = repo.Delete(ctx, employeeTest.ID, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestRepositoryEmployeeDelete_FailPrepare(t *testing.T) {
+func TestRepositoryEmployeeDelete_FailBegin(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
-	mock.ExpectPrepare(regexp.QuoteMeta(DeleteEmployee)).WillReturnError(ErrInternalError)
+	mock.ExpectBegin().WillReturnError(ErrInternalError)
 	repo := NewRepository(db)
-	err = repo.Delete(context.TODO(), employeeTest.ID)
+	err = repo.Delete(context.TODO(), employeeTest.ID, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 	assert.Error(t, err)
 	assert.EqualError(t, err, ErrInternalError.Error())
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -220,10 +220,12 @@ func TestRepositoryEmployeeDelete_FailExec(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
-	mock.ExpectPrepare(regexp.QuoteMeta(DeleteEmployee))
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LockEmployee)).WithArgs(employeeTest.ID).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(employeeTest.ID))
 	mock.ExpectExec(regexp.QuoteMeta(DeleteEmployee)).WillReturnError(ErrInternalError)
+	mock.ExpectRollback()
 	repo := NewRepository(db)
-	err = repo.Delete(context.TODO(), employeeTest.ID)
+	err = repo.Delete(context.TODO(), employeeTest.ID, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 	assert.Error(t, err)
 	assert.EqualError(t, err, ErrInternalError.Error())
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -233,16 +235,59 @@ func TestRepositoryEmployeeDelete_NotFound(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
-	mock.ExpectPrepare(regexp.QuoteMeta(DeleteEmployee))
-	mock.ExpectExec(regexp.QuoteMeta(DeleteEmployee)).WillReturnError(ErrEmployeeNotFound)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LockEmployee)).WithArgs(employeeTest.ID).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
 	repo := NewRepository(db)
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	err = repo.Delete(ctx, employeeTest.ID)
+	err = repo.Delete(ctx, employeeTest.ID, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 	assert.EqualError(t, err, ErrEmployeeNotFound.Error())
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestRepositoryEmployeeDelete_HasInboundOrders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LockEmployee)).WithArgs(employeeTest.ID).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(employeeTest.ID))
+	mock.ExpectExec(regexp.QuoteMeta(DeleteEmployee)).WithArgs(employeeTest.ID).WillReturnError(&mysql.MySQLError{Number: RowIsReferenced})
+	mock.ExpectRollback()
+	repo := NewRepository(db)
+	err = repo.Delete(context.TODO(), employeeTest.ID, domain.DeleteOptions{Strategy: domain.DeleteBlock})
+	assert.EqualError(t, err, ErrEmployeeHasInboundOrders.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepositoryEmployeeDelete_Reassign(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LockEmployee)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(LockEmployee)).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectExec(regexp.QuoteMeta(ReassignOrders)).WithArgs(2, 1).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(DeleteEmployee)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	repo := NewRepository(db)
+	err = repo.Delete(context.TODO(), 1, domain.DeleteOptions{Strategy: domain.DeleteReassign, To: 2})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepositoryEmployeeGetDependents_Ok(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	mock.ExpectQuery(regexp.QuoteMeta(InboundOrderIDs)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4).AddRow(6))
+	repo := NewRepository(db)
+	result, err := repo.GetDependents(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]int{"inbound_orders": {4, 6}}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestRepositoryEmployeeUpdate_Ok(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -18,8 +18,17 @@ var (
 	ErrEmployeeNotUpdated = errors.New("cannot update employee")
 	//ErrEmployeedNotSaved is returned when the requested result cannot be saved in db
 	ErrEmployeeNotSaved = errors.New("cannot save employee")
+	//ErrInvalidDeleteStrategy is returned when a delete has an unknown strategy or reassign has no other employee
+	ErrInvalidDeleteStrategy = errors.New("strategy must be block or reassign, reassign needs the id of another employee in to")
+	//ErrEmployeeHasInboundOrders is returned when the block strategy finds inbound orders of the employee
+	ErrEmployeeHasInboundOrders = errors.New("employee has inbound orders, delete it with the reassign strategy")
+	//ErrEmployeeTargetNotFound is returned when the employee to reassign the inbound orders to does not exist
+	ErrEmployeeTargetNotFound = errors.New("the employee to reassign to does not exist")
 )
 
+// DeleteStrategies are the strategies an employee can be deleted with, inbound orders can not be left without employee
+var DeleteStrategies = []string{domain.DeleteBlock, domain.DeleteReassign}
+
 type Service interface {
 	// GetAll returns all the employees that exist and are inside the repository
 	GetAll(ctx context.Context) ([]domain.Employee, error)
@@ -29,8 +38,11 @@ type Service interface {
 	Save(ctx context.Context, employee domain.Employee) (domain.Employee, error)
 	// Update updates the employee data inside the repository
 	Update(ctx context.Context, employee domain.Employee) (domain.Employee, error)
-	// Delete removes the employee with the specified ID from the repository
-	Delete(ctx context.Context, id int) error
+	// Delete removes the employee with the specified ID from the repository, with the reassign strategy
+	// its inbound orders move to the employee options.To
+	Delete(ctx context.Context, id int, options domain.DeleteOptions) error
+	// DeletePreview returns the inbound orders a delete of the employee affects
+	DeletePreview(ctx context.Context, id int) (domain.DeletePreview, error)
 }
 
 type service struct {
@@ -122,8 +134,13 @@ func (service *service) Update(ctx context.Context, employee domain.Employee) (d
 	return updatedEmployee, nil
 }
 
-func (service *service) Delete(ctx context.Context, id int) error {
-	err := service.repository.Delete(ctx, id)
+func (service *service) Delete(ctx context.Context, id int, options domain.DeleteOptions) error {
+	if !options.Valid(id, DeleteStrategies) {
+		logging.Log(ErrInvalidDeleteStrategy)
+		return ErrInvalidDeleteStrategy
+	}
+
+	err := service.repository.Delete(ctx, id, options)
 
 	if err != nil {
 		if err.Error() == ErrEmployeeNotFound.Error() {
@@ -137,3 +154,17 @@ func (service *service) Delete(ctx context.Context, id int) error {
 
 	return nil
 }
+
+func (service *service) DeletePreview(ctx context.Context, id int) (domain.DeletePreview, error) {
+	if _, err := service.Get(ctx, id); err != nil {
+		return domain.DeletePreview{}, err
+	}
+
+	dependents, err := service.repository.GetDependents(ctx, id)
+	if err != nil {
+		logging.Log(err)
+		return domain.DeletePreview{}, err
+	}
+
+	return domain.NewDeletePreview(id, DeleteStrategies, dependents), nil
+}
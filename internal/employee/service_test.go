@@ -229,7 +229,7 @@ func TestDelete(t *testing.T) {
 
 	service := NewService(&mockRepository)
 
-	err := service.Delete(ctx, id)
+	err := service.Delete(ctx, id, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 
 	assert.Nil(t, err)
 	assert.Equal(t, expectedResult, mockRepository.DataMock)
@@ -252,7 +252,60 @@ func TestDeleteFail(t *testing.T) {
 
 	service := NewService(&mockRepository)
 
-	err := service.Delete(ctx, id)
+	err := service.Delete(ctx, id, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 
 	assert.EqualError(t, err, expectedErr.Error())
 }
+
+func TestDeleteReassign(t *testing.T) {
+	var ctx context.Context
+
+	db := []domain.Employee{
+		{ID: 1, CardNumberID: "123456", FirstName: "John", LastName: "Doe", WarehouseID: 3},
+		{ID: 2, CardNumberID: "654321", FirstName: "Jane", LastName: "Doe", WarehouseID: 7},
+	}
+
+	mockRepository := MockRepository{
+		DataMock:      db,
+		InboundOrders: map[int][]int{1: {4, 6}, 2: {5}},
+	}
+
+	service := NewService(&mockRepository)
+
+	err := service.Delete(ctx, 1, domain.DeleteOptions{Strategy: domain.DeleteReassign, To: 2})
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[int][]int{2: {5, 4, 6}}, mockRepository.InboundOrders)
+}
+
+func TestDeleteBlockedByInboundOrders(t *testing.T) {
+	var ctx context.Context
+
+	mockRepository := MockRepository{
+		DataMock:      []domain.Employee{{ID: 1, CardNumberID: "123456", FirstName: "John", LastName: "Doe", WarehouseID: 3}},
+		InboundOrders: map[int][]int{1: {4}},
+	}
+
+	service := NewService(&mockRepository)
+
+	err := service.Delete(ctx, 1, domain.DeleteOptions{Strategy: domain.DeleteBlock})
+
+	assert.EqualError(t, err, ErrEmployeeHasInboundOrders.Error())
+	assert.Len(t, mockRepository.DataMock, 1)
+}
+
+func TestDeleteInvalidStrategy(t *testing.T) {
+	var ctx context.Context
+
+	mockRepository := MockRepository{
+		DataMock: []domain.Employee{{ID: 1, CardNumberID: "123456", FirstName: "John", LastName: "Doe", WarehouseID: 3}},
+	}
+
+	service := NewService(&mockRepository)
+
+	for _, options := range []domain.DeleteOptions{{Strategy: domain.DeleteDetach}, {Strategy: domain.DeleteReassign, To: 1}} {
+		err := service.Delete(ctx, 1, options)
+		assert.EqualError(t, err, ErrInvalidDeleteStrategy.Error())
+	}
+	assert.Len(t, mockRepository.DataMock, 1)
+}
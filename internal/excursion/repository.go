@@ -0,0 +1,167 @@
+package excursion
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+)
+
+// Errors
+var (
+	ErrInternal = errors.New("database internal error")
+)
+
+// Queries
+const (
+	// GetBatchLimits takes the lowest of the minimum temperature of the batch and the recommended freezing temperature of its product as limit
+	GetBatchLimits = `SELECT pb.id, pb.section_id, s.current_temperature, LEAST(pb.minimum_temperature, p.recommended_freezing_temperature) FROM product_batches AS pb
+	INNER JOIN sections AS s ON s.id = pb.section_id
+	INNER JOIN products AS p ON p.id = pb.product_id
+	WHERE pb.current_quantity > 0;`
+	GetAllExcursions = "SELECT id, section_id, product_batch_id, limit_temperature, maximum_temperature, started_at, ended_at FROM temperature_excursions"
+	SaveExcursion    = "INSERT INTO temperature_excursions (section_id, product_batch_id, limit_temperature, maximum_temperature, started_at) VALUES (?, ?, ?, ?, ?);"
+	QuarantineBatch  = "UPDATE product_batches SET quarantined = TRUE WHERE id = ?;"
+	CloseExcursion   = "UPDATE temperature_excursions SET ended_at = ? WHERE id = ?;"
+	UpdateMaximum    = "UPDATE temperature_excursions SET maximum_temperature = ? WHERE id = ?;"
+)
+
+func init() {
+	logging.InitLog(nil)
+}
+
+// Repository encapsulates the storage of the temperature excursions.
+type Repository interface {
+	GetAll(ctx context.Context, status string, sectionID int) ([]domain.Excursion, error)
+	GetBatchLimits(ctx context.Context) ([]domain.BatchTemperatureLimit, error)
+	Open(ctx context.Context, e domain.Excursion) (int, error)
+	Close(ctx context.Context, id int, endedAt time.Time) error
+	UpdateMaximum(ctx context.Context, id int, temperature float32) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+// GetAll returns the excursions ordered by start, only the open or closed ones when a status is given
+// and only the ones of a section when the sectionID is not zero
+func (r *repository) GetAll(ctx context.Context, status string, sectionID int) ([]domain.Excursion, error) {
+	query, args := buildGetAllQuery(status, sectionID)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+
+	excursions := []domain.Excursion{}
+	for rows.Next() {
+		e := domain.Excursion{}
+		var endedAt sql.NullTime
+		if err := rows.Scan(&e.ID, &e.SectionID, &e.ProductBatchID, &e.LimitTemperature, &e.MaximumTemperature, &e.StartedAt, &endedAt); err != nil {
+			logging.Log(err)
+			return nil, ErrInternal
+		}
+		if endedAt.Valid {
+			e.EndedAt = &endedAt.Time
+		}
+		excursions = append(excursions, e)
+	}
+	return excursions, nil
+}
+
+func (r *repository) GetBatchLimits(ctx context.Context) ([]domain.BatchTemperatureLimit, error) {
+	rows, err := r.db.QueryContext(ctx, GetBatchLimits)
+	if err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+
+	var limits []domain.BatchTemperatureLimit
+	for rows.Next() {
+		l := domain.BatchTemperatureLimit{}
+		if err := rows.Scan(&l.ProductBatchID, &l.SectionID, &l.SectionCurrentTemperature, &l.LimitTemperature); err != nil {
+			logging.Log(err)
+			return nil, ErrInternal
+		}
+		limits = append(limits, l)
+	}
+	return limits, nil
+}
+
+// Open stores the excursion and quarantines its product batch inside a single transaction
+func (r *repository) Open(ctx context.Context, e domain.Excursion) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Log(err)
+		return 0, ErrInternal
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, SaveExcursion, e.SectionID, e.ProductBatchID, e.LimitTemperature, e.MaximumTemperature, e.StartedAt)
+	if err != nil {
+		logging.Log(err)
+		return 0, ErrInternal
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		logging.Log(err)
+		return 0, ErrInternal
+	}
+	if _, err := tx.ExecContext(ctx, QuarantineBatch, e.ProductBatchID); err != nil {
+		logging.Log(err)
+		return 0, ErrInternal
+	}
+	if err := tx.Commit(); err != nil {
+		logging.Log(err)
+		return 0, ErrInternal
+	}
+	return int(id), nil
+}
+
+func (r *repository) Close(ctx context.Context, id int, endedAt time.Time) error {
+	if _, err := r.db.ExecContext(ctx, CloseExcursion, endedAt, id); err != nil {
+		logging.Log(err)
+		return ErrInternal
+	}
+	return nil
+}
+
+func (r *repository) UpdateMaximum(ctx context.Context, id int, temperature float32) error {
+	if _, err := r.db.ExecContext(ctx, UpdateMaximum, temperature, id); err != nil {
+		logging.Log(err)
+		return ErrInternal
+	}
+	return nil
+}
+
+func buildGetAllQuery(status string, sectionID int) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	switch status {
+	case domain.ExcursionOpen:
+		conditions = append(conditions, "ended_at IS NULL")
+	case domain.ExcursionClosed:
+		conditions = append(conditions, "ended_at IS NOT NULL")
+	}
+	if sectionID != 0 {
+		conditions = append(conditions, "section_id = ?")
+		args = append(args, sectionID)
+	}
+	query := GetAllExcursions
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	return query + " ORDER BY started_at, id;", args
+}
@@ -0,0 +1,73 @@
+package excursion
+
+import (
+	"context"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+)
+
+type MockRepository struct {
+	DataMockExcursions []domain.Excursion
+	DataMockLimits     []domain.BatchTemperatureLimit
+	QuarantinedBatches []int
+	Error              error
+}
+
+func (r *MockRepository) GetAll(ctx context.Context, status string, sectionID int) ([]domain.Excursion, error) {
+	if r.Error != nil {
+		return nil, r.Error
+	}
+	excursions := []domain.Excursion{}
+	for _, e := range r.DataMockExcursions {
+		if status == domain.ExcursionOpen && e.EndedAt != nil || status == domain.ExcursionClosed && e.EndedAt == nil {
+			continue
+		}
+		if sectionID != 0 && e.SectionID != sectionID {
+			continue
+		}
+		excursions = append(excursions, e)
+	}
+	return excursions, nil
+}
+
+func (r *MockRepository) GetBatchLimits(ctx context.Context) ([]domain.BatchTemperatureLimit, error) {
+	if r.Error != nil {
+		return nil, r.Error
+	}
+	return r.DataMockLimits, nil
+}
+
+func (r *MockRepository) Open(ctx context.Context, e domain.Excursion) (int, error) {
+	if r.Error != nil {
+		return 0, r.Error
+	}
+	e.ID = len(r.DataMockExcursions) + 1
+	r.DataMockExcursions = append(r.DataMockExcursions, e)
+	r.QuarantinedBatches = append(r.QuarantinedBatches, e.ProductBatchID)
+	return e.ID, nil
+}
+
+func (r *MockRepository) Close(ctx context.Context, id int, endedAt time.Time) error {
+	if r.Error != nil {
+		return r.Error
+	}
+	for i := range r.DataMockExcursions {
+		if r.DataMockExcursions[i].ID == id {
+			r.DataMockExcursions[i].EndedAt = &endedAt
+		}
+	}
+	return nil
+}
+
+func (r *MockRepository) UpdateMaximum(ctx context.Context, id int, temperature float32) error {
+	if r.Error != nil {
+		return r.Error
+	}
+	for i := range r.DataMockExcursions {
+		if r.DataMockExcursions[i].ID == id {
+			r.DataMockExcursions[i].MaximumTemperature = temperature
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package excursion
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+var excursionColumns = []string{"id", "section_id", "product_batch_id", "limit_temperature", "maximum_temperature", "started_at", "ended_at"}
+
+func TestGetAll_Ok(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	started := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	ended := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows(excursionColumns).
+		AddRow(1, 1, 1, -18, -10, started, ended).
+		AddRow(2, 1, 2, -18, -12, started, nil)
+	mock.ExpectQuery(regexp.QuoteMeta(GetAllExcursions + " ORDER BY started_at, id;")).WillReturnRows(rows)
+
+	expected := []domain.Excursion{
+		{ID: 1, SectionID: 1, ProductBatchID: 1, LimitTemperature: -18, MaximumTemperature: -10, StartedAt: started, EndedAt: &ended},
+		{ID: 2, SectionID: 1, ProductBatchID: 2, LimitTemperature: -18, MaximumTemperature: -12, StartedAt: started},
+	}
+
+	// ACT
+	repo := NewRepository(db)
+	result, err := repo.GetAll(context.TODO(), "", 0)
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAll_Filtered(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	query := GetAllExcursions + " WHERE ended_at IS NULL AND section_id = ? ORDER BY started_at, id;"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3).WillReturnRows(sqlmock.NewRows(excursionColumns))
+
+	// ACT
+	repo := NewRepository(db)
+	result, err := repo.GetAll(context.TODO(), domain.ExcursionOpen, 3)
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Excursion{}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBatchLimits_Ok(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "section_id", "current_temperature", "limit"}).AddRow(1, 2, -10, -18)
+	mock.ExpectQuery(regexp.QuoteMeta(GetBatchLimits)).WillReturnRows(rows)
+
+	// ACT
+	repo := NewRepository(db)
+	result, err := repo.GetBatchLimits(context.TODO())
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.BatchTemperatureLimit{{ProductBatchID: 1, SectionID: 2, SectionCurrentTemperature: -10, LimitTemperature: -18}}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOpen_Ok(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	e := domain.Excursion{SectionID: 2, ProductBatchID: 1, LimitTemperature: -18, MaximumTemperature: -10, StartedAt: time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(SaveExcursion)).WithArgs(2, 1, float32(-18), float32(-10), e.StartedAt).WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectExec(regexp.QuoteMeta(QuarantineBatch)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// ACT
+	repo := NewRepository(db)
+	id, err := repo.Open(context.TODO(), e)
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, 7, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOpen_QuarantineError(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(SaveExcursion)).WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectExec(regexp.QuoteMeta(QuarantineBatch)).WillReturnError(ErrInternal)
+	mock.ExpectRollback()
+
+	// ACT
+	repo := NewRepository(db)
+	id, err := repo.Open(context.TODO(), domain.Excursion{ProductBatchID: 1})
+
+	// ASSERT
+	assert.Empty(t, id)
+	assert.EqualError(t, err, ErrInternal.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClose_Ok(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	ended := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	mock.ExpectExec(regexp.QuoteMeta(CloseExcursion)).WithArgs(ended, 7).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// ACT
+	repo := NewRepository(db)
+	err = repo.Close(context.TODO(), 7, ended)
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,124 @@
+package excursion
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+)
+
+var (
+	// ErrInvalidStatus is returned when the status filter is not open or closed
+	ErrInvalidStatus = errors.New("the status must be open or closed")
+)
+
+// Clock returns the current time, the evaluation uses it for the start and end of the excursions
+type Clock func() time.Time
+
+type Service interface {
+	// GetAll returns the excursions, only the open or closed ones if a status is given
+	GetAll(ctx context.Context, status string, sectionID int) ([]domain.Excursion, error)
+	// Evaluate compares the current temperature of each section with the batches stored in it,
+	// opening an excursion and quarantining the batch when the section is too warm and closing it when it cools down
+	Evaluate(ctx context.Context) (opened int, closed int, err error)
+}
+
+type service struct {
+	repository Repository
+	clock      Clock
+}
+
+func NewService(r Repository, clock Clock) Service {
+	return &service{
+		repository: r,
+		clock:      clock,
+	}
+}
+
+func (s *service) GetAll(ctx context.Context, status string, sectionID int) ([]domain.Excursion, error) {
+	if status != "" && status != domain.ExcursionOpen && status != domain.ExcursionClosed {
+		logging.Log(ErrInvalidStatus)
+		return nil, ErrInvalidStatus
+	}
+	return s.repository.GetAll(ctx, status, sectionID)
+}
+
+// Evaluate keeps a single open excursion per batch and section, an excursion is also closed
+// when its batch ran out of stock or was moved to another section
+func (s *service) Evaluate(ctx context.Context) (opened int, closed int, err error) {
+	limits, err := s.repository.GetBatchLimits(ctx)
+	if err != nil {
+		logging.Log(err)
+		return 0, 0, err
+	}
+	open, err := s.repository.GetAll(ctx, domain.ExcursionOpen, 0)
+	if err != nil {
+		logging.Log(err)
+		return 0, 0, err
+	}
+	now := s.clock()
+
+	type key struct{ batch, section int }
+	current := map[key]domain.Excursion{}
+	for _, e := range open {
+		current[key{e.ProductBatchID, e.SectionID}] = e
+	}
+
+	for _, l := range limits {
+		k := key{l.ProductBatchID, l.SectionID}
+		e, isOpen := current[k]
+		if !l.Exceeded() {
+			continue
+		}
+		delete(current, k)
+		if isOpen {
+			if l.SectionCurrentTemperature > e.MaximumTemperature {
+				if err := s.repository.UpdateMaximum(ctx, e.ID, l.SectionCurrentTemperature); err != nil {
+					logging.Log(err)
+					return opened, closed, err
+				}
+			}
+			continue
+		}
+		excursion := domain.Excursion{
+			SectionID:          l.SectionID,
+			ProductBatchID:     l.ProductBatchID,
+			LimitTemperature:   l.LimitTemperature,
+			MaximumTemperature: l.SectionCurrentTemperature,
+			StartedAt:          now,
+		}
+		if _, err := s.repository.Open(ctx, excursion); err != nil {
+			logging.Log(err)
+			return opened, closed, err
+		}
+		opened++
+	}
+
+	// the excursions left are the ones whose batch is not too warm anymore
+	for _, e := range current {
+		if err := s.repository.Close(ctx, e.ID, now); err != nil {
+			logging.Log(err)
+			return opened, closed, err
+		}
+		closed++
+	}
+	return opened, closed, nil
+}
+
+// Run evaluates the excursions every interval until the context is done
+func Run(ctx context.Context, s Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, _, err := s.Evaluate(ctx); err != nil {
+				logging.Log(err)
+			}
+		}
+	}
+}
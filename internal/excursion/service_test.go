@@ -0,0 +1,135 @@
+package excursion
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+var evaluationTime = time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+
+func fixedClock() time.Time {
+	return evaluationTime
+}
+
+func TestEvaluateOpensExcursion(t *testing.T) {
+	// ARRANGE
+	repository := MockRepository{DataMockLimits: []domain.BatchTemperatureLimit{
+		{ProductBatchID: 1, SectionID: 1, SectionCurrentTemperature: -10, LimitTemperature: -18},
+		{ProductBatchID: 2, SectionID: 1, SectionCurrentTemperature: -10, LimitTemperature: 4},
+	}}
+	service := NewService(&repository, fixedClock)
+
+	// ACT
+	opened, closed, err := service.Evaluate(context.TODO())
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, 1, opened)
+	assert.Equal(t, 0, closed)
+	assert.Equal(t, []domain.Excursion{{ID: 1, SectionID: 1, ProductBatchID: 1, LimitTemperature: -18, MaximumTemperature: -10, StartedAt: evaluationTime}}, repository.DataMockExcursions)
+	assert.Equal(t, []int{1}, repository.QuarantinedBatches)
+}
+
+func TestEvaluateKeepsOpenExcursion(t *testing.T) {
+	// ARRANGE
+	started := evaluationTime.Add(-time.Hour)
+	repository := MockRepository{
+		DataMockLimits:     []domain.BatchTemperatureLimit{{ProductBatchID: 1, SectionID: 1, SectionCurrentTemperature: -5, LimitTemperature: -18}},
+		DataMockExcursions: []domain.Excursion{{ID: 1, SectionID: 1, ProductBatchID: 1, LimitTemperature: -18, MaximumTemperature: -10, StartedAt: started}},
+	}
+	service := NewService(&repository, fixedClock)
+
+	// ACT
+	opened, closed, err := service.Evaluate(context.TODO())
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, 0, opened)
+	assert.Equal(t, 0, closed)
+	assert.Len(t, repository.DataMockExcursions, 1)
+	assert.Equal(t, float32(-5), repository.DataMockExcursions[0].MaximumTemperature)
+	assert.Nil(t, repository.DataMockExcursions[0].EndedAt)
+	assert.Empty(t, repository.QuarantinedBatches)
+}
+
+func TestEvaluateClosesExcursion(t *testing.T) {
+	// ARRANGE
+	started := evaluationTime.Add(-time.Hour)
+	repository := MockRepository{
+		DataMockLimits: []domain.BatchTemperatureLimit{{ProductBatchID: 1, SectionID: 1, SectionCurrentTemperature: -20, LimitTemperature: -18}},
+		DataMockExcursions: []domain.Excursion{
+			{ID: 1, SectionID: 1, ProductBatchID: 1, LimitTemperature: -18, MaximumTemperature: -10, StartedAt: started},
+			// the batch 2 was moved to another section, so its excursion in the section 1 is over
+			{ID: 2, SectionID: 1, ProductBatchID: 2, LimitTemperature: -18, MaximumTemperature: -10, StartedAt: started},
+		},
+	}
+	service := NewService(&repository, fixedClock)
+
+	// ACT
+	opened, closed, err := service.Evaluate(context.TODO())
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, 0, opened)
+	assert.Equal(t, 2, closed)
+	assert.Equal(t, evaluationTime, *repository.DataMockExcursions[0].EndedAt)
+	assert.Equal(t, evaluationTime, *repository.DataMockExcursions[1].EndedAt)
+}
+
+func TestEvaluateError(t *testing.T) {
+	// ARRANGE
+	repository := MockRepository{Error: ErrInternal}
+	service := NewService(&repository, fixedClock)
+
+	// ACT
+	_, _, err := service.Evaluate(context.TODO())
+
+	// ASSERT
+	assert.EqualError(t, err, ErrInternal.Error())
+}
+
+func TestGetAllByStatus(t *testing.T) {
+	// ARRANGE
+	ended := evaluationTime
+	repository := MockRepository{DataMockExcursions: []domain.Excursion{
+		{ID: 1, SectionID: 1, ProductBatchID: 1, StartedAt: evaluationTime.Add(-time.Hour), EndedAt: &ended},
+		{ID: 2, SectionID: 2, ProductBatchID: 2, StartedAt: evaluationTime},
+	}}
+	service := NewService(&repository, fixedClock)
+
+	// ACT
+	open, errOpen := service.GetAll(context.TODO(), domain.ExcursionOpen, 0)
+	closed, errClosed := service.GetAll(context.TODO(), domain.ExcursionClosed, 0)
+	_, errInvalid := service.GetAll(context.TODO(), "pending", 0)
+
+	// ASSERT
+	assert.NoError(t, errOpen)
+	assert.NoError(t, errClosed)
+	assert.Equal(t, 2, open[0].ID)
+	assert.Equal(t, 1, closed[0].ID)
+	assert.EqualError(t, errInvalid, ErrInvalidStatus.Error())
+}
+
+func TestRunEvaluatesUntilDone(t *testing.T) {
+	// ARRANGE
+	repository := MockRepository{DataMockLimits: []domain.BatchTemperatureLimit{{ProductBatchID: 1, SectionID: 1, SectionCurrentTemperature: -10, LimitTemperature: -18}}}
+	service := NewService(&repository, fixedClock)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	// ACT
+	go func() {
+		Run(ctx, service, time.Millisecond)
+		close(done)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	<-done
+
+	// ASSERT
+	assert.Len(t, repository.DataMockExcursions, 1)
+}
@@ -0,0 +1,35 @@
+package geo
+
+import (
+	"math"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+)
+
+// EarthRadiusKm is the mean radius of the earth used by Distance
+const EarthRadiusKm = 6371.0
+
+// kmPerDegree is the length of a degree of latitude, the same everywhere on a sphere
+const kmPerDegree = EarthRadiusKm * math.Pi / 180
+
+// Distance returns the great-circle distance in kilometers between two points, using the haversine formula
+func Distance(from domain.Coordinates, to domain.Coordinates) float64 {
+	lat1 := radians(from.Latitude)
+	lat2 := radians(to.Latitude)
+	dLat := lat2 - lat1
+	dLon := radians(to.Longitude - from.Longitude)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	// rounding can leave h slightly above 1 for antipodal points
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// latitudeBand returns the latitudes every point within radiusKm of the center lies between
+func latitudeBand(center domain.Coordinates, radiusKm float64) (float64, float64) {
+	delta := radiusKm / kmPerDegree
+	return math.Max(-90, center.Latitude-delta), math.Min(90, center.Latitude+delta)
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
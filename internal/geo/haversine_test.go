@@ -0,0 +1,42 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	buenosAires = domain.Coordinates{Latitude: -34.603722, Longitude: -58.381592}
+	laPlata     = domain.Coordinates{Latitude: -34.921450, Longitude: -57.954530}
+	rosario     = domain.Coordinates{Latitude: -32.944243, Longitude: -60.650539}
+	cordoba     = domain.Coordinates{Latitude: -31.420083, Longitude: -64.188776}
+)
+
+// TestDistance passes when known distances between cities are within a kilometer
+func TestDistance(t *testing.T) {
+	assert.InDelta(t, 52.6, Distance(buenosAires, laPlata), 1)
+	assert.InDelta(t, 279.3, Distance(buenosAires, rosario), 1)
+	assert.InDelta(t, 646.7, Distance(buenosAires, cordoba), 1)
+	assert.Equal(t, Distance(buenosAires, cordoba), Distance(cordoba, buenosAires))
+	assert.Zero(t, Distance(rosario, rosario))
+}
+
+// TestDistance_Antipodes passes when opposite points are half the circumference apart
+func TestDistance_Antipodes(t *testing.T) {
+	d := Distance(domain.Coordinates{Latitude: 0, Longitude: 0}, domain.Coordinates{Latitude: 0, Longitude: 180})
+
+	assert.InDelta(t, 20015.1, d, 0.1)
+}
+
+// TestLatitudeBand passes when the band covers the radius and stops at the poles
+func TestLatitudeBand(t *testing.T) {
+	min, max := latitudeBand(buenosAires, 111.195)
+	assert.InDelta(t, -35.603722, min, 0.001)
+	assert.InDelta(t, -33.603722, max, 0.001)
+
+	min, max = latitudeBand(domain.Coordinates{Latitude: 89.5}, 500)
+	assert.Less(t, min, 89.5)
+	assert.Equal(t, 90.0, max)
+}
@@ -0,0 +1,118 @@
+package geo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+)
+
+// Errors
+var (
+	ErrLocalityNotFound = errors.New("locality not found")
+	ErrNoCoordinates    = errors.New("locality has no coordinates")
+	ErrInternal         = errors.New("internal server error")
+)
+
+// Queries
+const (
+	GET_LOCALITY_COORDINATES_QUERY = "SELECT latitude, longitude FROM localities WHERE id = ?;"
+	GET_WAREHOUSES_QUERY           = `SELECT w.id, COALESCE(w.address, ''), COALESCE(w.telephone, ''), COALESCE(w.warehouse_code, ''), COALESCE(w.minimum_capacity, 0), COALESCE(w.minimum_temperature, 0), w.temperature_policy, w.locality_id, l.latitude, l.longitude
+		FROM warehouses AS w
+		INNER JOIN localities AS l ON l.id = w.locality_id
+		WHERE l.latitude IS NOT NULL AND l.longitude IS NOT NULL;`
+	GET_CARRIES_QUERY = `SELECT c.id, COALESCE(c.cid, ''), COALESCE(c.company_name, ''), COALESCE(c.address, ''), COALESCE(c.telephone, ''), c.locality_id, l.latitude, l.longitude
+		FROM carries AS c
+		INNER JOIN localities AS l ON l.id = c.locality_id
+		WHERE l.latitude BETWEEN ? AND ? AND l.longitude IS NOT NULL;`
+)
+
+// Repository reads the coordinates of the localities and of the warehouses and carries located in them.
+// Warehouses and carries whose locality has no coordinates are left out.
+type Repository interface {
+	// GetCoordinates returns the coordinates of the locality, ErrLocalityNotFound or ErrNoCoordinates
+	GetCoordinates(ctx context.Context, localityID string) (domain.Coordinates, error)
+	GetWarehouses(ctx context.Context) ([]domain.WarehouseDistance, error)
+	// GetCarries returns the carries whose locality lies between the given latitudes
+	GetCarries(ctx context.Context, minLatitude float64, maxLatitude float64) ([]domain.CarryDistance, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) GetCoordinates(ctx context.Context, localityID string) (domain.Coordinates, error) {
+	var latitude, longitude sql.NullFloat64
+	err := r.db.QueryRowContext(ctx, GET_LOCALITY_COORDINATES_QUERY, localityID).Scan(&latitude, &longitude)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logging.Log(ErrLocalityNotFound)
+			return domain.Coordinates{}, ErrLocalityNotFound
+		}
+		logging.Log(err)
+		return domain.Coordinates{}, ErrInternal
+	}
+	if !latitude.Valid || !longitude.Valid {
+		logging.Log(ErrNoCoordinates)
+		return domain.Coordinates{}, ErrNoCoordinates
+	}
+	return domain.Coordinates{Latitude: latitude.Float64, Longitude: longitude.Float64}, nil
+}
+
+func (r *repository) GetWarehouses(ctx context.Context) ([]domain.WarehouseDistance, error) {
+	rows, err := r.db.QueryContext(ctx, GET_WAREHOUSES_QUERY)
+	if err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+
+	warehouses := []domain.WarehouseDistance{}
+	for rows.Next() {
+		w := domain.WarehouseDistance{}
+		err := rows.Scan(&w.ID, &w.Address, &w.Telephone, &w.WarehouseCode, &w.MinimumCapacity, &w.MinimumTemperature, &w.TemperaturePolicy, &w.LocalityID,
+			&w.Latitude, &w.Longitude)
+		if err != nil {
+			logging.Log(err)
+			return nil, ErrInternal
+		}
+		warehouses = append(warehouses, w)
+	}
+	if err := rows.Err(); err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	return warehouses, nil
+}
+
+func (r *repository) GetCarries(ctx context.Context, minLatitude float64, maxLatitude float64) ([]domain.CarryDistance, error) {
+	rows, err := r.db.QueryContext(ctx, GET_CARRIES_QUERY, minLatitude, maxLatitude)
+	if err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+
+	carries := []domain.CarryDistance{}
+	for rows.Next() {
+		c := domain.CarryDistance{}
+		if err := rows.Scan(&c.ID, &c.CID, &c.CompanyName, &c.Address, &c.Telephone, &c.Locality_id, &c.Latitude, &c.Longitude); err != nil {
+			logging.Log(err)
+			return nil, ErrInternal
+		}
+		carries = append(carries, c)
+	}
+	if err := rows.Err(); err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	return carries, nil
+}
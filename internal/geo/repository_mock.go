@@ -0,0 +1,38 @@
+package geo
+
+import (
+	"context"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+)
+
+type MockRepository struct {
+	Localities  map[string]domain.Coordinates
+	Warehouses  []domain.WarehouseDistance
+	Carries     []domain.CarryDistance
+	ErrorMock   error
+	LastLatBand [2]float64
+}
+
+func (m *MockRepository) GetCoordinates(ctx context.Context, localityID string) (domain.Coordinates, error) {
+	c, ok := m.Localities[localityID]
+	if !ok {
+		return domain.Coordinates{}, ErrLocalityNotFound
+	}
+	return c, nil
+}
+
+func (m *MockRepository) GetWarehouses(ctx context.Context) ([]domain.WarehouseDistance, error) {
+	if m.ErrorMock != nil {
+		return nil, m.ErrorMock
+	}
+	return append([]domain.WarehouseDistance{}, m.Warehouses...), nil
+}
+
+func (m *MockRepository) GetCarries(ctx context.Context, minLatitude float64, maxLatitude float64) ([]domain.CarryDistance, error) {
+	m.LastLatBand = [2]float64{minLatitude, maxLatitude}
+	if m.ErrorMock != nil {
+		return nil, m.ErrorMock
+	}
+	return append([]domain.CarryDistance{}, m.Carries...), nil
+}
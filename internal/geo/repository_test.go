@@ -0,0 +1,116 @@
+package geo
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetCoordinates passes when the coordinates of the locality are returned
+func TestGetCoordinates(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(GET_LOCALITY_COORDINATES_QUERY)).WithArgs("1000").
+		WillReturnRows(sqlmock.NewRows([]string{"latitude", "longitude"}).AddRow(buenosAires.Latitude, buenosAires.Longitude))
+
+	// Act
+	result, err := NewRepository(db).GetCoordinates(context.TODO(), "1000")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, buenosAires, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestGetCoordinates_Fail passes when the locality does not exist or has no coordinates
+func TestGetCoordinates_Fail(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(GET_LOCALITY_COORDINATES_QUERY)).WithArgs("9999").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(regexp.QuoteMeta(GET_LOCALITY_COORDINATES_QUERY)).WithArgs("1000").
+		WillReturnRows(sqlmock.NewRows([]string{"latitude", "longitude"}).AddRow(nil, nil))
+
+	// Act
+	repo := NewRepository(db)
+	_, errNotFound := repo.GetCoordinates(context.TODO(), "9999")
+	_, errNoCoordinates := repo.GetCoordinates(context.TODO(), "1000")
+
+	// Assert
+	assert.EqualError(t, errNotFound, ErrLocalityNotFound.Error())
+	assert.EqualError(t, errNoCoordinates, ErrNoCoordinates.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestGetWarehouses passes when the warehouses come with the coordinates of their locality
+func TestGetWarehouses(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	columns := []string{"id", "address", "telephone", "warehouse_code", "minimum_capacity", "minimum_temperature", "temperature_policy", "locality_id", "latitude", "longitude"}
+	rows := sqlmock.NewRows(columns).AddRow(1, "Monroe 1230", "47470000", "DHM1", 10, 0, domain.TemperaturePolicyReject, "1900", laPlata.Latitude, laPlata.Longitude)
+	mock.ExpectQuery(regexp.QuoteMeta(GET_WAREHOUSES_QUERY)).WillReturnRows(rows)
+
+	// Act
+	result, err := NewRepository(db).GetWarehouses(context.TODO())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.WarehouseDistance{{
+		Warehouse:   domain.Warehouse{ID: 1, Address: "Monroe 1230", Telephone: "47470000", WarehouseCode: "DHM1", MinimumCapacity: 10, TemperaturePolicy: domain.TemperaturePolicyReject, LocalityID: "1900"},
+		Coordinates: laPlata,
+	}}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestGetCarries passes when the latitude band is sent and the carries come with their coordinates
+func TestGetCarries(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	columns := []string{"id", "cid", "company_name", "address", "telephone", "locality_id", "latitude", "longitude"}
+	rows := sqlmock.NewRows(columns).AddRow(1, "CID1", "Fast", "Calle 1", "4444", "2000", rosario.Latitude, rosario.Longitude)
+	mock.ExpectQuery(regexp.QuoteMeta(GET_CARRIES_QUERY)).WithArgs(-36.0, -32.0).WillReturnRows(rows)
+
+	// Act
+	result, err := NewRepository(db).GetCarries(context.TODO(), -36, -32)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.CarryDistance{{
+		Carry:       domain.Carry{ID: 1, CID: "CID1", CompanyName: "Fast", Address: "Calle 1", Telephone: "4444", Locality_id: "2000"},
+		Coordinates: rosario,
+	}}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestGetCarries_FailQuery passes when the query fails
+func TestGetCarries_FailQuery(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(GET_CARRIES_QUERY)).WillReturnError(sql.ErrConnDone)
+
+	// Act
+	_, err = NewRepository(db).GetCarries(context.TODO(), -36, -32)
+
+	// Assert
+	assert.EqualError(t, err, ErrInternal.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,104 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+)
+
+var (
+	ErrInvalidLimit  = errors.New("limit must be a number greater than zero")
+	ErrInvalidRadius = errors.New("radius_km must be a number greater than zero")
+)
+
+// Service answers distance queries between localities and the warehouses and carries located in them.
+// Distances are great-circle distances in kilometers rounded to meters.
+type Service interface {
+	// NearestWarehouses returns up to limit warehouses ordered by their distance to the locality, closest first
+	NearestWarehouses(ctx context.Context, localityID string, limit int) ([]domain.WarehouseDistance, error)
+	// CarriesWithin returns the carries at most radiusKm away from the locality, closest first
+	CarriesWithin(ctx context.Context, localityID string, radiusKm float64) ([]domain.CarryDistance, error)
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(r Repository) Service {
+	return &service{
+		repository: r,
+	}
+}
+
+func (s *service) NearestWarehouses(ctx context.Context, localityID string, limit int) ([]domain.WarehouseDistance, error) {
+	if limit <= 0 {
+		logging.Log(ErrInvalidLimit)
+		return nil, ErrInvalidLimit
+	}
+	center, err := s.repository.GetCoordinates(ctx, localityID)
+	if err != nil {
+		return nil, err
+	}
+	warehouses, err := s.repository.GetWarehouses(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range warehouses {
+		warehouses[i].DistanceKm = roundKm(Distance(center, warehouses[i].Coordinates))
+	}
+	sort.SliceStable(warehouses, func(i, j int) bool {
+		if warehouses[i].DistanceKm != warehouses[j].DistanceKm {
+			return warehouses[i].DistanceKm < warehouses[j].DistanceKm
+		}
+		return warehouses[i].ID < warehouses[j].ID
+	})
+	if len(warehouses) > limit {
+		warehouses = warehouses[:limit]
+	}
+	return warehouses, nil
+}
+
+// CarriesWithin only asks the repository for the carries in the latitudes the radius can reach,
+// the exact distance is checked here
+func (s *service) CarriesWithin(ctx context.Context, localityID string, radiusKm float64) ([]domain.CarryDistance, error) {
+	if radiusKm <= 0 || math.IsNaN(radiusKm) || math.IsInf(radiusKm, 0) {
+		logging.Log(ErrInvalidRadius)
+		return nil, ErrInvalidRadius
+	}
+	center, err := s.repository.GetCoordinates(ctx, localityID)
+	if err != nil {
+		return nil, err
+	}
+	minLatitude, maxLatitude := latitudeBand(center, radiusKm)
+	candidates, err := s.repository.GetCarries(ctx, minLatitude, maxLatitude)
+	if err != nil {
+		return nil, err
+	}
+
+	carries := []domain.CarryDistance{}
+	for _, c := range candidates {
+		distance := Distance(center, c.Coordinates)
+		if distance > radiusKm {
+			continue
+		}
+		c.DistanceKm = roundKm(distance)
+		carries = append(carries, c)
+	}
+	sort.SliceStable(carries, func(i, j int) bool {
+		if carries[i].DistanceKm != carries[j].DistanceKm {
+			return carries[i].DistanceKm < carries[j].DistanceKm
+		}
+		return carries[i].ID < carries[j].ID
+	})
+	return carries, nil
+}
+
+// roundKm rounds a distance to meters
+func roundKm(km float64) float64 {
+	return math.Round(km*1000) / 1000
+}
@@ -0,0 +1,91 @@
+package geo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	logging.InitLog(nil)
+}
+
+func warehouseAt(id int, c domain.Coordinates) domain.WarehouseDistance {
+	return domain.WarehouseDistance{Warehouse: domain.Warehouse{ID: id}, Coordinates: c}
+}
+
+func carryAt(id int, c domain.Coordinates) domain.CarryDistance {
+	return domain.CarryDistance{Carry: domain.Carry{ID: id}, Coordinates: c}
+}
+
+// TestNearestWarehouses passes when the warehouses come closest first and are cut at the limit
+func TestNearestWarehouses(t *testing.T) {
+	// Arrange
+	repo := MockRepository{
+		Localities: map[string]domain.Coordinates{"1000": buenosAires},
+		Warehouses: []domain.WarehouseDistance{warehouseAt(1, cordoba), warehouseAt(2, laPlata), warehouseAt(3, rosario)},
+	}
+	service := NewService(&repo)
+
+	// Act
+	result, err := service.NearestWarehouses(context.TODO(), "1000", 2)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, 2, result[0].ID)
+	assert.Equal(t, 3, result[1].ID)
+	assert.Equal(t, 52.632, result[0].DistanceKm)
+}
+
+// TestNearestWarehouses_Fail passes when the limit is not positive or the locality does not exist
+func TestNearestWarehouses_Fail(t *testing.T) {
+	// Arrange
+	service := NewService(&MockRepository{})
+
+	// Act
+	_, errLimit := service.NearestWarehouses(context.TODO(), "1000", 0)
+	_, errLocality := service.NearestWarehouses(context.TODO(), "1000", 5)
+
+	// Assert
+	assert.EqualError(t, errLimit, ErrInvalidLimit.Error())
+	assert.EqualError(t, errLocality, ErrLocalityNotFound.Error())
+}
+
+// TestCarriesWithin passes when only the carries inside the radius are returned, closest first
+func TestCarriesWithin(t *testing.T) {
+	// Arrange
+	repo := MockRepository{
+		Localities: map[string]domain.Coordinates{"1000": buenosAires},
+		Carries:    []domain.CarryDistance{carryAt(1, rosario), carryAt(2, cordoba), carryAt(3, laPlata), carryAt(4, buenosAires)},
+	}
+	service := NewService(&repo)
+
+	// Act
+	result, err := service.CarriesWithin(context.TODO(), "1000", 300)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, result, 3)
+	assert.Equal(t, []int{4, 3, 1}, []int{result[0].ID, result[1].ID, result[2].ID})
+	assert.Zero(t, result[0].DistanceKm)
+	assert.Less(t, repo.LastLatBand[0], buenosAires.Latitude)
+	assert.Greater(t, repo.LastLatBand[1], rosario.Latitude)
+}
+
+// TestCarriesWithin_Fail passes when the radius is not positive
+func TestCarriesWithin_Fail(t *testing.T) {
+	// Arrange
+	repo := MockRepository{Localities: map[string]domain.Coordinates{"1000": buenosAires}}
+	service := NewService(&repo)
+
+	// Act
+	_, err := service.CarriesWithin(context.TODO(), "1000", -1)
+
+	// Assert
+	assert.EqualError(t, err, ErrInvalidRadius.Error())
+	assert.Equal(t, [2]float64{}, repo.LastLatBand)
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
@@ -15,16 +16,31 @@ import (
 const (
 	GetAllEmployeesInboundOrders = `SELECT e.id, e.card_number_id, e.first_name, e.last_name, e.warehouse_id, COUNT(ib.id) AS inbound_orders_count
 	FROM inbound_orders AS ib
-	RIGHT JOIN employees AS e ON ib.employee_id = e.id
+	RIGHT JOIN employees AS e ON ib.employee_id = e.id AND ib.cancelled_at IS NULL
 	GROUP BY e.id;`
 	GetEmployeeInboundOrders = `SELECT e.id, e.card_number_id, e.first_name, e.last_name, e.warehouse_id, COUNT(ib.id) AS inbound_orders_count
 	FROM inbound_orders AS ib
 	INNER JOIN employees AS e ON ib.employee_id = e.id
+	WHERE ib.cancelled_at IS NULL
 	GROUP BY e.id
 	HAVING e.id = ?;`
 	SaveInboundOrder = `INSERT INTO inbound_orders (order_date, order_number, employee_id, product_batch_id, warehouse_id)
 	VALUES (?, ?, ?, ?, ?);`
 	InboundOrderExists = `SELECT order_number FROM inbound_orders WHERE order_number = ?;`
+	GetInboundOrders   = `SELECT id, DATE_FORMAT(order_date, '%Y-%m-%d'), order_number, employee_id, product_batch_id, warehouse_id, cancelled_at
+	FROM inbound_orders`
+	GetInboundOrder    = GetInboundOrders + ` WHERE id = ?;`
+	UpdateInboundOrder = `UPDATE inbound_orders SET order_date = ?, order_number = ?, employee_id = ?, product_batch_id = ?, warehouse_id = ?
+	WHERE id = ?;`
+	CancelInboundOrder  = `UPDATE inbound_orders SET cancelled_at = ? WHERE id = ? AND cancelled_at IS NULL;`
+	GetSectionWarehouse = `SELECT warehouse_id FROM sections WHERE id = ?;`
+
+	GetEmployeesDailyInbound = `SELECT e.id, e.card_number_id, e.first_name, e.last_name, e.warehouse_id,
+	DATE_FORMAT(ib.order_date, '%Y-%m-%d'), COUNT(ib.id), COALESCE(SUM(pb.initial_quantity), 0)
+	FROM inbound_orders AS ib
+	INNER JOIN employees AS e ON e.id = ib.employee_id
+	INNER JOIN product_batches AS pb ON pb.id = ib.product_batch_id
+	WHERE ib.cancelled_at IS NULL`
 )
 
 const (
@@ -37,6 +53,8 @@ var (
 	ErrEmployeeNonExistent     = errors.New("the associated employee does not exist")
 	ErrWarehouseNonExistent    = errors.New("the associated warehouse does not exist")
 	ErrProductBatchNonExistent = errors.New("the associated product batch does not exist")
+	ErrSectionNonExistent      = errors.New("the associated section does not exist")
+	ErrSectionNotInWarehouse   = errors.New("the section does not belong to the warehouse of the inbound order")
 )
 
 // Repository encapsulates the storage of an inbound order.
@@ -44,15 +62,29 @@ type Repository interface {
 	GetAllEmployeesInboundOrders(ctx context.Context) ([]domain.EmployeeWithInboundOrders, error)
 	GetEmployeeInboundOrders(ctx context.Context, id int) (domain.EmployeeWithInboundOrders, error)
 	Save(ctx context.Context, inboundOrder domain.InboundOrder) (int, error)
+	GetAll(ctx context.Context, filter domain.InboundOrderFilter) ([]domain.InboundOrder, error)
+	Get(ctx context.Context, id int) (domain.InboundOrder, error)
+	Update(ctx context.Context, inboundOrder domain.InboundOrder) error
+	Cancel(ctx context.Context, id int, at time.Time) error
+	Receive(ctx context.Context, receipt domain.InboundReceipt) (domain.InboundReceipt, error)
+	GetEmployeesDailyInbound(ctx context.Context, filter domain.ProductivityFilter) ([]domain.EmployeeDailyInbound, error)
+}
+
+// Batches stores the product batch received with an inbound order inside the transaction of the order,
+// checking the section of the batch the same way a product batch created on its own is checked
+type Batches interface {
+	SaveTx(ctx context.Context, tx *sql.Tx, pb domain.ProductBatch) (domain.ProductBatch, error)
 }
 
 type repository struct {
-	db *sql.DB
+	db      *sql.DB
+	batches Batches
 }
 
-func NewRepository(db *sql.DB) Repository {
+func NewRepository(db *sql.DB, batches Batches) Repository {
 	return &repository{
-		db: db,
+		db:      db,
+		batches: batches,
 	}
 }
 
@@ -107,29 +139,7 @@ func (r *repository) Save(ctx context.Context, inboundOrder domain.InboundOrder)
 		&inboundOrder.EmployeeID, &inboundOrder.ProductBatchID, &inboundOrder.WarehouseID)
 
 	if err != nil {
-		message, ok := err.(*mysql.MySQLError)
-		if ok {
-			switch message.Number {
-			case ForeignKeyConstraint:
-				if strings.Contains(message.Message, "employees") {
-					logging.Log(ErrEmployeeNonExistent)
-					return 0, ErrEmployeeNonExistent
-				}
-				if strings.Contains(message.Message, "warehouses") {
-					logging.Log(ErrWarehouseNonExistent)
-					return 0, ErrWarehouseNonExistent
-				}
-				if strings.Contains(message.Message, "product_batches") {
-					logging.Log(ErrProductBatchNonExistent)
-					return 0, ErrProductBatchNonExistent
-				}
-			case DuplicateKeyConstraint:
-				logging.Log(ErrInboundOrderAlreadyExists)
-				return 0, ErrInboundOrderAlreadyExists
-			}
-		}
-		logging.Log(err)
-		return 0, err
+		return 0, parseWriteError(err)
 	}
 
 	id, err := res.LastInsertId()
@@ -141,3 +151,293 @@ func (r *repository) Save(ctx context.Context, inboundOrder domain.InboundOrder)
 
 	return int(id), nil
 }
+
+// GetAll returns the inbound orders that match the filter, the most recent first
+func (r *repository) GetAll(ctx context.Context, filter domain.InboundOrderFilter) ([]domain.InboundOrder, error) {
+	query, args := buildGetAllQuery(filter)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		logging.Log(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var inboundOrders []domain.InboundOrder
+
+	for rows.Next() {
+		inboundOrder, err := scanInboundOrder(rows)
+		if err != nil {
+			logging.Log(err)
+			return nil, err
+		}
+		inboundOrders = append(inboundOrders, inboundOrder)
+	}
+
+	if err := rows.Err(); err != nil {
+		logging.Log(err)
+		return nil, err
+	}
+
+	return inboundOrders, nil
+}
+
+// Get returns the inbound order with the given id, or ErrInboundOrderNotFound
+func (r *repository) Get(ctx context.Context, id int) (domain.InboundOrder, error) {
+	inboundOrder, err := scanInboundOrder(r.db.QueryRowContext(ctx, GetInboundOrder, id))
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logging.Log(ErrInboundOrderNotFound)
+			return domain.InboundOrder{}, ErrInboundOrderNotFound
+		}
+		logging.Log(err)
+		return domain.InboundOrder{}, err
+	}
+
+	return inboundOrder, nil
+}
+
+// Update stores every field of the inbound order
+func (r *repository) Update(ctx context.Context, inboundOrder domain.InboundOrder) error {
+	_, err := r.db.ExecContext(ctx, UpdateInboundOrder, inboundOrder.OrderDate, inboundOrder.OrderNumber,
+		inboundOrder.EmployeeID, inboundOrder.ProductBatchID, inboundOrder.WarehouseID, inboundOrder.ID)
+
+	if err != nil {
+		return parseWriteError(err)
+	}
+
+	return nil
+}
+
+// Cancel marks the inbound order as cancelled at the given instant,
+// or returns ErrInboundOrderCancelled if it was already cancelled
+func (r *repository) Cancel(ctx context.Context, id int, at time.Time) error {
+	res, err := r.db.ExecContext(ctx, CancelInboundOrder, at, id)
+
+	if err != nil {
+		logging.Log(err)
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+
+	if err != nil {
+		logging.Log(err)
+		return err
+	}
+
+	if affected == 0 {
+		logging.Log(ErrInboundOrderCancelled)
+		return ErrInboundOrderCancelled
+	}
+
+	return nil
+}
+
+// Receive stores the received product batch and the inbound order that references it inside a single transaction.
+// The section of the batch must belong to the warehouse of the order, the batch itself is checked and stored by batches
+func (r *repository) Receive(ctx context.Context, receipt domain.InboundReceipt) (domain.InboundReceipt, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		logging.Log(err)
+		return domain.InboundReceipt{}, err
+	}
+
+	received, err := r.receive(ctx, tx, receipt)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return domain.InboundReceipt{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.Log(err)
+		return domain.InboundReceipt{}, err
+	}
+
+	return received, nil
+}
+
+func (r *repository) receive(ctx context.Context, tx *sql.Tx, receipt domain.InboundReceipt) (domain.InboundReceipt, error) {
+	var warehouseID int
+
+	err := tx.QueryRowContext(ctx, GetSectionWarehouse, receipt.ProductBatch.SectionID).Scan(&warehouseID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logging.Log(ErrSectionNonExistent)
+			return domain.InboundReceipt{}, ErrSectionNonExistent
+		}
+		logging.Log(err)
+		return domain.InboundReceipt{}, err
+	}
+
+	if warehouseID != receipt.WarehouseID {
+		logging.Log(ErrSectionNotInWarehouse)
+		return domain.InboundReceipt{}, ErrSectionNotInWarehouse
+	}
+
+	batch, err := r.batches.SaveTx(ctx, tx, receipt.ProductBatch)
+
+	if err != nil {
+		return domain.InboundReceipt{}, err
+	}
+
+	batch.AvailableQuantity = batch.CurrentQuantity
+	receipt.ProductBatch = batch
+	receipt.ProductBatchID = batch.ID
+
+	res, err := tx.ExecContext(ctx, SaveInboundOrder, receipt.OrderDate, receipt.OrderNumber,
+		receipt.EmployeeID, receipt.ProductBatchID, receipt.WarehouseID)
+
+	if err != nil {
+		return domain.InboundReceipt{}, parseWriteError(err)
+	}
+
+	orderID, err := res.LastInsertId()
+
+	if err != nil {
+		logging.Log(err)
+		return domain.InboundReceipt{}, err
+	}
+
+	receipt.ID = int(orderID)
+	return receipt, nil
+}
+
+// GetEmployeesDailyInbound returns, for every employee and day, the inbound orders that are not cancelled and
+// the units received with them, ordered by employee and day
+func (r *repository) GetEmployeesDailyInbound(ctx context.Context, filter domain.ProductivityFilter) ([]domain.EmployeeDailyInbound, error) {
+	query, args := buildDailyInboundQuery(filter)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		logging.Log(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []domain.EmployeeDailyInbound
+
+	for rows.Next() {
+		var day domain.EmployeeDailyInbound
+		err := rows.Scan(&day.ID, &day.CardNumberID, &day.FirstName, &day.LastName, &day.WarehouseID,
+			&day.Day.Date, &day.Day.InboundOrders, &day.Day.ReceivedUnits)
+		if err != nil {
+			logging.Log(err)
+			return nil, err
+		}
+		days = append(days, day)
+	}
+
+	if err := rows.Err(); err != nil {
+		logging.Log(err)
+		return nil, err
+	}
+
+	return days, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanInboundOrder(row rowScanner) (domain.InboundOrder, error) {
+	var inboundOrder domain.InboundOrder
+	var cancelledAt sql.NullTime
+
+	err := row.Scan(&inboundOrder.ID, &inboundOrder.OrderDate, &inboundOrder.OrderNumber, &inboundOrder.EmployeeID,
+		&inboundOrder.ProductBatchID, &inboundOrder.WarehouseID, &cancelledAt)
+
+	if err != nil {
+		return domain.InboundOrder{}, err
+	}
+
+	if cancelledAt.Valid {
+		inboundOrder.CancelledAt = &cancelledAt.Time
+	}
+
+	return inboundOrder, nil
+}
+
+func buildGetAllQuery(filter domain.InboundOrderFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.WarehouseID != 0 {
+		conditions = append(conditions, "warehouse_id = ?")
+		args = append(args, filter.WarehouseID)
+	}
+	if filter.EmployeeID != 0 {
+		conditions = append(conditions, "employee_id = ?")
+		args = append(args, filter.EmployeeID)
+	}
+	if filter.ProductBatchID != 0 {
+		conditions = append(conditions, "product_batch_id = ?")
+		args = append(args, filter.ProductBatchID)
+	}
+	if filter.From != "" {
+		conditions = append(conditions, "order_date >= ?")
+		args = append(args, filter.From)
+	}
+	if filter.To != "" {
+		conditions = append(conditions, "order_date <= ?")
+		args = append(args, filter.To)
+	}
+
+	query := GetInboundOrders
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	return query + " ORDER BY order_date DESC, id DESC;", args
+}
+
+func buildDailyInboundQuery(filter domain.ProductivityFilter) (string, []interface{}) {
+	query := GetEmployeesDailyInbound
+	var args []interface{}
+
+	if filter.From != "" {
+		query += " AND ib.order_date >= ?"
+		args = append(args, filter.From)
+	}
+	if filter.To != "" {
+		query += " AND ib.order_date <= ?"
+		args = append(args, filter.To)
+	}
+	if filter.WarehouseID != 0 {
+		query += " AND ib.warehouse_id = ?"
+		args = append(args, filter.WarehouseID)
+	}
+
+	return query + " GROUP BY e.id, ib.order_date ORDER BY e.id, ib.order_date;", args
+}
+
+// parseWriteError maps the foreign key and unique constraint errors of MySQL to the errors of the package
+func parseWriteError(err error) error {
+	message, ok := err.(*mysql.MySQLError)
+	if ok {
+		switch message.Number {
+		case ForeignKeyConstraint:
+			if strings.Contains(message.Message, "employees") {
+				logging.Log(ErrEmployeeNonExistent)
+				return ErrEmployeeNonExistent
+			}
+			if strings.Contains(message.Message, "warehouses") {
+				logging.Log(ErrWarehouseNonExistent)
+				return ErrWarehouseNonExistent
+			}
+			if strings.Contains(message.Message, "product_batches") {
+				logging.Log(ErrProductBatchNonExistent)
+				return ErrProductBatchNonExistent
+			}
+		case DuplicateKeyConstraint:
+			logging.Log(ErrInboundOrderAlreadyExists)
+			return ErrInboundOrderAlreadyExists
+		}
+	}
+	logging.Log(err)
+	return err
+}
@@ -2,6 +2,8 @@ package inbound_order
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 )
@@ -18,6 +20,12 @@ type MockRepository struct {
 	MockErrorWarehouseFK      error
 	MockErrorProductBatchFK   error
 	MockErrorAlreadyExists    error
+	MockErrorUpdate           error
+	LastFilter                domain.InboundOrderFilter
+	MockErrorReceive          error
+	LastReceipt               domain.InboundReceipt
+	DataMockDailyInbound      []domain.EmployeeDailyInbound
+	LastProductivityFilter    domain.ProductivityFilter
 }
 
 func (mockRepository *MockRepository) GetAllEmployeesInboundOrders(ctx context.Context) ([]domain.EmployeeWithInboundOrders, error) {
@@ -69,3 +77,95 @@ func (mockRepository *MockRepository) Save(ctx context.Context, newInboundOrder
 	mockRepository.DataMockInboundOrders = append(mockRepository.DataMockInboundOrders, newInboundOrder)
 	return newInboundOrder.ID, nil
 }
+
+func (mockRepository *MockRepository) GetAll(ctx context.Context, filter domain.InboundOrderFilter) ([]domain.InboundOrder, error) {
+	if mockRepository.MockErrorGetAll != nil {
+		return nil, mockRepository.MockErrorGetAll
+	}
+
+	mockRepository.LastFilter = filter
+	return mockRepository.DataMockInboundOrders, nil
+}
+
+func (mockRepository *MockRepository) Get(ctx context.Context, id int) (domain.InboundOrder, error) {
+	if mockRepository.MockErrorGet != nil {
+		return domain.InboundOrder{}, mockRepository.MockErrorGet
+	}
+
+	for _, inboundOrder := range mockRepository.DataMockInboundOrders {
+		if inboundOrder.ID == id {
+			return inboundOrder, nil
+		}
+	}
+
+	return domain.InboundOrder{}, ErrInboundOrderNotFound
+}
+
+func (mockRepository *MockRepository) Update(ctx context.Context, inboundOrder domain.InboundOrder) error {
+	if mockRepository.MockErrorUpdate != nil {
+		return mockRepository.MockErrorUpdate
+	}
+
+	for i := range mockRepository.DataMockInboundOrders {
+		if mockRepository.DataMockInboundOrders[i].ID == inboundOrder.ID {
+			mockRepository.DataMockInboundOrders[i] = inboundOrder
+		}
+	}
+
+	return nil
+}
+
+func (mockRepository *MockRepository) Cancel(ctx context.Context, id int, at time.Time) error {
+	if mockRepository.MockErrorUpdate != nil {
+		return mockRepository.MockErrorUpdate
+	}
+
+	for i := range mockRepository.DataMockInboundOrders {
+		if mockRepository.DataMockInboundOrders[i].ID == id {
+			mockRepository.DataMockInboundOrders[i].CancelledAt = &at
+		}
+	}
+
+	return nil
+}
+
+func (mockRepository *MockRepository) Receive(ctx context.Context, receipt domain.InboundReceipt) (domain.InboundReceipt, error) {
+	if mockRepository.MockErrorReceive != nil {
+		return domain.InboundReceipt{}, mockRepository.MockErrorReceive
+	}
+
+	receipt.ID = mockRepository.ExpectedID
+	receipt.ProductBatch.ID = mockRepository.ExpectedID
+	receipt.ProductBatch.AvailableQuantity = receipt.ProductBatch.CurrentQuantity
+	receipt.ProductBatchID = mockRepository.ExpectedID
+	mockRepository.LastReceipt = receipt
+	mockRepository.DataMockInboundOrders = append(mockRepository.DataMockInboundOrders, receipt.InboundOrder)
+	return receipt, nil
+}
+
+func (mockRepository *MockRepository) GetEmployeesDailyInbound(ctx context.Context, filter domain.ProductivityFilter) ([]domain.EmployeeDailyInbound, error) {
+	if mockRepository.MockErrorGetAll != nil {
+		return nil, mockRepository.MockErrorGetAll
+	}
+
+	mockRepository.LastProductivityFilter = filter
+	return mockRepository.DataMockDailyInbound, nil
+}
+
+type MockBatches struct {
+	ExpectedID int
+	Warnings   []string
+	MockError  error
+	LastBatch  domain.ProductBatch
+}
+
+func (mockBatches *MockBatches) SaveTx(ctx context.Context, tx *sql.Tx, pb domain.ProductBatch) (domain.ProductBatch, error) {
+	if mockBatches.MockError != nil {
+		return domain.ProductBatch{}, mockBatches.MockError
+	}
+
+	mockBatches.LastBatch = pb
+	pb.ID = mockBatches.ExpectedID
+	pb.Warnings = append(pb.Warnings, mockBatches.Warnings...)
+	return pb, nil
+}
@@ -2,6 +2,7 @@ package inbound_order
 
 import (
 	"context"
+	"errors"
 	"regexp"
 	"testing"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -82,7 +84,7 @@ func TestRepositoryInboundOrdersSave_Ok(t *testing.T) {
 		ExpectExec().
 		WithArgs(inboundOrderTest.OrderDate, inboundOrderTest.OrderNumber, inboundOrderTest.EmployeeID, inboundOrderTest.ProductBatchID, inboundOrderTest.WarehouseID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
-	repo := NewRepository(db)
+	repo := NewRepository(db, &MockBatches{})
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
 	defer cancel()
 	resultID, errSave := repo.Save(ctx, inboundOrderTest)
@@ -103,7 +105,7 @@ func TestRepositoryInboundOrdersSave_FailEmployeeFK(t *testing.T) {
 		ExpectExec().
 		WithArgs(inboundOrderTestEmployeeFK.OrderDate, inboundOrderTestEmployeeFK.OrderNumber, inboundOrderTestEmployeeFK.EmployeeID, inboundOrderTestEmployeeFK.ProductBatchID, inboundOrderTestEmployeeFK.WarehouseID).
 		WillReturnError(ErrEmployeeNonExistent)
-	repo := NewRepository(db)
+	repo := NewRepository(db, &MockBatches{})
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
 	defer cancel()
 	result, err := repo.Save(ctx, inboundOrderTestEmployeeFK)
@@ -124,7 +126,7 @@ func TestRepositoryInboundOrdersSave_FailWarehouseFK(t *testing.T) {
 		ExpectExec().
 		WithArgs(inboundOrderTestWarehouseFK.OrderDate, inboundOrderTestWarehouseFK.OrderNumber, inboundOrderTestWarehouseFK.EmployeeID, inboundOrderTestWarehouseFK.ProductBatchID, inboundOrderTestWarehouseFK.WarehouseID).
 		WillReturnError(ErrWarehouseNonExistent)
-	repo := NewRepository(db)
+	repo := NewRepository(db, &MockBatches{})
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
 	defer cancel()
 	result, err := repo.Save(ctx, inboundOrderTestWarehouseFK)
@@ -145,7 +147,7 @@ func TestRepositoryInboundOrdersSave_FailProductBatchFK(t *testing.T) {
 		ExpectExec().
 		WithArgs(inboundOrderTestProductBatchFK.OrderDate, inboundOrderTestProductBatchFK.OrderNumber, inboundOrderTestProductBatchFK.EmployeeID, inboundOrderTestProductBatchFK.ProductBatchID, inboundOrderTestProductBatchFK.WarehouseID).
 		WillReturnError(ErrProductBatchNonExistent)
-	repo := NewRepository(db)
+	repo := NewRepository(db, &MockBatches{})
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
 	defer cancel()
 	result, err := repo.Save(ctx, inboundOrderTestProductBatchFK)
@@ -166,7 +168,7 @@ func TestRepositoryInboundOrdersSave_Conflict(t *testing.T) {
 		ExpectExec().
 		WithArgs(emptyInboundOrderTest.OrderDate, emptyInboundOrderTest.OrderNumber, emptyInboundOrderTest.EmployeeID, emptyInboundOrderTest.ProductBatchID, emptyInboundOrderTest.WarehouseID).
 		WillReturnError(ErrEmptyOrderNumber)
-	repo := NewRepository(db)
+	repo := NewRepository(db, &MockBatches{})
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
 	defer cancel()
 	result, err := repo.Save(ctx, emptyInboundOrderTest)
@@ -183,7 +185,7 @@ func TestRepositoryInboundOrdersGetAllEmployees_Ok(t *testing.T) {
 	rows := sqlmock.NewRows(columns)
 	rows.AddRow(employeeWithInboundOrders.ID, employeeWithInboundOrders.CardNumberID, employeeWithInboundOrders.FirstName,
 		employeeWithInboundOrders.LastName, employeeWithInboundOrders.WarehouseID, employeeWithInboundOrders.InboundOrders)
-	repo := NewRepository(db)
+	repo := NewRepository(db, &MockBatches{})
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	mock.ExpectQuery(regexp.QuoteMeta(GetAllEmployeesInboundOrders)).WillReturnRows(rows)
@@ -201,7 +203,7 @@ func TestRepositoryInboundOrdersGetEmployee_Ok(t *testing.T) {
 	rows := sqlmock.NewRows(columns)
 	rows.AddRow(employeeWithInboundOrders.ID, employeeWithInboundOrders.CardNumberID, employeeWithInboundOrders.FirstName,
 		employeeWithInboundOrders.LastName, employeeWithInboundOrders.WarehouseID, employeeWithInboundOrders.InboundOrders)
-	repo := NewRepository(db)
+	repo := NewRepository(db, &MockBatches{})
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	mock.ExpectQuery(regexp.QuoteMeta(GetEmployeeInboundOrders)).WithArgs(employeeWithInboundOrders.ID).WillReturnRows(rows)
@@ -215,7 +217,7 @@ func TestRepositoryInboundOrdersGetEmployee_NotFound(t *testing.T) {
 	db, mock, errSql := sqlmock.New()
 	assert.NoError(t, errSql)
 	defer db.Close()
-	repo := NewRepository(db)
+	repo := NewRepository(db, &MockBatches{})
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	mock.ExpectQuery(regexp.QuoteMeta(GetEmployeeInboundOrders)).WithArgs(employeeWithInboundOrders.ID).WillReturnError(ErrEmployeeWithInboundOrdersNotFound)
@@ -224,3 +226,151 @@ func TestRepositoryInboundOrdersGetEmployee_NotFound(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 	assert.Empty(t, result)
 }
+
+var inboundOrderColumns = []string{"id", "order_date", "order_number", "employee_id", "product_batch_id", "warehouse_id", "cancelled_at"}
+
+func TestRepositoryInboundOrdersGetAll_Filter(t *testing.T) {
+	db, mock, errSql := sqlmock.New()
+	assert.NoError(t, errSql)
+	defer db.Close()
+	cancelledAt := time.Date(2022, 1, 5, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows(inboundOrderColumns).
+		AddRow(2, "2022-01-20", "Order#2", 1, 3, 1, nil).
+		AddRow(1, "2022-01-10", "Order#1", 1, 3, 1, cancelledAt)
+	mock.ExpectQuery(regexp.QuoteMeta(GetInboundOrders+" WHERE warehouse_id = ? AND product_batch_id = ? AND order_date >= ? AND order_date <= ? ORDER BY order_date DESC, id DESC;")).
+		WithArgs(1, 3, "2022-01-01", "2022-01-31").
+		WillReturnRows(rows)
+	repo := NewRepository(db, &MockBatches{})
+	result, err := repo.GetAll(context.Background(), domain.InboundOrderFilter{WarehouseID: 1, ProductBatchID: 3, From: "2022-01-01", To: "2022-01-31"})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, []domain.InboundOrder{
+		{ID: 2, OrderDate: "2022-01-20", OrderNumber: "Order#2", EmployeeID: 1, ProductBatchID: 3, WarehouseID: 1},
+		{ID: 1, OrderDate: "2022-01-10", OrderNumber: "Order#1", EmployeeID: 1, ProductBatchID: 3, WarehouseID: 1, CancelledAt: &cancelledAt},
+	}, result)
+}
+
+func TestRepositoryInboundOrdersGet_NotFound(t *testing.T) {
+	db, mock, errSql := sqlmock.New()
+	assert.NoError(t, errSql)
+	defer db.Close()
+	mock.ExpectQuery(regexp.QuoteMeta(GetInboundOrder)).WithArgs(1).WillReturnRows(sqlmock.NewRows(inboundOrderColumns))
+	repo := NewRepository(db, &MockBatches{})
+	result, err := repo.Get(context.Background(), 1)
+	assert.ErrorIs(t, err, ErrInboundOrderNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Empty(t, result)
+}
+
+func TestRepositoryInboundOrdersUpdate_FailWarehouseFK(t *testing.T) {
+	db, mock, errSql := sqlmock.New()
+	assert.NoError(t, errSql)
+	defer db.Close()
+	mock.ExpectExec(regexp.QuoteMeta(UpdateInboundOrder)).
+		WithArgs("2022-01-10", "Order#1", 1, 3, 10, 1).
+		WillReturnError(&mysql.MySQLError{Number: ForeignKeyConstraint, Message: "a foreign key constraint fails (`inbound_orders`, FOREIGN KEY (`warehouse_id`) REFERENCES `warehouses` (`id`))"})
+	repo := NewRepository(db, &MockBatches{})
+	err := repo.Update(context.Background(), domain.InboundOrder{ID: 1, OrderDate: "2022-01-10", OrderNumber: "Order#1", EmployeeID: 1, ProductBatchID: 3, WarehouseID: 10})
+	assert.ErrorIs(t, err, ErrWarehouseNonExistent)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepositoryInboundOrdersCancel(t *testing.T) {
+	db, mock, errSql := sqlmock.New()
+	assert.NoError(t, errSql)
+	defer db.Close()
+	at := time.Date(2022, 1, 5, 0, 0, 0, 0, time.UTC)
+	mock.ExpectExec(regexp.QuoteMeta(CancelInboundOrder)).WithArgs(at, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(CancelInboundOrder)).WithArgs(at, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	repo := NewRepository(db, &MockBatches{})
+	assert.NoError(t, repo.Cancel(context.Background(), 1, at))
+	assert.ErrorIs(t, repo.Cancel(context.Background(), 1, at), ErrInboundOrderCancelled)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+var receiptTest = domain.InboundReceipt{
+	InboundOrder: domain.InboundOrder{OrderDate: "2022-01-10", OrderNumber: "Order#1", EmployeeID: 1, WarehouseID: 2},
+	ProductBatch: domain.ProductBatch{BatchNumber: 7, CurrentQuantity: 5, InitialQuantity: 5, CurrentTemperature: -5, MinimumTemperature: -10,
+		DueDate: "2022-03-01", ManufacturingDate: "2022-01-01", ManufacturingHour: 8, ProductID: 3, SectionID: 4},
+}
+
+func TestRepositoryInboundOrdersReceive_Ok(t *testing.T) {
+	db, mock, errSql := sqlmock.New()
+	assert.NoError(t, errSql)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(GetSectionWarehouse)).WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"warehouse_id"}).AddRow(2))
+	mock.ExpectExec(regexp.QuoteMeta(SaveInboundOrder)).WithArgs("2022-01-10", "Order#1", 1, 30, 2).WillReturnResult(sqlmock.NewResult(40, 1))
+	mock.ExpectCommit()
+	batches := MockBatches{ExpectedID: 30, Warnings: []string{"warm section"}}
+	repo := NewRepository(db, &batches)
+	result, err := repo.Receive(context.Background(), receiptTest)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, receiptTest.ProductBatch, batches.LastBatch)
+	assert.Equal(t, 40, result.ID)
+	assert.Equal(t, 30, result.ProductBatchID)
+	assert.Equal(t, 30, result.ProductBatch.ID)
+	assert.Equal(t, 5, result.ProductBatch.AvailableQuantity)
+	assert.Equal(t, []string{"warm section"}, result.ProductBatch.Warnings)
+}
+
+func TestRepositoryInboundOrdersReceive_Fail(t *testing.T) {
+	errTemperature := errors.New("the temperature of the section is not compatible with the product")
+	cases := []struct {
+		name     string
+		expect   func(mock sqlmock.Sqlmock)
+		batchErr error
+		err      error
+	}{
+		{"section not found", func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(regexp.QuoteMeta(GetSectionWarehouse)).WillReturnRows(sqlmock.NewRows([]string{"warehouse_id"}))
+		}, nil, ErrSectionNonExistent},
+		{"section of another warehouse", func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(regexp.QuoteMeta(GetSectionWarehouse)).WillReturnRows(sqlmock.NewRows([]string{"warehouse_id"}).AddRow(3))
+		}, nil, ErrSectionNotInWarehouse},
+		{"batch rejected", func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(regexp.QuoteMeta(GetSectionWarehouse)).WillReturnRows(sqlmock.NewRows([]string{"warehouse_id"}).AddRow(2))
+		}, errTemperature, errTemperature},
+		{"repeated order number", func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(regexp.QuoteMeta(GetSectionWarehouse)).WillReturnRows(sqlmock.NewRows([]string{"warehouse_id"}).AddRow(2))
+			mock.ExpectExec(regexp.QuoteMeta(SaveInboundOrder)).WillReturnError(&mysql.MySQLError{Number: DuplicateKeyConstraint, Message: "Duplicate entry 'Order#1' for key 'inbound_orders.order_number'"})
+		}, nil, ErrInboundOrderAlreadyExists},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db, mock, errSql := sqlmock.New()
+			assert.NoError(t, errSql)
+			defer db.Close()
+			mock.ExpectBegin()
+			c.expect(mock)
+			mock.ExpectRollback()
+			repo := NewRepository(db, &MockBatches{ExpectedID: 30, MockError: c.batchErr})
+			result, err := repo.Receive(context.Background(), receiptTest)
+			assert.ErrorIs(t, err, c.err)
+			assert.Empty(t, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepositoryInboundOrdersGetEmployeesDailyInbound(t *testing.T) {
+	db, mock, errSql := sqlmock.New()
+	assert.NoError(t, errSql)
+	defer db.Close()
+	rows := sqlmock.NewRows([]string{"id", "card_number_id", "first_name", "last_name", "warehouse_id", "date", "inbound_orders_count", "received_units"}).
+		AddRow(1, "asd321", "Ignacio", "Naya", 1, "2022-01-10", 2, 30).
+		AddRow(1, "asd321", "Ignacio", "Naya", 1, "2022-01-11", 1, 5)
+	mock.ExpectQuery(regexp.QuoteMeta(GetEmployeesDailyInbound+" AND ib.order_date >= ? AND ib.order_date <= ? AND ib.warehouse_id = ? GROUP BY e.id, ib.order_date ORDER BY e.id, ib.order_date;")).
+		WithArgs("2022-01-01", "2022-01-31", 1).
+		WillReturnRows(rows)
+	repo := NewRepository(db, &MockBatches{})
+	result, err := repo.GetEmployeesDailyInbound(context.Background(), domain.ProductivityFilter{From: "2022-01-01", To: "2022-01-31", WarehouseID: 1})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, []domain.EmployeeDailyInbound{
+		{Employee: employee, Day: domain.EmployeeDay{Date: "2022-01-10", InboundOrders: 2, ReceivedUnits: 30}},
+		{Employee: employee, Day: domain.EmployeeDay{Date: "2022-01-11", InboundOrders: 1, ReceivedUnits: 5}},
+	}, result)
+}
@@ -3,6 +3,8 @@ package inbound_order
 import (
 	"context"
 	"errors"
+	"sort"
+	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
@@ -17,8 +19,23 @@ var (
 	ErrInboundOrderNotSaved = errors.New("cannot save inbound order")
 	// ErrEmptyOrderNumber is returned when the request does not have an orderNumber
 	ErrEmptyOrderNumber = errors.New("orderNumber field cannot be empty")
+	// ErrInboundOrderNotFound is returned when the requested inbound order does not exist
+	ErrInboundOrderNotFound = errors.New("inbound order not found")
+	// ErrInboundOrderCancelled is returned when a cancelled inbound order is modified or cancelled again
+	ErrInboundOrderCancelled = errors.New("inbound order is cancelled")
+	// ErrInvalidOrderDate is returned when the order date is not a valid date
+	ErrInvalidOrderDate = errors.New("order_date must be a valid date in yyyy-mm-dd or dd/mm/yyyy format")
+	// ErrInvalidDateRange is returned when the dates of the filter are not valid or from is after to
+	ErrInvalidDateRange = errors.New("from and to must be valid dates in yyyy-mm-dd format and from cannot be after to")
+	// ErrInvalidReceivedBatch is returned when the received quantity is not positive or the dates of the batch are not valid
+	ErrInvalidReceivedBatch = errors.New("the received quantity must be greater than zero and the manufacturing and due dates valid yyyy-mm-dd dates, in that order")
+	// ErrInvalidTop is returned when the size of a ranking is not positive
+	ErrInvalidTop = errors.New("top must be greater than zero")
 )
 
+// orderDateLayouts are the accepted formats of an order date, the first one is the format it is stored with
+var orderDateLayouts = []string{domain.ISO8601, "02/01/2006", "02/01/06"}
+
 type Service interface {
 	// GetAllEmployeesInboundOrders returns all the employees and their inbound orders that exist and are inside the repository
 	GetAllEmployeesInboundOrders(ctx context.Context) ([]domain.EmployeeWithInboundOrders, error)
@@ -26,15 +43,31 @@ type Service interface {
 	GetEmployeeInboundOrders(ctx context.Context, id int) (domain.EmployeeWithInboundOrders, error)
 	// Save creates a new inbound order with the specified data inside the repository
 	Save(ctx context.Context, inboundOrder domain.InboundOrder) (domain.InboundOrder, error)
+	// GetAll returns the inbound orders that match the filter, the most recent first
+	GetAll(ctx context.Context, filter domain.InboundOrderFilter) ([]domain.InboundOrder, error)
+	// Get returns the inbound order with the given id
+	Get(ctx context.Context, id int) (domain.InboundOrder, error)
+	// Update changes the non zero fields of the inbound order, cancelled orders cannot be changed
+	Update(ctx context.Context, inboundOrder domain.InboundOrder) (domain.InboundOrder, error)
+	// Cancel marks the inbound order as cancelled, cancelled orders are left out of the employee reports
+	Cancel(ctx context.Context, id int) (domain.InboundOrder, error)
+	// Receive creates the received product batch and its inbound order at once, the batch is stored in a section of the warehouse of the order
+	Receive(ctx context.Context, receipt domain.InboundReceipt) (domain.InboundReceipt, error)
+	// GetEmployeesProductivity returns the inbound orders and received units of every employee that worked in the period, day by day
+	GetEmployeesProductivity(ctx context.Context, filter domain.ProductivityFilter) ([]domain.EmployeeProductivity, error)
+	// GetProductivityRanking returns the top employees of the period by inbound orders and by received units
+	GetProductivityRanking(ctx context.Context, filter domain.ProductivityFilter, top int) (domain.ProductivityRanking, error)
 }
 
 type service struct {
 	repository Repository
+	now        func() time.Time
 }
 
 func NewService(repo Repository) Service {
 	return &service{
 		repository: repo,
+		now:        time.Now,
 	}
 }
 
@@ -66,6 +99,14 @@ func (service *service) GetEmployeeInboundOrders(ctx context.Context, id int) (d
 }
 
 func (service *service) Save(ctx context.Context, inboundOrder domain.InboundOrder) (domain.InboundOrder, error) {
+	orderDate, err := normalizeOrderDate(inboundOrder.OrderDate)
+
+	if err != nil {
+		logging.Log(err)
+		return domain.InboundOrder{}, err
+	}
+
+	inboundOrder.OrderDate = orderDate
 	id, err := service.repository.Save(ctx, inboundOrder)
 
 	inboundOrder.ID = id
@@ -90,3 +131,242 @@ func (service *service) Save(ctx context.Context, inboundOrder domain.InboundOrd
 
 	return inboundOrder, nil
 }
+
+func (service *service) GetAll(ctx context.Context, filter domain.InboundOrderFilter) ([]domain.InboundOrder, error) {
+	if err := validateDateRange(filter.From, filter.To); err != nil {
+		logging.Log(err)
+		return nil, err
+	}
+
+	inboundOrders, err := service.repository.GetAll(ctx, filter)
+
+	if err != nil {
+		logging.Log(err)
+		return nil, err
+	}
+
+	return inboundOrders, nil
+}
+
+func (service *service) Get(ctx context.Context, id int) (domain.InboundOrder, error) {
+	return service.repository.Get(ctx, id)
+}
+
+func (service *service) Update(ctx context.Context, inboundOrder domain.InboundOrder) (domain.InboundOrder, error) {
+	stored, err := service.repository.Get(ctx, inboundOrder.ID)
+
+	if err != nil {
+		return domain.InboundOrder{}, err
+	}
+
+	if stored.CancelledAt != nil {
+		logging.Log(ErrInboundOrderCancelled)
+		return domain.InboundOrder{}, ErrInboundOrderCancelled
+	}
+
+	if inboundOrder.OrderDate != "" {
+		if stored.OrderDate, err = normalizeOrderDate(inboundOrder.OrderDate); err != nil {
+			logging.Log(err)
+			return domain.InboundOrder{}, err
+		}
+	}
+	if inboundOrder.OrderNumber != "" {
+		stored.OrderNumber = inboundOrder.OrderNumber
+	}
+	if inboundOrder.EmployeeID != 0 {
+		stored.EmployeeID = inboundOrder.EmployeeID
+	}
+	if inboundOrder.ProductBatchID != 0 {
+		stored.ProductBatchID = inboundOrder.ProductBatchID
+	}
+	if inboundOrder.WarehouseID != 0 {
+		stored.WarehouseID = inboundOrder.WarehouseID
+	}
+
+	if err := service.repository.Update(ctx, stored); err != nil {
+		return domain.InboundOrder{}, err
+	}
+
+	return stored, nil
+}
+
+func (service *service) Cancel(ctx context.Context, id int) (domain.InboundOrder, error) {
+	inboundOrder, err := service.repository.Get(ctx, id)
+
+	if err != nil {
+		return domain.InboundOrder{}, err
+	}
+
+	if inboundOrder.CancelledAt != nil {
+		logging.Log(ErrInboundOrderCancelled)
+		return domain.InboundOrder{}, ErrInboundOrderCancelled
+	}
+
+	cancelledAt := service.now().UTC().Truncate(time.Second)
+
+	if err := service.repository.Cancel(ctx, id, cancelledAt); err != nil {
+		return domain.InboundOrder{}, err
+	}
+
+	inboundOrder.CancelledAt = &cancelledAt
+	return inboundOrder, nil
+}
+
+func (service *service) Receive(ctx context.Context, receipt domain.InboundReceipt) (domain.InboundReceipt, error) {
+	orderDate, err := normalizeOrderDate(receipt.OrderDate)
+
+	if err != nil {
+		logging.Log(err)
+		return domain.InboundReceipt{}, err
+	}
+
+	receipt.OrderDate = orderDate
+
+	if receipt.OrderNumber == "" {
+		logging.Log(ErrEmptyOrderNumber)
+		return domain.InboundReceipt{}, ErrEmptyOrderNumber
+	}
+
+	batch := receipt.ProductBatch
+
+	if err := validateReceivedBatch(batch); err != nil {
+		logging.Log(err)
+		return domain.InboundReceipt{}, err
+	}
+
+	batch.InitialQuantity = batch.CurrentQuantity
+	receipt.ProductBatch = batch
+
+	received, err := service.repository.Receive(ctx, receipt)
+
+	if err != nil {
+		return domain.InboundReceipt{}, err
+	}
+
+	return received, nil
+}
+
+func (service *service) GetEmployeesProductivity(ctx context.Context, filter domain.ProductivityFilter) ([]domain.EmployeeProductivity, error) {
+	if err := validateDateRange(filter.From, filter.To); err != nil {
+		logging.Log(err)
+		return nil, err
+	}
+
+	days, err := service.repository.GetEmployeesDailyInbound(ctx, filter)
+
+	if err != nil {
+		logging.Log(err)
+		return nil, err
+	}
+
+	var employees []domain.EmployeeProductivity
+
+	for _, day := range days {
+		if len(employees) == 0 || employees[len(employees)-1].ID != day.ID {
+			employees = append(employees, domain.EmployeeProductivity{Employee: day.Employee})
+		}
+		employee := &employees[len(employees)-1]
+		employee.InboundOrders += day.Day.InboundOrders
+		employee.ReceivedUnits += day.Day.ReceivedUnits
+		employee.Days = append(employee.Days, day.Day)
+	}
+
+	return employees, nil
+}
+
+func (service *service) GetProductivityRanking(ctx context.Context, filter domain.ProductivityFilter, top int) (domain.ProductivityRanking, error) {
+	if top <= 0 {
+		logging.Log(ErrInvalidTop)
+		return domain.ProductivityRanking{}, ErrInvalidTop
+	}
+
+	employees, err := service.GetEmployeesProductivity(ctx, filter)
+
+	if err != nil {
+		return domain.ProductivityRanking{}, err
+	}
+
+	for i := range employees {
+		employees[i].Days = nil
+	}
+
+	byOrders := func(a, b domain.EmployeeProductivity) bool {
+		return a.InboundOrders > b.InboundOrders || a.InboundOrders == b.InboundOrders && a.ReceivedUnits > b.ReceivedUnits
+	}
+	byUnits := func(a, b domain.EmployeeProductivity) bool {
+		return a.ReceivedUnits > b.ReceivedUnits || a.ReceivedUnits == b.ReceivedUnits && a.InboundOrders > b.InboundOrders
+	}
+
+	return domain.ProductivityRanking{
+		ByInboundOrders: rank(employees, byOrders, top),
+		ByReceivedUnits: rank(employees, byUnits, top),
+	}, nil
+}
+
+// rank returns the first top employees sorted with less, ties are broken by the lowest employee id
+func rank(employees []domain.EmployeeProductivity, less func(a, b domain.EmployeeProductivity) bool, top int) []domain.EmployeeProductivity {
+	ranked := append([]domain.EmployeeProductivity{}, employees...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return less(ranked[i], ranked[j])
+	})
+
+	if len(ranked) > top {
+		ranked = ranked[:top]
+	}
+
+	return ranked
+}
+
+// validateReceivedBatch returns ErrInvalidReceivedBatch if the batch has no quantity,
+// or its manufacturing and due dates are not valid dates in order
+func validateReceivedBatch(batch domain.ProductBatch) error {
+	if batch.CurrentQuantity <= 0 {
+		return ErrInvalidReceivedBatch
+	}
+
+	manufacturingDate, err := time.Parse(domain.ISO8601, batch.ManufacturingDate)
+
+	if err != nil {
+		return ErrInvalidReceivedBatch
+	}
+
+	dueDate, err := time.Parse(domain.ISO8601, batch.DueDate)
+
+	if err != nil || dueDate.Before(manufacturingDate) {
+		return ErrInvalidReceivedBatch
+	}
+
+	return nil
+}
+
+// normalizeOrderDate returns the order date in yyyy-mm-dd format, or ErrInvalidOrderDate if it is not a date in any accepted format
+func normalizeOrderDate(orderDate string) (string, error) {
+	for _, layout := range orderDateLayouts {
+		if date, err := time.Parse(layout, orderDate); err == nil {
+			return date.Format(domain.ISO8601), nil
+		}
+	}
+	return "", ErrInvalidOrderDate
+}
+
+// validateDateRange returns ErrInvalidDateRange if any given date is not in yyyy-mm-dd format or from is after to
+func validateDateRange(from string, to string) error {
+	var fromDate, toDate time.Time
+	var err error
+
+	if from != "" {
+		if fromDate, err = time.Parse(domain.ISO8601, from); err != nil {
+			return ErrInvalidDateRange
+		}
+	}
+	if to != "" {
+		if toDate, err = time.Parse(domain.ISO8601, to); err != nil {
+			return ErrInvalidDateRange
+		}
+	}
+	if from != "" && to != "" && fromDate.After(toDate) {
+		return ErrInvalidDateRange
+	}
+
+	return nil
+}
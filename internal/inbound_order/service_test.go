@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
@@ -91,7 +92,7 @@ func TestGetEmployeeInboundOrders_NotFound(t *testing.T) {
 
 func TestSaveInboundOrder_Ok(t *testing.T) {
 	newInboundOrder := domain.InboundOrder{OrderDate: "01/01/2022", OrderNumber: "Test#1", EmployeeID: 1, ProductBatchID: 1, WarehouseID: 1}
-	expectedInboundOrder := domain.InboundOrder{ID: 1, OrderDate: "01/01/2022", OrderNumber: "Test#1", EmployeeID: 1, ProductBatchID: 1, WarehouseID: 1}
+	expectedInboundOrder := domain.InboundOrder{ID: 1, OrderDate: "2022-01-01", OrderNumber: "Test#1", EmployeeID: 1, ProductBatchID: 1, WarehouseID: 1}
 	expectedResult := []domain.InboundOrder{
 		expectedInboundOrder,
 	}
@@ -233,3 +234,200 @@ func TestSaveInboundOrder_FailProductBatchFK(t *testing.T) {
 	assert.EqualError(t, err, expectedErr.Error())
 	assert.Empty(t, result)
 }
+
+func TestSaveInboundOrder_NormalizesOrderDate(t *testing.T) {
+	mockRepository := MockRepository{ExpectedID: 1}
+	service := NewService(&mockRepository)
+
+	for orderDate, expected := range map[string]string{"2022-01-31": "2022-01-31", "31/01/2022": "2022-01-31", "31/01/22": "2022-01-31"} {
+		result, err := service.Save(ctx, domain.InboundOrder{OrderDate: orderDate, OrderNumber: "Test#1", EmployeeID: 1, ProductBatchID: 1, WarehouseID: 1})
+
+		assert.Nil(t, err)
+		assert.Equal(t, expected, result.OrderDate)
+	}
+}
+
+func TestSaveInboundOrder_InvalidOrderDate(t *testing.T) {
+	mockRepository := MockRepository{ExpectedID: 1}
+	service := NewService(&mockRepository)
+
+	for _, orderDate := range []string{"", "tomorrow", "01/31/2022", "2022-02-30"} {
+		result, err := service.Save(ctx, domain.InboundOrder{OrderDate: orderDate, OrderNumber: "Test#1", EmployeeID: 1, ProductBatchID: 1, WarehouseID: 1})
+
+		assert.ErrorIs(t, err, ErrInvalidOrderDate)
+		assert.Empty(t, result)
+	}
+	assert.Empty(t, mockRepository.DataMockInboundOrders)
+}
+
+func TestGetAllInboundOrders_Ok(t *testing.T) {
+	filter := domain.InboundOrderFilter{WarehouseID: 1, From: "2022-01-01", To: "2022-01-31"}
+	mockRepository := MockRepository{DataMockInboundOrders: []domain.InboundOrder{{ID: 1, OrderDate: "2022-01-10"}}}
+	service := NewService(&mockRepository)
+
+	result, err := service.GetAll(ctx, filter)
+
+	assert.Nil(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, filter, mockRepository.LastFilter)
+}
+
+func TestGetAllInboundOrders_InvalidDateRange(t *testing.T) {
+	service := NewService(&MockRepository{})
+
+	for _, filter := range []domain.InboundOrderFilter{{From: "01/01/2022"}, {To: "2022-13-01"}, {From: "2022-02-01", To: "2022-01-01"}} {
+		result, err := service.GetAll(ctx, filter)
+
+		assert.ErrorIs(t, err, ErrInvalidDateRange)
+		assert.Nil(t, result)
+	}
+}
+
+func TestUpdateInboundOrder_Ok(t *testing.T) {
+	mockRepository := MockRepository{DataMockInboundOrders: []domain.InboundOrder{{ID: 1, OrderDate: "2022-01-01", OrderNumber: "Test#1", EmployeeID: 1, ProductBatchID: 1, WarehouseID: 1}}}
+	service := NewService(&mockRepository)
+
+	result, err := service.Update(ctx, domain.InboundOrder{ID: 1, OrderDate: "02/01/2022", WarehouseID: 2})
+
+	expected := domain.InboundOrder{ID: 1, OrderDate: "2022-01-02", OrderNumber: "Test#1", EmployeeID: 1, ProductBatchID: 1, WarehouseID: 2}
+	assert.Nil(t, err)
+	assert.Equal(t, expected, result)
+	assert.Equal(t, expected, mockRepository.DataMockInboundOrders[0])
+}
+
+func TestUpdateInboundOrder_Fail(t *testing.T) {
+	cancelledAt := time.Date(2022, 1, 5, 0, 0, 0, 0, time.UTC)
+	mockRepository := MockRepository{DataMockInboundOrders: []domain.InboundOrder{
+		{ID: 1, OrderDate: "2022-01-01", OrderNumber: "Test#1"},
+		{ID: 2, OrderDate: "2022-01-01", OrderNumber: "Test#2", CancelledAt: &cancelledAt},
+	}}
+	service := NewService(&mockRepository)
+
+	_, err := service.Update(ctx, domain.InboundOrder{ID: 3, OrderNumber: "Test#3"})
+	assert.ErrorIs(t, err, ErrInboundOrderNotFound)
+
+	_, err = service.Update(ctx, domain.InboundOrder{ID: 2, OrderNumber: "Test#3"})
+	assert.ErrorIs(t, err, ErrInboundOrderCancelled)
+
+	_, err = service.Update(ctx, domain.InboundOrder{ID: 1, OrderDate: "2022/01/01"})
+	assert.ErrorIs(t, err, ErrInvalidOrderDate)
+
+	mockRepository.MockErrorUpdate = ErrEmployeeNonExistent
+	_, err = service.Update(ctx, domain.InboundOrder{ID: 1, EmployeeID: 10})
+	assert.ErrorIs(t, err, ErrEmployeeNonExistent)
+}
+
+func TestCancelInboundOrder_Ok(t *testing.T) {
+	now := time.Date(2022, 1, 5, 10, 30, 0, 0, time.UTC)
+	mockRepository := MockRepository{DataMockInboundOrders: []domain.InboundOrder{{ID: 1, OrderDate: "2022-01-01", OrderNumber: "Test#1"}}}
+	service := &service{repository: &mockRepository, now: func() time.Time { return now }}
+
+	result, err := service.Cancel(ctx, 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, now, *result.CancelledAt)
+	assert.Equal(t, now, *mockRepository.DataMockInboundOrders[0].CancelledAt)
+
+	_, err = service.Cancel(ctx, 1)
+	assert.ErrorIs(t, err, ErrInboundOrderCancelled)
+
+	_, err = service.Cancel(ctx, 2)
+	assert.ErrorIs(t, err, ErrInboundOrderNotFound)
+}
+
+func newReceiptTest() domain.InboundReceipt {
+	return domain.InboundReceipt{
+		InboundOrder: domain.InboundOrder{OrderDate: "10/01/2022", OrderNumber: "Order#1", EmployeeID: 1, WarehouseID: 2},
+		ProductBatch: domain.ProductBatch{BatchNumber: 7, CurrentQuantity: 5, CurrentTemperature: -5, MinimumTemperature: -10,
+			DueDate: "2022-03-01", ManufacturingDate: "2022-01-01", ManufacturingHour: 8, ProductID: 3, SectionID: 4},
+	}
+}
+
+func TestReceiveInboundOrder_Ok(t *testing.T) {
+	mockRepository := MockRepository{ExpectedID: 9}
+	service := NewService(&mockRepository)
+
+	result, err := service.Receive(ctx, newReceiptTest())
+
+	assert.Nil(t, err)
+	assert.Equal(t, "2022-01-10", mockRepository.LastReceipt.OrderDate)
+	assert.Equal(t, 5, mockRepository.LastReceipt.ProductBatch.InitialQuantity)
+	assert.Equal(t, 9, result.ProductBatchID)
+	assert.Equal(t, 5, result.ProductBatch.AvailableQuantity)
+	assert.Empty(t, result.ProductBatch.Warnings)
+}
+
+func TestReceiveInboundOrder_Invalid(t *testing.T) {
+	mockRepository := MockRepository{ExpectedID: 9}
+	service := NewService(&mockRepository)
+	cases := map[string]func(r *domain.InboundReceipt){
+		"order date":         func(r *domain.InboundReceipt) { r.OrderDate = "yesterday" },
+		"quantity":           func(r *domain.InboundReceipt) { r.ProductBatch.CurrentQuantity = 0 },
+		"due date":           func(r *domain.InboundReceipt) { r.ProductBatch.DueDate = "01/03/2022" },
+		"due before made":    func(r *domain.InboundReceipt) { r.ProductBatch.DueDate = "2021-12-31" },
+		"manufacturing date": func(r *domain.InboundReceipt) { r.ProductBatch.ManufacturingDate = "" },
+	}
+
+	for name, change := range cases {
+		receipt := newReceiptTest()
+		change(&receipt)
+		_, err := service.Receive(ctx, receipt)
+		assert.Error(t, err, name)
+	}
+	assert.Empty(t, mockRepository.LastReceipt)
+
+	mockRepository.MockErrorReceive = ErrSectionNonExistent
+	_, err := service.Receive(ctx, newReceiptTest())
+	assert.ErrorIs(t, err, ErrSectionNonExistent)
+}
+
+var (
+	otherEmployee = domain.Employee{ID: 2, CardNumberID: "654321", FirstName: "Jane", LastName: "Roe", WarehouseID: 1}
+	dailyInbound  = []domain.EmployeeDailyInbound{
+		{Employee: testEmployee, Day: domain.EmployeeDay{Date: "2022-01-10", InboundOrders: 2, ReceivedUnits: 10}},
+		{Employee: testEmployee, Day: domain.EmployeeDay{Date: "2022-01-11", InboundOrders: 1, ReceivedUnits: 5}},
+		{Employee: otherEmployee, Day: domain.EmployeeDay{Date: "2022-01-10", InboundOrders: 1, ReceivedUnits: 40}},
+	}
+)
+
+func TestGetEmployeesProductivity_Ok(t *testing.T) {
+	filter := domain.ProductivityFilter{From: "2022-01-01", To: "2022-01-31", WarehouseID: 1}
+	mockRepository := MockRepository{DataMockDailyInbound: dailyInbound}
+	service := NewService(&mockRepository)
+
+	result, err := service.GetEmployeesProductivity(ctx, filter)
+
+	assert.Nil(t, err)
+	assert.Equal(t, filter, mockRepository.LastProductivityFilter)
+	assert.Equal(t, []domain.EmployeeProductivity{
+		{Employee: testEmployee, InboundOrders: 3, ReceivedUnits: 15, Days: []domain.EmployeeDay{dailyInbound[0].Day, dailyInbound[1].Day}},
+		{Employee: otherEmployee, InboundOrders: 1, ReceivedUnits: 40, Days: []domain.EmployeeDay{dailyInbound[2].Day}},
+	}, result)
+}
+
+func TestGetEmployeesProductivity_InvalidDateRange(t *testing.T) {
+	service := NewService(&MockRepository{DataMockDailyInbound: dailyInbound})
+
+	result, err := service.GetEmployeesProductivity(ctx, domain.ProductivityFilter{From: "2022-02-01", To: "2022-01-01"})
+
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+	assert.Nil(t, result)
+}
+
+func TestGetProductivityRanking_Ok(t *testing.T) {
+	service := NewService(&MockRepository{DataMockDailyInbound: dailyInbound})
+
+	result, err := service.GetProductivityRanking(ctx, domain.ProductivityFilter{}, 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.EmployeeProductivity{{Employee: testEmployee, InboundOrders: 3, ReceivedUnits: 15}}, result.ByInboundOrders)
+	assert.Equal(t, []domain.EmployeeProductivity{{Employee: otherEmployee, InboundOrders: 1, ReceivedUnits: 40}}, result.ByReceivedUnits)
+}
+
+func TestGetProductivityRanking_InvalidTop(t *testing.T) {
+	service := NewService(&MockRepository{DataMockDailyInbound: dailyInbound})
+
+	_, err := service.GetProductivityRanking(ctx, domain.ProductivityFilter{}, 0)
+
+	assert.ErrorIs(t, err, ErrInvalidTop)
+}
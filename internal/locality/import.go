@@ -0,0 +1,192 @@
+package locality
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+)
+
+// Import formats
+const (
+	ImportCSV    = "csv"
+	ImportNDJSON = "ndjson"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("the import must be a csv or ndjson file")
+	ErrInvalidHeader     = errors.New("the csv header must name the columns id, locality_name and optionally province_id, province_name, country_id, country_name, latitude and longitude")
+	ErrUnreadableFile    = errors.New("the import file can not be read")
+)
+
+// importColumns are the columns an import csv can have, named like the json fields of domain.Locality
+var importColumns = []string{"id", "locality_name", "province_id", "province_name", "country_id", "country_name", "latitude", "longitude"}
+
+// maxNDJSONLine is the longest line accepted in an ndjson import
+const maxNDJSONLine = 64 * 1024
+
+// RowError is returned by an ImportReader for a line that can not be read, the next lines can still be read
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+// ImportReader returns the rows of an import file one at a time and io.EOF after the last one.
+// Any error other than a RowError means the rest of the file can not be read
+type ImportReader interface {
+	Next() (domain.LocalityImportRow, error)
+}
+
+// NewImportReader returns a reader of the given format, a csv file must start with its header
+func NewImportReader(format string, r io.Reader) (ImportReader, error) {
+	switch format {
+	case ImportCSV:
+		return newCSVReader(r)
+	case ImportNDJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 4096), maxNDJSONLine)
+		return &ndjsonReader{scanner: scanner}, nil
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+type csvReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, ErrInvalidHeader
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !knownColumn(name) {
+			return nil, ErrInvalidHeader
+		}
+		if _, repeated := columns[name]; repeated {
+			return nil, ErrInvalidHeader
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["id"]; !ok {
+		return nil, ErrInvalidHeader
+	}
+	if _, ok := columns["locality_name"]; !ok {
+		return nil, ErrInvalidHeader
+	}
+	reader.FieldsPerRecord = len(header)
+	return &csvReader{reader: reader, columns: columns}, nil
+}
+
+func (r *csvReader) Next() (domain.LocalityImportRow, error) {
+	record, err := r.reader.Read()
+	if err == io.EOF {
+		return domain.LocalityImportRow{}, io.EOF
+	}
+	if err != nil {
+		var parseError *csv.ParseError
+		if errors.As(err, &parseError) {
+			return domain.LocalityImportRow{}, &RowError{Line: parseError.StartLine, Err: parseError.Err}
+		}
+		return domain.LocalityImportRow{}, ErrUnreadableFile
+	}
+
+	line, _ := r.reader.FieldPos(0)
+	value := func(column string) string {
+		if i, ok := r.columns[column]; ok {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	l := domain.Locality{
+		ID:           value("id"),
+		LocalityName: value("locality_name"),
+		ProvinceName: value("province_name"),
+		CountryName:  value("country_name"),
+	}
+	if l.ProvinceID, err = parseInt(value("province_id")); err != nil {
+		return domain.LocalityImportRow{}, &RowError{Line: line, Err: errors.New("invalid province_id")}
+	}
+	if l.CountryID, err = parseInt(value("country_id")); err != nil {
+		return domain.LocalityImportRow{}, &RowError{Line: line, Err: errors.New("invalid country_id")}
+	}
+	if l.Latitude, err = parseFloat(value("latitude")); err != nil {
+		return domain.LocalityImportRow{}, &RowError{Line: line, Err: errors.New("invalid latitude")}
+	}
+	if l.Longitude, err = parseFloat(value("longitude")); err != nil {
+		return domain.LocalityImportRow{}, &RowError{Line: line, Err: errors.New("invalid longitude")}
+	}
+	return domain.LocalityImportRow{Line: line, Locality: l}, nil
+}
+
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// Next skips blank lines, every other line must be a json object with the fields of domain.Locality
+func (r *ndjsonReader) Next() (domain.LocalityImportRow, error) {
+	for r.scanner.Scan() {
+		r.line++
+		text := strings.TrimSpace(r.scanner.Text())
+		if text == "" {
+			continue
+		}
+		var l domain.Locality
+		if err := json.Unmarshal([]byte(text), &l); err != nil {
+			return domain.LocalityImportRow{}, &RowError{Line: r.line, Err: errors.New("invalid json")}
+		}
+		return domain.LocalityImportRow{Line: r.line, Locality: l}, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return domain.LocalityImportRow{}, ErrUnreadableFile
+	}
+	return domain.LocalityImportRow{}, io.EOF
+}
+
+func knownColumn(name string) bool {
+	for _, column := range importColumns {
+		if column == name {
+			return true
+		}
+	}
+	return false
+}
+
+// parseInt returns zero for an empty value, like a missing json field
+func parseInt(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// parseFloat returns nil for an empty value, like a missing json field
+func parseFloat(value string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
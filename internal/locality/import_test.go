@@ -0,0 +1,87 @@
+package locality
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCSVReader passes when the columns are matched by name and empty values are left unset
+func TestCSVReader(t *testing.T) {
+	// Arrange
+	file := "\ufeffLocality_Name, id ,latitude,longitude,province_id\n\"Capital, San Luis\",5700,-33.3,-66.3,3\nLa Plata,1900,,,\n"
+
+	// Act
+	rows, err := NewImportReader(ImportCSV, strings.NewReader(file))
+	assert.NoError(t, err)
+	first, errFirst := rows.Next()
+	second, errSecond := rows.Next()
+	_, errEnd := rows.Next()
+
+	// Assert
+	assert.NoError(t, errFirst)
+	assert.NoError(t, errSecond)
+	assert.Equal(t, io.EOF, errEnd)
+	assert.Equal(t, 2, first.Line)
+	assert.Equal(t, "Capital, San Luis", first.Locality.LocalityName)
+	assert.Equal(t, 3, first.Locality.ProvinceID)
+	assert.Equal(t, -66.3, *first.Locality.Longitude)
+	assert.Equal(t, domain.LocalityImportRow{Line: 3, Locality: domain.Locality{ID: "1900", LocalityName: "La Plata"}}, second)
+}
+
+// TestCSVReader_FailHeader passes when the header has unknown, repeated or missing columns
+func TestCSVReader_FailHeader(t *testing.T) {
+	for _, header := range []string{"", "id,name", "id,locality_name,id", "locality_name,province_id"} {
+		_, err := NewImportReader(ImportCSV, strings.NewReader(header+"\n"))
+		assert.EqualError(t, err, ErrInvalidHeader.Error(), header)
+	}
+}
+
+// TestCSVReader_RowErrors passes when a bad row is reported with its line and the next rows are still read
+func TestCSVReader_RowErrors(t *testing.T) {
+	// Arrange
+	file := "id,locality_name,latitude,longitude\n5700,Capital\n5730,Villa Mercedes,norte,0\n1900,La Plata,,\n"
+	rows, _ := NewImportReader(ImportCSV, strings.NewReader(file))
+
+	// Act
+	_, errFields := rows.Next()
+	_, errLatitude := rows.Next()
+	last, errLast := rows.Next()
+
+	// Assert
+	assert.Equal(t, 2, errFields.(*RowError).Line)
+	assert.EqualError(t, errLatitude, "line 3: invalid latitude")
+	assert.NoError(t, errLast)
+	assert.Equal(t, "1900", last.Locality.ID)
+}
+
+// TestNDJSONReader passes when blank lines are skipped and bad json is reported with its line
+func TestNDJSONReader(t *testing.T) {
+	// Arrange
+	file := "{\"id\": \"5700\", \"locality_name\": \"Capital\", \"latitude\": -33.3, \"longitude\": -66.3}\n\n{bad}\n{\"id\": \"1900\"}"
+	rows, _ := NewImportReader(ImportNDJSON, strings.NewReader(file))
+
+	// Act
+	first, errFirst := rows.Next()
+	_, errBad := rows.Next()
+	last, errLast := rows.Next()
+	_, errEnd := rows.Next()
+
+	// Assert
+	assert.NoError(t, errFirst)
+	assert.Equal(t, -33.3, *first.Locality.Latitude)
+	assert.EqualError(t, errBad, "line 3: invalid json")
+	assert.NoError(t, errLast)
+	assert.Equal(t, 4, last.Line)
+	assert.Equal(t, io.EOF, errEnd)
+}
+
+// TestNewImportReader_FailFormat passes when the format is unknown
+func TestNewImportReader_FailFormat(t *testing.T) {
+	_, err := NewImportReader("xlsx", strings.NewReader(""))
+
+	assert.EqualError(t, err, ErrUnsupportedFormat.Error())
+}
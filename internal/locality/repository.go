@@ -4,10 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 
 	"log"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
 	"github.com/go-sql-driver/mysql"
 )
 
@@ -18,6 +21,10 @@ var (
 	ErrInternal             = errors.New("database internal error")
 	ErrForeignKeyConstraint = errors.New("a column table constraint fails")
 	ErrAlreadyExists        = errors.New("id already exists")
+	ErrProvinceNotFound     = errors.New("province not found")
+	ErrCountryNotFound      = errors.New("country not found")
+	ErrInvalidCoordinates   = errors.New("latitude must be between -90 and 90 and longitude between -180 and 180, both given together")
+	ErrDataTooLong          = errors.New("data too long")
 )
 
 // Repository encapsulates the storage of a Locality.
@@ -27,8 +34,18 @@ type Repository interface {
 	Exists(ctx context.Context, id string) bool
 	Save(ctx context.Context, l domain.Locality) (string, error)
 	Get(ctx context.Context, id string) (domain.Locality, error)
+	// GetProvince returns the province with its country, or ErrProvinceNotFound
+	GetProvince(ctx context.Context, id int) (domain.Province, error)
+	// SaveByProvinceName stores the locality in the province with its province name, inside the country with its country id,
+	// or country name if the id is zero. Names are matched without case, a missing province or country is created in the
+	// same transaction as the locality and a missing country id is ErrCountryNotFound. The province is returned
+	SaveByProvinceName(ctx context.Context, l domain.Locality) (domain.Province, error)
 	ReportSellers(ctx context.Context) ([]domain.ReportSellers, error)
 	ReportSellersByLocationID(ctx context.Context, id string) ([]domain.ReportSellers, error)
+	UpdateCoordinates(ctx context.Context, id string, c domain.Coordinates) error
+	// SaveBatch stores the localities in a single transaction, resolving their provinces like SaveByProvinceName.
+	// The rows that fail are returned and left out, the rest is committed, or rolled back on a dry run
+	SaveBatch(ctx context.Context, rows []domain.LocalityImportRow, dryRun bool) ([]domain.LocalityImportError, error)
 }
 
 type repository struct {
@@ -40,10 +57,19 @@ const (
 	GET_CARRIES_BY_LOCATION_ID      = "SELECT localities.id, localities.locality_name, COUNT(carries.locality_id) AS carries_count FROM carries RIGHT JOIN localities ON carries.locality_id = localities.id WHERE localities.id = ? GROUP BY carries.locality_id, localities.locality_name, localities.id;"
 	GET_SELLERS                     = "SELECT localities.id, localities.locality_name, COUNT(sellers.locality_id) AS sellers_count FROM sellers RIGHT JOIN localities ON sellers.locality_id = localities.id GROUP BY sellers.locality_id, localities.locality_name, localities.id;"
 	GET_SELLERS_BY_LOCATION_ID      = "SELECT localities.id, localities.locality_name, COUNT(sellers.locality_id) AS sellers_count FROM sellers RIGHT JOIN localities ON sellers.locality_id = localities.id WHERE localities.id = ? GROUP BY sellers.locality_id, localities.locality_name, localities.id;"
-	SAVE_LOCALITY                   = "INSERT INTO localities (id, locality_name, province_name, country_name) VALUES (?, ?, ?, ?)"
+	SAVE_LOCALITY                   = "INSERT INTO localities (id, locality_name, province_id, latitude, longitude) VALUES (?, ?, ?, ?, ?)"
 	EXIST_LOCALITY                  = "SELECT id FROM localities WHERE id=?"
-	GET_LOCALITY                    = "SELECT id, locality_name, province_name, country_name FROM localities WHERE id=?;"
+	GET_LOCALITY                    = "SELECT l.id, l.locality_name, p.id, p.name, c.id, c.name, l.latitude, l.longitude FROM localities AS l INNER JOIN provinces AS p ON p.id = l.province_id INNER JOIN countries AS c ON c.id = p.country_id WHERE l.id=?;"
+	GET_PROVINCE                    = "SELECT p.id, p.name, c.id, c.name FROM provinces AS p INNER JOIN countries AS c ON c.id = p.country_id WHERE p.id=?;"
+	GET_COUNTRY_NAME                = "SELECT name FROM countries WHERE id=?;"
+	INSERT_COUNTRY                  = "INSERT IGNORE INTO countries (name) VALUES (?);"
+	FIND_COUNTRY                    = "SELECT id, name FROM countries WHERE name=?;"
+	INSERT_PROVINCE                 = "INSERT IGNORE INTO provinces (name, country_id) VALUES (?, ?);"
+	FIND_PROVINCE                   = "SELECT id, name FROM provinces WHERE country_id=? AND name=?;"
+	UPDATE_COORDINATES              = "UPDATE localities SET latitude=?, longitude=? WHERE id=?;"
 	MySqlNumberForeignKeyConstraint = 1452
+	MySqlNumberDuplicate            = 1062
+	MySqlNumberDataTooLong          = 1406
 )
 
 func NewRepository(db *sql.DB) Repository {
@@ -142,7 +168,7 @@ func (r *repository) Save(ctx context.Context, l domain.Locality) (string, error
 		return "0", err
 	}
 
-	_, err = stmt.Exec(l.ID, l.LocalityName, l.ProvinceName, l.CountryName)
+	_, err = stmt.Exec(l.ID, l.LocalityName, l.ProvinceID, l.Latitude, l.Longitude)
 	if err != nil {
 		mysqlError, ok := err.(*mysql.MySQLError)
 		if ok {
@@ -160,7 +186,8 @@ func (r *repository) Save(ctx context.Context, l domain.Locality) (string, error
 func (r *repository) Get(ctx context.Context, id string) (domain.Locality, error) {
 	row := r.db.QueryRow(GET_LOCALITY, id)
 	l := domain.Locality{}
-	err := row.Scan(&l.ID, &l.LocalityName, &l.ProvinceName, &l.CountryName)
+	var latitude, longitude sql.NullFloat64
+	err := row.Scan(&l.ID, &l.LocalityName, &l.ProvinceID, &l.ProvinceName, &l.CountryID, &l.CountryName, &latitude, &longitude)
 	if err != nil {
 		switch err {
 		case sql.ErrNoRows:
@@ -169,6 +196,194 @@ func (r *repository) Get(ctx context.Context, id string) (domain.Locality, error
 			return domain.Locality{}, ErrInternal
 		}
 	}
+	if latitude.Valid && longitude.Valid {
+		l.Latitude = &latitude.Float64
+		l.Longitude = &longitude.Float64
+	}
 
 	return l, nil
 }
+
+func (r *repository) UpdateCoordinates(ctx context.Context, id string, c domain.Coordinates) error {
+	if _, err := r.db.ExecContext(ctx, UPDATE_COORDINATES, c.Latitude, c.Longitude, id); err != nil {
+		return ErrInternal
+	}
+	return nil
+}
+
+func (r *repository) GetProvince(ctx context.Context, id int) (domain.Province, error) {
+	p := domain.Province{}
+	err := r.db.QueryRowContext(ctx, GET_PROVINCE, id).Scan(&p.ID, &p.Name, &p.CountryID, &p.CountryName)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return domain.Province{}, ErrProvinceNotFound
+		default:
+			return domain.Province{}, ErrInternal
+		}
+	}
+	return p, nil
+}
+
+func (r *repository) SaveByProvinceName(ctx context.Context, l domain.Locality) (domain.Province, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Log(err)
+		return domain.Province{}, ErrInternal
+	}
+
+	p, err := saveByProvinceName(ctx, tx, l)
+	if err != nil {
+		_ = tx.Rollback()
+		return domain.Province{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.Log(err)
+		return domain.Province{}, ErrInternal
+	}
+	return p, nil
+}
+
+// saveByProvinceName resolves the province of the locality and inserts the locality inside the transaction,
+// so a country or province created for a locality that fails is rolled back with it
+func saveByProvinceName(ctx context.Context, tx *sql.Tx, l domain.Locality) (domain.Province, error) {
+	p, err := resolveProvince(ctx, tx, l.CountryID, l.CountryName, l.ProvinceName)
+	if err != nil {
+		return domain.Province{}, err
+	}
+	if _, err := tx.ExecContext(ctx, SAVE_LOCALITY, l.ID, l.LocalityName, p.ID, l.Latitude, l.Longitude); err != nil {
+		logging.Log(err)
+		return domain.Province{}, parseInsertError(err)
+	}
+	return p, nil
+}
+
+// resolveProvince inserts the country and the province when they are missing, the unique names
+// of both tables make the inserts a no-op for existing names and the lookups return the stored spelling
+func resolveProvince(ctx context.Context, tx *sql.Tx, countryID int, countryName string, provinceName string) (domain.Province, error) {
+	p := domain.Province{CountryID: countryID}
+	if countryID != 0 {
+		err := tx.QueryRowContext(ctx, GET_COUNTRY_NAME, countryID).Scan(&p.CountryName)
+		if err != nil {
+			logging.Log(err)
+			switch err {
+			case sql.ErrNoRows:
+				return domain.Province{}, ErrCountryNotFound
+			default:
+				return domain.Province{}, ErrInternal
+			}
+		}
+	} else {
+		if _, err := tx.ExecContext(ctx, INSERT_COUNTRY, countryName); err != nil {
+			logging.Log(err)
+			return domain.Province{}, ErrInternal
+		}
+		if err := tx.QueryRowContext(ctx, FIND_COUNTRY, countryName).Scan(&p.CountryID, &p.CountryName); err != nil {
+			logging.Log(err)
+			return domain.Province{}, ErrInternal
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, INSERT_PROVINCE, provinceName, p.CountryID); err != nil {
+		logging.Log(err)
+		return domain.Province{}, ErrInternal
+	}
+	if err := tx.QueryRowContext(ctx, FIND_PROVINCE, p.CountryID, provinceName).Scan(&p.ID, &p.Name); err != nil {
+		logging.Log(err)
+		return domain.Province{}, ErrInternal
+	}
+	return p, nil
+}
+
+func (r *repository) SaveBatch(ctx context.Context, rows []domain.LocalityImportRow, dryRun bool) ([]domain.LocalityImportError, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, ErrInternal
+	}
+
+	failed, err := saveBatch(ctx, tx, rows)
+	if err != nil || dryRun {
+		_ = tx.Rollback()
+		return failed, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, ErrInternal
+	}
+	return failed, nil
+}
+
+// saveBatch inserts every row inside the transaction. MySQL only undoes the statement that failed,
+// so a row with a repeated id or a missing province is reported without losing the rest of the batch
+func saveBatch(ctx context.Context, tx *sql.Tx, rows []domain.LocalityImportRow) ([]domain.LocalityImportError, error) {
+	stmt, err := tx.PrepareContext(ctx, SAVE_LOCALITY)
+	if err != nil {
+		return nil, ErrInternal
+	}
+	defer stmt.Close()
+
+	failed := []domain.LocalityImportError{}
+	provinces := map[string]domain.Province{}
+	for _, row := range rows {
+		l := row.Locality
+		province, err := batchProvince(ctx, tx, l, provinces)
+		if err == nil {
+			_, err = stmt.ExecContext(ctx, l.ID, l.LocalityName, province.ID, l.Latitude, l.Longitude)
+			err = parseInsertError(err)
+		}
+		if err == ErrInternal {
+			return nil, ErrInternal
+		}
+		if err != nil {
+			failed = append(failed, domain.LocalityImportError{Line: row.Line, ID: l.ID, Error: err.Error()})
+		}
+	}
+	return failed, nil
+}
+
+// batchProvince returns the province of the locality, provinces resolved by name are kept for the rest of the batch
+func batchProvince(ctx context.Context, tx *sql.Tx, l domain.Locality, provinces map[string]domain.Province) (domain.Province, error) {
+	if l.ProvinceID != 0 {
+		p := domain.Province{}
+		err := tx.QueryRowContext(ctx, GET_PROVINCE, l.ProvinceID).Scan(&p.ID, &p.Name, &p.CountryID, &p.CountryName)
+		if err != nil {
+			switch err {
+			case sql.ErrNoRows:
+				return domain.Province{}, ErrProvinceNotFound
+			default:
+				return domain.Province{}, ErrInternal
+			}
+		}
+		return p, nil
+	}
+
+	key := fmt.Sprintf("%d|%s|%s", l.CountryID, strings.ToLower(l.CountryName), strings.ToLower(l.ProvinceName))
+	if p, ok := provinces[key]; ok {
+		return p, nil
+	}
+	p, err := resolveProvince(ctx, tx, l.CountryID, l.CountryName, l.ProvinceName)
+	if err != nil {
+		return domain.Province{}, err
+	}
+	provinces[key] = p
+	return p, nil
+}
+
+// parseInsertError translates the errors of a locality insert that only fail its own row
+func parseInsertError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if mysqlError, ok := err.(*mysql.MySQLError); ok {
+		switch mysqlError.Number {
+		case MySqlNumberDuplicate:
+			return ErrAlreadyExists
+		case MySqlNumberForeignKeyConstraint:
+			return ErrProvinceNotFound
+		case MySqlNumberDataTooLong:
+			return ErrDataTooLong
+		}
+	}
+	return ErrInternal
+}
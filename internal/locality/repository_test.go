@@ -15,7 +15,9 @@ import (
 var locality_test = domain.Locality{
 	ID:           "5700",
 	LocalityName: "Capital",
+	ProvinceID:   3,
 	ProvinceName: "San Luis",
+	CountryID:    1,
 	CountryName:  "Argentina",
 }
 
@@ -49,7 +51,7 @@ func TestSave_Locality_OK(t *testing.T) {
 	defer db.Close()
 
 	mock.ExpectPrepare(regexp.QuoteMeta(SAVE_LOCALITY))
-	mock.ExpectExec(regexp.QuoteMeta(SAVE_LOCALITY)).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(SAVE_LOCALITY)).WithArgs(locality_test.ID, locality_test.LocalityName, locality_test.ProvinceID, nil, nil).WillReturnResult(sqlmock.NewResult(1, 1))
 
 	columns := []string{"id", "locality_name", "province_name", "country_name"}
 	rows := sqlmock.NewRows(columns)
@@ -143,11 +145,11 @@ func TestGet_Locality_OK(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	column := []string{"id", "locality_name", "province_name", "country_name"}
+	column := []string{"id", "locality_name", "province_id", "province_name", "country_id", "country_name", "latitude", "longitude"}
 	rows := sqlmock.NewRows(column)
-	locality := domain.Locality{ID: locality_test.ID, LocalityName: locality_test.LocalityName, ProvinceName: locality_test.ProvinceName, CountryName: locality_test.CountryName}
+	locality := locality_test
 
-	rows.AddRow(locality.ID, locality.LocalityName, locality.ProvinceName, locality.CountryName)
+	rows.AddRow(locality.ID, locality.LocalityName, locality.ProvinceID, locality.ProvinceName, locality.CountryID, locality.CountryName, nil, nil)
 
 	mock.ExpectQuery(regexp.QuoteMeta(GET_LOCALITY)).WillReturnRows(rows)
 
@@ -161,6 +163,29 @@ func TestGet_Locality_OK(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+// TestGet_Locality_WithCoordinates passes when the stored coordinates are returned
+func TestGet_Locality_WithCoordinates(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	column := []string{"id", "locality_name", "province_id", "province_name", "country_id", "country_name", "latitude", "longitude"}
+	rows := sqlmock.NewRows(column)
+	rows.AddRow(locality_test.ID, locality_test.LocalityName, locality_test.ProvinceID, locality_test.ProvinceName, locality_test.CountryID, locality_test.CountryName, -33.301726, -66.337752)
+
+	mock.ExpectQuery(regexp.QuoteMeta(GET_LOCALITY)).WithArgs(locality_test.ID).WillReturnRows(rows)
+
+	// Act
+	result, err := NewRepository(db).Get(context.TODO(), locality_test.ID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, -33.301726, *result.Latitude)
+	assert.Equal(t, -66.337752, *result.Longitude)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGet_Locality_FailErrNotFound(t *testing.T) {
 	// Arrange
 	db, mock, err := sqlmock.New()
@@ -509,3 +534,205 @@ func TestRepositoryReportCarriesByLocationIDFailInternal(t *testing.T) {
 	assert.Empty(t, report)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+// * ---------------------- Provinces ----------------------
+// TestGetProvince_FailNotFound passes when the province does not exist
+func TestGetProvince_FailNotFound(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(GET_PROVINCE)).WithArgs(9).WillReturnError(sql.ErrNoRows)
+
+	// Act
+	_, err = NewRepository(db).GetProvince(context.TODO(), 9)
+
+	// Assert
+	assert.EqualError(t, err, ErrProvinceNotFound.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestSaveByProvinceName_OK passes when the country and province are created if missing and the locality is stored
+// in the same transaction with the stored names
+func TestSaveByProvinceName_OK(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_COUNTRY)).WithArgs("argentina").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(FIND_COUNTRY)).WithArgs("argentina").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Argentina"))
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_PROVINCE)).WithArgs("san luis", 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(FIND_PROVINCE)).WithArgs(1, "san luis").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "San Luis"))
+	mock.ExpectExec(regexp.QuoteMeta(SAVE_LOCALITY)).WithArgs("5700", "Capital", 3, nil, nil).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	result, err := NewRepository(db).SaveByProvinceName(context.TODO(), domain.Locality{ID: "5700", LocalityName: "Capital", CountryName: "argentina", ProvinceName: "san luis"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Province{ID: 3, Name: "San Luis", CountryID: 1, CountryName: "Argentina"}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestSaveByProvinceName_FailAlreadyExists passes when the locality fails and the new country and province are rolled back
+func TestSaveByProvinceName_FailAlreadyExists(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_COUNTRY)).WithArgs("argentina").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(FIND_COUNTRY)).WithArgs("argentina").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "argentina"))
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_PROVINCE)).WithArgs("san luis", 1).WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(FIND_PROVINCE)).WithArgs(1, "san luis").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "san luis"))
+	mock.ExpectExec(regexp.QuoteMeta(SAVE_LOCALITY)).WillReturnError(&mysql.MySQLError{Number: MySqlNumberDuplicate})
+	mock.ExpectRollback()
+
+	// Act
+	_, err = NewRepository(db).SaveByProvinceName(context.TODO(), domain.Locality{ID: "5700", LocalityName: "Capital", CountryName: "argentina", ProvinceName: "san luis"})
+
+	// Assert
+	assert.EqualError(t, err, ErrAlreadyExists.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestSaveByProvinceName_FailCountryNotFound passes when the country id does not exist and nothing is stored
+func TestSaveByProvinceName_FailCountryNotFound(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(GET_COUNTRY_NAME)).WithArgs(7).WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	// Act
+	_, err = NewRepository(db).SaveByProvinceName(context.TODO(), domain.Locality{ID: "5700", LocalityName: "Capital", CountryID: 7, ProvinceName: "San Luis"})
+
+	// Assert
+	assert.EqualError(t, err, ErrCountryNotFound.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// * ---------------------- UpdateCoordinates --------------------------
+// TestUpdateCoordinates_OK passes when the coordinates are stored
+func TestUpdateCoordinates_OK(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(UPDATE_COORDINATES)).WithArgs(-33.301726, -66.337752, "5700").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err = NewRepository(db).UpdateCoordinates(context.TODO(), "5700", domain.Coordinates{Latitude: -33.301726, Longitude: -66.337752})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestUpdateCoordinates_FailInternal passes when the update fails
+func TestUpdateCoordinates_FailInternal(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(UPDATE_COORDINATES)).WillReturnError(sql.ErrConnDone)
+
+	// Act
+	err = NewRepository(db).UpdateCoordinates(context.TODO(), "5700", domain.Coordinates{})
+
+	// Assert
+	assert.EqualError(t, err, ErrInternal.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// * ---------------------- SaveBatch --------------------------
+// TestSaveBatch_OK passes when a repeated id is reported and the rest of the batch is committed,
+// resolving each province name once
+func TestSaveBatch_OK(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := []domain.LocalityImportRow{
+		{Line: 2, Locality: domain.Locality{ID: "5700", LocalityName: "Capital", ProvinceID: 3}},
+		{Line: 3, Locality: domain.Locality{ID: "5730", LocalityName: "Villa Mercedes", ProvinceName: "San Luis", CountryName: "Argentina"}},
+		{Line: 4, Locality: domain.Locality{ID: "5881", LocalityName: "Merlo", ProvinceName: "san luis", CountryName: "argentina"}},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare(regexp.QuoteMeta(SAVE_LOCALITY))
+	mock.ExpectQuery(regexp.QuoteMeta(GET_PROVINCE)).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "id", "name"}).AddRow(3, "San Luis", 1, "Argentina"))
+	mock.ExpectExec(regexp.QuoteMeta(SAVE_LOCALITY)).WithArgs("5700", "Capital", 3, nil, nil).WillReturnError(&mysql.MySQLError{Number: MySqlNumberDuplicate})
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_COUNTRY)).WithArgs("Argentina").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(FIND_COUNTRY)).WithArgs("Argentina").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Argentina"))
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_PROVINCE)).WithArgs("San Luis", 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(FIND_PROVINCE)).WithArgs(1, "San Luis").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "San Luis"))
+	mock.ExpectExec(regexp.QuoteMeta(SAVE_LOCALITY)).WithArgs("5730", "Villa Mercedes", 3, nil, nil).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(SAVE_LOCALITY)).WithArgs("5881", "Merlo", 3, nil, nil).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	failed, err := NewRepository(db).SaveBatch(context.TODO(), rows, false)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.LocalityImportError{{Line: 2, ID: "5700", Error: ErrAlreadyExists.Error()}}, failed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestSaveBatch_DryRun passes when the batch is rolled back after checking every row
+func TestSaveBatch_DryRun(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := []domain.LocalityImportRow{{Line: 2, Locality: domain.Locality{ID: "5700", LocalityName: "Capital", ProvinceID: 9}}}
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare(regexp.QuoteMeta(SAVE_LOCALITY))
+	mock.ExpectQuery(regexp.QuoteMeta(GET_PROVINCE)).WithArgs(9).WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	// Act
+	failed, err := NewRepository(db).SaveBatch(context.TODO(), rows, true)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.LocalityImportError{{Line: 2, ID: "5700", Error: ErrProvinceNotFound.Error()}}, failed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestSaveBatch_FailInternal passes when an unexpected error rolls back the whole batch
+func TestSaveBatch_FailInternal(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := []domain.LocalityImportRow{{Line: 2, Locality: domain.Locality{ID: "5700", LocalityName: "Capital", ProvinceID: 3}}}
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare(regexp.QuoteMeta(SAVE_LOCALITY))
+	mock.ExpectQuery(regexp.QuoteMeta(GET_PROVINCE)).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "id", "name"}).AddRow(3, "San Luis", 1, "Argentina"))
+	mock.ExpectExec(regexp.QuoteMeta(SAVE_LOCALITY)).WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+
+	// Act
+	_, err = NewRepository(db).SaveBatch(context.TODO(), rows, false)
+
+	// Assert
+	assert.EqualError(t, err, ErrInternal.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
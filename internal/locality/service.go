@@ -2,6 +2,11 @@ package locality
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
@@ -13,8 +18,27 @@ type Service interface {
 	Create(ctx context.Context, locality domain.Locality) (domain.Locality, error)
 	Get(ctx context.Context, id string) (domain.Locality, error)
 	ReportSellers(ctx context.Context, locality_ID *string) ([]domain.ReportSellers, error)
+	UpdateCoordinates(ctx context.Context, id string, c domain.Coordinates) (domain.Locality, error)
+	// Import validates every row and stores the valid ones in transactions of ImportBatchSize rows,
+	// the report lists every row left out. A dry run validates against the database without storing anything
+	Import(ctx context.Context, rows ImportReader, dryRun bool) (domain.LocalityImportReport, error)
 }
 
+// ImportBatchSize is the number of localities stored by each transaction of an import
+const ImportBatchSize = 500
+
+// Limits of the localities, provinces and countries tables
+const (
+	MaxIDLength   = 10
+	MaxNameLength = 100
+)
+
+var (
+	ErrMissingFields   = errors.New("id and locality_name are required")
+	ErrMissingProvince = errors.New("a province_id, or a province_name with a country_id or country_name, is required")
+	ErrNameTooLong     = fmt.Errorf("id can not be longer than %d characters, province_name and country_name than %d", MaxIDLength, MaxNameLength)
+)
+
 type service struct {
 	repository Repository
 }
@@ -42,26 +66,36 @@ func (s *service) ReportSellers(ctx context.Context, locality_ID *string) (repor
 	return
 }
 
-// Create save a locality in the db and returns that locality
+// Create save a locality in the db and returns that locality.
+// The province is looked up by its id, or by its name in the country given by id or name
 func (s *service) Create(ctx context.Context, locality domain.Locality) (l domain.Locality, err error) {
 	if s.repository.Exists(ctx, locality.ID) {
+		logging.Log(ErrAlreadyExists)
 		err = ErrAlreadyExists
 		return
 	}
-
-	l = domain.Locality{
-		ID:           locality.ID,
-		LocalityName: locality.LocalityName,
-		ProvinceName: locality.ProvinceName,
-		CountryName:  locality.CountryName,
+	if !validCoordinates(locality) {
+		logging.Log(ErrInvalidCoordinates)
+		return domain.Locality{}, ErrInvalidCoordinates
 	}
 
-	_, err = s.repository.Save(ctx, l)
+	province, err := s.saveInProvince(ctx, locality)
 	if err != nil {
 		logging.Log(err)
 		return domain.Locality{}, err
 	}
 
+	l = domain.Locality{
+		ID:           locality.ID,
+		LocalityName: locality.LocalityName,
+		ProvinceID:   province.ID,
+		ProvinceName: province.Name,
+		CountryID:    province.CountryID,
+		CountryName:  province.CountryName,
+		Latitude:     locality.Latitude,
+		Longitude:    locality.Longitude,
+	}
+
 	return
 }
 
@@ -73,3 +107,150 @@ func (s *service) Get(ctx context.Context, id string) (l domain.Locality, err er
 	}
 	return
 }
+
+// UpdateCoordinates sets the coordinates of an existing locality and returns it
+func (s *service) UpdateCoordinates(ctx context.Context, id string, c domain.Coordinates) (domain.Locality, error) {
+	if !domain.ValidCoordinates(c.Latitude, c.Longitude) {
+		logging.Log(ErrInvalidCoordinates)
+		return domain.Locality{}, ErrInvalidCoordinates
+	}
+	l, err := s.repository.Get(ctx, id)
+	if err != nil {
+		logging.Log(err)
+		return domain.Locality{}, err
+	}
+	if err := s.repository.UpdateCoordinates(ctx, id, c); err != nil {
+		logging.Log(err)
+		return domain.Locality{}, err
+	}
+	l.Latitude = &c.Latitude
+	l.Longitude = &c.Longitude
+	return l, nil
+}
+
+func (s *service) Import(ctx context.Context, rows ImportReader, dryRun bool) (domain.LocalityImportReport, error) {
+	report := domain.LocalityImportReport{DryRun: dryRun, Errors: []domain.LocalityImportError{}}
+	seen := map[string]int{}
+	batch := make([]domain.LocalityImportRow, 0, ImportBatchSize)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		failed, err := s.repository.SaveBatch(ctx, batch, dryRun)
+		if err != nil {
+			logging.Log(err)
+			return err
+		}
+		report.Errors = append(report.Errors, failed...)
+		if !dryRun {
+			report.Imported += len(batch) - len(failed)
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		row, err := rows.Next()
+		if err == io.EOF {
+			break
+		}
+		var rowErr *RowError
+		if errors.As(err, &rowErr) {
+			report.Rows++
+			report.Errors = append(report.Errors, domain.LocalityImportError{Line: rowErr.Line, Error: rowErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			logging.Log(err)
+			return report, err
+		}
+
+		report.Rows++
+		l := normalize(row.Locality)
+		if err := validateImport(l); err != nil {
+			report.Errors = append(report.Errors, domain.LocalityImportError{Line: row.Line, ID: l.ID, Error: err.Error()})
+			continue
+		}
+		if line, ok := seen[l.ID]; ok {
+			report.Errors = append(report.Errors, domain.LocalityImportError{Line: row.Line, ID: l.ID, Error: fmt.Sprintf("id already in line %d", line)})
+			continue
+		}
+		seen[l.ID] = row.Line
+
+		batch = append(batch, domain.LocalityImportRow{Line: row.Line, Locality: l})
+		if len(batch) == ImportBatchSize {
+			if err := flush(); err != nil {
+				return report, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return report, err
+	}
+
+	// rows rejected by the database come after the ones rejected while reading the later lines
+	sort.SliceStable(report.Errors, func(i, j int) bool {
+		return report.Errors[i].Line < report.Errors[j].Line
+	})
+	report.Failed = len(report.Errors)
+	report.Valid = report.Rows - report.Failed
+	return report, nil
+}
+
+// normalize trims the names of an imported locality, Create does the same while resolving the province
+func normalize(l domain.Locality) domain.Locality {
+	l.ID = strings.TrimSpace(l.ID)
+	l.LocalityName = strings.TrimSpace(l.LocalityName)
+	l.ProvinceName = strings.TrimSpace(l.ProvinceName)
+	l.CountryName = strings.TrimSpace(l.CountryName)
+	return l
+}
+
+// validateImport checks an imported locality with the rules Create applies to a request,
+// plus the lengths of the columns so a single row can not stop the import
+func validateImport(l domain.Locality) error {
+	if l.ID == "" || l.LocalityName == "" {
+		return ErrMissingFields
+	}
+	if l.ProvinceID == 0 && (l.ProvinceName == "" || (l.CountryID == 0 && l.CountryName == "")) {
+		return ErrMissingProvince
+	}
+	if len([]rune(l.ID)) > MaxIDLength || len([]rune(l.ProvinceName)) > MaxNameLength || len([]rune(l.CountryName)) > MaxNameLength {
+		return ErrNameTooLong
+	}
+	if !validCoordinates(l) {
+		return ErrInvalidCoordinates
+	}
+	return nil
+}
+
+// validCoordinates reports whether the locality has no coordinates or both of them within range
+func validCoordinates(l domain.Locality) bool {
+	if l.Latitude == nil || l.Longitude == nil {
+		return l.Latitude == nil && l.Longitude == nil
+	}
+	return domain.ValidCoordinates(*l.Latitude, *l.Longitude)
+}
+
+// saveInProvince stores the locality and returns its province, ErrBadRequest is returned when the locality
+// has neither a province id nor the names it needs
+func (s *service) saveInProvince(ctx context.Context, locality domain.Locality) (domain.Province, error) {
+	if locality.ProvinceID != 0 {
+		province, err := s.repository.GetProvince(ctx, locality.ProvinceID)
+		if err != nil {
+			return domain.Province{}, err
+		}
+		if _, err := s.repository.Save(ctx, locality); err != nil {
+			return domain.Province{}, err
+		}
+		return province, nil
+	}
+
+	locality.ProvinceName = strings.TrimSpace(locality.ProvinceName)
+	locality.CountryName = strings.TrimSpace(locality.CountryName)
+	if locality.ProvinceName == "" || (locality.CountryID == 0 && locality.CountryName == "") {
+		return domain.Province{}, ErrBadRequest
+	}
+	return s.repository.SaveByProvinceName(ctx, locality)
+}
@@ -2,6 +2,8 @@ package locality
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
@@ -20,8 +22,15 @@ type MockRepositoryLocality struct {
 	Locality     domain.Locality
 	DataMock     []domain.Locality
 	Report       []domain.ReportSellers
+	Province     domain.Province
 	ErrorMock    error
 	ErrorIdExist error
+	LastSaved    domain.Locality
+	LastResolve  []string
+	LastCoords   *domain.Coordinates
+	Batches      [][]domain.LocalityImportRow
+	LastDryRun   bool
+	RejectIDs    map[string]error
 }
 
 func (r *MockRepositoryLocality) ReportCarries(ctx context.Context) ([]domain.ReportCarries, error) {
@@ -52,10 +61,28 @@ func (r *MockRepositoryLocality) Save(ctx context.Context, l domain.Locality) (i
 		err = r.ErrorMock
 		return
 	}
+	r.LastSaved = l
 	id = "5700"
 	return
 }
 
+func (r *MockRepositoryLocality) GetProvince(ctx context.Context, id int) (domain.Province, error) {
+	if r.Province.ID != id {
+		return domain.Province{}, ErrProvinceNotFound
+	}
+	return r.Province, nil
+}
+
+func (r *MockRepositoryLocality) SaveByProvinceName(ctx context.Context, l domain.Locality) (domain.Province, error) {
+	if r.ErrorMock != nil {
+		return domain.Province{}, r.ErrorMock
+	}
+	r.LastResolve = []string{l.CountryName, l.ProvinceName}
+	l.ProvinceID = r.Province.ID
+	r.LastSaved = l
+	return r.Province, nil
+}
+
 func (r *MockRepositoryLocality) Exists(ctx context.Context, id string) (exist bool) {
 	exist = r.ErrorIdExist != nil
 	return
@@ -70,6 +97,26 @@ func (r *MockRepositoryLocality) ReportSellers(ctx context.Context) (report []do
 	return
 }
 
+func (r *MockRepositoryLocality) UpdateCoordinates(ctx context.Context, id string, c domain.Coordinates) error {
+	r.LastCoords = &c
+	return nil
+}
+
+func (r *MockRepositoryLocality) SaveBatch(ctx context.Context, rows []domain.LocalityImportRow, dryRun bool) ([]domain.LocalityImportError, error) {
+	if r.ErrorMock != nil {
+		return nil, r.ErrorMock
+	}
+	r.Batches = append(r.Batches, append([]domain.LocalityImportRow{}, rows...))
+	r.LastDryRun = dryRun
+	failed := []domain.LocalityImportError{}
+	for _, row := range rows {
+		if err, ok := r.RejectIDs[row.Locality.ID]; ok {
+			failed = append(failed, domain.LocalityImportError{Line: row.Line, ID: row.Locality.ID, Error: err.Error()})
+		}
+	}
+	return failed, nil
+}
+
 func (r *MockRepositoryLocality) ReportSellersByLocationID(ctx context.Context, id string) (report []domain.ReportSellers, err error) {
 	if r.ErrorMock != nil {
 		err = r.ErrorMock
@@ -142,12 +189,15 @@ func TestCreate_Locality_OK(t *testing.T) {
 	localityExpected := domain.Locality{
 		ID:           "5700",
 		LocalityName: "Capital",
+		ProvinceID:   3,
 		ProvinceName: "San Luis",
+		CountryID:    1,
 		CountryName:  "Argentina",
 	}
 
 	mockRepo := MockRepositoryLocality{
 		Locality:  localityExpected,
+		Province:  domain.Province{ID: 3, Name: "San Luis", CountryID: 1, CountryName: "Argentina"},
 		ErrorMock: nil,
 	}
 	service := NewService(&mockRepo)
@@ -164,7 +214,7 @@ func TestCreate_Locality_OK(t *testing.T) {
 func TestCreateLocality_Fail(t *testing.T) {
 	// Arrange
 	expectedError := ErrAlreadyExists
-	localityToCreate := domain.Locality{}
+	localityToCreate := domain.Locality{ID: "5700", LocalityName: "Capital", ProvinceName: "San Luis", CountryName: "Argentina"}
 
 	mockRepo := MockRepositoryLocality{
 		ErrorMock: expectedError,
@@ -280,3 +330,198 @@ func TestReportSellersNil(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, expectedReport, result)
 }
+
+// TestCreateLocality_ResolvesNames passes when the locality is stored with the trimmed names of its province
+func TestCreateLocality_ResolvesNames(t *testing.T) {
+	// Arrange
+	mockRepo := MockRepositoryLocality{
+		Province: domain.Province{ID: 3, Name: "Buenos Aires", CountryID: 1, CountryName: "Argentina"},
+	}
+	service := NewService(&mockRepo)
+
+	// Act
+	result, err := service.Create(ctx, domain.Locality{ID: "1900", LocalityName: "La Plata", ProvinceName: " buenos aires ", CountryName: "argentina"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"argentina", "buenos aires"}, mockRepo.LastResolve)
+	assert.Equal(t, 3, mockRepo.LastSaved.ProvinceID)
+	assert.Equal(t, "Buenos Aires", result.ProvinceName)
+}
+
+// TestCreateLocality_ByProvinceID passes when the province is looked up by its id
+func TestCreateLocality_ByProvinceID(t *testing.T) {
+	// Arrange
+	mockRepo := MockRepositoryLocality{
+		Province: domain.Province{ID: 3, Name: "Buenos Aires", CountryID: 1, CountryName: "Argentina"},
+	}
+	service := NewService(&mockRepo)
+
+	// Act
+	result, err := service.Create(ctx, domain.Locality{ID: "1900", LocalityName: "La Plata", ProvinceID: 3})
+	_, errMissing := service.Create(ctx, domain.Locality{ID: "1900", LocalityName: "La Plata", ProvinceID: 4})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "Argentina", result.CountryName)
+	assert.Nil(t, mockRepo.LastResolve)
+	assert.EqualError(t, errMissing, ErrProvinceNotFound.Error())
+}
+
+// TestCreateLocality_FailWithoutCountry passes when a province name comes without its country
+func TestCreateLocality_FailWithoutCountry(t *testing.T) {
+	// Arrange
+	service := NewService(&MockRepositoryLocality{})
+
+	// Act
+	_, err := service.Create(ctx, domain.Locality{ID: "1900", LocalityName: "La Plata", ProvinceName: "Buenos Aires"})
+
+	// Assert
+	assert.EqualError(t, err, ErrBadRequest.Error())
+}
+
+// TestCreateLocality_WithCoordinates passes when the coordinates are stored with the locality
+func TestCreateLocality_WithCoordinates(t *testing.T) {
+	// Arrange
+	mockRepo := MockRepositoryLocality{
+		Province: domain.Province{ID: 3, Name: "San Luis", CountryID: 1, CountryName: "Argentina"},
+	}
+	service := NewService(&mockRepo)
+	latitude, longitude := -33.301726, -66.337752
+
+	// Act
+	result, err := service.Create(ctx, domain.Locality{ID: "5700", LocalityName: "Capital", ProvinceID: 3, Latitude: &latitude, Longitude: &longitude})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, &latitude, mockRepo.LastSaved.Latitude)
+	assert.Equal(t, &longitude, result.Longitude)
+}
+
+// TestCreateLocality_FailCoordinates passes when a coordinate is missing or out of range
+func TestCreateLocality_FailCoordinates(t *testing.T) {
+	// Arrange
+	service := NewService(&MockRepositoryLocality{Province: domain.Province{ID: 3}})
+	latitude, longitude := 91.0, -66.337752
+
+	// Act
+	_, errMissing := service.Create(ctx, domain.Locality{ID: "5700", LocalityName: "Capital", ProvinceID: 3, Longitude: &longitude})
+	_, errRange := service.Create(ctx, domain.Locality{ID: "5700", LocalityName: "Capital", ProvinceID: 3, Latitude: &latitude, Longitude: &longitude})
+
+	// Assert
+	assert.EqualError(t, errMissing, ErrInvalidCoordinates.Error())
+	assert.EqualError(t, errRange, ErrInvalidCoordinates.Error())
+}
+
+// TestUpdateLocalityCoordinates_OK passes when the locality is returned with its new coordinates
+func TestUpdateLocalityCoordinates_OK(t *testing.T) {
+	// Arrange
+	mockRepo := MockRepositoryLocality{Locality: domain.Locality{ID: "5700", LocalityName: "Capital"}}
+	service := NewService(&mockRepo)
+	coordinates := domain.Coordinates{Latitude: -33.301726, Longitude: -66.337752}
+
+	// Act
+	result, err := service.UpdateCoordinates(ctx, "5700", coordinates)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, coordinates, *mockRepo.LastCoords)
+	assert.Equal(t, -33.301726, *result.Latitude)
+	assert.Equal(t, "Capital", result.LocalityName)
+}
+
+// TestUpdateLocalityCoordinates_Fail passes when the coordinates are out of range or the locality does not exist
+func TestUpdateLocalityCoordinates_Fail(t *testing.T) {
+	// Arrange
+	mockRepo := MockRepositoryLocality{ErrorMock: ErrNotFound}
+	service := NewService(&mockRepo)
+
+	// Act
+	_, errRange := service.UpdateCoordinates(ctx, "5700", domain.Coordinates{Latitude: 0, Longitude: 181})
+	_, errMissing := service.UpdateCoordinates(ctx, "5700", domain.Coordinates{Latitude: 0, Longitude: 0})
+
+	// Assert
+	assert.EqualError(t, errRange, ErrInvalidCoordinates.Error())
+	assert.EqualError(t, errMissing, ErrNotFound.Error())
+	assert.Nil(t, mockRepo.LastCoords)
+}
+
+// * ---------------------- Import --------------------------
+// TestImport_Report passes when invalid, repeated and rejected rows are reported in line order and the rest is imported
+func TestImport_Report(t *testing.T) {
+	// Arrange
+	file := strings.Join([]string{
+		"id,locality_name,province_id,province_name,country_name,latitude,longitude",
+		"5700, Capital ,3,,,,",
+		"5730,Villa Mercedes,,san luis,argentina,-33.67,-65.46",
+		",Sin Id,3,,,,",
+		"5700,Repetida,3,,,,",
+		"1900,La Plata,,Buenos Aires,,,",
+		"1000,Existente,3,,,,",
+		"1001,Lejana,3,,,91,0",
+		"1002,Mala,abc,,,,",
+	}, "\n")
+	rows, err := NewImportReader(ImportCSV, strings.NewReader(file))
+	assert.NoError(t, err)
+	mockRepo := MockRepositoryLocality{RejectIDs: map[string]error{"1000": ErrAlreadyExists}}
+	service := NewService(&mockRepo)
+
+	// Act
+	report, err := service.Import(ctx, rows, false)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 8, report.Rows)
+	assert.Equal(t, 2, report.Valid)
+	assert.Equal(t, 2, report.Imported)
+	assert.Equal(t, 6, report.Failed)
+	assert.Equal(t, []domain.LocalityImportError{
+		{Line: 4, Error: ErrMissingFields.Error()},
+		{Line: 5, ID: "5700", Error: "id already in line 2"},
+		{Line: 6, ID: "1900", Error: ErrMissingProvince.Error()},
+		{Line: 7, ID: "1000", Error: ErrAlreadyExists.Error()},
+		{Line: 8, ID: "1001", Error: ErrInvalidCoordinates.Error()},
+		{Line: 9, Error: "invalid province_id"},
+	}, report.Errors)
+	assert.Len(t, mockRepo.Batches, 1)
+	assert.Equal(t, "Capital", mockRepo.Batches[0][0].Locality.LocalityName)
+	assert.Equal(t, "san luis", mockRepo.Batches[0][1].Locality.ProvinceName)
+}
+
+// TestImport_DryRunBatches passes when the rows are sent in batches and a dry run imports nothing
+func TestImport_DryRunBatches(t *testing.T) {
+	// Arrange
+	var file strings.Builder
+	for i := 0; i < ImportBatchSize+1; i++ {
+		fmt.Fprintf(&file, "{\"id\": \"%d\", \"locality_name\": \"L%d\", \"province_id\": 3}\n", i, i)
+	}
+	rows, _ := NewImportReader(ImportNDJSON, strings.NewReader(file.String()))
+	mockRepo := MockRepositoryLocality{}
+	service := NewService(&mockRepo)
+
+	// Act
+	report, err := service.Import(ctx, rows, true)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, mockRepo.LastDryRun)
+	assert.Len(t, mockRepo.Batches, 2)
+	assert.Len(t, mockRepo.Batches[0], ImportBatchSize)
+	assert.Equal(t, ImportBatchSize+1, report.Valid)
+	assert.Zero(t, report.Imported)
+	assert.Empty(t, report.Errors)
+}
+
+// TestImport_FailRepository passes when a batch can not be stored and the import stops
+func TestImport_FailRepository(t *testing.T) {
+	// Arrange
+	rows, _ := NewImportReader(ImportNDJSON, strings.NewReader(`{"id": "5700", "locality_name": "Capital", "province_id": 3}`))
+	service := NewService(&MockRepositoryLocality{ErrorMock: ErrInternal})
+
+	// Act
+	report, err := service.Import(ctx, rows, false)
+
+	// Assert
+	assert.EqualError(t, err, ErrInternal.Error())
+	assert.Zero(t, report.Imported)
+}
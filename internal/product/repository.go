@@ -8,6 +8,7 @@ import (
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
 	"github.com/go-sql-driver/mysql"
 	"log"
+	"strings"
 )
 
 var (
@@ -15,6 +16,7 @@ var (
 	RepositoryErrInternal             = errors.New("database internal error")
 	RepositoryErrForeignKeyConstraint = errors.New("a foreign key constraint fails")
 	RepositoryErrAlreadyExists        = errors.New("product code already exists")
+	RepositoryErrProductTypeNotFound  = errors.New("product type not found in database")
 )
 
 const (
@@ -24,6 +26,7 @@ const (
 	UpdateProduct                   = "UPDATE products SET description = ?, expiration_rate = ?, freezing_rate = ?, height = ?, lenght = ?, netweight = ?, product_code = ?, recommended_freezing_temperature = ?, width = ?, id_product_type = ?, id_seller = ? WHERE id = ?"
 	DeleteProduct                   = "DELETE FROM products WHERE id = ?"
 	ExistsProduct                   = "SELECT product_code FROM products WHERE product_code = ?;"
+	GetProductType                  = "SELECT id, name, minimum_temperature, maximum_temperature FROM product_types WHERE id = ?;"
 	MySqlNumberForeignKeyConstraint = 1452
 	MySqlNumberDuplicateEntry       = 1062
 )
@@ -36,6 +39,8 @@ type Repository interface {
 	Save(ctx context.Context, p domain.Product) (int, error)
 	Update(ctx context.Context, p domain.Product) error
 	Delete(ctx context.Context, id int) error
+	// GetProductType returns the product type with the temperature range new products of the type default to
+	GetProductType(ctx context.Context, id int) (domain.ProductType, error)
 }
 
 type repository struct {
@@ -92,6 +97,22 @@ func (r *repository) Get(ctx context.Context, id int) (domain.Product, error) {
 	return p, nil
 }
 
+func (r *repository) GetProductType(ctx context.Context, id int) (domain.ProductType, error) {
+	row := r.db.QueryRowContext(ctx, GetProductType, id)
+	pt := domain.ProductType{}
+	errScan := row.Scan(&pt.ID, &pt.Name, &pt.MinimumTemperature, &pt.MaximumTemperature)
+	if errScan != nil {
+		logging.Log(errScan)
+		switch errScan {
+		case sql.ErrNoRows:
+			return domain.ProductType{}, RepositoryErrProductTypeNotFound
+		default:
+			return domain.ProductType{}, RepositoryErrInternal
+		}
+	}
+	return pt, nil
+}
+
 func (r *repository) Save(ctx context.Context, p domain.Product) (int, error) {
 	stmt, errPrepare := r.db.PrepareContext(ctx, SaveProduct)
 	if errPrepare != nil {
@@ -106,7 +127,7 @@ func (r *repository) Save(ctx context.Context, p domain.Product) (int, error) {
 		if ok {
 			switch message.Number {
 			case MySqlNumberForeignKeyConstraint:
-				return 0, RepositoryErrForeignKeyConstraint
+				return 0, foreignKeyError(message)
 			case MySqlNumberDuplicateEntry:
 				// This is in case we implement unique with product_code (not happening on Sprint III)
 				return 0, RepositoryErrAlreadyExists
@@ -136,7 +157,7 @@ func (r *repository) Update(ctx context.Context, p domain.Product) error {
 		if ok {
 			switch message.Number {
 			case MySqlNumberForeignKeyConstraint:
-				return RepositoryErrForeignKeyConstraint
+				return foreignKeyError(message)
 			case MySqlNumberDuplicateEntry:
 				// This is in case we implement unique with product_code (not happening on Sprint III)
 				return RepositoryErrAlreadyExists
@@ -175,3 +196,11 @@ func (r *repository) Delete(ctx context.Context, id int) error {
 	}
 	return nil
 }
+
+// foreignKeyError tells a missing product type from a missing seller, the only other foreign key of a product
+func foreignKeyError(message *mysql.MySQLError) error {
+	if strings.Contains(message.Message, "id_product_type") {
+		return RepositoryErrProductTypeNotFound
+	}
+	return RepositoryErrForeignKeyConstraint
+}
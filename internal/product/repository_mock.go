@@ -20,6 +20,7 @@ type RepositoryMock struct {
 	FlagUpdate      bool
 	FlagDelete      bool
 	ExpectedID      int
+	ProductType     domain.ProductType
 }
 
 // GetAll returns only weird SQL errors
@@ -65,6 +66,14 @@ func (repository *RepositoryMock) Save(_ context.Context, product domain.Product
 	return
 }
 
+// GetProductType returns RepositoryErrProductTypeNotFound for every product type but ProductType
+func (repository *RepositoryMock) GetProductType(_ context.Context, id int) (domain.ProductType, error) {
+	if repository.ProductType.ID != id {
+		return domain.ProductType{}, RepositoryErrProductTypeNotFound
+	}
+	return repository.ProductType, nil
+}
+
 // Update returns only weird SQL errors
 func (repository *RepositoryMock) Update(_ context.Context, p domain.Product) error {
 	repository.FlagUpdate = true
@@ -8,10 +8,12 @@ import (
 )
 
 var (
-	ServiceErrNotFound           = errors.New("product not found")
-	ServiceErrInternal           = errors.New("internal error")
-	ServiceErrAlreadyExists      = errors.New("product code already exists")
-	ServiceErrForeignKeyNotFound = errors.New("seller not found")
+	ServiceErrNotFound            = errors.New("product not found")
+	ServiceErrInternal            = errors.New("internal error")
+	ServiceErrAlreadyExists       = errors.New("product code already exists")
+	ServiceErrForeignKeyNotFound  = errors.New("seller not found")
+	ServiceErrProductTypeNotFound = errors.New("product type not found")
+	ServiceErrNoTemperatureRange  = errors.New("the product type has no temperature range, recommended_freezing_temperature is required")
 )
 
 type Service interface {
@@ -62,17 +64,28 @@ func (s *service) Get(ctx *gin.Context, id int) (domain.Product, error) {
 // Save stores the given values in a new Product in te database.
 // sellerID is optional and productCode should be unique.
 // After storing, Save retrieves the new Product from the database and returns it.
+// A recommendedFreezingTemperature at or below -273 was not given, it defaults to the maximum temperature of the product type.
 // If there is any error it is returned to the controller layer to be handled.
 func (s *service) Save(ctx *gin.Context, product domain.Product) (domain.Product, error) {
 	if s.productRepository.Exists(ctx, product.ProductCode) {
 		return domain.Product{}, ServiceErrAlreadyExists
 	}
+	if product.RecommendedFreezingTemperature <= -273 {
+		temperature, errDefault := s.defaultTemperature(ctx, product.ProductTypeID)
+		if errDefault != nil {
+			logging.Log(errDefault)
+			return domain.Product{}, errDefault
+		}
+		product.RecommendedFreezingTemperature = temperature
+	}
 	prodID, errSave := s.productRepository.Save(ctx, product)
 	if errSave != nil {
 		logging.Log(errSave)
 		switch errSave {
 		case RepositoryErrForeignKeyConstraint:
 			return domain.Product{}, ServiceErrForeignKeyNotFound
+		case RepositoryErrProductTypeNotFound:
+			return domain.Product{}, ServiceErrProductTypeNotFound
 		case RepositoryErrAlreadyExists:
 			// This is in case we implement unique with product_code (not happening on Sprint III)
 			return domain.Product{}, ServiceErrAlreadyExists
@@ -83,6 +96,24 @@ func (s *service) Save(ctx *gin.Context, product domain.Product) (domain.Product
 	return s.Get(ctx, prodID)
 }
 
+// defaultTemperature returns the maximum temperature of the range of the product type, the warmest its products can be kept at
+func (s *service) defaultTemperature(ctx *gin.Context, productTypeID int) (float32, error) {
+	productType, errGet := s.productRepository.GetProductType(ctx, productTypeID)
+	if errGet != nil {
+		logging.Log(errGet)
+		switch errGet {
+		case RepositoryErrProductTypeNotFound:
+			return 0, ServiceErrProductTypeNotFound
+		default:
+			return 0, ServiceErrInternal
+		}
+	}
+	if !productType.HasTemperatureRange() {
+		return 0, ServiceErrNoTemperatureRange
+	}
+	return *productType.MaximumTemperature, nil
+}
+
 // PartialUpdate retrieves a Product from database and checks for values != nil to update.
 // productCode should be unique.
 // After updating, PartialUpdate retrieves the updated Product from the database and returns it.
@@ -141,6 +172,8 @@ func (s *service) PartialUpdate(ctx *gin.Context, id int, product domain.Product
 		switch errUpdate {
 		case RepositoryErrForeignKeyConstraint:
 			return domain.Product{}, ServiceErrForeignKeyNotFound
+		case RepositoryErrProductTypeNotFound:
+			return domain.Product{}, ServiceErrProductTypeNotFound
 		case RepositoryErrAlreadyExists:
 			// This is in case we implement unique with product_code (not happening on Sprint III)
 			return domain.Product{}, ServiceErrAlreadyExists
@@ -131,6 +131,41 @@ func TestService_Save_FailForeignKeyNotFound(t *testing.T) {
 	assert.Empty(t, result)
 }
 
+// TestService_Save_DefaultTemperature passes when a product without recommended freezing temperature takes the maximum of its product type
+func TestService_Save_DefaultTemperature(t *testing.T) {
+	// Arrange
+	minimum, maximum := float32(-30), float32(-18)
+	save := domain.Product{ProductCode: "frozen-peas", RecommendedFreezingTemperature: -273, ProductTypeID: 1}
+
+	// Act
+	ctx := setupProductServiceTest()
+	mockProductRepository := RepositoryMock{db: []domain.Product{}, ExpectedID: 1, ProductType: domain.ProductType{ID: 1, Name: "frozen", MinimumTemperature: &minimum, MaximumTemperature: &maximum}}
+	productService := NewService(&mockProductRepository)
+	result, err := productService.Save(ctx, save)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, float32(-18), result.RecommendedFreezingTemperature)
+}
+
+// TestService_Save_FailNoTemperatureRange passes when a product without recommended freezing temperature has a product type without range
+// (return empty domain.Product and error ServiceErrNoTemperatureRange)
+func TestService_Save_FailNoTemperatureRange(t *testing.T) {
+	// Arrange
+	save := domain.Product{ProductCode: "frozen-peas", RecommendedFreezingTemperature: -273, ProductTypeID: 1}
+
+	// Act
+	ctx := setupProductServiceTest()
+	mockProductRepository := RepositoryMock{db: []domain.Product{}, ProductType: domain.ProductType{ID: 1, Name: "product type 1"}}
+	productService := NewService(&mockProductRepository)
+	result, err := productService.Save(ctx, save)
+
+	// Assert
+	assert.False(t, mockProductRepository.FlagSave)
+	assert.EqualError(t, err, ServiceErrNoTemperatureRange.Error())
+	assert.Empty(t, result)
+}
+
 // TestService_GetAll_OK passes when there are no errors on repository layer (return slice of all domain.Product and nil error)
 func TestService_GetAll_OK(t *testing.T) {
 	// Arrange
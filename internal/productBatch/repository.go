@@ -20,11 +20,31 @@ var (
 	ErrForeignNotFoundCode    = 1452
 	ErrForeignProductNotFound = errors.New("the given id does not have a product atached to it")
 	ErrForeignSectionNotFound = errors.New("the given id does not have a section atached to it")
+	ErrReferencedCode         = 1451
+	ErrReferenced             = errors.New("the product batch has inbound orders, temperature excursions or stock reservations atached to it")
 	ErrInternal               = errors.New("database internal error")
+	ErrInsufficientStock      = errors.New("there is not enough stock of the product to pick the requested quantity")
+	ErrCapacityExceeded       = errors.New("the section does not have enough capacity to store the product batch")
+	ErrTemperature            = errors.New("the temperature of the section is not compatible with the product")
+	ErrProductType            = errors.New("the product type of the product is not the product type of the section")
+	ErrBelowReserved          = errors.New("the current quantity cannot be lower than the quantity reserved for purchase orders")
 )
 
 const (
-	SaveProductBatch = "INSERT INTO product_batches (batch_number,current_quantity,current_temperature,due_date,initial_quantity,manufacturing_date,manufacturing_hour,minimum_temperature,product_id,section_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);"
+	GetAllProductBatches   = "SELECT id, batch_number, current_quantity, current_temperature, DATE_FORMAT(due_date, '%Y-%m-%d'), initial_quantity, DATE_FORMAT(manufacturing_date, '%Y-%m-%d'), manufacturing_hour, minimum_temperature, product_id, section_id, quarantined, reserved_quantity, current_quantity - reserved_quantity FROM product_batches"
+	GetProductBatch        = GetAllProductBatches + " WHERE id = ?;"
+	UpdateProductBatch     = "UPDATE product_batches SET batch_number=?, current_quantity=?, current_temperature=?, due_date=?, initial_quantity=?, manufacturing_date=?, manufacturing_hour=?, minimum_temperature=?, product_id=?, section_id=? WHERE id=?;"
+	DeleteProductBatch     = "DELETE FROM product_batches WHERE id=?;"
+	SaveProductBatch       = "INSERT INTO product_batches (batch_number,current_quantity,current_temperature,due_date,initial_quantity,manufacturing_date,manufacturing_hour,minimum_temperature,product_id,section_id,quarantined) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);"
+	LockProductBatch       = "SELECT section_id, current_quantity FROM product_batches WHERE id = ? FOR UPDATE;"
+	LockSectionCapacity    = "SELECT current_capacity, maximum_capacity FROM sections WHERE id = ? FOR UPDATE;"
+	UpdateSectionCapacity  = "UPDATE sections SET current_capacity = GREATEST(current_capacity + ?, 0) WHERE id = ?;"
+	PickableProductBatches = "SELECT id, batch_number, current_quantity - reserved_quantity, DATE_FORMAT(due_date, '%Y-%m-%d'), section_id FROM product_batches WHERE product_id = ? AND current_quantity > reserved_quantity AND due_date >= CURDATE() AND NOT quarantined ORDER BY due_date, id FOR UPDATE;"
+	DecrementProductBatch  = "UPDATE product_batches SET current_quantity = current_quantity - ? WHERE id = ?;"
+	GetBatchTemperature    = `SELECT s.current_temperature, s.minimum_temperature, w.temperature_policy, p.recommended_freezing_temperature, s.id_product_type, IFNULL(p.id_product_type, 0) FROM sections AS s
+							INNER JOIN warehouses AS w ON w.id = s.warehouse_id
+							LEFT JOIN products AS p ON p.id = ?
+							WHERE s.id = ?;`
 )
 
 func init() {
@@ -33,7 +53,14 @@ func init() {
 
 // Repository encapsulates the storage of a section.
 type Repository interface {
+	GetAll(ctx context.Context, filter domain.ProductBatchFilter) ([]domain.ProductBatch, error)
+	Get(ctx context.Context, id int) (domain.ProductBatch, error)
 	Save(ctx context.Context, pb domain.ProductBatch) (int, error)
+	Update(ctx context.Context, pb domain.ProductBatch) error
+	Delete(ctx context.Context, id int) error
+	Pick(ctx context.Context, productID int, quantity int) ([]domain.BatchAllocation, error)
+	GetBatchTemperature(ctx context.Context, productID int, sectionID int) (domain.BatchTemperature, error)
+	SaveTx(ctx context.Context, tx *sql.Tx, pb domain.ProductBatch) (domain.ProductBatch, error)
 }
 
 type repository struct {
@@ -46,44 +73,399 @@ func NewRepository(db *sql.DB) Repository {
 	}
 }
 
-func (r *repository) Save(ctx context.Context, pb domain.ProductBatch) (int, error) {
+func (r *repository) GetAll(ctx context.Context, filter domain.ProductBatchFilter) ([]domain.ProductBatch, error) {
+	query, args := buildGetAllQuery(filter)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+
+	var productBatches []domain.ProductBatch
+
+	for rows.Next() {
+		pb := domain.ProductBatch{}
+		if err := rows.Scan(&pb.ID, &pb.BatchNumber, &pb.CurrentQuantity, &pb.CurrentTemperature, &pb.DueDate, &pb.InitialQuantity, &pb.ManufacturingDate, &pb.ManufacturingHour, &pb.MinimumTemperature, &pb.ProductID, &pb.SectionID, &pb.Quarantined, &pb.ReservedQuantity, &pb.AvailableQuantity); err != nil {
+			logging.Log(err)
+			return nil, ErrInternal
+		}
+		productBatches = append(productBatches, pb)
+	}
+
+	return productBatches, nil
+}
 
-	stmt, err := r.db.Prepare(SaveProductBatch)
+func (r *repository) Get(ctx context.Context, id int) (domain.ProductBatch, error) {
+	row := r.db.QueryRow(GetProductBatch, id)
+	pb := domain.ProductBatch{}
+	err := row.Scan(&pb.ID, &pb.BatchNumber, &pb.CurrentQuantity, &pb.CurrentTemperature, &pb.DueDate, &pb.InitialQuantity, &pb.ManufacturingDate, &pb.ManufacturingHour, &pb.MinimumTemperature, &pb.ProductID, &pb.SectionID, &pb.Quarantined, &pb.ReservedQuantity, &pb.AvailableQuantity)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			logging.Log(err)
+			return domain.ProductBatch{}, ErrNotFound
+		default:
+			logging.Log(err)
+			return domain.ProductBatch{}, ErrInternal
+		}
+	}
+
+	return pb, nil
+}
+
+// GetBatchTemperature returns the temperature needs and product type of the product next to the temperatures
+// and product type of the section and the temperature policy of its warehouse
+func (r *repository) GetBatchTemperature(ctx context.Context, productID int, sectionID int) (domain.BatchTemperature, error) {
+	return scanBatchTemperature(r.db.QueryRowContext(ctx, GetBatchTemperature, productID, sectionID), productID, sectionID)
+}
+
+// scanBatchTemperature reads the row of GetBatchTemperature, so it can be queried inside or outside a transaction
+func scanBatchTemperature(row *sql.Row, productID int, sectionID int) (domain.BatchTemperature, error) {
+	bt := domain.BatchTemperature{ProductID: productID, SectionID: sectionID}
+	var recommended sql.NullFloat64
+	err := row.Scan(&bt.SectionCurrentTemperature, &bt.SectionMinimumTemperature, &bt.TemperaturePolicy, &recommended, &bt.SectionProductTypeID, &bt.ProductTypeID)
+	if err != nil {
+		logging.Log(err)
+		if err == sql.ErrNoRows {
+			return domain.BatchTemperature{}, ErrForeignSectionNotFound
+		}
+		return domain.BatchTemperature{}, ErrInternal
+	}
+	if !recommended.Valid {
+		logging.Log(ErrForeignProductNotFound)
+		return domain.BatchTemperature{}, ErrForeignProductNotFound
+	}
+	bt.RecommendedFreezingTemperature = float32(recommended.Float64)
+	return bt, nil
+}
+
+// Save stores the product batch and adds its current quantity to the capacity of its section inside a single transaction
+func (r *repository) Save(ctx context.Context, pb domain.ProductBatch) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		logging.Log(err)
 		return 0, ErrInternal
 	}
 
-	res, err := stmt.Exec(&pb.BatchNumber, &pb.CurrentQuantity, &pb.CurrentTemperature, &pb.DueDate, &pb.InitialQuantity, &pb.ManufacturingDate, &pb.ManufacturingHour, &pb.MinimumTemperature, &pb.ProductID, &pb.SectionID)
+	if err := reserveSectionCapacity(ctx, tx, pb.SectionID, pb.CurrentQuantity); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	id, err := insertProductBatch(ctx, tx, pb)
 	if err != nil {
-		message, ok := err.(*mysql.MySQLError)
-		if ok {
-			switch int(message.Number) {
-			case ErrDateValueCode:
-				logging.Log(err)
-				return 0, ErrDateValue
-			case ErrForeignNotFoundCode:
-				if strings.Contains(message.Message, "product_id") {
-					logging.Log(err)
-					return 0, ErrForeignProductNotFound
-				} else {
-					logging.Log(err)
-					return 0, ErrForeignSectionNotFound
-				}
-			case ErrAlreadyExistsCode:
-				logging.Log(err)
-				return 0, ErrAlreadyExists
-			}
-		}
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
 		logging.Log(err)
 		return 0, ErrInternal
 	}
 
+	return id, nil
+}
+
+// SaveTx stores the product batch inside a transaction owned by the caller, which commits or rolls it back.
+// the section is locked before its temperature and product type are checked against the product, so the product batch
+// comes back with the warnings of the check and the id it was stored with
+func (r *repository) SaveTx(ctx context.Context, tx *sql.Tx, pb domain.ProductBatch) (domain.ProductBatch, error) {
+	if err := reserveSectionCapacity(ctx, tx, pb.SectionID, pb.CurrentQuantity); err != nil {
+		return domain.ProductBatch{}, err
+	}
+
+	bt, err := scanBatchTemperature(tx.QueryRowContext(ctx, GetBatchTemperature, pb.ProductID, pb.SectionID), pb.ProductID, pb.SectionID)
+	if err != nil {
+		return domain.ProductBatch{}, err
+	}
+
+	pb, err = compareTemperature(bt, pb)
+	if err != nil {
+		logging.Log(err)
+		return domain.ProductBatch{}, err
+	}
+
+	id, err := insertProductBatch(ctx, tx, pb)
+	if err != nil {
+		return domain.ProductBatch{}, err
+	}
+
+	pb.ID = id
+	return pb, nil
+}
+
+// insertProductBatch stores the product batch, the capacity of its section is left to the caller
+func insertProductBatch(ctx context.Context, tx *sql.Tx, pb domain.ProductBatch) (int, error) {
+	res, err := tx.ExecContext(ctx, SaveProductBatch, &pb.BatchNumber, &pb.CurrentQuantity, &pb.CurrentTemperature, &pb.DueDate, &pb.InitialQuantity, &pb.ManufacturingDate, &pb.ManufacturingHour, &pb.MinimumTemperature, &pb.ProductID, &pb.SectionID, &pb.Quarantined)
+	if err != nil {
+		logging.Log(err)
+		return 0, parseWriteError(err)
+	}
+
 	id, err := res.LastInsertId()
 	if err != nil {
 		logging.Log(err)
 		return 0, ErrInternal
 	}
-
 	return int(id), nil
 }
+
+// Update stores the product batch and moves its quantity between the capacities of the previous and the new section
+// inside a single transaction
+func (r *repository) Update(ctx context.Context, pb domain.ProductBatch) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Log(err)
+		return ErrInternal
+	}
+
+	previous, err := lockProductBatch(ctx, tx, pb.ID)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if previous.SectionID == pb.SectionID {
+		err = reserveSectionCapacity(ctx, tx, pb.SectionID, pb.CurrentQuantity-previous.CurrentQuantity)
+	} else {
+		err = reserveSectionCapacity(ctx, tx, previous.SectionID, -previous.CurrentQuantity)
+		if err == nil {
+			err = reserveSectionCapacity(ctx, tx, pb.SectionID, pb.CurrentQuantity)
+		}
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, UpdateProductBatch, &pb.BatchNumber, &pb.CurrentQuantity, &pb.CurrentTemperature, &pb.DueDate, &pb.InitialQuantity, &pb.ManufacturingDate, &pb.ManufacturingHour, &pb.MinimumTemperature, &pb.ProductID, &pb.SectionID, &pb.ID)
+	if err != nil {
+		logging.Log(err)
+		_ = tx.Rollback()
+		return parseWriteError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.Log(err)
+		return ErrInternal
+	}
+
+	return nil
+}
+
+// Delete removes the product batch and releases its current quantity from the capacity of its section
+// inside a single transaction
+func (r *repository) Delete(ctx context.Context, id int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Log(err)
+		return ErrInternal
+	}
+
+	previous, err := lockProductBatch(ctx, tx, id)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, DeleteProductBatch, id)
+	if err != nil {
+		_ = tx.Rollback()
+		message, ok := err.(*mysql.MySQLError)
+		if ok && int(message.Number) == ErrReferencedCode {
+			logging.Log(err)
+			return ErrReferenced
+		}
+		logging.Log(err)
+		return ErrInternal
+	}
+
+	if err := reserveSectionCapacity(ctx, tx, previous.SectionID, -previous.CurrentQuantity); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.Log(err)
+		return ErrInternal
+	}
+
+	return nil
+}
+
+// Pick locks the non expired batches of the product, allocates the quantity that is not reserved in first-expired-first-out order
+// and decrements the current quantity of every allocated batch and its section inside a single transaction
+func (r *repository) Pick(ctx context.Context, productID int, quantity int) ([]domain.BatchAllocation, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+
+	rows, err := tx.QueryContext(ctx, PickableProductBatches, productID)
+	if err != nil {
+		logging.Log(err)
+		_ = tx.Rollback()
+		return nil, ErrInternal
+	}
+
+	var batches []domain.ProductBatch
+	for rows.Next() {
+		pb := domain.ProductBatch{ProductID: productID}
+		if err := rows.Scan(&pb.ID, &pb.BatchNumber, &pb.CurrentQuantity, &pb.DueDate, &pb.SectionID); err != nil {
+			logging.Log(err)
+			_ = rows.Close()
+			_ = tx.Rollback()
+			return nil, ErrInternal
+		}
+		batches = append(batches, pb)
+	}
+	_ = rows.Close()
+
+	allocations, err := allocateFEFO(batches, quantity)
+	if err != nil {
+		logging.Log(err)
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	for _, allocation := range allocations {
+		if _, err := tx.ExecContext(ctx, DecrementProductBatch, allocation.Quantity, allocation.ProductBatchID); err != nil {
+			logging.Log(err)
+			_ = tx.Rollback()
+			return nil, ErrInternal
+		}
+		if err := reserveSectionCapacity(ctx, tx, allocation.SectionID, -allocation.Quantity); err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+
+	return allocations, nil
+}
+
+// allocateFEFO takes the quantity from the given batches in the order they are received,
+// which must already be sorted by due date
+func allocateFEFO(batches []domain.ProductBatch, quantity int) ([]domain.BatchAllocation, error) {
+	var allocations []domain.BatchAllocation
+	remaining := quantity
+	for _, pb := range batches {
+		if remaining == 0 {
+			break
+		}
+		taken := pb.CurrentQuantity
+		if taken > remaining {
+			taken = remaining
+		}
+		allocations = append(allocations, domain.BatchAllocation{ProductBatchID: pb.ID, BatchNumber: pb.BatchNumber, DueDate: pb.DueDate, SectionID: pb.SectionID, Quantity: taken})
+		remaining -= taken
+	}
+	if remaining > 0 {
+		return nil, ErrInsufficientStock
+	}
+	return allocations, nil
+}
+
+// lockProductBatch returns the section and current quantity of the product batch, locking its row until the transaction ends
+func lockProductBatch(ctx context.Context, tx *sql.Tx, id int) (domain.ProductBatch, error) {
+	pb := domain.ProductBatch{ID: id}
+	err := tx.QueryRowContext(ctx, LockProductBatch, id).Scan(&pb.SectionID, &pb.CurrentQuantity)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			logging.Log(err)
+			return domain.ProductBatch{}, ErrNotFound
+		default:
+			logging.Log(err)
+			return domain.ProductBatch{}, ErrInternal
+		}
+	}
+	return pb, nil
+}
+
+// reserveSectionCapacity locks the section and adds quantity to its current capacity, a negative quantity releases capacity.
+// if the section would exceed its maximum capacity, an error is returned
+func reserveSectionCapacity(ctx context.Context, tx *sql.Tx, sectionID int, quantity int) error {
+	var currentCapacity, maximumCapacity int
+	err := tx.QueryRowContext(ctx, LockSectionCapacity, sectionID).Scan(&currentCapacity, &maximumCapacity)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			logging.Log(err)
+			return ErrForeignSectionNotFound
+		default:
+			logging.Log(err)
+			return ErrInternal
+		}
+	}
+
+	if quantity > 0 && currentCapacity+quantity > maximumCapacity {
+		logging.Log(ErrCapacityExceeded)
+		return ErrCapacityExceeded
+	}
+	if quantity == 0 {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, UpdateSectionCapacity, quantity, sectionID); err != nil {
+		logging.Log(err)
+		return ErrInternal
+	}
+	return nil
+}
+
+// buildGetAllQuery appends to GetAllProductBatches a condition for every filter that is set
+func buildGetAllQuery(filter domain.ProductBatchFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if filter.ProductID != 0 {
+		conditions = append(conditions, "product_id = ?")
+		args = append(args, filter.ProductID)
+	}
+	if filter.SectionID != 0 {
+		conditions = append(conditions, "section_id = ?")
+		args = append(args, filter.SectionID)
+	}
+	if filter.DueFrom != "" {
+		conditions = append(conditions, "due_date >= ?")
+		args = append(args, filter.DueFrom)
+	}
+	if filter.DueTo != "" {
+		conditions = append(conditions, "due_date <= ?")
+		args = append(args, filter.DueTo)
+	}
+	if filter.ExpiredOnly {
+		conditions = append(conditions, "due_date < CURDATE()")
+	}
+
+	query := GetAllProductBatches
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	return query + " ORDER BY due_date, id;", args
+}
+
+// parseWriteError translates the mysql errors of an insert or update into the errors of the package
+func parseWriteError(err error) error {
+	message, ok := err.(*mysql.MySQLError)
+	if ok {
+		switch int(message.Number) {
+		case ErrDateValueCode:
+			return ErrDateValue
+		case ErrForeignNotFoundCode:
+			if strings.Contains(message.Message, "product_id") {
+				return ErrForeignProductNotFound
+			}
+			return ErrForeignSectionNotFound
+		case ErrAlreadyExistsCode:
+			return ErrAlreadyExists
+		}
+	}
+	return ErrInternal
+}
@@ -2,13 +2,31 @@ package productbatch
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 )
 
 type MockRepository struct {
 	mockProductBatches []domain.ProductBatch
+	mockAllocations    []domain.BatchAllocation
+	mockTemperature    domain.BatchTemperature
 	mockError          error
+	mockGetError       error
+}
+
+func (r *MockRepository) GetAll(ctx context.Context, filter domain.ProductBatchFilter) ([]domain.ProductBatch, error) {
+	if r.mockError != nil {
+		return nil, r.mockError
+	}
+	return r.mockProductBatches, nil
+}
+
+func (r *MockRepository) Get(ctx context.Context, id int) (domain.ProductBatch, error) {
+	if r.mockGetError != nil {
+		return domain.ProductBatch{}, r.mockGetError
+	}
+	return r.mockProductBatches[0], nil
 }
 
 func (r *MockRepository) Save(ctx context.Context, pb domain.ProductBatch) (int, error) {
@@ -20,6 +38,49 @@ func (r *MockRepository) Save(ctx context.Context, pb domain.ProductBatch) (int,
 	r.mockProductBatches = append(r.mockProductBatches, pb)
 	return pb.ID, nil
 }
+func (r *MockRepository) Update(ctx context.Context, pb domain.ProductBatch) error {
+	if r.mockError != nil {
+		return r.mockError
+	}
+	r.mockProductBatches[0] = pb
+	return nil
+}
+
+func (r *MockRepository) Delete(ctx context.Context, id int) error {
+	if r.mockError != nil {
+		return r.mockError
+	}
+	r.mockProductBatches = r.mockProductBatches[1:]
+	return nil
+}
+
 func (r *MockRepository) Exists(ctx context.Context, cid int) bool {
 	return r.mockError == ErrAlreadyExists
 }
+
+func (r *MockRepository) Pick(ctx context.Context, productID int, quantity int) ([]domain.BatchAllocation, error) {
+	if r.mockError != nil {
+		return nil, r.mockError
+	}
+	return r.mockAllocations, nil
+}
+
+func (r *MockRepository) GetBatchTemperature(ctx context.Context, productID int, sectionID int) (domain.BatchTemperature, error) {
+	if r.mockGetError != nil {
+		return domain.BatchTemperature{}, r.mockGetError
+	}
+	return r.mockTemperature, nil
+}
+
+func (r *MockRepository) SaveTx(ctx context.Context, tx *sql.Tx, pb domain.ProductBatch) (domain.ProductBatch, error) {
+	pb, err := compareTemperature(r.mockTemperature, pb)
+	if err != nil {
+		return domain.ProductBatch{}, err
+	}
+	id, err := r.Save(ctx, pb)
+	if err != nil {
+		return domain.ProductBatch{}, err
+	}
+	pb.ID = id
+	return pb, nil
+}
@@ -26,42 +26,27 @@ var productBatch_test = domain.ProductBatch{
 	SectionID:          1,
 }
 
+// expectSectionCapacity expects the lock of the section and, if quantity is not zero, the update of its capacity
+func expectSectionCapacity(mock sqlmock.Sqlmock, sectionID int, currentCapacity int, maximumCapacity int, quantity int) {
+	rows := sqlmock.NewRows([]string{"current_capacity", "maximum_capacity"}).AddRow(currentCapacity, maximumCapacity)
+	mock.ExpectQuery(regexp.QuoteMeta(LockSectionCapacity)).WithArgs(sectionID).WillReturnRows(rows)
+	if quantity != 0 {
+		mock.ExpectExec(regexp.QuoteMeta(UpdateSectionCapacity)).WithArgs(quantity, sectionID).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+}
+
 func TestCreate_Ok(t *testing.T) {
 	// ARRANGE
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectPrepare(regexp.QuoteMeta(SaveProductBatch))
-	mock.ExpectExec(regexp.QuoteMeta(SaveProductBatch)).WillReturnResult(sqlmock.NewResult(1, 1))
-
-	columns := []string{
-		"id",
-		"batch_number",
-		"current_quantity",
-		"current_temperature",
-		"due_date",
-		"initial_quantity",
-		"manufacturing_date",
-		"manufacturing_hour",
-		"minimum_temperature",
-		"product_id",
-		"section_id",
-	}
-	rows := sqlmock.NewRows(columns)
-	rows.AddRow(
-		productBatch_test.ID,
-		productBatch_test.BatchNumber,
-		productBatch_test.CurrentQuantity,
-		productBatch_test.CurrentTemperature,
-		productBatch_test.DueDate,
-		productBatch_test.InitialQuantity,
-		productBatch_test.ManufacturingDate,
-		productBatch_test.ManufacturingHour,
-		productBatch_test.MinimumTemperature,
-		productBatch_test.ProductID,
-		productBatch_test.SectionID,
-	)
+	mock.ExpectBegin()
+	expectSectionCapacity(mock, productBatch_test.SectionID, 0, 10, productBatch_test.CurrentQuantity)
+	mock.ExpectExec(regexp.QuoteMeta(SaveProductBatch)).
+		WithArgs(productBatch_test.BatchNumber, productBatch_test.CurrentQuantity, productBatch_test.CurrentTemperature, productBatch_test.DueDate, productBatch_test.InitialQuantity, productBatch_test.ManufacturingDate, productBatch_test.ManufacturingHour, productBatch_test.MinimumTemperature, productBatch_test.ProductID, productBatch_test.SectionID, productBatch_test.Quarantined).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
 	// ACT
 	repo := NewRepository(db)
@@ -74,6 +59,29 @@ func TestCreate_Ok(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestCreate_CapacityExceeded(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	expected := ErrCapacityExceeded
+
+	mock.ExpectBegin()
+	expectSectionCapacity(mock, productBatch_test.SectionID, 10, 10, 0)
+	mock.ExpectRollback()
+
+	// ACT
+	repo := NewRepository(db)
+
+	id, err := repo.Save(context.TODO(), productBatch_test)
+
+	// ASSERT
+	assert.Empty(t, id)
+	assert.EqualError(t, err, expected.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestCreate_Conflict(t *testing.T) {
 	// ARRANGE
 	db, mock, err := sqlmock.New()
@@ -82,8 +90,10 @@ func TestCreate_Conflict(t *testing.T) {
 
 	expected := ErrAlreadyExists
 
-	mock.ExpectPrepare(regexp.QuoteMeta(SaveProductBatch))
+	mock.ExpectBegin()
+	expectSectionCapacity(mock, productBatch_test.SectionID, 0, 10, productBatch_test.CurrentQuantity)
 	mock.ExpectExec(regexp.QuoteMeta(SaveProductBatch)).WillReturnError(&mysql.MySQLError{Number: uint16(ErrAlreadyExistsCode)})
+	mock.ExpectRollback()
 
 	// ACT
 	repo := NewRepository(db)
@@ -104,8 +114,10 @@ func TestCreate_MissingForeignProductId(t *testing.T) {
 
 	expected := ErrForeignProductNotFound
 
-	mock.ExpectPrepare(regexp.QuoteMeta(SaveProductBatch))
+	mock.ExpectBegin()
+	expectSectionCapacity(mock, productBatch_test.SectionID, 0, 10, productBatch_test.CurrentQuantity)
 	mock.ExpectExec(regexp.QuoteMeta(SaveProductBatch)).WillReturnError(&mysql.MySQLError{Number: uint16(ErrForeignNotFoundCode), Message: "product_id"})
+	mock.ExpectRollback()
 
 	// ACT
 	repo := NewRepository(db)
@@ -126,8 +138,9 @@ func TestCreate_MissingForeignSectionId(t *testing.T) {
 
 	expected := ErrForeignSectionNotFound
 
-	mock.ExpectPrepare(regexp.QuoteMeta(SaveProductBatch))
-	mock.ExpectExec(regexp.QuoteMeta(SaveProductBatch)).WillReturnError(&mysql.MySQLError{Number: uint16(ErrForeignNotFoundCode), Message: "section_id"})
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LockSectionCapacity)).WithArgs(productBatch_test.SectionID).WillReturnRows(sqlmock.NewRows([]string{"current_capacity", "maximum_capacity"}))
+	mock.ExpectRollback()
 
 	// ACT
 	repo := NewRepository(db)
@@ -148,8 +161,10 @@ func TestCreate_InvalidDate(t *testing.T) {
 
 	expected := ErrDateValue
 
-	mock.ExpectPrepare(regexp.QuoteMeta(SaveProductBatch))
+	mock.ExpectBegin()
+	expectSectionCapacity(mock, productBatch_test.SectionID, 0, 10, productBatch_test.CurrentQuantity)
 	mock.ExpectExec(regexp.QuoteMeta(SaveProductBatch)).WillReturnError(&mysql.MySQLError{Number: uint16(ErrDateValueCode)})
+	mock.ExpectRollback()
 
 	// ACT
 	repo := NewRepository(db)
@@ -170,8 +185,10 @@ func TestCreate_InternalError(t *testing.T) {
 
 	expected := ErrInternal
 
-	mock.ExpectPrepare(regexp.QuoteMeta(SaveProductBatch))
+	mock.ExpectBegin()
+	expectSectionCapacity(mock, productBatch_test.SectionID, 0, 10, productBatch_test.CurrentQuantity)
 	mock.ExpectExec(regexp.QuoteMeta(SaveProductBatch)).WillReturnError(&mysql.MySQLError{})
+	mock.ExpectRollback()
 
 	// ACT
 	repo := NewRepository(db)
@@ -192,8 +209,10 @@ func TestCreate_LastIdError(t *testing.T) {
 
 	expected := ErrInternal
 
-	mock.ExpectPrepare(regexp.QuoteMeta(SaveProductBatch))
+	mock.ExpectBegin()
+	expectSectionCapacity(mock, productBatch_test.SectionID, 0, 10, productBatch_test.CurrentQuantity)
 	mock.ExpectExec(regexp.QuoteMeta(SaveProductBatch)).WillReturnResult(sqlmock.NewErrorResult(ErrInternal))
+	mock.ExpectRollback()
 
 	// ACT
 	repo := NewRepository(db)
@@ -206,7 +225,7 @@ func TestCreate_LastIdError(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestCreate_PrepareErr(t *testing.T) {
+func TestCreate_BeginErr(t *testing.T) {
 	// ARRANGE
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -223,4 +242,507 @@ func TestCreate_PrepareErr(t *testing.T) {
 	assert.Empty(t, id)
 	assert.EqualError(t, err, expected.Error())
 	assert.NoError(t, mock.ExpectationsWereMet())
-}
\ No newline at end of file
+}
+
+func TestPick_Ok(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "batch_number", "current_quantity", "due_date", "section_id"}).
+		AddRow(3, 30, 5, "2030-01-01", 1).
+		AddRow(1, 10, 10, "2030-02-01", 2).
+		AddRow(2, 20, 10, "2030-03-01", 1)
+
+	expected := []domain.BatchAllocation{
+		{ProductBatchID: 3, BatchNumber: 30, DueDate: "2030-01-01", SectionID: 1, Quantity: 5},
+		{ProductBatchID: 1, BatchNumber: 10, DueDate: "2030-02-01", SectionID: 2, Quantity: 7},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(PickableProductBatches)).WithArgs(2).WillReturnRows(rows)
+	mock.ExpectExec(regexp.QuoteMeta(DecrementProductBatch)).WithArgs(5, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectSectionCapacity(mock, 1, 15, 20, -5)
+	mock.ExpectExec(regexp.QuoteMeta(DecrementProductBatch)).WithArgs(7, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectSectionCapacity(mock, 2, 10, 20, -7)
+	mock.ExpectCommit()
+
+	// ACT
+	repo := NewRepository(db)
+
+	allocations, err := repo.Pick(context.TODO(), 2, 12)
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, expected, allocations)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPick_InsufficientStock(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "batch_number", "current_quantity", "due_date", "section_id"}).
+		AddRow(1, 10, 10, "2030-02-01", 1)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(PickableProductBatches)).WithArgs(2).WillReturnRows(rows)
+	mock.ExpectRollback()
+
+	// ACT
+	repo := NewRepository(db)
+
+	allocations, err := repo.Pick(context.TODO(), 2, 12)
+
+	// ASSERT
+	assert.Empty(t, allocations)
+	assert.EqualError(t, err, ErrInsufficientStock.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPick_DecrementError(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "batch_number", "current_quantity", "due_date", "section_id"}).
+		AddRow(1, 10, 10, "2030-02-01", 1)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(PickableProductBatches)).WithArgs(2).WillReturnRows(rows)
+	mock.ExpectExec(regexp.QuoteMeta(DecrementProductBatch)).WithArgs(4, 1).WillReturnError(&mysql.MySQLError{})
+	mock.ExpectRollback()
+
+	// ACT
+	repo := NewRepository(db)
+
+	allocations, err := repo.Pick(context.TODO(), 2, 4)
+
+	// ASSERT
+	assert.Empty(t, allocations)
+	assert.EqualError(t, err, ErrInternal.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPick_BeginError(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin().WillReturnError(ErrInternal)
+
+	// ACT
+	repo := NewRepository(db)
+
+	allocations, err := repo.Pick(context.TODO(), 2, 4)
+
+	// ASSERT
+	assert.Empty(t, allocations)
+	assert.EqualError(t, err, ErrInternal.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+var productBatchColumns = []string{
+	"id",
+	"batch_number",
+	"current_quantity",
+	"current_temperature",
+	"due_date",
+	"initial_quantity",
+	"manufacturing_date",
+	"manufacturing_hour",
+	"minimum_temperature",
+	"product_id",
+	"section_id",
+	"quarantined",
+	"reserved_quantity",
+	"available_quantity",
+}
+
+func productBatchRow(rows *sqlmock.Rows, pb domain.ProductBatch) *sqlmock.Rows {
+	return rows.AddRow(pb.ID, pb.BatchNumber, pb.CurrentQuantity, pb.CurrentTemperature, pb.DueDate, pb.InitialQuantity, pb.ManufacturingDate, pb.ManufacturingHour, pb.MinimumTemperature, pb.ProductID, pb.SectionID, pb.Quarantined, pb.ReservedQuantity, pb.AvailableQuantity)
+}
+
+func TestGetAll_Ok(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := productBatchRow(sqlmock.NewRows(productBatchColumns), productBatch_test)
+	query := GetAllProductBatches + " ORDER BY due_date, id;"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(rows)
+
+	// ACT
+	repo := NewRepository(db)
+
+	result, err := repo.GetAll(context.TODO(), domain.ProductBatchFilter{})
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.ProductBatch{productBatch_test}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAll_Filtered(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := productBatchRow(sqlmock.NewRows(productBatchColumns), productBatch_test)
+	query := GetAllProductBatches + " WHERE product_id = ? AND section_id = ? AND due_date >= ? AND due_date <= ? AND due_date < CURDATE() ORDER BY due_date, id;"
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2, 1, "1999-01-01", "1999-12-31").WillReturnRows(rows)
+
+	// ACT
+	repo := NewRepository(db)
+
+	result, err := repo.GetAll(context.TODO(), domain.ProductBatchFilter{ProductID: 2, SectionID: 1, DueFrom: "1999-01-01", DueTo: "1999-12-31", ExpiredOnly: true})
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.ProductBatch{productBatch_test}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAll_InternalError(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(GetAllProductBatches)).WillReturnError(&mysql.MySQLError{})
+
+	// ACT
+	repo := NewRepository(db)
+
+	result, err := repo.GetAll(context.TODO(), domain.ProductBatchFilter{})
+
+	// ASSERT
+	assert.Empty(t, result)
+	assert.EqualError(t, err, ErrInternal.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGet_Ok(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := productBatchRow(sqlmock.NewRows(productBatchColumns), productBatch_test)
+	mock.ExpectQuery(regexp.QuoteMeta(GetProductBatch)).WithArgs(1).WillReturnRows(rows)
+
+	// ACT
+	repo := NewRepository(db)
+
+	result, err := repo.Get(context.TODO(), 1)
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, productBatch_test, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGet_NotFound(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(GetProductBatch)).WithArgs(1).WillReturnRows(sqlmock.NewRows(productBatchColumns))
+
+	// ACT
+	repo := NewRepository(db)
+
+	result, err := repo.Get(context.TODO(), 1)
+
+	// ASSERT
+	assert.Empty(t, result)
+	assert.EqualError(t, err, ErrNotFound.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdate_Ok(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	previous := sqlmock.NewRows([]string{"section_id", "current_quantity"}).AddRow(productBatch_test.SectionID, 4)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LockProductBatch)).WithArgs(productBatch_test.ID).WillReturnRows(previous)
+	expectSectionCapacity(mock, productBatch_test.SectionID, 4, 10, productBatch_test.CurrentQuantity-4)
+	mock.ExpectExec(regexp.QuoteMeta(UpdateProductBatch)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// ACT
+	repo := NewRepository(db)
+
+	err = repo.Update(context.TODO(), productBatch_test)
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdate_MoveSection(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	previous := sqlmock.NewRows([]string{"section_id", "current_quantity"}).AddRow(3, productBatch_test.CurrentQuantity)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LockProductBatch)).WithArgs(productBatch_test.ID).WillReturnRows(previous)
+	expectSectionCapacity(mock, 3, 5, 10, -productBatch_test.CurrentQuantity)
+	expectSectionCapacity(mock, productBatch_test.SectionID, 0, 10, productBatch_test.CurrentQuantity)
+	mock.ExpectExec(regexp.QuoteMeta(UpdateProductBatch)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// ACT
+	repo := NewRepository(db)
+
+	err = repo.Update(context.TODO(), productBatch_test)
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdate_CapacityExceeded(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	previous := sqlmock.NewRows([]string{"section_id", "current_quantity"}).AddRow(3, productBatch_test.CurrentQuantity)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LockProductBatch)).WithArgs(productBatch_test.ID).WillReturnRows(previous)
+	expectSectionCapacity(mock, 3, 5, 10, -productBatch_test.CurrentQuantity)
+	expectSectionCapacity(mock, productBatch_test.SectionID, 10, 10, 0)
+	mock.ExpectRollback()
+
+	// ACT
+	repo := NewRepository(db)
+
+	err = repo.Update(context.TODO(), productBatch_test)
+
+	// ASSERT
+	assert.EqualError(t, err, ErrCapacityExceeded.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdate_Conflict(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	previous := sqlmock.NewRows([]string{"section_id", "current_quantity"}).AddRow(productBatch_test.SectionID, productBatch_test.CurrentQuantity)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LockProductBatch)).WithArgs(productBatch_test.ID).WillReturnRows(previous)
+	expectSectionCapacity(mock, productBatch_test.SectionID, 5, 10, 0)
+	mock.ExpectExec(regexp.QuoteMeta(UpdateProductBatch)).WillReturnError(&mysql.MySQLError{Number: uint16(ErrAlreadyExistsCode)})
+	mock.ExpectRollback()
+
+	// ACT
+	repo := NewRepository(db)
+
+	err = repo.Update(context.TODO(), productBatch_test)
+
+	// ASSERT
+	assert.EqualError(t, err, ErrAlreadyExists.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDelete_Ok(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	previous := sqlmock.NewRows([]string{"section_id", "current_quantity"}).AddRow(2, 6)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LockProductBatch)).WithArgs(1).WillReturnRows(previous)
+	mock.ExpectExec(regexp.QuoteMeta(DeleteProductBatch)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectSectionCapacity(mock, 2, 6, 10, -6)
+	mock.ExpectCommit()
+
+	// ACT
+	repo := NewRepository(db)
+
+	err = repo.Delete(context.TODO(), 1)
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDelete_NotFound(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LockProductBatch)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"section_id", "current_quantity"}))
+	mock.ExpectRollback()
+
+	// ACT
+	repo := NewRepository(db)
+
+	err = repo.Delete(context.TODO(), 1)
+
+	// ASSERT
+	assert.EqualError(t, err, ErrNotFound.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDelete_Referenced(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	previous := sqlmock.NewRows([]string{"section_id", "current_quantity"}).AddRow(2, 6)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LockProductBatch)).WithArgs(1).WillReturnRows(previous)
+	mock.ExpectExec(regexp.QuoteMeta(DeleteProductBatch)).WithArgs(1).WillReturnError(&mysql.MySQLError{Number: uint16(ErrReferencedCode)})
+	mock.ExpectRollback()
+
+	// ACT
+	repo := NewRepository(db)
+
+	err = repo.Delete(context.TODO(), 1)
+
+	// ASSERT
+	assert.EqualError(t, err, ErrReferenced.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBatchTemperature_Ok(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"current_temperature", "minimum_temperature", "temperature_policy", "recommended_freezing_temperature", "id_product_type", "product_type"}).AddRow(-20, -25, "warn", -18.5, 1, 1)
+	mock.ExpectQuery(regexp.QuoteMeta(GetBatchTemperature)).WithArgs(2, 3).WillReturnRows(rows)
+
+	expected := domain.BatchTemperature{ProductID: 2, SectionID: 3, RecommendedFreezingTemperature: -18.5, SectionCurrentTemperature: -20, SectionMinimumTemperature: -25, TemperaturePolicy: "warn", ProductTypeID: 1, SectionProductTypeID: 1}
+
+	// ACT
+	repo := NewRepository(db)
+
+	result, err := repo.GetBatchTemperature(context.TODO(), 2, 3)
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+	assert.True(t, result.Compatible())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBatchTemperature_SectionNotFound(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"current_temperature", "minimum_temperature", "temperature_policy", "recommended_freezing_temperature"})
+	mock.ExpectQuery(regexp.QuoteMeta(GetBatchTemperature)).WithArgs(2, 3).WillReturnRows(rows)
+
+	// ACT
+	repo := NewRepository(db)
+
+	result, err := repo.GetBatchTemperature(context.TODO(), 2, 3)
+
+	// ASSERT
+	assert.Empty(t, result)
+	assert.EqualError(t, err, ErrForeignSectionNotFound.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBatchTemperature_ProductNotFound(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"current_temperature", "minimum_temperature", "temperature_policy", "recommended_freezing_temperature", "id_product_type", "product_type"}).AddRow(-20, -25, "reject", nil, 1, 0)
+	mock.ExpectQuery(regexp.QuoteMeta(GetBatchTemperature)).WithArgs(2, 3).WillReturnRows(rows)
+
+	// ACT
+	repo := NewRepository(db)
+
+	result, err := repo.GetBatchTemperature(context.TODO(), 2, 3)
+
+	// ASSERT
+	assert.Empty(t, result)
+	assert.EqualError(t, err, ErrForeignProductNotFound.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveTx_Warning(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	expectSectionCapacity(mock, productBatch_test.SectionID, 0, 10, productBatch_test.CurrentQuantity)
+	rows := sqlmock.NewRows([]string{"current_temperature", "minimum_temperature", "temperature_policy", "recommended_freezing_temperature", "id_product_type", "product_type"}).AddRow(4, 0, "warn", -2, 1, 1)
+	mock.ExpectQuery(regexp.QuoteMeta(GetBatchTemperature)).WithArgs(productBatch_test.ProductID, productBatch_test.SectionID).WillReturnRows(rows)
+	mock.ExpectExec(regexp.QuoteMeta(SaveProductBatch)).
+		WithArgs(productBatch_test.BatchNumber, productBatch_test.CurrentQuantity, productBatch_test.CurrentTemperature, productBatch_test.DueDate, productBatch_test.InitialQuantity, productBatch_test.ManufacturingDate, productBatch_test.ManufacturingHour, productBatch_test.MinimumTemperature, productBatch_test.ProductID, productBatch_test.SectionID, false).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+
+	// ACT
+	repo := NewRepository(db)
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+
+	result, err := repo.SaveTx(context.TODO(), tx, productBatch_test)
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, 7, result.ID)
+	assert.Len(t, result.Warnings, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveTx_Temperature(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	expectSectionCapacity(mock, productBatch_test.SectionID, 0, 10, productBatch_test.CurrentQuantity)
+	rows := sqlmock.NewRows([]string{"current_temperature", "minimum_temperature", "temperature_policy", "recommended_freezing_temperature", "id_product_type", "product_type"}).AddRow(4, 0, "reject", -2, 1, 1)
+	mock.ExpectQuery(regexp.QuoteMeta(GetBatchTemperature)).WithArgs(productBatch_test.ProductID, productBatch_test.SectionID).WillReturnRows(rows)
+
+	// ACT
+	repo := NewRepository(db)
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+
+	result, err := repo.SaveTx(context.TODO(), tx, productBatch_test)
+
+	// ASSERT
+	assert.Empty(t, result)
+	assert.EqualError(t, err, ErrTemperature.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,13 +2,33 @@ package productbatch
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
 )
 
+var (
+	ErrInvalidQuantity = errors.New("the quantity to pick must be greater than zero")
+	ErrQuantityExceeds = errors.New("the current quantity cannot be greater than the initial quantity")
+)
+
 type Service interface {
+	// GetAll returns the product batches that match the given filter, ordered by due date
+	GetAll(c context.Context, filter domain.ProductBatchFilter) ([]domain.ProductBatch, error)
+	// Get returns the product batch with the specified ID in the repository, if it exists
+	Get(c context.Context, id int) (domain.ProductBatch, error)
+	// Create saves the product batch if its section is of the product type of the product and the temperature
+	// of the section fits the product, or the warehouse only warns about it
 	Create(c context.Context, pb domain.ProductBatch) (domain.ProductBatch, error)
+	// Update updates the product batch with the specified data in the repository, if it exists
+	Update(c context.Context, pb domain.ProductBatch) (domain.ProductBatch, error)
+	// Delete deletes the product batch with the specified ID from the repository
+	Delete(c context.Context, id int) error
+	// Pick takes the given quantity of a product from its batches, the ones closest to expire first
+	Pick(c context.Context, productID int, quantity int) (domain.ProductBatchPick, error)
 }
 
 type service struct {
@@ -21,12 +41,151 @@ func NewService(r Repository) Service {
 	}
 }
 
+// GetAll returns an error if any of the due dates of the filter is not a valid yyyy-mm-dd date
+func (s *service) GetAll(c context.Context, filter domain.ProductBatchFilter) ([]domain.ProductBatch, error) {
+	for _, date := range []string{filter.DueFrom, filter.DueTo} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse(domain.ISO8601, date); err != nil {
+			logging.Log(err)
+			return nil, ErrDateValue
+		}
+	}
+	return s.repository.GetAll(c, filter)
+}
+
+func (s *service) Get(c context.Context, id int) (domain.ProductBatch, error) {
+	return s.repository.Get(c, id)
+}
+
 func (s *service) Create(c context.Context, pb domain.ProductBatch) (domain.ProductBatch, error) {
+	pb, err := s.checkTemperature(c, pb)
+	if err != nil {
+		logging.Log(err)
+		return domain.ProductBatch{}, err
+	}
 	id, err := s.repository.Save(c, pb)
 	if err != nil {
 		logging.Log(err)
 		return domain.ProductBatch{}, err
 	}
 	pb.ID = id
+	pb.AvailableQuantity = pb.CurrentQuantity
+	return pb, nil
+}
+
+// Update returns the updated product batch if successful, or a error if it failed
+// if a product batch with the given id doesn`t exist, an error is returned
+// only the values not in a null state are updated, temperatures use -273 and current quantity -1 as null
+// if the product or the section changes, the temperature of the section is checked against the product again
+func (s *service) Update(c context.Context, newProductBatch domain.ProductBatch) (domain.ProductBatch, error) {
+	pb, err := s.repository.Get(c, newProductBatch.ID)
+	if err != nil {
+		logging.Log(err)
+		return domain.ProductBatch{}, err
+	}
+	if newProductBatch.BatchNumber != 0 {
+		pb.BatchNumber = newProductBatch.BatchNumber
+	}
+	if newProductBatch.CurrentQuantity > -1 {
+		pb.CurrentQuantity = newProductBatch.CurrentQuantity
+	}
+	if newProductBatch.CurrentTemperature > -273 {
+		pb.CurrentTemperature = newProductBatch.CurrentTemperature
+	}
+	if newProductBatch.DueDate != "" {
+		pb.DueDate = newProductBatch.DueDate
+	}
+	if newProductBatch.InitialQuantity != 0 {
+		pb.InitialQuantity = newProductBatch.InitialQuantity
+	}
+	if newProductBatch.ManufacturingDate != "" {
+		pb.ManufacturingDate = newProductBatch.ManufacturingDate
+	}
+	if newProductBatch.ManufacturingHour != 0 {
+		pb.ManufacturingHour = newProductBatch.ManufacturingHour
+	}
+	if newProductBatch.MinimumTemperature > -273 {
+		pb.MinimumTemperature = newProductBatch.MinimumTemperature
+	}
+	if newProductBatch.ProductID != 0 {
+		pb.ProductID = newProductBatch.ProductID
+	}
+	if newProductBatch.SectionID != 0 {
+		pb.SectionID = newProductBatch.SectionID
+	}
+	if pb.CurrentQuantity > pb.InitialQuantity {
+		logging.Log(ErrQuantityExceeds)
+		return domain.ProductBatch{}, ErrQuantityExceeds
+	}
+	if pb.CurrentQuantity < pb.ReservedQuantity {
+		logging.Log(ErrBelowReserved)
+		return domain.ProductBatch{}, ErrBelowReserved
+	}
+	pb.AvailableQuantity = pb.CurrentQuantity - pb.ReservedQuantity
+	if newProductBatch.ProductID != 0 || newProductBatch.SectionID != 0 {
+		pb, err = s.checkTemperature(c, pb)
+		if err != nil {
+			logging.Log(err)
+			return domain.ProductBatch{}, err
+		}
+	}
+	err = s.repository.Update(c, pb)
+	if err != nil {
+		logging.Log(err)
+		return domain.ProductBatch{}, err
+	}
+	return pb, nil
+}
+
+// Delete returns an error if the deletion of the product batch failed
+// if a product batch with the given id doesn`t exist, an error is returned
+func (s *service) Delete(c context.Context, id int) error {
+	err := s.repository.Delete(c, id)
+	if err != nil {
+		logging.Log(err)
+	}
+	return err
+}
+
+func (s *service) Pick(c context.Context, productID int, quantity int) (domain.ProductBatchPick, error) {
+	if quantity <= 0 {
+		logging.Log(ErrInvalidQuantity)
+		return domain.ProductBatchPick{}, ErrInvalidQuantity
+	}
+	allocations, err := s.repository.Pick(c, productID, quantity)
+	if err != nil {
+		logging.Log(err)
+		return domain.ProductBatchPick{}, err
+	}
+	return domain.ProductBatchPick{ProductID: productID, Quantity: quantity, Allocations: allocations}, nil
+}
+
+// checkTemperature compares the temperature of the section with the needs of the product of the batch,
+// when they are not compatible the batch is rejected, or only warned about if its warehouse allows it.
+// A section of another product type always rejects the batch
+func (s *service) checkTemperature(c context.Context, pb domain.ProductBatch) (domain.ProductBatch, error) {
+	bt, err := s.repository.GetBatchTemperature(c, pb.ProductID, pb.SectionID)
+	if err != nil {
+		return domain.ProductBatch{}, err
+	}
+	return compareTemperature(bt, pb)
+}
+
+// compareTemperature returns an error if the product cannot be stored in the section, or the product batch with a warning
+// when the temperature is not compatible but the warehouse of the section only warns about it
+func compareTemperature(bt domain.BatchTemperature, pb domain.ProductBatch) (domain.ProductBatch, error) {
+	if bt.ProductTypeID != bt.SectionProductTypeID {
+		return domain.ProductBatch{}, ErrProductType
+	}
+	if bt.Compatible() {
+		return pb, nil
+	}
+	if bt.TemperaturePolicy != domain.TemperaturePolicyWarn {
+		return domain.ProductBatch{}, ErrTemperature
+	}
+	pb.Warnings = append(pb.Warnings, fmt.Sprintf("the section %d is at %d degrees (minimum %d) but the product %d must be kept at %.1f degrees or less",
+		bt.SectionID, bt.SectionCurrentTemperature, bt.SectionMinimumTemperature, bt.ProductID, bt.RecommendedFreezingTemperature))
 	return pb, nil
 }
@@ -8,9 +8,24 @@ import (
 
 type MockService struct {
 	MockProductBatches []domain.ProductBatch
+	MockAllocations    []domain.BatchAllocation
 	MockError          error
 }
 
+func (s *MockService) GetAll(c context.Context, filter domain.ProductBatchFilter) ([]domain.ProductBatch, error) {
+	if s.MockError != nil {
+		return nil, s.MockError
+	}
+	return s.MockProductBatches, nil
+}
+
+func (s *MockService) Get(c context.Context, id int) (domain.ProductBatch, error) {
+	if s.MockError != nil {
+		return domain.ProductBatch{}, s.MockError
+	}
+	return s.MockProductBatches[0], nil
+}
+
 func (s *MockService) Create(c context.Context, pb domain.ProductBatch) (domain.ProductBatch, error) {
 	if s.MockError != nil {
 		return domain.ProductBatch{}, s.MockError
@@ -20,6 +35,43 @@ func (s *MockService) Create(c context.Context, pb domain.ProductBatch) (domain.
 	s.MockProductBatches = append(s.MockProductBatches, pb)
 	return s.MockProductBatches[id-1], nil
 }
+func (s *MockService) Update(c context.Context, pb domain.ProductBatch) (domain.ProductBatch, error) {
+	if s.MockError != nil {
+		return domain.ProductBatch{}, s.MockError
+	}
+	if pb.BatchNumber != 0 {
+		s.MockProductBatches[0].BatchNumber = pb.BatchNumber
+	}
+	if pb.CurrentQuantity > -1 {
+		s.MockProductBatches[0].CurrentQuantity = pb.CurrentQuantity
+	}
+	if pb.CurrentTemperature > -273 {
+		s.MockProductBatches[0].CurrentTemperature = pb.CurrentTemperature
+	}
+	if pb.MinimumTemperature > -273 {
+		s.MockProductBatches[0].MinimumTemperature = pb.MinimumTemperature
+	}
+	if pb.SectionID != 0 {
+		s.MockProductBatches[0].SectionID = pb.SectionID
+	}
+	return s.MockProductBatches[0], nil
+}
+
+func (s *MockService) Delete(c context.Context, id int) error {
+	if s.MockError != nil {
+		return s.MockError
+	}
+	s.MockProductBatches = s.MockProductBatches[1:]
+	return nil
+}
+
 func (s *MockService) Exists(c context.Context, productBatchNumber int) error {
 	return nil
 }
+
+func (s *MockService) Pick(c context.Context, productID int, quantity int) (domain.ProductBatchPick, error) {
+	if s.MockError != nil {
+		return domain.ProductBatchPick{}, s.MockError
+	}
+	return domain.ProductBatchPick{ProductID: productID, Quantity: quantity, Allocations: s.MockAllocations}, nil
+}
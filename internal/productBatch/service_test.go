@@ -26,6 +26,7 @@ func TestCreateOk(t *testing.T) {
 		MinimumTemperature: 1,
 		ProductID:          1,
 		SectionID:          1,
+		AvailableQuantity:  1,
 	}
 
 	// ACT
@@ -94,3 +95,186 @@ func TestCreateMissingProductConflict(t *testing.T) {
 	assert.Empty(t, result)
 	assert.EqualError(t, expected, err.Error())
 }
+
+func TestPickOk(t *testing.T) {
+	// ARRANGE
+	allocations := []domain.BatchAllocation{
+		{ProductBatchID: 1, BatchNumber: 1, DueDate: "2030-01-01", Quantity: 5},
+		{ProductBatchID: 2, BatchNumber: 2, DueDate: "2030-02-01", Quantity: 3},
+	}
+	repository := MockRepository{mockAllocations: allocations}
+	service := NewService(&repository)
+	ctx := new(context.Context)
+
+	expected := domain.ProductBatchPick{ProductID: 1, Quantity: 8, Allocations: allocations}
+
+	// ACT
+	result, err := service.Pick(*ctx, 1, 8)
+
+	// ASSERT
+	assert.Nil(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestPickInvalidQuantity(t *testing.T) {
+	// ARRANGE
+	repository := MockRepository{}
+	service := NewService(&repository)
+	ctx := new(context.Context)
+
+	// ACT
+	result, err := service.Pick(*ctx, 1, -3)
+
+	// ASSERT
+	assert.Empty(t, result)
+	assert.EqualError(t, err, ErrInvalidQuantity.Error())
+}
+
+func TestPickInsufficientStock(t *testing.T) {
+	// ARRANGE
+	repository := MockRepository{mockError: ErrInsufficientStock}
+	service := NewService(&repository)
+	ctx := new(context.Context)
+
+	// ACT
+	result, err := service.Pick(*ctx, 1, 8)
+
+	// ASSERT
+	assert.Empty(t, result)
+	assert.EqualError(t, err, ErrInsufficientStock.Error())
+}
+
+func TestGetAllOk(t *testing.T) {
+	// ARRANGE
+	batches := []domain.ProductBatch{{ID: 1, BatchNumber: 1, DueDate: "1999-12-12", ProductID: 1, SectionID: 1}}
+	repository := MockRepository{mockProductBatches: batches}
+	service := NewService(&repository)
+	ctx := new(context.Context)
+
+	// ACT
+	result, err := service.GetAll(*ctx, domain.ProductBatchFilter{ProductID: 1, DueFrom: "1999-01-01", DueTo: "1999-12-31"})
+
+	// ASSERT
+	assert.Nil(t, err)
+	assert.Equal(t, batches, result)
+}
+
+func TestGetAllInvalidDate(t *testing.T) {
+	// ARRANGE
+	repository := MockRepository{}
+	service := NewService(&repository)
+	ctx := new(context.Context)
+
+	// ACT
+	result, err := service.GetAll(*ctx, domain.ProductBatchFilter{DueTo: "31/12/1999"})
+
+	// ASSERT
+	assert.Empty(t, result)
+	assert.EqualError(t, err, ErrDateValue.Error())
+}
+
+func TestUpdateOk(t *testing.T) {
+	// ARRANGE
+	repository := MockRepository{mockProductBatches: []domain.ProductBatch{{ID: 1, BatchNumber: 1, CurrentQuantity: 10, CurrentTemperature: 2, DueDate: "1999-12-12", InitialQuantity: 10, MinimumTemperature: -2, ProductID: 1, SectionID: 1}}}
+	service := NewService(&repository)
+	ctx := new(context.Context)
+
+	expected := domain.ProductBatch{ID: 1, BatchNumber: 1, CurrentQuantity: 0, CurrentTemperature: 0, DueDate: "1999-12-12", InitialQuantity: 10, MinimumTemperature: -2, ProductID: 1, SectionID: 2}
+
+	// ACT
+	result, err := service.Update(*ctx, domain.ProductBatch{ID: 1, CurrentQuantity: 0, CurrentTemperature: 0, MinimumTemperature: -273, SectionID: 2})
+
+	// ASSERT
+	assert.Nil(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestUpdateQuantityExceeds(t *testing.T) {
+	// ARRANGE
+	repository := MockRepository{mockProductBatches: []domain.ProductBatch{{ID: 1, CurrentQuantity: 10, InitialQuantity: 10}}}
+	service := NewService(&repository)
+	ctx := new(context.Context)
+
+	// ACT
+	result, err := service.Update(*ctx, domain.ProductBatch{ID: 1, CurrentQuantity: 11, CurrentTemperature: -273, MinimumTemperature: -273})
+
+	// ASSERT
+	assert.Empty(t, result)
+	assert.EqualError(t, err, ErrQuantityExceeds.Error())
+}
+
+func TestUpdateNonExistent(t *testing.T) {
+	// ARRANGE
+	repository := MockRepository{mockGetError: ErrNotFound}
+	service := NewService(&repository)
+	ctx := new(context.Context)
+
+	// ACT
+	result, err := service.Update(*ctx, domain.ProductBatch{ID: 1, CurrentQuantity: -1, CurrentTemperature: -273, MinimumTemperature: -273})
+
+	// ASSERT
+	assert.Empty(t, result)
+	assert.EqualError(t, err, ErrNotFound.Error())
+}
+
+func TestDeleteReferenced(t *testing.T) {
+	// ARRANGE
+	repository := MockRepository{mockError: ErrReferenced}
+	service := NewService(&repository)
+	ctx := new(context.Context)
+
+	// ACT
+	err := service.Delete(*ctx, 1)
+
+	// ASSERT
+	assert.EqualError(t, err, ErrReferenced.Error())
+}
+
+func TestCreateTemperatureRejected(t *testing.T) {
+	// ARRANGE
+	temperature := domain.BatchTemperature{ProductID: 1, SectionID: 1, RecommendedFreezingTemperature: -18, SectionCurrentTemperature: 4, SectionMinimumTemperature: 0, TemperaturePolicy: domain.TemperaturePolicyReject}
+	repository := MockRepository{mockProductBatches: []domain.ProductBatch{}, mockTemperature: temperature}
+	service := NewService(&repository)
+	ctx := new(context.Context)
+
+	// ACT
+	result, err := service.Create(*ctx, domain.ProductBatch{BatchNumber: 1, ProductID: 1, SectionID: 1})
+
+	// ASSERT
+	assert.Empty(t, result)
+	assert.EqualError(t, err, ErrTemperature.Error())
+	assert.Empty(t, repository.mockProductBatches)
+}
+
+func TestCreateProductTypeMismatch(t *testing.T) {
+	// ARRANGE
+	temperature := domain.BatchTemperature{ProductID: 1, SectionID: 1, RecommendedFreezingTemperature: -18, SectionCurrentTemperature: -20, SectionMinimumTemperature: -25, ProductTypeID: 1, SectionProductTypeID: 2}
+	repository := MockRepository{mockProductBatches: []domain.ProductBatch{}, mockTemperature: temperature}
+	service := NewService(&repository)
+	ctx := new(context.Context)
+
+	// ACT
+	result, err := service.Create(*ctx, domain.ProductBatch{BatchNumber: 1, ProductID: 1, SectionID: 1})
+
+	// ASSERT
+	assert.Empty(t, result)
+	assert.EqualError(t, err, ErrProductType.Error())
+	assert.Empty(t, repository.mockProductBatches)
+}
+
+func TestCreateTemperatureWarned(t *testing.T) {
+	// ARRANGE
+	temperature := domain.BatchTemperature{ProductID: 1, SectionID: 1, RecommendedFreezingTemperature: -18, SectionCurrentTemperature: 4, SectionMinimumTemperature: 0, TemperaturePolicy: domain.TemperaturePolicyWarn}
+	repository := MockRepository{mockProductBatches: []domain.ProductBatch{}, mockTemperature: temperature}
+	service := NewService(&repository)
+	ctx := new(context.Context)
+
+	// ACT
+	result, err := service.Create(*ctx, domain.ProductBatch{BatchNumber: 1, ProductID: 1, SectionID: 1})
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.ID)
+	assert.Len(t, result.Warnings, 1)
+	assert.Len(t, repository.mockProductBatches, 1)
+}
@@ -0,0 +1,135 @@
+package product_types
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/go-sql-driver/mysql"
+)
+
+// Errors
+var (
+	ErrNotFound      = errors.New("product type not found")
+	ErrAlreadyExists = errors.New("product type name already exists")
+	ErrInUse         = errors.New("product type has products or sections")
+	ErrBadRequest    = errors.New("product type name is required")
+	ErrInvalidRange  = errors.New("minimum_temperature can not be greater than maximum_temperature")
+	ErrPartialRange  = errors.New("minimum_temperature and maximum_temperature must be set together")
+	ErrInternal      = errors.New("database internal error")
+)
+
+// Repository encapsulates the storage of a ProductType.
+type Repository interface {
+	GetAll(ctx context.Context) ([]domain.ProductType, error)
+	Get(ctx context.Context, id int) (domain.ProductType, error)
+	Save(ctx context.Context, pt domain.ProductType) (int, error)
+	Update(ctx context.Context, pt domain.ProductType) error
+	Delete(ctx context.Context, id int) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+const (
+	GET_ALL_PRODUCT_TYPES = "SELECT id, name, minimum_temperature, maximum_temperature FROM product_types ORDER BY id;"
+	GET_PRODUCT_TYPE      = "SELECT id, name, minimum_temperature, maximum_temperature FROM product_types WHERE id=?;"
+	SAVE_PRODUCT_TYPE     = "INSERT INTO product_types (name, minimum_temperature, maximum_temperature) VALUES (?, ?, ?);"
+	UPDATE_PRODUCT_TYPE   = "UPDATE product_types SET name=?, minimum_temperature=?, maximum_temperature=? WHERE id=?;"
+	DELETE_PRODUCT_TYPE   = "DELETE FROM product_types WHERE id=?;"
+	MySqlNumberDuplicate  = 1062
+	MySqlNumberReferenced = 1451
+)
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) GetAll(ctx context.Context) ([]domain.ProductType, error) {
+	rows, err := r.db.QueryContext(ctx, GET_ALL_PRODUCT_TYPES)
+	if err != nil {
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+
+	productTypes := []domain.ProductType{}
+	for rows.Next() {
+		pt := domain.ProductType{}
+		if err := rows.Scan(&pt.ID, &pt.Name, &pt.MinimumTemperature, &pt.MaximumTemperature); err != nil {
+			return nil, ErrInternal
+		}
+		productTypes = append(productTypes, pt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, ErrInternal
+	}
+	return productTypes, nil
+}
+
+func (r *repository) Get(ctx context.Context, id int) (domain.ProductType, error) {
+	pt := domain.ProductType{}
+	err := r.db.QueryRowContext(ctx, GET_PRODUCT_TYPE, id).Scan(&pt.ID, &pt.Name, &pt.MinimumTemperature, &pt.MaximumTemperature)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return domain.ProductType{}, ErrNotFound
+		default:
+			return domain.ProductType{}, ErrInternal
+		}
+	}
+	return pt, nil
+}
+
+func (r *repository) Save(ctx context.Context, pt domain.ProductType) (int, error) {
+	res, err := r.db.ExecContext(ctx, SAVE_PRODUCT_TYPE, pt.Name, pt.MinimumTemperature, pt.MaximumTemperature)
+	if err != nil {
+		return 0, parseError(err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, ErrInternal
+	}
+	return int(id), nil
+}
+
+func (r *repository) Update(ctx context.Context, pt domain.ProductType) error {
+	if _, err := r.db.ExecContext(ctx, UPDATE_PRODUCT_TYPE, pt.Name, pt.MinimumTemperature, pt.MaximumTemperature, pt.ID); err != nil {
+		return parseError(err)
+	}
+	return nil
+}
+
+func (r *repository) Delete(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, DELETE_PRODUCT_TYPE, id)
+	if err != nil {
+		return parseError(err)
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return ErrInternal
+	}
+	if affect < 1 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// parseError translates the mysql errors of the product_types table, names are unique without case
+// and a product type can not be deleted while products or sections use it
+func parseError(err error) error {
+	if mysqlError, ok := err.(*mysql.MySQLError); ok {
+		switch mysqlError.Number {
+		case MySqlNumberDuplicate:
+			return ErrAlreadyExists
+		case MySqlNumberReferenced:
+			return ErrInUse
+		}
+	}
+	return ErrInternal
+}
@@ -0,0 +1,76 @@
+package product_types
+
+import (
+	"context"
+	"strings"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+)
+
+type MockRepository struct {
+	Data      []domain.ProductType
+	ErrorMock error
+	// InUse lists the ids of the product types used by products or sections
+	InUse []int
+}
+
+func (m *MockRepository) GetAll(ctx context.Context) ([]domain.ProductType, error) {
+	if m.ErrorMock != nil {
+		return nil, m.ErrorMock
+	}
+	return m.Data, nil
+}
+
+func (m *MockRepository) Get(ctx context.Context, id int) (domain.ProductType, error) {
+	for _, pt := range m.Data {
+		if pt.ID == id {
+			return pt, nil
+		}
+	}
+	return domain.ProductType{}, ErrNotFound
+}
+
+func (m *MockRepository) Save(ctx context.Context, pt domain.ProductType) (int, error) {
+	if m.ErrorMock != nil {
+		return 0, m.ErrorMock
+	}
+	for _, existing := range m.Data {
+		if strings.EqualFold(existing.Name, pt.Name) {
+			return 0, ErrAlreadyExists
+		}
+	}
+	pt.ID = len(m.Data) + 1
+	m.Data = append(m.Data, pt)
+	return pt.ID, nil
+}
+
+func (m *MockRepository) Update(ctx context.Context, pt domain.ProductType) error {
+	if m.ErrorMock != nil {
+		return m.ErrorMock
+	}
+	for i := range m.Data {
+		if m.Data[i].ID == pt.ID {
+			m.Data[i] = pt
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id int) error {
+	if m.ErrorMock != nil {
+		return m.ErrorMock
+	}
+	for _, used := range m.InUse {
+		if used == id {
+			return ErrInUse
+		}
+	}
+	for i := range m.Data {
+		if m.Data[i].ID == id {
+			m.Data = append(m.Data[:i], m.Data[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
@@ -0,0 +1,106 @@
+package product_types
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetAll_OK passes when every product type is returned
+func TestGetAll_OK(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "name", "minimum_temperature", "maximum_temperature"}).
+		AddRow(1, "frozen", -30, -18).AddRow(2, "refrigerated", 0, 8).AddRow(3, "product type 3", nil, nil)
+	mock.ExpectQuery(regexp.QuoteMeta(GET_ALL_PRODUCT_TYPES)).WillReturnRows(rows)
+
+	// Act
+	result, err := NewRepository(db).GetAll(context.TODO())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.ProductType{
+		{ID: 1, Name: "frozen", MinimumTemperature: temperature(-30), MaximumTemperature: temperature(-18)},
+		{ID: 2, Name: "refrigerated", MinimumTemperature: temperature(0), MaximumTemperature: temperature(8)},
+		{ID: 3, Name: "product type 3"},
+	}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestGet_FailNotFound passes when the product type does not exist
+func TestGet_FailNotFound(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(GET_PRODUCT_TYPE)).WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "minimum_temperature", "maximum_temperature"}))
+
+	// Act
+	_, err = NewRepository(db).Get(context.TODO(), 7)
+
+	// Assert
+	assert.EqualError(t, err, ErrNotFound.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestSave_FailAlreadyExists passes when the name exists with any case
+func TestSave_FailAlreadyExists(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(SAVE_PRODUCT_TYPE)).WithArgs("frozen", float32(-30), float32(-18)).
+		WillReturnError(&mysql.MySQLError{Number: MySqlNumberDuplicate})
+
+	// Act
+	_, err = NewRepository(db).Save(context.TODO(), domain.ProductType{Name: "frozen", MinimumTemperature: temperature(-30), MaximumTemperature: temperature(-18)})
+
+	// Assert
+	assert.EqualError(t, err, ErrAlreadyExists.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestDelete_FailInUse passes when products or sections reference the product type
+func TestDelete_FailInUse(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(DELETE_PRODUCT_TYPE)).WithArgs(1).WillReturnError(&mysql.MySQLError{Number: MySqlNumberReferenced})
+
+	// Act
+	err = NewRepository(db).Delete(context.TODO(), 1)
+
+	// Assert
+	assert.EqualError(t, err, ErrInUse.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestDelete_FailNotFound passes when no product type is deleted
+func TestDelete_FailNotFound(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(DELETE_PRODUCT_TYPE)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err = NewRepository(db).Delete(context.TODO(), 1)
+
+	// Assert
+	assert.EqualError(t, err, ErrNotFound.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
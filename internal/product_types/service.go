@@ -0,0 +1,97 @@
+package product_types
+
+import (
+	"context"
+	"strings"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+)
+
+// Service represents a service layer for ProductType
+type Service interface {
+	GetAll(ctx context.Context) ([]domain.ProductType, error)
+	Get(ctx context.Context, id int) (domain.ProductType, error)
+	Create(ctx context.Context, pt domain.ProductType) (domain.ProductType, error)
+	// Update changes only the given values, the resulting temperature range must still be valid.
+	// A product type without a range gets one only when both temperatures are given
+	Update(ctx context.Context, id int, name *string, minimumTemperature *float32, maximumTemperature *float32) (domain.ProductType, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(r Repository) Service {
+	return &service{
+		repository: r,
+	}
+}
+
+func (s *service) GetAll(ctx context.Context) ([]domain.ProductType, error) {
+	return s.repository.GetAll(ctx)
+}
+
+func (s *service) Get(ctx context.Context, id int) (domain.ProductType, error) {
+	return s.repository.Get(ctx, id)
+}
+
+// Create saves a product type with the trimmed name, or returns ErrAlreadyExists if the name exists with any case
+func (s *service) Create(ctx context.Context, pt domain.ProductType) (domain.ProductType, error) {
+	pt.Name = strings.TrimSpace(pt.Name)
+	if err := validate(pt); err != nil {
+		return domain.ProductType{}, err
+	}
+
+	id, err := s.repository.Save(ctx, pt)
+	if err != nil {
+		logging.Log(err)
+		return domain.ProductType{}, err
+	}
+	pt.ID = id
+	return pt, nil
+}
+
+func (s *service) Update(ctx context.Context, id int, name *string, minimumTemperature *float32, maximumTemperature *float32) (domain.ProductType, error) {
+	pt, err := s.repository.Get(ctx, id)
+	if err != nil {
+		return domain.ProductType{}, err
+	}
+	if name != nil {
+		pt.Name = strings.TrimSpace(*name)
+	}
+	if minimumTemperature != nil {
+		pt.MinimumTemperature = minimumTemperature
+	}
+	if maximumTemperature != nil {
+		pt.MaximumTemperature = maximumTemperature
+	}
+	if err := validate(pt); err != nil {
+		return domain.ProductType{}, err
+	}
+
+	if err := s.repository.Update(ctx, pt); err != nil {
+		logging.Log(err)
+		return domain.ProductType{}, err
+	}
+	return pt, nil
+}
+
+// Delete removes a product type no product or section uses
+func (s *service) Delete(ctx context.Context, id int) error {
+	return s.repository.Delete(ctx, id)
+}
+
+func validate(pt domain.ProductType) error {
+	if pt.Name == "" {
+		return ErrBadRequest
+	}
+	if (pt.MinimumTemperature == nil) != (pt.MaximumTemperature == nil) {
+		return ErrPartialRange
+	}
+	if pt.HasTemperatureRange() && *pt.MinimumTemperature > *pt.MaximumTemperature {
+		return ErrInvalidRange
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package product_types
+
+import (
+	"context"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	logging.InitLog(nil)
+}
+
+func temperature(value float32) *float32 {
+	return &value
+}
+
+// TestCreate_OK passes when the product type is stored with its trimmed name
+func TestCreate_OK(t *testing.T) {
+	// Arrange
+	mockRepo := MockRepository{}
+	service := NewService(&mockRepo)
+
+	// Act
+	result, err := service.Create(context.TODO(), domain.ProductType{Name: " frozen ", MinimumTemperature: temperature(-30), MaximumTemperature: temperature(-18)})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ProductType{ID: 1, Name: "frozen", MinimumTemperature: temperature(-30), MaximumTemperature: temperature(-18)}, result)
+}
+
+// TestCreate_FailInvalidRange passes when the minimum temperature is greater than the maximum
+func TestCreate_FailInvalidRange(t *testing.T) {
+	// Arrange
+	mockRepo := MockRepository{}
+	service := NewService(&mockRepo)
+
+	// Act
+	_, err := service.Create(context.TODO(), domain.ProductType{Name: "frozen", MinimumTemperature: temperature(-18), MaximumTemperature: temperature(-30)})
+
+	// Assert
+	assert.EqualError(t, err, ErrInvalidRange.Error())
+	assert.Empty(t, mockRepo.Data)
+}
+
+// TestCreate_FailAlreadyExists passes when the name exists with any case
+func TestCreate_FailAlreadyExists(t *testing.T) {
+	// Arrange
+	service := NewService(&MockRepository{Data: []domain.ProductType{{ID: 1, Name: "Frozen"}}})
+
+	// Act
+	_, err := service.Create(context.TODO(), domain.ProductType{Name: "frozen"})
+
+	// Assert
+	assert.EqualError(t, err, ErrAlreadyExists.Error())
+}
+
+// TestUpdate_OK passes when only the given values change
+func TestUpdate_OK(t *testing.T) {
+	// Arrange
+	mockRepo := MockRepository{Data: []domain.ProductType{{ID: 1, Name: "frozen", MinimumTemperature: temperature(-30), MaximumTemperature: temperature(-18)}}}
+	service := NewService(&mockRepo)
+	maximum := float32(-15)
+
+	// Act
+	result, err := service.Update(context.TODO(), 1, nil, nil, &maximum)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ProductType{ID: 1, Name: "frozen", MinimumTemperature: temperature(-30), MaximumTemperature: temperature(-15)}, result)
+	assert.Equal(t, result, mockRepo.Data[0])
+}
+
+// TestUpdate_FailInvalidRange passes when the change leaves the minimum above the stored maximum
+func TestUpdate_FailInvalidRange(t *testing.T) {
+	// Arrange
+	mockRepo := MockRepository{Data: []domain.ProductType{{ID: 1, Name: "frozen", MinimumTemperature: temperature(-30), MaximumTemperature: temperature(-18)}}}
+	service := NewService(&mockRepo)
+	minimum := float32(-10)
+
+	// Act
+	_, err := service.Update(context.TODO(), 1, nil, &minimum, nil)
+
+	// Assert
+	assert.EqualError(t, err, ErrInvalidRange.Error())
+	assert.Equal(t, temperature(-30), mockRepo.Data[0].MinimumTemperature)
+}
+
+// TestUpdate_FailPartialRange passes when a product type without a range gets only one of its temperatures
+func TestUpdate_FailPartialRange(t *testing.T) {
+	// Arrange
+	mockRepo := MockRepository{Data: []domain.ProductType{{ID: 1, Name: "product type 1"}}}
+	service := NewService(&mockRepo)
+
+	// Act
+	_, errPartial := service.Update(context.TODO(), 1, nil, temperature(-30), nil)
+	result, errRange := service.Update(context.TODO(), 1, nil, temperature(-30), temperature(-18))
+
+	// Assert
+	assert.EqualError(t, errPartial, ErrPartialRange.Error())
+	assert.NoError(t, errRange)
+	assert.True(t, result.HasTemperatureRange())
+}
+
+// TestUpdate_FailNotFound passes when the product type does not exist
+func TestUpdate_FailNotFound(t *testing.T) {
+	// Arrange
+	service := NewService(&MockRepository{})
+	name := "frozen"
+
+	// Act
+	_, err := service.Update(context.TODO(), 1, &name, nil, nil)
+
+	// Assert
+	assert.EqualError(t, err, ErrNotFound.Error())
+}
+
+// TestDelete_FailUsedByProducts passes when products or sections use the product type
+func TestDelete_FailUsedByProducts(t *testing.T) {
+	// Arrange
+	mockRepo := MockRepository{Data: []domain.ProductType{{ID: 1, Name: "frozen"}}, InUse: []int{1}}
+	service := NewService(&mockRepo)
+
+	// Act
+	err := service.Delete(context.TODO(), 1)
+
+	// Assert
+	assert.EqualError(t, err, ErrInUse.Error())
+	assert.Len(t, mockRepo.Data, 1)
+}
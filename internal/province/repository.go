@@ -0,0 +1,194 @@
+package province
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/go-sql-driver/mysql"
+)
+
+// Errors
+var (
+	ErrNotFound        = errors.New("province not found")
+	ErrCountryNotFound = errors.New("country not found")
+	ErrAlreadyExists   = errors.New("province name already exists in the country")
+	ErrHasLocalities   = errors.New("province has localities")
+	ErrBadRequest      = errors.New("province name and country_id are required")
+	ErrInternal        = errors.New("database internal error")
+)
+
+// Repository encapsulates the storage of a Province.
+type Repository interface {
+	// GetAll returns the provinces of the country, or of every country if countryID is zero
+	GetAll(ctx context.Context, countryID int) ([]domain.Province, error)
+	Get(ctx context.Context, id int) (domain.Province, error)
+	Save(ctx context.Context, p domain.Province) (int, error)
+	Update(ctx context.Context, p domain.Province) error
+	Delete(ctx context.Context, id int) error
+	// ReportSellers counts the sellers of every locality of the province
+	ReportSellers(ctx context.Context, id int) ([]domain.ReportSellers, error)
+	// ReportCarries counts the carries of every locality of the province
+	ReportCarries(ctx context.Context, id int) ([]domain.ReportCarries, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+const (
+	GET_ALL_PROVINCES     = "SELECT p.id, p.name, c.id, c.name FROM provinces AS p INNER JOIN countries AS c ON c.id = p.country_id"
+	GET_PROVINCES_ORDER   = " ORDER BY c.name, p.name;"
+	GET_COUNTRY_PROVINCES = GET_ALL_PROVINCES + " WHERE p.country_id = ?" + GET_PROVINCES_ORDER
+	GET_PROVINCE          = GET_ALL_PROVINCES + " WHERE p.id = ?;"
+	SAVE_PROVINCE         = "INSERT INTO provinces (name, country_id) VALUES (?, ?);"
+	UPDATE_PROVINCE       = "UPDATE provinces SET name=?, country_id=? WHERE id=?;"
+	DELETE_PROVINCE       = "DELETE FROM provinces WHERE id=?;"
+	REPORT_SELLERS        = "SELECT l.id, l.locality_name, COUNT(s.id) FROM localities AS l LEFT JOIN sellers AS s ON s.locality_id = l.id WHERE l.province_id = ? GROUP BY l.id, l.locality_name ORDER BY l.id;"
+	REPORT_CARRIES        = "SELECT l.id, l.locality_name, COUNT(c.id) FROM localities AS l LEFT JOIN carries AS c ON c.locality_id = l.id WHERE l.province_id = ? GROUP BY l.id, l.locality_name ORDER BY l.id;"
+	MySqlNumberDuplicate  = 1062
+	MySqlNumberReferenced = 1451
+	MySqlNumberForeignKey = 1452
+)
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) GetAll(ctx context.Context, countryID int) ([]domain.Province, error) {
+	var rows *sql.Rows
+	var err error
+	if countryID != 0 {
+		rows, err = r.db.QueryContext(ctx, GET_COUNTRY_PROVINCES, countryID)
+	} else {
+		rows, err = r.db.QueryContext(ctx, GET_ALL_PROVINCES+GET_PROVINCES_ORDER)
+	}
+	if err != nil {
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+
+	provinces := []domain.Province{}
+	for rows.Next() {
+		p := domain.Province{}
+		if err := rows.Scan(&p.ID, &p.Name, &p.CountryID, &p.CountryName); err != nil {
+			return nil, ErrInternal
+		}
+		provinces = append(provinces, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, ErrInternal
+	}
+	return provinces, nil
+}
+
+func (r *repository) Get(ctx context.Context, id int) (domain.Province, error) {
+	p := domain.Province{}
+	err := r.db.QueryRowContext(ctx, GET_PROVINCE, id).Scan(&p.ID, &p.Name, &p.CountryID, &p.CountryName)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return domain.Province{}, ErrNotFound
+		default:
+			return domain.Province{}, ErrInternal
+		}
+	}
+	return p, nil
+}
+
+func (r *repository) Save(ctx context.Context, p domain.Province) (int, error) {
+	res, err := r.db.ExecContext(ctx, SAVE_PROVINCE, p.Name, p.CountryID)
+	if err != nil {
+		return 0, parseError(err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, ErrInternal
+	}
+	return int(id), nil
+}
+
+func (r *repository) Update(ctx context.Context, p domain.Province) error {
+	if _, err := r.db.ExecContext(ctx, UPDATE_PROVINCE, p.Name, p.CountryID, p.ID); err != nil {
+		return parseError(err)
+	}
+	return nil
+}
+
+func (r *repository) Delete(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, DELETE_PROVINCE, id)
+	if err != nil {
+		return parseError(err)
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return ErrInternal
+	}
+	if affect < 1 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *repository) ReportSellers(ctx context.Context, id int) ([]domain.ReportSellers, error) {
+	rows, err := r.db.QueryContext(ctx, REPORT_SELLERS, id)
+	if err != nil {
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+
+	report := []domain.ReportSellers{}
+	for rows.Next() {
+		row := domain.ReportSellers{}
+		if err := rows.Scan(&row.LocalityID, &row.LocalityName, &row.SellersCount); err != nil {
+			return nil, ErrInternal
+		}
+		report = append(report, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, ErrInternal
+	}
+	return report, nil
+}
+
+func (r *repository) ReportCarries(ctx context.Context, id int) ([]domain.ReportCarries, error) {
+	rows, err := r.db.QueryContext(ctx, REPORT_CARRIES, id)
+	if err != nil {
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+
+	report := []domain.ReportCarries{}
+	for rows.Next() {
+		row := domain.ReportCarries{}
+		if err := rows.Scan(&row.LocalityID, &row.LocalityName, &row.CarriesCount); err != nil {
+			return nil, ErrInternal
+		}
+		report = append(report, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, ErrInternal
+	}
+	return report, nil
+}
+
+// parseError translates the mysql errors of the provinces table, names are unique without case inside a country
+// and a province can not be deleted while it has localities
+func parseError(err error) error {
+	if mysqlError, ok := err.(*mysql.MySQLError); ok {
+		switch mysqlError.Number {
+		case MySqlNumberDuplicate:
+			return ErrAlreadyExists
+		case MySqlNumberReferenced:
+			return ErrHasLocalities
+		case MySqlNumberForeignKey:
+			return ErrCountryNotFound
+		}
+	}
+	return ErrInternal
+}
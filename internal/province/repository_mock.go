@@ -0,0 +1,80 @@
+package province
+
+import (
+	"context"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+)
+
+type MockRepository struct {
+	Data          []domain.Province
+	ReportSeller  []domain.ReportSellers
+	ReportCarry   []domain.ReportCarries
+	ErrorMock     error
+	LastCountryID int
+}
+
+func (m *MockRepository) GetAll(ctx context.Context, countryID int) ([]domain.Province, error) {
+	m.LastCountryID = countryID
+	if m.ErrorMock != nil {
+		return nil, m.ErrorMock
+	}
+	return m.Data, nil
+}
+
+func (m *MockRepository) Get(ctx context.Context, id int) (domain.Province, error) {
+	for _, p := range m.Data {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return domain.Province{}, ErrNotFound
+}
+
+func (m *MockRepository) Save(ctx context.Context, p domain.Province) (int, error) {
+	if m.ErrorMock != nil {
+		return 0, m.ErrorMock
+	}
+	p.ID = len(m.Data) + 1
+	m.Data = append(m.Data, p)
+	return p.ID, nil
+}
+
+func (m *MockRepository) Update(ctx context.Context, p domain.Province) error {
+	if m.ErrorMock != nil {
+		return m.ErrorMock
+	}
+	for i := range m.Data {
+		if m.Data[i].ID == p.ID {
+			m.Data[i] = p
+		}
+	}
+	return nil
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id int) error {
+	if m.ErrorMock != nil {
+		return m.ErrorMock
+	}
+	for i := range m.Data {
+		if m.Data[i].ID == id {
+			m.Data = append(m.Data[:i], m.Data[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (m *MockRepository) ReportSellers(ctx context.Context, id int) ([]domain.ReportSellers, error) {
+	if m.ErrorMock != nil {
+		return nil, m.ErrorMock
+	}
+	return m.ReportSeller, nil
+}
+
+func (m *MockRepository) ReportCarries(ctx context.Context, id int) ([]domain.ReportCarries, error) {
+	if m.ErrorMock != nil {
+		return nil, m.ErrorMock
+	}
+	return m.ReportCarry, nil
+}
@@ -0,0 +1,86 @@
+package province
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+var provinceColumns = []string{"id", "name", "country_id", "country_name"}
+
+// TestGetAll_ByCountry passes when only the provinces of the country are returned
+func TestGetAll_ByCountry(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows(provinceColumns).AddRow(3, "San Luis", 1, "Argentina")
+	mock.ExpectQuery(regexp.QuoteMeta(GET_COUNTRY_PROVINCES)).WithArgs(1).WillReturnRows(rows)
+
+	// Act
+	result, err := NewRepository(db).GetAll(context.TODO(), 1)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Province{{ID: 3, Name: "San Luis", CountryID: 1, CountryName: "Argentina"}}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestSave_FailCountryNotFound passes when the country of the province does not exist
+func TestSave_FailCountryNotFound(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(SAVE_PROVINCE)).WithArgs("San Luis", 9).WillReturnError(&mysql.MySQLError{Number: MySqlNumberForeignKey})
+
+	// Act
+	_, err = NewRepository(db).Save(context.TODO(), domain.Province{Name: "San Luis", CountryID: 9})
+
+	// Assert
+	assert.EqualError(t, err, ErrCountryNotFound.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestDelete_FailHasLocalities passes when the province is referenced by its localities
+func TestDelete_FailHasLocalities(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(DELETE_PROVINCE)).WithArgs(3).WillReturnError(&mysql.MySQLError{Number: MySqlNumberReferenced})
+
+	// Act
+	err = NewRepository(db).Delete(context.TODO(), 3)
+
+	// Assert
+	assert.EqualError(t, err, ErrHasLocalities.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestReportSellers_OK passes when every locality of the province has its sellers count
+func TestReportSellers_OK(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "locality_name", "sellers_count"}).AddRow("5700", "Capital", 2).AddRow("5730", "Villa Mercedes", 0)
+	mock.ExpectQuery(regexp.QuoteMeta(REPORT_SELLERS)).WithArgs(3).WillReturnRows(rows)
+
+	// Act
+	result, err := NewRepository(db).ReportSellers(context.TODO(), 3)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.ReportSellers{{LocalityID: "5700", LocalityName: "Capital", SellersCount: 2}, {LocalityID: "5730", LocalityName: "Villa Mercedes"}}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
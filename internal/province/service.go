@@ -0,0 +1,120 @@
+package province
+
+import (
+	"context"
+	"strings"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+)
+
+// Service represents a service layer for Province
+type Service interface {
+	GetAll(ctx context.Context, countryID int) ([]domain.Province, error)
+	Get(ctx context.Context, id int) (domain.Province, error)
+	Create(ctx context.Context, p domain.Province) (domain.Province, error)
+	// Update changes the name or the country of the province, zero values are kept
+	Update(ctx context.Context, p domain.Province) (domain.Province, error)
+	Delete(ctx context.Context, id int) error
+	ReportSellers(ctx context.Context, id int) (domain.ProvinceReportSellers, error)
+	ReportCarries(ctx context.Context, id int) (domain.ProvinceReportCarries, error)
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(r Repository) Service {
+	return &service{
+		repository: r,
+	}
+}
+
+func (s *service) GetAll(ctx context.Context, countryID int) ([]domain.Province, error) {
+	return s.repository.GetAll(ctx, countryID)
+}
+
+func (s *service) Get(ctx context.Context, id int) (domain.Province, error) {
+	return s.repository.Get(ctx, id)
+}
+
+// Create saves a province with the trimmed name and returns it with the name of its country
+func (s *service) Create(ctx context.Context, p domain.Province) (domain.Province, error) {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" || p.CountryID == 0 {
+		logging.Log(ErrBadRequest)
+		return domain.Province{}, ErrBadRequest
+	}
+
+	id, err := s.repository.Save(ctx, p)
+	if err != nil {
+		logging.Log(err)
+		return domain.Province{}, err
+	}
+	return s.repository.Get(ctx, id)
+}
+
+func (s *service) Update(ctx context.Context, p domain.Province) (domain.Province, error) {
+	stored, err := s.repository.Get(ctx, p.ID)
+	if err != nil {
+		logging.Log(err)
+		return domain.Province{}, err
+	}
+	if name := strings.TrimSpace(p.Name); name != "" {
+		stored.Name = name
+	}
+	if p.CountryID != 0 {
+		stored.CountryID = p.CountryID
+	}
+
+	if err := s.repository.Update(ctx, stored); err != nil {
+		logging.Log(err)
+		return domain.Province{}, err
+	}
+	return s.repository.Get(ctx, p.ID)
+}
+
+// Delete removes a province without localities
+func (s *service) Delete(ctx context.Context, id int) error {
+	return s.repository.Delete(ctx, id)
+}
+
+// ReportSellers returns the sellers of every locality of the province and their total
+func (s *service) ReportSellers(ctx context.Context, id int) (domain.ProvinceReportSellers, error) {
+	p, err := s.repository.Get(ctx, id)
+	if err != nil {
+		logging.Log(err)
+		return domain.ProvinceReportSellers{}, err
+	}
+	localities, err := s.repository.ReportSellers(ctx, id)
+	if err != nil {
+		logging.Log(err)
+		return domain.ProvinceReportSellers{}, err
+	}
+
+	report := domain.ProvinceReportSellers{ProvinceID: p.ID, ProvinceName: p.Name, CountryName: p.CountryName, Localities: localities}
+	for _, l := range localities {
+		report.SellersCount += l.SellersCount
+	}
+	return report, nil
+}
+
+// ReportCarries returns the carries of every locality of the province and their total
+func (s *service) ReportCarries(ctx context.Context, id int) (domain.ProvinceReportCarries, error) {
+	p, err := s.repository.Get(ctx, id)
+	if err != nil {
+		logging.Log(err)
+		return domain.ProvinceReportCarries{}, err
+	}
+	localities, err := s.repository.ReportCarries(ctx, id)
+	if err != nil {
+		logging.Log(err)
+		return domain.ProvinceReportCarries{}, err
+	}
+
+	report := domain.ProvinceReportCarries{ProvinceID: p.ID, ProvinceName: p.Name, CountryName: p.CountryName, Localities: localities}
+	for _, l := range localities {
+		report.CarriesCount += l.CarriesCount
+	}
+	return report, nil
+}
@@ -0,0 +1,70 @@
+package province
+
+import (
+	"context"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	logging.InitLog(nil)
+}
+
+// TestCreate_FailWithoutCountry passes when the province has no country
+func TestCreate_FailWithoutCountry(t *testing.T) {
+	// Arrange
+	service := NewService(&MockRepository{})
+
+	// Act
+	_, err := service.Create(context.TODO(), domain.Province{Name: "San Luis"})
+
+	// Assert
+	assert.EqualError(t, err, ErrBadRequest.Error())
+}
+
+// TestUpdate_KeepsCountry passes when only the name of the province changes
+func TestUpdate_KeepsCountry(t *testing.T) {
+	// Arrange
+	mockRepo := MockRepository{Data: []domain.Province{{ID: 3, Name: "san luis", CountryID: 1, CountryName: "Argentina"}}}
+	service := NewService(&mockRepo)
+
+	// Act
+	result, err := service.Update(context.TODO(), domain.Province{ID: 3, Name: "San Luis"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Province{ID: 3, Name: "San Luis", CountryID: 1, CountryName: "Argentina"}, result)
+}
+
+// TestReportSellers_AddsLocalities passes when the sellers of every locality are added up
+func TestReportSellers_AddsLocalities(t *testing.T) {
+	// Arrange
+	localities := []domain.ReportSellers{{LocalityID: "5700", LocalityName: "Capital", SellersCount: 2}, {LocalityID: "5730", LocalityName: "Villa Mercedes", SellersCount: 3}}
+	mockRepo := MockRepository{
+		Data:         []domain.Province{{ID: 3, Name: "San Luis", CountryID: 1, CountryName: "Argentina"}},
+		ReportSeller: localities,
+	}
+	service := NewService(&mockRepo)
+
+	// Act
+	result, err := service.ReportSellers(context.TODO(), 3)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ProvinceReportSellers{ProvinceID: 3, ProvinceName: "San Luis", CountryName: "Argentina", SellersCount: 5, Localities: localities}, result)
+}
+
+// TestReportCarries_FailNotFound passes when the province does not exist
+func TestReportCarries_FailNotFound(t *testing.T) {
+	// Arrange
+	service := NewService(&MockRepository{})
+
+	// Act
+	_, err := service.ReportCarries(context.TODO(), 3)
+
+	// Assert
+	assert.EqualError(t, err, ErrNotFound.Error())
+}
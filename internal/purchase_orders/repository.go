@@ -4,6 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
@@ -18,6 +21,9 @@ var (
 	ErrAlreadyExists  = errors.New("order_number already exists")
 	ErrFKConstraint   = errors.New("a column table constraint fails")
 	ErrDataLong       = errors.New("a field exceeds the maximum length")
+	ErrStatusChanged  = errors.New("the order status was changed by someone else, try again")
+	// ErrInsufficientStock is returned when the available quantity of the batches of a product is lower than the ordered one
+	ErrInsufficientStock = errors.New("there is not enough available stock of the product")
 )
 
 const (
@@ -25,63 +31,74 @@ const (
 	EXISTS_ORDER_QUERY             = "SELECT id FROM purchase_orders WHERE order_number = ?;"
 	GET_ORDERS_BY_BUYERID_QUERY    = "SELECT b.id 'buyer_id', b.card_number_id, b.first_name, b.last_name , count(p.id) 'orders_count' FROM purchase_orders p RIGHT JOIN buyers b ON p.buyer_id = b.id WHERE b.id = ? GROUP BY b.id, b.card_number_id, b.first_name, b.last_name;"
 	GETALL_ORDERS_BY_BUYERID_QUERY = "SELECT b.id 'buyer_id', b.card_number_id, b.first_name, b.last_name , count(p.id) 'orders_count' FROM purchase_orders p RIGHT JOIN buyers b ON p.buyer_id = b.id GROUP BY b.id, b.card_number_id, b.first_name, b.last_name;"
+	GET_ORDER_STATUS_QUERY         = "SELECT order_status_id FROM purchase_orders WHERE id = ?;"
+	UPDATE_ORDER_STATUS_QUERY      = "UPDATE purchase_orders SET order_status_id = ? WHERE id = ? AND order_status_id = ?;"
+	INSERT_STATUS_HISTORY_QUERY    = "INSERT INTO purchase_order_status_history (purchase_order_id, from_status_id, to_status_id, actor, changed_at) VALUES (?, ?, ?, ?, ?);"
+	GET_STATUS_HISTORY_QUERY       = "SELECT id, purchase_order_id, from_status_id, to_status_id, actor, changed_at FROM purchase_order_status_history WHERE purchase_order_id = ? ORDER BY changed_at, id;"
+	EXISTS_BUYER_QUERY             = "SELECT id FROM buyers WHERE id = ?;"
+	INSERT_ORDER_ITEM_QUERY        = "INSERT INTO order_items (purchase_order_id, product_id, product_record_id, quantity, unit_price) VALUES (?, ?, ?, ?, ?);"
+	GET_PRODUCT_RECORD_QUERY       = "SELECT id, product_id, IFNULL(sale_price, 0), last_update_date FROM product_records WHERE id = ?;"
+	GET_ORDER_LINES_QUERY          = "SELECT product_id, quantity FROM order_items WHERE purchase_order_id = ? ORDER BY id;"
+	LOCK_RESERVABLE_BATCHES_QUERY  = "SELECT id, current_quantity - reserved_quantity FROM product_batches WHERE product_id = ? AND current_quantity > reserved_quantity AND due_date >= CURDATE() AND NOT quarantined ORDER BY due_date, id FOR UPDATE;"
+	RESERVE_BATCH_QUERY            = "UPDATE product_batches SET reserved_quantity = reserved_quantity + ? WHERE id = ?;"
+	RELEASE_BATCH_QUERY            = "UPDATE product_batches SET reserved_quantity = GREATEST(reserved_quantity - ?, 0) WHERE id = ?;"
+	CONSUME_BATCH_QUERY            = "UPDATE product_batches SET reserved_quantity = GREATEST(reserved_quantity - ?, 0), current_quantity = GREATEST(current_quantity - ?, 0) WHERE id = ?;"
+	RELEASE_SECTION_CAPACITY_QUERY = "UPDATE sections SET current_capacity = GREATEST(current_capacity - ?, 0) WHERE id = ?;"
+	INSERT_RESERVATION_QUERY       = "INSERT INTO stock_reservations (purchase_order_id, product_batch_id, quantity, status, expires_at, created_at) VALUES (?, ?, ?, ?, ?, ?);"
+	CONFIRM_RESERVATIONS_QUERY     = "UPDATE stock_reservations SET status = 'confirmed', expires_at = NULL WHERE purchase_order_id = ? AND status = 'held' AND expires_at > ?;"
+	SET_RESERVATION_STATUS_QUERY   = "UPDATE stock_reservations SET status = ? WHERE id = ?;"
+	LOCK_ORDER_RESERVATIONS_QUERY  = "SELECT r.id, r.product_batch_id, pb.section_id, r.quantity FROM stock_reservations AS r INNER JOIN product_batches AS pb ON pb.id = r.product_batch_id WHERE r.purchase_order_id = ? AND r.status IN (?, ?) FOR UPDATE;"
+	LOCK_LAPSED_RESERVATIONS_QUERY = "SELECT r.id, r.product_batch_id, pb.section_id, r.quantity FROM stock_reservations AS r INNER JOIN product_batches AS pb ON pb.id = r.product_batch_id WHERE r.status = 'held' AND r.expires_at <= ? FOR UPDATE;"
+	GET_RESERVATIONS_QUERY         = "SELECT r.id, r.purchase_order_id, r.product_batch_id, pb.product_id, r.quantity, r.status, r.expires_at, r.created_at FROM stock_reservations AS r INNER JOIN product_batches AS pb ON pb.id = r.product_batch_id WHERE r.purchase_order_id = ? ORDER BY r.id;"
 	MySqlNumberFKConstraint        = 1452
 	MySqlNumberDataLong            = 1406
 	MySqlNumberDuplicate           = 1062
+	// GET_ORDER_DETAILS_QUERY is completed with the conditions of a search by buildGetAllQuery
+	GET_ORDER_DETAILS_QUERY = `SELECT po.id, po.order_number, DATE_FORMAT(po.order_date, '%Y-%m-%d'), po.tracking_code, po.order_status_id,
+	b.id, b.card_number_id, b.first_name, b.last_name,
+	pr.id, pr.last_update_date, IFNULL(pr.purchase_price, 0), IFNULL(pr.sale_price, 0), pr.product_id
+	FROM purchase_orders AS po
+	INNER JOIN buyers AS b ON b.id = po.buyer_id
+	LEFT JOIN product_records AS pr ON pr.id = po.product_record_id`
+	GET_ORDER_DETAIL_QUERY = GET_ORDER_DETAILS_QUERY + " WHERE po.id = ?;"
+	// GET_ORDER_ITEMS_QUERY is completed with a placeholder for every purchase order
+	GET_ORDER_ITEMS_QUERY = "SELECT id, purchase_order_id, product_id, product_record_id, quantity, unit_price FROM order_items WHERE purchase_order_id IN "
 )
 
 type Repository interface {
-	SaveOrder(ctx context.Context, p domain.Purchase_orders) (int, error)
+	SaveOrder(ctx context.Context, p domain.Purchase_orders, now time.Time, holdUntil time.Time) (domain.Purchase_orders, error)
 	Exists(ctx context.Context, orderID string) bool
 	GetByBuyerId(ctx context.Context, buyerId int) ([]domain.Purchase_orders_buyer, error)
 	GetAllByBuyer(ctx context.Context) ([]domain.Purchase_orders_buyer, error)
+	GetStatus(ctx context.Context, id int) (int, error)
+	UpdateStatus(ctx context.Context, change domain.OrderStatusChange) (int, error)
+	UpdateStatusTx(ctx context.Context, tx *sql.Tx, change domain.OrderStatusChange) (int, error)
+	GetStatusHistory(ctx context.Context, id int) ([]domain.OrderStatusChange, error)
+	Get(ctx context.Context, id int) (domain.PurchaseOrderDetail, error)
+	GetAll(ctx context.Context, filter domain.PurchaseOrderFilter) ([]domain.PurchaseOrderDetail, error)
+	ExistsBuyer(ctx context.Context, buyerId int) bool
+	GetItems(ctx context.Context, orderIds []int) ([]domain.OrderItem, error)
+	GetPrices(ctx context.Context, productIds []int, at time.Time) ([]domain.ProductRecord, error)
+	GetProductRecord(ctx context.Context, id int) (domain.ProductRecord, error)
+	GetReservations(ctx context.Context, id int) ([]domain.StockReservation, error)
+	ExpireReservations(ctx context.Context, now time.Time) (int, error)
 }
 
-type repository struct {
-	db *sql.DB
+// Records looks up the product records in effect at a date, the sale price of the record is the price of an order item
+type Records interface {
+	GetEffectiveAll(ctx context.Context, productIDs []int, at time.Time) ([]domain.ProductRecord, error)
 }
 
-func NewRepository(db *sql.DB) Repository {
-	return &repository{
-		db: db,
-	}
+type repository struct {
+	db      *sql.DB
+	records Records
 }
 
-func (r *repository) SaveOrder(ctx context.Context, p domain.Purchase_orders) (int, error) {
-	stmt, err := r.db.PrepareContext(ctx, INSERT_ORDER_QUERY)
-	if err != nil {
-		logging.Log(err)
-		return 0, err
-	}
-	defer stmt.Close()
-
-	result, err := stmt.ExecContext(ctx, &p.OrderNumber, &p.OrderDate, &p.TrackingCode, &p.BuyerId, &p.ProductRecordId, &p.OrderStatusId)
-	if err != nil {
-		mysqlError, ok := err.(*mysql.MySQLError)
-		if ok {
-			switch mysqlError.Number {
-			case MySqlNumberFKConstraint:
-				logging.Log(ErrFKConstraint)
-				return 0, ErrFKConstraint
-			case MySqlNumberDataLong:
-				logging.Log(ErrDataLong)
-				return 0, ErrDataLong
-			case MySqlNumberDuplicate:
-				logging.Log(ErrAlreadyExists)
-				return 0, ErrAlreadyExists
-			}
-		}
-		logging.Log(ErrInternal)
-		return 0, ErrInternal
-	}
-
-	id, err := result.LastInsertId()
-	if err != nil {
-		logging.Log(err)
-		return 0, err
+func NewRepository(db *sql.DB, records Records) Repository {
+	return &repository{
+		db:      db,
+		records: records,
 	}
-
-	return int(id), nil
 }
 
 func (r *repository) Exists(ctx context.Context, orderNumber string) bool {
@@ -130,3 +147,563 @@ func (r *repository) GetAllByBuyer(ctx context.Context) ([]domain.Purchase_order
 
 	return result, nil
 }
+
+// GetStatus returns the current status id of the purchase order
+func (r *repository) GetStatus(ctx context.Context, id int) (int, error) {
+	var status int
+	row := r.db.QueryRowContext(ctx, GET_ORDER_STATUS_QUERY, id)
+	if err := row.Scan(&status); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logging.Log(ErrNotFound)
+			return 0, ErrNotFound
+		}
+		logging.Log(err)
+		return 0, ErrInternal
+	}
+	return status, nil
+}
+
+// UpdateStatus moves the purchase order to the new status, updates its stock reservations and stores the change in its history
+// inside a single transaction. The order must still be in the from status of the change, otherwise ErrStatusChanged is returned.
+func (r *repository) UpdateStatus(ctx context.Context, change domain.OrderStatusChange) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Log(err)
+		return 0, ErrInternal
+	}
+	defer tx.Rollback()
+
+	id, err := r.UpdateStatusTx(ctx, tx, change)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		logging.Log(err)
+		return 0, ErrInternal
+	}
+	return id, nil
+}
+
+// UpdateStatusTx makes the change of UpdateStatus inside a transaction owned by the caller, which commits or rolls it back
+func (r *repository) UpdateStatusTx(ctx context.Context, tx *sql.Tx, change domain.OrderStatusChange) (int, error) {
+	res, err := tx.ExecContext(ctx, UPDATE_ORDER_STATUS_QUERY, change.ToStatusId, change.PurchaseOrderId, change.FromStatusId)
+	if err != nil {
+		logging.Log(err)
+		return 0, ErrInternal
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		logging.Log(err)
+		return 0, ErrInternal
+	}
+	if affected == 0 {
+		logging.Log(ErrStatusChanged)
+		return 0, ErrStatusChanged
+	}
+
+	if err := applyReservations(ctx, tx, change); err != nil {
+		return 0, err
+	}
+
+	res, err = tx.ExecContext(ctx, INSERT_STATUS_HISTORY_QUERY, change.PurchaseOrderId, change.FromStatusId, change.ToStatusId, change.Actor, change.ChangedAt)
+	if err != nil {
+		mysqlError, ok := err.(*mysql.MySQLError)
+		if ok && mysqlError.Number == MySqlNumberDataLong {
+			logging.Log(ErrDataLong)
+			return 0, ErrDataLong
+		}
+		logging.Log(err)
+		return 0, ErrInternal
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		logging.Log(err)
+		return 0, ErrInternal
+	}
+	return int(id), nil
+}
+
+// GetStatusHistory returns the status changes of the purchase order from the oldest to the newest
+func (r *repository) GetStatusHistory(ctx context.Context, id int) ([]domain.OrderStatusChange, error) {
+	rows, err := r.db.QueryContext(ctx, GET_STATUS_HISTORY_QUERY, id)
+	if err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+
+	history := []domain.OrderStatusChange{}
+	for rows.Next() {
+		var c domain.OrderStatusChange
+		if err := rows.Scan(&c.ID, &c.PurchaseOrderId, &c.FromStatusId, &c.ToStatusId, &c.Actor, &c.ChangedAt); err != nil {
+			logging.Log(err)
+			return nil, ErrInternal
+		}
+		history = append(history, c)
+	}
+	if err := rows.Err(); err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	return history, nil
+}
+
+// Get returns the purchase order with its buyer and product record
+func (r *repository) Get(ctx context.Context, id int) (domain.PurchaseOrderDetail, error) {
+	row := r.db.QueryRowContext(ctx, GET_ORDER_DETAIL_QUERY, id)
+	o, err := scanOrderDetail(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logging.Log(ErrNotFound)
+			return domain.PurchaseOrderDetail{}, ErrNotFound
+		}
+		logging.Log(err)
+		return domain.PurchaseOrderDetail{}, ErrInternal
+	}
+	return o, nil
+}
+
+// GetAll returns the purchase orders that match the filter, the most recent first
+func (r *repository) GetAll(ctx context.Context, filter domain.PurchaseOrderFilter) ([]domain.PurchaseOrderDetail, error) {
+	query, args := buildGetAllQuery(filter)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+
+	orders := []domain.PurchaseOrderDetail{}
+	for rows.Next() {
+		o, err := scanOrderDetail(rows)
+		if err != nil {
+			logging.Log(err)
+			return nil, ErrInternal
+		}
+		orders = append(orders, o)
+	}
+	if err := rows.Err(); err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	return orders, nil
+}
+
+// ExistsBuyer returns true if there is a buyer with the given id
+func (r *repository) ExistsBuyer(ctx context.Context, buyerId int) bool {
+	row := r.db.QueryRowContext(ctx, EXISTS_BUYER_QUERY, buyerId)
+	err := row.Scan(&buyerId)
+	return err == nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanOrderDetail reads a row of GET_ORDER_DETAILS_QUERY
+func scanOrderDetail(row scanner) (domain.PurchaseOrderDetail, error) {
+	var o domain.PurchaseOrderDetail
+	var recordId, productId sql.NullInt64
+	var lastUpdateDate sql.NullTime
+	var purchasePrice, salePrice float32
+	err := row.Scan(&o.ID, &o.OrderNumber, &o.OrderDate, &o.TrackingCode, &o.OrderStatusId,
+		&o.Buyer.ID, &o.Buyer.CardNumberID, &o.Buyer.FirstName, &o.Buyer.LastName,
+		&recordId, &lastUpdateDate, &purchasePrice, &salePrice, &productId)
+	if err != nil {
+		return domain.PurchaseOrderDetail{}, err
+	}
+	if recordId.Valid {
+		o.ProductRecord = &domain.ProductRecord{
+			ID:             int(recordId.Int64),
+			LastUpdateDate: domain.MySqlTime{Time: lastUpdateDate.Time},
+			PurchasePrice:  purchasePrice,
+			SalePrice:      salePrice,
+			ProductID:      int(productId.Int64),
+		}
+	}
+	return o, nil
+}
+
+// buildGetAllQuery appends to GET_ORDER_DETAILS_QUERY a condition for every filter that is set
+func buildGetAllQuery(filter domain.PurchaseOrderFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if filter.BuyerId != 0 {
+		conditions = append(conditions, "po.buyer_id = ?")
+		args = append(args, filter.BuyerId)
+	}
+	if filter.StatusId != 0 {
+		conditions = append(conditions, "po.order_status_id = ?")
+		args = append(args, filter.StatusId)
+	}
+	if filter.From != "" {
+		conditions = append(conditions, "po.order_date >= ?")
+		args = append(args, filter.From)
+	}
+	if filter.To != "" {
+		conditions = append(conditions, "po.order_date <= ?")
+		args = append(args, filter.To)
+	}
+
+	query := GET_ORDER_DETAILS_QUERY
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	return query + " ORDER BY po.order_date DESC, po.id DESC;", args
+}
+
+// SaveOrder stores the purchase order and all of its items, and holds the ordered quantity of every item
+// in the batches of its product until holdUntil, inside a single transaction.
+// It returns the order with the ids of the new rows and its reservations.
+func (r *repository) SaveOrder(ctx context.Context, p domain.Purchase_orders, now time.Time, holdUntil time.Time) (domain.Purchase_orders, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Log(err)
+		return domain.Purchase_orders{}, ErrInternal
+	}
+	defer tx.Rollback()
+
+	var productRecordId interface{}
+	if p.ProductRecordId != 0 {
+		productRecordId = p.ProductRecordId
+	}
+	result, err := tx.ExecContext(ctx, INSERT_ORDER_QUERY, p.OrderNumber, p.OrderDate, p.TrackingCode, p.BuyerId, productRecordId, p.OrderStatusId)
+	if err != nil {
+		err = parseWriteError(err)
+		logging.Log(err)
+		return domain.Purchase_orders{}, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		logging.Log(err)
+		return domain.Purchase_orders{}, ErrInternal
+	}
+	p.ID = int(id)
+
+	items := make([]domain.OrderItem, len(p.Items))
+	copy(items, p.Items)
+	for i, item := range items {
+		result, err := tx.ExecContext(ctx, INSERT_ORDER_ITEM_QUERY, p.ID, item.ProductId, item.ProductRecordId, item.Quantity, item.UnitPrice)
+		if err != nil {
+			err = parseWriteError(err)
+			logging.Log(err)
+			return domain.Purchase_orders{}, err
+		}
+		itemId, err := result.LastInsertId()
+		if err != nil {
+			logging.Log(err)
+			return domain.Purchase_orders{}, ErrInternal
+		}
+		items[i].ID = int(itemId)
+		items[i].PurchaseOrderId = p.ID
+	}
+
+	reservations, err := reserve(ctx, tx, p.ID, items, domain.ReservationHeld, &holdUntil, now)
+	if err != nil {
+		return domain.Purchase_orders{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		logging.Log(err)
+		return domain.Purchase_orders{}, ErrInternal
+	}
+	p.Items = items
+	p.Reservations = reservations
+	return p, nil
+}
+
+// GetItems returns the items of the given purchase orders
+func (r *repository) GetItems(ctx context.Context, orderIds []int) ([]domain.OrderItem, error) {
+	items := []domain.OrderItem{}
+	if len(orderIds) == 0 {
+		return items, nil
+	}
+	args := make([]interface{}, len(orderIds))
+	for i, id := range orderIds {
+		args[i] = id
+	}
+	rows, err := r.db.QueryContext(ctx, GET_ORDER_ITEMS_QUERY+placeholders(len(orderIds))+" ORDER BY purchase_order_id, id;", args...)
+	if err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.OrderItem
+		if err := rows.Scan(&item.ID, &item.PurchaseOrderId, &item.ProductId, &item.ProductRecordId, &item.Quantity, &item.UnitPrice); err != nil {
+			logging.Log(err)
+			return nil, ErrInternal
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	return items, nil
+}
+
+// GetPrices returns the product record in effect at the given date for every product that has one
+func (r *repository) GetPrices(ctx context.Context, productIds []int, at time.Time) ([]domain.ProductRecord, error) {
+	if len(productIds) == 0 {
+		return []domain.ProductRecord{}, nil
+	}
+	records, err := r.records.GetEffectiveAll(ctx, productIds, at)
+	if err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	return records, nil
+}
+
+// placeholders returns a parenthesized list of n placeholders for an IN condition
+func placeholders(n int) string {
+	return "(" + strings.TrimSuffix(strings.Repeat("?, ", n), ", ") + ")"
+}
+
+// parseWriteError translates the mysql errors of an insert into the errors of the package
+func parseWriteError(err error) error {
+	mysqlError, ok := err.(*mysql.MySQLError)
+	if ok {
+		switch mysqlError.Number {
+		case MySqlNumberFKConstraint:
+			return ErrFKConstraint
+		case MySqlNumberDataLong:
+			return ErrDataLong
+		case MySqlNumberDuplicate:
+			return ErrAlreadyExists
+		}
+	}
+	return ErrInternal
+}
+
+// GetProductRecord returns the product and sale price of the product record, or ErrFKConstraint if it does not exist
+func (r *repository) GetProductRecord(ctx context.Context, id int) (domain.ProductRecord, error) {
+	var record domain.ProductRecord
+	var lastUpdateDate sql.NullTime
+	err := r.db.QueryRowContext(ctx, GET_PRODUCT_RECORD_QUERY, id).Scan(&record.ID, &record.ProductID, &record.SalePrice, &lastUpdateDate)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logging.Log(ErrFKConstraint)
+			return domain.ProductRecord{}, ErrFKConstraint
+		}
+		logging.Log(err)
+		return domain.ProductRecord{}, ErrInternal
+	}
+	if lastUpdateDate.Valid {
+		record.LastUpdateDate.Time = lastUpdateDate.Time
+	}
+	return record, nil
+}
+
+// GetReservations returns every stock reservation of the purchase order, in the order they were made
+func (r *repository) GetReservations(ctx context.Context, id int) ([]domain.StockReservation, error) {
+	rows, err := r.db.QueryContext(ctx, GET_RESERVATIONS_QUERY, id)
+	if err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+
+	reservations := []domain.StockReservation{}
+	for rows.Next() {
+		var res domain.StockReservation
+		var expiresAt sql.NullTime
+		if err := rows.Scan(&res.ID, &res.PurchaseOrderId, &res.ProductBatchId, &res.ProductId, &res.Quantity, &res.Status, &expiresAt, &res.CreatedAt); err != nil {
+			logging.Log(err)
+			return nil, ErrInternal
+		}
+		if expiresAt.Valid {
+			res.ExpiresAt = &expiresAt.Time
+		}
+		reservations = append(reservations, res)
+	}
+	if err := rows.Err(); err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	return reservations, nil
+}
+
+// ExpireReservations gives back to their batches the held reservations that lapsed before now and returns how many there were
+func (r *repository) ExpireReservations(ctx context.Context, now time.Time) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Log(err)
+		return 0, ErrInternal
+	}
+	defer tx.Rollback()
+
+	expired, err := settle(ctx, tx, domain.ReservationExpired, false, LOCK_LAPSED_RESERVATIONS_QUERY, now)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		logging.Log(err)
+		return 0, ErrInternal
+	}
+	return expired, nil
+}
+
+// applyReservations updates the reservations of the order to its new status.
+// Confirmed orders make their holds firm, or reserve the stock again if the holds lapsed,
+// cancelled orders give the stock back and shipped orders take it out of their batches and sections.
+func applyReservations(ctx context.Context, tx *sql.Tx, change domain.OrderStatusChange) error {
+	switch change.ToStatusId {
+	case domain.OrderStatusConfirmed:
+		res, err := tx.ExecContext(ctx, CONFIRM_RESERVATIONS_QUERY, change.PurchaseOrderId, change.ChangedAt)
+		if err != nil {
+			logging.Log(err)
+			return ErrInternal
+		}
+		confirmed, err := res.RowsAffected()
+		if err != nil {
+			logging.Log(err)
+			return ErrInternal
+		}
+		if confirmed > 0 {
+			return nil
+		}
+		if _, err := settle(ctx, tx, domain.ReservationExpired, false, LOCK_ORDER_RESERVATIONS_QUERY, change.PurchaseOrderId, domain.ReservationHeld, domain.ReservationHeld); err != nil {
+			return err
+		}
+		lines, err := orderLines(ctx, tx, change.PurchaseOrderId)
+		if err != nil {
+			return err
+		}
+		_, err = reserve(ctx, tx, change.PurchaseOrderId, lines, domain.ReservationConfirmed, nil, change.ChangedAt)
+		return err
+	case domain.OrderStatusCancelled:
+		_, err := settle(ctx, tx, domain.ReservationReleased, false, LOCK_ORDER_RESERVATIONS_QUERY, change.PurchaseOrderId, domain.ReservationHeld, domain.ReservationConfirmed)
+		return err
+	case domain.OrderStatusShipped:
+		_, err := settle(ctx, tx, domain.ReservationConsumed, true, LOCK_ORDER_RESERVATIONS_QUERY, change.PurchaseOrderId, domain.ReservationConfirmed, domain.ReservationConfirmed)
+		return err
+	}
+	return nil
+}
+
+// reserve allocates the quantity of every item in the batches of its product in first-expired-first-out order,
+// adds it to the reserved quantity of the batches and stores a reservation for every allocation
+func reserve(ctx context.Context, tx *sql.Tx, orderId int, items []domain.OrderItem, status string, expiresAt *time.Time, now time.Time) ([]domain.StockReservation, error) {
+	var reservations []domain.StockReservation
+	for _, item := range items {
+		rows, err := tx.QueryContext(ctx, LOCK_RESERVABLE_BATCHES_QUERY, item.ProductId)
+		if err != nil {
+			logging.Log(err)
+			return nil, ErrInternal
+		}
+		var allocations []domain.StockReservation
+		remaining := item.Quantity
+		for rows.Next() && remaining > 0 {
+			var batchId, available int
+			if err := rows.Scan(&batchId, &available); err != nil {
+				logging.Log(err)
+				_ = rows.Close()
+				return nil, ErrInternal
+			}
+			if available > remaining {
+				available = remaining
+			}
+			allocations = append(allocations, domain.StockReservation{PurchaseOrderId: orderId, ProductBatchId: batchId, ProductId: item.ProductId, Quantity: available, Status: status, ExpiresAt: expiresAt, CreatedAt: now})
+			remaining -= available
+		}
+		_ = rows.Close()
+		if remaining > 0 {
+			err := fmt.Errorf("%w: %d", ErrInsufficientStock, item.ProductId)
+			logging.Log(err)
+			return nil, err
+		}
+
+		for _, allocation := range allocations {
+			if _, err := tx.ExecContext(ctx, RESERVE_BATCH_QUERY, allocation.Quantity, allocation.ProductBatchId); err != nil {
+				logging.Log(err)
+				return nil, ErrInternal
+			}
+			res, err := tx.ExecContext(ctx, INSERT_RESERVATION_QUERY, orderId, allocation.ProductBatchId, allocation.Quantity, status, expiresAt, now)
+			if err != nil {
+				logging.Log(err)
+				return nil, ErrInternal
+			}
+			id, err := res.LastInsertId()
+			if err != nil {
+				logging.Log(err)
+				return nil, ErrInternal
+			}
+			allocation.ID = int(id)
+			reservations = append(reservations, allocation)
+		}
+	}
+	return reservations, nil
+}
+
+// settle locks the reservations returned by the query and moves them to the given status, taking their quantity out of
+// the reserved quantity of their batches. Consumed reservations also take it out of the current quantity of the batches
+// and their sections. It returns how many reservations were settled.
+func settle(ctx context.Context, tx *sql.Tx, status string, consume bool, query string, args ...interface{}) (int, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		logging.Log(err)
+		return 0, ErrInternal
+	}
+	type lockedReservation struct {
+		id, batchId, sectionId, quantity int
+	}
+	var locked []lockedReservation
+	for rows.Next() {
+		var l lockedReservation
+		if err := rows.Scan(&l.id, &l.batchId, &l.sectionId, &l.quantity); err != nil {
+			logging.Log(err)
+			_ = rows.Close()
+			return 0, ErrInternal
+		}
+		locked = append(locked, l)
+	}
+	_ = rows.Close()
+
+	for _, l := range locked {
+		if consume {
+			if _, err := tx.ExecContext(ctx, CONSUME_BATCH_QUERY, l.quantity, l.quantity, l.batchId); err != nil {
+				logging.Log(err)
+				return 0, ErrInternal
+			}
+			if _, err := tx.ExecContext(ctx, RELEASE_SECTION_CAPACITY_QUERY, l.quantity, l.sectionId); err != nil {
+				logging.Log(err)
+				return 0, ErrInternal
+			}
+		} else if _, err := tx.ExecContext(ctx, RELEASE_BATCH_QUERY, l.quantity, l.batchId); err != nil {
+			logging.Log(err)
+			return 0, ErrInternal
+		}
+		if _, err := tx.ExecContext(ctx, SET_RESERVATION_STATUS_QUERY, status, l.id); err != nil {
+			logging.Log(err)
+			return 0, ErrInternal
+		}
+	}
+	return len(locked), nil
+}
+
+// orderLines returns the product and quantity of every item of the order
+func orderLines(ctx context.Context, tx *sql.Tx, orderId int) ([]domain.OrderItem, error) {
+	rows, err := tx.QueryContext(ctx, GET_ORDER_LINES_QUERY, orderId)
+	if err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+
+	var lines []domain.OrderItem
+	for rows.Next() {
+		var line domain.OrderItem
+		if err := rows.Scan(&line.ProductId, &line.Quantity); err != nil {
+			logging.Log(err)
+			return nil, ErrInternal
+		}
+		lines = append(lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	return lines, nil
+}
@@ -2,6 +2,8 @@ package purchaseorders
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 )
@@ -11,19 +13,26 @@ type MockRepository struct {
 	DataOrdersByBuyers []domain.Purchase_orders_buyer
 	Err                error
 	ErrExist           error
+	Status             int
+	History            []domain.OrderStatusChange
+	ErrStatus          error
+	ErrUpdateStatus    error
+	LastChange         domain.OrderStatusChange
+	Details            []domain.PurchaseOrderDetail
+	Buyers             []int
+	LastFilter         domain.PurchaseOrderFilter
+	Items              []domain.OrderItem
+	Prices             []domain.ProductRecord
+	LastOrder          domain.Purchase_orders
+	ErrReserve         error
+	Reservations       []domain.StockReservation
+	ExpiredAt          time.Time
 }
 
 func (m *MockRepository) Exists(ctx context.Context, orderNumber string) bool {
 	return m.ErrExist != nil
 }
 
-func (m *MockRepository) SaveOrder(ctx context.Context, p domain.Purchase_orders) (int, error) {
-	if m.Err != nil {
-		return 0, m.Err
-	}
-	return p.ID, nil
-}
-
 func (m *MockRepository) GetByBuyerId(ctx context.Context, buyerId int) ([]domain.Purchase_orders_buyer, error) {
 	var result []domain.Purchase_orders_buyer
 	if m.Err != nil {
@@ -47,3 +56,139 @@ func (m *MockRepository) GetAllByBuyer(ctx context.Context) ([]domain.Purchase_o
 	}
 	return result, nil
 }
+
+func (m *MockRepository) GetStatus(ctx context.Context, id int) (int, error) {
+	if m.ErrStatus != nil {
+		return 0, m.ErrStatus
+	}
+	return m.Status, nil
+}
+
+func (m *MockRepository) UpdateStatus(ctx context.Context, change domain.OrderStatusChange) (int, error) {
+	if m.ErrUpdateStatus != nil {
+		return 0, m.ErrUpdateStatus
+	}
+	m.Status = change.ToStatusId
+	m.LastChange = change
+	m.History = append(m.History, change)
+	return len(m.History), nil
+}
+
+func (m *MockRepository) UpdateStatusTx(ctx context.Context, tx *sql.Tx, change domain.OrderStatusChange) (int, error) {
+	return m.UpdateStatus(ctx, change)
+}
+
+func (m *MockRepository) GetStatusHistory(ctx context.Context, id int) ([]domain.OrderStatusChange, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.History, nil
+}
+
+func (m *MockRepository) Get(ctx context.Context, id int) (domain.PurchaseOrderDetail, error) {
+	if m.Err != nil {
+		return domain.PurchaseOrderDetail{}, m.Err
+	}
+	for _, o := range m.Details {
+		if o.ID == id {
+			return o, nil
+		}
+	}
+	return domain.PurchaseOrderDetail{}, ErrNotFound
+}
+
+func (m *MockRepository) GetAll(ctx context.Context, filter domain.PurchaseOrderFilter) ([]domain.PurchaseOrderDetail, error) {
+	m.LastFilter = filter
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Details, nil
+}
+
+func (m *MockRepository) ExistsBuyer(ctx context.Context, buyerId int) bool {
+	for _, id := range m.Buyers {
+		if id == buyerId {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MockRepository) SaveOrder(ctx context.Context, p domain.Purchase_orders, now time.Time, holdUntil time.Time) (domain.Purchase_orders, error) {
+	if m.Err != nil {
+		return domain.Purchase_orders{}, m.Err
+	}
+	if m.ErrReserve != nil {
+		return domain.Purchase_orders{}, m.ErrReserve
+	}
+	p.ID = len(m.Data) + 1
+	for i := range p.Items {
+		p.Items[i].ID = i + 1
+		p.Items[i].PurchaseOrderId = p.ID
+		p.Reservations = append(p.Reservations, domain.StockReservation{
+			ID:              i + 1,
+			PurchaseOrderId: p.ID,
+			ProductId:       p.Items[i].ProductId,
+			Quantity:        p.Items[i].Quantity,
+			Status:          domain.ReservationHeld,
+			ExpiresAt:       &holdUntil,
+			CreatedAt:       now,
+		})
+	}
+	m.LastOrder = p
+	return p, nil
+}
+
+func (m *MockRepository) GetItems(ctx context.Context, orderIds []int) ([]domain.OrderItem, error) {
+	var result []domain.OrderItem
+	for _, item := range m.Items {
+		for _, id := range orderIds {
+			if item.PurchaseOrderId == id {
+				result = append(result, item)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (m *MockRepository) GetPrices(ctx context.Context, productIds []int, at time.Time) ([]domain.ProductRecord, error) {
+	return m.Prices, nil
+}
+
+func (m *MockRepository) GetProductRecord(ctx context.Context, id int) (domain.ProductRecord, error) {
+	for _, record := range m.Prices {
+		if record.ID == id {
+			return record, nil
+		}
+	}
+	return domain.ProductRecord{ID: id, ProductID: id}, nil
+}
+
+func (m *MockRepository) GetReservations(ctx context.Context, id int) ([]domain.StockReservation, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Reservations, nil
+}
+
+func (m *MockRepository) ExpireReservations(ctx context.Context, now time.Time) (int, error) {
+	if m.Err != nil {
+		return 0, m.Err
+	}
+	m.ExpiredAt = now
+	return len(m.Reservations), nil
+}
+
+type MockRecords struct {
+	Records []domain.ProductRecord
+	Err     error
+	LastIDs []int
+}
+
+func (m *MockRecords) GetEffectiveAll(ctx context.Context, productIDs []int, at time.Time) ([]domain.ProductRecord, error) {
+	m.LastIDs = productIDs
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Records, nil
+}
@@ -3,6 +3,7 @@ package purchaseorders
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"regexp"
 	"testing"
@@ -25,7 +26,9 @@ func TestSaveOrderSuccess(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectPrepare(regexp.QuoteMeta(INSERT_ORDER_QUERY)).ExpectExec().WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_ORDER_QUERY)).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 	orderId := 1
 
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
@@ -39,21 +42,21 @@ func TestSaveOrderSuccess(t *testing.T) {
 		ProductRecordId: 5,
 		OrderStatusId:   1,
 	}
-	repo := NewRepository(db)
-	o, err := repo.SaveOrder(ctx, order)
+	repo := NewRepository(db, &MockRecords{})
+	o, err := repo.SaveOrder(ctx, order, time.Now(), time.Now().Add(DefaultReservationTTL))
 	assert.NoError(t, err)
 	assert.NotZero(t, o)
-	assert.Equal(t, o, orderId)
+	assert.Equal(t, orderId, o.ID)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-// TestSaveOrderFailPrepare passes when return an error for Internal Server Error
-func TestSaveOrderFailPrepare(t *testing.T) {
+// TestSaveOrderFailBegin passes when return an error for Internal Server Error
+func TestSaveOrderFailBegin(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 	orderId := 1
-	mock.ExpectPrepare(regexp.QuoteMeta(INSERT_ORDER_QUERY)).WillReturnError(ErrInternal)
+	mock.ExpectBegin().WillReturnError(ErrInternal)
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
 	defer cancel()
 	order := domain.Purchase_orders{
@@ -65,8 +68,8 @@ func TestSaveOrderFailPrepare(t *testing.T) {
 		ProductRecordId: 5,
 		OrderStatusId:   1,
 	}
-	repo := NewRepository(db)
-	o, err := repo.SaveOrder(ctx, order)
+	repo := NewRepository(db, &MockRecords{})
+	o, err := repo.SaveOrder(ctx, order, time.Now(), time.Now().Add(DefaultReservationTTL))
 
 	assert.Empty(t, o)
 	assert.EqualError(t, ErrInternal, err.Error())
@@ -79,7 +82,9 @@ func TestSaveOrderFailInternalError(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 	orderId := 1
-	mock.ExpectPrepare(regexp.QuoteMeta(INSERT_ORDER_QUERY)).ExpectExec().WillReturnError(ErrInternal)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_ORDER_QUERY)).WillReturnError(ErrInternal)
+	mock.ExpectRollback()
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
 	defer cancel()
 	order := domain.Purchase_orders{
@@ -91,8 +96,8 @@ func TestSaveOrderFailInternalError(t *testing.T) {
 		ProductRecordId: 5,
 		OrderStatusId:   1,
 	}
-	repo := NewRepository(db)
-	o, err := repo.SaveOrder(ctx, order)
+	repo := NewRepository(db, &MockRecords{})
+	o, err := repo.SaveOrder(ctx, order, time.Now(), time.Now().Add(DefaultReservationTTL))
 
 	assert.Empty(t, o)
 	assert.EqualError(t, ErrInternal, err.Error())
@@ -105,7 +110,9 @@ func TestSaveOrderFailExecMySQLFK(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 	orderId := 1
-	mock.ExpectPrepare(regexp.QuoteMeta(INSERT_ORDER_QUERY)).ExpectExec().WillReturnError(&mysql.MySQLError{Number: MySqlNumberFKConstraint})
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_ORDER_QUERY)).WillReturnError(&mysql.MySQLError{Number: MySqlNumberFKConstraint})
+	mock.ExpectRollback()
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
 	defer cancel()
 	order := domain.Purchase_orders{
@@ -117,8 +124,8 @@ func TestSaveOrderFailExecMySQLFK(t *testing.T) {
 		ProductRecordId: 5,
 		OrderStatusId:   1,
 	}
-	repo := NewRepository(db)
-	o, err := repo.SaveOrder(ctx, order)
+	repo := NewRepository(db, &MockRecords{})
+	o, err := repo.SaveOrder(ctx, order, time.Now(), time.Now().Add(DefaultReservationTTL))
 
 	assert.Empty(t, o)
 	assert.EqualError(t, ErrFKConstraint, err.Error())
@@ -131,7 +138,9 @@ func TestSaveOrderFailExecMySQLDataLong(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 	orderId := 1
-	mock.ExpectPrepare(regexp.QuoteMeta(INSERT_ORDER_QUERY)).ExpectExec().WillReturnError(&mysql.MySQLError{Number: MySqlNumberDataLong})
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_ORDER_QUERY)).WillReturnError(&mysql.MySQLError{Number: MySqlNumberDataLong})
+	mock.ExpectRollback()
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
 	defer cancel()
 	order := domain.Purchase_orders{
@@ -143,8 +152,8 @@ func TestSaveOrderFailExecMySQLDataLong(t *testing.T) {
 		ProductRecordId: 5,
 		OrderStatusId:   1,
 	}
-	repo := NewRepository(db)
-	o, err := repo.SaveOrder(ctx, order)
+	repo := NewRepository(db, &MockRecords{})
+	o, err := repo.SaveOrder(ctx, order, time.Now(), time.Now().Add(DefaultReservationTTL))
 
 	assert.Empty(t, o)
 	assert.EqualError(t, ErrDataLong, err.Error())
@@ -157,7 +166,9 @@ func TestSaveOrderFailExecMySQLAlreadyExists(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 	orderId := 1
-	mock.ExpectPrepare(regexp.QuoteMeta(INSERT_ORDER_QUERY)).ExpectExec().WillReturnError(&mysql.MySQLError{Number: MySqlNumberDuplicate})
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_ORDER_QUERY)).WillReturnError(&mysql.MySQLError{Number: MySqlNumberDuplicate})
+	mock.ExpectRollback()
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
 	defer cancel()
 	order := domain.Purchase_orders{
@@ -169,8 +180,8 @@ func TestSaveOrderFailExecMySQLAlreadyExists(t *testing.T) {
 		ProductRecordId: 5,
 		OrderStatusId:   1,
 	}
-	repo := NewRepository(db)
-	o, err := repo.SaveOrder(ctx, order)
+	repo := NewRepository(db, &MockRecords{})
+	o, err := repo.SaveOrder(ctx, order, time.Now(), time.Now().Add(DefaultReservationTTL))
 
 	assert.Empty(t, o)
 	assert.EqualError(t, ErrAlreadyExists, err.Error())
@@ -183,7 +194,9 @@ func TestSaveOrderFailLastID(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 	orderId := 1
-	mock.ExpectPrepare(regexp.QuoteMeta(INSERT_ORDER_QUERY)).ExpectExec().WillReturnResult(sqlmock.NewErrorResult(ErrInternal))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_ORDER_QUERY)).WillReturnResult(sqlmock.NewErrorResult(ErrInternal))
+	mock.ExpectRollback()
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
 	defer cancel()
 	order := domain.Purchase_orders{
@@ -195,8 +208,8 @@ func TestSaveOrderFailLastID(t *testing.T) {
 		ProductRecordId: 5,
 		OrderStatusId:   1,
 	}
-	repo := NewRepository(db)
-	o, err := repo.SaveOrder(ctx, order)
+	repo := NewRepository(db, &MockRecords{})
+	o, err := repo.SaveOrder(ctx, order, time.Now(), time.Now().Add(DefaultReservationTTL))
 
 	assert.Empty(t, o)
 	assert.EqualError(t, ErrInternal, err.Error())
@@ -218,7 +231,7 @@ func TestExistsSuccess(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
 	defer cancel()
 
-	repo := NewRepository(db)
+	repo := NewRepository(db, &MockRecords{})
 	ok := repo.Exists(ctx, orderNumber)
 
 	assert.NotEmpty(t, ok)
@@ -236,7 +249,7 @@ func TestExistsFail(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
 	defer cancel()
 
-	repo := NewRepository(db)
+	repo := NewRepository(db, &MockRecords{})
 	ok := repo.Exists(ctx, orderNumber)
 
 	assert.Equal(t, false, ok)
@@ -263,7 +276,7 @@ func TestGetByBuyerIdSuccess(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
 	defer cancel()
 
-	repo := NewRepository(db)
+	repo := NewRepository(db, &MockRecords{})
 	countList, err := repo.GetByBuyerId(ctx, buyerId)
 
 	assert.NoError(t, err)
@@ -285,7 +298,7 @@ func TestGetByBuyerIdFailInternal(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
 	defer cancel()
 
-	repo := NewRepository(db)
+	repo := NewRepository(db, &MockRecords{})
 	countList, err := repo.GetByBuyerId(ctx, buyerId)
 
 	assert.Empty(t, countList)
@@ -304,7 +317,7 @@ func TestGetByBuyerIdFailNoRows(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
 	defer cancel()
 
-	repo := NewRepository(db)
+	repo := NewRepository(db, &MockRecords{})
 	countList, err := repo.GetByBuyerId(ctx, buyerId)
 	assert.Empty(t, countList)
 	assert.EqualError(t, ErrNotFound, err.Error())
@@ -322,7 +335,7 @@ func TestGetByBuyerIdFailInternalError(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
 	defer cancel()
 
-	repo := NewRepository(db)
+	repo := NewRepository(db, &MockRecords{})
 	countList, err := repo.GetByBuyerId(ctx, buyerId)
 	fmt.Println("Debugger Agus 8")
 	assert.Empty(t, countList)
@@ -350,7 +363,7 @@ func TestGetAllByBuyerSuccess(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
 	defer cancel()
 
-	repo := NewRepository(db)
+	repo := NewRepository(db, &MockRecords{})
 	countList, err := repo.GetAllByBuyer(ctx)
 
 	assert.NoError(t, err)
@@ -368,7 +381,7 @@ func TestGetAllByBuyerFailQuery(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
 	defer cancel()
 
-	repo := NewRepository(db)
+	repo := NewRepository(db, &MockRecords{})
 	countList, err := repo.GetAllByBuyer(ctx)
 
 	assert.Empty(t, countList)
@@ -390,10 +403,443 @@ func TestGetAllByBuyerFailScan(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
 	defer cancel()
 
-	repo := NewRepository(db)
+	repo := NewRepository(db, &MockRecords{})
 	countList, err := repo.GetAllByBuyer(ctx)
 
 	assert.Empty(t, countList)
 	assert.EqualError(t, ErrInternal, err.Error())
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+// TestGetStatusSuccess passes when return the status of the order
+func TestGetStatusSuccess(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(GET_ORDER_STATUS_QUERY)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"order_status_id"}).AddRow(domain.OrderStatusPicking))
+
+	status, err := NewRepository(db, &MockRecords{}).GetStatus(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusPicking, status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestGetStatusNotFound passes when the order does not exist
+func TestGetStatusNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(GET_ORDER_STATUS_QUERY)).WithArgs(1).WillReturnError(sql.ErrNoRows)
+
+	_, err = NewRepository(db, &MockRecords{}).GetStatus(context.Background(), 1)
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestUpdateStatusSuccess passes when the status and its history are stored in a transaction
+func TestUpdateStatusSuccess(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	changedAt := time.Date(2022, 10, 10, 12, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(UPDATE_ORDER_STATUS_QUERY)).WithArgs(domain.OrderStatusPicking, 1, domain.OrderStatusConfirmed).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_STATUS_HISTORY_QUERY)).WithArgs(1, domain.OrderStatusConfirmed, domain.OrderStatusPicking, "jdoe", changedAt).WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectCommit()
+
+	id, err := NewRepository(db, &MockRecords{}).UpdateStatus(context.Background(), domain.OrderStatusChange{
+		PurchaseOrderId: 1,
+		FromStatusId:    domain.OrderStatusConfirmed,
+		ToStatusId:      domain.OrderStatusPicking,
+		Actor:           "jdoe",
+		ChangedAt:       changedAt,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestUpdateStatusChanged passes when the order left the expected status before the update
+func TestUpdateStatusChanged(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(UPDATE_ORDER_STATUS_QUERY)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	_, err = NewRepository(db, &MockRecords{}).UpdateStatus(context.Background(), domain.OrderStatusChange{PurchaseOrderId: 1, FromStatusId: domain.OrderStatusPending, ToStatusId: domain.OrderStatusConfirmed})
+
+	assert.ErrorIs(t, err, ErrStatusChanged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestGetStatusHistorySuccess passes when return the status changes of the order
+func TestGetStatusHistorySuccess(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	changedAt := time.Date(2022, 10, 10, 12, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "purchase_order_id", "from_status_id", "to_status_id", "actor", "changed_at"}).
+		AddRow(1, 1, domain.OrderStatusPending, domain.OrderStatusConfirmed, "jdoe", changedAt)
+	mock.ExpectQuery(regexp.QuoteMeta(GET_STATUS_HISTORY_QUERY)).WithArgs(1).WillReturnRows(rows)
+
+	history, err := NewRepository(db, &MockRecords{}).GetStatusHistory(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.OrderStatusChange{{ID: 1, PurchaseOrderId: 1, FromStatusId: domain.OrderStatusPending, ToStatusId: domain.OrderStatusConfirmed, Actor: "jdoe", ChangedAt: changedAt}}, history)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+var orderDetailColumns = []string{"id", "order_number", "order_date", "tracking_code", "order_status_id",
+	"buyer_id", "card_number_id", "first_name", "last_name",
+	"product_record_id", "last_update_date", "purchase_price", "sale_price", "product_id"}
+
+// TestGetOrderDetailSuccess passes when return the order joined with its buyer and product record
+func TestGetOrderDetailSuccess(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	updated := time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows(orderDetailColumns).AddRow(1, "002", "2022-10-10", "asd233501", 4, 3, "402323", "Jhon", "Doe", 4, updated, 10, 15.5, 2)
+	mock.ExpectQuery(regexp.QuoteMeta(GET_ORDER_DETAIL_QUERY)).WithArgs(1).WillReturnRows(rows)
+
+	o, err := NewRepository(db, &MockRecords{}).Get(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Jhon", o.Buyer.FirstName)
+	assert.Equal(t, float32(15.5), o.ProductRecord.SalePrice)
+	assert.Equal(t, updated, o.ProductRecord.LastUpdateDate.Time)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestGetOrderDetailNotFound passes when the order does not exist
+func TestGetOrderDetailNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(GET_ORDER_DETAIL_QUERY)).WithArgs(1).WillReturnRows(sqlmock.NewRows(orderDetailColumns))
+
+	_, err = NewRepository(db, &MockRecords{}).Get(context.Background(), 1)
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestGetAllOrderDetailsFiltered passes when every filter is sent to the query
+func TestGetAllOrderDetailsFiltered(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	query := GET_ORDER_DETAILS_QUERY + " WHERE po.buyer_id = ? AND po.order_status_id = ? AND po.order_date >= ? AND po.order_date <= ? ORDER BY po.order_date DESC, po.id DESC;"
+	rows := sqlmock.NewRows(orderDetailColumns).AddRow(1, "002", "2022-10-10", "asd233501", 2, 3, "402323", "Jhon", "Doe", 4, nil, 0, 0, 2)
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(3, 2, "2022-10-01", "2022-10-31").WillReturnRows(rows)
+
+	orders, err := NewRepository(db, &MockRecords{}).GetAll(context.Background(), domain.PurchaseOrderFilter{BuyerId: 3, StatusId: 2, From: "2022-10-01", To: "2022-10-31"})
+
+	assert.NoError(t, err)
+	assert.Len(t, orders, 1)
+	assert.True(t, orders[0].ProductRecord.LastUpdateDate.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestGetAllOrderDetailsFailQuery passes when return an error
+func TestGetAllOrderDetailsFailQuery(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(GET_ORDER_DETAILS_QUERY)).WillReturnError(ErrInternal)
+
+	orders, err := NewRepository(db, &MockRecords{}).GetAll(context.Background(), domain.PurchaseOrderFilter{})
+
+	assert.Empty(t, orders)
+	assert.ErrorIs(t, err, ErrInternal)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestSaveOrderWithItemsSuccess passes when the order, its items and their held stock are stored in a transaction
+func TestSaveOrderWithItemsSuccess(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	now := time.Date(2022, 10, 10, 12, 0, 0, 0, time.UTC)
+	holdUntil := now.Add(DefaultReservationTTL)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_ORDER_QUERY)).WithArgs("001", "2022-10-10", "abc", 2, nil, domain.OrderStatusPending).WillReturnResult(sqlmock.NewResult(5, 1))
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_ORDER_ITEM_QUERY)).WithArgs(5, 1, 7, 3, 2.5).WillReturnResult(sqlmock.NewResult(11, 1))
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_ORDER_ITEM_QUERY)).WithArgs(5, 2, 8, 1, 10.0).WillReturnResult(sqlmock.NewResult(12, 1))
+	// product 1 is taken from two batches, the one closest to expire first
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_RESERVABLE_BATCHES_QUERY)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "available"}).AddRow(20, 2).AddRow(21, 10))
+	mock.ExpectExec(regexp.QuoteMeta(RESERVE_BATCH_QUERY)).WithArgs(2, 20).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_RESERVATION_QUERY)).WithArgs(5, 20, 2, domain.ReservationHeld, &holdUntil, now).WillReturnResult(sqlmock.NewResult(31, 1))
+	mock.ExpectExec(regexp.QuoteMeta(RESERVE_BATCH_QUERY)).WithArgs(1, 21).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_RESERVATION_QUERY)).WithArgs(5, 21, 1, domain.ReservationHeld, &holdUntil, now).WillReturnResult(sqlmock.NewResult(32, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_RESERVABLE_BATCHES_QUERY)).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id", "available"}).AddRow(22, 1))
+	mock.ExpectExec(regexp.QuoteMeta(RESERVE_BATCH_QUERY)).WithArgs(1, 22).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_RESERVATION_QUERY)).WithArgs(5, 22, 1, domain.ReservationHeld, &holdUntil, now).WillReturnResult(sqlmock.NewResult(33, 1))
+	mock.ExpectCommit()
+
+	order := domain.Purchase_orders{
+		OrderNumber:   "001",
+		OrderDate:     "2022-10-10",
+		TrackingCode:  "abc",
+		BuyerId:       2,
+		OrderStatusId: domain.OrderStatusPending,
+		Items: []domain.OrderItem{
+			{ProductId: 1, ProductRecordId: 7, Quantity: 3, UnitPrice: 2.5},
+			{ProductId: 2, ProductRecordId: 8, Quantity: 1, UnitPrice: 10},
+		},
+	}
+	saved, err := NewRepository(db, &MockRecords{}).SaveOrder(context.Background(), order, now, holdUntil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, saved.ID)
+	assert.Equal(t, 12, saved.Items[1].ID)
+	assert.Equal(t, 5, saved.Items[1].PurchaseOrderId)
+	assert.Len(t, saved.Reservations, 3)
+	assert.Equal(t, 31, saved.Reservations[0].ID)
+	assert.Equal(t, 2, saved.Reservations[0].Quantity)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestSaveOrderWithItemsRollback passes when a failing item rolls back the whole order
+func TestSaveOrderWithItemsRollback(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_ORDER_QUERY)).WillReturnResult(sqlmock.NewResult(5, 1))
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_ORDER_ITEM_QUERY)).WillReturnError(&mysql.MySQLError{Number: MySqlNumberFKConstraint})
+	mock.ExpectRollback()
+
+	order := domain.Purchase_orders{OrderNumber: "001", Items: []domain.OrderItem{{ProductId: 99, ProductRecordId: 7, Quantity: 1, UnitPrice: 1}}}
+	_, err = NewRepository(db, &MockRecords{}).SaveOrder(context.Background(), order, time.Now(), time.Now())
+
+	assert.ErrorIs(t, err, ErrFKConstraint)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestGetItemsSuccess passes when return the items of every requested order
+func TestGetItemsSuccess(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "purchase_order_id", "product_id", "product_record_id", "quantity", "unit_price"}).
+		AddRow(1, 1, 4, 7, 2, 3.5).
+		AddRow(2, 2, 5, 8, 1, 10)
+	mock.ExpectQuery(regexp.QuoteMeta(GET_ORDER_ITEMS_QUERY+"(?, ?) ORDER BY purchase_order_id, id;")).WithArgs(1, 2).WillReturnRows(rows)
+
+	items, err := NewRepository(db, &MockRecords{}).GetItems(context.Background(), []int{1, 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.OrderItem{ID: 1, PurchaseOrderId: 1, ProductId: 4, ProductRecordId: 7, Quantity: 2, UnitPrice: 3.5}, items[0])
+	assert.Len(t, items, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestGetPricesSuccess passes when return the record in effect of every product at the given date
+func TestGetPricesSuccess(t *testing.T) {
+	db, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	records := &MockRecords{Records: []domain.ProductRecord{{ID: 7, ProductID: 1, SalePrice: 2.5}}}
+	prices, err := NewRepository(db, records).GetPrices(context.Background(), []int{1, 2}, time.Date(2022, 10, 10, 15, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, records.LastIDs)
+	assert.Equal(t, []domain.ProductRecord{{ID: 7, ProductID: 1, SalePrice: 2.5}}, prices)
+}
+
+// TestGetPricesInternalError passes when the records cannot be looked up
+func TestGetPricesInternalError(t *testing.T) {
+	db, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	_, err = NewRepository(db, &MockRecords{Err: errors.New("connection lost")}).GetPrices(context.Background(), []int{1}, time.Now())
+
+	assert.ErrorIs(t, err, ErrInternal)
+}
+
+// TestSaveOrderInsufficientStock passes when the order is not stored because the stock of an item cannot be held
+func TestSaveOrderInsufficientStock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_ORDER_QUERY)).WillReturnResult(sqlmock.NewResult(5, 1))
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_ORDER_ITEM_QUERY)).WillReturnResult(sqlmock.NewResult(11, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_RESERVABLE_BATCHES_QUERY)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "available"}).AddRow(20, 2))
+	mock.ExpectRollback()
+
+	order := domain.Purchase_orders{OrderNumber: "001", Items: []domain.OrderItem{{ProductId: 1, ProductRecordId: 7, Quantity: 3, UnitPrice: 1}}}
+	_, err = NewRepository(db, &MockRecords{}).SaveOrder(context.Background(), order, time.Now(), time.Now())
+
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+var lockedReservationColumns = []string{"id", "product_batch_id", "section_id", "quantity"}
+
+// TestUpdateStatusConfirmHeld passes when confirming an order makes its held reservations firm
+func TestUpdateStatusConfirmHeld(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	changedAt := time.Date(2022, 10, 10, 12, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(UPDATE_ORDER_STATUS_QUERY)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(CONFIRM_RESERVATIONS_QUERY)).WithArgs(1, changedAt).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_STATUS_HISTORY_QUERY)).WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectCommit()
+
+	_, err = NewRepository(db, &MockRecords{}).UpdateStatus(context.Background(), domain.OrderStatusChange{PurchaseOrderId: 1, FromStatusId: domain.OrderStatusPending, ToStatusId: domain.OrderStatusConfirmed, ChangedAt: changedAt})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestUpdateStatusConfirmLapsed passes when confirming an order whose holds lapsed reserves its stock again
+func TestUpdateStatusConfirmLapsed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	changedAt := time.Date(2022, 10, 10, 12, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(UPDATE_ORDER_STATUS_QUERY)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(CONFIRM_RESERVATIONS_QUERY)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_ORDER_RESERVATIONS_QUERY)).WithArgs(1, domain.ReservationHeld, domain.ReservationHeld).WillReturnRows(sqlmock.NewRows(lockedReservationColumns).AddRow(30, 20, 3, 4))
+	mock.ExpectExec(regexp.QuoteMeta(RELEASE_BATCH_QUERY)).WithArgs(4, 20).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(SET_RESERVATION_STATUS_QUERY)).WithArgs(domain.ReservationExpired, 30).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(GET_ORDER_LINES_QUERY)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"product_id", "quantity"}).AddRow(9, 4))
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_RESERVABLE_BATCHES_QUERY)).WithArgs(9).WillReturnRows(sqlmock.NewRows([]string{"id", "available"}).AddRow(20, 6))
+	mock.ExpectExec(regexp.QuoteMeta(RESERVE_BATCH_QUERY)).WithArgs(4, 20).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_RESERVATION_QUERY)).WithArgs(1, 20, 4, domain.ReservationConfirmed, nil, changedAt).WillReturnResult(sqlmock.NewResult(31, 1))
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_STATUS_HISTORY_QUERY)).WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectCommit()
+
+	_, err = NewRepository(db, &MockRecords{}).UpdateStatus(context.Background(), domain.OrderStatusChange{PurchaseOrderId: 1, FromStatusId: domain.OrderStatusPending, ToStatusId: domain.OrderStatusConfirmed, ChangedAt: changedAt})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestUpdateStatusConfirmWithoutStock passes when the order cannot be confirmed because its stock is gone
+func TestUpdateStatusConfirmWithoutStock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(UPDATE_ORDER_STATUS_QUERY)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(CONFIRM_RESERVATIONS_QUERY)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_ORDER_RESERVATIONS_QUERY)).WillReturnRows(sqlmock.NewRows(lockedReservationColumns))
+	mock.ExpectQuery(regexp.QuoteMeta(GET_ORDER_LINES_QUERY)).WillReturnRows(sqlmock.NewRows([]string{"product_id", "quantity"}).AddRow(9, 4))
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_RESERVABLE_BATCHES_QUERY)).WillReturnRows(sqlmock.NewRows([]string{"id", "available"}))
+	mock.ExpectRollback()
+
+	_, err = NewRepository(db, &MockRecords{}).UpdateStatus(context.Background(), domain.OrderStatusChange{PurchaseOrderId: 1, FromStatusId: domain.OrderStatusPending, ToStatusId: domain.OrderStatusConfirmed})
+
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestUpdateStatusCancelReleases passes when cancelling an order gives its reserved stock back
+func TestUpdateStatusCancelReleases(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(UPDATE_ORDER_STATUS_QUERY)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_ORDER_RESERVATIONS_QUERY)).WithArgs(1, domain.ReservationHeld, domain.ReservationConfirmed).WillReturnRows(sqlmock.NewRows(lockedReservationColumns).AddRow(30, 20, 3, 4))
+	mock.ExpectExec(regexp.QuoteMeta(RELEASE_BATCH_QUERY)).WithArgs(4, 20).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(SET_RESERVATION_STATUS_QUERY)).WithArgs(domain.ReservationReleased, 30).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_STATUS_HISTORY_QUERY)).WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectCommit()
+
+	_, err = NewRepository(db, &MockRecords{}).UpdateStatus(context.Background(), domain.OrderStatusChange{PurchaseOrderId: 1, FromStatusId: domain.OrderStatusConfirmed, ToStatusId: domain.OrderStatusCancelled})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestUpdateStatusShipConsumes passes when shipping an order takes its reserved stock out of the batches and sections
+func TestUpdateStatusShipConsumes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(UPDATE_ORDER_STATUS_QUERY)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_ORDER_RESERVATIONS_QUERY)).WithArgs(1, domain.ReservationConfirmed, domain.ReservationConfirmed).WillReturnRows(sqlmock.NewRows(lockedReservationColumns).AddRow(30, 20, 3, 4))
+	mock.ExpectExec(regexp.QuoteMeta(CONSUME_BATCH_QUERY)).WithArgs(4, 4, 20).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(RELEASE_SECTION_CAPACITY_QUERY)).WithArgs(4, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(SET_RESERVATION_STATUS_QUERY)).WithArgs(domain.ReservationConsumed, 30).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_STATUS_HISTORY_QUERY)).WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectCommit()
+
+	_, err = NewRepository(db, &MockRecords{}).UpdateStatus(context.Background(), domain.OrderStatusChange{PurchaseOrderId: 1, FromStatusId: domain.OrderStatusPicking, ToStatusId: domain.OrderStatusShipped})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestExpireReservationsSuccess passes when the lapsed holds are given back to their batches
+func TestExpireReservationsSuccess(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	now := time.Date(2022, 10, 10, 12, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_LAPSED_RESERVATIONS_QUERY)).WithArgs(now).WillReturnRows(sqlmock.NewRows(lockedReservationColumns).AddRow(30, 20, 3, 4).AddRow(31, 21, 3, 1))
+	mock.ExpectExec(regexp.QuoteMeta(RELEASE_BATCH_QUERY)).WithArgs(4, 20).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(SET_RESERVATION_STATUS_QUERY)).WithArgs(domain.ReservationExpired, 30).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(RELEASE_BATCH_QUERY)).WithArgs(1, 21).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(SET_RESERVATION_STATUS_QUERY)).WithArgs(domain.ReservationExpired, 31).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	expired, err := NewRepository(db, &MockRecords{}).ExpireReservations(context.Background(), now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, expired)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestGetReservationsSuccess passes when return the reservations of the order
+func TestGetReservationsSuccess(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	created := time.Date(2022, 10, 10, 12, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "purchase_order_id", "product_batch_id", "product_id", "quantity", "status", "expires_at", "created_at"}).
+		AddRow(30, 1, 20, 9, 4, domain.ReservationConfirmed, nil, created)
+	mock.ExpectQuery(regexp.QuoteMeta(GET_RESERVATIONS_QUERY)).WithArgs(1).WillReturnRows(rows)
+
+	reservations, err := NewRepository(db, &MockRecords{}).GetReservations(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.StockReservation{{ID: 30, PurchaseOrderId: 1, ProductBatchId: 20, ProductId: 9, Quantity: 4, Status: domain.ReservationConfirmed, CreatedAt: created}}, reservations)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,33 +2,91 @@ package purchaseorders
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
 )
 
+const (
+	// DefaultReservationTTL is how long the stock of a new order is held when no other TTL is configured
+	DefaultReservationTTL = 30 * time.Minute
+)
+
+var (
+	ErrInvalidStatus     = errors.New("invalid order status")
+	ErrInitialStatus     = errors.New("purchase orders must be created as pending")
+	ErrInvalidTransition = errors.New("invalid order status transition")
+	ErrInvalidDate       = errors.New("from and to must be yyyy-mm-dd dates")
+	ErrBuyerNotFound     = errors.New("buyer not found")
+	ErrNoItems           = errors.New("an order needs items or a product_record_id")
+	ErrItemsAndRecord    = errors.New("an order takes either items or a product_record_id, not both")
+	ErrInvalidItems      = errors.New("every item needs a product_id and a positive quantity, and a product can only appear once")
+	ErrPriceNotFound     = errors.New("product has no price")
+	ErrPriceNotEffective = errors.New("product record is a scheduled price change that is not effective yet")
+)
+
 type Service interface {
 	SaveOrder(ctx context.Context, p domain.Purchase_orders) (domain.Purchase_orders, error)
 	Exists(ctx context.Context, orderID string) bool
 	GetAllByBuyer(ctx context.Context, orderId int) ([]domain.Purchase_orders_buyer, error)
+	UpdateStatus(ctx context.Context, id int, status string, actor string) (domain.OrderStatusChange, error)
+	GetStatusHistory(ctx context.Context, id int) ([]domain.OrderStatusChange, error)
+	Get(ctx context.Context, id int) (domain.PurchaseOrderDetail, error)
+	GetAll(ctx context.Context, filter domain.PurchaseOrderFilter, status string) ([]domain.PurchaseOrderDetail, error)
+	GetByBuyer(ctx context.Context, buyerId int) ([]domain.PurchaseOrderDetail, error)
+	// GetReservations returns the stock reservations of the purchase order
+	GetReservations(ctx context.Context, id int) ([]domain.StockReservation, error)
+	// ExpireReservations gives back the stock held for orders that were not confirmed in time
+	ExpireReservations(ctx context.Context) (int, error)
 }
 
 type service struct {
-	repository Repository
+	repository     Repository
+	now            func() time.Time
+	reservationTTL time.Duration
 }
 
-func NewService(r Repository) Service {
+// NewService returns a service that holds the stock of new orders for reservationTTL, or DefaultReservationTTL if it is not positive
+func NewService(r Repository, reservationTTL time.Duration) Service {
+	if reservationTTL <= 0 {
+		reservationTTL = DefaultReservationTTL
+	}
 	return &service{
-		repository: r,
+		repository:     r,
+		now:            time.Now,
+		reservationTTL: reservationTTL,
 	}
 }
 
 // Save returns the created a purchase_order if successful, or a error if it failed
-// if the purchase_order is already exist, a error is returned
+// if the purchase_order is already exist, a error is returned.
+// Orders with items are priced with the current sale price of every product, orders with a product_record_id
+// get a single item of its product at its price, an order with both is rejected. The order is stored with all its items and the stock of every item
+// is held until the reservation TTL ends, or nothing is stored at all.
 func (s *service) SaveOrder(ctx context.Context, p domain.Purchase_orders) (domain.Purchase_orders, error) {
 	if s.repository.Exists(ctx, p.OrderNumber) {
 		return domain.Purchase_orders{}, ErrAlreadyExists
 	}
+	if p.OrderStatusId == 0 {
+		p.OrderStatusId = domain.OrderStatusPending
+	}
+	if p.OrderStatusId != domain.OrderStatusPending {
+		logging.Log(ErrInitialStatus)
+		return domain.Purchase_orders{}, ErrInitialStatus
+	}
+
+	if len(p.Items) == 0 && p.ProductRecordId == 0 {
+		logging.Log(ErrNoItems)
+		return domain.Purchase_orders{}, ErrNoItems
+	}
+	if len(p.Items) > 0 && p.ProductRecordId != 0 {
+		logging.Log(ErrItemsAndRecord)
+		return domain.Purchase_orders{}, ErrItemsAndRecord
+	}
 
 	order := domain.Purchase_orders{
 		OrderNumber:     p.OrderNumber,
@@ -38,15 +96,24 @@ func (s *service) SaveOrder(ctx context.Context, p domain.Purchase_orders) (doma
 		ProductRecordId: p.ProductRecordId,
 		OrderStatusId:   p.OrderStatusId,
 	}
+	var err error
+	if len(p.Items) > 0 {
+		order.Items, err = s.priceItems(ctx, p.Items)
+	} else {
+		order.Items, err = s.recordItem(ctx, p.ProductRecordId)
+	}
+	if err != nil {
+		return domain.Purchase_orders{}, err
+	}
 
-	id, err := s.repository.SaveOrder(ctx, order)
+	now := s.now().UTC().Truncate(time.Second)
+	saved, err := s.repository.SaveOrder(ctx, order, now, now.Add(s.reservationTTL))
 	if err != nil {
 		logging.Log(err)
 		return domain.Purchase_orders{}, err
 	}
-
-	order.ID = id
-	return order, nil
+	saved.Total = computeTotals(saved.Items)
+	return saved, nil
 }
 
 // Exists returns true if the Purchase_order is already exist, or false if it doesn´t
@@ -62,3 +129,239 @@ func (s *service) GetAllByBuyer(ctx context.Context, orderId int) ([]domain.Purc
 	}
 	return s.repository.GetAllByBuyer(ctx)
 }
+
+// UpdateStatus moves the purchase order to the status with the given name and returns the recorded change,
+// or ErrInvalidTransition if the order can not go from its current status to the new one
+func (s *service) UpdateStatus(ctx context.Context, id int, status string, actor string) (domain.OrderStatusChange, error) {
+	to, ok := domain.OrderStatusID(status)
+	if !ok {
+		logging.Log(ErrInvalidStatus)
+		return domain.OrderStatusChange{}, ErrInvalidStatus
+	}
+
+	from, err := s.repository.GetStatus(ctx, id)
+	if err != nil {
+		return domain.OrderStatusChange{}, err
+	}
+	if !domain.CanTransition(from, to) {
+		err := fmt.Errorf("%w: %s to %s", ErrInvalidTransition, domain.OrderStatusNames[from], status)
+		logging.Log(err)
+		return domain.OrderStatusChange{}, err
+	}
+
+	change := domain.OrderStatusChange{
+		PurchaseOrderId: id,
+		FromStatusId:    from,
+		FromStatus:      domain.OrderStatusNames[from],
+		ToStatusId:      to,
+		ToStatus:        status,
+		Actor:           actor,
+		ChangedAt:       s.now().UTC().Truncate(time.Second),
+	}
+	change.ID, err = s.repository.UpdateStatus(ctx, change)
+	if err != nil {
+		return domain.OrderStatusChange{}, err
+	}
+	return change, nil
+}
+
+// GetStatusHistory returns every status change of the purchase order, the oldest first
+func (s *service) GetStatusHistory(ctx context.Context, id int) ([]domain.OrderStatusChange, error) {
+	if _, err := s.repository.GetStatus(ctx, id); err != nil {
+		return nil, err
+	}
+	history, err := s.repository.GetStatusHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for i := range history {
+		history[i].FromStatus = domain.OrderStatusNames[history[i].FromStatusId]
+		history[i].ToStatus = domain.OrderStatusNames[history[i].ToStatusId]
+	}
+	return history, nil
+}
+
+// Get returns the purchase order with its status, buyer and items, or ErrNotFound
+func (s *service) Get(ctx context.Context, id int) (domain.PurchaseOrderDetail, error) {
+	o, err := s.repository.Get(ctx, id)
+	if err != nil {
+		return domain.PurchaseOrderDetail{}, err
+	}
+	orders := []domain.PurchaseOrderDetail{o}
+	if err := s.completeDetails(ctx, orders); err != nil {
+		return domain.PurchaseOrderDetail{}, err
+	}
+	return orders[0], nil
+}
+
+// GetAll returns the purchase orders that match the filter and are in the status with the given name, if any
+func (s *service) GetAll(ctx context.Context, filter domain.PurchaseOrderFilter, status string) ([]domain.PurchaseOrderDetail, error) {
+	if status != "" {
+		id, ok := domain.OrderStatusID(status)
+		if !ok {
+			logging.Log(ErrInvalidStatus)
+			return nil, ErrInvalidStatus
+		}
+		filter.StatusId = id
+	}
+	for _, date := range []string{filter.From, filter.To} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse(domain.ISO8601, date); err != nil {
+			logging.Log(err)
+			return nil, ErrInvalidDate
+		}
+	}
+
+	orders, err := s.repository.GetAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.completeDetails(ctx, orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// GetByBuyer returns every purchase order of the buyer, or ErrBuyerNotFound
+func (s *service) GetByBuyer(ctx context.Context, buyerId int) ([]domain.PurchaseOrderDetail, error) {
+	if !s.repository.ExistsBuyer(ctx, buyerId) {
+		logging.Log(ErrBuyerNotFound)
+		return nil, ErrBuyerNotFound
+	}
+	return s.GetAll(ctx, domain.PurchaseOrderFilter{BuyerId: buyerId}, "")
+}
+
+// priceItems validates the items and snapshots the current sale price of every product
+func (s *service) priceItems(ctx context.Context, items []domain.OrderItem) ([]domain.OrderItem, error) {
+	productIds := make([]int, 0, len(items))
+	seen := map[int]bool{}
+	for _, item := range items {
+		if item.ProductId <= 0 || item.Quantity <= 0 || seen[item.ProductId] {
+			logging.Log(ErrInvalidItems)
+			return nil, ErrInvalidItems
+		}
+		seen[item.ProductId] = true
+		productIds = append(productIds, item.ProductId)
+	}
+
+	records, err := s.repository.GetPrices(ctx, productIds, s.now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	prices := map[int]domain.ProductRecord{}
+	for _, record := range records {
+		prices[record.ProductID] = record
+	}
+
+	priced := make([]domain.OrderItem, len(items))
+	for i, item := range items {
+		record, ok := prices[item.ProductId]
+		if !ok {
+			err := fmt.Errorf("%w: %d", ErrPriceNotFound, item.ProductId)
+			logging.Log(err)
+			return nil, err
+		}
+		priced[i] = domain.OrderItem{
+			ProductId:       item.ProductId,
+			ProductRecordId: record.ID,
+			Quantity:        item.Quantity,
+			UnitPrice:       roundCents(float64(record.SalePrice)),
+		}
+	}
+	return priced, nil
+}
+
+// recordItem returns the single item of an order placed with a product record,
+// a record dated after today is a scheduled price change and can not be ordered yet
+func (s *service) recordItem(ctx context.Context, productRecordId int) ([]domain.OrderItem, error) {
+	record, err := s.repository.GetProductRecord(ctx, productRecordId)
+	if err != nil {
+		return nil, err
+	}
+	if record.LastUpdateDate.Format(domain.ISO8601) > s.now().UTC().Format(domain.ISO8601) {
+		logging.Log(ErrPriceNotEffective)
+		return nil, ErrPriceNotEffective
+	}
+	return []domain.OrderItem{{
+		ProductId:       record.ProductID,
+		ProductRecordId: record.ID,
+		Quantity:        1,
+		UnitPrice:       roundCents(float64(record.SalePrice)),
+	}}, nil
+}
+
+// completeDetails sets the status name, the items and the total of every order.
+// Orders placed with a single product_record_id cost the sale price of that record.
+func (s *service) completeDetails(ctx context.Context, orders []domain.PurchaseOrderDetail) error {
+	ids := make([]int, len(orders))
+	for i, o := range orders {
+		ids[i] = o.ID
+	}
+	items, err := s.repository.GetItems(ctx, ids)
+	if err != nil {
+		return err
+	}
+	byOrder := map[int][]domain.OrderItem{}
+	for _, item := range items {
+		byOrder[item.PurchaseOrderId] = append(byOrder[item.PurchaseOrderId], item)
+	}
+
+	for i := range orders {
+		o := &orders[i]
+		o.Status = domain.OrderStatusNames[o.OrderStatusId]
+		o.Items = byOrder[o.ID]
+		if o.Items == nil {
+			o.Items = []domain.OrderItem{}
+		}
+		o.Total = computeTotals(o.Items)
+		if len(o.Items) == 0 && o.ProductRecord != nil {
+			o.Total = roundCents(float64(o.ProductRecord.SalePrice))
+		}
+	}
+	return nil
+}
+
+// computeTotals sets the subtotal of every item and returns the sum of them
+func computeTotals(items []domain.OrderItem) float64 {
+	var total float64
+	for i := range items {
+		items[i].Subtotal = roundCents(items[i].UnitPrice * float64(items[i].Quantity))
+		total += items[i].Subtotal
+	}
+	return roundCents(total)
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// GetReservations returns the stock reservations of the purchase order, or ErrNotFound
+func (s *service) GetReservations(ctx context.Context, id int) ([]domain.StockReservation, error) {
+	if _, err := s.repository.GetStatus(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repository.GetReservations(ctx, id)
+}
+
+// ExpireReservations returns how many held reservations lapsed and were given back to their batches
+func (s *service) ExpireReservations(ctx context.Context) (int, error) {
+	return s.repository.ExpireReservations(ctx, s.now().UTC())
+}
+
+// Run expires the lapsed reservations every interval until the context is done
+func Run(ctx context.Context, s Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.ExpireReservations(ctx); err != nil {
+				logging.Log(err)
+			}
+		}
+	}
+}
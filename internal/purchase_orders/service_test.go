@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
@@ -25,14 +26,14 @@ func TestSaveOrdersSuccess(t *testing.T) {
 		TrackingCode:    "sdaksdjf664387",
 		BuyerId:         1,
 		ProductRecordId: 5,
-		OrderStatusId:   200,
+		OrderStatusId:   domain.OrderStatusPending,
 	}
 	result := []domain.Purchase_orders{}
 	result = append(result, expectedOrder)
 	mockRepo := MockRepository{
 		Data: result,
 	}
-	s := NewService(&mockRepo)
+	s := NewService(&mockRepo, DefaultReservationTTL)
 	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
 	p, err := s.SaveOrder(ctx, expectedOrder)
 
@@ -50,12 +51,12 @@ func TestSaveOrdersFailureSave(t *testing.T) {
 		TrackingCode:    "sdaksdjf664387",
 		BuyerId:         1,
 		ProductRecordId: 5,
-		OrderStatusId:   200,
+		OrderStatusId:   domain.OrderStatusPending,
 	}
 	mockRepo := MockRepository{
 		ErrExist: ErrAlreadyExists,
 	}
-	s := NewService(&mockRepo)
+	s := NewService(&mockRepo, DefaultReservationTTL)
 	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
 	p, err := s.SaveOrder(ctx, expectedOrder)
 	assert.EqualError(t, ErrAlreadyExists, err.Error())
@@ -72,12 +73,12 @@ func TestSaveOrdersFailureSaveInternalError(t *testing.T) {
 		TrackingCode:    "sdaksdjf664387",
 		BuyerId:         1,
 		ProductRecordId: 5,
-		OrderStatusId:   200,
+		OrderStatusId:   domain.OrderStatusPending,
 	}
 	mockRepo := MockRepository{
 		Err: ErrInternal,
 	}
-	s := NewService(&mockRepo)
+	s := NewService(&mockRepo, DefaultReservationTTL)
 	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
 	p, err := s.SaveOrder(ctx, expectedOrder)
 	assert.EqualError(t, ErrInternal, err.Error())
@@ -90,7 +91,7 @@ func TestExistsSuccessOrders(t *testing.T) {
 	mockRepo := MockRepository{
 		ErrExist: errors.New("order_number already exists"),
 	}
-	s := NewService(&mockRepo)
+	s := NewService(&mockRepo, DefaultReservationTTL)
 	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
 	err := s.Exists(ctx, orderId)
 
@@ -101,7 +102,7 @@ func TestExistsSuccessOrders(t *testing.T) {
 func TestExistsFailOrders(t *testing.T) {
 	orderId := "001"
 	mockRepo := MockRepository{}
-	s := NewService(&mockRepo)
+	s := NewService(&mockRepo, DefaultReservationTTL)
 	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
 	err := s.Exists(ctx, orderId)
 
@@ -123,7 +124,7 @@ func TestGetAllByBuyerSuccessWithId(t *testing.T) {
 	mockRepo := MockRepository{
 		DataOrdersByBuyers: result,
 	}
-	s := NewService(&mockRepo)
+	s := NewService(&mockRepo, DefaultReservationTTL)
 	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
 	p, err := s.GetAllByBuyer(ctx, orderId)
 
@@ -146,7 +147,7 @@ func TestGetAllByBuyerSuccessNotId(t *testing.T) {
 	mockRepo := MockRepository{
 		DataOrdersByBuyers: result,
 	}
-	s := NewService(&mockRepo)
+	s := NewService(&mockRepo, DefaultReservationTTL)
 	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
 	p, err := s.GetAllByBuyer(ctx, orderId)
 
@@ -160,7 +161,7 @@ func TestGetAllByBuyerFailWithId(t *testing.T) {
 	mockRepo := MockRepository{
 		Err: ErrInternal,
 	}
-	s := NewService(&mockRepo)
+	s := NewService(&mockRepo, DefaultReservationTTL)
 	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
 	p, err := s.GetAllByBuyer(ctx, orderId)
 	assert.EqualError(t, ErrInternal, err.Error())
@@ -172,10 +173,293 @@ func TestGetAllByBuyerFailNotId(t *testing.T) {
 	mockRepo := MockRepository{
 		Err: ErrInternal,
 	}
-	s := NewService(&mockRepo)
+	s := NewService(&mockRepo, DefaultReservationTTL)
 	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
 	p, err := s.GetAllByBuyer(ctx, orderId)
 
 	assert.EqualError(t, ErrInternal, err.Error())
 	assert.Empty(t, p)
 }
+
+// TestSaveOrdersFailureNotPending passes when an order is created in other status than pending
+func TestSaveOrdersFailureNotPending(t *testing.T) {
+	order := domain.Purchase_orders{OrderNumber: "001", OrderStatusId: domain.OrderStatusShipped}
+	s := NewService(&MockRepository{}, DefaultReservationTTL)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	p, err := s.SaveOrder(ctx, order)
+
+	assert.ErrorIs(t, err, ErrInitialStatus)
+	assert.Empty(t, p)
+}
+
+// TestSaveOrdersDefaultPending passes when an order without status is created as pending
+func TestSaveOrdersDefaultPending(t *testing.T) {
+	order := domain.Purchase_orders{OrderNumber: "001", ProductRecordId: 1}
+	s := NewService(&MockRepository{}, DefaultReservationTTL)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	p, err := s.SaveOrder(ctx, order)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusPending, p.OrderStatusId)
+}
+
+// TestUpdateStatusTransitions passes when only the legal transitions are accepted
+func TestUpdateStatusTransitions(t *testing.T) {
+	cases := []struct {
+		from  int
+		to    string
+		legal bool
+	}{
+		{domain.OrderStatusPending, "confirmed", true},
+		{domain.OrderStatusPending, "cancelled", true},
+		{domain.OrderStatusPending, "shipped", false},
+		{domain.OrderStatusConfirmed, "picking", true},
+		{domain.OrderStatusConfirmed, "pending", false},
+		{domain.OrderStatusPicking, "shipped", true},
+		{domain.OrderStatusShipped, "delivered", true},
+		{domain.OrderStatusShipped, "cancelled", false},
+		{domain.OrderStatusDelivered, "cancelled", false},
+		{domain.OrderStatusCancelled, "confirmed", false},
+		{domain.OrderStatusPicking, "picking", false},
+	}
+	for _, c := range cases {
+		t.Run(domain.OrderStatusNames[c.from]+" to "+c.to, func(t *testing.T) {
+			mockRepo := MockRepository{Status: c.from}
+			s := NewService(&mockRepo, DefaultReservationTTL)
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			change, err := s.UpdateStatus(ctx, 1, c.to, "jdoe")
+
+			if !c.legal {
+				assert.ErrorIs(t, err, ErrInvalidTransition)
+				assert.Equal(t, c.from, mockRepo.Status)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, domain.OrderStatusNames[c.from], change.FromStatus)
+			assert.Equal(t, c.to, change.ToStatus)
+			assert.Equal(t, "jdoe", mockRepo.LastChange.Actor)
+			assert.False(t, mockRepo.LastChange.ChangedAt.IsZero())
+		})
+	}
+}
+
+// TestUpdateStatusFailures passes when the errors of the status update are returned
+func TestUpdateStatusFailures(t *testing.T) {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	_, err := NewService(&MockRepository{}, DefaultReservationTTL).UpdateStatus(ctx, 1, "lost", "jdoe")
+	assert.ErrorIs(t, err, ErrInvalidStatus)
+
+	_, err = NewService(&MockRepository{ErrStatus: ErrNotFound}, DefaultReservationTTL).UpdateStatus(ctx, 1, "confirmed", "jdoe")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = NewService(&MockRepository{Status: domain.OrderStatusPending, ErrUpdateStatus: ErrStatusChanged}, DefaultReservationTTL).UpdateStatus(ctx, 1, "confirmed", "jdoe")
+	assert.ErrorIs(t, err, ErrStatusChanged)
+}
+
+// TestGetStatusHistory passes when the history is returned with the status names
+func TestGetStatusHistory(t *testing.T) {
+	mockRepo := MockRepository{History: []domain.OrderStatusChange{
+		{ID: 1, FromStatusId: domain.OrderStatusPending, ToStatusId: domain.OrderStatusCancelled},
+	}}
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	history, err := NewService(&mockRepo, DefaultReservationTTL).GetStatusHistory(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "pending", history[0].FromStatus)
+	assert.Equal(t, "cancelled", history[0].ToStatus)
+}
+
+// TestGetOrderDetail passes when the order is returned with the name of its status
+func TestGetOrderDetail(t *testing.T) {
+	mockRepo := MockRepository{Details: []domain.PurchaseOrderDetail{{ID: 1, OrderStatusId: domain.OrderStatusPicking}}}
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	o, err := NewService(&mockRepo, DefaultReservationTTL).Get(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "picking", o.Status)
+
+	_, err = NewService(&mockRepo, DefaultReservationTTL).Get(ctx, 2)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+// TestGetAllOrders passes when the status name is translated into the filter and the dates are validated
+func TestGetAllOrders(t *testing.T) {
+	mockRepo := MockRepository{Details: []domain.PurchaseOrderDetail{{ID: 1, OrderStatusId: domain.OrderStatusConfirmed}}}
+	s := NewService(&mockRepo, DefaultReservationTTL)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	orders, err := s.GetAll(ctx, domain.PurchaseOrderFilter{BuyerId: 2, From: "2022-01-01"}, "confirmed")
+	assert.NoError(t, err)
+	assert.Equal(t, "confirmed", orders[0].Status)
+	assert.Equal(t, domain.PurchaseOrderFilter{BuyerId: 2, StatusId: domain.OrderStatusConfirmed, From: "2022-01-01"}, mockRepo.LastFilter)
+
+	_, err = s.GetAll(ctx, domain.PurchaseOrderFilter{}, "lost")
+	assert.ErrorIs(t, err, ErrInvalidStatus)
+
+	_, err = s.GetAll(ctx, domain.PurchaseOrderFilter{To: "2022-13-01"}, "")
+	assert.ErrorIs(t, err, ErrInvalidDate)
+}
+
+// TestGetOrdersByBuyer passes when only the orders of existing buyers are searched
+func TestGetOrdersByBuyer(t *testing.T) {
+	mockRepo := MockRepository{Buyers: []int{5}}
+	s := NewService(&mockRepo, DefaultReservationTTL)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	_, err := s.GetByBuyer(ctx, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, mockRepo.LastFilter.BuyerId)
+
+	_, err = s.GetByBuyer(ctx, 6)
+	assert.ErrorIs(t, err, ErrBuyerNotFound)
+}
+
+// TestSaveOrdersWithItems passes when every item gets the current sale price and the total is their sum
+func TestSaveOrdersWithItems(t *testing.T) {
+	mockRepo := MockRepository{Prices: []domain.ProductRecord{{ID: 7, ProductID: 1, SalePrice: 1.1}, {ID: 8, ProductID: 2, SalePrice: 20}}}
+	order := domain.Purchase_orders{
+		OrderNumber: "001",
+		Items:       []domain.OrderItem{{ProductId: 1, Quantity: 3}, {ProductId: 2, Quantity: 2}},
+	}
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	p, err := NewService(&mockRepo, DefaultReservationTTL).SaveOrder(ctx, order)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 43.3, p.Total)
+	assert.Equal(t, 3.3, p.Items[0].Subtotal)
+	assert.Equal(t, 7, mockRepo.LastOrder.Items[0].ProductRecordId)
+	assert.Equal(t, 20.0, mockRepo.LastOrder.Items[1].UnitPrice)
+	assert.Equal(t, domain.OrderStatusPending, mockRepo.LastOrder.OrderStatusId)
+}
+
+// TestSaveOrdersWithItemsFailures passes when orders without valid items are not stored
+func TestSaveOrdersWithItemsFailures(t *testing.T) {
+	mockRepo := MockRepository{Prices: []domain.ProductRecord{{ID: 7, ProductID: 1, SalePrice: 1}}}
+	s := NewService(&mockRepo, DefaultReservationTTL)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	_, err := s.SaveOrder(ctx, domain.Purchase_orders{OrderNumber: "001"})
+	assert.ErrorIs(t, err, ErrNoItems)
+
+	_, err = s.SaveOrder(ctx, domain.Purchase_orders{OrderNumber: "001", Items: []domain.OrderItem{{ProductId: 1, Quantity: 0}}})
+	assert.ErrorIs(t, err, ErrInvalidItems)
+
+	_, err = s.SaveOrder(ctx, domain.Purchase_orders{OrderNumber: "001", Items: []domain.OrderItem{{ProductId: 1, Quantity: 1}, {ProductId: 1, Quantity: 1}}})
+	assert.ErrorIs(t, err, ErrInvalidItems)
+
+	_, err = s.SaveOrder(ctx, domain.Purchase_orders{OrderNumber: "001", Items: []domain.OrderItem{{ProductId: 2, Quantity: 1}}})
+	assert.ErrorIs(t, err, ErrPriceNotFound)
+
+	_, err = s.SaveOrder(ctx, domain.Purchase_orders{OrderNumber: "001", ProductRecordId: 7, Items: []domain.OrderItem{{ProductId: 1, Quantity: 1}}})
+	assert.ErrorIs(t, err, ErrItemsAndRecord)
+
+	assert.Empty(t, mockRepo.LastOrder)
+}
+
+// TestGetOrderDetailTotals passes when the total is the sum of the items, or the price of the record of single product orders
+func TestGetOrderDetailTotals(t *testing.T) {
+	mockRepo := MockRepository{
+		Details: []domain.PurchaseOrderDetail{
+			{ID: 1},
+			{ID: 2, ProductRecord: &domain.ProductRecord{ID: 3, SalePrice: 9.99}},
+		},
+		Items: []domain.OrderItem{
+			{ID: 1, PurchaseOrderId: 1, ProductId: 1, Quantity: 2, UnitPrice: 0.35},
+			{ID: 2, PurchaseOrderId: 1, ProductId: 2, Quantity: 1, UnitPrice: 4},
+		},
+	}
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	orders, err := NewService(&mockRepo, DefaultReservationTTL).GetAll(ctx, domain.PurchaseOrderFilter{}, "")
+
+	assert.NoError(t, err)
+	assert.Len(t, orders[0].Items, 2)
+	assert.Equal(t, 4.7, orders[0].Total)
+	assert.Empty(t, orders[1].Items)
+	assert.Equal(t, 9.99, orders[1].Total)
+}
+
+// TestSaveOrdersHoldsStock passes when the stock of every item is held until the reservation TTL lapses
+func TestSaveOrdersHoldsStock(t *testing.T) {
+	mockRepo := MockRepository{Prices: []domain.ProductRecord{{ID: 7, ProductID: 1, SalePrice: 1}}}
+	s := &service{repository: &mockRepo, reservationTTL: 10 * time.Minute, now: func() time.Time {
+		return time.Date(2022, 10, 10, 12, 0, 0, 500, time.UTC)
+	}}
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	p, err := s.SaveOrder(ctx, domain.Purchase_orders{OrderNumber: "001", Items: []domain.OrderItem{{ProductId: 1, Quantity: 3}}})
+
+	assert.NoError(t, err)
+	assert.Len(t, p.Reservations, 1)
+	assert.Equal(t, domain.ReservationHeld, p.Reservations[0].Status)
+	assert.Equal(t, 3, p.Reservations[0].Quantity)
+	assert.Equal(t, time.Date(2022, 10, 10, 12, 10, 0, 0, time.UTC), *p.Reservations[0].ExpiresAt)
+}
+
+// TestSaveOrdersRecordItem passes when an order with a product_record_id holds one unit of its product
+func TestSaveOrdersRecordItem(t *testing.T) {
+	mockRepo := MockRepository{Prices: []domain.ProductRecord{{ID: 7, ProductID: 3, SalePrice: 12.5}}}
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	p, err := NewService(&mockRepo, DefaultReservationTTL).SaveOrder(ctx, domain.Purchase_orders{OrderNumber: "001", ProductRecordId: 7})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.OrderItem{{ID: 1, PurchaseOrderId: 1, ProductId: 3, ProductRecordId: 7, Quantity: 1, UnitPrice: 12.5, Subtotal: 12.5}}, p.Items)
+	assert.Equal(t, 12.5, p.Total)
+}
+
+// TestSaveOrdersRecordItemScheduled passes when an order with a product_record_id of a scheduled price change is rejected
+// until the date of the change
+func TestSaveOrdersRecordItemScheduled(t *testing.T) {
+	scheduled := domain.ProductRecord{ID: 7, ProductID: 3, SalePrice: 12.5, LastUpdateDate: domain.MySqlTime{Time: time.Date(2022, 10, 11, 0, 0, 0, 0, time.UTC)}}
+	mockRepo := MockRepository{Prices: []domain.ProductRecord{scheduled}}
+	s := &service{repository: &mockRepo, reservationTTL: DefaultReservationTTL, now: func() time.Time {
+		return time.Date(2022, 10, 10, 23, 59, 0, 0, time.UTC)
+	}}
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	_, errBefore := s.SaveOrder(ctx, domain.Purchase_orders{OrderNumber: "001", ProductRecordId: 7})
+
+	s.now = func() time.Time { return time.Date(2022, 10, 11, 0, 1, 0, 0, time.UTC) }
+	p, errOn := s.SaveOrder(ctx, domain.Purchase_orders{OrderNumber: "001", ProductRecordId: 7})
+
+	assert.ErrorIs(t, errBefore, ErrPriceNotEffective)
+	assert.NoError(t, errOn)
+	assert.Equal(t, 12.5, p.Total)
+}
+
+// TestSaveOrdersInsufficientStock passes when the order is rejected because its stock cannot be held
+func TestSaveOrdersInsufficientStock(t *testing.T) {
+	mockRepo := MockRepository{Prices: []domain.ProductRecord{{ID: 7, ProductID: 1, SalePrice: 1}}, ErrReserve: ErrInsufficientStock}
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	p, err := NewService(&mockRepo, DefaultReservationTTL).SaveOrder(ctx, domain.Purchase_orders{OrderNumber: "001", Items: []domain.OrderItem{{ProductId: 1, Quantity: 3}}})
+
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+	assert.Empty(t, p)
+}
+
+// TestGetReservations passes when the reservations are returned only for existing orders
+func TestGetReservations(t *testing.T) {
+	mockRepo := MockRepository{Reservations: []domain.StockReservation{{ID: 1, PurchaseOrderId: 1, Quantity: 2}}}
+	s := NewService(&mockRepo, DefaultReservationTTL)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	reservations, err := s.GetReservations(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, mockRepo.Reservations, reservations)
+
+	mockRepo.ErrStatus = ErrNotFound
+	_, err = s.GetReservations(ctx, 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+// TestExpireReservations passes when the holds that lapsed before now are expired
+func TestExpireReservations(t *testing.T) {
+	now := time.Date(2022, 10, 10, 12, 0, 0, 0, time.UTC)
+	mockRepo := MockRepository{Reservations: []domain.StockReservation{{ID: 1}, {ID: 2}}}
+	s := &service{repository: &mockRepo, now: func() time.Time { return now }}
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	expired, err := s.ExpireReservations(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, expired)
+	assert.Equal(t, now, mockRepo.ExpiredAt)
+}
@@ -0,0 +1,86 @@
+package margin_report
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+)
+
+var (
+	RepositoryErrInternal = errors.New("database internal error")
+)
+
+const (
+	GetProducts = "SELECT `id`, `description`, `id_seller`, `id_product_type` FROM `products` ORDER BY `id`;"
+)
+
+type Repository interface {
+	// GetPrices returns the product record in effect at the date for every product that has one, like the prices of the purchase orders
+	GetPrices(ctx context.Context, at time.Time) ([]domain.ProductPrice, error)
+}
+
+// Records looks up the product records in effect at a date, every product without a record is left out of the report
+type Records interface {
+	GetEffectiveAll(ctx context.Context, productIDs []int, at time.Time) ([]domain.ProductRecord, error)
+}
+
+type repository struct {
+	db      *sql.DB
+	records Records
+}
+
+func NewRepository(db *sql.DB, records Records) Repository {
+	return &repository{
+		db:      db,
+		records: records,
+	}
+}
+
+func (repository *repository) GetPrices(ctx context.Context, at time.Time) ([]domain.ProductPrice, error) {
+	records, errRecords := repository.records.GetEffectiveAll(ctx, nil, at)
+	if errRecords != nil {
+		logging.Log(errRecords)
+		return nil, RepositoryErrInternal
+	}
+	effective := make(map[int]domain.ProductRecord, len(records))
+	for _, record := range records {
+		effective[record.ProductID] = record
+	}
+
+	rows, errQuery := repository.db.QueryContext(ctx, GetProducts)
+	if errQuery != nil {
+		logging.Log(errQuery)
+		return nil, RepositoryErrInternal
+	}
+	defer rows.Close()
+
+	prices := []domain.ProductPrice{}
+	for rows.Next() {
+		var price domain.ProductPrice
+		var sellerID sql.NullInt64
+		if errScan := rows.Scan(&price.ProductID, &price.Description, &sellerID, &price.ProductTypeID); errScan != nil {
+			logging.Log(errScan)
+			return nil, RepositoryErrInternal
+		}
+		record, ok := effective[price.ProductID]
+		if !ok {
+			continue
+		}
+		price.PurchasePrice = record.PurchasePrice
+		price.SalePrice = record.SalePrice
+		if sellerID.Valid {
+			id := int(sellerID.Int64)
+			price.SellerID = &id
+		}
+		prices = append(prices, price)
+	}
+	if errRows := rows.Err(); errRows != nil {
+		logging.Log(errRows)
+		return nil, RepositoryErrInternal
+	}
+	return prices, nil
+}
@@ -0,0 +1,40 @@
+package margin_report
+
+import (
+	"context"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+)
+
+type RepositoryMock struct {
+	// Prices are the prices returned for every date, PricesAt overrides them for a date formatted as domain.ISO8601
+	Prices          []domain.ProductPrice
+	PricesAt        map[string][]domain.ProductPrice
+	ForcedErrPrices error
+	Dates           []string
+}
+
+func (repository *RepositoryMock) GetPrices(_ context.Context, at time.Time) ([]domain.ProductPrice, error) {
+	date := at.Format(domain.ISO8601)
+	repository.Dates = append(repository.Dates, date)
+	if repository.ForcedErrPrices != nil {
+		return nil, repository.ForcedErrPrices
+	}
+	if prices, ok := repository.PricesAt[date]; ok {
+		return prices, nil
+	}
+	return repository.Prices, nil
+}
+
+type RecordsMock struct {
+	Records   []domain.ProductRecord
+	ForcedErr error
+}
+
+func (records *RecordsMock) GetEffectiveAll(_ context.Context, _ []int, _ time.Time) ([]domain.ProductRecord, error) {
+	if records.ForcedErr != nil {
+		return nil, records.ForcedErr
+	}
+	return records.Records, nil
+}
@@ -0,0 +1,83 @@
+package margin_report
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	logging.InitLog(nil)
+}
+
+// TestRepository_GetPrices_OK passes when the prices in effect are returned (return []domain.ProductPrice and nil error)
+func TestRepository_GetPrices_OK(t *testing.T) {
+	// Arrange
+	sellerID := 3
+	columns := []string{"id", "description", "id_seller", "id_product_type"}
+	rows := sqlmock.NewRows(columns).
+		AddRow(1, "milk", sellerID, 2).
+		AddRow(2, "cheese", nil, 2).
+		AddRow(3, "butter", sellerID, 2)
+	records := &RecordsMock{Records: []domain.ProductRecord{
+		{ID: 7, ProductID: 1, PurchasePrice: 10.5, SalePrice: 15},
+		{ID: 8, ProductID: 2, PurchasePrice: 20, SalePrice: 18},
+	}}
+	expected := []domain.ProductPrice{
+		{ProductID: 1, Description: "milk", SellerID: &sellerID, ProductTypeID: 2, PurchasePrice: 10.5, SalePrice: 15},
+		{ProductID: 2, Description: "cheese", ProductTypeID: 2, PurchasePrice: 20, SalePrice: 18},
+	}
+
+	// Act
+	db, mock, errSql := sqlmock.New()
+	assert.NoError(t, errSql)
+	defer db.Close()
+	repo := NewRepository(db, records)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	mock.ExpectQuery(regexp.QuoteMeta(GetProducts)).WillReturnRows(rows)
+	prices, errGet := repo.GetPrices(ctx, time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC))
+
+	// Assert
+	assert.NoError(t, errGet)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, expected, prices)
+}
+
+// TestRepository_GetPrices_FailInternalErr passes when unexpected error occurs (return nil and error RepositoryErrInternal)
+func TestRepository_GetPrices_FailInternalErr(t *testing.T) {
+	// Act
+	db, mock, errSql := sqlmock.New()
+	assert.NoError(t, errSql)
+	defer db.Close()
+	repo := NewRepository(db, &RecordsMock{})
+	mock.ExpectQuery(regexp.QuoteMeta(GetProducts)).WillReturnError(sql.ErrConnDone)
+	prices, errGet := repo.GetPrices(context.Background(), time.Now())
+
+	// Assert
+	assert.EqualError(t, errGet, RepositoryErrInternal.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Nil(t, prices)
+}
+
+// TestRepository_GetPrices_FailRecords passes when the records in effect cannot be looked up (return nil and error RepositoryErrInternal)
+func TestRepository_GetPrices_FailRecords(t *testing.T) {
+	// Act
+	db, mock, errSql := sqlmock.New()
+	assert.NoError(t, errSql)
+	defer db.Close()
+	repo := NewRepository(db, &RecordsMock{ForcedErr: sql.ErrConnDone})
+	prices, errGet := repo.GetPrices(context.Background(), time.Now())
+
+	// Assert
+	assert.EqualError(t, errGet, RepositoryErrInternal.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Nil(t, prices)
+}
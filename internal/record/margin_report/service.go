@@ -0,0 +1,181 @@
+package margin_report
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	ServiceErrInternal  = errors.New("database internal error")
+	ServiceErrDateRange = errors.New("from can not be after to")
+	ServiceErrGroupBy   = errors.New("group_by must be seller or product_type")
+)
+
+type Service interface {
+	// Get returns the margins in effect at to, or today when it is nil, and their change since from when it is given
+	Get(ctx *gin.Context, from *time.Time, to *time.Time, groupBy string) (domain.MarginReport, error)
+}
+
+type service struct {
+	repository Repository
+	now        func() time.Time
+}
+
+func NewService(repository Repository) Service {
+	return &service{
+		repository: repository,
+		now:        time.Now,
+	}
+}
+
+func (service *service) Get(ctx *gin.Context, from *time.Time, to *time.Time, groupBy string) (domain.MarginReport, error) {
+	if groupBy != "" && groupBy != domain.MarginGroupBySeller && groupBy != domain.MarginGroupByProductType {
+		logging.Log(ServiceErrGroupBy)
+		return domain.MarginReport{}, ServiceErrGroupBy
+	}
+	end := service.now()
+	if to != nil {
+		end = *to
+	}
+	if from != nil && from.After(end) {
+		logging.Log(ServiceErrDateRange)
+		return domain.MarginReport{}, ServiceErrDateRange
+	}
+
+	prices, errPrices := service.repository.GetPrices(ctx, end)
+	if errPrices != nil {
+		logging.Log(errPrices)
+		return domain.MarginReport{}, ServiceErrInternal
+	}
+	report := domain.MarginReport{
+		To:        end.Format(domain.ISO8601),
+		GroupBy:   groupBy,
+		Products:  []domain.ProductMargin{},
+		BelowCost: []domain.ProductMargin{},
+	}
+
+	previous := map[int]domain.ProductPrice{}
+	if from != nil {
+		report.From = from.Format(domain.ISO8601)
+		startPrices, errStart := service.repository.GetPrices(ctx, *from)
+		if errStart != nil {
+			logging.Log(errStart)
+			return domain.MarginReport{}, ServiceErrInternal
+		}
+		for _, price := range startPrices {
+			previous[price.ProductID] = price
+		}
+	}
+
+	for _, price := range prices {
+		margin := productMargin(price)
+		if start, ok := previous[price.ProductID]; ok {
+			previousMargin := round(float64(start.SalePrice) - float64(start.PurchasePrice))
+			change := round(margin.Margin - previousMargin)
+			margin.PreviousMargin = &previousMargin
+			margin.MarginChange = &change
+			margin.DroppedBelowCost = margin.BelowCost && start.SalePrice >= start.PurchasePrice
+		}
+		report.Products = append(report.Products, margin)
+		if margin.BelowCost {
+			report.BelowCost = append(report.BelowCost, margin)
+		}
+	}
+	if groupBy != "" {
+		report.Groups = groupMargins(report.Products, groupBy)
+	}
+	return report, nil
+}
+
+func productMargin(price domain.ProductPrice) domain.ProductMargin {
+	margin := domain.ProductMargin{
+		ProductID:     price.ProductID,
+		Description:   price.Description,
+		SellerID:      price.SellerID,
+		ProductTypeID: price.ProductTypeID,
+		PurchasePrice: price.PurchasePrice,
+		SalePrice:     price.SalePrice,
+		Margin:        round(float64(price.SalePrice) - float64(price.PurchasePrice)),
+		BelowCost:     price.SalePrice < price.PurchasePrice,
+	}
+	if price.SalePrice != 0 {
+		rate := round((float64(price.SalePrice) - float64(price.PurchasePrice)) / float64(price.SalePrice) * 100)
+		margin.MarginRate = &rate
+	}
+	return margin
+}
+
+// groupMargins summarizes the margins by seller or product type, the products without seller go first
+func groupMargins(margins []domain.ProductMargin, groupBy string) []domain.MarginGroup {
+	type totals struct {
+		group       domain.MarginGroup
+		margin      float64
+		change      float64
+		changeCount int
+	}
+	byID := map[int]*totals{}
+	var withoutID *totals
+	for _, margin := range margins {
+		id := &margin.ProductTypeID
+		if groupBy == domain.MarginGroupBySeller {
+			id = margin.SellerID
+		}
+		var t *totals
+		if id == nil {
+			if withoutID == nil {
+				withoutID = &totals{}
+			}
+			t = withoutID
+		} else {
+			if byID[*id] == nil {
+				groupID := *id
+				byID[*id] = &totals{group: domain.MarginGroup{ID: &groupID}}
+			}
+			t = byID[*id]
+		}
+		t.group.ProductsCount++
+		t.margin += margin.Margin
+		if margin.MarginChange != nil {
+			t.change += *margin.MarginChange
+			t.changeCount++
+		}
+		if margin.BelowCost {
+			t.group.BelowCostCount++
+		}
+	}
+
+	all := make([]*totals, 0, len(byID)+1)
+	if withoutID != nil {
+		all = append(all, withoutID)
+	}
+	ids := make([]int, 0, len(byID))
+	for id := range byID {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		all = append(all, byID[id])
+	}
+
+	groups := make([]domain.MarginGroup, 0, len(all))
+	for _, t := range all {
+		t.group.AverageMargin = round(t.margin / float64(t.group.ProductsCount))
+		if t.changeCount > 0 {
+			change := round(t.change / float64(t.changeCount))
+			t.group.AverageMarginChange = &change
+		}
+		groups = append(groups, t.group)
+	}
+	return groups
+}
+
+// round leaves two decimals, prices are stored as float and would otherwise show their binary error
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
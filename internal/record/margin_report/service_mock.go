@@ -0,0 +1,30 @@
+package margin_report
+
+import (
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type ServiceMock struct {
+	Report       domain.MarginReport
+	ForcedErrGet error
+	FlagGet      bool
+	LastFrom     *time.Time
+	LastTo       *time.Time
+	LastGroupBy  string
+}
+
+func (service *ServiceMock) Get(_ *gin.Context, from *time.Time, to *time.Time, groupBy string) (report domain.MarginReport, err error) {
+	service.FlagGet = true
+	service.LastFrom = from
+	service.LastTo = to
+	service.LastGroupBy = groupBy
+	if service.ForcedErrGet != nil {
+		err = service.ForcedErrGet
+		return
+	}
+	report = service.Report
+	return
+}
@@ -0,0 +1,138 @@
+package margin_report
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupMarginReportServiceTest() (ctx *gin.Context) {
+	gin.SetMode("test")
+	ctx, _ = gin.CreateTestContext(httptest.NewRecorder())
+	return
+}
+
+func newIntPointer(i int) *int {
+	return &i
+}
+
+func newFloatPointer(f float64) *float64 {
+	return &f
+}
+
+// marginRepositoryTest has a product that dropped below cost, one that improved its margin and one without price at the start
+func marginRepositoryTest() *RepositoryMock {
+	return &RepositoryMock{
+		PricesAt: map[string][]domain.ProductPrice{
+			"2026-01-01": {
+				{ProductID: 1, SellerID: newIntPointer(1), ProductTypeID: 1, PurchasePrice: 10, SalePrice: 12},
+				{ProductID: 2, SellerID: newIntPointer(2), ProductTypeID: 1, PurchasePrice: 5, SalePrice: 8},
+			},
+			"2026-06-30": {
+				{ProductID: 1, SellerID: newIntPointer(1), ProductTypeID: 1, PurchasePrice: 10, SalePrice: 9.5},
+				{ProductID: 2, SellerID: newIntPointer(2), ProductTypeID: 1, PurchasePrice: 5, SalePrice: 10},
+				{ProductID: 3, ProductTypeID: 2, PurchasePrice: 3.3, SalePrice: 4.4},
+			},
+		},
+	}
+}
+
+// TestService_Get_OK passes when the margins and their change over the period are computed
+func TestService_Get_OK(t *testing.T) {
+	// Arrange
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	dropped := domain.ProductMargin{
+		ProductID: 1, SellerID: newIntPointer(1), ProductTypeID: 1, PurchasePrice: 10, SalePrice: 9.5,
+		Margin: -0.5, MarginRate: newFloatPointer(-5.26), PreviousMargin: newFloatPointer(2), MarginChange: newFloatPointer(-2.5),
+		BelowCost: true, DroppedBelowCost: true,
+	}
+	expected := domain.MarginReport{
+		From: "2026-01-01",
+		To:   "2026-06-30",
+		Products: []domain.ProductMargin{
+			dropped,
+			{
+				ProductID: 2, SellerID: newIntPointer(2), ProductTypeID: 1, PurchasePrice: 5, SalePrice: 10,
+				Margin: 5, MarginRate: newFloatPointer(50), PreviousMargin: newFloatPointer(3), MarginChange: newFloatPointer(2),
+			},
+			{ProductID: 3, ProductTypeID: 2, PurchasePrice: 3.3, SalePrice: 4.4, Margin: 1.1, MarginRate: newFloatPointer(25)},
+		},
+		BelowCost: []domain.ProductMargin{dropped},
+	}
+
+	// Act
+	repository := marginRepositoryTest()
+	report, errGet := NewService(repository).Get(setupMarginReportServiceTest(), &from, &to, "")
+
+	// Assert
+	assert.NoError(t, errGet)
+	assert.Equal(t, expected, report)
+	assert.Equal(t, []string{"2026-06-30", "2026-01-01"}, repository.Dates)
+}
+
+// TestService_Get_OKGrouped passes when the margins are summarized by seller and by product type
+func TestService_Get_OKGrouped(t *testing.T) {
+	// Arrange
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	expectedBySeller := []domain.MarginGroup{
+		{ProductsCount: 1, AverageMargin: 1.1},
+		{ID: newIntPointer(1), ProductsCount: 1, AverageMargin: -0.5, AverageMarginChange: newFloatPointer(-2.5), BelowCostCount: 1},
+		{ID: newIntPointer(2), ProductsCount: 1, AverageMargin: 5, AverageMarginChange: newFloatPointer(2)},
+	}
+	expectedByType := []domain.MarginGroup{
+		{ID: newIntPointer(1), ProductsCount: 2, AverageMargin: 2.25, AverageMarginChange: newFloatPointer(-0.25), BelowCostCount: 1},
+		{ID: newIntPointer(2), ProductsCount: 1, AverageMargin: 1.1},
+	}
+
+	// Act
+	ctx := setupMarginReportServiceTest()
+	bySeller, errSeller := NewService(marginRepositoryTest()).Get(ctx, &from, &to, domain.MarginGroupBySeller)
+	byType, errType := NewService(marginRepositoryTest()).Get(ctx, &from, &to, domain.MarginGroupByProductType)
+
+	// Assert
+	assert.NoError(t, errSeller)
+	assert.Equal(t, expectedBySeller, bySeller.Groups)
+	assert.NoError(t, errType)
+	assert.Equal(t, expectedByType, byType.Groups)
+}
+
+// TestService_Get_OKToday passes when the period has no dates (return the margins of today without change)
+func TestService_Get_OKToday(t *testing.T) {
+	// Arrange
+	today := time.Date(2026, 6, 30, 15, 0, 0, 0, time.UTC)
+
+	// Act
+	repository := marginRepositoryTest()
+	marginService := &service{repository: repository, now: func() time.Time { return today }}
+	report, errGet := marginService.Get(setupMarginReportServiceTest(), nil, nil, "")
+
+	// Assert
+	assert.NoError(t, errGet)
+	assert.Equal(t, "2026-06-30", report.To)
+	assert.Empty(t, report.From)
+	assert.Len(t, report.Products, 3)
+	assert.Nil(t, report.Products[0].MarginChange)
+	assert.False(t, report.Products[0].DroppedBelowCost)
+	assert.Equal(t, []string{"2026-06-30"}, repository.Dates)
+}
+
+// TestService_Get_Fail passes when the dates or grouping are invalid or the repository fails
+func TestService_Get_Fail(t *testing.T) {
+	from := time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx := setupMarginReportServiceTest()
+
+	_, errRange := NewService(&RepositoryMock{}).Get(ctx, &from, &to, "")
+	_, errGroup := NewService(&RepositoryMock{}).Get(ctx, nil, nil, "warehouse")
+	_, errInternal := NewService(&RepositoryMock{ForcedErrPrices: RepositoryErrInternal}).Get(ctx, nil, nil, "")
+
+	assert.ErrorIs(t, errRange, ServiceErrDateRange)
+	assert.ErrorIs(t, errGroup, ServiceErrGroupBy)
+	assert.ErrorIs(t, errInternal, ServiceErrInternal)
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
+	"time"
+
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/product"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
@@ -14,17 +17,44 @@ var (
 	RepositoryErrNotFound             = errors.New("product record not found in database")
 	RepositoryErrInternal             = errors.New("database internal error")
 	RepositoryErrForeignKeyConstraint = errors.New("a foreign key constraint fails")
+	RepositoryErrNotScheduled         = errors.New("product record is already effective")
+	RepositoryErrReferenced           = errors.New("product record is referenced by purchase orders")
 )
 
 const (
 	SaveProductRecord               = "INSERT INTO `product_records`(`last_update_date`, `purchase_price`, `sale_price`, `product_id`) VALUES (?, ?, ?, ?);"
 	GetProductRecord                = "SELECT `id`, `last_update_date`, `purchase_price`, `sale_price`, `product_id` FROM `product_records` WHERE `id` = ?;"
+	SelectProductID                 = "SELECT `id` FROM `products` WHERE `id` = ?;"
+	GetProductRecordHistory         = "SELECT `id`, `last_update_date`, IFNULL(`purchase_price`, 0), IFNULL(`sale_price`, 0), `product_id` FROM `product_records` WHERE `product_id` = ? AND `last_update_date` IS NOT NULL"
+	GetScheduledProductRecords      = "SELECT `id`, `last_update_date`, IFNULL(`purchase_price`, 0), IFNULL(`sale_price`, 0), `product_id` FROM `product_records` WHERE `last_update_date` > ?"
+	DeleteScheduledProductRecord    = "DELETE FROM `product_records` WHERE `id` = ? AND `last_update_date` > ?;"
 	MySqlNumberForeignKeyConstraint = 1452
+	MySqlNumberRowIsReferenced      = 1451
+	// GetEffectiveProductRecords takes for every product the newest record already started at the date,
+	// the newest id breaks ties between records of the same date
+	GetEffectiveProductRecords = "SELECT `pr`.`id`, `pr`.`last_update_date`, IFNULL(`pr`.`purchase_price`, 0), IFNULL(`pr`.`sale_price`, 0), `pr`.`product_id` FROM `product_records` AS `pr` " +
+		"WHERE `pr`.`last_update_date` <= ? AND NOT EXISTS (" +
+		"SELECT 1 FROM `product_records` AS `newer` WHERE `newer`.`product_id` = `pr`.`product_id` AND `newer`.`last_update_date` <= ? " +
+		"AND (`newer`.`last_update_date` > `pr`.`last_update_date` OR (`newer`.`last_update_date` = `pr`.`last_update_date` AND `newer`.`id` > `pr`.`id`)))"
+	GetEffectiveProductRecord = GetEffectiveProductRecords + " AND `pr`.`product_id` = ?;"
 )
 
 type Repository interface {
 	Get(ctx context.Context, id int) (domain.ProductRecord, error)
 	Save(ctx context.Context, record domain.ProductRecord) (int, error)
+	ProductExists(ctx context.Context, productID int) (bool, error)
+	// GetHistory returns the records of the product ordered by date, from and to are inclusive and ignored when nil
+	GetHistory(ctx context.Context, productID int, from *time.Time, to *time.Time) ([]domain.ProductRecord, error)
+	// GetEffective returns the newest record of the product already started at the date, or RepositoryErrNotFound.
+	// The newest id breaks ties between records of the same date
+	GetEffective(ctx context.Context, productID int, at time.Time) (domain.ProductRecord, error)
+	// GetEffectiveAll returns like GetEffective the record of every product that has one at the date ordered by product,
+	// only the ones of the given products when productIDs is not empty. Purchase orders and the margin report take their prices from it
+	GetEffectiveAll(ctx context.Context, productIDs []int, at time.Time) ([]domain.ProductRecord, error)
+	// GetScheduled returns the records dated after the date ordered by date, only the ones of the product when it is given
+	GetScheduled(ctx context.Context, productID *int, after time.Time) ([]domain.ProductRecord, error)
+	// DeleteScheduled deletes the record only if it is dated after the date, or returns RepositoryErrNotScheduled
+	DeleteScheduled(ctx context.Context, id int, after time.Time) error
 }
 
 type repository struct {
@@ -83,3 +113,116 @@ func (repository *repository) Save(ctx context.Context, record domain.ProductRec
 	savedID = int(id)
 	return
 }
+
+func (repository *repository) ProductExists(ctx context.Context, productID int) (bool, error) {
+	var id int
+	errScan := repository.db.QueryRowContext(ctx, SelectProductID, productID).Scan(&id)
+	switch errScan {
+	case nil:
+		return true, nil
+	case sql.ErrNoRows:
+		return false, nil
+	default:
+		logging.Log(errScan)
+		return false, RepositoryErrInternal
+	}
+}
+
+func (repository *repository) GetHistory(ctx context.Context, productID int, from *time.Time, to *time.Time) ([]domain.ProductRecord, error) {
+	query := GetProductRecordHistory
+	args := []interface{}{productID}
+	if from != nil {
+		query += " AND `last_update_date` >= ?"
+		args = append(args, from.Format(domain.ISO8601))
+	}
+	if to != nil {
+		query += " AND `last_update_date` <= ?"
+		args = append(args, to.Format(domain.ISO8601))
+	}
+	return repository.query(ctx, query+" ORDER BY `last_update_date`, `id`;", args...)
+}
+
+func (repository *repository) GetScheduled(ctx context.Context, productID *int, after time.Time) ([]domain.ProductRecord, error) {
+	query := GetScheduledProductRecords
+	args := []interface{}{after.Format(domain.ISO8601)}
+	if productID != nil {
+		query += " AND `product_id` = ?"
+		args = append(args, *productID)
+	}
+	return repository.query(ctx, query+" ORDER BY `last_update_date`, `id`;", args...)
+}
+
+func (repository *repository) DeleteScheduled(ctx context.Context, id int, after time.Time) error {
+	result, errExec := repository.db.ExecContext(ctx, DeleteScheduledProductRecord, id, after.Format(domain.ISO8601))
+	if errExec != nil {
+		logging.Log(errExec)
+		if message, ok := errExec.(*mysql.MySQLError); ok && message.Number == MySqlNumberRowIsReferenced {
+			return RepositoryErrReferenced
+		}
+		return RepositoryErrInternal
+	}
+	affected, errAffected := result.RowsAffected()
+	if errAffected != nil {
+		logging.Log(errAffected)
+		return RepositoryErrInternal
+	}
+	if affected == 0 {
+		logging.Log(RepositoryErrNotScheduled)
+		return RepositoryErrNotScheduled
+	}
+	return nil
+}
+
+// query returns the records selected by a query with the columns of GetProductRecordHistory
+func (repository *repository) query(ctx context.Context, query string, args ...interface{}) ([]domain.ProductRecord, error) {
+	rows, errQuery := repository.db.QueryContext(ctx, query, args...)
+	if errQuery != nil {
+		logging.Log(errQuery)
+		return nil, RepositoryErrInternal
+	}
+	defer rows.Close()
+
+	records := []domain.ProductRecord{}
+	for rows.Next() {
+		var record domain.ProductRecord
+		if errScan := rows.Scan(&record.ID, &record.LastUpdateDate.Time, &record.PurchasePrice, &record.SalePrice, &record.ProductID); errScan != nil {
+			logging.Log(errScan)
+			return nil, RepositoryErrInternal
+		}
+		records = append(records, record)
+	}
+	if errRows := rows.Err(); errRows != nil {
+		logging.Log(errRows)
+		return nil, RepositoryErrInternal
+	}
+	return records, nil
+}
+
+func (repository *repository) GetEffective(ctx context.Context, productID int, at time.Time) (record domain.ProductRecord, err error) {
+	date := at.Format(domain.ISO8601)
+	row := repository.db.QueryRowContext(ctx, GetEffectiveProductRecord, date, date, productID)
+	errScan := row.Scan(&record.ID, &record.LastUpdateDate.Time, &record.PurchasePrice, &record.SalePrice, &record.ProductID)
+	if errScan != nil {
+		logging.Log(errScan)
+		switch errScan {
+		case sql.ErrNoRows:
+			err = RepositoryErrNotFound
+		default:
+			err = RepositoryErrInternal
+		}
+	}
+	return
+}
+
+func (repository *repository) GetEffectiveAll(ctx context.Context, productIDs []int, at time.Time) ([]domain.ProductRecord, error) {
+	date := at.Format(domain.ISO8601)
+	query := GetEffectiveProductRecords
+	args := []interface{}{date, date}
+	if len(productIDs) > 0 {
+		query += " AND `pr`.`product_id` IN (?" + strings.Repeat(", ?", len(productIDs)-1) + ")"
+		for _, id := range productIDs {
+			args = append(args, id)
+		}
+	}
+	return repository.query(ctx, query+" ORDER BY `pr`.`product_id`;", args...)
+}
@@ -2,6 +2,9 @@ package product_record
 
 import (
 	"context"
+	"sort"
+	"time"
+
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 )
 
@@ -12,6 +15,16 @@ type RepositoryMock struct {
 	FlagGet       bool
 	FlagSave      bool
 	ExpectedID    int
+	// History is returned by GetHistory, the newest record already started is returned by GetEffective and GetEffectiveAll
+	History          []domain.ProductRecord
+	ProductMissing   bool
+	ForcedErrHistory error
+	LastFrom         *time.Time
+	LastTo           *time.Time
+	// Scheduled is returned by GetScheduled, DeleteScheduled removes from it the records dated after the date
+	Scheduled          []domain.ProductRecord
+	ForcedErrScheduled error
+	LastAfter          time.Time
 }
 
 func (repository *RepositoryMock) Get(_ context.Context, _ int) (productRecord domain.ProductRecord, err error) {
@@ -37,3 +50,79 @@ func (repository *RepositoryMock) Save(_ context.Context, record domain.ProductR
 	err = repository.ForcedErrSave
 	return
 }
+
+func (repository *RepositoryMock) ProductExists(_ context.Context, _ int) (bool, error) {
+	return !repository.ProductMissing, nil
+}
+
+func (repository *RepositoryMock) GetHistory(_ context.Context, _ int, from *time.Time, to *time.Time) ([]domain.ProductRecord, error) {
+	repository.LastFrom = from
+	repository.LastTo = to
+	if repository.ForcedErrHistory != nil {
+		return nil, repository.ForcedErrHistory
+	}
+	return repository.History, nil
+}
+
+func (repository *RepositoryMock) GetEffective(_ context.Context, _ int, at time.Time) (record domain.ProductRecord, err error) {
+	if repository.ForcedErrHistory != nil {
+		err = repository.ForcedErrHistory
+		return
+	}
+	found := false
+	for _, r := range repository.History {
+		if !r.LastUpdateDate.After(at) && (!found || !r.LastUpdateDate.Before(record.LastUpdateDate.Time)) {
+			record = r
+			found = true
+		}
+	}
+	if !found {
+		err = RepositoryErrNotFound
+	}
+	return
+}
+
+func (repository *RepositoryMock) GetEffectiveAll(_ context.Context, productIDs []int, at time.Time) ([]domain.ProductRecord, error) {
+	if repository.ForcedErrHistory != nil {
+		return nil, repository.ForcedErrHistory
+	}
+	wanted := map[int]bool{}
+	for _, id := range productIDs {
+		wanted[id] = true
+	}
+	effective := map[int]domain.ProductRecord{}
+	for _, r := range repository.History {
+		newest, found := effective[r.ProductID]
+		if (len(wanted) == 0 || wanted[r.ProductID]) && !r.LastUpdateDate.After(at) && (!found || !r.LastUpdateDate.Before(newest.LastUpdateDate.Time)) {
+			effective[r.ProductID] = r
+		}
+	}
+	records := []domain.ProductRecord{}
+	for _, r := range effective {
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ProductID < records[j].ProductID })
+	return records, nil
+}
+
+func (repository *RepositoryMock) GetScheduled(_ context.Context, _ *int, after time.Time) ([]domain.ProductRecord, error) {
+	repository.LastAfter = after
+	if repository.ForcedErrScheduled != nil {
+		return nil, repository.ForcedErrScheduled
+	}
+	return repository.Scheduled, nil
+}
+
+func (repository *RepositoryMock) DeleteScheduled(_ context.Context, id int, after time.Time) error {
+	repository.LastAfter = after
+	if repository.ForcedErrScheduled != nil {
+		return repository.ForcedErrScheduled
+	}
+	for i, record := range repository.Scheduled {
+		if record.ID == id && record.LastUpdateDate.After(after) {
+			repository.Scheduled = append(repository.Scheduled[:i], repository.Scheduled[i+1:]...)
+			return nil
+		}
+	}
+	return RepositoryErrNotScheduled
+}
@@ -211,3 +211,194 @@ func TestRepository_Get_FailInternalErr(t *testing.T) {
 	assert.NotNil(t, productResult)
 	assert.Empty(t, productResult)
 }
+
+// TestRepository_GetHistory_OK passes when the records between from and to are returned ordered by date
+func TestRepository_GetHistory_OK(t *testing.T) {
+	// Arrange
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 12, 31, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "last_update_date", "purchase_price", "sale_price", "product_id"}
+	rows := sqlmock.NewRows(columns)
+	rows.AddRow(productRecordTest.ID, productRecordTest.LastUpdateDate.Time, productRecordTest.PurchasePrice, productRecordTest.SalePrice, productRecordTest.ProductID)
+	expectedQuery := GetProductRecordHistory + " AND `last_update_date` >= ? AND `last_update_date` <= ? ORDER BY `last_update_date`, `id`;"
+
+	// Act
+	db, mock, errSql := sqlmock.New()
+	assert.NoError(t, errSql)
+	defer db.Close()
+	repo := NewRepository(db)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(productRecordTest.ProductID, "2022-01-01", "2022-12-31").WillReturnRows(rows)
+	records, errGet := repo.GetHistory(ctx, productRecordTest.ProductID, &from, &to)
+
+	// Assert
+	assert.NoError(t, errGet)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, []domain.ProductRecord{productRecordTest}, records)
+}
+
+// TestRepository_GetHistory_FailInternalErr passes when unexpected error occurs (return nil and error RepositoryErrInternal)
+func TestRepository_GetHistory_FailInternalErr(t *testing.T) {
+	// Act
+	db, mock, errSql := sqlmock.New()
+	assert.NoError(t, errSql)
+	defer db.Close()
+	repo := NewRepository(db)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	mock.ExpectQuery(regexp.QuoteMeta(GetProductRecordHistory)).WithArgs(productRecordTest.ProductID).WillReturnError(sql.ErrConnDone)
+	records, errGet := repo.GetHistory(ctx, productRecordTest.ProductID, nil, nil)
+
+	// Assert
+	assert.EqualError(t, errGet, RepositoryErrInternal.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Nil(t, records)
+}
+
+// TestRepository_GetEffective_OK passes when a record already started at the date (return the newest one)
+func TestRepository_GetEffective_OK(t *testing.T) {
+	// Arrange
+	at := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "last_update_date", "purchase_price", "sale_price", "product_id"}
+	rows := sqlmock.NewRows(columns)
+	rows.AddRow(productRecordTest.ID, productRecordTest.LastUpdateDate.Time, productRecordTest.PurchasePrice, productRecordTest.SalePrice, productRecordTest.ProductID)
+
+	// Act
+	db, mock, errSql := sqlmock.New()
+	assert.NoError(t, errSql)
+	defer db.Close()
+	repo := NewRepository(db)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	mock.ExpectQuery(regexp.QuoteMeta(GetEffectiveProductRecord)).WithArgs("2026-05-01", "2026-05-01", productRecordTest.ProductID).WillReturnRows(rows)
+	record, errGet := repo.GetEffective(ctx, productRecordTest.ProductID, at)
+
+	// Assert
+	assert.NoError(t, errGet)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, productRecordTest, record)
+}
+
+// TestRepository_GetEffective_FailNotFound passes when no record started at the date (return error RepositoryErrNotFound)
+func TestRepository_GetEffective_FailNotFound(t *testing.T) {
+	// Act
+	db, mock, errSql := sqlmock.New()
+	assert.NoError(t, errSql)
+	defer db.Close()
+	repo := NewRepository(db)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	mock.ExpectQuery(regexp.QuoteMeta(GetEffectiveProductRecord)).WithArgs("2026-05-01", "2026-05-01", productRecordTest.ProductID).WillReturnError(sql.ErrNoRows)
+	record, errGet := repo.GetEffective(ctx, productRecordTest.ProductID, time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC))
+
+	// Assert
+	assert.EqualError(t, errGet, RepositoryErrNotFound.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Empty(t, record)
+}
+
+// TestRepository_GetEffectiveAll_OK passes when the records in effect of the given products are returned ordered by product
+func TestRepository_GetEffectiveAll_OK(t *testing.T) {
+	// Arrange
+	columns := []string{"id", "last_update_date", "purchase_price", "sale_price", "product_id"}
+	rows := sqlmock.NewRows(columns)
+	rows.AddRow(productRecordTest.ID, productRecordTest.LastUpdateDate.Time, productRecordTest.PurchasePrice, productRecordTest.SalePrice, productRecordTest.ProductID)
+	query := GetEffectiveProductRecords + " AND `pr`.`product_id` IN (?, ?) ORDER BY `pr`.`product_id`;"
+
+	// Act
+	db, mock, errSql := sqlmock.New()
+	assert.NoError(t, errSql)
+	defer db.Close()
+	repo := NewRepository(db)
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("2026-05-01", "2026-05-01", productRecordTest.ProductID, 99).WillReturnRows(rows)
+	records, errGet := repo.GetEffectiveAll(context.Background(), []int{productRecordTest.ProductID, 99}, time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC))
+
+	// Assert
+	assert.NoError(t, errGet)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, []domain.ProductRecord{productRecordTest}, records)
+}
+
+// TestRepository_GetEffectiveAll_FailInternalErr passes when unexpected error occurs listing every product (return nil and error RepositoryErrInternal)
+func TestRepository_GetEffectiveAll_FailInternalErr(t *testing.T) {
+	// Act
+	db, mock, errSql := sqlmock.New()
+	assert.NoError(t, errSql)
+	defer db.Close()
+	repo := NewRepository(db)
+	mock.ExpectQuery(regexp.QuoteMeta(GetEffectiveProductRecords + " ORDER BY `pr`.`product_id`;")).WillReturnError(sql.ErrConnDone)
+	records, errGet := repo.GetEffectiveAll(context.Background(), nil, time.Now())
+
+	// Assert
+	assert.EqualError(t, errGet, RepositoryErrInternal.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Nil(t, records)
+}
+
+// TestRepository_ProductExists passes when a missing product returns false without error
+func TestRepository_ProductExists(t *testing.T) {
+	// Act
+	db, mock, errSql := sqlmock.New()
+	assert.NoError(t, errSql)
+	defer db.Close()
+	repo := NewRepository(db)
+	mock.ExpectQuery(regexp.QuoteMeta(SelectProductID)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(SelectProductID)).WithArgs(2).WillReturnError(sql.ErrNoRows)
+	exists, errExists := repo.ProductExists(context.Background(), 1)
+	missing, errMissing := repo.ProductExists(context.Background(), 2)
+
+	// Assert
+	assert.NoError(t, errExists)
+	assert.True(t, exists)
+	assert.NoError(t, errMissing)
+	assert.False(t, missing)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestRepository_GetScheduled_OK passes when the records dated after the date are returned for the product
+func TestRepository_GetScheduled_OK(t *testing.T) {
+	// Arrange
+	productID := 1
+	columns := []string{"id", "last_update_date", "purchase_price", "sale_price", "product_id"}
+	rows := sqlmock.NewRows(columns)
+	rows.AddRow(productRecordTest.ID, productRecordTest.LastUpdateDate.Time, productRecordTest.PurchasePrice, productRecordTest.SalePrice, productRecordTest.ProductID)
+	expectedQuery := GetScheduledProductRecords + " AND `product_id` = ? ORDER BY `last_update_date`, `id`;"
+
+	// Act
+	db, mock, errSql := sqlmock.New()
+	assert.NoError(t, errSql)
+	defer db.Close()
+	repo := NewRepository(db)
+	mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs("2026-10-16", productID).WillReturnRows(rows)
+	records, errGet := repo.GetScheduled(context.Background(), &productID, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC))
+
+	// Assert
+	assert.NoError(t, errGet)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, []domain.ProductRecord{productRecordTest}, records)
+}
+
+// TestRepository_DeleteScheduled passes when only a record dated after the date is deleted
+func TestRepository_DeleteScheduled(t *testing.T) {
+	// Arrange
+	after := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	// Act
+	db, mock, errSql := sqlmock.New()
+	assert.NoError(t, errSql)
+	defer db.Close()
+	repo := NewRepository(db)
+	mock.ExpectExec(regexp.QuoteMeta(DeleteScheduledProductRecord)).WithArgs(1, "2026-10-16").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(DeleteScheduledProductRecord)).WithArgs(2, "2026-10-16").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(DeleteScheduledProductRecord)).WithArgs(3, "2026-10-16").WillReturnError(&mysql.MySQLError{Number: MySqlNumberRowIsReferenced})
+	errDeleted := repo.DeleteScheduled(context.Background(), 1, after)
+	errEffective := repo.DeleteScheduled(context.Background(), 2, after)
+	errReferenced := repo.DeleteScheduled(context.Background(), 3, after)
+
+	// Assert
+	assert.NoError(t, errDeleted)
+	assert.ErrorIs(t, errEffective, RepositoryErrNotScheduled)
+	assert.ErrorIs(t, errReferenced, RepositoryErrReferenced)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -13,20 +13,33 @@ var (
 	ServiceErrInternal           = errors.New("internal error")
 	ServiceErrForeignKeyNotFound = errors.New("product not found")
 	ServiceErrDate               = errors.New("invalid date")
+	ServiceErrDateRange          = errors.New("from can not be after to")
+	ServiceErrNoPrice            = errors.New("product has no price at the given date")
+	ServiceErrRecordNotFound     = errors.New("product record not found")
+	ServiceErrNotScheduled       = errors.New("only scheduled price changes, dated after today, can be canceled")
+	ServiceErrReferenced         = errors.New("scheduled price change is used by purchase orders")
 )
 
 type Service interface {
 	Get(ctx *gin.Context, id int) (domain.ProductRecord, error)
 	Save(ctx *gin.Context, record domain.ProductRecord) (domain.ProductRecord, error)
+	GetPriceHistory(ctx *gin.Context, productID int, from *time.Time, to *time.Time) (domain.PriceHistory, error)
+	GetEffectivePrice(ctx *gin.Context, productID int, at time.Time) (domain.EffectivePrice, error)
+	// GetScheduled returns the price changes dated after today, that become effective on their date
+	GetScheduled(ctx *gin.Context, productID *int) ([]domain.ProductRecord, error)
+	// Cancel deletes a price change dated after today
+	Cancel(ctx *gin.Context, id int) error
 }
 
 type service struct {
 	repository Repository
+	now        func() time.Time
 }
 
 func NewService(repository Repository) Service {
 	return &service{
 		repository: repository,
+		now:        time.Now,
 	}
 }
 
@@ -45,9 +58,7 @@ func (service *service) Get(ctx *gin.Context, id int) (domain.ProductRecord, err
 }
 
 func (service *service) Save(ctx *gin.Context, record domain.ProductRecord) (domain.ProductRecord, error) {
-	now := time.Now()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
-	if record.LastUpdateDate.Before(today) {
+	if record.LastUpdateDate.Before(service.today()) {
 		logging.Log(ServiceErrDate)
 		return domain.ProductRecord{}, ServiceErrDate
 	}
@@ -63,3 +74,110 @@ func (service *service) Save(ctx *gin.Context, record domain.ProductRecord) (dom
 	}
 	return service.Get(ctx, id)
 }
+
+// GetPriceHistory returns the purchase and sale prices of the product ordered by date, between from and to when they are given
+func (service *service) GetPriceHistory(ctx *gin.Context, productID int, from *time.Time, to *time.Time) (domain.PriceHistory, error) {
+	if from != nil && to != nil && from.After(*to) {
+		logging.Log(ServiceErrDateRange)
+		return domain.PriceHistory{}, ServiceErrDateRange
+	}
+	if errExists := service.productExists(ctx, productID); errExists != nil {
+		return domain.PriceHistory{}, errExists
+	}
+	records, errHistory := service.repository.GetHistory(ctx, productID, from, to)
+	if errHistory != nil {
+		logging.Log(errHistory)
+		return domain.PriceHistory{}, ServiceErrInternal
+	}
+
+	history := domain.PriceHistory{ProductID: productID, Records: records}
+	if from != nil {
+		history.From = from.Format(domain.ISO8601)
+	}
+	if to != nil {
+		history.To = to.Format(domain.ISO8601)
+	}
+	return history, nil
+}
+
+// GetEffectivePrice returns the record of the product in effect at the date, the one purchase orders take their price from
+func (service *service) GetEffectivePrice(ctx *gin.Context, productID int, at time.Time) (domain.EffectivePrice, error) {
+	if errExists := service.productExists(ctx, productID); errExists != nil {
+		return domain.EffectivePrice{}, errExists
+	}
+	record, errGet := service.repository.GetEffective(ctx, productID, at)
+	if errGet != nil {
+		logging.Log(errGet)
+		switch errGet {
+		case RepositoryErrNotFound:
+			return domain.EffectivePrice{}, ServiceErrNoPrice
+		default:
+			return domain.EffectivePrice{}, ServiceErrInternal
+		}
+	}
+	return domain.EffectivePrice{ProductID: productID, At: at.Format(domain.ISO8601), Record: record}, nil
+}
+
+func (service *service) productExists(ctx *gin.Context, productID int) error {
+	exists, errExists := service.repository.ProductExists(ctx, productID)
+	if errExists != nil {
+		logging.Log(errExists)
+		return ServiceErrInternal
+	}
+	if !exists {
+		logging.Log(ServiceErrNotFound)
+		return ServiceErrNotFound
+	}
+	return nil
+}
+
+func (service *service) GetScheduled(ctx *gin.Context, productID *int) ([]domain.ProductRecord, error) {
+	if productID != nil {
+		if errExists := service.productExists(ctx, *productID); errExists != nil {
+			return nil, errExists
+		}
+	}
+	records, errGet := service.repository.GetScheduled(ctx, productID, service.today())
+	if errGet != nil {
+		logging.Log(errGet)
+		return nil, ServiceErrInternal
+	}
+	return records, nil
+}
+
+func (service *service) Cancel(ctx *gin.Context, id int) error {
+	record, errGet := service.repository.Get(ctx, id)
+	if errGet != nil {
+		logging.Log(errGet)
+		switch errGet {
+		case RepositoryErrNotFound:
+			return ServiceErrRecordNotFound
+		default:
+			return ServiceErrInternal
+		}
+	}
+	today := service.today()
+	if !record.LastUpdateDate.After(today) {
+		logging.Log(ServiceErrNotScheduled)
+		return ServiceErrNotScheduled
+	}
+	if errDelete := service.repository.DeleteScheduled(ctx, id, today); errDelete != nil {
+		logging.Log(errDelete)
+		switch errDelete {
+		case RepositoryErrNotScheduled:
+			return ServiceErrNotScheduled
+		case RepositoryErrReferenced:
+			return ServiceErrReferenced
+		default:
+			return ServiceErrInternal
+		}
+	}
+	return nil
+}
+
+// today returns the current UTC date at midnight, like the dates of the records, whatever the local timezone is.
+// A record dated today is already effective, one dated after today is a scheduled price change
+func (service *service) today() time.Time {
+	now := service.now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -3,6 +3,7 @@ package product_record
 import (
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/gin-gonic/gin"
+	"time"
 )
 
 type ServiceMock struct {
@@ -12,6 +13,16 @@ type ServiceMock struct {
 	FlagGet                 bool
 	FlagSave                bool
 	ExpectedID              int
+	History                 domain.PriceHistory
+	Effective               domain.EffectivePrice
+	ForcedErrHistory        error
+	LastFrom                *time.Time
+	LastTo                  *time.Time
+	LastAt                  time.Time
+	Scheduled               []domain.ProductRecord
+	ForcedErrScheduled      error
+	LastProductID           *int
+	CanceledID              int
 }
 
 func (service *ServiceMock) Get(_ *gin.Context, _ int) (productRecord domain.ProductRecord, err error) {
@@ -37,3 +48,42 @@ func (service *ServiceMock) Save(_ *gin.Context, productRecord domain.ProductRec
 	err = service.ForcedErrSave
 	return
 }
+
+func (service *ServiceMock) GetPriceHistory(_ *gin.Context, _ int, from *time.Time, to *time.Time) (history domain.PriceHistory, err error) {
+	service.LastFrom = from
+	service.LastTo = to
+	if service.ForcedErrHistory != nil {
+		err = service.ForcedErrHistory
+		return
+	}
+	history = service.History
+	return
+}
+
+func (service *ServiceMock) GetEffectivePrice(_ *gin.Context, _ int, at time.Time) (effective domain.EffectivePrice, err error) {
+	service.LastAt = at
+	if service.ForcedErrHistory != nil {
+		err = service.ForcedErrHistory
+		return
+	}
+	effective = service.Effective
+	return
+}
+
+func (service *ServiceMock) GetScheduled(_ *gin.Context, productID *int) (records []domain.ProductRecord, err error) {
+	service.LastProductID = productID
+	if service.ForcedErrScheduled != nil {
+		err = service.ForcedErrScheduled
+		return
+	}
+	records = service.Scheduled
+	return
+}
+
+func (service *ServiceMock) Cancel(_ *gin.Context, id int) error {
+	if service.ForcedErrScheduled != nil {
+		return service.ForcedErrScheduled
+	}
+	service.CanceledID = id
+	return nil
+}
@@ -164,3 +164,137 @@ func TestService_Save_FailBadDate(t *testing.T) {
 	assert.Empty(t, productRecord)
 	assert.False(t, productRecordRepository.FlagSave)
 }
+
+func priceHistoryTest() []domain.ProductRecord {
+	return []domain.ProductRecord{
+		{ID: 1, LastUpdateDate: domain.MySqlTime{Time: time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)}, PurchasePrice: 10, SalePrice: 15, ProductID: 1},
+		{ID: 2, LastUpdateDate: domain.MySqlTime{Time: time.Date(2026, 4, 20, 0, 0, 0, 0, time.UTC)}, PurchasePrice: 12, SalePrice: 18, ProductID: 1},
+		{ID: 3, LastUpdateDate: domain.MySqlTime{Time: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)}, PurchasePrice: 13, SalePrice: 20, ProductID: 1},
+	}
+}
+
+// TestService_GetPriceHistory_OK passes when the product exists (return domain.PriceHistory with the given dates)
+func TestService_GetPriceHistory_OK(t *testing.T) {
+	// Arrange
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	// Act
+	productRecordRepository := RepositoryMock{History: priceHistoryTest()}
+	productRecordService := NewService(&productRecordRepository)
+	history, errGet := productRecordService.GetPriceHistory(setupProductRecordServiceTest(), 1, &from, &to)
+
+	// Assert
+	assert.NoError(t, errGet)
+	assert.Equal(t, domain.PriceHistory{ProductID: 1, From: "2026-01-01", To: "2026-12-31", Records: priceHistoryTest()}, history)
+	assert.Equal(t, &from, productRecordRepository.LastFrom)
+	assert.Equal(t, &to, productRecordRepository.LastTo)
+}
+
+// TestService_GetPriceHistory_Fail passes when the dates are reversed, the product is missing or the repository fails
+func TestService_GetPriceHistory_Fail(t *testing.T) {
+	from := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx := setupProductRecordServiceTest()
+
+	_, errRange := NewService(&RepositoryMock{}).GetPriceHistory(ctx, 1, &from, &to)
+	_, errMissing := NewService(&RepositoryMock{ProductMissing: true}).GetPriceHistory(ctx, 1, nil, nil)
+	_, errInternal := NewService(&RepositoryMock{ForcedErrHistory: RepositoryErrInternal}).GetPriceHistory(ctx, 1, nil, nil)
+
+	assert.ErrorIs(t, errRange, ServiceErrDateRange)
+	assert.ErrorIs(t, errMissing, ServiceErrNotFound)
+	assert.ErrorIs(t, errInternal, ServiceErrInternal)
+}
+
+// TestService_GetEffectivePrice_OK passes when a record already started at the date (return the newest one)
+func TestService_GetEffectivePrice_OK(t *testing.T) {
+	// Arrange
+	at := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	// Act
+	productRecordService := NewService(&RepositoryMock{History: priceHistoryTest()})
+	effective, errGet := productRecordService.GetEffectivePrice(setupProductRecordServiceTest(), 1, at)
+
+	// Assert
+	assert.NoError(t, errGet)
+	assert.Equal(t, domain.EffectivePrice{ProductID: 1, At: "2026-05-01", Record: priceHistoryTest()[1]}, effective)
+}
+
+// TestService_GetEffectivePrice_Fail passes when the product had no price yet or does not exist
+func TestService_GetEffectivePrice_Fail(t *testing.T) {
+	at := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	ctx := setupProductRecordServiceTest()
+
+	_, errNoPrice := NewService(&RepositoryMock{History: priceHistoryTest()}).GetEffectivePrice(ctx, 1, at)
+	_, errMissing := NewService(&RepositoryMock{ProductMissing: true}).GetEffectivePrice(ctx, 1, at)
+
+	assert.ErrorIs(t, errNoPrice, ServiceErrNoPrice)
+	assert.ErrorIs(t, errMissing, ServiceErrNotFound)
+}
+
+func scheduledServiceTest(repository *RepositoryMock) *service {
+	return &service{repository: repository, now: func() time.Time { return time.Date(2026, 10, 16, 18, 30, 0, 0, time.Local) }}
+}
+
+// TestService_today passes when today is the UTC date of the clock, not the local one
+func TestService_today(t *testing.T) {
+	// Arrange
+	bogota := time.FixedZone("UTC-5", -5*60*60)
+	service := &service{now: func() time.Time { return time.Date(2026, 10, 16, 21, 30, 0, 0, bogota) }}
+
+	// Act
+	today := service.today()
+
+	// Assert
+	assert.Equal(t, time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC), today)
+}
+
+// TestService_GetScheduled_OK passes when the records dated after today are returned
+func TestService_GetScheduled_OK(t *testing.T) {
+	// Arrange
+	productID := 1
+	scheduled := []domain.ProductRecord{{ID: 4, LastUpdateDate: domain.MySqlTime{Time: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)}, PurchasePrice: 11, SalePrice: 16, ProductID: 1}}
+
+	// Act
+	productRecordRepository := RepositoryMock{Scheduled: scheduled}
+	records, errGet := scheduledServiceTest(&productRecordRepository).GetScheduled(setupProductRecordServiceTest(), &productID)
+
+	// Assert
+	assert.NoError(t, errGet)
+	assert.Equal(t, scheduled, records)
+	assert.Equal(t, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), productRecordRepository.LastAfter)
+}
+
+// TestService_GetScheduled_Fail passes when the product is missing or the repository fails
+func TestService_GetScheduled_Fail(t *testing.T) {
+	productID := 1
+	ctx := setupProductRecordServiceTest()
+
+	_, errMissing := scheduledServiceTest(&RepositoryMock{ProductMissing: true}).GetScheduled(ctx, &productID)
+	_, errInternal := scheduledServiceTest(&RepositoryMock{ForcedErrScheduled: RepositoryErrInternal}).GetScheduled(ctx, nil)
+
+	assert.ErrorIs(t, errMissing, ServiceErrNotFound)
+	assert.ErrorIs(t, errInternal, ServiceErrInternal)
+}
+
+// TestService_Cancel passes when only a record dated after today is deleted
+func TestService_Cancel(t *testing.T) {
+	// Arrange
+	tomorrow := domain.ProductRecord{ID: 4, LastUpdateDate: domain.MySqlTime{Time: time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)}, ProductID: 1}
+	today := domain.ProductRecord{ID: 3, LastUpdateDate: domain.MySqlTime{Time: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)}, ProductID: 1}
+	ctx := setupProductRecordServiceTest()
+
+	// Act
+	scheduledRepository := RepositoryMock{db: []domain.ProductRecord{tomorrow}, Scheduled: []domain.ProductRecord{tomorrow}}
+	errCanceled := scheduledServiceTest(&scheduledRepository).Cancel(ctx, tomorrow.ID)
+	errEffective := scheduledServiceTest(&RepositoryMock{db: []domain.ProductRecord{today}}).Cancel(ctx, today.ID)
+	errMissing := scheduledServiceTest(&RepositoryMock{ForcedErrGet: RepositoryErrNotFound}).Cancel(ctx, 9)
+	errReferenced := scheduledServiceTest(&RepositoryMock{db: []domain.ProductRecord{tomorrow}, ForcedErrScheduled: RepositoryErrReferenced}).Cancel(ctx, tomorrow.ID)
+
+	// Assert
+	assert.NoError(t, errCanceled)
+	assert.Empty(t, scheduledRepository.Scheduled)
+	assert.ErrorIs(t, errEffective, ServiceErrNotScheduled)
+	assert.ErrorIs(t, errMissing, ServiceErrRecordNotFound)
+	assert.ErrorIs(t, errReferenced, ServiceErrReferenced)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
@@ -16,6 +17,18 @@ var (
 	ErrInternal            = errors.New("database internal error")
 	ErrForeignNotFoundCode = 1452
 	ErrForeignNotFound     = errors.New("the given id does not have a warehouse atached to it")
+	ErrProductTypeNotFound = errors.New("the given id does not have a product type atached to it")
+	ErrCapacityExceeded    = errors.New("the current capacity cannot be greater than the maximum capacity")
+	ErrTemperature         = errors.New("the new temperature of the section is not compatible with the products stored in it")
+	ErrCapacityReadOnly    = errors.New("current_capacity is kept by the product batches of the section and can not be updated")
+	ErrRowIsReferencedCode = 1451
+	ErrHasDependents       = errors.New("the section still has batches, excursions or temperature readings")
+	ErrHasHistory          = errors.New("the temperature readings and excursions of the section can not be reassigned to another section")
+	ErrTargetNotFound      = errors.New("the section to reassign to does not exist")
+	ErrTargetProductType   = errors.New("the section to reassign to stores another product type")
+	ErrStoredProductType   = errors.New("the section stores batches of another product type, its product type can not be changed")
+	ErrInvalidStrategy     = errors.New("strategy must be block, or reassign with the id of another section")
+	ErrNoTemperatureRange  = errors.New("the product type has no temperature range, current_temperature and minimum_temperature are required")
 )
 
 const (
@@ -23,7 +36,7 @@ const (
 	GetSection         = `SELECT * FROM sections WHERE id=?;`
 	ExistsSection      = `SELECT section_number FROM sections WHERE section_number=?;`
 	SaveSection        = `INSERT INTO sections (section_number, current_temperature, minimum_temperature, current_capacity, minimum_capacity, maximum_capacity, warehouse_id, id_product_type) VALUES (?, ?, ?, ?, ?, ?, ?, ?);`
-	UpdateSection      = `UPDATE sections SET section_number=?, current_temperature=?, minimum_temperature=?, current_capacity=?, minimum_capacity=?, maximum_capacity=?, warehouse_id=?, id_product_type=? WHERE id=?;`
+	UpdateSection      = `UPDATE sections SET section_number=?, current_temperature=?, minimum_temperature=?, minimum_capacity=?, maximum_capacity=?, warehouse_id=?, id_product_type=? WHERE id=?;`
 	DeleteSection      = `DELETE FROM sections WHERE id=?;`
 	ProductsBySections = `SELECT s.id, s.section_number, IFNULL(sum(pb.current_quantity), 0) as products_count FROM product_batches as pb
 							RIGHT JOIN sections as s ON s.id = pb.section_id
@@ -32,8 +45,25 @@ const (
 							RIGHT JOIN sections as s ON s.id = pb.section_id
 							WHERE s.id = ?
 							GROUP BY s.id;`
+	BatchTemperatures = `SELECT pb.id, pb.batch_number, pb.product_id, p.recommended_freezing_temperature FROM product_batches as pb
+							INNER JOIN products as p ON p.id = pb.product_id
+							WHERE pb.section_id = ? AND pb.current_quantity > 0
+							ORDER BY pb.id;`
+	TemperaturePolicy = `SELECT temperature_policy FROM warehouses WHERE id=?;`
+	ProductTypeRange  = `SELECT id, name, minimum_temperature, maximum_temperature FROM product_types WHERE id=?;`
+	LockSection       = `SELECT current_capacity, maximum_capacity, id_product_type, current_temperature, minimum_temperature, warehouse_id FROM sections WHERE id=? FOR UPDATE;`
+	AddCapacity       = `UPDATE sections SET current_capacity=current_capacity+? WHERE id=?;`
+	CountHistory      = `SELECT (SELECT COUNT(*) FROM section_temperatures WHERE section_id=?) + (SELECT COUNT(*) FROM temperature_excursions WHERE section_id=?);`
+	MoveBatches       = `UPDATE product_batches SET section_id=? WHERE section_id=?;`
+	OtherTypeBatches  = `SELECT COUNT(*) FROM product_batches as pb
+							INNER JOIN products as p ON p.id = pb.product_id
+							WHERE pb.section_id = ? AND pb.current_quantity > 0 AND p.id_product_type <> ?;`
 )
 
+// dependentTables are the tables with a section_id, they block the delete of a section.
+// On reassign only the product batches move, the temperature readings and excursions belong to the section that produced them
+var dependentTables = []string{"product_batches", "temperature_excursions", "section_temperatures"}
+
 func init() {
 	logging.InitLog(nil)
 }
@@ -45,9 +75,20 @@ type Repository interface {
 	Exists(ctx context.Context, cid int) bool
 	Save(ctx context.Context, s domain.Section) (int, error)
 	Update(ctx context.Context, s domain.Section) error
-	Delete(ctx context.Context, id int) error
+	// Delete removes the section in a transaction, on reassign its batches and current capacity are moved to options.To first.
+	// The moved batches whose products do not fit the temperatures of options.To are returned, and with ErrTemperature
+	// when its warehouse rejects incompatible temperatures
+	Delete(ctx context.Context, id int, options domain.DeleteOptions) ([]domain.BatchTemperature, error)
+	// GetDependents returns the ids of the rows of every dependent table that reference the section
+	GetDependents(ctx context.Context, id int) (map[string][]int, error)
 	GetProductsBySections(ctx context.Context) ([]domain.ProductsBySection, error)
 	GetProductsBySection(ctx context.Context, sectionID int) ([]domain.ProductsBySection, error)
+	GetBatchTemperatures(ctx context.Context, sectionID int) ([]domain.BatchTemperature, error)
+	GetTemperaturePolicy(ctx context.Context, warehouseID int) (string, error)
+	// CountOtherTypeBatches returns how many batches with stock inside the section hold products of another product type
+	CountOtherTypeBatches(ctx context.Context, sectionID, productTypeID int) (int, error)
+	// GetProductType returns the product type with the temperature range new sections of the type default to
+	GetProductType(ctx context.Context, productTypeID int) (domain.ProductType, error)
 }
 
 type repository struct {
@@ -111,16 +152,8 @@ func (r *repository) Save(ctx context.Context, s domain.Section) (int, error) {
 
 	res, err := stmt.Exec(&s.SectionNumber, &s.CurrentTemperature, &s.MinimumTemperature, &s.CurrentCapacity, &s.MinimumCapacity, &s.MaximumCapacity, &s.WarehouseID, &s.ProductTypeID)
 	if err != nil {
-		message, ok := err.(*mysql.MySQLError)
-		if ok {
-			switch int(message.Number) {
-			case ErrForeignNotFoundCode:
-				logging.Log(err)
-				return 0, ErrForeignNotFound
-			}
-		}
 		logging.Log(err)
-		return 0, ErrInternal
+		return 0, parseWriteError(err)
 	}
 
 	id, err := res.LastInsertId()
@@ -139,10 +172,10 @@ func (r *repository) Update(ctx context.Context, s domain.Section) error {
 		return ErrInternal
 	}
 
-	res, err := stmt.Exec(&s.SectionNumber, &s.CurrentTemperature, &s.MinimumTemperature, &s.CurrentCapacity, &s.MinimumCapacity, &s.MaximumCapacity, &s.WarehouseID, &s.ProductTypeID, &s.ID)
+	res, err := stmt.Exec(&s.SectionNumber, &s.CurrentTemperature, &s.MinimumTemperature, &s.MinimumCapacity, &s.MaximumCapacity, &s.WarehouseID, &s.ProductTypeID, &s.ID)
 	if err != nil {
 		logging.Log(err)
-		return ErrInternal
+		return parseWriteError(err)
 	}
 
 	_, err = res.RowsAffected()
@@ -154,31 +187,173 @@ func (r *repository) Update(ctx context.Context, s domain.Section) error {
 	return nil
 }
 
-func (r *repository) Delete(ctx context.Context, id int) error {
-	stmt, err := r.db.Prepare(DeleteSection)
+func (r *repository) Delete(ctx context.Context, id int, options domain.DeleteOptions) ([]domain.BatchTemperature, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		logging.Log(err)
-		return ErrInternal
+		return nil, ErrInternal
 	}
 
-	res, err := stmt.Exec(id)
+	affected, err := deleteSection(ctx, tx, id, options)
 	if err != nil {
+		_ = tx.Rollback()
+		return affected, err
+	}
+
+	if err := tx.Commit(); err != nil {
 		logging.Log(err)
-		return ErrInternal
+		return nil, ErrInternal
 	}
+	return affected, nil
+}
 
-	affect, err := res.RowsAffected()
+// deleteSection locks the section, applies the strategy and deletes it inside the transaction
+func deleteSection(ctx context.Context, tx *sql.Tx, id int, options domain.DeleteOptions) ([]domain.BatchTemperature, error) {
+	source, err := lockSection(ctx, tx, id, ErrNotFound)
 	if err != nil {
+		return nil, err
+	}
+
+	var affected []domain.BatchTemperature
+	if options.Strategy == domain.DeleteReassign {
+		if affected, err = reassignBatches(ctx, tx, source, options.To); err != nil {
+			return affected, err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, DeleteSection, id); err != nil {
 		logging.Log(err)
-		return ErrInternal
+		if message, ok := err.(*mysql.MySQLError); ok && int(message.Number) == ErrRowIsReferencedCode {
+			return nil, ErrHasDependents
+		}
+		return nil, ErrInternal
+	}
+	return affected, nil
+}
+
+// reassignBatches moves the batches and current capacity of the source section to the target one, which must store
+// the same product type, have room for them and fit the temperatures of their products unless its warehouse only warns.
+// A section with temperature readings or excursions is not reassigned, they are not moved to a section that did not produce them
+func reassignBatches(ctx context.Context, tx *sql.Tx, source domain.Section, targetID int) ([]domain.BatchTemperature, error) {
+	target, err := lockSection(ctx, tx, targetID, ErrTargetNotFound)
+	if err != nil {
+		return nil, err
+	}
+	if target.ProductTypeID != source.ProductTypeID {
+		logging.Log(ErrTargetProductType)
+		return nil, ErrTargetProductType
+	}
+	if target.CurrentCapacity+source.CurrentCapacity > target.MaximumCapacity {
+		logging.Log(ErrCapacityExceeded)
+		return nil, ErrCapacityExceeded
 	}
 
-	if affect < 1 {
+	var history int
+	if err := tx.QueryRowContext(ctx, CountHistory, source.ID, source.ID).Scan(&history); err != nil {
 		logging.Log(err)
-		return ErrNotFound
+		return nil, ErrInternal
+	}
+	if history > 0 {
+		logging.Log(ErrHasHistory)
+		return nil, ErrHasHistory
 	}
 
-	return nil
+	affected, err := incompatibleBatches(ctx, tx, source.ID, target)
+	if err != nil {
+		return nil, err
+	}
+	if len(affected) > 0 {
+		var policy string
+		if err := tx.QueryRowContext(ctx, TemperaturePolicy, target.WarehouseID).Scan(&policy); err != nil {
+			logging.Log(err)
+			return nil, ErrInternal
+		}
+		if policy != domain.TemperaturePolicyWarn {
+			logging.Log(ErrTemperature)
+			return affected, ErrTemperature
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, MoveBatches, target.ID, source.ID); err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	if _, err := tx.ExecContext(ctx, AddCapacity, source.CurrentCapacity, target.ID); err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	return affected, nil
+}
+
+// incompatibleBatches returns the batches stored in the section whose products do not fit the temperatures of the target
+func incompatibleBatches(ctx context.Context, tx *sql.Tx, sectionID int, target domain.Section) ([]domain.BatchTemperature, error) {
+	rows, err := tx.QueryContext(ctx, BatchTemperatures, sectionID)
+	if err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+
+	var affected []domain.BatchTemperature
+	for rows.Next() {
+		bt := domain.BatchTemperature{SectionID: target.ID, SectionCurrentTemperature: target.CurrentTemperature, SectionMinimumTemperature: target.MinimumTemperature}
+		if err := rows.Scan(&bt.ProductBatchID, &bt.BatchNumber, &bt.ProductID, &bt.RecommendedFreezingTemperature); err != nil {
+			logging.Log(err)
+			return nil, ErrInternal
+		}
+		if !bt.Compatible() {
+			affected = append(affected, bt)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	return affected, nil
+}
+
+// lockSection locks the section for the rest of the transaction and returns its capacities, product type and temperatures,
+// or notFound if it does not exist
+func lockSection(ctx context.Context, tx *sql.Tx, id int, notFound error) (domain.Section, error) {
+	s := domain.Section{ID: id}
+	err := tx.QueryRowContext(ctx, LockSection, id).Scan(&s.CurrentCapacity, &s.MaximumCapacity, &s.ProductTypeID, &s.CurrentTemperature, &s.MinimumTemperature, &s.WarehouseID)
+	if err != nil {
+		logging.Log(err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Section{}, notFound
+		}
+		return domain.Section{}, ErrInternal
+	}
+	return s, nil
+}
+
+func (r *repository) GetDependents(ctx context.Context, id int) (map[string][]int, error) {
+	dependents := make(map[string][]int, len(dependentTables))
+	for _, table := range dependentTables {
+		rows, err := r.db.QueryContext(ctx, "SELECT id FROM "+table+" WHERE section_id=? ORDER BY id;", id)
+		if err != nil {
+			logging.Log(err)
+			return nil, ErrInternal
+		}
+		ids := []int{}
+		for rows.Next() {
+			var dependentID int
+			if err := rows.Scan(&dependentID); err != nil {
+				rows.Close()
+				logging.Log(err)
+				return nil, ErrInternal
+			}
+			ids = append(ids, dependentID)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			logging.Log(err)
+			return nil, ErrInternal
+		}
+		dependents[table] = ids
+	}
+	return dependents, nil
 }
 
 func (r *repository) GetProductsBySections(ctx context.Context) ([]domain.ProductsBySection, error) {
@@ -224,3 +399,81 @@ func (r *repository) GetProductsBySection(ctx context.Context, sectionID int) ([
 
 	return productsBySections, nil
 }
+
+// GetBatchTemperatures returns the batches with stock inside the section next to the temperature needs of their products
+func (r *repository) GetBatchTemperatures(ctx context.Context, sectionID int) ([]domain.BatchTemperature, error) {
+	rows, err := r.db.Query(BatchTemperatures, sectionID)
+	if err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+
+	var batchTemperatures []domain.BatchTemperature
+
+	for rows.Next() {
+		bt := domain.BatchTemperature{SectionID: sectionID}
+		err = rows.Scan(&bt.ProductBatchID, &bt.BatchNumber, &bt.ProductID, &bt.RecommendedFreezingTemperature)
+		if err != nil {
+			logging.Log(err)
+			return nil, ErrInternal
+		}
+		batchTemperatures = append(batchTemperatures, bt)
+	}
+
+	return batchTemperatures, nil
+}
+
+func (r *repository) GetTemperaturePolicy(ctx context.Context, warehouseID int) (string, error) {
+	row := r.db.QueryRow(TemperaturePolicy, warehouseID)
+	var policy string
+	err := row.Scan(&policy)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			logging.Log(err)
+			return "", ErrForeignNotFound
+		default:
+			logging.Log(err)
+			return "", ErrInternal
+		}
+	}
+
+	return policy, nil
+}
+
+func (r *repository) CountOtherTypeBatches(ctx context.Context, sectionID, productTypeID int) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, OtherTypeBatches, sectionID, productTypeID).Scan(&count)
+	if err != nil {
+		logging.Log(err)
+		return 0, ErrInternal
+	}
+	return count, nil
+}
+
+func (r *repository) GetProductType(ctx context.Context, productTypeID int) (domain.ProductType, error) {
+	var productType domain.ProductType
+	err := r.db.QueryRowContext(ctx, ProductTypeRange, productTypeID).Scan(&productType.ID, &productType.Name, &productType.MinimumTemperature, &productType.MaximumTemperature)
+	if err != nil {
+		logging.Log(err)
+		switch err {
+		case sql.ErrNoRows:
+			return domain.ProductType{}, ErrProductTypeNotFound
+		default:
+			return domain.ProductType{}, ErrInternal
+		}
+	}
+	return productType, nil
+}
+
+// parseWriteError translates a missing warehouse or product type, the foreign keys of a section
+func parseWriteError(err error) error {
+	if message, ok := err.(*mysql.MySQLError); ok && int(message.Number) == ErrForeignNotFoundCode {
+		if strings.Contains(message.Message, "id_product_type") {
+			return ErrProductTypeNotFound
+		}
+		return ErrForeignNotFound
+	}
+	return ErrInternal
+}
@@ -9,6 +9,12 @@ import (
 type MockRepository struct {
 	mockSections			[]domain.Section
 	mockProductsBySection	[]domain.ProductsBySection
+	mockBatchTemperatures	[]domain.BatchTemperature
+	mockTemperaturePolicy	string
+	mockOtherTypeBatches	int
+	mockProductType			domain.ProductType
+	mockDependents			map[string][]int
+	deleteOptions			domain.DeleteOptions
 	mockError				error
 	mockGetError			error
 }
@@ -46,12 +52,20 @@ func (r *MockRepository) Update(ctx context.Context, s domain.Section) error {
 	return nil
 }
 
-func (r *MockRepository) Delete(ctx context.Context, id int) error {
+func (r *MockRepository) Delete(ctx context.Context, id int, options domain.DeleteOptions) ([]domain.BatchTemperature, error) {
 	if r.mockError != nil {
-		return r.mockError
+		return r.mockBatchTemperatures, r.mockError
 	}
+	r.deleteOptions = options
 	r.mockSections = r.mockSections[1:]
-	return nil
+	return r.mockBatchTemperatures, nil
+}
+
+func (r *MockRepository) GetDependents(ctx context.Context, id int) (map[string][]int, error) {
+	if r.mockError != nil {
+		return nil, r.mockError
+	}
+	return r.mockDependents, nil
 }
 
 func (r *MockRepository) GetProductsBySections(ctx context.Context) ([]domain.ProductsBySection, error) {
@@ -67,3 +81,22 @@ func (r *MockRepository) GetProductsBySection(ctx context.Context, sectionID int
 	}
 	return []domain.ProductsBySection{r.mockProductsBySection[0]}, nil
 }
+
+func (r *MockRepository) GetBatchTemperatures(ctx context.Context, sectionID int) ([]domain.BatchTemperature, error) {
+	return r.mockBatchTemperatures, nil
+}
+
+func (r *MockRepository) GetTemperaturePolicy(ctx context.Context, warehouseID int) (string, error) {
+	return r.mockTemperaturePolicy, nil
+}
+
+func (r *MockRepository) CountOtherTypeBatches(ctx context.Context, sectionID, productTypeID int) (int, error) {
+	return r.mockOtherTypeBatches, nil
+}
+
+func (r *MockRepository) GetProductType(ctx context.Context, productTypeID int) (domain.ProductType, error) {
+	if r.mockProductType.ID != productTypeID {
+		return domain.ProductType{}, ErrProductTypeNotFound
+	}
+	return r.mockProductType, nil
+}
@@ -581,20 +581,22 @@ func TestDelete_Ok(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectPrepare(regexp.QuoteMeta(DeleteSection))
-	mock.ExpectExec(regexp.QuoteMeta(DeleteSection)).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LockSection)).WithArgs(1).WillReturnRows(lockedSection(0, 100, 1))
+	mock.ExpectExec(regexp.QuoteMeta(DeleteSection)).WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
 	// ACT
 	repo := NewRepository(db)
 
-	err = repo.Delete(context.TODO(),1)
+	_, err = repo.Delete(context.TODO(), 1, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 
 	//ASSERT
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestDelete_RowsAffectedErr(t *testing.T) {
+func TestDelete_BeginErr(t *testing.T) {
 	// ARRANGE
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -602,13 +604,12 @@ func TestDelete_RowsAffectedErr(t *testing.T) {
 
 	expected := ErrInternal
 
-	mock.ExpectPrepare(regexp.QuoteMeta(DeleteSection))
-	mock.ExpectExec(regexp.QuoteMeta(DeleteSection)).WillReturnResult(sqlmock.NewErrorResult(ErrInternal))
+	mock.ExpectBegin().WillReturnError(errors.New("connection refused"))
 
 	// ACT
 	repo := NewRepository(db)
 
-	err = repo.Delete(context.TODO(), 1)
+	_, err = repo.Delete(context.TODO(), 1, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 
 	// ASSERT
 	assert.EqualError(t, err, expected.Error())
@@ -623,54 +624,280 @@ func TestDelete_ExecErr(t *testing.T) {
 
 	expected := ErrInternal
 
-	mock.ExpectPrepare(regexp.QuoteMeta(DeleteSection))
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LockSection)).WithArgs(1).WillReturnRows(lockedSection(0, 100, 1))
 	mock.ExpectExec(regexp.QuoteMeta(DeleteSection)).WillReturnError(&mysql.MySQLError{})
+	mock.ExpectRollback()
 
 	// ACT
 	repo := NewRepository(db)
 
-	err = repo.Delete(context.TODO(), 1)
+	_, err = repo.Delete(context.TODO(), 1, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 
 	// ASSERT
 	assert.EqualError(t, err, expected.Error())
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestDelete_PrepareErr(t *testing.T) {
+func TestDelete_NotFoundErr(t *testing.T) {
 	// ARRANGE
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
-	expected := ErrInternal
+	expected := ErrNotFound
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LockSection)).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockedSectionColumns))
+	mock.ExpectRollback()
 
 	// ACT
 	repo := NewRepository(db)
 
-	err = repo.Delete(context.TODO(), 1)
+	_, err = repo.Delete(context.TODO(), 1, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 
 	// ASSERT
 	assert.EqualError(t, err, expected.Error())
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestDelete_NotFoundErr(t *testing.T) {
+func TestDelete_HasDependentsErr(t *testing.T) {
 	// ARRANGE
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
-	expected := ErrNotFound
+	expected := ErrHasDependents
 
-	mock.ExpectPrepare(regexp.QuoteMeta(DeleteSection))
-	mock.ExpectExec(regexp.QuoteMeta(DeleteSection)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LockSection)).WithArgs(1).WillReturnRows(lockedSection(10, 100, 1))
+	mock.ExpectExec(regexp.QuoteMeta(DeleteSection)).WithArgs(1).WillReturnError(&mysql.MySQLError{Number: 1451})
+	mock.ExpectRollback()
 
 	// ACT
 	repo := NewRepository(db)
 
-	err = repo.Delete(context.TODO(), 1)
+	_, err = repo.Delete(context.TODO(), 1, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 
 	// ASSERT
 	assert.EqualError(t, err, expected.Error())
 	assert.NoError(t, mock.ExpectationsWereMet())
-}
\ No newline at end of file
+}
+
+// TestDelete_Reassign tests if only the batches and the capacity move to the target section, the readings and excursions stay
+func TestDelete_Reassign(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	batches := sqlmock.NewRows([]string{"id", "batch_number", "product_id", "recommended_freezing_temperature"}).AddRow(4, 40, 2, -18)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LockSection)).WithArgs(1).WillReturnRows(lockedSection(30, 100, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(LockSection)).WithArgs(2).WillReturnRows(lockedSection(70, 100, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(CountHistory)).WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"history"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta(BatchTemperatures)).WithArgs(1).WillReturnRows(batches)
+	mock.ExpectExec(regexp.QuoteMeta(MoveBatches)).WithArgs(2, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(AddCapacity)).WithArgs(30, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(DeleteSection)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// ACT
+	repo := NewRepository(db)
+
+	affected, err := repo.Delete(context.TODO(), 1, domain.DeleteOptions{Strategy: domain.DeleteReassign, To: 2})
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Empty(t, affected)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestDelete_ReassignHistory tests if a section with temperature readings or excursions is not reassigned,
+// no UPDATE of section_temperatures or temperature_excursions is expected
+func TestDelete_ReassignHistory(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LockSection)).WithArgs(1).WillReturnRows(lockedSection(30, 100, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(LockSection)).WithArgs(2).WillReturnRows(lockedSection(70, 100, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(CountHistory)).WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"history"}).AddRow(3))
+	mock.ExpectRollback()
+
+	// ACT
+	repo := NewRepository(db)
+
+	_, err = repo.Delete(context.TODO(), 1, domain.DeleteOptions{Strategy: domain.DeleteReassign, To: 2})
+
+	// ASSERT
+	assert.EqualError(t, err, ErrHasHistory.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestDelete_ReassignTemperature tests if batches whose products do not fit the target are not moved when its warehouse rejects them,
+// and are moved and returned when it only warns
+func TestDelete_ReassignTemperature(t *testing.T) {
+	for _, policy := range []string{domain.TemperaturePolicyReject, domain.TemperaturePolicyWarn} {
+		t.Run(policy, func(t *testing.T) {
+			// ARRANGE
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			ambient := sqlmock.NewRows(lockedSectionColumns).AddRow(0, 100, 1, 20, 15, 3)
+			batches := sqlmock.NewRows([]string{"id", "batch_number", "product_id", "recommended_freezing_temperature"}).AddRow(4, 40, 2, -18)
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta(LockSection)).WithArgs(1).WillReturnRows(lockedSection(30, 100, 1))
+			mock.ExpectQuery(regexp.QuoteMeta(LockSection)).WithArgs(2).WillReturnRows(ambient)
+			mock.ExpectQuery(regexp.QuoteMeta(CountHistory)).WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"history"}).AddRow(0))
+			mock.ExpectQuery(regexp.QuoteMeta(BatchTemperatures)).WithArgs(1).WillReturnRows(batches)
+			mock.ExpectQuery(regexp.QuoteMeta(TemperaturePolicy)).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"temperature_policy"}).AddRow(policy))
+			if policy == domain.TemperaturePolicyWarn {
+				mock.ExpectExec(regexp.QuoteMeta(MoveBatches)).WithArgs(2, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(AddCapacity)).WithArgs(30, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(DeleteSection)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			// ACT
+			repo := NewRepository(db)
+
+			affected, err := repo.Delete(context.TODO(), 1, domain.DeleteOptions{Strategy: domain.DeleteReassign, To: 2})
+
+			// ASSERT
+			if policy == domain.TemperaturePolicyWarn {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, ErrTemperature.Error())
+			}
+			assert.Equal(t, []domain.BatchTemperature{{ProductBatchID: 4, BatchNumber: 40, ProductID: 2, SectionID: 2, RecommendedFreezingTemperature: -18, SectionCurrentTemperature: 20, SectionMinimumTemperature: 15}}, affected)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDelete_ReassignErr(t *testing.T) {
+	cases := []struct {
+		name     string
+		target   *sqlmock.Rows
+		expected error
+	}{
+		{"target not found", sqlmock.NewRows(lockedSectionColumns), ErrTargetNotFound},
+		{"other product type", lockedSection(0, 100, 2), ErrTargetProductType},
+		{"capacity exceeded", lockedSection(71, 100, 1), ErrCapacityExceeded},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// ARRANGE
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta(LockSection)).WithArgs(1).WillReturnRows(lockedSection(30, 100, 1))
+			mock.ExpectQuery(regexp.QuoteMeta(LockSection)).WithArgs(2).WillReturnRows(c.target)
+			mock.ExpectRollback()
+
+			// ACT
+			repo := NewRepository(db)
+
+			_, err = repo.Delete(context.TODO(), 1, domain.DeleteOptions{Strategy: domain.DeleteReassign, To: 2})
+
+			// ASSERT
+			assert.EqualError(t, err, c.expected.Error())
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepositoryGetDependents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM product_batches WHERE section_id=? ORDER BY id;")).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM temperature_excursions WHERE section_id=? ORDER BY id;")).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM section_temperatures WHERE section_id=? ORDER BY id;")).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5).AddRow(6))
+
+	repo := NewRepository(db)
+	result, err := repo.GetDependents(context.TODO(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]int{"product_batches": {3}, "temperature_excursions": {}, "section_temperatures": {5, 6}}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+var lockedSectionColumns = []string{"current_capacity", "maximum_capacity", "id_product_type", "current_temperature", "minimum_temperature", "warehouse_id"}
+
+// lockedSection returns the row LockSection reads for a frozen section of the warehouse 1
+func lockedSection(currentCapacity, maximumCapacity, productTypeID int) *sqlmock.Rows {
+	return sqlmock.NewRows(lockedSectionColumns).AddRow(currentCapacity, maximumCapacity, productTypeID, -20, -30, 1)
+}
+
+func TestRepositoryGetBatchTemperatures(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "batch_number", "product_id", "recommended_freezing_temperature"}).AddRow(1, 10, 2, -18)
+	mock.ExpectQuery(regexp.QuoteMeta(BatchTemperatures)).WithArgs(3).WillReturnRows(rows)
+
+	repo := NewRepository(db)
+	result, err := repo.GetBatchTemperatures(context.TODO(), 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.BatchTemperature{{ProductBatchID: 1, BatchNumber: 10, ProductID: 2, SectionID: 3, RecommendedFreezingTemperature: -18}}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepositoryGetTemperaturePolicyNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(TemperaturePolicy)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"temperature_policy"}))
+
+	repo := NewRepository(db)
+	result, err := repo.GetTemperaturePolicy(context.TODO(), 1)
+
+	assert.Empty(t, result)
+	assert.EqualError(t, err, ErrForeignNotFound.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepositoryCountOtherTypeBatches(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(OtherTypeBatches)).WithArgs(3, 2).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	repo := NewRepository(db)
+	result, err := repo.CountOtherTypeBatches(context.TODO(), 3, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepositoryGetProductTypeWithoutRange(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "name", "minimum_temperature", "maximum_temperature"}).AddRow(1, "product type 1", nil, nil)
+	mock.ExpectQuery(regexp.QuoteMeta(ProductTypeRange)).WithArgs(1).WillReturnRows(rows)
+
+	repo := NewRepository(db)
+	result, err := repo.GetProductType(context.TODO(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ProductType{ID: 1, Name: "product type 1"}, result)
+	assert.False(t, result.HasTemperatureRange())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,6 +2,7 @@ package section
 
 import (
 	"context"
+	"math"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
@@ -16,14 +17,21 @@ type Service interface {
 	Create(c context.Context, section domain.Section) (domain.Section, error)
 	// Update updates the section with the specified data in the repository, if it exists
 	Update(c context.Context, section domain.Section) (domain.Section, error)
-	// Delete deletes a section with the specified ID from the repository
-	Delete(c context.Context, id int) error
+	// Delete deletes a section with the specified ID from the repository, blocking it while it has dependents
+	// or moving its batches to another section of the same product type with enough capacity,
+	// the moved batches whose products do not fit the temperatures of that section are returned
+	Delete(c context.Context, id int, options domain.DeleteOptions) ([]domain.BatchTemperature, error)
+	// DeletePreview returns the dependents a delete of the section affects
+	DeletePreview(c context.Context, id int) (domain.DeletePreview, error)
 	// Exists checks if a section with the specified section number exists inside the repository
 	Exists(c context.Context, sectionNumber int) error
 	// GetSectionProducts returns the amount of products in each section, if the section id given = 0, or only of the section with the same id, if one is given
 	GetSectionProducts(c context.Context, sectionID int) ([]domain.ProductsBySection, error)
 }
 
+// DeleteStrategies are the strategies a section can be deleted with
+var DeleteStrategies = []string{domain.DeleteBlock, domain.DeleteReassign}
+
 type service struct {
 	repository Repository
 }
@@ -43,11 +51,22 @@ func (s *service) Get(c context.Context, id int) (domain.Section, error) {
 }
 
 // Create returns the created section if successful, or a error if it failed
+// the current capacity is kept by the product batches, so a new section always starts empty
+// the temperatures in a null state are taken from the temperature range of the product type, if it has none a error is returned
 func (s *service) Create(c context.Context, section domain.Section) (domain.Section, error) {
 	if err := s.Exists(c, section.SectionNumber); err != nil {
 		logging.Log(err)
 		return domain.Section{}, err
 	}
+	section.CurrentCapacity = 0
+	if section.CurrentTemperature <= -273 || section.MinimumTemperature <= -273 {
+		defaulted, err := s.defaultTemperatures(c, section)
+		if err != nil {
+			logging.Log(err)
+			return domain.Section{}, err
+		}
+		section = defaulted
+	}
 	id, err := s.repository.Save(c, section)
 	if err != nil {
 		logging.Log(err)
@@ -60,6 +79,10 @@ func (s *service) Create(c context.Context, section domain.Section) (domain.Sect
 // Update returns the updated section if successful, or a error if it failed
 // if a section with the given id doesn`t exist, an error is returned
 // if the sectionNumber is not unique (with exception to the section currently updating), a error is returned
+// the current capacity is kept by the product batches and never changes, if it ends up greater than the maximum capacity, a error is returned
+// the product type can not change while the section stores batches of another product type
+// if the temperatures change, the stored batches whose products do not fit anymore are returned as affected batches,
+// and when the warehouse rejects incompatible temperatures a error is returned along with them
 // only the values not in a null state are updated
 func (s *service) Update(c context.Context, newSection domain.Section) (domain.Section, error) {
 	section, err := s.Get(c, newSection.ID)
@@ -67,6 +90,7 @@ func (s *service) Update(c context.Context, newSection domain.Section) (domain.S
 		logging.Log(err)
 		return domain.Section{}, err
 	}
+	previousCurrent, previousMinimum := section.CurrentTemperature, section.MinimumTemperature
 	if newSection.SectionNumber != 0 && newSection.SectionNumber != section.SectionNumber {
 		if err := s.Exists(c, newSection.SectionNumber); err != nil {
 			logging.Log(err)
@@ -80,9 +104,6 @@ func (s *service) Update(c context.Context, newSection domain.Section) (domain.S
 	if newSection.MinimumTemperature > -273 {
 		section.MinimumTemperature = newSection.MinimumTemperature
 	}
-	if newSection.CurrentCapacity != 0 {
-		section.CurrentCapacity = newSection.CurrentCapacity
-	}
 	if newSection.MinimumCapacity != 0 {
 		section.MinimumCapacity = newSection.MinimumCapacity
 	}
@@ -92,9 +113,41 @@ func (s *service) Update(c context.Context, newSection domain.Section) (domain.S
 	if newSection.WarehouseID != 0 {
 		section.WarehouseID = newSection.WarehouseID
 	}
-	if newSection.ProductTypeID != 0 {
+	if newSection.ProductTypeID != 0 && newSection.ProductTypeID != section.ProductTypeID {
+		stored, err := s.repository.CountOtherTypeBatches(c, section.ID, newSection.ProductTypeID)
+		if err != nil {
+			logging.Log(err)
+			return domain.Section{}, err
+		}
+		if stored > 0 {
+			logging.Log(ErrStoredProductType)
+			return domain.Section{}, ErrStoredProductType
+		}
 		section.ProductTypeID = newSection.ProductTypeID
 	}
+	if section.CurrentCapacity > section.MaximumCapacity {
+		logging.Log(ErrCapacityExceeded)
+		return domain.Section{}, ErrCapacityExceeded
+	}
+	if section.CurrentTemperature != previousCurrent || section.MinimumTemperature != previousMinimum {
+		affected, err := s.affectedBatches(c, section)
+		if err != nil {
+			logging.Log(err)
+			return domain.Section{}, err
+		}
+		if len(affected) > 0 {
+			policy, err := s.repository.GetTemperaturePolicy(c, section.WarehouseID)
+			if err != nil {
+				logging.Log(err)
+				return domain.Section{}, err
+			}
+			if policy != domain.TemperaturePolicyWarn {
+				logging.Log(ErrTemperature)
+				return domain.Section{AffectedBatches: affected}, ErrTemperature
+			}
+			section.AffectedBatches = affected
+		}
+	}
 	err = s.repository.Update(c, section)
 	if err != nil {
 		logging.Log(err)
@@ -105,15 +158,33 @@ func (s *service) Update(c context.Context, newSection domain.Section) (domain.S
 
 // Delete returns an error if the deletion of the section failed
 // if a section with the given id doesn`t exist, an error is returned
-func (s *service) Delete(c context.Context, id int) error {
+// on reassign the batches that do not fit the temperatures of the target section are returned,
+// along with an error when the warehouse of the target rejects incompatible temperatures
+func (s *service) Delete(c context.Context, id int, options domain.DeleteOptions) ([]domain.BatchTemperature, error) {
+	if !options.Valid(id, DeleteStrategies) {
+		logging.Log(ErrInvalidStrategy)
+		return nil, ErrInvalidStrategy
+	}
 	_, err := s.repository.Get(c, id)
 	if err != nil {
 		logging.Log(err)
-		return err
+		return nil, err
 	}
-	err = s.repository.Delete(c, id)
+	affected, err := s.repository.Delete(c, id, options)
 	logging.Log(err)
-	return err
+	return affected, err
+}
+
+func (s *service) DeletePreview(c context.Context, id int) (domain.DeletePreview, error) {
+	if _, err := s.repository.Get(c, id); err != nil {
+		logging.Log(err)
+		return domain.DeletePreview{}, err
+	}
+	dependents, err := s.repository.GetDependents(c, id)
+	if err != nil {
+		return domain.DeletePreview{}, err
+	}
+	return domain.NewDeletePreview(id, DeleteStrategies, dependents), nil
 }
 
 func (s *service) Exists(c context.Context, sectionNumber int) error {
@@ -129,3 +200,39 @@ func (s *service) GetSectionProducts(c context.Context, sectionID int) ([]domain
 	}
 	return s.repository.GetProductsBySection(c, sectionID)
 }
+
+// defaultTemperatures sets the temperatures in a null state from the range of the product type of the section,
+// the section reaches the coldest temperature of the range and is kept at the warmest one its products allow
+func (s *service) defaultTemperatures(c context.Context, section domain.Section) (domain.Section, error) {
+	productType, err := s.repository.GetProductType(c, section.ProductTypeID)
+	if err != nil {
+		return domain.Section{}, err
+	}
+	if !productType.HasTemperatureRange() {
+		return domain.Section{}, ErrNoTemperatureRange
+	}
+	if section.MinimumTemperature <= -273 {
+		section.MinimumTemperature = int(math.Floor(float64(*productType.MinimumTemperature)))
+	}
+	if section.CurrentTemperature <= -273 {
+		section.CurrentTemperature = int(math.Floor(float64(*productType.MaximumTemperature)))
+	}
+	return section, nil
+}
+
+// affectedBatches returns the batches stored in the section whose products do not fit its temperatures
+func (s *service) affectedBatches(c context.Context, section domain.Section) ([]domain.BatchTemperature, error) {
+	batches, err := s.repository.GetBatchTemperatures(c, section.ID)
+	if err != nil {
+		return nil, err
+	}
+	var affected []domain.BatchTemperature
+	for _, bt := range batches {
+		bt.SectionCurrentTemperature = section.CurrentTemperature
+		bt.SectionMinimumTemperature = section.MinimumTemperature
+		if !bt.Compatible() {
+			affected = append(affected, bt)
+		}
+	}
+	return affected, nil
+}
@@ -9,6 +9,9 @@ import (
 type MockService struct {
 	MockSections          []domain.Section
 	MockProductsBySection []domain.ProductsBySection
+	MockPreview           domain.DeletePreview
+	MockDeleteOptions     domain.DeleteOptions
+	MockAffectedBatches   []domain.BatchTemperature
 	MockError             error
 }
 
@@ -49,9 +52,6 @@ func (s *MockService) Update(c context.Context, section domain.Section) (domain.
 	if section.MinimumTemperature > -273 {
 		s.MockSections[0].MinimumTemperature = section.MinimumTemperature
 	}
-	if section.CurrentCapacity != 0 {
-		s.MockSections[0].CurrentCapacity = section.CurrentCapacity
-	}
 	if section.MinimumCapacity != 0 {
 		s.MockSections[0].MinimumCapacity = section.MinimumCapacity
 	}
@@ -67,12 +67,20 @@ func (s *MockService) Update(c context.Context, section domain.Section) (domain.
 	return s.MockSections[0], nil
 }
 
-func (s *MockService) Delete(c context.Context, id int) error {
+func (s *MockService) Delete(c context.Context, id int, options domain.DeleteOptions) ([]domain.BatchTemperature, error) {
 	if s.MockError != nil {
-		return s.MockError
+		return s.MockAffectedBatches, s.MockError
 	}
+	s.MockDeleteOptions = options
 	s.MockSections = s.MockSections[1:]
-	return nil
+	return s.MockAffectedBatches, nil
+}
+
+func (s *MockService) DeletePreview(c context.Context, id int) (domain.DeletePreview, error) {
+	if s.MockError != nil {
+		return domain.DeletePreview{}, s.MockError
+	}
+	return s.MockPreview, nil
 }
 
 func (s *MockService) Exists(c context.Context, sectionNumber int) error {
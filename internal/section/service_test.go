@@ -8,7 +8,8 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-// TestCreateOk tests if the service correctly calls the repository to create and return the given section
+// TestCreateOk tests if the service correctly calls the repository to create and return the given section,
+// which starts empty whatever current capacity is given
 func TestCreateOk(t *testing.T) {
 	// ARANGE
 	repository := MockRepository{}
@@ -20,7 +21,7 @@ func TestCreateOk(t *testing.T) {
 		SectionNumber:      1,
 		CurrentTemperature: 2.0,
 		MinimumTemperature: -1.0,
-		CurrentCapacity:    100,
+		CurrentCapacity:    0,
 		MaximumCapacity:    1000,
 		MinimumCapacity:    10,
 		WarehouseID:        1,
@@ -89,6 +90,36 @@ func TestCreateConflict(t *testing.T) {
 
 }
 
+// TestCreateDefaultTemperatures tests if the service takes the temperatures not given from the range of the product type
+func TestCreateDefaultTemperatures(t *testing.T) {
+	// ARRANGE
+	minimum, maximum := float32(-30.5), float32(-18)
+	repository := MockRepository{mockProductType: domain.ProductType{ID: 1, Name: "frozen", MinimumTemperature: &minimum, MaximumTemperature: &maximum}}
+	service := NewService(&repository)
+
+	// ACT
+	result, err := service.Create(context.TODO(), domain.Section{SectionNumber: 1, CurrentTemperature: -273, MinimumTemperature: -273, MaximumCapacity: 10, ProductTypeID: 1})
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, -18, result.CurrentTemperature)
+	assert.Equal(t, -31, result.MinimumTemperature)
+}
+
+// TestCreateNoTemperatureRange tests if the service rejects a section without temperatures when its product type has no range
+func TestCreateNoTemperatureRange(t *testing.T) {
+	// ARRANGE
+	repository := MockRepository{mockProductType: domain.ProductType{ID: 1, Name: "product type 1"}}
+	service := NewService(&repository)
+
+	// ACT
+	_, err := service.Create(context.TODO(), domain.Section{SectionNumber: 1, CurrentTemperature: 2, MinimumTemperature: -273, MaximumCapacity: 10, ProductTypeID: 1})
+
+	// ASSERT
+	assert.ErrorIs(t, err, ErrNoTemperatureRange)
+	assert.Empty(t, repository.mockSections)
+}
+
 func TestCreateInternalErr(t *testing.T) {
 	// ARRANGE
 	repository := MockRepository{mockError: ErrInternal}
@@ -267,7 +298,7 @@ func TestUpdateExistent(t *testing.T) {
 		SectionNumber:      2,
 		CurrentTemperature: 3.0,
 		MinimumTemperature: -2.0,
-		CurrentCapacity:    90,
+		CurrentCapacity:    100,
 		MaximumCapacity:    1100,
 		MinimumCapacity:    20,
 		WarehouseID:        2,
@@ -407,7 +438,7 @@ func TestDeleteNonExistent(t *testing.T) {
 	expected := ErrNotFound
 
 	// ACT
-	err := service.Delete(*ctx, 1)
+	_, err := service.Delete(*ctx, 1, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 
 	// ASSERT
 	assert.EqualError(t, expected, err.Error())
@@ -435,7 +466,7 @@ func TestDeleteOk(t *testing.T) {
 	ctx := new(context.Context)
 
 	// ACT
-	err1 := service.Delete(*ctx, 1)
+	_, err1 := service.Delete(*ctx, 1, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 	result, err2 := service.GetAll(*ctx)
 
 	// ASSERT
@@ -444,6 +475,71 @@ func TestDeleteOk(t *testing.T) {
 	assert.Empty(t, result)
 }
 
+// TestDeleteReassign tests if the service passes the target section to the repository
+func TestDeleteReassign(t *testing.T) {
+	// ARRANGE
+	repository := MockRepository{
+		mockSections: []domain.Section{{ID: 1, SectionNumber: 1, WarehouseID: 1, ProductTypeID: 1}},
+	}
+	service := NewService(&repository)
+	options := domain.DeleteOptions{Strategy: domain.DeleteReassign, To: 2}
+
+	// ACT
+	_, err := service.Delete(context.TODO(), 1, options)
+
+	// ASSERT
+	assert.Nil(t, err)
+	assert.Equal(t, options, repository.deleteOptions)
+}
+
+// TestDeleteInvalidStrategy tests if the service rejects the strategies a section can´t be deleted with before reaching the repository
+func TestDeleteInvalidStrategy(t *testing.T) {
+	// ARRANGE
+	repository := MockRepository{
+		mockSections: []domain.Section{{ID: 1, SectionNumber: 1, WarehouseID: 1, ProductTypeID: 1}},
+	}
+	service := NewService(&repository)
+
+	for _, options := range []domain.DeleteOptions{{Strategy: domain.DeleteDetach}, {Strategy: domain.DeleteReassign}, {Strategy: domain.DeleteReassign, To: 1}} {
+		// ACT
+		_, err := service.Delete(context.TODO(), 1, options)
+
+		// ASSERT
+		assert.EqualError(t, err, ErrInvalidStrategy.Error())
+	}
+	assert.Len(t, repository.mockSections, 1)
+}
+
+// TestDeletePreview tests if the service returns the dependents of the section and whether they block its delete
+func TestDeletePreview(t *testing.T) {
+	// ARRANGE
+	repository := MockRepository{
+		mockSections:   []domain.Section{{ID: 1, SectionNumber: 1, WarehouseID: 1, ProductTypeID: 1}},
+		mockDependents: map[string][]int{"product_batches": {3}, "temperature_excursions": {}, "section_temperatures": {}},
+	}
+	service := NewService(&repository)
+
+	// ACT
+	result, err := service.DeletePreview(context.TODO(), 1)
+
+	// ASSERT
+	assert.Nil(t, err)
+	assert.Equal(t, domain.DeletePreview{ID: 1, Strategies: DeleteStrategies, Blocked: true, Dependents: repository.mockDependents}, result)
+}
+
+// TestDeletePreviewNonExistent tests if the service returns the correct error when a section with the given id doesn´t exists
+func TestDeletePreviewNonExistent(t *testing.T) {
+	// ARRANGE
+	repository := MockRepository{mockGetError: ErrNotFound}
+	service := NewService(&repository)
+
+	// ACT
+	_, err := service.DeletePreview(context.TODO(), 1)
+
+	// ASSERT
+	assert.EqualError(t, err, ErrNotFound.Error())
+}
+
 func TestGetAllSectionProductsOk(t *testing.T) {
 	//	ARRANGE
 	repository := MockRepository{
@@ -533,3 +629,91 @@ func TestGetSectionProductsIdNotExistent(t *testing.T) {
 	assert.Empty(t, result)
 	assert.EqualError(t, expected, err.Error())
 }
+
+// TestUpdateCapacityExceeded tests if the service returns the correct error when the maximum capacity is lowered below the current one
+func TestUpdateCapacityExceeded(t *testing.T) {
+	// ARRANGE
+	repository := MockRepository{mockSections: []domain.Section{{ID: 1, SectionNumber: 1, CurrentCapacity: 100, MaximumCapacity: 1000}}}
+	service := NewService(&repository)
+	ctx := new(context.Context)
+
+	// ACT
+	result, err := service.Update(*ctx, domain.Section{ID: 1, CurrentTemperature: -273, MinimumTemperature: -273, MaximumCapacity: 50})
+
+	// ASSERT
+	assert.Empty(t, result)
+	assert.EqualError(t, err, ErrCapacityExceeded.Error())
+}
+
+// TestUpdateStoredProductType tests if the service keeps the product type while the section stores batches of another one,
+// and changes it once the section holds none
+func TestUpdateStoredProductType(t *testing.T) {
+	// ARRANGE
+	repository := MockRepository{
+		mockSections:         []domain.Section{{ID: 1, SectionNumber: 1, MaximumCapacity: 1000, ProductTypeID: 1}},
+		mockOtherTypeBatches: 2,
+	}
+	service := NewService(&repository)
+	ctx := new(context.Context)
+
+	// ACT
+	result, err := service.Update(*ctx, domain.Section{ID: 1, CurrentTemperature: -273, MinimumTemperature: -273, ProductTypeID: 2})
+
+	// ASSERT
+	assert.Empty(t, result)
+	assert.EqualError(t, err, ErrStoredProductType.Error())
+	assert.Equal(t, 1, repository.mockSections[0].ProductTypeID)
+
+	// ACT
+	repository.mockOtherTypeBatches = 0
+	result, err = service.Update(*ctx, domain.Section{ID: 1, CurrentTemperature: -273, MinimumTemperature: -273, ProductTypeID: 2})
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.ProductTypeID)
+}
+
+// TestUpdateTemperatureRejected tests if the service returns the affected batches along with an error when the warehouse rejects incompatible temperatures
+func TestUpdateTemperatureRejected(t *testing.T) {
+	// ARRANGE
+	affected := domain.BatchTemperature{ProductBatchID: 1, BatchNumber: 10, ProductID: 1, SectionID: 1, RecommendedFreezingTemperature: -18}
+	repository := MockRepository{
+		mockSections:          []domain.Section{{ID: 1, SectionNumber: 1, CurrentTemperature: -20, MinimumTemperature: -25, MaximumCapacity: 1000, WarehouseID: 1}},
+		mockBatchTemperatures: []domain.BatchTemperature{affected, {ProductBatchID: 2, BatchNumber: 20, ProductID: 2, SectionID: 1, RecommendedFreezingTemperature: 5}},
+		mockTemperaturePolicy: domain.TemperaturePolicyReject,
+	}
+	service := NewService(&repository)
+	ctx := new(context.Context)
+
+	affected.SectionCurrentTemperature = 2
+	affected.SectionMinimumTemperature = -25
+
+	// ACT
+	result, err := service.Update(*ctx, domain.Section{ID: 1, CurrentTemperature: 2, MinimumTemperature: -273})
+
+	// ASSERT
+	assert.EqualError(t, err, ErrTemperature.Error())
+	assert.Equal(t, []domain.BatchTemperature{affected}, result.AffectedBatches)
+	assert.Equal(t, -20, repository.mockSections[0].CurrentTemperature)
+}
+
+// TestUpdateTemperatureWarned tests if the service updates the section and returns the affected batches when the warehouse only warns
+func TestUpdateTemperatureWarned(t *testing.T) {
+	// ARRANGE
+	repository := MockRepository{
+		mockSections:          []domain.Section{{ID: 1, SectionNumber: 1, CurrentTemperature: -20, MinimumTemperature: -25, MaximumCapacity: 1000, WarehouseID: 1}},
+		mockBatchTemperatures: []domain.BatchTemperature{{ProductBatchID: 1, BatchNumber: 10, ProductID: 1, SectionID: 1, RecommendedFreezingTemperature: -18}},
+		mockTemperaturePolicy: domain.TemperaturePolicyWarn,
+	}
+	service := NewService(&repository)
+	ctx := new(context.Context)
+
+	// ACT
+	result, err := service.Update(*ctx, domain.Section{ID: 1, CurrentTemperature: 2, MinimumTemperature: -273})
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.CurrentTemperature)
+	assert.Len(t, result.AffectedBatches, 1)
+	assert.Equal(t, 2, repository.mockSections[0].CurrentTemperature)
+}
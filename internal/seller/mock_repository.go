@@ -2,6 +2,7 @@ package seller
 
 import (
 	"context"
+	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 )
@@ -11,6 +12,12 @@ type MockRepositorySeller struct {
 	DataMock      []domain.Seller
 	ErrorMock     error
 	ErrorCidExist error
+	Products      []domain.Product
+	Performance   []domain.SellerPerformance
+	LastFrom      *time.Time
+	LastTo        *time.Time
+	Dependents    map[string][]int
+	DeleteOptions domain.DeleteOptions
 }
 
 func (r *MockRepositorySeller) GetAll(ctx context.Context) (sellers []domain.Seller, err error) {
@@ -53,10 +60,39 @@ func (r *MockRepositorySeller) Update(ctx context.Context, s domain.Seller) (err
 	return
 }
 
-func (r *MockRepositorySeller) Delete(ctx context.Context, id int) (err error) {
+func (r *MockRepositorySeller) Delete(ctx context.Context, id int, options domain.DeleteOptions) (err error) {
+	r.DeleteOptions = options
 	if r.ErrorMock != nil {
 		err = r.ErrorMock
 		return
 	}
 	return
 }
+
+func (r *MockRepositorySeller) GetProducts(ctx context.Context, id int) (products []domain.Product, err error) {
+	if r.ErrorMock != nil {
+		err = r.ErrorMock
+		return
+	}
+	products = append([]domain.Product{}, r.Products...)
+	return
+}
+
+func (r *MockRepositorySeller) GetPerformance(ctx context.Context, from *time.Time, to *time.Time) (performances []domain.SellerPerformance, err error) {
+	r.LastFrom, r.LastTo = from, to
+	if r.ErrorMock != nil {
+		err = r.ErrorMock
+		return
+	}
+	performances = append([]domain.SellerPerformance{}, r.Performance...)
+	return
+}
+
+func (r *MockRepositorySeller) GetDependents(ctx context.Context, id int) (dependents map[string][]int, err error) {
+	if r.ErrorMock != nil {
+		err = r.ErrorMock
+		return
+	}
+	dependents = map[string][]int{"products": append([]int{}, r.Dependents["products"]...)}
+	return
+}
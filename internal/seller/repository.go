@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"log"
+	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/go-sql-driver/mysql"
@@ -17,6 +18,8 @@ var (
 	ErrAlreadyExists        = errors.New("cid already exists")
 	ErrInternal             = errors.New("Database internal error")
 	ErrForeignKeyConstraint = errors.New("a column table constraint fails")
+	ErrHasProducts          = errors.New("seller has products, delete it with the reassign or detach strategy")
+	ErrTargetNotFound       = errors.New("the seller to reassign the products to does not exist")
 )
 
 // Repository encapsulates the storage of a Seller.
@@ -26,7 +29,15 @@ type Repository interface {
 	Exists(ctx context.Context, cid int) bool
 	Save(ctx context.Context, s domain.Seller) (int, error)
 	Update(ctx context.Context, s domain.Seller) error
-	Delete(ctx context.Context, id int) error
+	// Delete removes the seller and, in the same transaction, reassigns or detaches its products as the options say
+	Delete(ctx context.Context, id int, options domain.DeleteOptions) error
+	// GetDependents returns the ids of the products of the seller by table
+	GetDependents(ctx context.Context, id int) (map[string][]int, error)
+	// GetProducts returns the products of the seller ordered by id
+	GetProducts(ctx context.Context, id int) ([]domain.Product, error)
+	// GetPerformance returns the performance of every seller ordered by id, the orders are counted
+	// from and to the given dates inclusive, a nil date leaves that side of the period open
+	GetPerformance(ctx context.Context, from *time.Time, to *time.Time) ([]domain.SellerPerformance, error)
 }
 
 type repository struct {
@@ -40,7 +51,37 @@ const (
 	SAVE_SELLER                     = "INSERT INTO sellers (cid, company_name, address, telephone, locality_id) VALUES (?, ?, ?, ?, ?)"
 	UPDATE_SELLER                   = "UPDATE sellers SET cid=?, company_name=?, address=?, telephone=?, locality_id=? WHERE id=?"
 	DELETE_SELLER                   = "DELETE FROM sellers WHERE id=?"
+	LOCK_SELLER                     = "SELECT id FROM sellers WHERE id=? FOR UPDATE;"
+	GET_SELLER_PRODUCT_IDS          = "SELECT id FROM products WHERE id_seller=? ORDER BY id;"
+	REASSIGN_SELLER_PRODUCTS        = "UPDATE products SET id_seller=? WHERE id_seller=?;"
+	DETACH_SELLER_PRODUCTS          = "UPDATE products SET id_seller=NULL WHERE id_seller=?;"
+	GET_SELLER_PRODUCTS             = "SELECT id, description, expiration_rate, freezing_rate, height, lenght, netweight, product_code, recommended_freezing_temperature, width, id_product_type, id_seller FROM products WHERE id_seller=? ORDER BY id;"
 	MySqlNumberForeignKeyConstraint = 1452
+	MySqlNumberRowIsReferenced      = 1451
+	// GET_SELLERS_PERFORMANCE is completed with the conditions of the period on sold.order_date and GROUP_SELLERS_PERFORMANCE,
+	// sales are summed in a derived table so the products and batches of a seller are not repeated per item.
+	// An order placed only with a product_record_id and without items is sold as one unit of the product of the record,
+	// and the stock on hand is the quantity the batches can still be picked from, like PickableProductBatches
+	GET_SELLERS_PERFORMANCE = `SELECT s.id, s.company_name,
+	(SELECT COUNT(*) FROM products AS p WHERE p.id_seller = s.id),
+	(SELECT IFNULL(SUM(GREATEST(pb.current_quantity - pb.reserved_quantity, 0)), 0) FROM product_batches AS pb INNER JOIN products AS p ON p.id = pb.product_id
+		WHERE p.id_seller = s.id AND pb.due_date >= CURDATE() AND NOT pb.quarantined),
+	IFNULL(sales.units, 0), IFNULL(sales.revenue, 0)
+	FROM sellers AS s
+	LEFT JOIN (SELECT sold.id_seller, SUM(sold.quantity) AS units, ROUND(SUM(sold.quantity * sold.sale_price), 2) AS revenue
+		FROM (SELECT p.id_seller, oi.quantity, IFNULL(pr.sale_price, 0) AS sale_price, po.order_date, po.order_status_id
+			FROM order_items AS oi
+			INNER JOIN purchase_orders AS po ON po.id = oi.purchase_order_id
+			INNER JOIN products AS p ON p.id = oi.product_id
+			INNER JOIN product_records AS pr ON pr.id = oi.product_record_id
+			UNION ALL
+			SELECT p.id_seller, 1, IFNULL(pr.sale_price, 0), po.order_date, po.order_status_id
+			FROM purchase_orders AS po
+			INNER JOIN product_records AS pr ON pr.id = po.product_record_id
+			INNER JOIN products AS p ON p.id = pr.product_id
+			WHERE NOT EXISTS (SELECT 1 FROM order_items AS oi WHERE oi.purchase_order_id = po.id)) AS sold
+		WHERE sold.order_status_id <> ?`
+	GROUP_SELLERS_PERFORMANCE = " GROUP BY sold.id_seller) AS sales ON sales.id_seller = s.id ORDER BY s.id;"
 )
 
 func NewRepository(db *sql.DB) Repository {
@@ -149,26 +190,133 @@ func (r *repository) Update(ctx context.Context, s domain.Seller) error {
 	return nil
 }
 
-func (r *repository) Delete(ctx context.Context, id int) error {
-	stmt, err := r.db.Prepare(DELETE_SELLER)
+func (r *repository) Delete(ctx context.Context, id int, options domain.DeleteOptions) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return ErrInternal
 	}
-	defer CloseStmt(stmt)
 
-	res, err := stmt.Exec(id)
-	if err != nil {
+	if err := deleteSeller(ctx, tx, id, options); err != nil {
+		_ = tx.Rollback()
 		return err
 	}
 
-	affect, err := res.RowsAffected()
-	if err != nil {
+	if err := tx.Commit(); err != nil {
+		return ErrInternal
+	}
+	return nil
+}
+
+// deleteSeller locks the seller, moves its products as the strategy says and deletes it inside the transaction
+func deleteSeller(ctx context.Context, tx *sql.Tx, id int, options domain.DeleteOptions) error {
+	if err := lockSeller(ctx, tx, id, ErrNotFound); err != nil {
 		return err
 	}
 
-	if affect < 1 {
-		return ErrNotFound
+	switch options.Strategy {
+	case domain.DeleteReassign:
+		if err := lockSeller(ctx, tx, options.To, ErrTargetNotFound); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, REASSIGN_SELLER_PRODUCTS, options.To, id); err != nil {
+			return ErrInternal
+		}
+	case domain.DeleteDetach:
+		if _, err := tx.ExecContext(ctx, DETACH_SELLER_PRODUCTS, id); err != nil {
+			return ErrInternal
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, DELETE_SELLER, id); err != nil {
+		if mysqlError, ok := err.(*mysql.MySQLError); ok && mysqlError.Number == MySqlNumberRowIsReferenced {
+			return ErrHasProducts
+		}
+		return ErrInternal
 	}
+	return nil
+}
 
+// lockSeller locks the row of the seller until the end of the transaction, or returns notFound
+func lockSeller(ctx context.Context, tx *sql.Tx, id int, notFound error) error {
+	if err := tx.QueryRowContext(ctx, LOCK_SELLER, id).Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return notFound
+		}
+		return ErrInternal
+	}
 	return nil
 }
+
+func (r *repository) GetDependents(ctx context.Context, id int) (map[string][]int, error) {
+	rows, err := r.db.QueryContext(ctx, GET_SELLER_PRODUCT_IDS, id)
+	if err != nil {
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+
+	products := []int{}
+	for rows.Next() {
+		var productID int
+		if err := rows.Scan(&productID); err != nil {
+			return nil, ErrInternal
+		}
+		products = append(products, productID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, ErrInternal
+	}
+	return map[string][]int{"products": products}, nil
+}
+
+func (r *repository) GetProducts(ctx context.Context, id int) ([]domain.Product, error) {
+	rows, err := r.db.QueryContext(ctx, GET_SELLER_PRODUCTS, id)
+	if err != nil {
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+
+	products := []domain.Product{}
+	for rows.Next() {
+		p := domain.Product{}
+		if err := rows.Scan(&p.ID, &p.Description, &p.ExpirationRate, &p.FreezingRate, &p.Height, &p.Length, &p.NetWeight, &p.ProductCode, &p.RecommendedFreezingTemperature, &p.Width, &p.ProductTypeID, &p.SellerID); err != nil {
+			return nil, ErrInternal
+		}
+		products = append(products, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, ErrInternal
+	}
+	return products, nil
+}
+
+func (r *repository) GetPerformance(ctx context.Context, from *time.Time, to *time.Time) ([]domain.SellerPerformance, error) {
+	query := GET_SELLERS_PERFORMANCE
+	args := []interface{}{domain.OrderStatusCancelled}
+	if from != nil {
+		query += " AND sold.order_date >= ?"
+		args = append(args, from.Format(domain.ISO8601))
+	}
+	if to != nil {
+		query += " AND sold.order_date <= ?"
+		args = append(args, to.Format(domain.ISO8601))
+	}
+
+	rows, err := r.db.QueryContext(ctx, query+GROUP_SELLERS_PERFORMANCE, args...)
+	if err != nil {
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+
+	performances := []domain.SellerPerformance{}
+	for rows.Next() {
+		p := domain.SellerPerformance{}
+		if err := rows.Scan(&p.SellerID, &p.CompanyName, &p.ProductsCount, &p.StockOnHand, &p.UnitsSold, &p.Revenue); err != nil {
+			return nil, ErrInternal
+		}
+		performances = append(performances, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, ErrInternal
+	}
+	return performances, nil
+}
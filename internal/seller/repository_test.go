@@ -6,6 +6,7 @@ import (
 	"errors"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
@@ -421,36 +422,224 @@ func TestDelete_Selle_OK(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectPrepare(regexp.QuoteMeta(DELETE_SELLER))
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_SELLER)).WithArgs(seller_test.ID).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(seller_test.ID))
 	mock.ExpectExec(regexp.QuoteMeta(DELETE_SELLER)).WithArgs(seller_test.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
 
 	repository := NewRepository(db)
 
 	// Act
-	result := repository.Delete(context.TODO(), seller_test.ID)
+	result := repository.Delete(context.TODO(), seller_test.ID, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 
 	// Assert
 	assert.NoError(t, result)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-// TestDelete_Seller_FailPrepare passes when function Prepare returns an error
-func TestDelete_Seller_FailPrepare(t *testing.T) {
+// TestDelete_Seller_FailBegin passes when the transaction can not start
+func TestDelete_Seller_FailBegin(t *testing.T) {
 	// Arrange
-	expectedError := ErrInternal
-
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectPrepare(regexp.QuoteMeta(DELETE_SELLER)).WillReturnError(expectedError)
+	mock.ExpectBegin().WillReturnError(sql.ErrConnDone)
 
 	// Act
 	repository := NewRepository(db)
 
-	result := repository.Delete(context.TODO(), seller_test.ID)
+	result := repository.Delete(context.TODO(), seller_test.ID, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 
 	// Assert
-	assert.EqualError(t, result, expectedError.Error())
+	assert.EqualError(t, result, ErrInternal.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestDelete_Seller_FailNotFound passes when the seller does not exist and nothing is changed
+func TestDelete_Seller_FailNotFound(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_SELLER)).WithArgs(seller_test.ID).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	// Act
+	result := NewRepository(db).Delete(context.TODO(), seller_test.ID, domain.DeleteOptions{Strategy: domain.DeleteDetach})
+
+	// Assert
+	assert.EqualError(t, result, ErrNotFound.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestDelete_Seller_FailHasProducts passes when the block strategy finds products of the seller
+func TestDelete_Seller_FailHasProducts(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_SELLER)).WithArgs(seller_test.ID).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(seller_test.ID))
+	mock.ExpectExec(regexp.QuoteMeta(DELETE_SELLER)).WithArgs(seller_test.ID).WillReturnError(&mysql.MySQLError{Number: MySqlNumberRowIsReferenced})
+	mock.ExpectRollback()
+
+	// Act
+	result := NewRepository(db).Delete(context.TODO(), seller_test.ID, domain.DeleteOptions{Strategy: domain.DeleteBlock})
+
+	// Assert
+	assert.EqualError(t, result, ErrHasProducts.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestDelete_Seller_Reassign passes when the products move to the other seller before the delete
+func TestDelete_Seller_Reassign(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_SELLER)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_SELLER)).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectExec(regexp.QuoteMeta(REASSIGN_SELLER_PRODUCTS)).WithArgs(2, 1).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta(DELETE_SELLER)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	result := NewRepository(db).Delete(context.TODO(), 1, domain.DeleteOptions{Strategy: domain.DeleteReassign, To: 2})
+
+	// Assert
+	assert.NoError(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestDelete_Seller_FailTargetNotFound passes when the seller to reassign to does not exist
+func TestDelete_Seller_FailTargetNotFound(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_SELLER)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_SELLER)).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	// Act
+	result := NewRepository(db).Delete(context.TODO(), 1, domain.DeleteOptions{Strategy: domain.DeleteReassign, To: 2})
+
+	// Assert
+	assert.EqualError(t, result, ErrTargetNotFound.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestDelete_Seller_Detach passes when the products are left without seller before the delete
+func TestDelete_Seller_Detach(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_SELLER)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec(regexp.QuoteMeta(DETACH_SELLER_PRODUCTS)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta(DELETE_SELLER)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	result := NewRepository(db).Delete(context.TODO(), 1, domain.DeleteOptions{Strategy: domain.DeleteDetach})
+
+	// Assert
+	assert.NoError(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// * ---------------------- GetDependents ----------------------------
+// TestGetDependents_Seller_OK passes when return the ids of the products of the seller
+func TestGetDependents_Seller_OK(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(GET_SELLER_PRODUCT_IDS)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3).AddRow(5))
+
+	// Act
+	result, err := NewRepository(db).GetDependents(context.TODO(), 1)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]int{"products": {3, 5}}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// * ---------------------- GetProducts ----------------------------
+// TestGetProducts_Seller_OK passes when return the products of the seller
+func TestGetProducts_Seller_OK(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	columns := []string{"id", "description", "expiration_rate", "freezing_rate", "height", "lenght", "netweight", "product_code", "recommended_freezing_temperature", "width", "id_product_type", "id_seller"}
+	rows := sqlmock.NewRows(columns).AddRow(3, "milk", 2, 1, 10, 5, 1, "P3", -2, 5, 2, seller_test.ID)
+	mock.ExpectQuery(regexp.QuoteMeta(GET_SELLER_PRODUCTS)).WithArgs(seller_test.ID).WillReturnRows(rows)
+
+	// Act
+	result, err := NewRepository(db).GetProducts(context.TODO(), seller_test.ID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Product{{ID: 3, Description: "milk", ExpirationRate: 2, FreezingRate: 1, Height: 10, Length: 5, NetWeight: 1,
+		ProductCode: "P3", RecommendedFreezingTemperature: -2, Width: 5, ProductTypeID: 2, SellerID: &seller_test.ID}}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// * ---------------------- GetPerformance ----------------------------
+// TestGetPerformance_Seller_OK passes when the period conditions are added and every seller is returned
+func TestGetPerformance_Seller_OK(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "company_name", "products_count", "stock_on_hand", "units", "revenue"}
+	rows := sqlmock.NewRows(columns).AddRow(1, "Kiosco 1", 2, 40, 5, 52.5).AddRow(2, "Kiosco 2", 0, 0, 0, 0)
+	query := GET_SELLERS_PERFORMANCE + " AND sold.order_date >= ? AND sold.order_date <= ?" + GROUP_SELLERS_PERFORMANCE
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(domain.OrderStatusCancelled, "2022-01-01", "2022-01-31").WillReturnRows(rows)
+
+	// Act
+	result, err := NewRepository(db).GetPerformance(context.TODO(), &from, &to)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.SellerPerformance{
+		{SellerID: 1, CompanyName: "Kiosco 1", ProductsCount: 2, StockOnHand: 40, UnitsSold: 5, Revenue: 52.5},
+		{SellerID: 2, CompanyName: "Kiosco 2"},
+	}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestGetPerformance_Seller_FailQuery passes when the query returns an error
+func TestGetPerformance_Seller_FailQuery(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(GET_SELLERS_PERFORMANCE + GROUP_SELLERS_PERFORMANCE)).WithArgs(domain.OrderStatusCancelled).WillReturnError(sql.ErrConnDone)
+
+	// Act
+	result, err := NewRepository(db).GetPerformance(context.TODO(), nil, nil)
+
+	// Assert
+	assert.Nil(t, result)
+	assert.EqualError(t, err, ErrInternal.Error())
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -3,6 +3,7 @@ package seller
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
@@ -13,15 +14,23 @@ var (
 	ServiceErrInternal           = errors.New("internal error")
 	ServiceErrAlreadyExists      = errors.New("seller code already exists")
 	ServiceErrForeignKeyNotFound = errors.New("locality not found")
+	ServiceErrDateRange          = errors.New("from can not be after to")
+	ServiceErrInvalidStrategy    = errors.New("strategy must be block, reassign or detach, reassign needs the id of another seller in to")
 )
 
+// DeleteStrategies are the strategies a seller can be deleted with, products can be left without seller
+var DeleteStrategies = []string{domain.DeleteBlock, domain.DeleteReassign, domain.DeleteDetach}
+
 // Service represents a service layer for Seller
 type Service interface {
 	GetAll(ctx context.Context) ([]domain.Seller, error)
 	Create(ctx context.Context, sell domain.Seller) (domain.Seller, error)
 	Get(ctx context.Context, id int) (domain.Seller, error)
-	Delete(ctx context.Context, id int) error
+	Delete(ctx context.Context, id int, options domain.DeleteOptions) error
+	DeletePreview(ctx context.Context, id int) (domain.DeletePreview, error)
 	Update(context.Context, int, *int, *string, *string, *string, *string) (domain.Seller, error)
+	GetProducts(ctx context.Context, id int) ([]domain.Product, error)
+	GetPerformance(ctx context.Context, from *time.Time, to *time.Time) ([]domain.SellerPerformance, error)
 }
 
 type service struct {
@@ -80,9 +89,15 @@ func (s *service) Get(ctx context.Context, id int) (seller domain.Seller, err er
 	return
 }
 
-// Delete receive an id and delete from db
-func (s *service) Delete(ctx context.Context, id int) (err error) {
-	err = s.repository.Delete(ctx, id)
+// Delete receive an id and delete from db, its products are reassigned or detached as the options say
+// or the delete fails with ErrHasProducts
+func (s *service) Delete(ctx context.Context, id int, options domain.DeleteOptions) (err error) {
+	if !options.Valid(id, DeleteStrategies) {
+		logging.Log(ServiceErrInvalidStrategy)
+		return ServiceErrInvalidStrategy
+	}
+
+	err = s.repository.Delete(ctx, id, options)
 	if err != nil {
 		logging.Log(err)
 		return
@@ -141,3 +156,49 @@ func (s *service) Update(ctx context.Context, id int, cid *int, companyName, add
 
 	return
 }
+
+// GetProducts returns the products of a seller, or ErrNotFound if the seller does not exist
+func (s *service) GetProducts(ctx context.Context, id int) (products []domain.Product, err error) {
+	if _, err = s.repository.Get(ctx, id); err != nil {
+		logging.Log(err)
+		return nil, err
+	}
+
+	products, err = s.repository.GetProducts(ctx, id)
+	if err != nil {
+		logging.Log(err)
+		return nil, err
+	}
+	return
+}
+
+// GetPerformance returns the products, stock, units sold and revenue of every seller in the period
+func (s *service) GetPerformance(ctx context.Context, from *time.Time, to *time.Time) (performances []domain.SellerPerformance, err error) {
+	if from != nil && to != nil && from.After(*to) {
+		logging.Log(ServiceErrDateRange)
+		return nil, ServiceErrDateRange
+	}
+
+	performances, err = s.repository.GetPerformance(ctx, from, to)
+	if err != nil {
+		logging.Log(err)
+		return nil, err
+	}
+	return
+}
+
+// DeletePreview returns the products a delete of the seller affects
+func (s *service) DeletePreview(ctx context.Context, id int) (preview domain.DeletePreview, err error) {
+	if _, err = s.repository.Get(ctx, id); err != nil {
+		logging.Log(err)
+		return
+	}
+
+	dependents, err := s.repository.GetDependents(ctx, id)
+	if err != nil {
+		logging.Log(err)
+		return
+	}
+	preview = domain.NewDeletePreview(id, DeleteStrategies, dependents)
+	return
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
@@ -233,7 +234,7 @@ func TestDelete_Seller(t *testing.T) {
 	service := NewService(&mockRepo)
 
 	// Act
-	err := service.Delete(ctx, 1)
+	err := service.Delete(ctx, 1, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 
 	// Assert
 	assert.Nil(t, err)
@@ -267,12 +268,72 @@ func TestDeleteFail_Seller(t *testing.T) {
 	service := NewService(&mockRepo)
 
 	// Act
-	err := service.Delete(ctx, 1)
+	err := service.Delete(ctx, 1, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 
 	// Assert
 	assert.EqualError(t, expectedError, err.Error())
 }
 
+// TestDeleteInvalidStrategy_Seller passes when the strategy is unknown or reassign has no other seller
+func TestDeleteInvalidStrategy_Seller(t *testing.T) {
+	// Arrange
+	mockRepo := MockRepositorySeller{}
+	service := NewService(&mockRepo)
+	cases := []domain.DeleteOptions{{}, {Strategy: "cascade"}, {Strategy: domain.DeleteReassign}, {Strategy: domain.DeleteReassign, To: 1}}
+
+	for _, options := range cases {
+		// Act
+		err := service.Delete(ctx, 1, options)
+
+		// Assert
+		assert.ErrorIs(t, err, ServiceErrInvalidStrategy, options.Strategy)
+	}
+	assert.Empty(t, mockRepo.DeleteOptions)
+}
+
+// TestDeleteReassign_Seller passes when the options reach the repository
+func TestDeleteReassign_Seller(t *testing.T) {
+	// Arrange
+	mockRepo := MockRepositorySeller{}
+	service := NewService(&mockRepo)
+	options := domain.DeleteOptions{Strategy: domain.DeleteReassign, To: 2}
+
+	// Act
+	err := service.Delete(ctx, 1, options)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, options, mockRepo.DeleteOptions)
+}
+
+// TestDeletePreview_Seller passes when return the products of the seller and the strategies
+func TestDeletePreview_Seller(t *testing.T) {
+	// Arrange
+	mockRepo := MockRepositorySeller{Seller: domain.Seller{ID: 1}, Dependents: map[string][]int{"products": {3, 5}}}
+	service := NewService(&mockRepo)
+
+	// Act
+	result, err := service.DeletePreview(ctx, 1)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, domain.DeletePreview{ID: 1, Strategies: DeleteStrategies, Blocked: true, Dependents: map[string][]int{"products": {3, 5}}}, result)
+}
+
+// TestDeletePreviewNoProducts_Seller passes when a seller without products is not blocked
+func TestDeletePreviewNoProducts_Seller(t *testing.T) {
+	// Arrange
+	service := NewService(&MockRepositorySeller{Seller: domain.Seller{ID: 1}})
+
+	// Act
+	result, err := service.DeletePreview(ctx, 1)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.False(t, result.Blocked)
+	assert.Equal(t, map[string][]int{"products": {}}, result.Dependents)
+}
+
 // * ---------------------- Update ---------------------------
 // TestUpdate_Seller passes when return seller updated
 func TestUpdate_Seller(t *testing.T) {
@@ -404,3 +465,69 @@ func TestUpdateErrorFail_Seller(t *testing.T) {
 	assert.EqualError(t, expectedError, err.Error())
 	assert.Equal(t, domain.Seller{}, result)
 }
+
+// * ---------------------- GetProducts ---------------------------
+// TestGetProducts_Seller passes when return the products of the seller
+func TestGetProducts_Seller(t *testing.T) {
+	// Arrange
+	sellerID := 1
+	products := []domain.Product{{ID: 3, Description: "milk", SellerID: &sellerID}}
+	mockRepo := MockRepositorySeller{Seller: domain.Seller{ID: 1}, Products: products}
+	service := NewService(&mockRepo)
+
+	// Act
+	result, err := service.GetProducts(ctx, 1)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, products, result)
+}
+
+// TestGetProducts_IDNonExistent_Seller passes when the seller does not exist
+func TestGetProducts_IDNonExistent_Seller(t *testing.T) {
+	// Arrange
+	mockRepo := MockRepositorySeller{ErrorMock: ErrNotFound}
+	service := NewService(&mockRepo)
+
+	// Act
+	result, err := service.GetProducts(ctx, 7)
+
+	// Assert
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+// * ---------------------- GetPerformance ---------------------------
+// TestGetPerformance_Seller passes when return the performance of the sellers in the period
+func TestGetPerformance_Seller(t *testing.T) {
+	// Arrange
+	performance := []domain.SellerPerformance{{SellerID: 1, CompanyName: "Kiosco 1", ProductsCount: 2, StockOnHand: 40, UnitsSold: 5, Revenue: 52.5}}
+	mockRepo := MockRepositorySeller{Performance: performance}
+	service := NewService(&mockRepo)
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Act
+	result, err := service.GetPerformance(ctx, &from, nil)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, performance, result)
+	assert.Equal(t, &from, mockRepo.LastFrom)
+	assert.Nil(t, mockRepo.LastTo)
+}
+
+// TestGetPerformanceDateRange_Seller passes when from is after to
+func TestGetPerformanceDateRange_Seller(t *testing.T) {
+	// Arrange
+	mockRepo := MockRepositorySeller{}
+	service := NewService(&mockRepo)
+	from := time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Act
+	_, err := service.GetPerformance(ctx, &from, &to)
+
+	// Assert
+	assert.ErrorIs(t, err, ServiceErrDateRange)
+	assert.Nil(t, mockRepo.LastFrom)
+}
@@ -0,0 +1,348 @@
+package shipment
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+	"github.com/go-sql-driver/mysql"
+)
+
+// Errors
+var (
+	ErrNotFound             = errors.New("shipment not found")
+	ErrCarryNotFound        = errors.New("carry not found")
+	ErrOrderNotFound        = errors.New("purchase order not found")
+	ErrOrderNotAssignable   = errors.New("only picking purchase orders can be assigned to a carry")
+	ErrOrderAlreadyAssigned = errors.New("purchase order already belongs to a shipment")
+	ErrTrackingCodeExists   = errors.New("tracking code already exists")
+	ErrInvalidState         = errors.New("shipment changed its status")
+	ErrInternal             = errors.New("internal server error")
+)
+
+// Queries
+const (
+	CARRY_EXISTS_QUERY          = "SELECT id FROM carries WHERE id = ?;"
+	LOCK_ORDERS_QUERY           = "SELECT id, order_status_id FROM purchase_orders WHERE id IN "
+	INSERT_SHIPMENT_QUERY       = "INSERT INTO shipments (carry_id, tracking_code, created_at) VALUES (?, ?, ?);"
+	INSERT_SHIPMENT_ORDER_QUERY = "INSERT INTO shipment_orders (shipment_id, purchase_order_id) VALUES (?, ?);"
+	SET_TRACKING_CODE_QUERY     = "UPDATE purchase_orders SET tracking_code = ? WHERE id = ?;"
+	GET_SHIPMENTS_QUERY         = "SELECT id, carry_id, tracking_code, created_at, dispatched_at, delivered_at FROM shipments"
+	GET_SHIPMENT_QUERY          = GET_SHIPMENTS_QUERY + " WHERE id = ?;"
+	GET_CARRY_SHIPMENTS_QUERY   = GET_SHIPMENTS_QUERY + " WHERE carry_id = ? ORDER BY id;"
+	GET_SHIPMENT_ORDERS_QUERY   = "SELECT shipment_id, purchase_order_id FROM shipment_orders WHERE shipment_id IN "
+	SET_DISPATCHED_QUERY        = "UPDATE shipments SET dispatched_at = ? WHERE id = ? AND dispatched_at IS NULL;"
+	SET_DELIVERED_QUERY         = "UPDATE shipments SET delivered_at = ? WHERE id = ? AND dispatched_at IS NOT NULL AND delivered_at IS NULL;"
+	MySqlNumberDuplicate        = 1062
+)
+
+// Repository encapsulates the storage of the shipments and their purchase orders.
+type Repository interface {
+	// Save stores the shipment with its orders and sets its tracking code to every order, or stores nothing
+	Save(ctx context.Context, s domain.Shipment) (int, error)
+	Get(ctx context.Context, id int) (domain.Shipment, error)
+	GetByCarry(ctx context.Context, carryID int) ([]domain.Shipment, error)
+	// SetDispatched records the dispatch and makes the status changes of its orders, or neither
+	SetDispatched(ctx context.Context, id int, at time.Time, changes []domain.OrderStatusChange) error
+	// SetDelivered records the delivery and makes the status changes of its orders, or neither
+	SetDelivered(ctx context.Context, id int, at time.Time, changes []domain.OrderStatusChange) error
+}
+
+// OrderStatuses changes the status of a purchase order inside the transaction of a shipment
+type OrderStatuses interface {
+	UpdateStatusTx(ctx context.Context, tx *sql.Tx, change domain.OrderStatusChange) (int, error)
+}
+
+type repository struct {
+	db     *sql.DB
+	orders OrderStatuses
+}
+
+func NewRepository(db *sql.DB, orders OrderStatuses) Repository {
+	return &repository{
+		db:     db,
+		orders: orders,
+	}
+}
+
+func (r *repository) Save(ctx context.Context, s domain.Shipment) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Log(err)
+		return 0, ErrInternal
+	}
+
+	id, err := save(ctx, tx, s)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.Log(err)
+		return 0, ErrInternal
+	}
+	return id, nil
+}
+
+// save checks the carry, locks the orders and stores the shipment inside the transaction
+func save(ctx context.Context, tx *sql.Tx, s domain.Shipment) (int, error) {
+	var carryID int
+	if err := tx.QueryRowContext(ctx, CARRY_EXISTS_QUERY, s.CarryID).Scan(&carryID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logging.Log(ErrCarryNotFound)
+			return 0, ErrCarryNotFound
+		}
+		logging.Log(err)
+		return 0, ErrInternal
+	}
+
+	args := make([]interface{}, len(s.PurchaseOrderIDs))
+	for i, orderID := range s.PurchaseOrderIDs {
+		args[i] = orderID
+	}
+	rows, err := tx.QueryContext(ctx, LOCK_ORDERS_QUERY+placeholders(len(args))+" FOR UPDATE;", args...)
+	if err != nil {
+		logging.Log(err)
+		return 0, ErrInternal
+	}
+	found := 0
+	for rows.Next() {
+		var orderID, statusID int
+		if err := rows.Scan(&orderID, &statusID); err != nil {
+			rows.Close()
+			logging.Log(err)
+			return 0, ErrInternal
+		}
+		if statusID != domain.OrderStatusPicking {
+			rows.Close()
+			logging.Log(ErrOrderNotAssignable)
+			return 0, ErrOrderNotAssignable
+		}
+		found++
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		logging.Log(err)
+		return 0, ErrInternal
+	}
+	rows.Close()
+	if found != len(s.PurchaseOrderIDs) {
+		logging.Log(ErrOrderNotFound)
+		return 0, ErrOrderNotFound
+	}
+
+	res, err := tx.ExecContext(ctx, INSERT_SHIPMENT_QUERY, s.CarryID, s.TrackingCode, s.CreatedAt)
+	if err != nil {
+		return 0, parseWriteError(err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		logging.Log(err)
+		return 0, ErrInternal
+	}
+
+	for _, orderID := range s.PurchaseOrderIDs {
+		if _, err := tx.ExecContext(ctx, INSERT_SHIPMENT_ORDER_QUERY, id, orderID); err != nil {
+			return 0, parseWriteError(err)
+		}
+		if _, err := tx.ExecContext(ctx, SET_TRACKING_CODE_QUERY, s.TrackingCode, orderID); err != nil {
+			logging.Log(err)
+			return 0, ErrInternal
+		}
+	}
+	return int(id), nil
+}
+
+func (r *repository) Get(ctx context.Context, id int) (domain.Shipment, error) {
+	s, err := scanShipment(r.db.QueryRowContext(ctx, GET_SHIPMENT_QUERY, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logging.Log(ErrNotFound)
+			return domain.Shipment{}, ErrNotFound
+		}
+		logging.Log(err)
+		return domain.Shipment{}, ErrInternal
+	}
+
+	shipments := []domain.Shipment{s}
+	if err := r.addOrders(ctx, shipments); err != nil {
+		return domain.Shipment{}, err
+	}
+	return shipments[0], nil
+}
+
+// GetByCarry returns the shipments of the carry with their orders, or ErrCarryNotFound
+func (r *repository) GetByCarry(ctx context.Context, carryID int) ([]domain.Shipment, error) {
+	var id int
+	if err := r.db.QueryRowContext(ctx, CARRY_EXISTS_QUERY, carryID).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logging.Log(ErrCarryNotFound)
+			return nil, ErrCarryNotFound
+		}
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+
+	rows, err := r.db.QueryContext(ctx, GET_CARRY_SHIPMENTS_QUERY, carryID)
+	if err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+
+	shipments := []domain.Shipment{}
+	for rows.Next() {
+		s, err := scanShipment(rows)
+		if err != nil {
+			logging.Log(err)
+			return nil, ErrInternal
+		}
+		shipments = append(shipments, s)
+	}
+	if err := rows.Err(); err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+
+	if err := r.addOrders(ctx, shipments); err != nil {
+		return nil, err
+	}
+	return shipments, nil
+}
+
+// SetDispatched records the dispatch of the shipment, or returns ErrInvalidState if it was already dispatched
+func (r *repository) SetDispatched(ctx context.Context, id int, at time.Time, changes []domain.OrderStatusChange) error {
+	return r.setTimestamp(ctx, SET_DISPATCHED_QUERY, id, at, changes)
+}
+
+// SetDelivered records the delivery of a dispatched shipment, or returns ErrInvalidState
+// if it was not dispatched or was already delivered
+func (r *repository) SetDelivered(ctx context.Context, id int, at time.Time, changes []domain.OrderStatusChange) error {
+	return r.setTimestamp(ctx, SET_DELIVERED_QUERY, id, at, changes)
+}
+
+func (r *repository) setTimestamp(ctx context.Context, query string, id int, at time.Time, changes []domain.OrderStatusChange) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Log(err)
+		return ErrInternal
+	}
+
+	if err := r.moveOrders(ctx, tx, query, id, at, changes); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.Log(err)
+		return ErrInternal
+	}
+	return nil
+}
+
+// moveOrders sets the timestamp of the shipment and makes every status change inside the transaction
+func (r *repository) moveOrders(ctx context.Context, tx *sql.Tx, query string, id int, at time.Time, changes []domain.OrderStatusChange) error {
+	res, err := tx.ExecContext(ctx, query, at, id)
+	if err != nil {
+		logging.Log(err)
+		return ErrInternal
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		logging.Log(err)
+		return ErrInternal
+	}
+	if affected == 0 {
+		logging.Log(ErrInvalidState)
+		return ErrInvalidState
+	}
+
+	for _, change := range changes {
+		if _, err := r.orders.UpdateStatusTx(ctx, tx, change); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addOrders fills the purchase order ids of every shipment with a single query
+func (r *repository) addOrders(ctx context.Context, shipments []domain.Shipment) error {
+	if len(shipments) == 0 {
+		return nil
+	}
+	args := make([]interface{}, len(shipments))
+	index := make(map[int]int, len(shipments))
+	for i := range shipments {
+		args[i] = shipments[i].ID
+		index[shipments[i].ID] = i
+		shipments[i].PurchaseOrderIDs = []int{}
+	}
+
+	rows, err := r.db.QueryContext(ctx, GET_SHIPMENT_ORDERS_QUERY+placeholders(len(args))+" ORDER BY shipment_id, purchase_order_id;", args...)
+	if err != nil {
+		logging.Log(err)
+		return ErrInternal
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var shipmentID, orderID int
+		if err := rows.Scan(&shipmentID, &orderID); err != nil {
+			logging.Log(err)
+			return ErrInternal
+		}
+		i := index[shipmentID]
+		shipments[i].PurchaseOrderIDs = append(shipments[i].PurchaseOrderIDs, orderID)
+	}
+	if err := rows.Err(); err != nil {
+		logging.Log(err)
+		return ErrInternal
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanShipment(row rowScanner) (domain.Shipment, error) {
+	var s domain.Shipment
+	var dispatchedAt, deliveredAt sql.NullTime
+	if err := row.Scan(&s.ID, &s.CarryID, &s.TrackingCode, &s.CreatedAt, &dispatchedAt, &deliveredAt); err != nil {
+		return domain.Shipment{}, err
+	}
+	if dispatchedAt.Valid {
+		s.DispatchedAt = &dispatchedAt.Time
+	}
+	if deliveredAt.Valid {
+		s.DeliveredAt = &deliveredAt.Time
+	}
+	s.Status = domain.ShipmentStatus(s)
+	return s, nil
+}
+
+// parseWriteError translates the duplicate keys of the shipment tables into the errors of the package
+func parseWriteError(err error) error {
+	if mysqlError, ok := err.(*mysql.MySQLError); ok && mysqlError.Number == MySqlNumberDuplicate {
+		if strings.Contains(mysqlError.Message, "tracking_code") {
+			logging.Log(ErrTrackingCodeExists)
+			return ErrTrackingCodeExists
+		}
+		if strings.Contains(mysqlError.Message, "purchase_order_id") {
+			logging.Log(ErrOrderAlreadyAssigned)
+			return ErrOrderAlreadyAssigned
+		}
+	}
+	logging.Log(err)
+	return ErrInternal
+}
+
+// placeholders returns a parenthesized list of n placeholders for an IN condition
+func placeholders(n int) string {
+	return "(" + strings.TrimSuffix(strings.Repeat("?, ", n), ", ") + ")"
+}
@@ -0,0 +1,84 @@
+package shipment
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+)
+
+type MockRepository struct {
+	Data          []domain.Shipment
+	Err           error
+	ErrSave       error
+	LastShipment  domain.Shipment
+	DispatchedAt  time.Time
+	DeliveredAt   time.Time
+	ErrTimestamps error
+	Changes       []domain.OrderStatusChange
+}
+
+func (m *MockRepository) Save(ctx context.Context, s domain.Shipment) (int, error) {
+	if m.ErrSave != nil {
+		return 0, m.ErrSave
+	}
+	m.LastShipment = s
+	return len(m.Data) + 1, nil
+}
+
+func (m *MockRepository) Get(ctx context.Context, id int) (domain.Shipment, error) {
+	if m.Err != nil {
+		return domain.Shipment{}, m.Err
+	}
+	for _, s := range m.Data {
+		if s.ID == id {
+			return s, nil
+		}
+	}
+	return domain.Shipment{}, ErrNotFound
+}
+
+func (m *MockRepository) GetByCarry(ctx context.Context, carryID int) ([]domain.Shipment, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	result := []domain.Shipment{}
+	for _, s := range m.Data {
+		if s.CarryID == carryID {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockRepository) SetDispatched(ctx context.Context, id int, at time.Time, changes []domain.OrderStatusChange) error {
+	if m.ErrTimestamps != nil {
+		return m.ErrTimestamps
+	}
+	m.DispatchedAt = at
+	m.Changes = changes
+	return nil
+}
+
+func (m *MockRepository) SetDelivered(ctx context.Context, id int, at time.Time, changes []domain.OrderStatusChange) error {
+	if m.ErrTimestamps != nil {
+		return m.ErrTimestamps
+	}
+	m.DeliveredAt = at
+	m.Changes = changes
+	return nil
+}
+
+type MockOrderStatuses struct {
+	Changes []domain.OrderStatusChange
+	Err     error
+}
+
+func (m *MockOrderStatuses) UpdateStatusTx(ctx context.Context, tx *sql.Tx, change domain.OrderStatusChange) (int, error) {
+	if m.Err != nil {
+		return 0, m.Err
+	}
+	m.Changes = append(m.Changes, change)
+	return len(m.Changes), nil
+}
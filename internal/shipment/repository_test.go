@@ -0,0 +1,211 @@
+package shipment
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	logging.InitLog(nil)
+}
+
+var (
+	shipmentColumns      = []string{"id", "carry_id", "tracking_code", "created_at", "dispatched_at", "delivered_at"}
+	shipmentOrderColumns = []string{"shipment_id", "purchase_order_id"}
+	lockedOrderColumns   = []string{"id", "order_status_id"}
+	shipmentCreatedAt    = time.Date(2022, 5, 5, 10, 0, 0, 0, time.UTC)
+)
+
+// TestSaveSuccess passes when the shipment, its orders and their tracking code are stored in one transaction
+func TestSaveSuccess(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(CARRY_EXISTS_QUERY)).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_ORDERS_QUERY+"(?, ?) FOR UPDATE;")).WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows(lockedOrderColumns).AddRow(1, domain.OrderStatusPicking).AddRow(2, domain.OrderStatusPicking))
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_SHIPMENT_QUERY)).WithArgs(3, "TRK-1", shipmentCreatedAt).WillReturnResult(sqlmock.NewResult(9, 1))
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_SHIPMENT_ORDER_QUERY)).WithArgs(9, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(SET_TRACKING_CODE_QUERY)).WithArgs("TRK-1", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_SHIPMENT_ORDER_QUERY)).WithArgs(9, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(SET_TRACKING_CODE_QUERY)).WithArgs("TRK-1", 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	id, err := NewRepository(db, &MockOrderStatuses{}).Save(context.Background(), domain.Shipment{CarryID: 3, TrackingCode: "TRK-1", PurchaseOrderIDs: []int{1, 2}, CreatedAt: shipmentCreatedAt})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 9, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestSaveOrderNotAssignable passes when an order was already shipped and nothing is stored
+func TestSaveOrderNotAssignable(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(CARRY_EXISTS_QUERY)).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_ORDERS_QUERY + "(?) FOR UPDATE;")).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(lockedOrderColumns).AddRow(1, domain.OrderStatusShipped))
+	mock.ExpectRollback()
+
+	_, err = NewRepository(db, &MockOrderStatuses{}).Save(context.Background(), domain.Shipment{CarryID: 3, TrackingCode: "TRK-1", PurchaseOrderIDs: []int{1}, CreatedAt: shipmentCreatedAt})
+
+	assert.ErrorIs(t, err, ErrOrderNotAssignable)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestSaveOrderNotFound passes when one of the orders does not exist
+func TestSaveOrderNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(CARRY_EXISTS_QUERY)).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_ORDERS_QUERY+"(?, ?) FOR UPDATE;")).WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows(lockedOrderColumns).AddRow(1, domain.OrderStatusPicking))
+	mock.ExpectRollback()
+
+	_, err = NewRepository(db, &MockOrderStatuses{}).Save(context.Background(), domain.Shipment{CarryID: 3, TrackingCode: "TRK-1", PurchaseOrderIDs: []int{1, 2}, CreatedAt: shipmentCreatedAt})
+
+	assert.ErrorIs(t, err, ErrOrderNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestSaveTrackingCodeExists passes when the tracking code belongs to another shipment
+func TestSaveTrackingCodeExists(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(CARRY_EXISTS_QUERY)).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_ORDERS_QUERY + "(?) FOR UPDATE;")).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(lockedOrderColumns).AddRow(1, domain.OrderStatusPicking))
+	mock.ExpectExec(regexp.QuoteMeta(INSERT_SHIPMENT_QUERY)).
+		WillReturnError(&mysql.MySQLError{Number: MySqlNumberDuplicate, Message: "Duplicate entry 'TRK-1' for key 'shipments.tracking_code'"})
+	mock.ExpectRollback()
+
+	_, err = NewRepository(db, &MockOrderStatuses{}).Save(context.Background(), domain.Shipment{CarryID: 3, TrackingCode: "TRK-1", PurchaseOrderIDs: []int{1}, CreatedAt: shipmentCreatedAt})
+
+	assert.ErrorIs(t, err, ErrTrackingCodeExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestSaveCarryNotFound passes when the carry does not exist
+func TestSaveCarryNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(CARRY_EXISTS_QUERY)).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	_, err = NewRepository(db, &MockOrderStatuses{}).Save(context.Background(), domain.Shipment{CarryID: 3, PurchaseOrderIDs: []int{1}})
+
+	assert.ErrorIs(t, err, ErrCarryNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestGetByCarrySuccess passes when every shipment of the carry has its orders and status
+func TestGetByCarrySuccess(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dispatchedAt := shipmentCreatedAt.Add(time.Hour)
+	mock.ExpectQuery(regexp.QuoteMeta(CARRY_EXISTS_QUERY)).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta(GET_CARRY_SHIPMENTS_QUERY)).WithArgs(3).WillReturnRows(sqlmock.NewRows(shipmentColumns).
+		AddRow(1, 3, "TRK-1", shipmentCreatedAt, dispatchedAt, nil).
+		AddRow(2, 3, "TRK-2", shipmentCreatedAt, nil, nil))
+	mock.ExpectQuery(regexp.QuoteMeta(GET_SHIPMENT_ORDERS_QUERY+"(?, ?) ORDER BY shipment_id, purchase_order_id;")).WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows(shipmentOrderColumns).AddRow(1, 10).AddRow(1, 11).AddRow(2, 12))
+
+	result, err := NewRepository(db, &MockOrderStatuses{}).GetByCarry(context.Background(), 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Shipment{
+		{ID: 1, CarryID: 3, TrackingCode: "TRK-1", Status: domain.ShipmentDispatched, PurchaseOrderIDs: []int{10, 11}, CreatedAt: shipmentCreatedAt, DispatchedAt: &dispatchedAt},
+		{ID: 2, CarryID: 3, TrackingCode: "TRK-2", Status: domain.ShipmentAssigned, PurchaseOrderIDs: []int{12}, CreatedAt: shipmentCreatedAt},
+	}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestGetNotFound passes when the shipment does not exist
+func TestGetNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(GET_SHIPMENT_QUERY)).WithArgs(5).WillReturnRows(sqlmock.NewRows(shipmentColumns))
+
+	_, err = NewRepository(db, &MockOrderStatuses{}).Get(context.Background(), 5)
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestSetDispatchedMovesOrders passes when the dispatch and the status changes of its orders are committed together
+func TestSetDispatchedMovesOrders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	changes := []domain.OrderStatusChange{{PurchaseOrderId: 10, FromStatusId: domain.OrderStatusPicking, ToStatusId: domain.OrderStatusShipped}}
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(SET_DISPATCHED_QUERY)).WithArgs(shipmentCreatedAt, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	orders := MockOrderStatuses{}
+
+	err = NewRepository(db, &orders).SetDispatched(context.Background(), 1, shipmentCreatedAt, changes)
+
+	assert.NoError(t, err)
+	assert.Equal(t, changes, orders.Changes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestSetDispatchedOrderFails passes when a status change fails and the dispatch is rolled back
+func TestSetDispatchedOrderFails(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	changes := []domain.OrderStatusChange{{PurchaseOrderId: 10, FromStatusId: domain.OrderStatusPicking, ToStatusId: domain.OrderStatusShipped}}
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(SET_DISPATCHED_QUERY)).WithArgs(shipmentCreatedAt, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback()
+
+	err = NewRepository(db, &MockOrderStatuses{Err: ErrInternal}).SetDispatched(context.Background(), 1, shipmentCreatedAt, changes)
+
+	assert.ErrorIs(t, err, ErrInternal)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestSetDispatchedAlreadyDispatched passes when no shipment is updated
+func TestSetDispatchedAlreadyDispatched(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(SET_DISPATCHED_QUERY)).WithArgs(shipmentCreatedAt, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err = NewRepository(db, &MockOrderStatuses{}).SetDispatched(context.Background(), 1, shipmentCreatedAt, nil)
+
+	assert.ErrorIs(t, err, ErrInvalidState)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,198 @@
+package shipment
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+)
+
+// TrackingCodePrefix starts every generated tracking code
+const TrackingCodePrefix = "TRK-"
+
+var (
+	ErrInvalidOrders     = errors.New("a shipment needs positive purchase order ids, each one only once")
+	ErrOrderNotReady     = errors.New("purchase order can not follow the shipment")
+	ErrAlreadyDispatched = errors.New("shipment already dispatched")
+	ErrNotDispatched     = errors.New("shipment not dispatched")
+	ErrAlreadyDelivered  = errors.New("shipment already delivered")
+)
+
+// Orders is the part of the purchase orders service a shipment needs to read the status of its orders,
+// which only changes through the state machine of the orders
+type Orders interface {
+	Get(ctx context.Context, id int) (domain.PurchaseOrderDetail, error)
+}
+
+type Service interface {
+	// Save assigns the purchase orders to the carry with the given tracking code, or a generated one if it is empty
+	Save(ctx context.Context, carryID int, orderIDs []int, trackingCode string) (domain.Shipment, error)
+	Get(ctx context.Context, id int) (domain.Shipment, error)
+	GetByCarry(ctx context.Context, carryID int) ([]domain.Shipment, error)
+	// Dispatch ships every order of the shipment and records when it left
+	Dispatch(ctx context.Context, id int, actor string) (domain.Shipment, error)
+	// Deliver delivers every order of a dispatched shipment and records when it arrived
+	Deliver(ctx context.Context, id int, actor string) (domain.Shipment, error)
+}
+
+type service struct {
+	repository Repository
+	orders     Orders
+	now        func() time.Time
+}
+
+func NewService(r Repository, orders Orders) Service {
+	return &service{
+		repository: r,
+		orders:     orders,
+		now:        time.Now,
+	}
+}
+
+func (s *service) Save(ctx context.Context, carryID int, orderIDs []int, trackingCode string) (domain.Shipment, error) {
+	if !validOrderIDs(orderIDs) {
+		logging.Log(ErrInvalidOrders)
+		return domain.Shipment{}, ErrInvalidOrders
+	}
+	trackingCode = strings.TrimSpace(trackingCode)
+	if trackingCode == "" {
+		code, err := generateTrackingCode()
+		if err != nil {
+			logging.Log(err)
+			return domain.Shipment{}, ErrInternal
+		}
+		trackingCode = code
+	}
+
+	shipment := domain.Shipment{
+		CarryID:          carryID,
+		TrackingCode:     trackingCode,
+		Status:           domain.ShipmentAssigned,
+		PurchaseOrderIDs: orderIDs,
+		CreatedAt:        s.now().UTC().Truncate(time.Second),
+	}
+	id, err := s.repository.Save(ctx, shipment)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+	shipment.ID = id
+	return shipment, nil
+}
+
+func (s *service) Get(ctx context.Context, id int) (domain.Shipment, error) {
+	return s.repository.Get(ctx, id)
+}
+
+func (s *service) GetByCarry(ctx context.Context, carryID int) ([]domain.Shipment, error) {
+	return s.repository.GetByCarry(ctx, carryID)
+}
+
+func (s *service) Dispatch(ctx context.Context, id int, actor string) (domain.Shipment, error) {
+	shipment, err := s.repository.Get(ctx, id)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+	if shipment.DispatchedAt != nil {
+		logging.Log(ErrAlreadyDispatched)
+		return domain.Shipment{}, ErrAlreadyDispatched
+	}
+
+	at := s.now().UTC().Truncate(time.Second)
+	changes, err := s.orderChanges(ctx, shipment.PurchaseOrderIDs, domain.OrderStatusShipped, actor, at)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+	if err := s.repository.SetDispatched(ctx, id, at, changes); err != nil {
+		return domain.Shipment{}, err
+	}
+	shipment.DispatchedAt = &at
+	shipment.Status = domain.ShipmentStatus(shipment)
+	return shipment, nil
+}
+
+func (s *service) Deliver(ctx context.Context, id int, actor string) (domain.Shipment, error) {
+	shipment, err := s.repository.Get(ctx, id)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+	if shipment.DispatchedAt == nil {
+		logging.Log(ErrNotDispatched)
+		return domain.Shipment{}, ErrNotDispatched
+	}
+	if shipment.DeliveredAt != nil {
+		logging.Log(ErrAlreadyDelivered)
+		return domain.Shipment{}, ErrAlreadyDelivered
+	}
+
+	at := s.now().UTC().Truncate(time.Second)
+	changes, err := s.orderChanges(ctx, shipment.PurchaseOrderIDs, domain.OrderStatusDelivered, actor, at)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+	if err := s.repository.SetDelivered(ctx, id, at, changes); err != nil {
+		return domain.Shipment{}, err
+	}
+	shipment.DeliveredAt = &at
+	shipment.Status = domain.ShipmentStatus(shipment)
+	return shipment, nil
+}
+
+// orderChanges returns the status change of every order that is not in the status yet, or an error if any of them
+// cannot reach it. The orders cancelled after they were assigned stay in the shipment and are left out.
+// The repository makes the changes in the same transaction that records the dispatch or delivery
+func (s *service) orderChanges(ctx context.Context, orderIDs []int, to int, actor string, at time.Time) ([]domain.OrderStatusChange, error) {
+	var changes []domain.OrderStatusChange
+	for _, orderID := range orderIDs {
+		order, err := s.orders.Get(ctx, orderID)
+		if err != nil {
+			return nil, err
+		}
+		if order.OrderStatusId == to || order.OrderStatusId == domain.OrderStatusCancelled {
+			continue
+		}
+		if !domain.CanTransition(order.OrderStatusId, to) {
+			err := fmt.Errorf("%w: order %d is %s", ErrOrderNotReady, orderID, domain.OrderStatusNames[order.OrderStatusId])
+			logging.Log(err)
+			return nil, err
+		}
+		changes = append(changes, domain.OrderStatusChange{
+			PurchaseOrderId: orderID,
+			FromStatusId:    order.OrderStatusId,
+			FromStatus:      domain.OrderStatusNames[order.OrderStatusId],
+			ToStatusId:      to,
+			ToStatus:        domain.OrderStatusNames[to],
+			Actor:           actor,
+			ChangedAt:       at,
+		})
+	}
+	return changes, nil
+}
+
+func validOrderIDs(orderIDs []int) bool {
+	if len(orderIDs) == 0 {
+		return false
+	}
+	seen := make(map[int]bool, len(orderIDs))
+	for _, id := range orderIDs {
+		if id <= 0 || seen[id] {
+			return false
+		}
+		seen[id] = true
+	}
+	return true
+}
+
+// generateTrackingCode returns TrackingCodePrefix followed by 12 random hexadecimal characters
+func generateTrackingCode() (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return TrackingCodePrefix + strings.ToUpper(hex.EncodeToString(b)), nil
+}
@@ -0,0 +1,145 @@
+package shipment
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockOrders struct {
+	statuses map[int]int
+}
+
+func (m *mockOrders) Get(ctx context.Context, id int) (domain.PurchaseOrderDetail, error) {
+	return domain.PurchaseOrderDetail{ID: id, OrderStatusId: m.statuses[id]}, nil
+}
+
+var serviceNow = time.Date(2022, 5, 5, 10, 0, 0, 0, time.UTC)
+
+func newTestService(repo Repository, orders Orders) *service {
+	return &service{repository: repo, orders: orders, now: func() time.Time { return serviceNow }}
+}
+
+// TestSaveKeepsTrackingCode passes when the given tracking code is stored
+func TestSaveKeepsTrackingCode(t *testing.T) {
+	repo := MockRepository{}
+	s := newTestService(&repo, &mockOrders{})
+
+	result, err := s.Save(context.Background(), 3, []int{1, 2}, " TRK-1 ")
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Shipment{ID: 1, CarryID: 3, TrackingCode: "TRK-1", Status: domain.ShipmentAssigned, PurchaseOrderIDs: []int{1, 2}, CreatedAt: serviceNow}, result)
+	assert.Equal(t, "TRK-1", repo.LastShipment.TrackingCode)
+}
+
+// TestSaveGeneratesTrackingCode passes when a shipment without tracking code gets a generated one
+func TestSaveGeneratesTrackingCode(t *testing.T) {
+	repo := MockRepository{}
+	s := newTestService(&repo, &mockOrders{})
+
+	first, err := s.Save(context.Background(), 3, []int{1}, "")
+	assert.NoError(t, err)
+	second, err := s.Save(context.Background(), 3, []int{2}, "")
+	assert.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(first.TrackingCode, TrackingCodePrefix))
+	assert.Len(t, first.TrackingCode, len(TrackingCodePrefix)+12)
+	assert.NotEqual(t, first.TrackingCode, second.TrackingCode)
+}
+
+// TestSaveInvalidOrders passes when the order ids are missing or repeated
+func TestSaveInvalidOrders(t *testing.T) {
+	s := newTestService(&MockRepository{}, &mockOrders{})
+
+	_, errEmpty := s.Save(context.Background(), 3, nil, "")
+	_, errRepeated := s.Save(context.Background(), 3, []int{1, 1}, "")
+
+	assert.ErrorIs(t, errEmpty, ErrInvalidOrders)
+	assert.ErrorIs(t, errRepeated, ErrInvalidOrders)
+}
+
+// TestDispatchShipsOrders passes when every order is shipped through the state machine and the dispatch is recorded
+func TestDispatchShipsOrders(t *testing.T) {
+	repo := MockRepository{Data: []domain.Shipment{{ID: 1, CarryID: 3, PurchaseOrderIDs: []int{10, 11}}}}
+	orders := mockOrders{statuses: map[int]int{10: domain.OrderStatusPicking, 11: domain.OrderStatusPicking}}
+	s := newTestService(&repo, &orders)
+
+	result, err := s.Dispatch(context.Background(), 1, "carrier")
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ShipmentDispatched, result.Status)
+	assert.Equal(t, serviceNow, *result.DispatchedAt)
+	assert.Equal(t, serviceNow, repo.DispatchedAt)
+	assert.Len(t, repo.Changes, 2)
+	assert.Equal(t, domain.OrderStatusChange{PurchaseOrderId: 10, FromStatusId: domain.OrderStatusPicking, FromStatus: "picking",
+		ToStatusId: domain.OrderStatusShipped, ToStatus: "shipped", Actor: "carrier", ChangedAt: serviceNow}, repo.Changes[0])
+}
+
+// TestDispatchOrderNotReady passes when an order is not picking and no order changes
+func TestDispatchOrderNotReady(t *testing.T) {
+	repo := MockRepository{Data: []domain.Shipment{{ID: 1, CarryID: 3, PurchaseOrderIDs: []int{10, 11}}}}
+	orders := mockOrders{statuses: map[int]int{10: domain.OrderStatusPicking, 11: domain.OrderStatusConfirmed}}
+	s := newTestService(&repo, &orders)
+
+	_, err := s.Dispatch(context.Background(), 1, "carrier")
+
+	assert.ErrorIs(t, err, ErrOrderNotReady)
+	assert.Empty(t, repo.Changes)
+	assert.True(t, repo.DispatchedAt.IsZero())
+}
+
+// TestDispatchSkipsShippedOrders passes when the orders that are already shipped are not moved again
+func TestDispatchSkipsShippedOrders(t *testing.T) {
+	repo := MockRepository{Data: []domain.Shipment{{ID: 1, CarryID: 3, PurchaseOrderIDs: []int{10, 11}}}}
+	orders := mockOrders{statuses: map[int]int{10: domain.OrderStatusShipped, 11: domain.OrderStatusPicking}}
+	s := newTestService(&repo, &orders)
+
+	_, err := s.Dispatch(context.Background(), 1, "carrier")
+
+	assert.NoError(t, err)
+	assert.Len(t, repo.Changes, 1)
+	assert.Equal(t, 11, repo.Changes[0].PurchaseOrderId)
+}
+
+// TestDispatchSkipsCancelledOrders passes when the orders cancelled after they were assigned do not block the dispatch
+func TestDispatchSkipsCancelledOrders(t *testing.T) {
+	repo := MockRepository{Data: []domain.Shipment{{ID: 1, CarryID: 3, PurchaseOrderIDs: []int{10, 11}}}}
+	orders := mockOrders{statuses: map[int]int{10: domain.OrderStatusCancelled, 11: domain.OrderStatusPicking}}
+	s := newTestService(&repo, &orders)
+
+	result, err := s.Dispatch(context.Background(), 1, "carrier")
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ShipmentDispatched, result.Status)
+	assert.Len(t, repo.Changes, 1)
+	assert.Equal(t, 11, repo.Changes[0].PurchaseOrderId)
+}
+
+// TestDeliverNotDispatched passes when the shipment has not left yet
+func TestDeliverNotDispatched(t *testing.T) {
+	repo := MockRepository{Data: []domain.Shipment{{ID: 1, CarryID: 3, PurchaseOrderIDs: []int{10}}}}
+	s := newTestService(&repo, &mockOrders{})
+
+	_, err := s.Deliver(context.Background(), 1, "carrier")
+
+	assert.ErrorIs(t, err, ErrNotDispatched)
+}
+
+// TestDeliverDeliversOrders passes when every order of a dispatched shipment is delivered
+func TestDeliverDeliversOrders(t *testing.T) {
+	dispatchedAt := serviceNow.Add(-time.Hour)
+	repo := MockRepository{Data: []domain.Shipment{{ID: 1, CarryID: 3, PurchaseOrderIDs: []int{10}, DispatchedAt: &dispatchedAt}}}
+	orders := mockOrders{statuses: map[int]int{10: domain.OrderStatusShipped}}
+	s := newTestService(&repo, &orders)
+
+	result, err := s.Deliver(context.Background(), 1, "carrier")
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ShipmentDelivered, result.Status)
+	assert.Equal(t, serviceNow, repo.DeliveredAt)
+	assert.Equal(t, domain.OrderStatusDelivered, repo.Changes[0].ToStatusId)
+}
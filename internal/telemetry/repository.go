@@ -0,0 +1,146 @@
+package telemetry
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+	"github.com/go-sql-driver/mysql"
+)
+
+// Errors
+var (
+	ErrSectionNotFound     = errors.New("section not found")
+	ErrInternal            = errors.New("database internal error")
+	ErrForeignNotFoundCode = 1452
+)
+
+// DateTime is the layout used to store the timestamps of the readings, always in UTC
+const DateTime = "2006-01-02 15:04:05"
+
+// Queries
+const (
+	SaveReading              = "INSERT INTO section_temperatures (section_id, recorded_at, temperature) VALUES (?, ?, ?);"
+	UpdateCurrentTemperature = `UPDATE sections SET current_temperature = (
+		SELECT ROUND(temperature) FROM section_temperatures WHERE section_id = ? ORDER BY recorded_at DESC, id DESC LIMIT 1
+	) WHERE id = ?;`
+	ExistsSection = "SELECT id FROM sections WHERE id = ?;"
+	GetBuckets    = `SELECT DATE_FORMAT(TIMESTAMPADD(SECOND, FLOOR(TIMESTAMPDIFF(SECOND, '1970-01-01', recorded_at) / ?) * ?, '1970-01-01'), '%Y-%m-%dT%H:%i:%sZ') AS bucket_from,
+		MIN(temperature), AVG(temperature), MAX(temperature), COUNT(id)
+		FROM section_temperatures
+		WHERE section_id = ? AND recorded_at >= ? AND recorded_at < ?
+		GROUP BY bucket_from
+		ORDER BY bucket_from;`
+)
+
+func init() {
+	logging.InitLog(nil)
+}
+
+// Repository encapsulates the storage of the temperature readings of the sections.
+type Repository interface {
+	SaveReadings(ctx context.Context, readings []domain.SectionTemperature) ([]int, error)
+	Exists(ctx context.Context, sectionID int) bool
+	GetBuckets(ctx context.Context, sectionID int, from time.Time, to time.Time, bucket time.Duration) ([]domain.TemperatureBucket, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+// SaveReadings stores all the readings and refreshes the current temperature of their sections inside a single transaction,
+// the current temperature is taken from the latest reading of each section
+func (r *repository) SaveReadings(ctx context.Context, readings []domain.SectionTemperature) ([]int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, SaveReading)
+	if err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	defer stmt.Close()
+
+	ids := make([]int, 0, len(readings))
+	var sections []int
+	seen := map[int]bool{}
+	for _, reading := range readings {
+		res, err := stmt.ExecContext(ctx, reading.SectionID, reading.RecordedAt.UTC().Format(DateTime), reading.Temperature)
+		if err != nil {
+			logging.Log(err)
+			if message, ok := err.(*mysql.MySQLError); ok && int(message.Number) == ErrForeignNotFoundCode {
+				return nil, ErrSectionNotFound
+			}
+			return nil, ErrInternal
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			logging.Log(err)
+			return nil, ErrInternal
+		}
+		ids = append(ids, int(id))
+		if !seen[reading.SectionID] {
+			seen[reading.SectionID] = true
+			sections = append(sections, reading.SectionID)
+		}
+	}
+
+	for _, sectionID := range sections {
+		if _, err := tx.ExecContext(ctx, UpdateCurrentTemperature, sectionID, sectionID); err != nil {
+			logging.Log(err)
+			return nil, ErrInternal
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	return ids, nil
+}
+
+func (r *repository) Exists(ctx context.Context, sectionID int) bool {
+	row := r.db.QueryRow(ExistsSection, sectionID)
+	err := row.Scan(&sectionID)
+	return err == nil
+}
+
+// GetBuckets groups the readings of the section taken between from (inclusive) and to (exclusive) in buckets of the given duration,
+// buckets without readings are not returned
+func (r *repository) GetBuckets(ctx context.Context, sectionID int, from time.Time, to time.Time, bucket time.Duration) ([]domain.TemperatureBucket, error) {
+	seconds := int(bucket.Seconds())
+	rows, err := r.db.QueryContext(ctx, GetBuckets, seconds, seconds, sectionID, from.UTC().Format(DateTime), to.UTC().Format(DateTime))
+	if err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+
+	buckets := []domain.TemperatureBucket{}
+	for rows.Next() {
+		b := domain.TemperatureBucket{}
+		if err := rows.Scan(&b.From, &b.Minimum, &b.Average, &b.Maximum, &b.Readings); err != nil {
+			logging.Log(err)
+			return nil, ErrInternal
+		}
+		buckets = append(buckets, b)
+	}
+	if err := rows.Err(); err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
+	}
+	return buckets, nil
+}
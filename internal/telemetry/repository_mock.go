@@ -0,0 +1,43 @@
+package telemetry
+
+import (
+	"context"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+)
+
+type MockRepository struct {
+	DataMockReadings []domain.SectionTemperature
+	DataMockBuckets  []domain.TemperatureBucket
+	MissingSection   bool
+	Error            error
+	LastFrom         time.Time
+	LastTo           time.Time
+	LastBucket       time.Duration
+}
+
+func (r *MockRepository) SaveReadings(ctx context.Context, readings []domain.SectionTemperature) ([]int, error) {
+	if r.Error != nil {
+		return nil, r.Error
+	}
+	ids := make([]int, 0, len(readings))
+	for _, reading := range readings {
+		reading.ID = len(r.DataMockReadings) + 1
+		r.DataMockReadings = append(r.DataMockReadings, reading)
+		ids = append(ids, reading.ID)
+	}
+	return ids, nil
+}
+
+func (r *MockRepository) Exists(ctx context.Context, sectionID int) bool {
+	return !r.MissingSection
+}
+
+func (r *MockRepository) GetBuckets(ctx context.Context, sectionID int, from time.Time, to time.Time, bucket time.Duration) ([]domain.TemperatureBucket, error) {
+	r.LastFrom, r.LastTo, r.LastBucket = from, to, bucket
+	if r.Error != nil {
+		return nil, r.Error
+	}
+	return r.DataMockBuckets, nil
+}
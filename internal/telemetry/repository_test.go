@@ -0,0 +1,108 @@
+package telemetry
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+var readings_test = []domain.SectionTemperature{
+	{SectionID: 1, RecordedAt: time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC), Temperature: -18.5},
+	{SectionID: 2, RecordedAt: time.Date(2026, 5, 1, 13, 0, 0, 0, time.FixedZone("ART", -3*60*60)), Temperature: 4},
+	{SectionID: 1, RecordedAt: time.Date(2026, 5, 1, 10, 5, 0, 0, time.UTC), Temperature: -18},
+}
+
+func TestSaveReadings_Ok(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare(regexp.QuoteMeta(SaveReading))
+	mock.ExpectExec(regexp.QuoteMeta(SaveReading)).WithArgs(1, "2026-05-01 10:00:00", float32(-18.5)).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(SaveReading)).WithArgs(2, "2026-05-01 16:00:00", float32(4)).WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectExec(regexp.QuoteMeta(SaveReading)).WithArgs(1, "2026-05-01 10:05:00", float32(-18)).WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectExec(regexp.QuoteMeta(UpdateCurrentTemperature)).WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(UpdateCurrentTemperature)).WithArgs(2, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// ACT
+	repo := NewRepository(db)
+	ids, err := repo.SaveReadings(context.TODO(), readings_test)
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveReadings_SectionNotFound(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare(regexp.QuoteMeta(SaveReading))
+	mock.ExpectExec(regexp.QuoteMeta(SaveReading)).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(SaveReading)).WillReturnError(&mysql.MySQLError{Number: uint16(ErrForeignNotFoundCode)})
+	mock.ExpectRollback()
+
+	// ACT
+	repo := NewRepository(db)
+	ids, err := repo.SaveReadings(context.TODO(), readings_test)
+
+	// ASSERT
+	assert.Nil(t, ids)
+	assert.EqualError(t, err, ErrSectionNotFound.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBuckets_Ok(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	from := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"bucket_from", "min", "avg", "max", "count"}).
+		AddRow("2026-05-01T10:00:00Z", -18.5, -18.25, -18, 2)
+	mock.ExpectQuery(regexp.QuoteMeta(GetBuckets)).WithArgs(900, 900, 1, "2026-05-01 00:00:00", "2026-05-02 00:00:00").WillReturnRows(rows)
+
+	expected := []domain.TemperatureBucket{{From: "2026-05-01T10:00:00Z", Minimum: -18.5, Average: -18.25, Maximum: -18, Readings: 2}}
+
+	// ACT
+	repo := NewRepository(db)
+	buckets, err := repo.GetBuckets(context.TODO(), 1, from, to, 15*time.Minute)
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, expected, buckets)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBuckets_Empty(t *testing.T) {
+	// ARRANGE
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(GetBuckets)).WillReturnRows(sqlmock.NewRows([]string{"bucket_from", "min", "avg", "max", "count"}))
+
+	// ACT
+	repo := NewRepository(db)
+	buckets, err := repo.GetBuckets(context.TODO(), 1, time.Now().Add(-time.Hour), time.Now(), time.Hour)
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.TemperatureBucket{}, buckets)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
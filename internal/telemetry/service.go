@@ -0,0 +1,127 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+)
+
+const (
+	// MaxReadings is the maximum amount of readings accepted in a single request
+	MaxReadings = 1000
+	// DefaultBucket is used when no bucket is requested
+	DefaultBucket = time.Hour
+	// DefaultPeriod is how far back the history goes when no from is requested
+	DefaultPeriod = 24 * time.Hour
+	// AbsoluteZero is the lowest temperature a sensor can report
+	AbsoluteZero = -273.15
+)
+
+var (
+	// ErrNoReadings is returned when the request does not have any reading
+	ErrNoReadings = errors.New("at least one reading is required")
+	// ErrTooManyReadings is returned when the request has more readings than the ones accepted at once
+	ErrTooManyReadings = fmt.Errorf("no more than %d readings can be sent at once", MaxReadings)
+	// ErrInvalidReading is returned when a reading does not have a section, a timestamp or a possible temperature
+	ErrInvalidReading = errors.New("every reading needs a section_id, a timestamp and a temperature above -273.15")
+	// ErrInvalidPeriod is returned when from or to are not dates, or from is not before to
+	ErrInvalidPeriod = errors.New("from and to must be yyyy-mm-dd dates or RFC3339 timestamps, and from must be before to")
+	// ErrInvalidBucket is returned when the bucket is not a duration of at least one second
+	ErrInvalidBucket = errors.New("bucket must be a duration of at least one second, like 15m or 1h")
+)
+
+type Service interface {
+	// Ingest stores the readings of the sensors and refreshes the current temperature of their sections
+	Ingest(ctx context.Context, readings []domain.SectionTemperature) ([]domain.SectionTemperature, error)
+	// GetHistory returns the minimum, average and maximum temperature of a section per bucket of time
+	GetHistory(ctx context.Context, sectionID int, from string, to string, bucket string) ([]domain.TemperatureBucket, error)
+}
+
+type service struct {
+	repository Repository
+	now        func() time.Time
+}
+
+func NewService(r Repository) Service {
+	return &service{
+		repository: r,
+		now:        time.Now,
+	}
+}
+
+// Ingest returns the stored readings with their ids, all of them are stored or none is
+func (s *service) Ingest(ctx context.Context, readings []domain.SectionTemperature) ([]domain.SectionTemperature, error) {
+	if len(readings) == 0 {
+		logging.Log(ErrNoReadings)
+		return nil, ErrNoReadings
+	}
+	if len(readings) > MaxReadings {
+		logging.Log(ErrTooManyReadings)
+		return nil, ErrTooManyReadings
+	}
+	for _, reading := range readings {
+		if reading.SectionID <= 0 || reading.RecordedAt.IsZero() || reading.Temperature < AbsoluteZero {
+			logging.Log(ErrInvalidReading)
+			return nil, ErrInvalidReading
+		}
+	}
+	ids, err := s.repository.SaveReadings(ctx, readings)
+	if err != nil {
+		logging.Log(err)
+		return nil, err
+	}
+	for i := range readings {
+		readings[i].ID = ids[i]
+	}
+	return readings, nil
+}
+
+// GetHistory uses the last day and buckets of one hour when from, to or bucket are empty
+// if the section doesn`t exist, an error is returned
+func (s *service) GetHistory(ctx context.Context, sectionID int, from string, to string, bucket string) ([]domain.TemperatureBucket, error) {
+	end := s.now()
+	if to != "" {
+		var err error
+		if end, err = parseInstant(to); err != nil {
+			logging.Log(err)
+			return nil, ErrInvalidPeriod
+		}
+	}
+	start := end.Add(-DefaultPeriod)
+	if from != "" {
+		var err error
+		if start, err = parseInstant(from); err != nil {
+			logging.Log(err)
+			return nil, ErrInvalidPeriod
+		}
+	}
+	if !start.Before(end) {
+		logging.Log(ErrInvalidPeriod)
+		return nil, ErrInvalidPeriod
+	}
+	size := DefaultBucket
+	if bucket != "" {
+		var err error
+		if size, err = time.ParseDuration(bucket); err != nil || size < time.Second {
+			logging.Log(ErrInvalidBucket)
+			return nil, ErrInvalidBucket
+		}
+	}
+	if !s.repository.Exists(ctx, sectionID) {
+		logging.Log(ErrSectionNotFound)
+		return nil, ErrSectionNotFound
+	}
+	return s.repository.GetBuckets(ctx, sectionID, start, end, size)
+}
+
+// parseInstant accepts a RFC3339 timestamp or a yyyy-mm-dd date, which is taken as midnight UTC
+func parseInstant(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(domain.ISO8601, value)
+}
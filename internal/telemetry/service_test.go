@@ -0,0 +1,107 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIngestOk(t *testing.T) {
+	// ARRANGE
+	repository := MockRepository{}
+	service := NewService(&repository)
+	readings := []domain.SectionTemperature{
+		{SectionID: 1, RecordedAt: time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC), Temperature: -18.5},
+		{SectionID: 2, RecordedAt: time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC), Temperature: 4},
+	}
+
+	// ACT
+	result, err := service.Ingest(context.TODO(), readings)
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result[0].ID)
+	assert.Equal(t, 2, result[1].ID)
+	assert.Len(t, repository.DataMockReadings, 2)
+}
+
+func TestIngestInvalidReading(t *testing.T) {
+	// ARRANGE
+	repository := MockRepository{}
+	service := NewService(&repository)
+
+	// ACT
+	_, errNoSection := service.Ingest(context.TODO(), []domain.SectionTemperature{{RecordedAt: time.Now(), Temperature: 1}})
+	_, errNoTimestamp := service.Ingest(context.TODO(), []domain.SectionTemperature{{SectionID: 1, Temperature: 1}})
+	_, errTemperature := service.Ingest(context.TODO(), []domain.SectionTemperature{{SectionID: 1, RecordedAt: time.Now(), Temperature: -300}})
+	_, errEmpty := service.Ingest(context.TODO(), nil)
+
+	// ASSERT
+	assert.EqualError(t, errNoSection, ErrInvalidReading.Error())
+	assert.EqualError(t, errNoTimestamp, ErrInvalidReading.Error())
+	assert.EqualError(t, errTemperature, ErrInvalidReading.Error())
+	assert.EqualError(t, errEmpty, ErrNoReadings.Error())
+	assert.Empty(t, repository.DataMockReadings)
+}
+
+func TestGetHistoryDefaults(t *testing.T) {
+	// ARRANGE
+	now := time.Date(2026, 5, 2, 12, 0, 0, 0, time.UTC)
+	repository := MockRepository{DataMockBuckets: []domain.TemperatureBucket{{From: "2026-05-02T11:00:00Z", Readings: 1}}}
+	service := &service{repository: &repository, now: func() time.Time { return now }}
+
+	// ACT
+	result, err := service.GetHistory(context.TODO(), 1, "", "", "")
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, now.Add(-DefaultPeriod), repository.LastFrom)
+	assert.Equal(t, now, repository.LastTo)
+	assert.Equal(t, DefaultBucket, repository.LastBucket)
+}
+
+func TestGetHistoryPeriod(t *testing.T) {
+	// ARRANGE
+	repository := MockRepository{}
+	service := NewService(&repository)
+
+	// ACT
+	_, err := service.GetHistory(context.TODO(), 1, "2026-05-01", "2026-05-01T12:00:00Z", "15m")
+
+	// ASSERT
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC), repository.LastFrom)
+	assert.Equal(t, time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC), repository.LastTo)
+	assert.Equal(t, 15*time.Minute, repository.LastBucket)
+}
+
+func TestGetHistoryInvalid(t *testing.T) {
+	// ARRANGE
+	service := NewService(&MockRepository{})
+
+	// ACT
+	_, errFrom := service.GetHistory(context.TODO(), 1, "yesterday", "", "")
+	_, errOrder := service.GetHistory(context.TODO(), 1, "2026-05-02", "2026-05-01", "")
+	_, errBucket := service.GetHistory(context.TODO(), 1, "", "", "1ms")
+
+	// ASSERT
+	assert.EqualError(t, errFrom, ErrInvalidPeriod.Error())
+	assert.EqualError(t, errOrder, ErrInvalidPeriod.Error())
+	assert.EqualError(t, errBucket, ErrInvalidBucket.Error())
+}
+
+func TestGetHistorySectionNotFound(t *testing.T) {
+	// ARRANGE
+	service := NewService(&MockRepository{MissingSection: true})
+
+	// ACT
+	result, err := service.GetHistory(context.TODO(), 1, "", "", "")
+
+	// ASSERT
+	assert.Nil(t, result)
+	assert.EqualError(t, err, ErrSectionNotFound.Error())
+}
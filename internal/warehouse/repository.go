@@ -7,27 +7,46 @@ import (
 
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+	"github.com/go-sql-driver/mysql"
 )
 
 // Errors
 var (
-	ErrNotFound       = errors.New("warehouse not found")
-	ErrAlreadyExists  = errors.New("warehouse code already exists")
-	ErrInternal       = errors.New("database internal error")
-	ErrBadRequest     = errors.New("bad request")
-	ErrBodyValidation = errors.New("invalid request body")
+	ErrNotFound         = errors.New("warehouse not found")
+	ErrAlreadyExists    = errors.New("warehouse code already exists")
+	ErrInternal         = errors.New("database internal error")
+	ErrBadRequest       = errors.New("bad request")
+	ErrBodyValidation   = errors.New("invalid request body")
+	ErrInvalidPolicy    = errors.New("the temperature policy must be reject or warn")
+	ErrInvalidDays      = errors.New("days must be a number greater than or equal to zero")
+	ErrLocalityNotFound = errors.New("locality not found")
+	ErrInvalidStrategy  = errors.New("strategy must be block or reassign, reassign needs the id of another warehouse in to")
+	ErrHasDependents    = errors.New("warehouse has sections, employees or inbound orders, delete it with the reassign strategy")
+	ErrTargetNotFound   = errors.New("the warehouse to reassign to does not exist")
 )
 
 // Queries
 const (
-	GET_ALL_WAREHOUSES = "SELECT * FROM warehouses"
-	GET_WAREHOUSE      = "SELECT * FROM warehouses WHERE id=?;"
+	GET_ALL_WAREHOUSES = "SELECT id, address, telephone, warehouse_code, minimum_capacity, minimum_temperature, temperature_policy, COALESCE(locality_id, '') FROM warehouses"
+	GET_WAREHOUSE      = "SELECT id, address, telephone, warehouse_code, minimum_capacity, minimum_temperature, temperature_policy, COALESCE(locality_id, '') FROM warehouses WHERE id=?;"
 	EXISTS             = "SELECT warehouse_code FROM warehouses WHERE warehouse_code=?;"
-	SAVE_WAREHOUSE     = "INSERT INTO warehouses (address, telephone, warehouse_code, minimum_capacity, minimum_temperature) VALUES (?, ?, ?, ?, ?)"
-	UPDATE_WAREHOUSE   = "UPDATE warehouses SET address=?, telephone=?, warehouse_code=?, minimum_capacity=?, minimum_temperature=? WHERE id=?"
+	SAVE_WAREHOUSE     = "INSERT INTO warehouses (address, telephone, warehouse_code, minimum_capacity, minimum_temperature, temperature_policy, locality_id) VALUES (?, ?, ?, ?, ?, ?, NULLIF(?, ''))"
+	UPDATE_WAREHOUSE   = "UPDATE warehouses SET address=?, telephone=?, warehouse_code=?, minimum_capacity=?, minimum_temperature=?, temperature_policy=?, locality_id=NULLIF(?, '') WHERE id=?"
 	DELETE_WAREHOUSE   = "DELETE FROM warehouses WHERE id=?"
+	LOCK_WAREHOUSE     = "SELECT id FROM warehouses WHERE id=? FOR UPDATE;"
+	GET_EXPIRING_STOCK = `SELECT s.id, s.section_number, p.id, p.product_code, p.description, pb.id, pb.batch_number, DATE_FORMAT(pb.due_date, '%Y-%m-%d'), DATEDIFF(pb.due_date, CURDATE()), pb.current_quantity, pb.quarantined
+		FROM product_batches AS pb
+		INNER JOIN sections AS s ON s.id = pb.section_id
+		INNER JOIN products AS p ON p.id = pb.product_id
+		WHERE s.warehouse_id = ? AND pb.current_quantity > 0 AND pb.due_date >= CURDATE() AND pb.due_date <= DATE_ADD(CURDATE(), INTERVAL ? DAY)
+		ORDER BY s.section_number, s.id, p.id, pb.due_date, pb.id;`
+	MySqlNumberForeignKeyConstraint = 1452
+	MySqlNumberRowIsReferenced      = 1451
 )
 
+// dependentTables are the tables whose warehouse_id references a warehouse, a delete reassigns them in this order
+var dependentTables = []string{"sections", "employees", "inbound_orders"}
+
 // Repository encapsulates the storage of a warehouse.
 type Repository interface {
 	GetAll(ctx context.Context) ([]domain.Warehouse, error)
@@ -35,7 +54,12 @@ type Repository interface {
 	Exists(ctx context.Context, warehouseCode string) bool
 	Save(ctx context.Context, w domain.Warehouse) (int, error)
 	Update(ctx context.Context, w domain.Warehouse) error
-	Delete(ctx context.Context, id int) error
+	// Delete removes the warehouse, with the reassign strategy its sections, employees and inbound orders
+	// move to the warehouse options.To in the same transaction
+	Delete(ctx context.Context, id int, options domain.DeleteOptions) error
+	// GetDependents returns the ids of the rows that reference the warehouse by table
+	GetDependents(ctx context.Context, id int) (map[string][]int, error)
+	GetExpiringStock(ctx context.Context, id int, days int) ([]domain.ExpiringStock, error)
 }
 
 type repository struct {
@@ -59,7 +83,7 @@ func (r *repository) GetAll(ctx context.Context) ([]domain.Warehouse, error) {
 
 	for rows.Next() {
 		w := domain.Warehouse{}
-		_ = rows.Scan(&w.ID, &w.Address, &w.Telephone, &w.WarehouseCode, &w.MinimumCapacity, &w.MinimumTemperature)
+		_ = rows.Scan(&w.ID, &w.Address, &w.Telephone, &w.WarehouseCode, &w.MinimumCapacity, &w.MinimumTemperature, &w.TemperaturePolicy, &w.LocalityID)
 		warehouses = append(warehouses, w)
 	}
 
@@ -69,7 +93,7 @@ func (r *repository) GetAll(ctx context.Context) ([]domain.Warehouse, error) {
 func (r *repository) Get(ctx context.Context, id int) (domain.Warehouse, error) {
 	row := r.db.QueryRow(GET_WAREHOUSE, id)
 	w := domain.Warehouse{}
-	err := row.Scan(&w.ID, &w.Address, &w.Telephone, &w.WarehouseCode, &w.MinimumCapacity, &w.MinimumTemperature)
+	err := row.Scan(&w.ID, &w.Address, &w.Telephone, &w.WarehouseCode, &w.MinimumCapacity, &w.MinimumTemperature, &w.TemperaturePolicy, &w.LocalityID)
 	if err != nil {
 		switch err {
 		case sql.ErrNoRows:
@@ -97,10 +121,10 @@ func (r *repository) Save(ctx context.Context, w domain.Warehouse) (int, error)
 		return 0, err
 	}
 
-	res, err := stmt.Exec(&w.Address, &w.Telephone, &w.WarehouseCode, &w.MinimumCapacity, &w.MinimumTemperature)
+	res, err := stmt.Exec(&w.Address, &w.Telephone, &w.WarehouseCode, &w.MinimumCapacity, &w.MinimumTemperature, &w.TemperaturePolicy, &w.LocalityID)
 	if err != nil {
 		logging.Log(err)
-		return 0, err
+		return 0, parseLocalityError(err)
 	}
 
 	id, err := res.LastInsertId()
@@ -119,10 +143,10 @@ func (r *repository) Update(ctx context.Context, w domain.Warehouse) error {
 		return err
 	}
 
-	res, err := stmt.Exec(&w.Address, &w.Telephone, &w.WarehouseCode, &w.MinimumCapacity, &w.MinimumTemperature, &w.ID)
+	res, err := stmt.Exec(&w.Address, &w.Telephone, &w.WarehouseCode, &w.MinimumCapacity, &w.MinimumTemperature, &w.TemperaturePolicy, &w.LocalityID, &w.ID)
 	if err != nil {
 		logging.Log(err)
-		return err
+		return parseLocalityError(err)
 	}
 
 	_, err = res.RowsAffected()
@@ -134,29 +158,133 @@ func (r *repository) Update(ctx context.Context, w domain.Warehouse) error {
 	return nil
 }
 
-func (r *repository) Delete(ctx context.Context, id int) error {
-	stmt, err := r.db.Prepare(DELETE_WAREHOUSE)
+func (r *repository) Delete(ctx context.Context, id int, options domain.DeleteOptions) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		logging.Log(err)
+		return ErrInternal
+	}
+
+	if err := deleteWarehouse(ctx, tx, id, options); err != nil {
+		_ = tx.Rollback()
 		return err
 	}
 
-	res, err := stmt.Exec(id)
-	if err != nil {
+	if err := tx.Commit(); err != nil {
 		logging.Log(err)
+		return ErrInternal
+	}
+	return nil
+}
+
+// deleteWarehouse locks the warehouse, reassigns its dependents when the strategy says so and deletes it inside the transaction
+func deleteWarehouse(ctx context.Context, tx *sql.Tx, id int, options domain.DeleteOptions) error {
+	if err := lockWarehouse(ctx, tx, id, ErrNotFound); err != nil {
 		return err
 	}
 
-	affect, err := res.RowsAffected()
+	if options.Strategy == domain.DeleteReassign {
+		if err := lockWarehouse(ctx, tx, options.To, ErrTargetNotFound); err != nil {
+			return err
+		}
+		for _, table := range dependentTables {
+			if _, err := tx.ExecContext(ctx, "UPDATE "+table+" SET warehouse_id=? WHERE warehouse_id=?;", options.To, id); err != nil {
+				logging.Log(err)
+				return ErrInternal
+			}
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, DELETE_WAREHOUSE, id); err != nil {
+		if mysqlError, ok := err.(*mysql.MySQLError); ok && mysqlError.Number == MySqlNumberRowIsReferenced {
+			logging.Log(ErrHasDependents)
+			return ErrHasDependents
+		}
+		logging.Log(err)
+		return ErrInternal
+	}
+	return nil
+}
+
+// lockWarehouse locks the row of the warehouse until the end of the transaction, or returns notFound
+func lockWarehouse(ctx context.Context, tx *sql.Tx, id int, notFound error) error {
+	if err := tx.QueryRowContext(ctx, LOCK_WAREHOUSE, id).Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			logging.Log(notFound)
+			return notFound
+		}
+		logging.Log(err)
+		return ErrInternal
+	}
+	return nil
+}
+
+func (r *repository) GetDependents(ctx context.Context, id int) (map[string][]int, error) {
+	dependents := make(map[string][]int, len(dependentTables))
+	for _, table := range dependentTables {
+		ids, err := r.getIDs(ctx, "SELECT id FROM "+table+" WHERE warehouse_id=? ORDER BY id;", id)
+		if err != nil {
+			return nil, err
+		}
+		dependents[table] = ids
+	}
+	return dependents, nil
+}
+
+func (r *repository) getIDs(ctx context.Context, query string, args ...interface{}) ([]int, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		logging.Log(err)
-		return err
+		return nil, ErrInternal
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			logging.Log(err)
+			return nil, ErrInternal
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
 	}
+	return ids, nil
+}
 
-	if affect < 1 {
-		logging.Log(ErrNotFound)
-		return ErrNotFound
+// GetExpiringStock returns the batches with stock stored in the warehouse that expire between today and the given days from now,
+// ordered by section, product and due date
+func (r *repository) GetExpiringStock(ctx context.Context, id int, days int) ([]domain.ExpiringStock, error) {
+	rows, err := r.db.QueryContext(ctx, GET_EXPIRING_STOCK, id, days)
+	if err != nil {
+		logging.Log(err)
+		return nil, ErrInternal
 	}
+	defer rows.Close()
 
-	return nil
+	var stock []domain.ExpiringStock
+	for rows.Next() {
+		es := domain.ExpiringStock{}
+		err := rows.Scan(&es.SectionID, &es.SectionNumber, &es.ProductID, &es.ProductCode, &es.Description,
+			&es.Batch.ProductBatchID, &es.Batch.BatchNumber, &es.Batch.DueDate, &es.Batch.DaysRemaining, &es.Batch.CurrentQuantity, &es.Batch.Quarantined)
+		if err != nil {
+			logging.Log(err)
+			return nil, ErrInternal
+		}
+		stock = append(stock, es)
+	}
+
+	return stock, nil
+}
+
+// parseLocalityError returns ErrLocalityNotFound when the locality of the warehouse does not exist,
+// any other error is returned as it is
+func parseLocalityError(err error) error {
+	if mysqlError, ok := err.(*mysql.MySQLError); ok && mysqlError.Number == MySqlNumberForeignKeyConstraint {
+		return ErrLocalityNotFound
+	}
+	return err
 }
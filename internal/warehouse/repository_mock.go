@@ -9,9 +9,12 @@ import (
 type MockRepo struct {
 	mockWarehouse     domain.Warehouse
 	mockWarehouses    []domain.Warehouse
+	mockExpiringStock []domain.ExpiringStock
 	mockErrorInternal error
 	mockErrorExists   error
 	mockErrorUpdate   error
+	mockDependents    map[string][]int
+	deleteOptions     domain.DeleteOptions
 }
 
 func (r *MockRepo) GetAll(ctx context.Context) ([]domain.Warehouse, error) {
@@ -46,9 +49,24 @@ func (r *MockRepo) Update(ctx context.Context, w domain.Warehouse) error {
 	return nil
 }
 
-func (r *MockRepo) Delete(ctx context.Context, id int) error {
+func (r *MockRepo) Delete(ctx context.Context, id int, options domain.DeleteOptions) error {
+	r.deleteOptions = options
 	if r.mockErrorInternal != nil {
 		return r.mockErrorInternal
 	}
 	return nil
 }
+
+func (r *MockRepo) GetDependents(ctx context.Context, id int) (map[string][]int, error) {
+	if r.mockErrorInternal != nil {
+		return nil, r.mockErrorInternal
+	}
+	return r.mockDependents, nil
+}
+
+func (r *MockRepo) GetExpiringStock(ctx context.Context, id int, days int) ([]domain.ExpiringStock, error) {
+	if r.mockErrorInternal != nil {
+		return nil, r.mockErrorInternal
+	}
+	return r.mockExpiringStock, nil
+}
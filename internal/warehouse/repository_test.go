@@ -2,12 +2,14 @@ package warehouse
 
 import (
 	"context"
+	"database/sql"
 	"regexp"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w6-2/pkg/logging"
+	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -26,15 +28,16 @@ func TestRepositoryGetAll(t *testing.T) {
 		WarehouseCode:      "W001",
 		MinimumCapacity:    100,
 		MinimumTemperature: 0,
+		TemperaturePolicy:  domain.TemperaturePolicyReject,
 	}
 	warehouses := []domain.Warehouse{warehouse}
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
-	columns := []string{"id", "address", "telephone", "warehouse_code", "minimum_capacity", "minimum_temperature"}
+	columns := []string{"id", "address", "telephone", "warehouse_code", "minimum_capacity", "minimum_temperature", "temperature_policy", "locality_id"}
 	rows := sqlmock.NewRows(columns)
-	rows.AddRow(warehouse.ID, warehouse.Address, warehouse.Telephone, warehouse.WarehouseCode, warehouse.MinimumCapacity, warehouse.MinimumTemperature)
+	rows.AddRow(warehouse.ID, warehouse.Address, warehouse.Telephone, warehouse.WarehouseCode, warehouse.MinimumCapacity, warehouse.MinimumTemperature, warehouse.TemperaturePolicy, warehouse.LocalityID)
 
 	mock.ExpectQuery(regexp.QuoteMeta(GET_ALL_WAREHOUSES)).WillReturnRows(rows)
 
@@ -80,14 +83,15 @@ func TestRepositoryGet(t *testing.T) {
 		WarehouseCode:      "W001",
 		MinimumCapacity:    100,
 		MinimumTemperature: 0,
+		TemperaturePolicy:  domain.TemperaturePolicyReject,
 	}
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
-	columns := []string{"id", "address", "telephone", "warehouse_code", "minimum_capacity", "minimum_temperature"}
+	columns := []string{"id", "address", "telephone", "warehouse_code", "minimum_capacity", "minimum_temperature", "temperature_policy", "locality_id"}
 	rows := sqlmock.NewRows(columns)
-	rows.AddRow(warehouse.ID, warehouse.Address, warehouse.Telephone, warehouse.WarehouseCode, warehouse.MinimumCapacity, warehouse.MinimumTemperature)
+	rows.AddRow(warehouse.ID, warehouse.Address, warehouse.Telephone, warehouse.WarehouseCode, warehouse.MinimumCapacity, warehouse.MinimumTemperature, warehouse.TemperaturePolicy, warehouse.LocalityID)
 
 	mock.ExpectQuery(regexp.QuoteMeta(GET_WAREHOUSE)).WillReturnRows(rows)
 
@@ -111,7 +115,7 @@ func TestRepositoryGetFailScanNoRows(t *testing.T) {
 	warehouseID := 1
 	expectedError := ErrNotFound
 
-	columns := []string{"id", "address", "telephone", "warehouse_code", "minimum_capacity", "minimum_temperature"}
+	columns := []string{"id", "address", "telephone", "warehouse_code", "minimum_capacity", "minimum_temperature", "temperature_policy", "locality_id"}
 	rows := sqlmock.NewRows(columns)
 	mock.ExpectQuery(regexp.QuoteMeta(GET_WAREHOUSE)).WillReturnRows(rows)
 
@@ -135,9 +139,9 @@ func TestRepositoryGetFailScanInternal(t *testing.T) {
 	warehouseID := 1
 	expectedError := ErrInternal
 
-	columns := []string{"id", "address", "telephone", "warehouse_code", "minimum_capacity", "minimum_temperature"}
+	columns := []string{"id", "address", "telephone", "warehouse_code", "minimum_capacity", "minimum_temperature", "temperature_policy", "locality_id"}
 	rows := sqlmock.NewRows(columns)
-	rows.AddRow(nil, nil, nil, nil, nil, nil) // this can't be parsed by Scan function
+	rows.AddRow(nil, nil, nil, nil, nil, nil, nil, nil) // this can't be parsed by Scan function
 	mock.ExpectQuery(regexp.QuoteMeta(GET_WAREHOUSE)).WillReturnRows(rows)
 
 	// Act
@@ -161,7 +165,7 @@ func TestRepositoryExistsFail(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	columns := []string{"id", "address", "telephone", "warehouse_code", "minimum_capacity", "minimum_temperature"}
+	columns := []string{"id", "address", "telephone", "warehouse_code", "minimum_capacity", "minimum_temperature", "temperature_policy", "locality_id"}
 	rows := sqlmock.NewRows(columns)
 
 	mock.ExpectQuery(regexp.QuoteMeta(EXISTS)).WillReturnRows(rows)
@@ -187,14 +191,15 @@ func TestRepositorySave(t *testing.T) {
 		WarehouseCode:      "W001",
 		MinimumCapacity:    100,
 		MinimumTemperature: 0,
+		TemperaturePolicy:  domain.TemperaturePolicyReject,
 	}
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
-	columns := []string{"id", "address", "telephone", "warehouse_code", "minimum_capacity", "minimum_temperature"}
+	columns := []string{"id", "address", "telephone", "warehouse_code", "minimum_capacity", "minimum_temperature", "temperature_policy", "locality_id"}
 	rows := sqlmock.NewRows(columns)
-	rows.AddRow(warehouse.ID, warehouse.Address, warehouse.Telephone, warehouse.WarehouseCode, warehouse.MinimumCapacity, warehouse.MinimumTemperature)
+	rows.AddRow(warehouse.ID, warehouse.Address, warehouse.Telephone, warehouse.WarehouseCode, warehouse.MinimumCapacity, warehouse.MinimumTemperature, warehouse.TemperaturePolicy, warehouse.LocalityID)
 
 	mock.ExpectPrepare(regexp.QuoteMeta(SAVE_WAREHOUSE))
 	mock.ExpectExec(regexp.QuoteMeta(SAVE_WAREHOUSE)).WillReturnResult(sqlmock.NewResult(1, 1))
@@ -219,6 +224,7 @@ func TestRepositorySaveFailPrepare(t *testing.T) {
 		WarehouseCode:      "W001",
 		MinimumCapacity:    100,
 		MinimumTemperature: 0,
+		TemperaturePolicy:  domain.TemperaturePolicyReject,
 	}
 	expectedError := ErrInternal
 
@@ -249,6 +255,7 @@ func TestRepositorySaveFailExec(t *testing.T) {
 		WarehouseCode:      "W001",
 		MinimumCapacity:    100,
 		MinimumTemperature: 0,
+		TemperaturePolicy:  domain.TemperaturePolicyReject,
 	}
 	expectedError := ErrInternal
 
@@ -270,6 +277,29 @@ func TestRepositorySaveFailExec(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+// TestRepositorySaveFailLocalityNotFound is correct when the locality of the warehouse does not exist
+func TestRepositorySaveFailLocalityNotFound(t *testing.T) {
+	// Arrange
+	warehouse := domain.Warehouse{Address: "avenida siempre viva", WarehouseCode: "W001", TemperaturePolicy: domain.TemperaturePolicyReject, LocalityID: "9999"}
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(SAVE_WAREHOUSE))
+	mock.ExpectExec(regexp.QuoteMeta(SAVE_WAREHOUSE)).
+		WithArgs(warehouse.Address, warehouse.Telephone, warehouse.WarehouseCode, warehouse.MinimumCapacity, warehouse.MinimumTemperature, warehouse.TemperaturePolicy, "9999").
+		WillReturnError(&mysql.MySQLError{Number: MySqlNumberForeignKeyConstraint})
+
+	// Act
+	newID, err := NewRepository(db).Save(context.TODO(), warehouse)
+
+	// Assert
+	assert.EqualError(t, err, ErrLocalityNotFound.Error())
+	assert.Empty(t, newID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 // TestRepositorySaveFailLastID is correct when function LastInsertID returns an error
 func TestRepositorySaveFailLastID(t *testing.T) {
 	// Arrange
@@ -280,15 +310,16 @@ func TestRepositorySaveFailLastID(t *testing.T) {
 		WarehouseCode:      "W001",
 		MinimumCapacity:    100,
 		MinimumTemperature: 0,
+		TemperaturePolicy:  domain.TemperaturePolicyReject,
 	}
 	expectedError := ErrInternal
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
-	columns := []string{"id", "address", "telephone", "warehouse_code", "minimum_capacity", "minimum_temperature"}
+	columns := []string{"id", "address", "telephone", "warehouse_code", "minimum_capacity", "minimum_temperature", "temperature_policy", "locality_id"}
 	rows := sqlmock.NewRows(columns)
-	rows.AddRow(warehouse.ID, warehouse.Address, warehouse.Telephone, warehouse.WarehouseCode, warehouse.MinimumCapacity, warehouse.MinimumTemperature)
+	rows.AddRow(warehouse.ID, warehouse.Address, warehouse.Telephone, warehouse.WarehouseCode, warehouse.MinimumCapacity, warehouse.MinimumTemperature, warehouse.TemperaturePolicy, warehouse.LocalityID)
 
 	mock.ExpectPrepare(regexp.QuoteMeta(SAVE_WAREHOUSE))
 	mock.ExpectExec(regexp.QuoteMeta(SAVE_WAREHOUSE)).WillReturnResult(sqlmock.NewErrorResult(ErrInternal))
@@ -315,14 +346,15 @@ func TestRepositoryUpdate(t *testing.T) {
 		WarehouseCode:      "W001",
 		MinimumCapacity:    100,
 		MinimumTemperature: 0,
+		TemperaturePolicy:  domain.TemperaturePolicyReject,
 	}
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
-	columns := []string{"id", "address", "telephone", "warehouse_code", "minimum_capacity", "minimum_temperature"}
+	columns := []string{"id", "address", "telephone", "warehouse_code", "minimum_capacity", "minimum_temperature", "temperature_policy", "locality_id"}
 	rows := sqlmock.NewRows(columns)
-	rows.AddRow(warehouse.ID, warehouse.Address, warehouse.Telephone, warehouse.WarehouseCode, warehouse.MinimumCapacity, warehouse.MinimumTemperature)
+	rows.AddRow(warehouse.ID, warehouse.Address, warehouse.Telephone, warehouse.WarehouseCode, warehouse.MinimumCapacity, warehouse.MinimumTemperature, warehouse.TemperaturePolicy, warehouse.LocalityID)
 
 	mock.ExpectPrepare(regexp.QuoteMeta(UPDATE_WAREHOUSE))
 	mock.ExpectExec(regexp.QuoteMeta(UPDATE_WAREHOUSE)).WillReturnResult(sqlmock.NewResult(1, 1))
@@ -346,6 +378,7 @@ func TestRepositoryUpdateFailPrepare(t *testing.T) {
 		WarehouseCode:      "W001",
 		MinimumCapacity:    100,
 		MinimumTemperature: 0,
+		TemperaturePolicy:  domain.TemperaturePolicyReject,
 	}
 	expectedError := ErrInternal
 
@@ -375,6 +408,7 @@ func TestRepositoryUpdateFailExec(t *testing.T) {
 		WarehouseCode:      "W001",
 		MinimumCapacity:    100,
 		MinimumTemperature: 0,
+		TemperaturePolicy:  domain.TemperaturePolicyReject,
 	}
 	expectedError := ErrInternal
 
@@ -405,15 +439,16 @@ func TestRepositoryUpdateFailRowsAffected(t *testing.T) {
 		WarehouseCode:      "W001",
 		MinimumCapacity:    100,
 		MinimumTemperature: 0,
+		TemperaturePolicy:  domain.TemperaturePolicyReject,
 	}
 	expectedError := ErrInternal
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
-	columns := []string{"id", "address", "telephone", "warehouse_code", "minimum_capacity", "minimum_temperature"}
+	columns := []string{"id", "address", "telephone", "warehouse_code", "minimum_capacity", "minimum_temperature", "temperature_policy", "locality_id"}
 	rows := sqlmock.NewRows(columns)
-	rows.AddRow(warehouse.ID, warehouse.Address, warehouse.Telephone, warehouse.WarehouseCode, warehouse.MinimumCapacity, warehouse.MinimumTemperature)
+	rows.AddRow(warehouse.ID, warehouse.Address, warehouse.Telephone, warehouse.WarehouseCode, warehouse.MinimumCapacity, warehouse.MinimumTemperature, warehouse.TemperaturePolicy, warehouse.LocalityID)
 
 	mock.ExpectPrepare(regexp.QuoteMeta(UPDATE_WAREHOUSE))
 	mock.ExpectExec(regexp.QuoteMeta(UPDATE_WAREHOUSE)).WillReturnResult(sqlmock.NewErrorResult(ErrInternal))
@@ -437,20 +472,22 @@ func TestRepositoryDelete(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectPrepare(regexp.QuoteMeta(DELETE_WAREHOUSE))
-	mock.ExpectExec(regexp.QuoteMeta(DELETE_WAREHOUSE)).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_WAREHOUSE)).WithArgs(warehouseID).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(warehouseID))
+	mock.ExpectExec(regexp.QuoteMeta(DELETE_WAREHOUSE)).WithArgs(warehouseID).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
 	// Act
 	repository := NewRepository(db)
-	err = repository.Delete(context.TODO(), warehouseID)
+	err = repository.Delete(context.TODO(), warehouseID, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 
 	// Assert
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-// TestRepositoryDeleteFailPrepare is correct when function Prepare returns an error
-func TestRepositoryDeleteFailPrepare(t *testing.T) {
+// TestRepositoryDeleteFailBegin is correct when the transaction can not start
+func TestRepositoryDeleteFailBegin(t *testing.T) {
 	// Arrange
 	warehouseID := 1
 	expectedError := ErrInternal
@@ -459,12 +496,12 @@ func TestRepositoryDeleteFailPrepare(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectPrepare(regexp.QuoteMeta(DELETE_WAREHOUSE)).WillReturnError(expectedError)
+	mock.ExpectBegin().WillReturnError(sql.ErrConnDone)
 
 	// Act
 	repository := NewRepository(db)
 
-	err = repository.Delete(context.TODO(), warehouseID)
+	err = repository.Delete(context.TODO(), warehouseID, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 
 	// Assert
 	assert.EqualError(t, err, expectedError.Error())
@@ -481,38 +518,39 @@ func TestRepositoryDeleteFailExec(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectPrepare(regexp.QuoteMeta(DELETE_WAREHOUSE))
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_WAREHOUSE)).WithArgs(warehouseID).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(warehouseID))
 	mock.ExpectExec(regexp.QuoteMeta(DELETE_WAREHOUSE)).WillReturnError(expectedError)
+	mock.ExpectRollback()
 
 	// Act
 	repository := NewRepository(db)
 
-	err = repository.Delete(context.TODO(), warehouseID)
+	err = repository.Delete(context.TODO(), warehouseID, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 
 	// Assert
 	assert.EqualError(t, err, expectedError.Error())
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-// TestRepositoryDeleteFailRowsAffected is correct when function RowsAffected returns an error
-func TestRepositoryDeleteFailRowsAffected(t *testing.T) {
+// TestRepositoryDeleteFailDependents is correct when the block strategy finds rows that reference the warehouse
+func TestRepositoryDeleteFailDependents(t *testing.T) {
 	// Arrange
 	warehouseID := 1
-	expectedError := ErrInternal
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectPrepare(regexp.QuoteMeta(DELETE_WAREHOUSE))
-	mock.ExpectExec(regexp.QuoteMeta(DELETE_WAREHOUSE)).WillReturnResult(sqlmock.NewErrorResult(ErrInternal))
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_WAREHOUSE)).WithArgs(warehouseID).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(warehouseID))
+	mock.ExpectExec(regexp.QuoteMeta(DELETE_WAREHOUSE)).WithArgs(warehouseID).WillReturnError(&mysql.MySQLError{Number: MySqlNumberRowIsReferenced})
+	mock.ExpectRollback()
 
 	// Act
-	repository := NewRepository(db)
-
-	err = repository.Delete(context.TODO(), warehouseID)
+	err = NewRepository(db).Delete(context.TODO(), warehouseID, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 
 	// Assert
-	assert.EqualError(t, err, expectedError.Error())
+	assert.EqualError(t, err, ErrHasDependents.Error())
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -525,14 +563,109 @@ func TestRepositoryDeleteFailNotFound(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectPrepare(regexp.QuoteMeta(DELETE_WAREHOUSE))
-	mock.ExpectExec(regexp.QuoteMeta(DELETE_WAREHOUSE)).WillReturnResult(sqlmock.NewResult(1, 0)) // forced 0 affected rows
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_WAREHOUSE)).WithArgs(warehouseID).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
 
 	// Act
 	repository := NewRepository(db)
-	err = repository.Delete(context.TODO(), warehouseID)
+	err = repository.Delete(context.TODO(), warehouseID, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 
 	// Assert
 	assert.EqualError(t, err, expectedError.Error())
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+// TestRepositoryDeleteReassign is correct when every dependent moves to the other warehouse before the delete
+func TestRepositoryDeleteReassign(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_WAREHOUSE)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_WAREHOUSE)).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	for _, table := range dependentTables {
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE "+table+" SET warehouse_id=? WHERE warehouse_id=?;")).WithArgs(2, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectExec(regexp.QuoteMeta(DELETE_WAREHOUSE)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	err = NewRepository(db).Delete(context.TODO(), 1, domain.DeleteOptions{Strategy: domain.DeleteReassign, To: 2})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestRepositoryDeleteFailTargetNotFound is correct when the warehouse to reassign to does not exist
+func TestRepositoryDeleteFailTargetNotFound(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_WAREHOUSE)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(LOCK_WAREHOUSE)).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	// Act
+	err = NewRepository(db).Delete(context.TODO(), 1, domain.DeleteOptions{Strategy: domain.DeleteReassign, To: 2})
+
+	// Assert
+	assert.EqualError(t, err, ErrTargetNotFound.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// * ---------------------- GetDependents --------------------------
+// TestRepositoryGetDependents checks the ids of every table that references the warehouse are returned
+func TestRepositoryGetDependents(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM sections WHERE warehouse_id=? ORDER BY id;")).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM employees WHERE warehouse_id=? ORDER BY id;")).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM inbound_orders WHERE warehouse_id=? ORDER BY id;")).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+
+	// Act
+	result, err := NewRepository(db).GetDependents(context.TODO(), 1)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]int{"sections": {2, 3}, "employees": {}, "inbound_orders": {9}}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// * ---------------------- GetExpiringStock --------------------------
+// TestRepositoryGetExpiringStock checks the correct operation of the GetExpiringStock repository method
+func TestRepositoryGetExpiringStock(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	columns := []string{"section_id", "section_number", "product_id", "product_code", "description", "product_batch_id", "batch_number", "due_date", "days_remaining", "current_quantity", "quarantined"}
+	rows := sqlmock.NewRows(columns)
+	rows.AddRow(1, 10, 2, "P2", "milk", 3, 30, "2026-05-03", 2, 15, false)
+	mock.ExpectQuery(regexp.QuoteMeta(GET_EXPIRING_STOCK)).WithArgs(1, 7).WillReturnRows(rows)
+
+	expected := []domain.ExpiringStock{{SectionID: 1, SectionNumber: 10, ProductID: 2, ProductCode: "P2", Description: "milk",
+		Batch: domain.ExpiringBatch{ProductBatchID: 3, BatchNumber: 30, DueDate: "2026-05-03", DaysRemaining: 2, CurrentQuantity: 15}}}
+
+	// Act
+	repository := NewRepository(db)
+	result, err := repository.GetExpiringStock(context.TODO(), 1, 7)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -11,11 +11,16 @@ import (
 type Service interface {
 	Get(ctx context.Context, id int) (domain.Warehouse, error)
 	GetAll(ctx context.Context) ([]domain.Warehouse, error)
-	Create(ctx context.Context, address string, telephone string, warehouseCode string, minimumCapacity int, minimumTemperature int) (domain.Warehouse, error)
-	Delete(ctx context.Context, id int) error
-	Update(ctx context.Context, id int, address *string, telephone *string, warehouseCode *string, minimumCapacity *int, minimumTemperature *int) (domain.Warehouse, error)
+	Create(ctx context.Context, address string, telephone string, warehouseCode string, minimumCapacity int, minimumTemperature int, temperaturePolicy string, localityID string) (domain.Warehouse, error)
+	Delete(ctx context.Context, id int, options domain.DeleteOptions) error
+	DeletePreview(ctx context.Context, id int) (domain.DeletePreview, error)
+	Update(ctx context.Context, id int, address *string, telephone *string, warehouseCode *string, minimumCapacity *int, minimumTemperature *int, temperaturePolicy *string, localityID *string) (domain.Warehouse, error)
+	ReportExpiring(ctx context.Context, id int, days int) ([]domain.ExpiringSection, error)
 }
 
+// DeleteStrategies are the strategies a warehouse can be deleted with, its dependents can not be left without warehouse
+var DeleteStrategies = []string{domain.DeleteBlock, domain.DeleteReassign}
+
 type service struct {
 	repository Repository
 }
@@ -50,8 +55,18 @@ func (s *service) GetAll(ctx context.Context) ([]domain.Warehouse, error) {
 
 // Create returns the created warehouse provided by the repository if succesful.
 // if the warehouseCode is not unique, a error is returned.
+// if the temperaturePolicy is empty the warehouse rejects incompatible temperatures, if it is unknown a error is returned.
+// if the localityID is not empty and the locality doesn't exist, an error is returned.
 // any other error encountered is also returned.
-func (s *service) Create(ctx context.Context, address string, telephone string, warehouseCode string, minimumCapacity int, minimumTemperature int) (domain.Warehouse, error) {
+func (s *service) Create(ctx context.Context, address string, telephone string, warehouseCode string, minimumCapacity int, minimumTemperature int, temperaturePolicy string, localityID string) (domain.Warehouse, error) {
+	if temperaturePolicy == "" {
+		temperaturePolicy = domain.TemperaturePolicyReject
+	}
+	if !domain.ValidTemperaturePolicy(temperaturePolicy) {
+		logging.Log(ErrInvalidPolicy)
+		return domain.Warehouse{}, ErrInvalidPolicy
+	}
+
 	warehouseExists := s.repository.Exists(ctx, warehouseCode)
 	if warehouseExists {
 		logging.Log(ErrAlreadyExists)
@@ -64,6 +79,8 @@ func (s *service) Create(ctx context.Context, address string, telephone string,
 		WarehouseCode:      warehouseCode,
 		MinimumCapacity:    minimumCapacity,
 		MinimumTemperature: minimumTemperature,
+		TemperaturePolicy:  temperaturePolicy,
+		LocalityID:         localityID,
 	}
 	warehouseID, err := s.repository.Save(ctx, warehouse)
 	if err != nil {
@@ -77,9 +94,16 @@ func (s *service) Create(ctx context.Context, address string, telephone string,
 
 // Delete returns an error if the deletion of the warehouse failed.
 // if a warehouse with the given id doesn`t exist, an error is returned.
+// if the strategy is not block or reassign, or reassign has no other warehouse, an error is returned.
+// with the block strategy, if sections, employees or inbound orders reference the warehouse, an error is returned.
 // any other error encountered is also returned.
-func (s *service) Delete(ctx context.Context, id int) error {
-	err := s.repository.Delete(ctx, id)
+func (s *service) Delete(ctx context.Context, id int, options domain.DeleteOptions) error {
+	if !options.Valid(id, DeleteStrategies) {
+		logging.Log(ErrInvalidStrategy)
+		return ErrInvalidStrategy
+	}
+
+	err := s.repository.Delete(ctx, id, options)
 	if err != nil {
 		logging.Log(err)
 		return err
@@ -87,12 +111,30 @@ func (s *service) Delete(ctx context.Context, id int) error {
 	return nil
 }
 
+// DeletePreview returns the sections, employees and inbound orders a deletion of the warehouse affects.
+// if a warehouse with the given id doesn't exist, an error is returned.
+func (s *service) DeletePreview(ctx context.Context, id int) (domain.DeletePreview, error) {
+	if _, err := s.repository.Get(ctx, id); err != nil {
+		logging.Log(err)
+		return domain.DeletePreview{}, err
+	}
+
+	dependents, err := s.repository.GetDependents(ctx, id)
+	if err != nil {
+		logging.Log(err)
+		return domain.DeletePreview{}, err
+	}
+	return domain.NewDeletePreview(id, DeleteStrategies, dependents), nil
+}
+
 // Update returns the updated warehouse if successful.
 // if a warehouse with the given id doesn't exist, an error is returned.
 // if the warehouseCode is not unique (with exception to the warehouse currently updating), an error is returned.
+// if the temperaturePolicy is unknown, an error is returned.
+// if the localityID is not empty and the locality doesn't exist, an error is returned, an empty one removes the locality.
 // any other error encountered is also returned.
 // only the values not in a null state are updated.
-func (s *service) Update(ctx context.Context, id int, address *string, telephone *string, warehouseCode *string, minimumCapacity *int, minimumTemperature *int) (domain.Warehouse, error) {
+func (s *service) Update(ctx context.Context, id int, address *string, telephone *string, warehouseCode *string, minimumCapacity *int, minimumTemperature *int, temperaturePolicy *string, localityID *string) (domain.Warehouse, error) {
 	// Get Original Warehouse
 	warehouse, err := s.repository.Get(ctx, id)
 	if err != nil {
@@ -125,6 +167,16 @@ func (s *service) Update(ctx context.Context, id int, address *string, telephone
 	if minimumTemperature != nil {
 		warehouse.MinimumTemperature = *minimumTemperature
 	}
+	if temperaturePolicy != nil {
+		if !domain.ValidTemperaturePolicy(*temperaturePolicy) {
+			logging.Log(ErrInvalidPolicy)
+			return domain.Warehouse{}, ErrInvalidPolicy
+		}
+		warehouse.TemperaturePolicy = *temperaturePolicy
+	}
+	if localityID != nil {
+		warehouse.LocalityID = *localityID
+	}
 
 	// Update only valid entries
 	err = s.repository.Update(ctx, warehouse)
@@ -134,3 +186,39 @@ func (s *service) Update(ctx context.Context, id int, address *string, telephone
 	}
 	return warehouse, nil
 }
+
+// ReportExpiring returns the batches of the warehouse that expire within the given days, grouped by section and product.
+// if a warehouse with the given id doesn't exist, an error is returned.
+// if days is negative, an error is returned.
+func (s *service) ReportExpiring(ctx context.Context, id int, days int) ([]domain.ExpiringSection, error) {
+	if days < 0 {
+		logging.Log(ErrInvalidDays)
+		return nil, ErrInvalidDays
+	}
+	if _, err := s.repository.Get(ctx, id); err != nil {
+		logging.Log(err)
+		return nil, err
+	}
+	stock, err := s.repository.GetExpiringStock(ctx, id, days)
+	if err != nil {
+		logging.Log(err)
+		return nil, err
+	}
+
+	// the stock comes ordered by section and product, so each group only needs to be compared with the last one
+	sections := []domain.ExpiringSection{}
+	for _, es := range stock {
+		if len(sections) == 0 || sections[len(sections)-1].SectionID != es.SectionID {
+			sections = append(sections, domain.ExpiringSection{SectionID: es.SectionID, SectionNumber: es.SectionNumber})
+		}
+		section := &sections[len(sections)-1]
+		if len(section.Products) == 0 || section.Products[len(section.Products)-1].ProductID != es.ProductID {
+			section.Products = append(section.Products, domain.ExpiringProduct{ProductID: es.ProductID, ProductCode: es.ProductCode, Description: es.Description, DaysRemaining: es.Batch.DaysRemaining})
+		}
+		product := &section.Products[len(section.Products)-1]
+		product.Batches = append(product.Batches, es.Batch)
+		product.Quantity += es.Batch.CurrentQuantity
+		section.Quantity += es.Batch.CurrentQuantity
+	}
+	return sections, nil
+}
@@ -87,12 +87,13 @@ func TestCreate(t *testing.T) {
 		WarehouseCode:      "DHM1",
 		MinimumCapacity:    10,
 		MinimumTemperature: 0,
+		TemperaturePolicy:  domain.TemperaturePolicyReject,
 	}
 	mockRepo := MockRepo{mockWarehouse: expectedWarehouse}
 	service := NewService(&mockRepo)
 	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
 	// act
-	result, _ := service.Create(ctx, expectedWarehouse.Address, expectedWarehouse.Telephone, expectedWarehouse.WarehouseCode, expectedWarehouse.MinimumCapacity, expectedWarehouse.MinimumTemperature)
+	result, _ := service.Create(ctx, expectedWarehouse.Address, expectedWarehouse.Telephone, expectedWarehouse.WarehouseCode, expectedWarehouse.MinimumCapacity, expectedWarehouse.MinimumTemperature, expectedWarehouse.TemperaturePolicy, expectedWarehouse.LocalityID)
 	// assert
 	assert.Equal(t, expectedWarehouse, result)
 }
@@ -113,7 +114,7 @@ func TestCreateFailureSave(t *testing.T) {
 	service := NewService(&mockRepo)
 	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
 	//act
-	_, err := service.Create(ctx, warehouse.Address, warehouse.Telephone, warehouse.WarehouseCode, warehouse.MinimumCapacity, warehouse.MinimumTemperature)
+	_, err := service.Create(ctx, warehouse.Address, warehouse.Telephone, warehouse.WarehouseCode, warehouse.MinimumCapacity, warehouse.MinimumTemperature, warehouse.TemperaturePolicy, warehouse.LocalityID)
 	//assert
 	if assert.Error(t, err) {
 		assert.Equal(t, expectedError, err)
@@ -136,7 +137,7 @@ func TestCreateFailureExists(t *testing.T) {
 	service := NewService(&mockRepo)
 	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
 	//act
-	_, err := service.Create(ctx, warehouse.Address, warehouse.Telephone, warehouse.WarehouseCode, warehouse.MinimumCapacity, warehouse.MinimumTemperature)
+	_, err := service.Create(ctx, warehouse.Address, warehouse.Telephone, warehouse.WarehouseCode, warehouse.MinimumCapacity, warehouse.MinimumTemperature, warehouse.TemperaturePolicy, warehouse.LocalityID)
 	//assert
 	if assert.Error(t, err) {
 		assert.Equal(t, expectedError, err)
@@ -158,7 +159,7 @@ func TestUpdate(t *testing.T) {
 	service := NewService(&mockRepo)
 	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
 	// act
-	result, _ := service.Update(ctx, warehouse.ID, &warehouse.Address, &warehouse.Telephone, &warehouse.WarehouseCode, &warehouse.MinimumCapacity, &warehouse.MinimumTemperature)
+	result, _ := service.Update(ctx, warehouse.ID, &warehouse.Address, &warehouse.Telephone, &warehouse.WarehouseCode, &warehouse.MinimumCapacity, &warehouse.MinimumTemperature, nil, nil)
 	// assert
 	assert.Equal(t, warehouse, result)
 }
@@ -179,7 +180,7 @@ func TestUpdateFailureGet(t *testing.T) {
 	service := NewService(&mockRepo)
 	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
 	// act
-	_, err := service.Update(ctx, warehouse.ID, &warehouse.Address, &warehouse.Telephone, &warehouse.WarehouseCode, &warehouse.MinimumCapacity, &warehouse.MinimumTemperature)
+	_, err := service.Update(ctx, warehouse.ID, &warehouse.Address, &warehouse.Telephone, &warehouse.WarehouseCode, &warehouse.MinimumCapacity, &warehouse.MinimumTemperature, nil, nil)
 	// assert
 	if assert.Error(t, err) {
 		assert.Equal(t, expectedError, err)
@@ -202,7 +203,7 @@ func TestUpdateFailureExists(t *testing.T) {
 	service := NewService(&mockRepo)
 	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
 	// act
-	_, err := service.Update(ctx, warehouse.ID, &warehouse.Address, &warehouse.Telephone, &warehouse.WarehouseCode, &warehouse.MinimumCapacity, &warehouse.MinimumTemperature)
+	_, err := service.Update(ctx, warehouse.ID, &warehouse.Address, &warehouse.Telephone, &warehouse.WarehouseCode, &warehouse.MinimumCapacity, &warehouse.MinimumTemperature, nil, nil)
 	// assert
 	if assert.Error(t, err) {
 		assert.Equal(t, expectedError, err)
@@ -225,7 +226,7 @@ func TestUpdateFailureUpdate(t *testing.T) {
 	service := NewService(&mockRepo)
 	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
 	// act
-	_, err := service.Update(ctx, warehouse.ID, &warehouse.Address, &warehouse.Telephone, &warehouse.WarehouseCode, &warehouse.MinimumCapacity, &warehouse.MinimumTemperature)
+	_, err := service.Update(ctx, warehouse.ID, &warehouse.Address, &warehouse.Telephone, &warehouse.WarehouseCode, &warehouse.MinimumCapacity, &warehouse.MinimumTemperature, nil, nil)
 	// assert
 	if assert.Error(t, err) {
 		assert.Equal(t, expectedError, err)
@@ -239,7 +240,7 @@ func TestDelete(t *testing.T) {
 	service := NewService(&mockRepo)
 	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
 	// act
-	err := service.Delete(ctx, 1)
+	err := service.Delete(ctx, 1, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 	// assert
 	assert.Nil(t, err)
 }
@@ -252,9 +253,143 @@ func TestDeleteFailureInternal(t *testing.T) {
 	service := NewService(&mockRepo)
 	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
 	// act
-	err := service.Delete(ctx, 1)
+	err := service.Delete(ctx, 1, domain.DeleteOptions{Strategy: domain.DeleteBlock})
 	// assert
 	if assert.Error(t, err) {
 		assert.Equal(t, expectedError, err)
 	}
 }
+
+// TestDeleteInvalidStrategy is correct when the strategy is detach or reassign has no other warehouse
+func TestDeleteInvalidStrategy(t *testing.T) {
+	// arrange
+	mockRepo := MockRepo{}
+	service := NewService(&mockRepo)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	cases := []domain.DeleteOptions{{Strategy: domain.DeleteDetach}, {Strategy: domain.DeleteReassign}, {Strategy: domain.DeleteReassign, To: 1}}
+	for _, options := range cases {
+		// act
+		err := service.Delete(ctx, 1, options)
+		// assert
+		assert.Equal(t, ErrInvalidStrategy, err, options.Strategy)
+	}
+	assert.Empty(t, mockRepo.deleteOptions)
+}
+
+// TestDeletePreview checks the correct operation of the DeletePreview service method
+func TestDeletePreview(t *testing.T) {
+	// arrange
+	dependents := map[string][]int{"sections": {}, "employees": {4}, "inbound_orders": {}}
+	mockRepo := MockRepo{mockWarehouse: domain.Warehouse{ID: 1}, mockDependents: dependents}
+	service := NewService(&mockRepo)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	// act
+	preview, err := service.DeletePreview(ctx, 1)
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, domain.DeletePreview{ID: 1, Strategies: DeleteStrategies, Blocked: true, Dependents: dependents}, preview)
+}
+
+// TestCreateInvalidPolicy is correct when the temperature policy is unknown
+func TestCreateInvalidPolicy(t *testing.T) {
+	// arrange
+	mockRepo := MockRepo{}
+	service := NewService(&mockRepo)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	// act
+	_, err := service.Create(ctx, "Monroe 1230", "47470000", "DHM1", 10, 0, "ignore", "")
+	// assert
+	assert.Equal(t, ErrInvalidPolicy, err)
+}
+
+// TestUpdatePolicy checks that only the temperature policy changes when it is the only value given
+func TestUpdatePolicy(t *testing.T) {
+	// arrange
+	warehouse := domain.Warehouse{
+		ID:                1,
+		Address:           "Monroe 1230",
+		Telephone:         "47470000",
+		WarehouseCode:     "DHM1",
+		MinimumCapacity:   10,
+		TemperaturePolicy: domain.TemperaturePolicyReject,
+	}
+	mockRepo := MockRepo{mockWarehouse: warehouse}
+	service := NewService(&mockRepo)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	policy := domain.TemperaturePolicyWarn
+	// act
+	result, err := service.Update(ctx, warehouse.ID, nil, nil, nil, nil, nil, &policy, nil)
+	// assert
+	warehouse.TemperaturePolicy = domain.TemperaturePolicyWarn
+	assert.NoError(t, err)
+	assert.Equal(t, warehouse, result)
+}
+
+// TestServiceUpdateLocality checks that only the locality changes and an empty one removes it
+func TestServiceUpdateLocality(t *testing.T) {
+	// arrange
+	warehouse := domain.Warehouse{
+		ID:                1,
+		Address:           "Monroe 1230",
+		WarehouseCode:     "DHM1",
+		TemperaturePolicy: domain.TemperaturePolicyReject,
+		LocalityID:        "5700",
+	}
+	mockRepo := MockRepo{mockWarehouse: warehouse}
+	service := NewService(&mockRepo)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	localityID := "1900"
+	noLocality := ""
+	// act
+	moved, err := service.Update(ctx, warehouse.ID, nil, nil, nil, nil, nil, nil, &localityID)
+	removed, errRemoved := service.Update(ctx, warehouse.ID, nil, nil, nil, nil, nil, nil, &noLocality)
+	// assert
+	assert.NoError(t, err)
+	assert.NoError(t, errRemoved)
+	assert.Equal(t, "1900", moved.LocalityID)
+	assert.Equal(t, "DHM1", moved.WarehouseCode)
+	assert.Empty(t, removed.LocalityID)
+}
+
+// TestReportExpiring checks that the expiring stock is grouped by section and product
+func TestReportExpiring(t *testing.T) {
+	// arrange
+	batch := func(id int, days int, quantity int) domain.ExpiringBatch {
+		return domain.ExpiringBatch{ProductBatchID: id, BatchNumber: id * 10, DueDate: "2026-05-01", DaysRemaining: days, CurrentQuantity: quantity}
+	}
+	mockRepo := MockRepo{mockExpiringStock: []domain.ExpiringStock{
+		{SectionID: 1, SectionNumber: 10, ProductID: 1, ProductCode: "P1", Batch: batch(1, 1, 5)},
+		{SectionID: 1, SectionNumber: 10, ProductID: 1, ProductCode: "P1", Batch: batch(2, 3, 7)},
+		{SectionID: 1, SectionNumber: 10, ProductID: 2, ProductCode: "P2", Batch: batch(3, 2, 1)},
+		{SectionID: 2, SectionNumber: 20, ProductID: 1, ProductCode: "P1", Batch: batch(4, 0, 4)},
+	}}
+	service := NewService(&mockRepo)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	expected := []domain.ExpiringSection{
+		{SectionID: 1, SectionNumber: 10, Quantity: 13, Products: []domain.ExpiringProduct{
+			{ProductID: 1, ProductCode: "P1", Quantity: 12, DaysRemaining: 1, Batches: []domain.ExpiringBatch{batch(1, 1, 5), batch(2, 3, 7)}},
+			{ProductID: 2, ProductCode: "P2", Quantity: 1, DaysRemaining: 2, Batches: []domain.ExpiringBatch{batch(3, 2, 1)}},
+		}},
+		{SectionID: 2, SectionNumber: 20, Quantity: 4, Products: []domain.ExpiringProduct{
+			{ProductID: 1, ProductCode: "P1", Quantity: 4, DaysRemaining: 0, Batches: []domain.ExpiringBatch{batch(4, 0, 4)}},
+		}},
+	}
+	// act
+	result, err := service.ReportExpiring(ctx, 1, 7)
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
+// TestReportExpiringFailure is correct when the days are negative or the warehouse does not exist
+func TestReportExpiringFailure(t *testing.T) {
+	// arrange
+	service := NewService(&MockRepo{mockErrorInternal: ErrNotFound})
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	// act
+	_, errDays := service.ReportExpiring(ctx, 1, -1)
+	_, errNotFound := service.ReportExpiring(ctx, 1, 7)
+	// assert
+	assert.Equal(t, ErrInvalidDays, errDays)
+	assert.Equal(t, ErrNotFound, errNotFound)
+}
@@ -0,0 +1,19 @@
+-- Turns inbound_orders.order_date from free text into a date and adds the cancellation of inbound orders.
+-- Dates stored as yyyy-mm-dd (optionally followed by a time), dd/mm/yyyy or dd/mm/yy are converted,
+-- the original text is kept in legacy_order_date. Any other value leaves order_date empty: set it by hand
+-- before running 012_inbound_orders_order_date_required.sql, which refuses to run while one is missing.
+use melisprint;
+
+alter table inbound_orders
+    change order_date legacy_order_date text null,
+    add order_date date null after `id`,
+    add cancelled_at datetime null;
+
+update inbound_orders set order_date = case
+    when legacy_order_date regexp '^[0-9]{4}-[0-9]{2}-[0-9]{2}' then str_to_date(left(legacy_order_date, 10), '%Y-%m-%d')
+    when legacy_order_date regexp '^[0-9]{2}/[0-9]{2}/[0-9]{4}$' then str_to_date(legacy_order_date, '%d/%m/%Y')
+    when legacy_order_date regexp '^[0-9]{2}/[0-9]{2}/[0-9]{2}$' then str_to_date(legacy_order_date, '%d/%m/%y')
+end;
+
+-- Rows listed here could not be converted, set their order_date before running 012
+select id, order_number, legacy_order_date from inbound_orders where order_date is null;
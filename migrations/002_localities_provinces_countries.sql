@@ -0,0 +1,37 @@
+-- Moves the free text province_name and country_name of the localities into the countries and provinces tables.
+-- Names are trimmed and compared with the case insensitive collation of the tables, so "Buenos Aires" and
+-- "buenos aires" end up as a single province, named after the first spelling found.
+use melisprint;
+
+create table countries(
+    `id` int not null primary key auto_increment,
+    `name` varchar(100) not null unique
+);
+create table provinces(
+    `id` int not null primary key auto_increment,
+    `name` varchar(100) not null,
+    country_id int not null,
+    unique (country_id, `name`),
+    foreign key (country_id) references countries(id)
+);
+
+insert ignore into countries (`name`)
+    select trim(country_name) from localities order by id;
+
+insert ignore into provinces (`name`, country_id)
+    select trim(l.province_name), c.id from localities as l
+    inner join countries as c on c.`name` = trim(l.country_name)
+    order by l.id;
+
+alter table localities add province_id int null after locality_name;
+
+update localities as l
+    inner join countries as c on c.`name` = trim(l.country_name)
+    inner join provinces as p on p.country_id = c.id and p.`name` = trim(l.province_name)
+    set l.province_id = p.id;
+
+alter table localities
+    modify province_id int not null,
+    add foreign key (province_id) references provinces(id),
+    drop column province_name,
+    drop column country_name;
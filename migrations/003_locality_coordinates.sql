@@ -0,0 +1,11 @@
+-- Adds the coordinates of the localities and the locality of the warehouses, both start empty.
+-- Warehouses and carries without a locality with coordinates are left out of the distance queries.
+use melisprint;
+
+alter table localities
+    add latitude decimal(9,6) null,
+    add longitude decimal(9,6) null;
+
+alter table warehouses
+    add locality_id varchar(10) null,
+    add foreign key (locality_id) references localities(id);
@@ -0,0 +1,5 @@
+-- Indexes the product records by product and date, every price lookup takes the newest record
+-- of a product already effective at a date and records dated after today are scheduled price changes.
+use melisprint;
+
+create index product_records_product_date on product_records (product_id, last_update_date);
@@ -0,0 +1,21 @@
+-- Adds the product types behind products.id_product_type and sections.id_product_type.
+-- Every type already in use gets a row named after its id with a wide temperature range,
+-- rename them and narrow their ranges before relying on the defaults.
+use melisprint;
+
+create table product_types(
+    `id` int not null primary key auto_increment,
+    `name` varchar(50) not null unique,
+    minimum_temperature float not null,
+    maximum_temperature float not null
+);
+
+insert into product_types (`id`, `name`, minimum_temperature, maximum_temperature)
+select used.id_product_type, concat('product type ', used.id_product_type), -50, 50
+from (select id_product_type from products union select id_product_type from sections) as used;
+
+alter table products
+    add foreign key (id_product_type) references product_types(id);
+
+alter table sections
+    add foreign key (id_product_type) references product_types(id);
@@ -0,0 +1,6 @@
+-- Adds the temperature policy of the warehouses, it decides whether a section temperature that does not fit
+-- the products stored in it is rejected or only warned about. Existing warehouses keep rejecting them.
+use melisprint;
+
+alter table warehouses
+    add temperature_policy varchar(10) not null default 'reject' after minimum_temperature;
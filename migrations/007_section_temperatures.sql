@@ -0,0 +1,11 @@
+-- Adds the temperature readings of the sections, existing sections start without history.
+use melisprint;
+
+create table section_temperatures(
+    `id` int not null primary key auto_increment,
+    section_id int not null,
+    recorded_at datetime not null,
+    temperature float not null,
+    index (section_id, recorded_at),
+    foreign key (section_id) references sections(id)
+);
@@ -0,0 +1,18 @@
+-- Adds the temperature excursions of the sections and the quarantine of the batches they affect.
+-- Existing batches start out of quarantine, they are only quarantined by readings ingested from now on.
+use melisprint;
+
+alter table product_batches
+    add quarantined boolean not null default false after section_id;
+
+create table temperature_excursions(
+    `id` int not null primary key auto_increment,
+    section_id int not null,
+    product_batch_id int not null,
+    limit_temperature float not null,
+    maximum_temperature float not null,
+    started_at datetime not null,
+    ended_at datetime null,
+    foreign key (section_id) references sections(id),
+    foreign key (product_batch_id) references product_batches(id)
+);
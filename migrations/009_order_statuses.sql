@@ -0,0 +1,42 @@
+-- Turns purchase_orders.order_status_id into a reference to the statuses of the order state machine
+-- and adds the history of the status changes.
+-- Ids 1 to 6 already stand for pending, confirmed, picking, shipped, delivered and cancelled. Orders with any
+-- other id keep it in legacy_order_status_id and start over as pending, they are listed for review.
+use melisprint;
+
+create table order_statuses(
+    `id` int not null primary key,
+    `name` varchar(20) not null unique
+);
+insert into order_statuses (`id`, `name`) values
+    (1, 'pending'),
+    (2, 'confirmed'),
+    (3, 'picking'),
+    (4, 'shipped'),
+    (5, 'delivered'),
+    (6, 'cancelled');
+
+alter table purchase_orders
+    add legacy_order_status_id int null after order_status_id;
+
+update purchase_orders set legacy_order_status_id = order_status_id, order_status_id = 1
+where order_status_id not in (select `id` from order_statuses);
+
+-- Orders listed here were moved to pending, set their status before placing them again
+select `id`, order_number, legacy_order_status_id from purchase_orders where legacy_order_status_id is not null;
+
+alter table purchase_orders
+    modify order_status_id int not null default 1,
+    add foreign key (order_status_id) references order_statuses(id);
+
+create table purchase_order_status_history(
+    `id` int not null primary key auto_increment,
+    purchase_order_id int not null,
+    from_status_id int not null,
+    to_status_id int not null,
+    actor varchar(100) not null,
+    changed_at datetime not null,
+    foreign key (purchase_order_id) references purchase_orders(id),
+    foreign key (from_status_id) references order_statuses(id),
+    foreign key (to_status_id) references order_statuses(id)
+);
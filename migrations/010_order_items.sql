@@ -0,0 +1,26 @@
+-- Lets a purchase order have several priced items and makes purchase_orders.product_record_id optional.
+-- Every order placed with a product_record_id gets the item it stands for, one unit of the product
+-- of the record at its sale price, the same item the orders placed that way get from now on.
+use melisprint;
+
+alter table purchase_orders
+    modify product_record_id int null;
+
+create table order_items(
+    `id` int not null primary key auto_increment,
+    purchase_order_id int not null,
+    product_id int not null,
+    product_record_id int not null,
+    quantity int not null,
+    unit_price decimal(19,2) not null,
+    unique (purchase_order_id, product_id),
+    foreign key (purchase_order_id) references purchase_orders(id),
+    foreign key (product_id) references products(id),
+    foreign key (product_record_id) references product_records(id)
+);
+
+insert into order_items (purchase_order_id, product_id, product_record_id, quantity, unit_price)
+select po.id, pr.product_id, pr.id, 1, round(ifnull(pr.sale_price, 0), 2)
+from purchase_orders as po
+inner join product_records as pr on pr.id = po.product_record_id
+order by po.id;
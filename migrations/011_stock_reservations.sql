@@ -0,0 +1,19 @@
+-- Adds the stock reservations of the purchase orders and the reserved quantity of the batches.
+-- Orders placed before hold no stock, their batches start with nothing reserved.
+use melisprint;
+
+alter table product_batches
+    add reserved_quantity int not null default 0 after quarantined;
+
+create table stock_reservations(
+    `id` int not null primary key auto_increment,
+    purchase_order_id int not null,
+    product_batch_id int not null,
+    quantity int not null,
+    `status` varchar(10) not null,
+    expires_at datetime,
+    created_at datetime not null,
+    index (`status`, expires_at),
+    foreign key (purchase_order_id) references purchase_orders(id),
+    foreign key (product_batch_id) references product_batches(id)
+);
@@ -0,0 +1,23 @@
+-- Makes inbound_orders.order_date required once 001_inbound_orders_order_date.sql converted it.
+-- It stops without changing anything while an order has no order_date, list them with
+--     select id, order_number, legacy_order_date from inbound_orders where order_date is null;
+-- legacy_order_date is kept so the conversion can still be checked, a later migration drops it.
+use melisprint;
+
+drop procedure if exists check_inbound_order_dates;
+
+delimiter //
+create procedure check_inbound_order_dates()
+begin
+    if exists (select 1 from inbound_orders where order_date is null) then
+        signal sqlstate '45000' set message_text = 'inbound orders without order_date, set them before running this migration';
+    end if;
+end//
+delimiter ;
+
+call check_inbound_order_dates();
+drop procedure check_inbound_order_dates;
+
+alter table inbound_orders
+    modify order_date date not null,
+    add index (order_date);
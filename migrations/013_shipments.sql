@@ -0,0 +1,21 @@
+-- Adds the shipments that assign a carry to purchase orders and record their dispatch and delivery.
+-- Existing orders keep their tracking code and belong to no shipment.
+use melisprint;
+
+create table shipments(
+    `id` int not null primary key auto_increment,
+    carry_id int not null,
+    tracking_code varchar(50) not null unique,
+    created_at datetime not null,
+    dispatched_at datetime null,
+    delivered_at datetime null,
+    foreign key (carry_id) references carries(id)
+);
+
+create table shipment_orders(
+    shipment_id int not null,
+    purchase_order_id int not null unique,
+    primary key (shipment_id, purchase_order_id),
+    foreign key (shipment_id) references shipments(id),
+    foreign key (purchase_order_id) references purchase_orders(id)
+);
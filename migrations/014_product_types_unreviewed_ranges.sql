@@ -0,0 +1,11 @@
+-- Sections and products created without temperatures now take them from the range of their product type.
+-- The -50..50 range 005 gave to the product types already in use was never reviewed, it is cleared so those
+-- types have no default until someone sets their real range.
+use melisprint;
+
+alter table product_types
+    modify minimum_temperature float null,
+    modify maximum_temperature float null;
+
+update product_types set minimum_temperature = null, maximum_temperature = null
+where minimum_temperature = -50 and maximum_temperature = 50 and `name` = concat('product type ', `id`);
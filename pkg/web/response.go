@@ -1,6 +1,7 @@
 package web
 
 import (
+	"encoding/csv"
 	"fmt"
 	"net/http"
 	"strings"
@@ -37,3 +38,21 @@ func Error(c *gin.Context, status int, format string, args ...interface{}) {
 
 	Response(c, status, err)
 }
+
+// WantsCSV reports whether the client asked for a CSV response, with ?format=csv or an Accept: text/csv header.
+func WantsCSV(c *gin.Context) bool {
+	if format := c.Query("format"); format != "" {
+		return strings.EqualFold(format, "csv")
+	}
+	return strings.Contains(c.GetHeader("Accept"), "text/csv")
+}
+
+// CSV writes the header and the records as a CSV attachment with the given filename.
+func CSV(c *gin.Context, status int, filename string, header []string, records [][]string) {
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(status)
+	c.Writer.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write(header)
+	_ = w.WriteAll(records)
+}